@@ -0,0 +1,2758 @@
+// Code generated by gendts from the redis package, and the README; DO NOT EDIT.
+
+declare module "k6/x/redis" {
+  /**
+   * Client represents the Client constructor (i.e. `new redis.Client()`) and
+   * returns a new Redis client object.
+   */
+  export class Client {
+    /**
+     * Client is the JS constructor for the redis Client.
+     *
+     * Under the hood, the redis.UniversalClient will be used. The universal client
+     * supports failover/sentinel, cluster and single-node modes. Depending on the options,
+     * the internal universal client instance will be one of those.
+     *
+     * The type of the underlying client depends on the following conditions:
+     * If the first argument is a string, it's parsed as a Redis URL, and a
+     * single-node Client is used.
+     * Otherwise, an object is expected, and depending on its properties:
+     * 1. If the masterName property is defined, a sentinel-backed FailoverClient is used.
+     * 2. If the cluster property is defined, a ClusterClient is used, even with a
+     * single seed node.
+     * 3. Otherwise, a single-node Client is used.
+     *
+     * Without arguments, the options are read from the test's environment
+     * variables, see readEnvOptions, so that credentials stay out of scripts.
+     *
+     * To support being instantiated in the init context, while not
+     * producing any IO, as it is the convention in k6, the produced
+     * Client is initially configured, but in a disconnected state.
+     * The connection is automatically established when using any of the Redis
+     * commands exposed by the Client.
+     */
+    constructor(options: string | {[option: string]: any});
+
+    /**
+     * aclDelUser deletes the provided ACL users, and closes their connections.
+     * Cluster clients delete the users from every master node.
+     *
+     * The promise resolves with the number of users deleted, from any of the
+     * nodes, not counting those which don't exist.
+     */
+    aclDelUser(...usernames: string[]): Promise<number>;
+
+    /**
+     * aclList returns the ACL users of the server, with their rules, in the
+     * format of ACL files.
+     *
+     * The promise resolves with an array of strings, such as
+     * "user default on nopass ~* &* +@all".
+     */
+    aclList(): Promise<string[]>;
+
+    /**
+     * aclSetUser creates the ACL user `username`, or modifies its rules, such
+     * as "on", ">password", "~cache:*", or "+get". Cluster clients set the user
+     * on every master node, as ACL users are specific to each node.
+     *
+     * The promise resolves with "OK".
+     */
+    aclSetUser(username: string, ...rules: string[]): Promise<string>;
+
+    /**
+     * aclWhoami returns the name of the ACL user the client's connections are
+     * authenticated as.
+     *
+     * The promise resolves with the name of the user, "default" if the client
+     * wasn't given a username.
+     */
+    aclWhoami(): Promise<string>;
+
+    /**
+     * addHook adds callbacks called around each command sent by the client,
+     * and the clients derived from it with withTimeout and withDatabase, such
+     * as to log, tag, audit, or measure specific commands, without wrapping
+     * every call site. The `hook` object holds either, or both, of the callbacks:
+     *   - beforeCommand is called with the command, an object holding its
+     *     `name`, and its `args`, before it is sent. The callback may modify
+     *     the arguments in place, such as to prefix keys, but not their number.
+     *   - afterCommand is called with the command, once its reply is received,
+     *     along with its `durationMs`, and its `error` message, or null if it
+     *     succeeded.
+     *
+     * The commands of pipelines and transactions are passed to the callbacks
+     * one by one. An exception thrown by a callback fails the command, with
+     * the exception's message.
+     *
+     * The callbacks are called on the event loop, which delays the commands
+     * until it is available. Hence, blocking clients don't support hooks.
+     */
+    addHook(hook: any): void;
+
+    /**
+     * append appends `value` at the end of the string stored at `key`. If `key`
+     * does not exist, it is created holding `value`.
+     *
+     * The value can be binary: ArrayBuffer or Uint8Array. The promise resolves
+     * with the length of the string after the append, in bytes.
+     */
+    append(key: string, value: string | ArrayBuffer | Uint8Array): Promise<number>;
+
+    /**
+     * appendLog appends `entry` at the end of the string log stored at `key`,
+     * using the APPEND command. If `key` does not exist, it is created holding
+     * `entry`. As APPEND is atomic, concurrent appends never overwrite each other.
+     *
+     * The promise resolves with the new total length of the log, in bytes.
+     */
+    appendLog(key: string, entry: string): Promise<number>;
+
+    /**
+     * barrier waits until `participants` callers, possibly running in distinct VUs or
+     * k6 instances, have reached the barrier identified by `channel`, so they can all
+     * proceed together.
+     *
+     * Each participant subscribes to `channel`, increments the arrivals counter
+     * stored at the key of the same name, and publishes the resulting count. The
+     * barrier is met once the count reaches `participants`. As the counter keeps
+     * growing, the same barrier can be reused: each group of `participants`
+     * successive arrivals is released together.
+     *
+     * If the barrier is not met within `timeoutMs` milliseconds, the promise is
+     * rejected with an error. The subscription is closed either way.
+     */
+    barrier(channel: string, participants: number, timeoutMs: number): Promise<void>;
+
+    /**
+     * bfAdd adds `item` to the Bloom filter stored at `key`, creating it with
+     * the server's default capacity and error rate if it does not exist.
+     *
+     * The promise resolves with true if the item was added, or false if it
+     * may have been added already.
+     */
+    bfAdd(key: string, item: string | number | boolean): Promise<boolean>;
+
+    /**
+     * bfExists returns whether `item` may have been added to the Bloom filter
+     * stored at `key`.
+     *
+     * The promise resolves with false if the item was certainly not added, or
+     * if `key` does not exist, and true otherwise.
+     */
+    bfExists(key: string, item: string | number | boolean): Promise<boolean>;
+
+    /**
+     * bfMAdd is like BfAdd, except that it adds all the provided items.
+     *
+     * The promise resolves with an array holding, for each item, in order,
+     * whether it was added.
+     */
+    bfMAdd(key: string, ...items: (string | number | boolean)[]): Promise<boolean[]>;
+
+    /**
+     * bfMExists is like BfExists, except that it checks all the provided
+     * items.
+     *
+     * The promise resolves with an array holding, for each item, in order,
+     * whether it may have been added.
+     */
+    bfMExists(key: string, ...items: (string | number | boolean)[]): Promise<boolean[]>;
+
+    /**
+     * bfReserve creates an empty Bloom filter at `key`, sized to hold
+     * `capacity` items with a false positive rate of `errorRate`, between 0
+     * and 1.
+     *
+     * The promise resolves with "OK". If `key` already exists, the promise is
+     * rejected with an error.
+     */
+    bfReserve(key: string, errorRate: number, capacity: number, options?: {expansion?: number, nonScaling?: boolean}): Promise<string>;
+
+    /**
+     * bitcount returns the number of bits set in the string value stored at
+     * `key`, or in the range of it delimited by the `start` and `end` options.
+     *
+     * The promise resolves with the number of bits set, or 0 if `key` does not
+     * exist.
+     */
+    bitcount(key: string, options?: {start?: number, end?: number, unit?: "byte" | "bit"}): Promise<number>;
+
+    /**
+     * bitfield performs the provided operations on the integers of arbitrary
+     * width, and offset, stored in the string value at `key`, as
+     * {op, type, offset, value} objects.
+     *
+     * The promise resolves with an array holding the reply of each "get",
+     * "set", and "incrby" operation: the integer read, the previous value of
+     * the integer set, and the incremented value, respectively. Increments
+     * failing with the "fail" overflow behavior reply with null.
+     */
+    bitfield(key: string, operations: {op: "get" | "set" | "incrby" | "overflow", type?: string, offset?: number | string, value?: number | string}[]): Promise<(number | null)[]>;
+
+    /**
+     * bitop performs the bitwise `operation` ("and", "or", "xor", or "not")
+     * between the string values stored at `keys`, and stores the result at
+     * `destination`. The "not" operation takes a single key.
+     *
+     * The promise resolves with the length of the string stored at
+     * `destination`.
+     */
+    bitop(operation: "and" | "or" | "xor" | "not", destination: string, ...keys: string[]): Promise<number>;
+
+    /**
+     * bitpos returns the position of the first bit set to `bit`, 0 or 1, in the
+     * string value stored at `key`, or in the range of it delimited by the
+     * `start` and `end` options.
+     *
+     * The promise resolves with the position of the bit, or -1 if no such bit
+     * is found.
+     */
+    bitpos(key: string, bit: 0 | 1, options?: {start?: number, end?: number, unit?: "byte" | "bit"}): Promise<number>;
+
+    /**
+     * blmove atomically removes the element at the `from` end, "left" or
+     * "right", of the list stored at `source`, and pushes it at the `to` end of
+     * the list stored at `destination`, waiting up to `timeout` seconds for an
+     * element to be pushed to `source` if it is empty.
+     *
+     * The promise resolves with the moved element, or null if the timeout
+     * expired.
+     */
+    blmove(source: string, destination: string, from: "left" | "right", to: "left" | "right", timeout: number): Promise<string | null>;
+
+    /**
+     * blmpop is the blocking variant of Lmpop: it waits up to `timeout` seconds
+     * for an element to be pushed if all the lists are empty. It requires
+     * Redis 7.
+     *
+     * The promise resolves with an object holding the `key` the elements were
+     * popped from and its `values`, or null if the timeout expired.
+     */
+    blmpop(keys: string[], from: "left" | "right", timeout: number, count?: number): Promise<{key: string, values: string[]} | null>;
+
+    /**
+     * blpop removes and returns the first element of the first non-empty list
+     * among `keys`, waiting up to `timeout` seconds for an element to be
+     * pushed if they are all empty.
+     *
+     * The promise resolves with an object holding the `key` the element was
+     * popped from and its `value`, or null if the timeout expired.
+     */
+    blpop(keys: string[], timeout: number): Promise<{key: string, value: string} | null>;
+
+    /**
+     * brpop removes and returns the last element of the first non-empty list
+     * among `keys`, waiting up to `timeout` seconds for an element to be
+     * pushed if they are all empty.
+     *
+     * The promise resolves with an object holding the `key` the element was
+     * popped from and its `value`, or null if the timeout expired.
+     */
+    brpop(keys: string[], timeout: number): Promise<{key: string, value: string} | null>;
+
+    /**
+     * bzpopmin removes and returns the member with the lowest score of the
+     * first non-empty sorted set among `keys`, waiting up to `timeout` seconds
+     * for a member to be added if they are all empty.
+     *
+     * The promise resolves with an object holding the `key` the member was
+     * popped from, the `member`, and its `score`, or null if the timeout
+     * expired.
+     */
+    bzpopmin(keys: string[], timeout: number): Promise<{key: string, member: string, score: number} | null>;
+
+    /**
+     * cfAdd adds `item` to the Cuckoo filter stored at `key`, creating it with
+     * the server's default capacity if it does not exist. Unlike Bloom
+     * filters, Cuckoo filters hold duplicate items, each of which can be
+     * deleted.
+     *
+     * The promise resolves with true. If the filter is full, the promise is
+     * rejected with an error.
+     */
+    cfAdd(key: string, item: string | number | boolean): Promise<boolean>;
+
+    /**
+     * cfAddNx is like CfAdd, except that it only adds `item` if it wasn't
+     * added already.
+     *
+     * The promise resolves with true if the item was added, or false if it
+     * may have been added already.
+     */
+    cfAddNx(key: string, item: string | number | boolean): Promise<boolean>;
+
+    /**
+     * cfCount returns the number of times `item` may have been added to the
+     * Cuckoo filter stored at `key`.
+     *
+     * The promise resolves with the count, which may exceed the actual one,
+     * or 0 if `key` does not exist.
+     */
+    cfCount(key: string, item: string | number | boolean): Promise<number>;
+
+    /**
+     * cfDel deletes one occurrence of `item` from the Cuckoo filter stored at
+     * `key`.
+     *
+     * The promise resolves with true if the item was deleted, or false if it
+     * wasn't found. If `key` does not exist, the promise is rejected with an
+     * error.
+     */
+    cfDel(key: string, item: string | number | boolean): Promise<boolean>;
+
+    /**
+     * cfExists returns whether `item` may have been added to the Cuckoo filter
+     * stored at `key`.
+     *
+     * The promise resolves with false if the item was certainly not added, or
+     * if `key` does not exist, and true otherwise.
+     */
+    cfExists(key: string, item: string | number | boolean): Promise<boolean>;
+
+    /**
+     * cfReserve creates an empty Cuckoo filter at `key`, sized to hold
+     * `capacity` items.
+     *
+     * The promise resolves with "OK". If `key` already exists, the promise is
+     * rejected with an error.
+     */
+    cfReserve(key: string, capacity: number, options?: {bucketSize?: number, maxIterations?: number, expansion?: number}): Promise<string>;
+
+    /**
+     * cleanupIteration deletes the keys written by the client, and the clients
+     * derived from it, since the previous call, such as at the end of each
+     * iteration of a soak test, so that the dataset doesn't grow over time.
+     * It requires the trackKeys option.
+     *
+     * The promise resolves with the number of keys deleted.
+     */
+    cleanupIteration(): Promise<number>;
+
+    /**
+     * clientKill closes the connections of the server's clients matching the
+     * provided filters, with CLIENT KILL, such as to inject disruptions in the
+     * system under test. Cluster clients close the matching connections of
+     * every master node.
+     *
+     * The promise resolves with the number of connections closed.
+     */
+    clientKill(filters: {id?: number, addr?: string, laddr?: string, type?: "normal" | "master" | "replica" | "pubsub", user?: string, skipMe?: boolean}): Promise<number>;
+
+    /**
+     * clientList returns the connections of the server's clients, as reported
+     * by CLIENT LIST.
+     *
+     * The promise resolves with an array holding an object for each
+     * connection, mapping its properties, such as `addr`, `name`, or `age`,
+     * to their values. Numeric values are converted to numbers.
+     */
+    clientList(): Promise<{[property: string]: any}[]>;
+
+    /**
+     * clientNoEvict excludes the connection it is sent on from the server's
+     * client eviction, with CLIENT NO-EVICT, when `enabled`, or includes it
+     * back otherwise. As the connections of the Client are pooled, it only
+     * applies to one of them: to apply it to the commands it is meant for,
+     * send it along with them in a pipeline, with sendCommand.
+     *
+     * The promise resolves with "OK".
+     */
+    clientNoEvict(enabled: boolean): Promise<string>;
+
+    /**
+     * clientPause suspends the server's clients for `timeoutMs` milliseconds,
+     * with CLIENT PAUSE, such as to simulate a stalled server. With the "write"
+     * `mode`, only the commands writing data are suspended; the "all" mode, the
+     * default, suspends all of them. Cluster clients pause every master node.
+     *
+     * The promise resolves with "OK".
+     */
+    clientPause(timeoutMs: number, mode?: "all" | "write"): Promise<string>;
+
+    /**
+     * clientUnpause resumes the server's clients suspended by clientPause,
+     * with CLIENT UNPAUSE, before its timeout. Cluster clients unpause every
+     * master node.
+     *
+     * The promise resolves with "OK".
+     */
+    clientUnpause(): Promise<string>;
+
+    /**
+     * close releases the client's connection pool, which is closed, along with
+     * its connections, once no other client uses it. The pool being shared by
+     * all the clients using the same options, such as those of other VUs,
+     * closing a client only closes the pool once they are all closed, or the
+     * k6 process exits, which closes all the pools.
+     *
+     * Commands sent by the client afterwards fail, once the pool is closed.
+     * Closing a client returned by withTimeout does nothing, as it uses the
+     * pool of the client it was derived from.
+     */
+    close(): Promise<void>;
+
+    /**
+     * clusterKeyslot returns the hash slot of `key`, as computed by the
+     * cluster with CLUSTER KEYSLOT. Under the keyPrefix option, the slot is
+     * the one of the prefixed key, which the key's commands are routed by.
+     *
+     * The promise resolves with the slot, a number between 0 and 16383.
+     *
+     * ClusterKeyslot is only supported by cluster clients.
+     */
+    clusterKeyslot(key: string): Promise<number>;
+
+    /**
+     * clusterNodes lists the nodes of the cluster, as reported by CLUSTER
+     * NODES.
+     *
+     * The promise resolves with an array of objects holding, for each node,
+     * its `id` and `address`, its `role`: either "master" or "replica", its
+     * `flags`, such as "myself" or "fail", the `masterId` of replicas, the
+     * `pingSent` and `pongReceived` Unix times, in milliseconds, its
+     * `configEpoch` and `linkState`, and the `slots` ranges it serves, as
+     * {start, end} objects. The slots being resharded are listed as
+     * `migrating` to, or `importing` from, another node, as {slot, node}
+     * objects. The nodes are sorted by address.
+     *
+     * ClusterNodes is only supported by cluster clients.
+     */
+    clusterNodes(): Promise<{id: string, address: string, role: "master" | "replica", flags: string[], masterId: string | null, pingSent: number, pongReceived: number, configEpoch: number, linkState: string, slots: {start: number, end: number}[], migrating: {slot: number, node: string}[], importing: {slot: number, node: string}[]}[]>;
+
+    /**
+     * clusterShards returns the shards of the cluster, as reported by CLUSTER
+     * SHARDS, available since Redis 7.
+     *
+     * The promise resolves with an array of objects holding the `slots` ranges
+     * served by each shard, as {start, end} objects, and its `nodes`, as
+     * {id, address, endpoint, ip, hostname, port, tlsPort, role,
+     * replicationOffset, health} objects, the address being the "ip:port" one
+     * the nodes are known by, such as by runOnNode.
+     *
+     * ClusterShards is only supported by cluster clients.
+     */
+    clusterShards(): Promise<{slots: {start: number, end: number}[], nodes: {id: string, address: string, endpoint: string, ip: string, hostname: string, port: number, tlsPort: number, role: string, replicationOffset: number, health: string}[]}[]>;
+
+    /**
+     * clusterSlots returns the layout of the cluster's hash slots, as reported
+     * by CLUSTER SLOTS.
+     *
+     * The promise resolves with an array of objects holding the `start` and
+     * `end` slots of each range, both inclusive, and the `master` and
+     * `replicas` nodes serving it, as {address, id} objects.
+     *
+     * ClusterSlots is only supported by cluster clients.
+     */
+    clusterSlots(): Promise<{start: number, end: number, master: {address: string, id: string}, replicas: {address: string, id: string}[]}[]>;
+
+    /**
+     * cmsIncrBy increments the count of the items of the Count-Min sketch
+     * stored at `key` by their value in `increments`.
+     *
+     * The promise resolves with an object mapping each item to its count. If
+     * `key` does not exist, the promise is rejected with an error.
+     */
+    cmsIncrBy(key: string, increments: {[item: string]: number}): Promise<{[item: string]: number}>;
+
+    /**
+     * cmsInitByDim creates an empty Count-Min sketch at `key`, `width`
+     * counters wide, and `depth` counters deep.
+     *
+     * The promise resolves with "OK". If `key` already exists, the promise is
+     * rejected with an error.
+     */
+    cmsInitByDim(key: string, width: number, depth: number): Promise<string>;
+
+    /**
+     * cmsInitByProb creates an empty Count-Min sketch at `key`, sized for the
+     * counts to overestimate by at most `errorRate` of the total count, with a
+     * probability of `probability`, both between 0 and 1.
+     *
+     * The promise resolves with "OK". If `key` already exists, the promise is
+     * rejected with an error.
+     */
+    cmsInitByProb(key: string, errorRate: number, probability: number): Promise<string>;
+
+    /**
+     * cmsQuery returns the count of the provided items in the Count-Min
+     * sketch stored at `key`.
+     *
+     * The promise resolves with an object mapping each item to its count,
+     * which may exceed the actual one. If `key` does not exist, the promise is
+     * rejected with an error.
+     */
+    cmsQuery(key: string, ...items: string[]): Promise<{[item: string]: number}>;
+
+    /**
+     * commandHistogram returns the distribution of the commands sent by the
+     * Client so far, by name and size of their arguments, to help confirming
+     * the generated load matches the intended command mix and payload sizes.
+     *
+     * As the tally is kept by each Client, the distribution only covers the
+     * commands sent by the VU it belongs to.
+     *
+     * It requires the collectCommandHistogram option, so that tallying
+     * commands doesn't cost anything to the clients that don't use it.
+     */
+    commandHistogram(): {[key: string]: any};
+
+    /**
+     * configGet returns the server's configuration parameters matching the
+     * glob-style `parameter`.
+     *
+     * The promise resolves with an object mapping each parameter to its value.
+     */
+    configGet(parameter: string): Promise<{[parameter: string]: string}>;
+
+    /**
+     * configSet sets the server's configuration `parameter` to `value`.
+     *
+     * The promise resolves with "OK".
+     */
+    configSet(parameter: string, value: string): Promise<string>;
+
+    /**
+     * connect establishes the client's connections, which are otherwise
+     * established by its first commands, such as in the setup function, so
+     * that dialing doesn't skew the latencies of the first iterations. Each of
+     * the nodes the client is connected to is sent a PING, to check it is
+     * reachable.
+     *
+     * With the `fillIdleConns` option, the promise also waits for the pool of
+     * each node to hold the minIdleConns idle connections, which go-redis opens
+     * in the background. As the pool is shared by all the VUs using the same
+     * client options, the VUs use the connections opened in setup.
+     *
+     * The promise resolves once the connections are established.
+     */
+    connect(options?: {fillIdleConns?: boolean}): Promise<void>;
+
+    /**
+     * connectionCount returns the number of connections currently open by the
+     * client's connection pool, as an object mapping each node's address to
+     * its count of connections. Cluster clients report a count for each of the
+     * cluster's nodes, while other clients report a single count.
+     *
+     * Note that the connection pool is shared by all the VUs using the same
+     * client options, and so are the reported counts.
+     */
+    connectionCount(): Promise<{[address: string]: number}>;
+
+    /**
+     * copy copies the value stored at `source` to the `destination` key.
+     *
+     * The promise resolves with true if the value was copied, and false if
+     * the destination key already exists and the replace option isn't set.
+     */
+    copy(source: string, destination: string, options?: {db?: number, replace?: boolean}): Promise<boolean>;
+
+    /**
+     * debugSleep makes the server sleep for `seconds`, which can be fractional,
+     * blocking all of its clients, so that tests can simulate a stalled server.
+     * The DEBUG command is disabled by default since Redis 7.
+     *
+     * The promise resolves with "OK" once the server wakes up.
+     */
+    debugSleep(seconds: number): Promise<string>;
+
+    /**
+     * decr decrements the number stored at `key` by one. If the key does
+     * not exist, it is set to zero before performing the operation. An
+     * error is returned if the key contains a value of the wrong type, or
+     * contains a string that cannot be represented as an integer.
+     */
+    decr(key: string): Promise<number>;
+
+    /**
+     * decrBy decrements the number stored at `key` by `decrement`. If the key does
+     * not exist, it is set to zero before performing the operation. An
+     * error is returned if the key contains a value of the wrong type, or
+     * contains a string that cannot be represented as an integer.
+     */
+    decrBy(key: string, decrement: number): Promise<number>;
+
+    /**
+     * del removes the specified keys. A key is ignored if it does not exist
+     *
+     * Keys can be provided as separate arguments, arrays, or both.
+     * Calls exceeding the commandChunkSize option are split in pipelined chunks.
+     */
+    del(...keys: (string | string[])[]): Promise<number>;
+
+    /**
+     * deleteByPattern deletes all the keys matching the glob-style `pattern`,
+     * such as to clean up the keys created by a test in its teardown. The keys
+     * are scanned, and unlinked, in batches, in Go, so that the whole keyspace
+     * is covered, without transferring the keys to JS. Cluster clients delete
+     * the keys of every master node.
+     *
+     * The promise resolves with the number of keys deleted.
+     */
+    deleteByPattern(pattern: string, options?: {batchSize?: number, type?: string}): Promise<number>;
+
+    /**
+     * dump serializes the value stored at `key` in the server's format, for
+     * restore to recreate it, possibly on another server.
+     *
+     * The promise resolves with the serialized value as an ArrayBuffer, as it
+     * is binary, or null if the key doesn't exist.
+     */
+    dump(key: string): Promise<ArrayBuffer | null>;
+
+    /**
+     * encodings returns the internal encoding Redis uses to store the value of
+     * each of the provided keys, as reported by OBJECT ENCODING.
+     *
+     * The OBJECT ENCODING commands are pipelined, so that auditing the encodings
+     * of a large dataset doesn't take a round-trip per key.
+     *
+     * The promise resolves with an object mapping each key to its encoding,
+     * or to null if the key doesn't exist.
+     */
+    encodings(...keys: string[]): Promise<{[key: string]: string | null}>;
+
+    /**
+     * estimateSize approximates the number of bytes taken by the value of
+     * `key`, using type-specific commands rather than MEMORY USAGE, which may
+     * be disabled, or slow, on some servers.
+     *
+     * The size of strings is their length. The size of collections is
+     * extrapolated from a sample of their elements: the average size of the
+     * sampled elements is multiplied by the number of elements. Only the
+     * payload is accounted for, not the overhead of Redis' internal encodings,
+     * so the estimate is a rough sizing signal, and not a measure of the
+     * server's memory usage.
+     *
+     * The promise resolves with the estimated `bytes`, and the `method` used
+     * to estimate them, or with null if the key doesn't exist.
+     */
+    estimateSize(key: string): Promise<{bytes: number, method: string} | null>;
+
+    /**
+     * eval evaluates the Lua `script` server-side, with the provided keys and
+     * arguments, made available to the script as the KEYS and ARGV arrays.
+     *
+     * The promise resolves with the script's reply, null standing for a nil
+     * reply.
+     */
+    eval(script: string, keys: string[], ...args: any[]): Promise<any>;
+
+    /**
+     * evalsha evaluates the Lua script cached by the server under the `sha1`
+     * digest, as returned by scriptLoad, with the provided keys and arguments.
+     *
+     * The promise is rejected with a NOSCRIPT error if the server doesn't know
+     * the script.
+     */
+    evalsha(sha1: string, keys: string[], ...args: any[]): Promise<any>;
+
+    /**
+     * exists returns the number of key arguments that exist.
+     * Note that if the same existing key is mentioned in the argument
+     * multiple times, it will be counted multiple times.
+     *
+     * Like Del, it accepts keys as separate arguments, arrays, or both.
+     */
+    exists(...keys: (string | string[])[]): Promise<number>;
+
+    /**
+     * expect sends a command, as sendCommand does, and checks its outcome
+     * against `expectations`: that it completes within the maxDuration budget,
+     * in milliseconds, that its reply equals the equals value, and whether it
+     * exists, that is isn't null. A failed command fails its expectations.
+     *
+     * The outcome is recorded by the redis_expectations rate metric, and, when
+     * maxDuration is set, whether the budget was exceeded by the
+     * redis_budget_exceeded one, both tagged with the command, so that Redis
+     * SLOs can be enforced by thresholds.
+     *
+     * The promise resolves with {ok, duration, reply, failures}: whether all
+     * the expectations were met, the duration of the command, in milliseconds,
+     * including waiting for a connection and its retries, its reply, and a
+     * description of each unmet expectation.
+     */
+    expect(command: string, args: any[], expectations: {[key: string]: any}): Promise<any>;
+
+    /**
+     * expire sets a timeout on key, after which the key will automatically
+     * be deleted.
+     * Note that calling Expire with a non-positive timeout will result in
+     * the key being deleted rather than expired.
+     *
+     * The nx, xx, gt, and lt options only set the timeout under the matching
+     * condition, and require Redis 7.
+     */
+    expire(key: string, seconds: number, options?: {nx?: boolean, xx?: boolean, gt?: boolean, lt?: boolean}): Promise<boolean>;
+
+    /**
+     * expireat is like Expire, except that the key expires at the provided
+     * absolute Unix time, in seconds. A time in the past deletes the key.
+     */
+    expireat(key: string, timestamp: number, options?: {nx?: boolean, xx?: boolean, gt?: boolean, lt?: boolean}): Promise<boolean>;
+
+    /**
+     * expiretime returns the absolute Unix time, in seconds, at which `key`
+     * expires. It requires Redis 7.
+     *
+     * The promise resolves with the expiration time, -1 if the key has no
+     * timeout, or -2 if the key does not exist.
+     */
+    expiretime(key: string): Promise<number>;
+
+    /**
+     * failover triggers a failover, promoting a replica to master, so that the
+     * behavior of the system under test can be observed while the load is
+     * running. Depending on the client's mode, it sends:
+     *   - FAILOVER to the master, for single-node clients.
+     *   - SENTINEL FAILOVER to the sentinels, for sentinel clients.
+     *   - CLUSTER FAILOVER to the `node` replica, for cluster clients.
+     *
+     * The promise resolves with "OK" once the failover started. It completes
+     * asynchronously.
+     */
+    failover(options?: {to?: string, force?: boolean, abort?: boolean, timeout?: number, node?: string, takeover?: boolean}): Promise<string>;
+
+    /**
+     * fcall calls the Redis 7 `function`, loaded with functionLoad, with the
+     * provided keys and arguments.
+     *
+     * The promise resolves with the function's reply, null standing for a nil
+     * reply.
+     */
+    fcall(name: string, keys: string[], ...args: any[]): Promise<any>;
+
+    /**
+     * fcallRo is like Fcall, for functions flagged as read-only, which can be
+     * called on replicas.
+     */
+    fcallRo(name: string, keys: string[], ...args: any[]): Promise<any>;
+
+    /**
+     * flushAll is like FlushDb, except that it deletes the keys of all the
+     * logical databases of the server.
+     */
+    flushAll(options?: {async?: boolean}): Promise<string>;
+
+    /**
+     * flushDb deletes all the keys of the client's logical database, such as to
+     * isolate tests from each other. Cluster clients flush every master node.
+     *
+     * As flushing is dangerous, flushDb throws unless the allowFlush option is
+     * set, and with the keyPrefix option, whose namespace it would escape.
+     *
+     * The promise resolves with "OK".
+     */
+    flushDb(options?: {async?: boolean}): Promise<string>;
+
+    /**
+     * ftAggregate runs the RediSearch `query` against `index`, and processes
+     * the matching documents through the pipeline of `stages`, as {groupBy,
+     * reduce}, {sortBy, max}, {apply, as}, {filter}, or {limit} objects.
+     *
+     * The promise resolves with {total, rows}: the number of rows reported by
+     * RediSearch, and the resulting rows, as objects mapping each property to
+     * its value.
+     */
+    ftAggregate(index: string, query: string, stages: object[]): Promise<{total: number, rows: {[property: string]: any}[]}>;
+
+    /**
+     * ftCreate creates the RediSearch `index`, indexing the fields described by
+     * `schema`, as {name, type, as, weight, separator, sortable, noIndex}
+     * objects, of the hashes, or JSON documents, whose key starts with one of
+     * the `prefix` option's prefixes. Vector fields are described by their
+     * algorithm, vectorType, dim, and distanceMetric instead.
+     *
+     * The promise resolves with "OK".
+     */
+    ftCreate(index: string, schema: {name: string, type: string, as?: string, weight?: number, separator?: string, sortable?: boolean, noIndex?: boolean, algorithm?: string, vectorType?: string, dim?: number, distanceMetric?: string, m?: number, efConstruction?: number}[], options?: {on?: string, prefix?: string[]}): Promise<string>;
+
+    /**
+     * ftDropIndex drops the RediSearch `index`. The indexed documents are kept,
+     * unless the deleteDocuments option is set.
+     *
+     * The promise resolves with "OK".
+     */
+    ftDropIndex(index: string, options?: {deleteDocuments?: boolean}): Promise<string>;
+
+    /**
+     * ftSearch runs the RediSearch `query` against `index`.
+     *
+     * The promise resolves with {total, documents}: the total number of
+     * matching documents, and the documents returned, as {id, fields} objects.
+     * With the noContent option set, the documents only hold their id.
+     *
+     * Vector similarity queries, such as "*=>[KNN 10 @vec $vector AS dist]",
+     * are given their vector as a param, as a Float32Array, or Float64Array,
+     * and, with the scoreField option set to the name of the distance field,
+     * "dist", the documents also hold their distance, as a number, as score.
+     */
+    ftSearch(index: string, query: string, options?: {noContent?: boolean, return?: string[], sortBy?: string, sortOrder?: string, limit?: {offset?: number, num: number}, params?: {[name: string]: any}, dialect?: number, scoreField?: string}): Promise<{total: number, documents: {id: string, fields?: {[field: string]: string}, score?: number}[]}>;
+
+    /**
+     * functionLoad loads the Redis 7 functions library whose source is `code`.
+     *
+     * The promise resolves with the name of the library. It is rejected if the
+     * library already exists, unless the `replace` option is set.
+     */
+    functionLoad(code: string, options?: {replace?: boolean}): Promise<string>;
+
+    /**
+     * geoadd adds the provided locations, as {longitude, latitude, member}
+     * objects, to the geospatial index stored at `key`, or updates the
+     * coordinates of the members already in it.
+     *
+     * The promise resolves with the number of members added. Calls exceeding
+     * the commandChunkSize option are split in pipelined chunks.
+     */
+    geoadd(key: string, locations: {longitude: number, latitude: number, member: string}[]): Promise<number>;
+
+    /**
+     * geodist returns the distance between `member1` and `member2` in the
+     * geospatial index stored at `key`, in `unit`: "m", "km", "ft", or "mi".
+     * It defaults to meters.
+     *
+     * The promise resolves with the distance, or null if any of the members
+     * does not exist.
+     */
+    geodist(key: string, member1: string, member2: string, unit?: string): Promise<number | null>;
+
+    /**
+     * geopos returns the coordinates of the provided members of the geospatial
+     * index stored at `key`.
+     *
+     * The promise resolves with an array of {longitude, latitude} objects, in
+     * the order of the members, null standing for a missing member.
+     */
+    geopos(key: string, ...members: string[]): Promise<({longitude: number, latitude: number} | null)[]>;
+
+    /**
+     * geosearch returns the members of the geospatial index stored at `key`
+     * within the area described by `query`: around a `member` of the index, or
+     * `longitude` and `latitude` coordinates, within a `radius`, or a box of
+     * `width` and `height`.
+     *
+     * The promise resolves with an array of members, or, when any of the
+     * `withCoord`, `withDist`, and `withHash` options is set, with an array of
+     * {member, distance, coordinates: {longitude, latitude}, hash} objects,
+     * holding the requested properties.
+     */
+    geosearch(key: string, query: {member?: string, longitude?: number, latitude?: number, radius?: number, width?: number, height?: number, unit?: string, sort?: "asc" | "desc", count?: number, any?: boolean, withCoord?: boolean, withDist?: boolean, withHash?: boolean}): Promise<string[] | {member: string, distance?: number, coordinates?: {longitude: number, latitude: number}, hash?: number}[]>;
+
+    /**
+     * get returns the value for the given key.
+     *
+     * If the key does not exist, the promise is rejected with an error.
+     *
+     * When the `cacheMs` option is set, the value read is cached by the client
+     * for that many milliseconds, and subsequent gets of the same key, with the
+     * `cacheMs` option set, are served from the cache without hitting Redis.
+     * The cache is specific to the client, and thus to the VU, and is not
+     * invalidated by writes to the key, unless the clientTracking option is
+     * set, see readCache.
+     */
+    get(key: string, options?: {cacheMs?: number}): Promise<string>;
+
+    /**
+     * getBuffer is like Get, but resolves the value of `key` as an ArrayBuffer.
+     * It doesn't support the `cacheMs` option, as the cache holds strings.
+     */
+    getBuffer(key: string): Promise<ArrayBuffer>;
+
+    /**
+     * getDel gets the value of key and deletes the key.
+     *
+     * If the key does not exist, the promise is rejected with an error.
+     */
+    getDel(key: string): Promise<string>;
+
+    /**
+     * getDelBuffer is like GetDel, but resolves the value of `key` as an
+     * ArrayBuffer.
+     */
+    getDelBuffer(key: string): Promise<ArrayBuffer>;
+
+    /**
+     * getJSON returns the value of `key`, parsed as JSON.
+     *
+     * The promise resolves with the parsed value. If the key does not exist,
+     * or its value is not valid JSON, the promise is rejected with an error.
+     */
+    getJSON(key: string): Promise<any>;
+
+    /**
+     * getRandom gets the value of a key picked as PickKey does.
+     *
+     * The promise resolves with an object holding the `key` picked, and its
+     * `value`, or null if the key doesn't exist, so that scripts can account
+     * for hits and misses.
+     */
+    getRandom(options: {pattern: string, count: number, distribution?: "uniform" | "zipfian" | "hotspot", skew?: number, hotspotFraction?: number, hotspotRate?: number}): Promise<{key: string, value: string | null}>;
+
+    /**
+     * getSet sets the value of key to value and returns the old value stored
+     *
+     * If the provided value is not a supported type, the promise is rejected with an error.
+     * The value can be binary: ArrayBuffer or Uint8Array.
+     */
+    getSet(key: string, value: any): Promise<string>;
+
+    /**
+     * getVerified returns the value of `key`, as set by setChecksummed, once
+     * its checksum is verified.
+     *
+     * Each value verified is recorded by the redis_data_corruption rate metric:
+     * as corrupted if its checksum doesn't match, or is missing, in which case
+     * the promise is rejected with an error, or as intact otherwise. If the key
+     * does not exist, the promise is rejected with an error, and nothing is
+     * recorded, as no value was read.
+     */
+    getVerified(key: string): Promise<string>;
+
+    /**
+     * getbit returns the bit at `offset` in the string value stored at `key`.
+     *
+     * The promise resolves with the bit, 0 standing for offsets beyond the
+     * string's length, and missing keys.
+     */
+    getbit(key: string, offset: number): Promise<number>;
+
+    /**
+     * getex returns the value of `key`, and atomically updates its time to
+     * live according to the options: the ex, px, exat, and pxat options set it
+     * as they do for set, and persist removes it. Without options, it behaves
+     * like Get.
+     *
+     * If the key does not exist, the promise is rejected with an error.
+     */
+    getex(key: string, options?: {ex?: number, px?: number, exat?: number, pxat?: number, persist?: boolean}): Promise<string>;
+
+    /**
+     * getrange returns the substring of the string stored at `key` between the
+     * `start` and `end` offsets, both included. Negative offsets count from the
+     * end of the string.
+     *
+     * The promise resolves with an empty string if the key does not exist.
+     */
+    getrange(key: string, start: number, end: number): Promise<string>;
+
+    /**
+     * hdel deletes the specified fields from the hash stored at `key`.
+     *
+     * Fields can be binary: ArrayBuffer or Uint8Array.
+     */
+    hdel(key: string, ...fields: (string | ArrayBuffer | Uint8Array)[]): Promise<number>;
+
+    /**
+     * healthCheck checks the server is reachable, and responsive, such as in
+     * the setup function, to fail fast before the load starts.
+     *
+     * The promise resolves with an object holding the `latencyMs` of a PING,
+     * in milliseconds, and the server's `version`, and `mode`, "standalone",
+     * "sentinel", or "cluster", as reported by INFO. The promise is rejected
+     * if the server can't be reached.
+     */
+    healthCheck(): Promise<{latencyMs: number, version: string, mode: string}>;
+
+    /**
+     * hget returns the value associated with `field` in the hash stored at `key`.
+     *
+     * If the hash does not exist, this command rejects the promise with an error.
+     *
+     * `field` can be binary: ArrayBuffer or Uint8Array.
+     */
+    hget(key: string, field: string | ArrayBuffer | Uint8Array): Promise<string>;
+
+    /**
+     * hgetBuffer is like Hget, but resolves the value associated with `field`
+     * in the hash stored at `key` as an ArrayBuffer.
+     */
+    hgetBuffer(key: string, field: string | ArrayBuffer | Uint8Array): Promise<ArrayBuffer>;
+
+    /**
+     * hgetall returns all fields and values of the hash stored at `key`.
+     *
+     * If the hash does not exist, this command rejects the promise with an error.
+     */
+    hgetall(key: string): Promise<{[field: string]: string}>;
+
+    /**
+     * hincrby increments the integer value of `field` in the hash stored at `key`
+     * by `increment`. If `key` does not exist, a new key holding a hash is created.
+     * If `field` does not exist the value is set to 0 before the operation is
+     * set to 0 before the operation is performed.
+     */
+    hincrby(key: string, field: string, increment: number): Promise<number>;
+
+    /**
+     * hkeys returns all fields of the hash stored at `key`.
+     *
+     * If the hash does not exist, this command rejects the promise with an error.
+     */
+    hkeys(key: string): Promise<string[]>;
+
+    /**
+     * hkeysBuffer is like Hkeys, but resolves the fields of the hash stored at
+     * `key` as ArrayBuffer objects.
+     */
+    hkeysBuffer(key: string): Promise<ArrayBuffer[]>;
+
+    /**
+     * hlen returns the number of fields in the hash stored at `key`.
+     *
+     * If the hash does not exist, this command rejects the promise with an error.
+     */
+    hlen(key: string): Promise<number>;
+
+    /**
+     * hmset sets the fields of the hash stored at `key` to the values of the
+     * `values` object. If the `key` does not exist, a new key holding a hash is
+     * created. Fields that already exist in the hash are overwritten.
+     *
+     * Values can be binary: ArrayBuffer or Uint8Array. Calls exceeding the
+     * commandChunkSize option are split in pipelined chunks.
+     */
+    hmset(key: string, values: {[field: string]: any}): Promise<string>;
+
+    /**
+     * hrandfield returns random fields of the hash stored at `key`: one by
+     * default, or up to `count` distinct fields, or exactly `-count` possibly
+     * repeated fields when `count` is negative.
+     *
+     * The promise resolves with an array of fields, or, with the `withValues`
+     * option set, of {field, value} objects. The array is empty if the hash
+     * does not exist.
+     */
+    hrandfield(key: string, options?: {count?: number, withValues?: boolean}): Promise<string[] | {field: string, value: string}[]>;
+
+    /**
+     * hscan iterates over the fields of the hash stored at `key`, starting
+     * from `cursor`: 0 to start a new iteration, or the cursor returned by the
+     * previous call. The `match` and `count` options are passed to HSCAN as the
+     * MATCH and COUNT arguments.
+     *
+     * The promise resolves with an object holding the `cursor` to continue the
+     * iteration from, as a string, which is "0" once the iteration is
+     * complete, and the `entries` read, mapping fields to their values.
+     */
+    hscan(key: string, cursor: number | string, options?: {match?: string, count?: number}): Promise<{cursor: string, entries: {[field: string]: string}}>;
+
+    /**
+     * hscanAll iterates over all the fields of the hash stored at `key` with
+     * HSCAN, handling the cursors. The promise resolves with an object mapping
+     * the fields matching the `match` option to their values.
+     */
+    hscanAll(key: string, options?: {match?: string, count?: number}): Promise<{[field: string]: string}>;
+
+    /**
+     * hset sets the specified field in the hash stored at `key` to `value`.
+     * If the `key` does not exist, a new key holding a hash is created.
+     * If `field` already exists in the hash, it is overwritten.
+     *
+     * If the hash does not exist, this command rejects the promise with an error.
+     *
+     * Both `field` and `value` can be binary: ArrayBuffer or Uint8Array.
+     *
+     * Several fields can be set at once by passing an object mapping each
+     * field to its value as `field`, and no `value`. Calls exceeding the
+     * commandChunkSize option are then split in pipelined chunks.
+     */
+    hset(key: string, field: string | ArrayBuffer | Uint8Array, value: string | ArrayBuffer | Uint8Array): Promise<number>;
+    hset(key: string, values: {[field: string]: any}): Promise<number>;
+
+    /**
+     * hsetnx sets the specified field in the hash stored at `key` to `value`,
+     * only if `field` does not yet exist. If `key` does not exist, a new key
+     * holding a hash is created. If `field` already exists, this operation
+     * has no effect.
+     */
+    hsetnx(key: string, field: string, value: string): Promise<boolean>;
+
+    /**
+     * hvals returns all values of the hash stored at `key`.
+     *
+     * If the hash does not exist, this command rejects the promise with an error.
+     */
+    hvals(key: string): Promise<string[]>;
+
+    /**
+     * incr increments the number stored at `key` by one. If the key does
+     * not exist, it is set to zero before performing the operation. An
+     * error is returned if the key contains a value of the wrong type, or
+     * contains a string that cannot be represented as an integer.
+     */
+    incr(key: string): Promise<number>;
+
+    /**
+     * incrBy increments the number stored at `key` by `increment`. If the key does
+     * not exist, it is set to zero before performing the operation. An
+     * error is returned if the key contains a value of the wrong type, or
+     * contains a string that cannot be represented as an integer.
+     */
+    incrBy(key: string, increment: number): Promise<number>;
+
+    /**
+     * info returns the server's information and statistics, as reported by
+     * INFO for the provided sections, or for the default ones if none is
+     * provided.
+     *
+     * The promise resolves with an object mapping each field to its value.
+     * Numeric values are converted to numbers, and values made of
+     * comma-separated key=value pairs, such as those of the keyspace section,
+     * to objects.
+     */
+    info(...sections: string[]): Promise<{[field: string]: any}>;
+
+    /**
+     * isConnected returns true if the client is connected to redis.
+     */
+    isConnected(): boolean;
+
+    /**
+     * jobCount returns the number of jobs waiting in the work queue stored at
+     * `queue`.
+     *
+     * The promise resolves with zero if `queue` does not exist.
+     */
+    jobCount(queue: string): Promise<number>;
+
+    /**
+     * jsonDel deletes the JSON values at `path`, "$" by default, in the
+     * RedisJSON document stored at `key`. Deleting the root path deletes the
+     * key.
+     *
+     * The promise resolves with the number of values deleted.
+     */
+    jsonDel(key: string, path?: string): Promise<number>;
+
+    /**
+     * jsonGet returns the JSON values at the provided `paths` in the RedisJSON
+     * document stored at `key`, or the whole document if none are provided.
+     *
+     * The promise resolves with the parsed value. As RedisJSON replies to paths
+     * starting with "$" with the array of values they match, and to several
+     * paths with an object mapping each path to its values, so does the
+     * resolved value. If `key` does not exist, the promise resolves with null.
+     */
+    jsonGet(key: string, ...paths: string[]): Promise<any>;
+
+    /**
+     * jsonMGet returns the JSON values at `path`, "$" by default, in the
+     * RedisJSON documents stored at `keys`.
+     *
+     * The promise resolves with an array holding the parsed value of each key,
+     * in order, null standing for missing keys.
+     */
+    jsonMGet(keys: string[], path?: string): Promise<any[]>;
+
+    /**
+     * jsonNumIncrBy increments the numbers at `path`, in the RedisJSON document
+     * stored at `key`, by `increment`.
+     *
+     * The promise resolves with the parsed reply: the array of incremented
+     * values, null standing for the values matched by `path` which are not
+     * numbers, or the incremented value itself for paths not starting with "$".
+     */
+    jsonNumIncrBy(key: string, path: string, increment: number): Promise<any>;
+
+    /**
+     * jsonSet sets the JSON value at `path`, "$" by default, in the RedisJSON
+     * document stored at `key` to the JSON serialization of `value`. Setting the
+     * root path of a missing key creates the document.
+     *
+     * The promise resolves with "OK", or null if the `nx` or `xx` option
+     * prevented the value from being set. If `value` cannot be serialized to
+     * JSON, the promise is rejected with an error.
+     */
+    jsonSet(key: string, path: string, value: any, options?: {nx?: boolean, xx?: boolean}): Promise<string | null>;
+
+    /**
+     * latencyHistory returns the latency spikes of `event`, as recorded by the
+     * server's latency monitor.
+     *
+     * The promise resolves with an array holding an object for each spike,
+     * from the oldest to the most recent: the Unix `timestamp`, in
+     * milliseconds, it occurred at, and its `latency`, in milliseconds.
+     */
+    latencyHistory(event: string): Promise<{timestamp: number, latency: number}[]>;
+
+    /**
+     * latencyLatest returns the latest latency spikes of each event monitored
+     * by the server's latency monitor, which its latency-monitor-threshold
+     * configuration parameter enables.
+     *
+     * The promise resolves with an array holding an object for each event:
+     * its name, `event`, the Unix `timestamp`, in milliseconds, of its latest
+     * spike, and the `latest` and `max` spikes' latencies, in milliseconds.
+     */
+    latencyLatest(): Promise<{event: string, timestamp: number, latest: number, max: number}[]>;
+
+    /**
+     * latencyReset resets the latency spikes recorded for `events`, or for all
+     * the events if none is provided.
+     *
+     * The promise resolves with the number of events reset.
+     */
+    latencyReset(...events: string[]): Promise<number>;
+
+    /**
+     * lindex returns the specified element of the list stored at `key`.
+     * The index is zero-based. Negative indices can be used to designate
+     * elements starting at the tail of the list.
+     *
+     * If the list does not exist, this command rejects the promise with an error.
+     */
+    lindex(key: string, index: number): Promise<string>;
+
+    /**
+     * llen returns the length of the list stored at `key`. If `key`
+     * does not exist, it is interpreted as an empty list and 0 is returned.
+     *
+     * If the list does not exist, this command rejects the promise with an error.
+     */
+    llen(key: string): Promise<number>;
+
+    /**
+     * lmove atomically removes the element at the `from` end, "left" or
+     * "right", of the list stored at `source`, and pushes it at the `to` end of
+     * the list stored at `destination`. It is the non-blocking variant of
+     * Blmove.
+     *
+     * The promise resolves with the moved element, or null if `source` is
+     * empty.
+     */
+    lmove(source: string, destination: string, from: "left" | "right", to: "left" | "right"): Promise<string | null>;
+
+    /**
+     * lmpop pops up to `count` elements, 1 by default, from the `from` end,
+     * "left" or "right", of the first non-empty list among `keys`. It requires
+     * Redis 7.
+     *
+     * The promise resolves with an object holding the `key` the elements were
+     * popped from and its `values`, or null if all the lists are empty.
+     */
+    lmpop(keys: string[], from: "left" | "right", count?: number): Promise<{key: string, values: string[]} | null>;
+
+    /**
+     * lock acquires the lock stored at `key`, with SET NX, so that a single
+     * holder, across VUs and k6 instances, gets it at a time. The lock is held
+     * with a random token, so that only its holder can release, or extend, it.
+     *
+     * Locks are acquired on the single Redis instance, or cluster shard, `key`
+     * belongs to, rather than on several independent masters as Redlock does.
+     *
+     * The promise resolves with the lock, exposing the release and extend
+     * methods. If the lock is still held by another holder after the provided
+     * number of retries, the promise is rejected with an error.
+     */
+    lock(key: string, options?: {ttl?: number, retries?: number, retryDelay?: number}): Promise<Lock>;
+
+    /**
+     * lpop removes and returns the first element of the list stored at `key`.
+     *
+     * If the list does not exist, this command rejects the promise with an error.
+     */
+    lpop(key: string): Promise<string>;
+
+    /**
+     * lpopBuffer is like Lpop, but resolves the first element of the list stored
+     * at `key` as an ArrayBuffer.
+     */
+    lpopBuffer(key: string): Promise<ArrayBuffer>;
+
+    /**
+     * lpos returns the index of `element` in the list stored at `key`.
+     *
+     * The promise resolves with the index, or null if `element` is not found.
+     * With the count option set, it resolves with an array of indexes instead.
+     */
+    lpos(key: string, element: string, options?: {rank?: number, count?: number, maxlen?: number}): Promise<number | number[] | null>;
+
+    /**
+     * lpush inserts all the specified values at the head of the list stored
+     * at `key`. If `key` does not exist, it is created as empty list before
+     * performing the push operations. When `key` holds a value that is not
+     * a list, and error is returned.
+     *
+     * Values can be binary: ArrayBuffer or Uint8Array.
+     */
+    lpush(key: string, ...values: any[]): Promise<number>;
+
+    /**
+     * lrange returns the specified elements of the list stored at `key`. The
+     * offsets start and stop are zero-based indexes. These offsets can be
+     * negative numbers, where they indicate offsets starting at the end of
+     * the list.
+     */
+    lrange(key: string, start: number, stop: number): Promise<string[]>;
+
+    /**
+     * lrangeBuffer is like Lrange, but resolves the elements of the list stored
+     * at `key` as ArrayBuffer objects.
+     */
+    lrangeBuffer(key: string, start: number, stop: number): Promise<ArrayBuffer[]>;
+
+    /**
+     * lrem removes the first `count` occurrences of `value` from the list stored
+     * at `key`. If `count` is positive, elements are removed from the beginning of the list.
+     * If `count` is negative, elements are removed from the end of the list.
+     * If `count` is zero, all elements matching `value` are removed.
+     *
+     * If the list does not exist, this command rejects the promise with an error.
+     */
+    lrem(key: string, count: number, value: string): Promise<number>;
+
+    /**
+     * lset sets the list element at `index` to `element`.
+     *
+     * If the list does not exist, this command rejects the promise with an error.
+     */
+    lset(key: string, index: number, element: string): Promise<string>;
+
+    /**
+     * memoryProfile reports the memory taken by the keys matching the
+     * glob-style `pattern`, such as to size a dataset generated by a test.
+     *
+     * The keyspace is scanned with SCAN, and a uniform sample of sampleSize
+     * matching keys is measured with MEMORY USAGE, and TYPE, which are
+     * pipelined. The promise resolves with the number of matching `keys`, the
+     * number of `sampled` keys, their total `bytes`, and `avgBytes`, the
+     * `estimatedBytes` of all the matching keys, extrapolated from the sample,
+     * and the `sampled`, `bytes`, and `avgBytes` of each type of value of the
+     * sample, as `types`.
+     */
+    memoryProfile(pattern: string, options?: {sampleSize?: number, count?: number}): Promise<object>;
+
+    /**
+     * memoryUsage returns the number of bytes the value of `key`, and the key
+     * itself, take in the server's memory, as reported by MEMORY USAGE.
+     *
+     * The promise resolves with the number of bytes, or null if the key doesn't
+     * exist.
+     */
+    memoryUsage(key: string, options?: {samples?: number}): Promise<number | null>;
+
+    /**
+     * mget returns the values associated with the specified keys.
+     *
+     * The keys can be provided as arguments, or as a single array, and be
+     * followed by an options object. With the `asObject` option set, the
+     * values are resolved as an object keyed by key, and with the `omitNulls`
+     * option set, the null values are left out. With the `partial` option set,
+     * the keys are fetched with a command per cluster hash slot, and those that
+     * could not be fetched are reported, rather than failing the whole call,
+     * see mgetPartial.
+     */
+    mget(...keys: string[]): Promise<any[]>;
+    mget(keys: string[], options?: {asObject?: boolean, omitNulls?: boolean, partial?: boolean}): Promise<any[] | {[key: string]: any}>;
+
+    /**
+     * migrate moves `keys` to the server listening on `host` and `port`, which
+     * the server connected to sends them to directly. The keys are deleted
+     * from the source server, unless the copy option is set.
+     *
+     * The promise resolves with "OK", or "NOKEY" if none of the keys exist.
+     */
+    migrate(host: string, port: number, keys: string[], options?: {db?: number, timeoutMs?: number, copy?: boolean, replace?: boolean, username?: string, password?: string}): Promise<string>;
+
+    /**
+     * mset sets the provided keys to their respective values, provided as an
+     * object, or as a Map.
+     *
+     * If any of the provided values is not a supported type, the promise is
+     * rejected with an error. Values can be binary: ArrayBuffer or Uint8Array.
+     * Calls exceeding the commandChunkSize option are split in pipelined
+     * chunks. With the `partial` option set, the keys are set
+     * with a command per cluster hash slot, and those that could not be set
+     * are reported, rather than failing the whole call, see msetPartial.
+     */
+    mset(values: {[key: string]: any} | Map<string, any>, options?: {partial?: boolean}): Promise<string>;
+
+    /**
+     * multi returns a new, empty, pipeline executing its commands in a
+     * MULTI/EXEC transaction.
+     */
+    multi(): Pipeline;
+
+    /**
+     * objectEncoding returns the internal encoding Redis uses to store the value
+     * of `key`, such as "listpack" or "hashtable", so that encoding transitions
+     * can be asserted on as the value grows.
+     *
+     * The promise resolves with the encoding, or null if the key doesn't exist.
+     */
+    objectEncoding(key: string): Promise<string | null>;
+
+    /**
+     * objectFreq returns the logarithmic access frequency counter of `key`, as
+     * reported by OBJECT FREQ. It requires the server's maxmemory-policy to be
+     * one of the LFU policies.
+     *
+     * The promise resolves with the counter, or null if the key doesn't exist.
+     */
+    objectFreq(key: string): Promise<number | null>;
+
+    /**
+     * objectIdletime returns the number of seconds elapsed since `key` was
+     * last accessed, as reported by OBJECT IDLETIME. It is unavailable with the
+     * LFU maxmemory policies.
+     *
+     * The promise resolves with the idle time, or null if the key doesn't
+     * exist.
+     */
+    objectIdletime(key: string): Promise<number | null>;
+
+    /**
+     * onKeyEvent subscribes to the keyspace notifications of the client's
+     * database, and calls `callback` with an object holding the `event`, the
+     * `key` it affected, and the `database` of each notification.
+     *
+     * The glob-style `patterns` match the names of the events, such as
+     * "expired" or "evicted", or, with the keyspace option, the keys. The
+     * server only publishes the notifications enabled by its
+     * notify-keyspace-events parameter, which the notifyKeyspaceEvents option
+     * sets beforehand.
+     *
+     * Notifications share the client's subscription, and its connection. The
+     * promise resolves with the channel patterns subscribed to, once the server
+     * confirmed the subscription, so that they can be passed to punsubscribe.
+     *
+     * Cluster nodes only publish the notifications of their own keys, so
+     * OnKeyEvent isn't supported by cluster clients.
+     */
+    onKeyEvent(patterns: string | string[], callback: (event: {event: string, key: string, database: number}) => void, options?: {keyspace?: boolean, notifyKeyspaceEvents?: string}): Promise<string[]>;
+
+    /**
+     * options returns the effective options of the Client, as resolved from the
+     * ones it was instantiated with, to help diagnosing misconfigurations.
+     *
+     * Durations are expressed in milliseconds, and zero values stand for
+     * go-redis' defaults. Passwords are redacted. The returned `hash` identifies
+     * the underlying go-redis client: clients reporting the same hash share the
+     * same connection pool.
+     */
+    options(): {[key: string]: any};
+
+    /**
+     * persist removes the existing timeout on key.
+     */
+    persist(key: string): Promise<boolean>;
+
+    /**
+     * pexpire is like Expire, except that the timeout is set in milliseconds.
+     */
+    pexpire(key: string, milliseconds: number, options?: {nx?: boolean, xx?: boolean, gt?: boolean, lt?: boolean}): Promise<boolean>;
+
+    /**
+     * pfadd adds the provided members to the HyperLogLog stored at `key`,
+     * creating it if it does not exist.
+     *
+     * The promise resolves with 1 if the HyperLogLog's estimated cardinality
+     * changed, and 0 otherwise. Members can be binary: ArrayBuffer or
+     * Uint8Array. Calls exceeding the commandChunkSize option are split in
+     * pipelined chunks.
+     */
+    pfadd(key: string, ...members: any[]): Promise<number>;
+
+    /**
+     * pfcount returns the approximate cardinality of the HyperLogLog stored at
+     * `key`, or of the union of the HyperLogLogs stored at the provided keys.
+     *
+     * The promise resolves with the approximate cardinality, or 0 if none of
+     * the keys exist.
+     */
+    pfcount(...keys: string[]): Promise<number>;
+
+    /**
+     * pfmerge merges the HyperLogLogs stored at the `sources` keys into the
+     * HyperLogLog stored at `destination`, creating it if it does not exist.
+     *
+     * The promise resolves with "OK".
+     */
+    pfmerge(destination: string, ...sources: string[]): Promise<string>;
+
+    /**
+     * pickKey returns the name of a key picked following a distribution, such
+     * as to access the keys created by seed the way a cache's clients would.
+     *
+     * The keys are named after the pattern option, where {i} is replaced by an
+     * index between 0 and count - 1. With the default "uniform" distribution,
+     * all the indexes are equally likely. With the "zipfian" distribution, the
+     * probability of an index decreases as a power, skew, of its rank, so that
+     * a few keys get most of the accesses. With the "hotspot" distribution, the
+     * hotspotFraction of the keys with the lowest indexes get the hotspotRate
+     * of the accesses.
+     */
+    pickKey(options: {pattern: string, count: number, distribution?: "uniform" | "zipfian" | "hotspot", skew?: number, hotspotFraction?: number, hotspotRate?: number}): string;
+
+    /**
+     * pipeline returns a new, empty, pipeline sending its commands through the
+     * client.
+     */
+    pipeline(): Pipeline;
+
+    /**
+     * poolStats returns the statistics of the client's connection pool, summed
+     * over the cluster's nodes for cluster clients:
+     *   - hits: the number of times an idle connection was found in the pool.
+     *   - misses: the number of times no idle connection was found in the pool.
+     *   - timeouts: the number of times waiting for a connection timed out.
+     *   - totalConns: the number of connections in the pool.
+     *   - idleConns: the number of idle connections in the pool.
+     *   - staleConns: the number of stale connections removed from the pool.
+     *
+     * As the connectionCount method, it reports on the connection pool shared
+     * by all the VUs using the same client options.
+     */
+    poolStats(): Promise<{hits: number, misses: number, timeouts: number, totalConns: number, idleConns: number, staleConns: number}>;
+
+    /**
+     * popJob removes and returns the oldest job of the work queue stored at
+     * `queue`, waiting up to `timeout` seconds, with BLPOP, for one to be
+     * pushed if the queue is empty.
+     *
+     * The promise resolves with the job, or null if none was pushed within
+     * `timeout` seconds.
+     */
+    popJob(queue: string, timeout: number): Promise<string | null>;
+
+    /**
+     * psubscribe subscribes the client to the provided glob-style patterns,
+     * such as "news.*", and calls `handler` with an object holding the
+     * `pattern`, `channel`, and `payload` of each message published to a
+     * channel matching them.
+     *
+     * Patterns share the subscription, and thus the connection, of the
+     * channels subscribed to with Subscribe. A message published to a channel
+     * matching several patterns, or both a pattern and a channel subscribed
+     * to, is delivered once for each of them, as Redis does.
+     *
+     * The promise resolves once the server confirmed the subscription to all
+     * the provided patterns.
+     */
+    psubscribe(patterns: string | string[], handler: (message: {pattern: string, channel: string, payload: string}) => void): Promise<void>;
+
+    /**
+     * pttl returns the remaining time to live, in milliseconds, of a key that
+     * has a timeout.
+     *
+     * The promise resolves with the time to live, -1 if the key has no
+     * timeout, or -2 if the key does not exist.
+     */
+    pttl(key: string): Promise<number>;
+
+    /**
+     * pubSubRoundTrip measures the time it takes for a message published to
+     * `channel` to be delivered back to a subscriber of it.
+     *
+     * It subscribes to `channel` on a dedicated connection, publishes a
+     * timestamped message once the subscription is confirmed, and waits for
+     * that very message to be received: other messages published to the
+     * channel are ignored. The subscription is closed once the message is
+     * received, or the VU's context is done.
+     *
+     * The promise resolves with the round-trip latency, in milliseconds. Call
+     * it repeatedly to build a latency distribution.
+     */
+    pubSubRoundTrip(channel: string): Promise<number>;
+
+    /**
+     * publish publishes `message` to `channel`.
+     *
+     * The promise resolves with the number of clients that received the
+     * message.
+     */
+    publish(channel: string, message: any): Promise<number>;
+
+    /**
+     * punsubscribe unsubscribes the client from the provided patterns, or from
+     * all the patterns it is subscribed to if none is provided, as Unsubscribe
+     * does for channels.
+     */
+    punsubscribe(...patterns: string[]): Promise<void>;
+
+    /**
+     * pushJob appends `jobs` to the work queue stored, as a list, at `queue`, so
+     * that each of them is popped by a single caller, across VUs and k6
+     * instances. It is typically used in the setup function, to distribute
+     * unique test data, such as user IDs or tokens.
+     *
+     * Jobs can be binary: ArrayBuffer or Uint8Array.
+     *
+     * The promise resolves with the number of jobs in the queue after the push.
+     */
+    pushJob(queue: string, ...jobs: any[]): Promise<number>;
+
+    /**
+     * quorumGet reads the value of `key` from the master serving it, and from
+     * its replicas, concurrently, and reports whether they agree. It is meant
+     * to measure replication consistency windows in cluster tests.
+     *
+     * The `replicas` option limits the number of replicas read from. Replicas
+     * are read from in READONLY mode, regardless of the client's options.
+     *
+     * The promise resolves with an object holding the address of the `primary`
+     * node, the `values` read from each node, indexed by address, null
+     * standing for a missing key, and an `agreed` flag, true when all the
+     * values are equal. It is rejected if any of the reads fails.
+     *
+     * QuorumGet is only supported by cluster clients.
+     */
+    quorumGet(key: string, options?: {replicas?: number}): Promise<{primary: string, values: {[address: string]: string | null}, agreed: boolean}>;
+
+    /**
+     * randomKey returns a random key.
+     *
+     * If the database is empty, the promise is rejected with an error.
+     */
+    randomKey(): Promise<string>;
+
+    /**
+     * rateLimit counts a request against the rate limiter stored at `key`,
+     * which allows `limit` requests per `window` milliseconds, so that VUs,
+     * across k6 instances, can pace their requests together.
+     *
+     * The "fixed" algorithm counts the requests of windows starting with their
+     * first request, and the "sliding" one those of the last `window`
+     * milliseconds, more accurately but at the cost of storing each request.
+     * The limiter runs as a Lua script, so that concurrent requests can't race.
+     *
+     * The promise resolves with {allowed, remaining, resetAfter}: whether the
+     * request is allowed, the number of requests still allowed, and the number
+     * of milliseconds until more requests are allowed.
+     */
+    rateLimit(key: string, options: {limit: number, window: number, algorithm?: "fixed" | "sliding"}): Promise<{allowed: boolean, remaining: number, resetAfter: number}>;
+
+    /**
+     * rename renames `key` to `newKey`, overwriting `newKey` if it exists.
+     *
+     * The promise resolves with "OK", and is rejected if `key` doesn't exist.
+     */
+    rename(key: string, newKey: string): Promise<string>;
+
+    /**
+     * renamenx is like Rename, except that it doesn't overwrite `newKey`.
+     *
+     * The promise resolves with true if the key was renamed, and false if
+     * `newKey` already exists.
+     */
+    renamenx(key: string, newKey: string): Promise<boolean>;
+
+    /**
+     * restore creates `key` holding the `value` serialized by Dump, which is
+     * either an ArrayBuffer, or a Uint8Array. The key expires after `ttl`
+     * milliseconds, unless it is 0.
+     *
+     * The promise resolves with "OK", and is rejected if the key already
+     * exists and the replace option isn't set.
+     */
+    restore(key: string, ttl: number, value: ArrayBuffer | Uint8Array, options?: {replace?: boolean, absTtl?: boolean}): Promise<string>;
+
+    /**
+     * rpop removes and returns the last element of the list stored at `key`.
+     *
+     * If the list does not exist, this command rejects the promise with an error.
+     */
+    rpop(key: string): Promise<string>;
+
+    /**
+     * rpopBuffer is like Rpop, but resolves the last element of the list stored
+     * at `key` as an ArrayBuffer.
+     */
+    rpopBuffer(key: string): Promise<ArrayBuffer>;
+
+    /**
+     * rpush inserts all the specified values at the tail of the list stored
+     * at `key`. If `key` does not exist, it is created as empty list before
+     * performing the push operations.
+     *
+     * Values can be binary: ArrayBuffer or Uint8Array.
+     */
+    rpush(key: string, ...values: any[]): Promise<number>;
+
+    /**
+     * runOnNode sends a command to the cluster node at `addr`, bypassing the
+     * slot-based routing of commands. It allows node-level introspection, such
+     * as running INFO or CONFIG GET against each node, or reading from a
+     * specific replica.
+     *
+     * The node must be part of the cluster, as listed by ClusterNodes.
+     *
+     * RunOnNode is only supported by cluster clients.
+     */
+    runOnNode(address: string, command: string, ...args: any[]): Promise<any>;
+
+    /**
+     * sadd adds the specified members to the set stored at key.
+     * Specified members that are already a member of this set are ignored.
+     * If key does not exist, a new set is created before adding the specified members.
+     *
+     * Members can be binary: ArrayBuffer or Uint8Array. Calls exceeding the
+     * commandChunkSize option are split in pipelined chunks.
+     */
+    sadd(key: string, ...members: any[]): Promise<number>;
+
+    /**
+     * sampleConsumerLag periodically samples the lag of the consumer groups of
+     * the stream stored at `key`, as reported by XINFO GROUPS, and emits it as
+     * the redis_consumer_lag metric, tagged with the `stream` and `group`. It
+     * tells whether the consumers of each group keep up with the producers.
+     *
+     * Sampling runs in the background, until the VU's context is done, at the
+     * interval set by the `intervalMs` option, which defaults to one second.
+     * Groups whose lag can't be determined by the server are reported with a
+     * zero lag. Sampling errors following the first sample are ignored: the
+     * samples are skipped, and sampling goes on.
+     *
+     * The promise resolves once the first sample is emitted. If the stream's
+     * lag is already being sampled by the Client, or the first sample fails,
+     * the promise is rejected with an error.
+     */
+    sampleConsumerLag(key: string, options?: {intervalMs?: number}): Promise<void>;
+
+    /**
+     * sampleServerStats periodically samples the server's statistics, as
+     * reported by INFO, and emits its used memory, number of connected
+     * clients, and instantaneous operations per second, as the
+     * redis_used_memory, redis_connected_clients, and
+     * redis_instantaneous_ops_per_sec metrics, so that the server's state can
+     * be correlated with the load. Cluster clients sample each master, and tag
+     * the samples with its `address`.
+     *
+     * Sampling runs in the background, until the VU's context is done, at the
+     * interval set by the `intervalMs` option, which defaults to five seconds.
+     * Sampling errors following the first sample are ignored, as
+     * SampleConsumerLag does.
+     *
+     * The promise resolves once the first sample is emitted. If the server's
+     * statistics are already being sampled by the Client, or the first sample
+     * fails, the promise is rejected with an error.
+     */
+    sampleServerStats(options?: {intervalMs?: number}): Promise<void>;
+
+    /**
+     * scan iterates over the keyspace, starting from `cursor`: 0 to start a new
+     * iteration, or the cursor returned by the previous call. The `match`,
+     * `count`, and `type` options are passed to SCAN as the MATCH, COUNT, and
+     * TYPE arguments.
+     *
+     * The promise resolves with an object holding the `cursor` to continue the
+     * iteration from, as a string, which is "0" once the iteration is
+     * complete, and the `keys` read.
+     *
+     * Scan is not supported by cluster clients, as each node has a cursor of
+     * its own: scanAll scans all their nodes.
+     */
+    scan(cursor: number | string, options?: {match?: string, count?: number, type?: string}): Promise<{cursor: string, keys: string[]}>;
+
+    /**
+     * scanAll iterates over the whole keyspace with SCAN, handling the cursors,
+     * and returns the keys matching the `match`, `count`, and `type` options.
+     * Keys are listed once, even if SCAN returns them several times. Cluster
+     * clients scan all the master nodes.
+     */
+    scanAll(options?: {match?: string, count?: number, type?: string}): Promise<string[]>;
+
+    /**
+     * scanShard scans the whole keyspace, and returns the keys assigned to the
+     * shard `shardIndex` out of `shardCount`. It allows many VUs to
+     * cooperatively process the whole keyspace: each VU scanning a distinct
+     * shard index gets a distinct subset of the keys, and together, all the
+     * shard indexes cover all the keys.
+     *
+     * Keys are assigned to shards deterministically, based on the FNV-1a hash
+     * of their name, so the assignment doesn't depend on the order keys are
+     * scanned in, nor on the VU scanning them. Keys are listed once, even if
+     * SCAN returns them several times. Cluster clients scan all the master
+     * nodes.
+     *
+     * The `match`, `count`, and `type` options are passed to SCAN as the
+     * MATCH, COUNT, and TYPE arguments.
+     *
+     * The promise resolves with the array of keys of the shard.
+     */
+    scanShard(shardIndex: number, shardCount: number, options?: {match?: string, count?: number, type?: string}): Promise<string[]>;
+
+    /**
+     * scard returns the number of members of the set stored at `key`, or 0 if
+     * it doesn't exist.
+     */
+    scard(key: string): Promise<number>;
+
+    /**
+     * script returns a new Script of the provided Lua source.
+     */
+    script(source: string): Script;
+
+    /**
+     * scriptLoad loads the Lua `script` in the server's scripts cache, without
+     * executing it. The promise resolves with the script's SHA1 digest, to be
+     * passed to evalsha.
+     */
+    scriptLoad(script: string): Promise<string>;
+
+    /**
+     * sdiff returns the members of the set stored at the first of `keys` that
+     * aren't members of the sets stored at the other ones.
+     */
+    sdiff(keys: string[]): Promise<string[]>;
+
+    /**
+     * sdiffstore stores the difference of the sets stored at `keys`, as
+     * returned by Sdiff, in the set stored at `destination`, as Sinterstore
+     * does.
+     */
+    sdiffstore(destination: string, keys: string[]): Promise<number>;
+
+    /**
+     * seed populates the database with generated keys, such as to prepare the
+     * dataset of a test in its setup function. The keys are created in Go, in
+     * pipelines of pipelineSize keys, which is much faster than looping over
+     * commands in JS.
+     *
+     * The keys are named after the keyPattern option, where {i} is replaced
+     * by the index of each key, and hold strings, hashes, or sorted sets, of
+     * `elements` fields or members, depending on the type option. Values are
+     * random strings of valueSize bytes, generated deterministically, as
+     * randomValue does, so that seeding is reproducible. Existing keys are
+     * overwritten.
+     *
+     * The promise resolves with the number of keys created, once all of them
+     * were.
+     */
+    seed(options: {count: number, keyPattern?: string, valueSize?: number, type?: "string" | "hash" | "zset", elements?: number, pipelineSize?: number}): Promise<number>;
+
+    /**
+     * sendCommand sends a command to the redis server.
+     *
+     * It allows using any command, such as the ones of Redis modules, or of
+     * newer servers, without waiting for the client to support them. The
+     * arguments can be binary: ArrayBuffer or Uint8Array. The promise resolves
+     * with the raw reply: arrays resolve as JS arrays, and nil replies as null.
+     */
+    sendCommand(command: string, ...args: any[]): Promise<any>;
+
+    /**
+     * set the given key with the given value.
+     *
+     * If the provided value is not a supported type, the promise is rejected with an error.
+     * The value can be binary: ArrayBuffer or Uint8Array.
+     *
+     * The third argument is either the expiration, interpreted as seconds, or
+     * an object of {ex, px, exat, pxat, keepTtl, nx, xx, get} options.
+     *
+     * The promise resolves with "OK", or with null if the nx or xx option
+     * prevented the key from being set. With the get option, it resolves with
+     * the key's previous value instead, or null if it did not exist.
+     */
+    set(key: string, value: any, expirationOrOptions?: number | {ex?: number, px?: number, exat?: number, pxat?: number, keepTtl?: boolean, nx?: boolean, xx?: boolean, get?: boolean}): Promise<string | null>;
+
+    /**
+     * setChecksummed sets `key` to `value`, followed by its checksum, so that
+     * getVerified can tell whether the value read back is the one written, such
+     * as to use Redis as a correctness probe during soak tests. The checksum is
+     * computed by the client, with the algorithm option, crc32 by default, or
+     * sha256, and the key expires after the expiration option, in seconds, if
+     * set.
+     *
+     * If the provided value is not a supported type, or the options are
+     * invalid, the promise is rejected with an error.
+     */
+    setChecksummed(key: string, value: any, options?: {expiration?: number, algorithm?: "crc32" | "sha256"}): Promise<string>;
+
+    /**
+     * setJSON sets `key` to hold the JSON serialization of `value`, with a time
+     * to live equal to `expiration` seconds, as set does.
+     *
+     * The promise resolves with "OK". If `value` cannot be serialized to JSON,
+     * as is the case of functions, the promise is rejected with an error, and
+     * nothing is sent to Redis.
+     */
+    setJSON(key: string, value: any, expiration: number): Promise<string>;
+
+    /**
+     * setbit sets, or clears, the bit at `offset` in the string value stored at
+     * `key`, depending on `value`, which must be 0 or 1. The string is grown as
+     * needed, and created if it does not exist.
+     *
+     * The promise resolves with the bit's previous value.
+     */
+    setbit(key: string, offset: number, value: 0 | 1): Promise<number>;
+
+    /**
+     * setrange overwrites the string stored at `key` with `value`, starting at
+     * `offset`. The string is padded with zero bytes if it is shorter than
+     * `offset`, and created if the key does not exist.
+     *
+     * The value can be binary: ArrayBuffer or Uint8Array. The promise resolves
+     * with the length of the string after it was modified, in bytes.
+     */
+    setrange(key: string, offset: number, value: string | ArrayBuffer | Uint8Array): Promise<number>;
+
+    /**
+     * signal raises the signal identified by `name`, releasing the callers of
+     * waitFor, possibly running in distinct VUs or k6 instances, waiting for
+     * it, such as to start a phase of the test on every runner at once.
+     *
+     * The signal's value is stored at the key of the same name, so that the
+     * callers of waitFor arriving late see it, and published to the channel
+     * of the same name, for those waiting already. A signal stays raised until
+     * its key expires, or is deleted.
+     *
+     * The promise resolves with the number of callers of waitFor, as counted by
+     * Redis, the signal was delivered to.
+     */
+    signal(name: string, options?: {value?: string, ttlMs?: number}): Promise<number>;
+
+    /**
+     * sinter returns the members of the intersection of the sets stored at
+     * `keys`.
+     */
+    sinter(keys: string[]): Promise<string[]>;
+
+    /**
+     * sintercard returns the number of members of the intersection of the sets
+     * stored at `keys`, without transferring it. With a positive `limit`, the
+     * computation stops once the intersection reaches that many members. It
+     * requires Redis 7.
+     */
+    sintercard(keys: string[], limit?: number): Promise<number>;
+
+    /**
+     * sinterstore stores the intersection of the sets stored at `keys` in the
+     * set stored at `destination`, overwriting it, so that it is computed
+     * server-side, without transferring it.
+     *
+     * The promise resolves with the number of members of the resulting set.
+     */
+    sinterstore(destination: string, keys: string[]): Promise<number>;
+
+    /**
+     * sismember returns if member is a member of the set stored at key.
+     *
+     * `member` can be binary: ArrayBuffer or Uint8Array.
+     */
+    sismember(key: string, member: any): Promise<boolean>;
+
+    /**
+     * slowlogGet returns the `count` most recent entries of the server's slow
+     * log, or all of them if `count` is negative. Without `count`, the server
+     * returns 10 entries.
+     *
+     * The promise resolves with an array holding an object for each entry,
+     * from the most recent to the oldest: its `id`, the Unix `timestamp`, in
+     * milliseconds, it was logged at, its `duration`, in milliseconds, the
+     * `args` of the command, and the `clientAddr` and `clientName` of the
+     * client which sent it.
+     */
+    slowlogGet(count?: number): Promise<{id: number, timestamp: number, duration: number, args: string[], clientAddr: string, clientName: string}[]>;
+
+    /**
+     * slowlogLen returns the number of entries of the server's slow log.
+     */
+    slowlogLen(): Promise<number>;
+
+    /**
+     * slowlogReset empties the server's slow log, so that only the entries
+     * logged during the test are reported afterwards.
+     *
+     * The promise resolves with "OK".
+     */
+    slowlogReset(): Promise<string>;
+
+    /**
+     * smembers returns all members of the set stored at key.
+     */
+    smembers(key: string): Promise<string[]>;
+
+    /**
+     * smembersBuffer is like Smembers, but resolves the members of the set
+     * stored at `key` as ArrayBuffer objects.
+     */
+    smembersBuffer(key: string): Promise<ArrayBuffer[]>;
+
+    /**
+     * smismember returns whether each of `members` is a member of the set
+     * stored at `key`.
+     *
+     * Members can be binary: ArrayBuffer or Uint8Array.
+     */
+    smismember(key: string, ...members: any[]): Promise<boolean[]>;
+
+    /**
+     * smove moves `member` from the set stored at `source` to the set stored at
+     * `destination`.
+     *
+     * The promise resolves with true if the member was moved, or false if it
+     * isn't a member of `source`. `member` can be binary: ArrayBuffer or
+     * Uint8Array.
+     */
+    smove(source: string, destination: string, member: any): Promise<boolean>;
+
+    /**
+     * spop removes and returns a random element from the set value stored at key.
+     *
+     * If the set does not exist, the promise is rejected with an error.
+     */
+    spop(key: string): Promise<string>;
+
+    /**
+     * srandmember returns a random element from the set value stored at key.
+     *
+     * If the set does not exist, the promise is rejected with an error.
+     */
+    srandmember(key: string): Promise<string>;
+
+    /**
+     * srem removes the specified members from the set stored at key.
+     * Specified members that are not a member of this set are ignored.
+     * If key does not exist, it is treated as an empty set and this command returns 0.
+     *
+     * Members can be binary: ArrayBuffer or Uint8Array. Calls exceeding the
+     * commandChunkSize option are split in pipelined chunks.
+     */
+    srem(key: string, ...members: any[]): Promise<number>;
+
+    /**
+     * sscan iterates over the members of the set stored at `key`, starting
+     * from `cursor`, as hscan does over the fields of a hash.
+     *
+     * The promise resolves with an object holding the `cursor` to continue the
+     * iteration from, and the `members` read.
+     */
+    sscan(key: string, cursor: number | string, options?: {match?: string, count?: number}): Promise<{cursor: string, members: string[]}>;
+
+    /**
+     * sscanAll iterates over all the members of the set stored at `key` with
+     * SSCAN, handling the cursors. The promise resolves with the members
+     * matching the `match` option, listed once each.
+     */
+    sscanAll(key: string, options?: {match?: string, count?: number}): Promise<string[]>;
+
+    /**
+     * stats returns the latency of the commands sent by the client, and the
+     * clients derived from it with withTimeout, withDatabase, withUser, and
+     * withTags, so far, by command name: their `count`, their count of
+     * `errors`, and their `p50`, `p90`, `p99`, and `max` latencies, in
+     * milliseconds, such as to report Redis-specific latencies beyond k6's
+     * built-in trends.
+     *
+     * Latencies span from the moment commands are sent, once throttled, until
+     * their reply is received, including their retries. Those of the commands
+     * of pipelines and transactions are those of the pipeline as a whole.
+     *
+     * As the stats are kept by each Client, they only cover the commands sent
+     * by the VU it belongs to.
+     */
+    stats(): {[key: string]: any};
+
+    /**
+     * statsReset discards the latencies returned by the stats method, such as
+     * to leave those of a warm-up phase out.
+     */
+    statsReset(): void;
+
+    /**
+     * strlen returns the length, in bytes, of the string stored at `key`, or 0
+     * if the key does not exist.
+     */
+    strlen(key: string): Promise<number>;
+
+    /**
+     * subscribe subscribes the client to the provided channels, and calls
+     * `handler` with an object holding the `channel` and `payload` of each
+     * message published to them.
+     *
+     * All the channels a client subscribes to, over any number of calls, share
+     * a single subscription, and thus a single connection. Messages are
+     * delivered to the handlers in the order they are received, which preserves
+     * their order across channels.
+     *
+     * As long as the client is subscribed to channels, the VU's iteration keeps
+     * running. Use Unsubscribe to end the subscription.
+     *
+     * The promise resolves once the server confirmed the subscription to all
+     * the provided channels.
+     */
+    subscribe(channels: string | string[], handler: (message: {channel: string, payload: string}) => void): Promise<void>;
+
+    /**
+     * sunion returns the members of the union of the sets stored at `keys`.
+     */
+    sunion(keys: string[]): Promise<string[]>;
+
+    /**
+     * sunionstore stores the union of the sets stored at `keys` in the set
+     * stored at `destination`, as Sinterstore does.
+     */
+    sunionstore(destination: string, keys: string[]): Promise<number>;
+
+    /**
+     * swapdb swaps the logical databases `index1` and `index2`, so that the
+     * clients connected to either database immediately see the keys of the
+     * other one.
+     *
+     * The promise resolves with "OK".
+     */
+    swapdb(index1: number, index2: number): Promise<string>;
+
+    /**
+     * tailLog returns the last `bytes` bytes of the string log stored at `key`,
+     * using the GETRANGE command, without transferring the whole value.
+     *
+     * If the log is shorter than `bytes`, it is returned in its entirety. If `key`
+     * does not exist, the promise resolves with an empty string.
+     */
+    tailLog(key: string, bytes: number): Promise<string>;
+
+    /**
+     * topkAdd adds the provided items to the Top-K sketch stored at `key`.
+     *
+     * The promise resolves with an array holding the items expelled from the
+     * top-k list by the added items, which is empty if none were.
+     */
+    topkAdd(key: string, ...items: (string | number | boolean)[]): Promise<string[]>;
+
+    /**
+     * topkIncrBy increments the count of the items of the Top-K sketch stored
+     * at `key` by their value in `increments`.
+     *
+     * The promise resolves with an array holding the items expelled from the
+     * top-k list by the incremented items, which is empty if none were.
+     */
+    topkIncrBy(key: string, increments: {[item: string]: number}): Promise<string[]>;
+
+    /**
+     * topkList returns the top-k list of the Top-K sketch stored at `key`.
+     *
+     * The promise resolves with an array holding the items of the list, most
+     * frequent first, or with the `withCount` option set, an array of objects
+     * holding each `item`, and its `count`.
+     */
+    topkList(key: string, options?: {withCount?: boolean}): Promise<string[] | {item: string, count: number}[]>;
+
+    /**
+     * topkQuery returns whether the provided items are in the top-k list of
+     * the Top-K sketch stored at `key`.
+     *
+     * The promise resolves with an array holding, for each item, in order,
+     * whether it is in the list.
+     */
+    topkQuery(key: string, ...items: (string | number | boolean)[]): Promise<boolean[]>;
+
+    /**
+     * topkReserve creates an empty Top-K sketch at `key`, keeping track of the
+     * `topk` most frequent items.
+     *
+     * The promise resolves with "OK". If `key` already exists, the promise is
+     * rejected with an error.
+     */
+    topkReserve(key: string, topk: number, options?: {width?: number, depth?: number, decay?: number}): Promise<string>;
+
+    /**
+     * touch updates the last access time of the specified keys, as reading
+     * them would, and returns the number of keys that exist.
+     */
+    touch(...keys: (string | string[])[]): Promise<number>;
+
+    /**
+     * tsAdd adds a sample of `value`, at `timestamp`, in milliseconds, or "*"
+     * for the server's current time, to the RedisTimeSeries time series
+     * stored at `key`, creating it with the provided options if it does not
+     * exist.
+     *
+     * The promise resolves with the timestamp of the added sample.
+     */
+    tsAdd(key: string, timestamp: number | "*", value: number, options?: {retentionMs?: number, labels?: {[name: string]: string}, onDuplicate?: string}): Promise<number>;
+
+    /**
+     * tsAppend appends `value` to the time series stored, as a sorted set, at
+     * `key`, with `timestamp`, in milliseconds, as its score. With the
+     * `retentionMs` option set, the entries older than `timestamp` minus the
+     * retention are removed, so that the sorted set holds a sliding window of
+     * entries.
+     *
+     * The append and the trim are performed atomically, by a Lua script, so
+     * that concurrent VUs appending to the same time series can't race. As
+     * sorted sets hold unique members, appending a value already present in the
+     * time series moves it to the new timestamp.
+     *
+     * The promise resolves with the number of entries of the time series.
+     */
+    tsAppend(key: string, timestamp: number, value: any, options?: {retentionMs?: number}): Promise<number>;
+
+    /**
+     * tsMAdd adds the provided samples, objects holding a `key`, a
+     * `timestamp`, and a `value`, as with TsAdd, to existing time series.
+     *
+     * The promise resolves with an array holding the timestamp of each added
+     * sample, in order. If any of the samples could not be added, such as
+     * when its time series does not exist, the promise is rejected with the
+     * error of the first of them.
+     */
+    tsMAdd(samples: {key: string, timestamp: number | "*", value: number}[]): Promise<number[]>;
+
+    /**
+     * tsMRange is like TsRange, except that it returns the samples of all the
+     * time series matching the provided `filters`, such as "sensor=temp", or
+     * "region=(eu,us)", as supported by TS.MRANGE.
+     *
+     * On top of those of tsRange, the `withLabels` option returns the labels
+     * of the time series, or the `selectedLabels` option only the provided
+     * ones, and the `groupBy` option merges the time series sharing the same
+     * value of its `label`, with its `reduce` reducer, such as "sum".
+     *
+     * The promise resolves with an array of objects holding the `key` of each
+     * time series, its `labels`, as an object, empty unless requested, and
+     * its `samples`, as returned by tsRange.
+     */
+    tsMRange(from: number | "-", to: number | "+", filters: string[], options?: {latest?: boolean, filterByTs?: number[], filterByValue?: {min: number, max: number}, withLabels?: boolean, selectedLabels?: string[], count?: number, aggregation?: {type: string, bucketMs: number}, groupBy?: {label: string, reduce: string}}): Promise<{key: string, labels: {[name: string]: string}, samples: {timestamp: number, value: number}[]}[]>;
+
+    /**
+     * tsRange returns the samples of the time series stored at `key`, from
+     * `from` to `to`, both inclusive, which are timestamps in milliseconds,
+     * or "-" and "+" for the earliest and latest samples.
+     *
+     * The `filterByTs`, and `filterByValue`, options only return the samples
+     * with one of the provided timestamps, or whose value is within `min` and
+     * `max`. The `count` option limits the number of samples returned. The
+     * `aggregation` option aggregates the samples in buckets of `bucketMs`
+     * milliseconds, with the aggregator of its `type`, such as "avg".
+     *
+     * The promise resolves with an array of objects holding the `timestamp`,
+     * and the `value`, of each sample, in chronological order.
+     */
+    tsRange(key: string, from: number | "-", to: number | "+", options?: {latest?: boolean, filterByTs?: number[], filterByValue?: {min: number, max: number}, count?: number, aggregation?: {type: string, bucketMs: number}}): Promise<{timestamp: number, value: number}[]>;
+
+    /**
+     * ttl returns the remaining time to live of a key that has a timeout.
+     */
+    ttl(key: string): Promise<number>;
+
+    /**
+     * unlink is like Del, except that the memory of the values is reclaimed in
+     * the background, so that removing large values doesn't block the server.
+     */
+    unlink(...keys: (string | string[])[]): Promise<number>;
+
+    /**
+     * unsubscribe unsubscribes the client from the provided channels, or from
+     * all the channels it is subscribed to if none is provided. Once the
+     * client isn't subscribed to any channel, nor pattern, anymore, the
+     * subscription's connection is closed.
+     */
+    unsubscribe(...channels: string[]): Promise<void>;
+
+    /**
+     * wait waits for the write commands previously sent on the connection to be
+     * acknowledged by at least `numReplicas` replicas, or for `timeout`
+     * milliseconds to pass, whichever comes first.
+     *
+     * WAIT only accounts for the writes sent on the connection it is itself
+     * sent on. As the client pools its connections, asserting the durability of
+     * specific writes requires sending them, along with WAIT, in a pipeline,
+     * whose commands share a single connection.
+     *
+     * The promise resolves with the number of replicas which acknowledged the
+     * writes. Wait isn't supported by cluster clients.
+     */
+    wait(numReplicas: number, timeout: number): Promise<number>;
+
+    /**
+     * waitFor waits for the signal identified by `name` to be raised with
+     * signal, by any caller, possibly running in a distinct VU or k6 instance.
+     * If the signal was raised already, it returns right away.
+     *
+     * The promise resolves with the value of the signal. If it isn't raised
+     * within `timeoutMs` milliseconds, the promise is rejected with an error.
+     */
+    waitFor(name: string, timeoutMs: number): Promise<string>;
+
+    /**
+     * watch executes an optimistically locked transaction over `keys`.
+     *
+     * The keys are watched, and their values read. `callback` is then called
+     * with the values, null standing for a missing key, and a transaction
+     * pipeline to queue the transaction's commands on, which are executed in a
+     * MULTI/EXEC transaction once the callback returns. If any of the watched
+     * keys is modified before the transaction is executed, the transaction is
+     * aborted, and the whole process is retried, up to `retries` times.
+     *
+     * The promise resolves with the results of the transaction's commands. It
+     * is rejected if the callback throws, or the transaction is still aborted
+     * after the last retry.
+     */
+    watch(keys: string[], callback: (values: (string | null)[], tx: Pipeline) => void, options?: {retries?: number}): Promise<any[]>;
+
+    /**
+     * watchLeaderboard models a live leaderboard client: each time an
+     * invalidation message is published to `channel`, it reads the top ranked
+     * members of the sorted set stored at `key`, and calls `callback` with the
+     * fresh ranking, an array of objects holding each `member` and its `score`,
+     * from the highest score to the lowest.
+     *
+     * The `top` option sets the number of members read, 10 by default. The
+     * `debounceMs` option coalesces the invalidations received within that many
+     * milliseconds of the first one into a single read, to avoid read storms.
+     *
+     * The watch relies on the client's subscription: it ends when the client
+     * unsubscribes from `channel`. If reading the ranking fails, the error is
+     * thrown on the event loop, which fails the iteration.
+     *
+     * The promise resolves once the subscription to `channel` is confirmed.
+     */
+    watchLeaderboard(key: string, channel: string, callback: (ranking: {member: string, score: number}[]) => void, options?: {top?: number, debounceMs?: number}): Promise<void>;
+
+    /**
+     * withDatabase returns a client sending its commands to the logical database
+     * `db` of the same server as c, with the same options otherwise.
+     *
+     * As SELECT changes the database of a single connection, rather than that
+     * of the whole connection pool, databases are rather selected by the
+     * client's options: the returned client uses the connection pool of the
+     * clients created with the `database` option set to `db`.
+     */
+    withDatabase(db: number): Client;
+
+    /**
+     * withTags returns a client sending its commands through the same
+     * connection pool as c, whose metrics are tagged with `tags`, on top of
+     * c's tags, such as {operation: "session-read"}, so that the latency of
+     * Redis commands can be sliced by business operation in thresholds and
+     * dashboards.
+     *
+     * The tags override those of the tags option, and of c, with the same
+     * name, but not the command and address tags of the module's metrics.
+     */
+    withTags(tags: {[key: string]: string}): Client;
+
+    /**
+     * withTimeout returns a client sending its commands through the same
+     * connection pool as c, whose commands fail if they don't complete within
+     * `timeoutMs` milliseconds, regardless of the commandTimeout option.
+     *
+     * Commands which time out, whether waiting for a connection or for their
+     * reply, are rejected with an error of the "deadline" kind.
+     */
+    withTimeout(timeoutMs: number): Client;
+
+    /**
+     * withUser returns a client authenticating as the ACL user `username`, with
+     * `password`, with the same options as c otherwise, such as to load test
+     * the users restricted by ACL rules alongside the default one. As with the
+     * `username` and `password` options, the returned client uses a connection
+     * pool of its own.
+     */
+    withUser(username: string, password: string): Client;
+
+    /**
+     * xack acknowledges the entries with the provided IDs as processed by the
+     * consumer group `group` of the stream stored at `key`, removing them from
+     * the group's pending entries list.
+     *
+     * The promise resolves with the number of acknowledged entries.
+     */
+    xack(key: string, group: string, ...ids: string[]): Promise<number>;
+
+    /**
+     * xadd appends a new entry, made of the provided fields, to the stream
+     * stored at `key`. The entry is created with the provided `id`, or with an
+     * auto-generated one when `id` is "*".
+     *
+     * Each successfully added entry is counted by the redis_stream_entries_added
+     * metric.
+     *
+     * The promise resolves with the ID of the added entry.
+     */
+    xadd(key: string, id: string, fields: {[field: string]: any}): Promise<string>;
+
+    /**
+     * xgroupCreate creates the consumer group `group` of the stream stored at
+     * `key`, delivering the entries following the `start` ID: "$" for new
+     * entries only, or "0" for all of them. With the `mkstream` option set, the
+     * stream is created if it doesn't exist.
+     *
+     * The promise resolves with "OK".
+     */
+    xgroupCreate(key: string, group: string, start: string, options?: {mkstream?: boolean}): Promise<string>;
+
+    /**
+     * xpending returns a summary of the pending entries of the consumer group
+     * `group` of the stream stored at `key`: the entries delivered to its
+     * consumers, but not acknowledged yet.
+     *
+     * The promise resolves with an object holding the `count` of pending
+     * entries, the `lower` and `higher` of their IDs, and the number of pending
+     * entries of each of the group's `consumers`.
+     */
+    xpending(key: string, group: string): Promise<{count: number, lower: string, higher: string, consumers: {[consumer: string]: number}}>;
+
+    /**
+     * xrange returns the entries of the stream stored at `key` whose IDs are
+     * within the `start` and `end` IDs, inclusive. The special "-" and "+" IDs
+     * stand for the lowest and highest IDs of the stream. When `count` is
+     * positive, at most `count` entries are returned.
+     *
+     * The promise resolves with an array of entries, each with an `id` and
+     * `fields` property.
+     */
+    xrange(key: string, start: string, end: string, count?: number): Promise<{id: string, fields: {[field: string]: string}}[]>;
+
+    /**
+     * xread reads the entries of one or more streams, starting after the
+     * provided IDs. The `streams` object maps each stream's key to the ID
+     * after which its entries should be read. When `count` is positive, at most
+     * `count` entries are returned per stream.
+     *
+     * Each read entry is counted by the redis_stream_entries_read metric, and
+     * its end-to-end latency, computed from the millisecond timestamp encoded
+     * in its ID, is emitted as the redis_stream_entry_latency metric.
+     *
+     * The promise resolves with an array of objects holding the `stream` key,
+     * and its `entries`, each with an `id` and `fields` property. If no entries
+     * are available, the promise resolves with an empty array.
+     */
+    xread(streams: {[key: string]: string}, count?: number): Promise<{stream: string, entries: {id: string, fields: {[field: string]: string}}[]}[]>;
+
+    /**
+     * xreadBlock reads the entries of one or more streams, starting after the
+     * provided IDs, as Xxread does, but waits up to `timeout` seconds for
+     * entries to be added if none are available. The "$" ID only reads the
+     * entries added while waiting.
+     *
+     * The promise resolves with the read entries, in the same shape as Xxread,
+     * or an empty array if the timeout expired.
+     */
+    xreadBlock(streams: {[key: string]: string}, count: number, timeout: number): Promise<{stream: string, entries: {id: string, fields: {[field: string]: string}}[]}[]>;
+
+    /**
+     * xreadgroup reads the entries of one or more streams on behalf of
+     * `consumer`, a member of the consumer group `group`. The `streams` object
+     * maps each stream's key to the ID after which its entries should be read:
+     * ">" reads the entries never delivered to the group's consumers, while
+     * other IDs read the consumer's pending entries. When `count` is positive,
+     * at most `count` entries are returned per stream.
+     *
+     * As for Xxread, read entries are counted by the redis_stream_entries_read
+     * metric, and their latency emitted as the redis_stream_entry_latency one.
+     *
+     * The promise resolves with the read entries, in the same shape as Xxread.
+     */
+    xreadgroup(group: string, consumer: string, streams: {[key: string]: string}, count?: number): Promise<{stream: string, entries: {id: string, fields: {[field: string]: string}}[]}[]>;
+
+    /**
+     * zadd adds the provided members, as {score, member} objects, to the
+     * sorted set stored at `key`, or updates the score of those already in it.
+     *
+     * The promise resolves with the number of members added, or changed when
+     * the `ch` option is set. Calls exceeding the commandChunkSize option are
+     * split in pipelined chunks. With the `incr` option set, the score of the
+     * single member is incremented instead, and the promise resolves with its
+     * new score, or null if the other options prevented the update.
+     */
+    zadd(key: string, members: {score: number, member: any}[], options?: {nx?: boolean, xx?: boolean, gt?: boolean, lt?: boolean, ch?: boolean, incr?: boolean}): Promise<number | null>;
+
+    /**
+     * zcard returns the number of members of the sorted set stored at `key`,
+     * or 0 if it does not exist.
+     */
+    zcard(key: string): Promise<number>;
+
+    /**
+     * zincrby increments the score of `member` in the sorted set stored at
+     * `key` by `increment`. If `member` doesn't exist, it is added with
+     * `increment` as its score.
+     *
+     * The promise resolves with the new score of `member`.
+     */
+    zincrby(key: string, increment: number, member: any): Promise<number>;
+
+    /**
+     * zintercard is like Sintercard, for the intersection of the sorted sets
+     * stored at `keys`. It requires Redis 7.
+     */
+    zintercard(keys: string[], limit?: number): Promise<number>;
+
+    /**
+     * zmpop pops up to `count` members, 1 by default, with the lowest, or
+     * highest, scores, depending on `order`, "min" or "max", from the first
+     * non-empty sorted set among `keys`. It requires Redis 7.
+     *
+     * The promise resolves with an object holding the `key` the members were
+     * popped from, and the popped `members`, as {member, score} objects, or
+     * null if all the sorted sets are empty.
+     */
+    zmpop(keys: string[], order: "min" | "max", count?: number): Promise<{key: string, members: {member: string, score: number}[]} | null>;
+
+    /**
+     * zrange returns the members of the sorted set stored at `key` in the
+     * range from `start` to `stop`.
+     *
+     * By default, the range is of ranks, from the lowest score to the highest.
+     * The `byScore`, and `byLex`, options make it a range of scores, and a
+     * lexicographical range, respectively, using the Redis syntax for exclusive
+     * and infinite bounds, such as "(1" or "-inf". The `rev` option reverses
+     * the ordering, and the `limit` option paginates score and lexicographical
+     * ranges.
+     *
+     * The promise resolves with an array of members, or, with the `withScores`
+     * option set, of {member, score} objects.
+     */
+    zrange(key: string, start: any, stop: any, options?: {byScore?: boolean, byLex?: boolean, rev?: boolean, limit?: {offset: number, count: number}, withScores?: boolean}): Promise<string[] | {member: string, score: number}[]>;
+
+    /**
+     * zrangebyscore returns the members of the sorted set stored at `key` whose
+     * score is between `min` and `max`, from the lowest score to the highest.
+     * The bounds use the Redis syntax for exclusive and infinite bounds, such
+     * as "(1" or "+inf".
+     *
+     * The promise resolves with an array of members, or, with the `withScores`
+     * option set, of {member, score} objects.
+     */
+    zrangebyscore(key: string, min: any, max: any, options?: {limit?: {offset: number, count: number}, withScores?: boolean}): Promise<string[] | {member: string, score: number}[]>;
+
+    /**
+     * zrem removes the specified members from the sorted set stored at `key`.
+     * Members that are not in the sorted set are ignored.
+     *
+     * The promise resolves with the number of members removed. Calls exceeding
+     * the commandChunkSize option are split in pipelined chunks.
+     */
+    zrem(key: string, ...members: any[]): Promise<number>;
+
+    /**
+     * zscan iterates over the members of the sorted set stored at `key`,
+     * starting from `cursor`, as hscan does over the fields of a hash.
+     *
+     * The promise resolves with an object holding the `cursor` to continue the
+     * iteration from, and the `members` read, as {member, score} objects.
+     */
+    zscan(key: string, cursor: number | string, options?: {match?: string, count?: number}): Promise<{cursor: string, members: {member: string, score: number}[]}>;
+
+    /**
+     * zscanAll iterates over all the members of the sorted set stored at `key`
+     * with ZSCAN, handling the cursors. The promise resolves with the members
+     * matching the `match` option, listed once each, as {member, score}
+     * objects.
+     */
+    zscanAll(key: string, options?: {match?: string, count?: number}): Promise<{member: string, score: number}[]>;
+
+    /**
+     * zscore returns the score of `member` in the sorted set stored at `key`.
+     *
+     * If the sorted set, or the member, does not exist, the promise is rejected
+     * with an error.
+     */
+    zscore(key: string, member: any): Promise<number>;
+  }
+
+  /**
+   * ClientGroup calls the same client methods on several clients, its
+   * targets, such as those of the old and new deployments of a migration, or
+   * of the two sides of an A/B benchmark, so that scripts don't duplicate
+   * every call for each of them.
+   *
+   * Each target is derived from the provided client with withTags, so that
+   * the metrics of its commands are tagged with its name, as the target tag.
+   */
+  export class ClientGroup {
+    /**
+     * ClientGroup is the JS constructor for the ClientGroup. It expects an
+     * object holding the clients to target, by name, such as {old: oldClient,
+     * new: newClient}, and an optional options object.
+     */
+    constructor(targets: {[name: string]: Client}, options?: {compare?: boolean});
+
+    /**
+     * call calls the client method named `method`, such as "get", with `args`,
+     * on each of the group's targets, without waiting for one to complete
+     * before calling the next.
+     *
+     * The promise resolves once the calls completed on every target, with an
+     * object holding their outcome, by target name, as its `targets` property:
+     * the `value` its promise resolved with, or its `error`, and its
+     * `duration`, in milliseconds. The promise isn't rejected when the call
+     * fails on a target, but if `method` isn't a client method.
+     *
+     * With the compare option, the object also holds whether the targets
+     * agreed, as its `match` property: whether their values, and errors, are
+     * equal. The outcome is recorded by the redis_target_matches rate metric,
+     * tagged with the `method`.
+     */
+    call(method: string, ...args: any[]): Promise<{targets: {[name: string]: {value: any, error: string | null, duration: number}}, match?: boolean}>;
+
+    /**
+     * targets returns the names of the group's targets, sorted.
+     */
+    targets(): string[];
+  }
+
+  /**
+   * Pipeline buffers commands, and sends them to Redis in a single round-trip
+   * when executed.
+   *
+   * Its methods queue a command and return the pipeline itself, so that calls
+   * can be chained. They throw if the command's arguments are not of a
+   * supported type, or its options are invalid.
+   */
+  export interface Pipeline {
+    /**
+     * decr queues a DECR command.
+     */
+    decr(key: string): Pipeline;
+
+    /**
+     * decrBy queues a DECRBY command.
+     */
+    decrBy(key: string, decrement: number): Pipeline;
+
+    /**
+     * del queues a DEL command.
+     */
+    del(...keys: string[]): Pipeline;
+
+    /**
+     * exec sends the queued commands to Redis in a single round-trip, and
+     * resolves to the array of their results, in the order they were queued.
+     *
+     * The commands of pipelines returned by multi are wrapped in a MULTI/EXEC
+     * transaction, so that they are executed atomically.
+     *
+     * Nil replies, such as those of GET for missing keys, resolve to null. If
+     * any of the commands fails, the promise is rejected with the error of the
+     * first one that did. The queue is emptied either way, so that the pipeline
+     * can be reused.
+     */
+    exec(): Promise<any>;
+
+    /**
+     * expire queues an EXPIRE command.
+     */
+    expire(key: string, seconds: number): Pipeline;
+
+    /**
+     * get queues a GET command.
+     */
+    get(key: string): Pipeline;
+
+    /**
+     * hget queues an HGET command.
+     */
+    hget(key: string, field: any): Pipeline;
+
+    /**
+     * hset queues an HSET command.
+     */
+    hset(key: string, field: any, value: any): Pipeline;
+
+    /**
+     * incr queues an INCR command.
+     */
+    incr(key: string): Pipeline;
+
+    /**
+     * incrBy queues an INCRBY command.
+     */
+    incrBy(key: string, increment: number): Pipeline;
+
+    /**
+     * lpush queues an LPUSH command.
+     */
+    lpush(key: string, ...values: any[]): Pipeline;
+
+    /**
+     * rpush queues an RPUSH command.
+     */
+    rpush(key: string, ...values: any[]): Pipeline;
+
+    /**
+     * sadd queues an SADD command.
+     */
+    sadd(key: string, ...members: any[]): Pipeline;
+
+    /**
+     * sendCommand queues an arbitrary command.
+     */
+    sendCommand(command: string, ...args: any[]): Pipeline;
+
+    /**
+     * set queues a SET command. The third argument is either the expiration,
+     * interpreted as seconds, or an object of the options of the client's set
+     * method.
+     */
+    set(key: string, value: any, expirationOrOptions: any): Pipeline;
+
+    /**
+     * sintercard queues a SINTERCARD command, with an optional `limit`, as the
+     * client's sintercard method.
+     */
+    sintercard(keys: string[], ...limit: number[]): Pipeline;
+
+    /**
+     * zadd queues a ZADD command, with the same arguments, and options, as the
+     * client's zadd method. With the incr option set, it resolves with the new
+     * score of the member, as a number.
+     */
+    zadd(key: string, members: any[], options: {[key: string]: any}): Pipeline;
+  }
+
+  /**
+   * Script is a Lua script, executed with EVALSHA, so that its source is only
+   * sent to the server when it isn't in the server's scripts cache yet.
+   */
+  export interface Script {
+    /**
+     * hash returns the SHA1 digest of the script's source.
+     */
+    hash(): string;
+
+    /**
+     * run evaluates the script with the provided keys and arguments. It sends
+     * EVALSHA, and falls back to EVAL, which caches the script, when the server
+     * replies with a NOSCRIPT error.
+     *
+     * The promise resolves with the script's reply, null standing for a nil
+     * reply.
+     */
+    run(keys: string[], ...args: any[]): Promise<any>;
+  }
+
+  /**
+   * Lock is a lock held on a key, as acquired by the Client's lock method.
+   */
+  export interface Lock {
+    /**
+     * extend sets the time to live of the lock to `ttlMs` milliseconds, or to
+     * the ttl it was acquired with if zero, provided it is still held.
+     *
+     * The promise resolves with true if the lock was extended, and false if it
+     * had expired, possibly being acquired by another holder since.
+     */
+    extend(ttlMs: number): Promise<any>;
+
+    /**
+     * release releases the lock, provided it is still held.
+     *
+     * The promise resolves with true if the lock was released, and false if it
+     * had expired, possibly being acquired by another holder since.
+     */
+    release(): Promise<any>;
+  }
+
+  /**
+   * randomKey returns `prefix` followed by a pseudo-random suffix generated
+   * from `seed`. The same prefix and seed always produce the same key.
+   */
+  export function randomKey(prefix: string, seed: number): string;
+
+  /**
+   * randomValue returns a pseudo-random value of `size` bytes, generated
+   * from `seed`. The same seed always produces the same value, across VUs
+   * and test runs, so that payloads are reproducible, and benchmarks
+   * comparable.
+   *
+   * By default, the value is a string of URL-safe characters. With the
+   * `binary` option set, it is a Uint8Array of arbitrary bytes.
+   */
+  export function randomValue(size: number, seed: number, options?: {binary?: boolean}): string | Uint8Array;
+}
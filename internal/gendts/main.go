@@ -0,0 +1,641 @@
+// Command gendts generates index.d.ts, the TypeScript definitions of the
+// k6/x/redis module, from the Go surface of the redis package, so that
+// editors complete the methods of its objects.
+//
+// The methods, and their documentation, are those the redis package exports
+// to JS. The signatures of the client's, and the client group's, methods,
+// and of the module's functions, are read from the API tables of the
+// README, which describe the shape of the values their promises resolve
+// with. The methods missing from the tables, and those of the other
+// objects, have their signature derived from their Go types instead.
+//
+// It is run from the root of the repository with go generate, and the
+// index.d.ts test fails whenever the definitions are out of date.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// classes lists the Go types of the redis package exposed to JS as
+// objects, in the order they are declared.
+var classes = []string{"Client", "ClientGroup", "Pipeline", "Script", "Lock"}
+
+// constructorParams lists the parameters of the constructors of the classes
+// scripts construct, by class, as their Go constructors take a
+// sobek.ConstructorCall, and parse its arguments themselves: the client's
+// URL, or options object, and the client group's targets.
+var constructorParams = map[string]string{
+	"Client":      "options: string | {[option: string]: any}",
+	"ClientGroup": "targets: {[name: string]: Client}, options?: {compare?: boolean}",
+}
+
+// moduleType is the Go type whose Exports method lists the module's
+// exports: the constructors, and functions.
+const moduleType = "ModuleInstance"
+
+func main() {
+	root := flag.String("root", ".", "the root of the repository")
+	out := flag.String("out", "index.d.ts", "the file to write the definitions to, relative to the root")
+	flag.Parse()
+
+	definitions, err := generate(*root)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(*root, *out), definitions, 0o644); err != nil { //nolint:gosec
+		log.Fatal(err)
+	}
+}
+
+// method is a method of the redis package exposed to JS.
+type method struct {
+	name      string
+	goName    string
+	doc       string
+	signature signature
+}
+
+// signature is the TypeScript signature of a method: its parameters, as
+// written between the parentheses, and its return type.
+type signature struct {
+	params  string
+	returns string
+
+	// arity is the number of parameters of the Go method, and variadic
+	// whether its last one is, for the signatures derived from Go.
+	arity    int
+	variadic bool
+}
+
+// generate returns the TypeScript definitions of the module whose
+// repository is rooted at `root`.
+func generate(root string) ([]byte, error) {
+	docs, methods, exports, err := parsePackage(filepath.Join(root, "redis"))
+	if err != nil {
+		return nil, err
+	}
+
+	readme, err := os.ReadFile(filepath.Join(root, "README.md")) //nolint:gosec
+	if err != nil {
+		return nil, err
+	}
+	documented := readmeSignatures(string(readme))
+
+	// The module's exports are named after their export, rather than the
+	// Go method implementing them.
+	var functions []method
+	constructors := make(map[string]method)
+	for _, m := range methods[moduleType] {
+		export, ok := exports[m.goName]
+		if !ok {
+			continue
+		}
+
+		m.name, m.doc = export, strings.Replace(m.doc, m.name, export, 1)
+		if _, ok := constructorParams[export]; ok {
+			constructors[export] = m
+		} else {
+			functions = append(functions, m)
+		}
+	}
+	for class := range constructorParams {
+		if _, ok := constructors[class]; !ok {
+			return nil, fmt.Errorf("the %s constructor isn't exported by the %s type", class, moduleType)
+		}
+	}
+
+	var (
+		buf        bytes.Buffer
+		mismatches []string
+	)
+	buf.WriteString("// Code generated by gendts from the redis package, and the README; DO NOT EDIT.\n\n")
+	buf.WriteString("declare module \"k6/x/redis\" {\n")
+
+	for idx, class := range classes {
+		if idx > 0 {
+			buf.WriteString("\n")
+		}
+
+		writeDoc(&buf, "  ", docs[class])
+		constructor, constructed := constructors[class]
+		if constructed {
+			fmt.Fprintf(&buf, "  export class %s {\n", class)
+			writeDoc(&buf, "    ", constructor.doc)
+			fmt.Fprintf(&buf, "    constructor(%s);\n", constructorParams[class])
+		} else {
+			fmt.Fprintf(&buf, "  export interface %s {\n", class)
+		}
+
+		for midx, m := range methods[class] {
+			// The client's tables name the other objects' methods alike,
+			// which are only described by the README sections of their own.
+			signatures := []signature{m.signature}
+			if sigs, ok := documented[class][m.name]; ok {
+				signatures = sigs
+			}
+
+			if midx > 0 || constructed {
+				buf.WriteString("\n")
+			}
+			writeDoc(&buf, "    ", m.doc)
+			for _, sig := range signatures {
+				if err := checkSignature(m.signature, sig); err != nil {
+					mismatches = append(mismatches, fmt.Sprintf("%s.%s: %s", class, m.name, err))
+				}
+				fmt.Fprintf(&buf, "    %s(%s): %s;\n", m.name, sig.params, sig.returns)
+			}
+		}
+		buf.WriteString("  }\n")
+	}
+
+	for _, m := range functions {
+		signatures := []signature{m.signature}
+		if sigs, ok := documented[moduleType][m.name]; ok {
+			signatures = sigs
+		}
+
+		buf.WriteString("\n")
+		writeDoc(&buf, "  ", m.doc)
+		for _, sig := range signatures {
+			if err := checkSignature(m.signature, sig); err != nil {
+				mismatches = append(mismatches, fmt.Sprintf("%s: %s", m.name, err))
+			}
+			fmt.Fprintf(&buf, "  export function %s(%s): %s;\n", m.name, sig.params, sig.returns)
+		}
+	}
+	buf.WriteString("}\n")
+
+	// The client's tables also document the other objects' methods, such as
+	// the pipeline's exec.
+	for typ, signatures := range documented {
+		exported := map[string][]method{typ: methods[typ]}
+		switch typ {
+		case classes[0]:
+			exported = methods
+		case moduleType:
+			exported = map[string][]method{typ: functions}
+		}
+
+		for name := range signatures {
+			if !hasMethod(exported, name) {
+				mismatches = append(mismatches, fmt.Sprintf("%s: documented, but not exported", name))
+			}
+		}
+	}
+	sort.Strings(mismatches)
+
+	if len(mismatches) > 0 {
+		return nil, fmt.Errorf("the README documents signatures which don't match the redis package:\n%s",
+			strings.Join(mismatches, "\n"))
+	}
+
+	return buf.Bytes(), nil
+}
+
+// parsePackage parses the Go files of the package in `dir`, and returns the
+// documentation of its types, by name, the exported methods of the classes,
+// and of the module, sorted by name, and the names the module exports its
+// methods as, by Go name.
+func parsePackage(dir string) (map[string]string, map[string][]method, map[string]string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	exposed := map[string]bool{moduleType: true}
+	for _, class := range classes {
+		exposed[class] = true
+	}
+
+	docs := make(map[string]string)
+	methods := make(map[string][]method)
+	exports := make(map[string]string)
+	fset := token.NewFileSet()
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+
+		f, err := parser.ParseFile(fset, file, nil, parser.ParseComments)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		for _, decl := range f.Decls {
+			switch decl := decl.(type) {
+			case *ast.GenDecl:
+				for _, spec := range decl.Specs {
+					ts, ok := spec.(*ast.TypeSpec)
+					if !ok || !exposed[ts.Name.Name] {
+						continue
+					}
+					doc := decl.Doc
+					if ts.Doc != nil {
+						doc = ts.Doc
+					}
+					docs[ts.Name.Name] = doc.Text()
+				}
+			case *ast.FuncDecl:
+				receiver := receiverType(decl)
+				if !exposed[receiver] || !decl.Name.IsExported() {
+					continue
+				}
+
+				if receiver == moduleType && decl.Name.Name == "Exports" {
+					moduleExports(decl, exports)
+				}
+
+				methods[receiver] = append(methods[receiver], method{
+					name:      jsName(decl.Name.Name),
+					goName:    decl.Name.Name,
+					doc:       jsDoc(decl.Name.Name, decl.Doc.Text()),
+					signature: goSignature(decl.Type),
+				})
+			}
+		}
+	}
+
+	for _, class := range classes {
+		if len(methods[class]) == 0 {
+			return nil, nil, nil, fmt.Errorf("no methods found for the %s type in %s", class, dir)
+		}
+	}
+
+	for _, ms := range methods {
+		sort.Slice(ms, func(i, j int) bool { return ms[i].name < ms[j].name })
+	}
+
+	return docs, methods, exports, nil
+}
+
+// moduleExports records the exports of the Exports method `decl` of the
+// module, such as `"randomValue": mi.RandomValue`, in `exports`, by the
+// name of the method they export.
+func moduleExports(decl *ast.FuncDecl, exports map[string]string) {
+	ast.Inspect(decl, func(node ast.Node) bool {
+		kv, ok := node.(*ast.KeyValueExpr)
+		if !ok {
+			return true
+		}
+
+		key, ok := kv.Key.(*ast.BasicLit)
+		if !ok || key.Kind != token.STRING {
+			return true
+		}
+		if value, ok := kv.Value.(*ast.SelectorExpr); ok {
+			exports[value.Sel.Name] = strings.Trim(key.Value, `"`)
+		}
+
+		return true
+	})
+}
+
+// hasMethod returns whether any of the types of `methods` has a method
+// named `name` in JS.
+func hasMethod(methods map[string][]method, name string) bool {
+	for _, ms := range methods {
+		for _, m := range ms {
+			if m.name == name {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// receiverType returns the name of the type of the receiver of `decl`, or
+// an empty string if it is a function.
+func receiverType(decl *ast.FuncDecl) string {
+	if decl.Recv == nil || len(decl.Recv.List) == 0 {
+		return ""
+	}
+
+	typ := decl.Recv.List[0].Type
+	if star, ok := typ.(*ast.StarExpr); ok {
+		typ = star.X
+	}
+	if ident, ok := typ.(*ast.Ident); ok {
+		return ident.Name
+	}
+
+	return ""
+}
+
+// jsName returns the name of the Go method `name` in JS, as k6's
+// common.MethodName maps it: methods starting with an X have it stripped,
+// and the others have their first letter lowercased.
+func jsName(name string) string {
+	if strings.HasPrefix(name, "X") {
+		return name[1:]
+	}
+
+	return strings.ToLower(name[:1]) + name[1:]
+}
+
+// jsDoc returns the documentation `doc` of the Go method `name`, starting
+// with its JS name, rather than its Go one.
+func jsDoc(name, doc string) string {
+	if rest, ok := strings.CutPrefix(doc, name+" "); ok {
+		return jsName(name) + " " + rest
+	}
+
+	return doc
+}
+
+// writeDoc writes `doc` as a JSDoc comment, indented with `indent`.
+func writeDoc(buf *bytes.Buffer, indent, doc string) {
+	doc = strings.TrimSpace(doc)
+	if doc == "" {
+		return
+	}
+
+	fmt.Fprintf(buf, "%s/**\n", indent)
+	for _, line := range strings.Split(doc, "\n") {
+		// The comment would be closed by the end of a glob pattern.
+		line = strings.ReplaceAll(line, "*/", "*\\/")
+		if line == "" {
+			fmt.Fprintf(buf, "%s *\n", indent)
+			continue
+		}
+		fmt.Fprintf(buf, "%s * %s\n", indent, line)
+	}
+	fmt.Fprintf(buf, "%s */\n", indent)
+}
+
+// goSignature returns the TypeScript signature of the Go function type
+// `fn`, once exposed to JS.
+func goSignature(fn *ast.FuncType) signature {
+	var params []string
+	for idx, field := range fn.Params.List {
+		typ := field.Type
+		variadic := false
+		if ellipsis, ok := typ.(*ast.Ellipsis); ok {
+			typ, variadic = ellipsis.Elt, true
+		}
+
+		names := field.Names
+		if len(names) == 0 {
+			names = []*ast.Ident{ast.NewIdent(fmt.Sprintf("arg%d", idx))}
+		}
+		for _, name := range names {
+			if variadic {
+				params = append(params, fmt.Sprintf("...%s: %s[]", name.Name, elementType(tsType(typ))))
+			} else {
+				params = append(params, fmt.Sprintf("%s: %s", name.Name, tsType(typ)))
+			}
+		}
+	}
+
+	returns := "void"
+	if fn.Results != nil && len(fn.Results.List) > 0 {
+		returns = tsType(fn.Results.List[0].Type)
+	}
+
+	last := fn.Params.List
+	variadic := len(last) > 0 && isEllipsis(last[len(last)-1].Type)
+
+	return signature{params: strings.Join(params, ", "), returns: returns, arity: len(params), variadic: variadic}
+}
+
+// isEllipsis returns whether `expr` is the type of a variadic parameter.
+func isEllipsis(expr ast.Expr) bool {
+	_, ok := expr.(*ast.Ellipsis)
+	return ok
+}
+
+// tsType returns the TypeScript type of the values of the Go type `expr`,
+// once exposed to JS.
+func tsType(expr ast.Expr) string {
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		switch expr.Name {
+		case "string":
+			return "string"
+		case "bool":
+			return "boolean"
+		case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64",
+			"float32", "float64":
+			return "number"
+		}
+		for _, class := range classes {
+			if expr.Name == class {
+				return class
+			}
+		}
+	case *ast.StarExpr:
+		if sel, ok := expr.X.(*ast.SelectorExpr); ok && sel.Sel.Name == "Promise" {
+			return "Promise<any>"
+		}
+		return tsType(expr.X)
+	case *ast.ArrayType:
+		return elementType(tsType(expr.Elt)) + "[]"
+	case *ast.MapType:
+		return fmt.Sprintf("{[key: string]: %s}", tsType(expr.Value))
+	case *ast.FuncType:
+		return "(...args: any[]) => any"
+	}
+
+	return "any"
+}
+
+// elementType returns `typ` as the element type of an array, wrapping it
+// in parentheses if it is a union, or a function.
+func elementType(typ string) string {
+	if strings.Contains(typ, "|") || strings.Contains(typ, "=>") {
+		return "(" + typ + ")"
+	}
+
+	return typ
+}
+
+// codeSpan matches the code spans of a Markdown line.
+var codeSpan = regexp.MustCompile("`[^`]+`")
+
+// sections lists the README sections documenting the methods of another
+// type than the client, by title, such as the module's functions, some of
+// which share their name with the client's methods, such as randomKey.
+var sections = map[string]string{
+	"### Client groups":      "ClientGroup",
+	"### Payload generation": moduleType,
+}
+
+// readmeSignatures returns the signatures the README documents, by type,
+// and by name: those of the code spans of its tables, such as
+// `get(key: string) => Promise<string>`, which document the client's
+// methods, unless their section is listed by sections. The methods
+// documented with several signatures, such as mget, are declared with an
+// overload for each.
+func readmeSignatures(readme string) map[string]map[string][]signature {
+	documented := make(map[string]map[string][]signature)
+
+	typ := classes[0]
+	for _, line := range strings.Split(readme, "\n") {
+		if strings.HasPrefix(line, "#") {
+			typ = classes[0]
+			if t, ok := sections[line]; ok {
+				typ = t
+			}
+			continue
+		}
+		if !strings.HasPrefix(line, "|") {
+			continue
+		}
+
+		signatures, ok := documented[typ]
+		if !ok {
+			signatures = make(map[string][]signature)
+			documented[typ] = signatures
+		}
+
+		// Pipes are escaped within the cells of tables.
+		for _, span := range codeSpan.FindAllString(strings.ReplaceAll(line, `\|`, "|"), -1) {
+			name, sig, err := parseSignature(strings.Trim(span, "`"))
+			if err != nil {
+				continue
+			}
+			if !containsSignature(signatures[name], sig) {
+				signatures[name] = append(signatures[name], sig)
+			}
+		}
+	}
+
+	return documented
+}
+
+// containsSignature returns whether `signatures` holds `sig`.
+func containsSignature(signatures []signature, sig signature) bool {
+	for _, s := range signatures {
+		if s == sig {
+			return true
+		}
+	}
+
+	return false
+}
+
+// signatureName matches the name a signature starts with, up to the
+// opening parenthesis of its parameters.
+var signatureName = regexp.MustCompile(`^([a-z][A-Za-z0-9]*)\(`)
+
+// parseSignature parses a signature documented in the README, such as
+// `get(key: string) => Promise<string>`, and returns its name.
+func parseSignature(s string) (string, signature, error) {
+	match := signatureName.FindStringSubmatch(s)
+	if match == nil {
+		return "", signature{}, errors.New("not a signature")
+	}
+
+	// The parameters end with the parenthesis balancing the opening one, as
+	// callbacks' parameters are written within parentheses too.
+	depth := 0
+	for idx := len(match[0]) - 1; idx < len(s); idx++ {
+		switch s[idx] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		}
+		if depth > 0 {
+			continue
+		}
+
+		returns, ok := strings.CutPrefix(s[idx+1:], " => ")
+		if !ok || returns == "" {
+			return "", signature{}, errors.New("no return type")
+		}
+
+		return match[1], signature{params: s[len(match[0]):idx], returns: returns}, nil
+	}
+
+	return "", signature{}, errors.New("unbalanced parentheses")
+}
+
+// reservedWords lists the reserved words of TypeScript which can't name
+// parameters.
+var reservedWords = map[string]struct{}{
+	"break": {}, "case": {}, "catch": {}, "class": {}, "const": {}, "continue": {}, "debugger": {},
+	"default": {}, "delete": {}, "do": {}, "else": {}, "enum": {}, "export": {}, "extends": {}, "false": {},
+	"finally": {}, "for": {}, "function": {}, "if": {}, "import": {}, "in": {}, "instanceof": {}, "new": {},
+	"null": {}, "return": {}, "super": {}, "switch": {}, "this": {}, "throw": {}, "true": {}, "try": {},
+	"typeof": {}, "var": {}, "void": {}, "while": {}, "with": {},
+}
+
+// checkSignature returns an error if the documented signature `sig` isn't
+// valid TypeScript, or doesn't match `goSig`, the signature derived from
+// the Go method: methods which aren't variadic take as many parameters as
+// documented, or less, as sobek passes undefined for the missing ones.
+// Variadic ones take a rest parameter, unless they are documented with
+// other parameters, such as an array of keys, and options, or an optional
+// one.
+func checkSignature(goSig, sig signature) error {
+	params := splitParams(sig.params)
+	rest := false
+	for idx, param := range params {
+		name, _, _ := strings.Cut(param, ":")
+		name = strings.TrimSuffix(name, "?")
+		if name, rest = strings.CutPrefix(name, "..."); rest && idx != len(params)-1 {
+			return fmt.Errorf("the rest parameter %s isn't the last one", name)
+		}
+		if _, ok := reservedWords[name]; ok {
+			return fmt.Errorf("the parameter %s is named after a reserved word", name)
+		}
+	}
+
+	switch {
+	case !goSig.variadic && (len(params) > goSig.arity || rest):
+		return fmt.Errorf("documented with %d parameters, rather than %d", len(params), goSig.arity)
+	case goSig.variadic && len(params) == goSig.arity && !rest && !strings.Contains(params[len(params)-1], "?:"):
+		return fmt.Errorf("the variadic parameter is documented as %q", params[len(params)-1])
+	}
+
+	return nil
+}
+
+// splitParams returns the parameters of a signature, separated by the
+// commas which aren't nested within the types of the parameters.
+func splitParams(params string) []string {
+	if params == "" {
+		return nil
+	}
+
+	var (
+		split []string
+		depth int
+		start int
+	)
+	for idx := 0; idx < len(params); idx++ {
+		switch params[idx] {
+		case '(', '[', '{', '<':
+			depth++
+		case ')', ']', '}':
+			depth--
+		case '>':
+			// The arrows of function types don't close anything.
+			if idx == 0 || params[idx-1] != '=' {
+				depth--
+			}
+		case ',':
+			if depth == 0 {
+				split = append(split, strings.TrimSpace(params[start:idx]))
+				start = idx + 1
+			}
+		}
+	}
+
+	return append(split, strings.TrimSpace(params[start:]))
+}
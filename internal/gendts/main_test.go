@@ -0,0 +1,108 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateUpToDate(t *testing.T) {
+	t.Parallel()
+
+	definitions, err := generate("../..")
+	require.NoError(t, err)
+
+	committed, err := os.ReadFile("../../index.d.ts")
+	require.NoError(t, err)
+
+	assert.Equal(t, string(committed), string(definitions), "index.d.ts is out of date, run go generate ./...")
+}
+
+func TestParseSignature(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		span    string
+		name    string
+		params  string
+		returns string
+		wantErr bool
+	}{
+		{
+			span:    "get(key: string) => Promise<string>",
+			name:    "get",
+			params:  "key: string",
+			returns: "Promise<string>",
+		},
+		{
+			span:    "watch(keys: string[], callback: (values: (string | null)[], tx: Pipeline) => void) => Promise<any[]>",
+			name:    "watch",
+			params:  "keys: string[], callback: (values: (string | null)[], tx: Pipeline) => void",
+			returns: "Promise<any[]>",
+		},
+		{
+			span:    "geopos(key: string) => Promise<({longitude: number, latitude: number} | null)[]>",
+			name:    "geopos",
+			params:  "key: string",
+			returns: "Promise<({longitude: number, latitude: number} | null)[]>",
+		},
+		{span: "set(key: string, value: any, expiration: number)", wantErr: true},
+		{span: "client.pipeline().get('key').exec()", wantErr: true},
+		{span: "SET", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.span, func(t *testing.T) {
+			t.Parallel()
+
+			name, sig, err := parseSignature(tt.span)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.name, name)
+			assert.Equal(t, tt.params, sig.params)
+			assert.Equal(t, tt.returns, sig.returns)
+		})
+	}
+}
+
+func TestCheckSignature(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		goSig   signature
+		params  string
+		wantErr bool
+	}{
+		{name: "matching", goSig: signature{arity: 2}, params: "key: string, value: any"},
+		{name: "missing optional parameter", goSig: signature{arity: 3}, params: "key: string, values: {[field: string]: any}"},
+		{name: "extra parameter", goSig: signature{arity: 2}, params: "key: string, start: number, stop: number", wantErr: true},
+		{name: "rest parameter", goSig: signature{arity: 2, variadic: true}, params: "key: string, ...members: any[]"},
+		{name: "variadic as an array", goSig: signature{arity: 2, variadic: true}, params: "key: string, members: any[]", wantErr: true},
+		{name: "variadic as an option", goSig: signature{arity: 2, variadic: true}, params: "keys: string[], limit?: number"},
+		{name: "rest parameter first", goSig: signature{arity: 1, variadic: true}, params: "...keys: string[], options?: {}", wantErr: true},
+		{name: "reserved word", goSig: signature{arity: 1}, params: "function: string", wantErr: true},
+		{name: "callback", goSig: signature{arity: 2}, params: "keys: string[], callback: (a: string, b: number) => void"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := checkSignature(tt.goSig, signature{params: tt.params})
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/grafana/sobek"
+	"github.com/redis/go-redis/v9"
+	"go.k6.io/k6/js/common"
+)
+
+// WithUser returns a client authenticating as the ACL user `username`, with
+// `password`, with the same options as c otherwise, such as to load test
+// the users restricted by ACL rules alongside the default one. As with the
+// `username` and `password` options, the returned client uses a connection
+// pool of its own.
+func (c *Client) WithUser(username, password string) *Client {
+	if username == "" {
+		common.Throw(c.vu.Runtime(), errors.New("withUser requires a username"))
+	}
+
+	uopts := *c.redisOptions.UniversalOptions
+	uopts.Username = username
+	uopts.Password = password
+
+	opts := *c.redisOptions
+	opts.UniversalOptions = &uopts
+
+	return &Client{
+		vu:               c.vu,
+		redisOptions:     &opts,
+		getRedisClient:   c.getRedisClient,
+		closeRedisClient: c.closeRedisClient,
+		metrics:          c.metrics,
+		timeout:          c.timeout,
+		tags:             c.tags,
+		stats:            c.stats,
+		hooks:            c.hooks,
+		trackedKeys:      c.trackedKeys,
+		syncCalls:        c.syncCalls,
+	}
+}
+
+// AclSetUser creates the ACL user `username`, or modifies its rules, such
+// as "on", ">password", "~cache:*", or "+get". Cluster clients set the user
+// on every master node, as ACL users are specific to each node.
+//
+// The promise resolves with "OK".
+func (c *Client) AclSetUser(username string, rules ...string) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	if username == "" {
+		reject(errors.New("aclSetUser requires a username"))
+		return promise
+	}
+
+	args := append([]interface{}{"acl", "setuser", username}, stringsToArgs(rules)...)
+
+	go func() {
+		err := c.forEachScanClient(c.context(), func(ctx context.Context, client redis.UniversalClient) error {
+			cmd := redis.NewStatusCmd(ctx, args...)
+			_ = client.Process(ctx, cmd)
+
+			return cmd.Err()
+		})
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve("OK")
+	}()
+
+	return promise
+}
+
+// AclDelUser deletes the provided ACL users, and closes their connections.
+// Cluster clients delete the users from every master node.
+//
+// The promise resolves with the number of users deleted, from any of the
+// nodes, not counting those which don't exist.
+func (c *Client) AclDelUser(usernames ...string) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	if len(usernames) == 0 {
+		reject(errors.New("aclDelUser requires at least one username"))
+		return promise
+	}
+
+	args := append([]interface{}{"acl", "deluser"}, stringsToArgs(usernames)...)
+
+	go func() {
+		var (
+			mu      sync.Mutex
+			deleted int64
+		)
+		err := c.forEachScanClient(c.context(), func(ctx context.Context, client redis.UniversalClient) error {
+			cmd := redis.NewIntCmd(ctx, args...)
+			_ = client.Process(ctx, cmd)
+
+			count, err := cmd.Result()
+			if err != nil {
+				return err
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if count > deleted {
+				deleted = count
+			}
+
+			return nil
+		})
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(deleted)
+	}()
+
+	return promise
+}
+
+// AclList returns the ACL users of the server, with their rules, in the
+// format of ACL files.
+//
+// The promise resolves with an array of strings, such as
+// "user default on nopass ~* &* +@all".
+func (c *Client) AclList() *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		ctx := c.context()
+		cmd := redis.NewStringSliceCmd(ctx, "acl", "list")
+		_ = c.redisClient.Process(ctx, cmd)
+
+		users, err := cmd.Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(users)
+	}()
+
+	return promise
+}
+
+// AclWhoami returns the name of the ACL user the client's connections are
+// authenticated as.
+//
+// The promise resolves with the name of the user, "default" if the client
+// wasn't given a username.
+func (c *Client) AclWhoami() *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		ctx := c.context()
+		cmd := redis.NewStringCmd(ctx, "acl", "whoami")
+		_ = c.redisClient.Process(ctx, cmd)
+
+		username, err := cmd.Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(username)
+	}()
+
+	return promise
+}
@@ -0,0 +1,75 @@
+package redis
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientACL(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunT(t)
+	rs.RegisterCommandHandler("ACL", func(c *Connection, args []string) {
+		switch strings.ToUpper(args[0]) {
+		case "SETUSER":
+			c.WriteOK()
+		case "DELUSER":
+			c.WriteInteger(len(args) - 1)
+		case "LIST":
+			c.WriteArray("user default on nopass ~* &* +@all", "user bob on #abc ~cache:* -@all +get")
+		case "WHOAMI":
+			c.WriteBulkString("default")
+		}
+	})
+	rs.RegisterCommandHandler("AUTH", func(c *Connection, _ []string) {
+		c.WriteOK()
+	})
+	rs.RegisterCommandHandler("SET", func(c *Connection, _ []string) {
+		c.WriteError(errors.New("NOPERM User bob has no permissions to run the 'set' command"))
+	})
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client('redis://%s');
+			const bob = redis.withUser("bob", "secret");
+
+			if (bob.options().username !== "bob" || bob.options().hash === redis.options().hash) {
+				throw 'expected the client to use its own connection pool: ' + JSON.stringify(bob.options())
+			}
+
+			redis.aclSetUser("bob", "on", ">secret", "~cache:*", "-@all", "+get")
+				.then(res => { if (res !== "OK") { throw 'unexpected value for aclSetUser result: ' + res } })
+				.then(() => redis.aclList())
+				.then(res => { if (res.length !== 2) { throw 'unexpected value for aclList result: ' + res } })
+				.then(() => redis.aclWhoami())
+				.then(res => { if (res !== "default") { throw 'unexpected value for aclWhoami result: ' + res } })
+				.then(() => bob.set("foo", "bar", 0))
+				.then(
+					res => { throw 'expected set to fail' },
+					err => { if (err.kind !== "noperm") { throw 'unexpected error kind: ' + err.kind } }
+				)
+				.then(() => redis.aclDelUser("bob", "alice"))
+				.then(res => { if (res !== 2) { throw 'unexpected value for aclDelUser result: ' + res } })
+		`, rs.Addr()))
+
+		return err
+	})
+	require.NoError(t, gotScriptErr)
+
+	assert.Equal(t, [][]string{
+		{"HELLO", "2"},
+		{"ACL", "setuser", "bob", "on", ">secret", "~cache:*", "-@all", "+get"},
+		{"ACL", "list"},
+		{"ACL", "whoami"},
+		{"HELLO", "2", "auth", "bob", "secret"},
+		{"AUTH", "bob", "secret"},
+		{"SET", "foo", "bar"},
+		{"ACL", "deluser", "bob", "alice"},
+	}, rs.GotCommands())
+}
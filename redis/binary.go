@@ -0,0 +1,303 @@
+package redis
+
+import (
+	"fmt"
+
+	"github.com/grafana/sobek"
+)
+
+// binaryArgs returns the provided arguments, converted to a representation
+// go-redis sends to the server verbatim.
+//
+// On top of the types supported by isSupportedType, it accepts binary
+// values: ArrayBuffer and Uint8Array. Those are converted to Go strings,
+// which are binary-safe, and hold a copy of the bytes: the JS buffers can
+// be modified while the command is in flight without affecting it.
+//
+// Like isSupportedType, the `offset` argument allows to report the position
+// of an argument of an unsupported type in the larger context of a call.
+func (c *Client) binaryArgs(offset int, args ...interface{}) ([]interface{}, error) {
+	converted := make([]interface{}, len(args))
+
+	for idx, arg := range args {
+		switch v := arg.(type) {
+		case sobek.ArrayBuffer:
+			converted[idx] = string(v.Bytes())
+		case []byte:
+			converted[idx] = string(v)
+		case string, int, int64, float64, bool:
+			converted[idx] = v
+		default:
+			return nil, fmt.Errorf(
+				"unsupported type provided for argument at index %d, "+
+					"supported types are string, number, boolean, ArrayBuffer, and Uint8Array", idx+offset)
+		}
+	}
+
+	return converted, nil
+}
+
+// binaryFields is like binaryArgs, but for arguments which are always sent
+// as strings, such as hash field names. Non-binary values are formatted the
+// way JS would coerce them to strings.
+func (c *Client) binaryFields(offset int, fields ...interface{}) ([]string, error) {
+	args, err := c.binaryArgs(offset, fields...)
+	if err != nil {
+		return nil, err
+	}
+
+	converted := make([]string, len(args))
+	for idx, arg := range args {
+		converted[idx] = fmt.Sprint(arg)
+	}
+
+	return converted, nil
+}
+
+// newBufferPromise is like newPromise, except that the promise resolves
+// the strings it is resolved with, as returned by go-redis for binary-safe
+// replies, as ArrayBuffer objects. Both strings and slices of strings are
+// supported.
+//
+// As ArrayBuffer objects can only be created by the JS runtime, the
+// conversion happens once the resolution is dispatched to the event loop.
+func (c *Client) newBufferPromise() (*sobek.Promise, func(result interface{}), func(reason interface{})) {
+	if c.syncCalls != nil {
+		return c.syncCalls.newPromise(toArrayBuffers)
+	}
+
+	rt := c.vu.Runtime()
+	promise, resolveFunc, rejectFunc := rt.NewPromise()
+	callback := c.vu.RegisterCallback()
+	release := c.hooks.hold(c.vu.RegisterCallback)
+
+	resolve := func(result interface{}) {
+		release()
+		callback(func() error {
+			resolveFunc(toArrayBuffers(rt, result))
+			return nil
+		})
+	}
+
+	reject := func(reason interface{}) {
+		release()
+		if err, ok := reason.(error); ok {
+			reason = classifyError(err)
+		}
+
+		callback(func() error {
+			rejectFunc(reason)
+			return nil
+		})
+	}
+
+	return promise, resolve, reject
+}
+
+// toArrayBuffers converts the strings `result` is made of, either a string
+// or a slice of strings, to ArrayBuffer objects. Other results are returned
+// as is.
+func toArrayBuffers(rt *sobek.Runtime, result interface{}) interface{} {
+	switch v := result.(type) {
+	case string:
+		return rt.NewArrayBuffer([]byte(v))
+	case []string:
+		buffers := make([]sobek.ArrayBuffer, len(v))
+		for idx, s := range v {
+			buffers[idx] = rt.NewArrayBuffer([]byte(s))
+		}
+		return buffers
+	default:
+		return v
+	}
+}
+
+// HgetBuffer is like Hget, but resolves the value associated with `field`
+// in the hash stored at `key` as an ArrayBuffer.
+func (c *Client) HgetBuffer(key string, field interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newBufferPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	fields, err := c.binaryFields(1, field)
+	if err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		value, err := c.redisClient.HGet(c.context(), key, fields[0]).Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(value)
+	}()
+
+	return promise
+}
+
+// HkeysBuffer is like Hkeys, but resolves the fields of the hash stored at
+// `key` as ArrayBuffer objects.
+func (c *Client) HkeysBuffer(key string) *sobek.Promise {
+	promise, resolve, reject := c.newBufferPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		keys, err := c.redisClient.HKeys(c.context(), key).Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(keys)
+	}()
+
+	return promise
+}
+
+// SmembersBuffer is like Smembers, but resolves the members of the set
+// stored at `key` as ArrayBuffer objects.
+func (c *Client) SmembersBuffer(key string) *sobek.Promise {
+	promise, resolve, reject := c.newBufferPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		members, err := c.redisClient.SMembers(c.context(), key).Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(members)
+	}()
+
+	return promise
+}
+
+// GetBuffer is like Get, but resolves the value of `key` as an ArrayBuffer.
+// It doesn't support the `cacheMs` option, as the cache holds strings.
+func (c *Client) GetBuffer(key string) *sobek.Promise {
+	promise, resolve, reject := c.newBufferPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		value, err := c.redisClient.Get(c.context(), key).Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(value)
+	}()
+
+	return promise
+}
+
+// GetDelBuffer is like GetDel, but resolves the value of `key` as an
+// ArrayBuffer.
+func (c *Client) GetDelBuffer(key string) *sobek.Promise {
+	promise, resolve, reject := c.newBufferPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		value, err := c.redisClient.GetDel(c.context(), key).Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(value)
+	}()
+
+	return promise
+}
+
+// LpopBuffer is like Lpop, but resolves the first element of the list stored
+// at `key` as an ArrayBuffer.
+func (c *Client) LpopBuffer(key string) *sobek.Promise {
+	promise, resolve, reject := c.newBufferPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		value, err := c.redisClient.LPop(c.context(), key).Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(value)
+	}()
+
+	return promise
+}
+
+// RpopBuffer is like Rpop, but resolves the last element of the list stored
+// at `key` as an ArrayBuffer.
+func (c *Client) RpopBuffer(key string) *sobek.Promise {
+	promise, resolve, reject := c.newBufferPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		value, err := c.redisClient.RPop(c.context(), key).Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(value)
+	}()
+
+	return promise
+}
+
+// LrangeBuffer is like Lrange, but resolves the elements of the list stored
+// at `key` as ArrayBuffer objects.
+func (c *Client) LrangeBuffer(key string, start, stop int64) *sobek.Promise {
+	promise, resolve, reject := c.newBufferPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		values, err := c.redisClient.LRange(c.context(), key, start, stop).Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(values)
+	}()
+
+	return promise
+}
@@ -0,0 +1,203 @@
+package redis
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientBinaryFieldsAndMembers(t *testing.T) {
+	t.Parallel()
+
+	t.Run("binary fields and members are sent verbatim", func(t *testing.T) {
+		t.Parallel()
+
+		ts := newTestSetup(t)
+		rs := RunT(t)
+		rs.RegisterCommandHandler("HSET", func(c *Connection, _ []string) {
+			c.WriteInteger(1)
+		})
+		rs.RegisterCommandHandler("HGET", func(c *Connection, _ []string) {
+			c.WriteBulkString("bar")
+		})
+		rs.RegisterCommandHandler("SADD", func(c *Connection, args []string) {
+			c.WriteInteger(len(args) - 1)
+		})
+		rs.RegisterCommandHandler("SISMEMBER", func(c *Connection, _ []string) {
+			c.WriteInteger(1)
+		})
+
+		gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+			_, err := ts.rt.RunString(fmt.Sprintf(`
+				const redis = new Client('redis://%s');
+				const field = new Uint8Array([0xff, 0x00, 0xc3, 0x28]);
+				const value = new Uint8Array([0x01, 0xfe]).buffer;
+
+				redis.hset("hash", field, value)
+					.then(res => { if (res !== 1) { throw 'unexpected value for hset result: ' + res } })
+					.then(() => redis.hget("hash", field))
+					.then(res => { if (res !== "bar") { throw 'unexpected value for hget result: ' + res } })
+					.then(() => redis.sadd("set", field, "plain"))
+					.then(res => { if (res !== 2) { throw 'unexpected value for sadd result: ' + res } })
+					.then(() => redis.sismember("set", field))
+					.then(res => { if (res !== true) { throw 'unexpected value for sismember result: ' + res } })
+			`, rs.Addr()))
+
+			return err
+		})
+
+		assert.NoError(t, gotScriptErr)
+		assert.Equal(t, [][]string{
+			{"HELLO", "2"},
+			{"HSET", "hash", "\xff\x00\xc3\x28", "\x01\xfe"},
+			{"HGET", "hash", "\xff\x00\xc3\x28"},
+			{"SADD", "set", "\xff\x00\xc3\x28", "plain"},
+			{"SISMEMBER", "set", "\xff\x00\xc3\x28"},
+		}, rs.GotCommands())
+	})
+
+	t.Run("buffer variants resolve to array buffers", func(t *testing.T) {
+		t.Parallel()
+
+		ts := newTestSetup(t)
+		rs := RunT(t)
+		rs.RegisterCommandHandler("HGET", func(c *Connection, _ []string) {
+			c.WriteBulkString("\x01\xfe")
+		})
+		rs.RegisterCommandHandler("HKEYS", func(c *Connection, _ []string) {
+			c.WriteArray("\xff\x00", "plain")
+		})
+		rs.RegisterCommandHandler("SMEMBERS", func(c *Connection, _ []string) {
+			c.WriteArray("\xc3\x28")
+		})
+
+		gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+			_, err := ts.rt.RunString(fmt.Sprintf(`
+				const redis = new Client('redis://%s');
+				const bytes = (buffer) => Array.from(new Uint8Array(buffer)).join(",");
+
+				redis.hgetBuffer("hash", new Uint8Array([0xff, 0x00]))
+					.then(res => {
+						if (!(res instanceof ArrayBuffer) || bytes(res) !== "1,254") {
+							throw 'unexpected value for hgetBuffer result: ' + bytes(res)
+						}
+					})
+					.then(() => redis.hkeysBuffer("hash"))
+					.then(res => {
+						if (res.length !== 2 || bytes(res[0]) !== "255,0" || bytes(res[1]) !== "112,108,97,105,110") {
+							throw 'unexpected value for hkeysBuffer result: ' + res.map(bytes)
+						}
+					})
+					.then(() => redis.smembersBuffer("set"))
+					.then(res => {
+						if (res.length !== 1 || bytes(res[0]) !== "195,40") {
+							throw 'unexpected value for smembersBuffer result: ' + res.map(bytes)
+						}
+					})
+			`, rs.Addr()))
+
+			return err
+		})
+
+		assert.NoError(t, gotScriptErr)
+		assert.Equal(t, 3, rs.HandledCommandsCount())
+	})
+
+	t.Run("unsupported types are rejected", func(t *testing.T) {
+		t.Parallel()
+
+		ts := newTestSetup(t)
+		rs := RunT(t)
+
+		gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+			_, err := ts.rt.RunString(fmt.Sprintf(`
+				const redis = new Client('redis://%s');
+
+				redis.hset("hash", new Int16Array([1, 2]), "value")
+					.then(
+						res => { throw 'expected hset to fail' },
+						err => {
+							if (!err.error().startsWith('unsupported type provided for argument at index 1')) {
+								throw 'unexpected error: ' + err
+							}
+						}
+					)
+			`, rs.Addr()))
+
+			return err
+		})
+
+		assert.NoError(t, gotScriptErr)
+		assert.Equal(t, 0, rs.HandledCommandsCount())
+	})
+}
+
+func TestClientBinaryValues(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunT(t)
+	values := make(map[string]string)
+	rs.RegisterCommandHandler("SET", func(c *Connection, args []string) {
+		values[args[0]] = args[1]
+		c.WriteOK()
+	})
+	rs.RegisterCommandHandler("GET", func(c *Connection, args []string) {
+		c.WriteBulkString(values[args[0]])
+	})
+	rs.RegisterCommandHandler("MSET", func(c *Connection, _ []string) {
+		c.WriteOK()
+	})
+	rs.RegisterCommandHandler("RPUSH", func(c *Connection, args []string) {
+		c.WriteInteger(len(args) - 1)
+	})
+	rs.RegisterCommandHandler("LRANGE", func(c *Connection, _ []string) {
+		c.WriteArray("\x08\x96\x01", "plain")
+	})
+	rs.RegisterCommandHandler("LPOP", func(c *Connection, _ []string) {
+		c.WriteBulkString("\x08\x96\x01")
+	})
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client('redis://%s');
+			const bytes = (buffer) => Array.from(new Uint8Array(buffer)).join(",");
+
+			// A protobuf-encoded message, which is not valid UTF-8.
+			const message = new Uint8Array([0x08, 0x96, 0x01]);
+
+			redis.set("message", message.buffer, 0)
+				.then(() => redis.getBuffer("message"))
+				.then(res => {
+					if (!(res instanceof ArrayBuffer) || bytes(res) !== "8,150,1") {
+						throw 'unexpected value for getBuffer result: ' + bytes(res)
+					}
+				})
+				.then(() => redis.mset({ message: message }))
+				.then(() => redis.rpush("messages", message, "plain"))
+				.then(res => { if (res !== 2) { throw 'unexpected value for rpush result: ' + res } })
+				.then(() => redis.lrangeBuffer("messages", 0, -1))
+				.then(res => {
+					if (res.length !== 2 || bytes(res[0]) !== "8,150,1" || bytes(res[1]) !== "112,108,97,105,110") {
+						throw 'unexpected value for lrangeBuffer result: ' + res.map(bytes)
+					}
+				})
+				.then(() => redis.lpopBuffer("messages"))
+				.then(res => { if (bytes(res) !== "8,150,1") { throw 'unexpected value for lpopBuffer result: ' + bytes(res) } })
+		`, rs.Addr()))
+
+		return err
+	})
+
+	assert.NoError(t, gotScriptErr)
+	assert.Equal(t, [][]string{
+		{"HELLO", "2"},
+		{"SET", "message", "\x08\x96\x01"},
+		{"GET", "message"},
+		{"MSET", "message", "\x08\x96\x01"},
+		{"RPUSH", "messages", "\x08\x96\x01", "plain"},
+		{"LRANGE", "messages", "0", "-1"},
+		{"LPOP", "messages"},
+	}, rs.GotCommands())
+}
@@ -0,0 +1,377 @@
+package redis
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/grafana/sobek"
+	"github.com/redis/go-redis/v9"
+)
+
+// Setbit sets, or clears, the bit at `offset` in the string value stored at
+// `key`, depending on `value`, which must be 0 or 1. The string is grown as
+// needed, and created if it does not exist.
+//
+// The promise resolves with the bit's previous value.
+func (c *Client) Setbit(key string, offset int64, value int) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	if value != 0 && value != 1 {
+		reject(fmt.Errorf("invalid bit value: %d; expected 0 or 1", value))
+		return promise
+	}
+
+	go func() {
+		previous, err := c.redisClient.SetBit(c.context(), key, offset, value).Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(previous)
+	}()
+
+	return promise
+}
+
+// Getbit returns the bit at `offset` in the string value stored at `key`.
+//
+// The promise resolves with the bit, 0 standing for offsets beyond the
+// string's length, and missing keys.
+func (c *Client) Getbit(key string, offset int64) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		bit, err := c.redisClient.GetBit(c.context(), key, offset).Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(bit)
+	}()
+
+	return promise
+}
+
+// bitRangeOptions holds the options of the Client's bitcount and bitpos
+// methods.
+type bitRangeOptions struct {
+	// Start and End restrict the command to a range of the string, in
+	// bytes by default. Negative values count from the end of the string.
+	Start *int64 `json:"start,omitempty"`
+	End   *int64 `json:"end,omitempty"`
+
+	// Unit is the unit of Start and End: "byte", or "bit".
+	Unit string `json:"unit,omitempty"`
+}
+
+// args returns the arguments the range options translate to.
+func (o bitRangeOptions) args() ([]interface{}, error) {
+	switch strings.ToLower(o.Unit) {
+	case "", "byte", "bit":
+	default:
+		return nil, fmt.Errorf("invalid unit: %q; expected %q or %q", o.Unit, "byte", "bit")
+	}
+
+	if o.End != nil && o.Start == nil {
+		return nil, errors.New("end requires start")
+	}
+
+	if o.Unit != "" && o.End == nil {
+		return nil, errors.New("unit requires start and end")
+	}
+
+	var args []interface{}
+	if o.Start != nil {
+		args = append(args, *o.Start)
+	}
+	if o.End != nil {
+		args = append(args, *o.End)
+	}
+	if o.Unit != "" {
+		args = append(args, o.Unit)
+	}
+
+	return args, nil
+}
+
+// Bitcount returns the number of bits set in the string value stored at
+// `key`, or in the range of it delimited by the `start` and `end` options.
+//
+// The promise resolves with the number of bits set, or 0 if `key` does not
+// exist.
+func (c *Client) Bitcount(key string, options map[string]interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	var opts bitRangeOptions
+	if err := decodeOptions(options, &opts); err != nil {
+		reject(fmt.Errorf("invalid bitcount options; reason: %w", err))
+		return promise
+	}
+
+	rangeArgs, err := opts.args()
+	if err != nil {
+		reject(fmt.Errorf("invalid bitcount options; %w", err))
+		return promise
+	}
+
+	if opts.Start != nil && opts.End == nil {
+		reject(errors.New("invalid bitcount options; start requires end"))
+		return promise
+	}
+
+	go func() {
+		ctx := c.context()
+		cmd := redis.NewIntCmd(ctx, append([]interface{}{"bitcount", key}, rangeArgs...)...)
+		_ = c.redisClient.Process(ctx, cmd)
+
+		count, err := cmd.Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(count)
+	}()
+
+	return promise
+}
+
+// Bitpos returns the position of the first bit set to `bit`, 0 or 1, in the
+// string value stored at `key`, or in the range of it delimited by the
+// `start` and `end` options.
+//
+// The promise resolves with the position of the bit, or -1 if no such bit
+// is found.
+func (c *Client) Bitpos(key string, bit int, options map[string]interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	if bit != 0 && bit != 1 {
+		reject(fmt.Errorf("invalid bit value: %d; expected 0 or 1", bit))
+		return promise
+	}
+
+	var opts bitRangeOptions
+	if err := decodeOptions(options, &opts); err != nil {
+		reject(fmt.Errorf("invalid bitpos options; reason: %w", err))
+		return promise
+	}
+
+	rangeArgs, err := opts.args()
+	if err != nil {
+		reject(fmt.Errorf("invalid bitpos options; %w", err))
+		return promise
+	}
+
+	go func() {
+		ctx := c.context()
+		cmd := redis.NewIntCmd(ctx, append([]interface{}{"bitpos", key, bit}, rangeArgs...)...)
+		_ = c.redisClient.Process(ctx, cmd)
+
+		pos, err := cmd.Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(pos)
+	}()
+
+	return promise
+}
+
+// Bitop performs the bitwise `operation` ("and", "or", "xor", or "not")
+// between the string values stored at `keys`, and stores the result at
+// `destination`. The "not" operation takes a single key.
+//
+// The promise resolves with the length of the string stored at
+// `destination`.
+func (c *Client) Bitop(operation string, destination string, keys ...string) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	if len(keys) == 0 {
+		reject(errors.New("at least one key must be provided to bitop"))
+		return promise
+	}
+
+	var bitop func() *redis.IntCmd
+	ctx := c.context()
+	switch strings.ToLower(operation) {
+	case "and":
+		bitop = func() *redis.IntCmd { return c.redisClient.BitOpAnd(ctx, destination, keys...) }
+	case "or":
+		bitop = func() *redis.IntCmd { return c.redisClient.BitOpOr(ctx, destination, keys...) }
+	case "xor":
+		bitop = func() *redis.IntCmd { return c.redisClient.BitOpXor(ctx, destination, keys...) }
+	case "not":
+		if len(keys) != 1 {
+			reject(fmt.Errorf("the not bitop operation takes a single key; got %d", len(keys)))
+			return promise
+		}
+		bitop = func() *redis.IntCmd { return c.redisClient.BitOpNot(ctx, destination, keys[0]) }
+	default:
+		reject(fmt.Errorf("invalid bitop operation: %q; expected one of %q, %q, %q, or %q",
+			operation, "and", "or", "xor", "not"))
+		return promise
+	}
+
+	go func() {
+		length, err := bitop().Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(length)
+	}()
+
+	return promise
+}
+
+// bitfieldOperation is an operation of the Client's bitfield method.
+type bitfieldOperation struct {
+	// Op is the operation: "get", "set", "incrby", or "overflow".
+	Op string `json:"op"`
+
+	// Type is the type of the integer operated on, such as "u8" or "i16".
+	Type string `json:"type,omitempty"`
+
+	// Offset is the offset of the integer, as a number of bits, or as a
+	// multiple of the type's width when prefixed with "#".
+	Offset interface{} `json:"offset,omitempty"`
+
+	// Value is the value set by "set" operations, the increment of
+	// "incrby" ones, and the overflow behavior ("wrap", "sat", or "fail")
+	// of "overflow" ones.
+	Value interface{} `json:"value,omitempty"`
+}
+
+// args returns the arguments of the BITFIELD subcommand the operation
+// translates to.
+func (o bitfieldOperation) args() ([]interface{}, error) {
+	op := strings.ToLower(o.Op)
+
+	if op == "overflow" {
+		behavior := strings.ToLower(fmt.Sprint(o.Value))
+		switch behavior {
+		case "wrap", "sat", "fail":
+			return []interface{}{op, behavior}, nil
+		default:
+			return nil, fmt.Errorf("invalid overflow behavior: %v; expected one of %q, %q, or %q",
+				o.Value, "wrap", "sat", "fail")
+		}
+	}
+
+	if o.Type == "" {
+		return nil, errors.New("type must be set")
+	}
+
+	var offset interface{}
+	switch v := o.Offset.(type) {
+	case float64:
+		offset = int64(v)
+	case string:
+		offset = v
+	default:
+		return nil, errors.New("offset must be a number, or a string such as \"#1\"")
+	}
+
+	switch op {
+	case "get":
+		return []interface{}{op, o.Type, offset}, nil
+	case "set", "incrby":
+		value, ok := o.Value.(float64)
+		if !ok {
+			return nil, fmt.Errorf("value of %s operations must be a number", op)
+		}
+
+		return []interface{}{op, o.Type, offset, int64(value)}, nil
+	default:
+		return nil, fmt.Errorf("invalid op: %q; expected one of %q, %q, %q, or %q",
+			o.Op, "get", "set", "incrby", "overflow")
+	}
+}
+
+// Bitfield performs the provided operations on the integers of arbitrary
+// width, and offset, stored in the string value at `key`, as
+// {op, type, offset, value} objects.
+//
+// The promise resolves with an array holding the reply of each "get",
+// "set", and "incrby" operation: the integer read, the previous value of
+// the integer set, and the incremented value, respectively. Increments
+// failing with the "fail" overflow behavior reply with null.
+func (c *Client) Bitfield(key string, operations []interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	if len(operations) == 0 {
+		reject(errors.New("at least one operation must be provided to bitfield"))
+		return promise
+	}
+
+	args := []interface{}{"bitfield", key}
+	for idx, operation := range operations {
+		obj, ok := operation.(map[string]interface{})
+		if !ok {
+			reject(fmt.Errorf("invalid bitfield operation at index %d; expected an {op, type, offset, value} object", idx))
+			return promise
+		}
+
+		var op bitfieldOperation
+		if err := decodeOptions(obj, &op); err != nil {
+			reject(fmt.Errorf("invalid bitfield operation at index %d; reason: %w", idx, err))
+			return promise
+		}
+
+		opArgs, err := op.args()
+		if err != nil {
+			reject(fmt.Errorf("invalid bitfield operation at index %d; %w", idx, err))
+			return promise
+		}
+
+		args = append(args, opArgs...)
+	}
+
+	go func() {
+		replies, err := c.redisClient.Do(c.context(), args...).Slice()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(replies)
+	}()
+
+	return promise
+}
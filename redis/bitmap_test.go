@@ -0,0 +1,96 @@
+package redis
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientBitmaps(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunT(t)
+	rs.RegisterCommandHandler("SETBIT", func(c *Connection, _ []string) {
+		c.WriteInteger(0)
+	})
+	rs.RegisterCommandHandler("GETBIT", func(c *Connection, _ []string) {
+		c.WriteInteger(1)
+	})
+	rs.RegisterCommandHandler("BITCOUNT", func(c *Connection, args []string) {
+		c.WriteInteger(len(args))
+	})
+	rs.RegisterCommandHandler("BITPOS", func(c *Connection, _ []string) {
+		c.WriteInteger(-1)
+	})
+	rs.RegisterCommandHandler("BITOP", func(c *Connection, _ []string) {
+		c.WriteInteger(4)
+	})
+	rs.RegisterCommandHandler("BITFIELD", func(c *Connection, _ []string) {
+		c.WriteValue([]interface{}{0, 200, nil})
+	})
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client('redis://%s');
+
+			redis.setbit("flags", 7, 1)
+				.then(res => { if (res !== 0) { throw 'unexpected value for setbit result: ' + res } })
+				.then(() => redis.getbit("flags", 7))
+				.then(res => { if (res !== 1) { throw 'unexpected value for getbit result: ' + res } })
+				.then(() => redis.bitcount("flags"))
+				.then(res => { if (res !== 1) { throw 'unexpected value for bitcount result: ' + res } })
+				.then(() => redis.bitcount("flags", { start: 0, end: 7, unit: "bit" }))
+				.then(res => { if (res !== 4) { throw 'unexpected value for bitcount result: ' + res } })
+				.then(() => redis.bitpos("flags", 0, { start: 2 }))
+				.then(res => { if (res !== -1) { throw 'unexpected value for bitpos result: ' + res } })
+				.then(() => redis.bitop("or", "all", "flags", "other"))
+				.then(res => { if (res !== 4) { throw 'unexpected value for bitop result: ' + res } })
+				.then(() => redis.bitfield("counters", [
+					{ op: "set", type: "u8", offset: 0, value: 100 },
+					{ op: "incrby", type: "u8", offset: "#1", value: 200 },
+					{ op: "overflow", value: "fail" },
+					{ op: "incrby", type: "u8", offset: 0, value: 200 },
+				]))
+				.then(res => { if (JSON.stringify(res) !== '[0,200,null]') { throw 'unexpected value for bitfield result: ' + JSON.stringify(res) } })
+				.then(() => redis.setbit("flags", 7, 2))
+				.then(
+					res => { throw 'expected setbit to fail' },
+					err => { if (!err.error().startsWith('invalid bit value')) { throw 'unexpected error: ' + err.error() } }
+				)
+				.then(() => redis.bitcount("flags", { start: 0 }))
+				.then(
+					res => { throw 'expected bitcount to fail' },
+					err => { if (!err.error().endsWith('start requires end')) { throw 'unexpected error: ' + err.error() } }
+				)
+				.then(() => redis.bitop("not", "inverted", "flags", "other"))
+				.then(
+					res => { throw 'expected bitop to fail' },
+					err => { if (!err.error().includes('single key')) { throw 'unexpected error: ' + err.error() } }
+				)
+				.then(() => redis.bitfield("counters", [{ op: "get", offset: 0 }]))
+				.then(
+					res => { throw 'expected bitfield to fail' },
+					err => { if (!err.error().startsWith('invalid bitfield operation at index 0')) { throw 'unexpected error: ' + err.error() } }
+				)
+		`, rs.Addr()))
+
+		return err
+	})
+
+	assert.NoError(t, gotScriptErr)
+	assert.Equal(t, [][]string{
+		{"HELLO", "2"},
+		{"SETBIT", "flags", "7", "1"},
+		{"GETBIT", "flags", "7"},
+		{"BITCOUNT", "flags"},
+		{"BITCOUNT", "flags", "0", "7", "bit"},
+		{"BITPOS", "flags", "0", "2"},
+		{"BITOP", "or", "all", "flags", "other"},
+		{
+			"BITFIELD", "counters", "set", "u8", "0", "100", "incrby", "u8", "#1", "200",
+			"overflow", "fail", "incrby", "u8", "0", "200",
+		},
+	}, rs.GotCommands())
+}
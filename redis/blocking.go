@@ -0,0 +1,329 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/grafana/sobek"
+	"github.com/redis/go-redis/v9"
+)
+
+// Blocking commands wait, server-side, for data to be available on their
+// keys. Each waiting command holds a connection of the pool until it
+// returns, so the pool should be sized accordingly.
+
+// blockingTimeoutContextKey is the context key under which the timeout of a
+// blocking command is stored, so that the command timeout isn't cut short
+// by the commandTimeout option.
+type blockingTimeoutContextKey struct{}
+
+// blockingTimeout validates the `timeout`, in seconds, of `command`. It must
+// be positive, as a blocking command waiting indefinitely would keep the
+// VU's iteration from ever ending.
+func blockingTimeout(command string, timeout int64) (time.Duration, error) {
+	if timeout <= 0 {
+		return 0, fmt.Errorf("invalid %s timeout %d; expected a positive number of seconds", command, timeout)
+	}
+
+	return time.Duration(timeout) * time.Second, nil
+}
+
+// runBlocking runs the blocking `command` with the provided `timeout`, and
+// waits for it to return, or for the VU's context to be done, whichever
+// comes first.
+//
+// go-redis doesn't interrupt commands waiting for their reply when their
+// context is canceled. If the VU's iteration is interrupted, runBlocking
+// returns right away, while the command keeps its connection until its
+// timeout expires.
+func (c *Client) runBlocking(command string, timeout time.Duration, run func(ctx context.Context) error) error {
+	ctx := context.WithValue(c.context(), blockingTimeoutContextKey{}, timeout)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- run(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("%s was interrupted; reason: %w", command, ctx.Err())
+	}
+}
+
+// Blpop removes and returns the first element of the first non-empty list
+// among `keys`, waiting up to `timeout` seconds for an element to be
+// pushed if they are all empty.
+//
+// The promise resolves with an object holding the `key` the element was
+// popped from and its `value`, or null if the timeout expired.
+func (c *Client) Blpop(keys []string, timeout int64) *sobek.Promise {
+	return c.blockingPop("blpop", keys, timeout, redis.UniversalClient.BLPop)
+}
+
+// Brpop removes and returns the last element of the first non-empty list
+// among `keys`, waiting up to `timeout` seconds for an element to be
+// pushed if they are all empty.
+//
+// The promise resolves with an object holding the `key` the element was
+// popped from and its `value`, or null if the timeout expired.
+func (c *Client) Brpop(keys []string, timeout int64) *sobek.Promise {
+	return c.blockingPop("brpop", keys, timeout, redis.UniversalClient.BRPop)
+}
+
+// blockingPop implements Blpop and Brpop, using the provided go-redis
+// `pop` method, as the go-redis client is only known once connected.
+func (c *Client) blockingPop(
+	command string,
+	keys []string,
+	timeout int64,
+	pop func(client redis.UniversalClient, ctx context.Context, timeout time.Duration, keys ...string) *redis.StringSliceCmd,
+) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	if len(keys) == 0 {
+		reject(fmt.Errorf("at least one key must be provided to %s", command))
+		return promise
+	}
+
+	duration, err := blockingTimeout(command, timeout)
+	if err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		var reply []string
+		err := c.runBlocking(command, duration, func(ctx context.Context) (err error) {
+			reply, err = pop(c.redisClient, ctx, duration, keys...).Result()
+			return err
+		})
+		if errors.Is(err, redis.Nil) {
+			resolve(nil)
+			return
+		}
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(map[string]interface{}{"key": reply[0], "value": reply[1]})
+	}()
+
+	return promise
+}
+
+// Blmove atomically removes the element at the `from` end, "left" or
+// "right", of the list stored at `source`, and pushes it at the `to` end of
+// the list stored at `destination`, waiting up to `timeout` seconds for an
+// element to be pushed to `source` if it is empty.
+//
+// The promise resolves with the moved element, or null if the timeout
+// expired.
+func (c *Client) Blmove(source, destination, from, to string, timeout int64) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	if err := checkListEnds("blmove", from, to); err != nil {
+		reject(err)
+		return promise
+	}
+
+	duration, err := blockingTimeout("blmove", timeout)
+	if err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		var moved string
+		err := c.runBlocking("blmove", duration, func(ctx context.Context) (err error) {
+			moved, err = c.redisClient.BLMove(ctx, source, destination, from, to, duration).Result()
+			return err
+		})
+		if errors.Is(err, redis.Nil) {
+			resolve(nil)
+			return
+		}
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(moved)
+	}()
+
+	return promise
+}
+
+// Blmpop is the blocking variant of Lmpop: it waits up to `timeout` seconds
+// for an element to be pushed if all the lists are empty. It requires
+// Redis 7.
+//
+// The promise resolves with an object holding the `key` the elements were
+// popped from and its `values`, or null if the timeout expired.
+func (c *Client) Blmpop(keys []string, from string, timeout int64, count ...int64) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	if len(keys) == 0 {
+		reject(errors.New("at least one key must be provided to blmpop"))
+		return promise
+	}
+
+	if err := checkListEnds("blmpop", from); err != nil {
+		reject(err)
+		return promise
+	}
+
+	n, err := popCount("blmpop", count)
+	if err != nil {
+		reject(err)
+		return promise
+	}
+
+	duration, err := blockingTimeout("blmpop", timeout)
+	if err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		var (
+			key    string
+			values []string
+		)
+		err := c.runBlocking("blmpop", duration, func(ctx context.Context) (err error) {
+			key, values, err = c.redisClient.BLMPop(ctx, duration, from, n, keys...).Result()
+			return err
+		})
+		if errors.Is(err, redis.Nil) {
+			resolve(nil)
+			return
+		}
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(map[string]interface{}{"key": key, "values": values})
+	}()
+
+	return promise
+}
+
+// Bzpopmin removes and returns the member with the lowest score of the
+// first non-empty sorted set among `keys`, waiting up to `timeout` seconds
+// for a member to be added if they are all empty.
+//
+// The promise resolves with an object holding the `key` the member was
+// popped from, the `member`, and its `score`, or null if the timeout
+// expired.
+func (c *Client) Bzpopmin(keys []string, timeout int64) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	if len(keys) == 0 {
+		reject(errors.New("at least one key must be provided to bzpopmin"))
+		return promise
+	}
+
+	duration, err := blockingTimeout("bzpopmin", timeout)
+	if err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		var popped *redis.ZWithKey
+		err := c.runBlocking("bzpopmin", duration, func(ctx context.Context) (err error) {
+			popped, err = c.redisClient.BZPopMin(ctx, duration, keys...).Result()
+			return err
+		})
+		if errors.Is(err, redis.Nil) {
+			resolve(nil)
+			return
+		}
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(map[string]interface{}{
+			"key":    popped.Key,
+			"member": popped.Member,
+			"score":  popped.Score,
+		})
+	}()
+
+	return promise
+}
+
+// XxreadBlock reads the entries of one or more streams, starting after the
+// provided IDs, as Xxread does, but waits up to `timeout` seconds for
+// entries to be added if none are available. The "$" ID only reads the
+// entries added while waiting.
+//
+// The promise resolves with the read entries, in the same shape as Xxread,
+// or an empty array if the timeout expired.
+func (c *Client) XxreadBlock(streams map[string]string, count int64, timeout int64) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	if len(streams) == 0 {
+		reject(errors.New("at least one stream must be provided to xreadBlock"))
+		return promise
+	}
+
+	duration, err := blockingTimeout("xreadBlock", timeout)
+	if err != nil {
+		reject(err)
+		return promise
+	}
+
+	args := streamsArg(streams)
+
+	go func() {
+		var read []redis.XStream
+		err := c.runBlocking("xreadBlock", duration, func(ctx context.Context) (err error) {
+			read, err = c.redisClient.XRead(ctx, &redis.XReadArgs{
+				Streams: args,
+				Count:   count,
+				Block:   duration,
+			}).Result()
+			return err
+		})
+		if err != nil && !errors.Is(err, redis.Nil) {
+			reject(err)
+			return
+		}
+
+		resolve(c.readStreams(read))
+	}()
+
+	return promise
+}
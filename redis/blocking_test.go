@@ -0,0 +1,118 @@
+package redis
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientBlockingCommands(t *testing.T) {
+	t.Parallel()
+
+	t.Run("blocking commands resolve with the popped elements", func(t *testing.T) {
+		t.Parallel()
+
+		ts := newTestSetup(t)
+		rs := RunT(t)
+		rs.RegisterCommandHandler("BLPOP", func(c *Connection, args []string) {
+			c.WriteArray(args[1], "first")
+		})
+		rs.RegisterCommandHandler("BRPOP", func(c *Connection, _ []string) {
+			c.WriteNull()
+		})
+		rs.RegisterCommandHandler("BLMOVE", func(c *Connection, _ []string) {
+			c.WriteBulkString("moved")
+		})
+		rs.RegisterCommandHandler("BZPOPMIN", func(c *Connection, args []string) {
+			c.WriteArray(args[0], "alice", "1.5")
+		})
+		rs.RegisterCommandHandler("XREAD", func(c *Connection, args []string) {
+			c.WriteValue([]interface{}{
+				[]interface{}{args[5], []interface{}{
+					[]interface{}{"1-0", []interface{}{"field", "value"}},
+				}},
+			})
+		})
+
+		gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+			_, err := ts.rt.RunString(fmt.Sprintf(`
+				const redis = new Client('redis://%s');
+
+				redis.blpop(["empty", "jobs"], 5)
+					.then(res => {
+						if (res.key !== "jobs" || res.value !== "first") { throw 'unexpected value for blpop result: ' + JSON.stringify(res) }
+					})
+					.then(() => redis.brpop(["jobs"], 1))
+					.then(res => { if (res !== null) { throw 'unexpected value for brpop result: ' + JSON.stringify(res) } })
+					.then(() => redis.blmove("jobs", "done", "left", "RIGHT", 2))
+					.then(res => { if (res !== "moved") { throw 'unexpected value for blmove result: ' + res } })
+					.then(() => redis.bzpopmin(["scores"], 3))
+					.then(res => {
+						if (res.key !== "scores" || res.member !== "alice" || res.score !== 1.5) {
+							throw 'unexpected value for bzpopmin result: ' + JSON.stringify(res)
+						}
+					})
+					.then(() => redis.xreadBlock({ events: "$" }, 10, 4))
+					.then(res => {
+						if (res.length !== 1 || res[0].stream !== "events" || res[0].entries[0].fields.field !== "value") {
+							throw 'unexpected value for xreadBlock result: ' + JSON.stringify(res)
+						}
+					})
+					.then(() => redis.blpop(["jobs"], 0))
+					.then(
+						res => { throw 'expected blpop to fail' },
+						err => { if (err.error() !== 'invalid blpop timeout 0; expected a positive number of seconds') { throw 'unexpected error: ' + err.error() } }
+					)
+					.then(() => redis.blmove("jobs", "done", "up", "left", 1))
+					.then(
+						res => { throw 'expected blmove to fail' },
+						err => { if (err.error() !== 'invalid blmove list end "up"; expected "left" or "right"') { throw 'unexpected error: ' + err.error() } }
+					)
+			`, rs.Addr()))
+
+			return err
+		})
+
+		assert.NoError(t, gotScriptErr)
+		assert.Equal(t, [][]string{
+			{"HELLO", "2"},
+			{"BLPOP", "empty", "jobs", "5"},
+			{"BRPOP", "jobs", "1"},
+			{"BLMOVE", "jobs", "done", "left", "RIGHT", "2"},
+			{"BZPOPMIN", "scores", "3"},
+			{"XREAD", "count", "10", "block", "4000", "streams", "events", "$"},
+		}, rs.GotCommands())
+	})
+
+	t.Run("blocking commands are not cut short by the commandTimeout option", func(t *testing.T) {
+		t.Parallel()
+
+		ts := newTestSetup(t)
+		rs := RunT(t)
+		rs.RegisterCommandHandler("BLPOP", func(c *Connection, args []string) {
+			time.Sleep(300 * time.Millisecond)
+			c.WriteArray(args[0], "late")
+		})
+
+		gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+			_, err := ts.rt.RunString(fmt.Sprintf(`
+				const redis = new Client({
+					socket: {
+						host: '%s',
+						port: %d,
+					},
+					commandTimeout: 100,
+				});
+
+				redis.blpop(["jobs"], 1)
+					.then(res => { if (res.value !== "late") { throw 'unexpected value for blpop result: ' + JSON.stringify(res) } })
+			`, rs.Addr().IP.String(), rs.Addr().Port))
+
+			return err
+		})
+
+		assert.NoError(t, gotScriptErr)
+	})
+}
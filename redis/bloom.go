@@ -0,0 +1,805 @@
+package redis
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/grafana/sobek"
+)
+
+// bfReserveOptions holds the options of the Client's bfReserve method.
+type bfReserveOptions struct {
+	// Expansion is the factor the capacity of the sub-filters created once
+	// the filter is full grows by.
+	Expansion int64 `json:"expansion,omitempty"`
+
+	// NonScaling makes adding items fail once the filter is full, rather
+	// than creating a sub-filter.
+	NonScaling bool `json:"nonScaling,omitempty"`
+}
+
+// BfReserve creates an empty Bloom filter at `key`, sized to hold
+// `capacity` items with a false positive rate of `errorRate`, between 0
+// and 1.
+//
+// The promise resolves with "OK". If `key` already exists, the promise is
+// rejected with an error.
+func (c *Client) BfReserve(key string, errorRate float64, capacity int64, options map[string]interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	var opts bfReserveOptions
+	if err := decodeOptions(options, &opts); err != nil {
+		reject(fmt.Errorf("invalid bfReserve options; reason: %w", err))
+		return promise
+	}
+
+	if opts.Expansion != 0 && opts.NonScaling {
+		reject(errors.New("invalid bfReserve options; expansion and nonScaling are mutually exclusive"))
+		return promise
+	}
+
+	args := []interface{}{"bf.reserve", key, errorRate, capacity}
+	if opts.Expansion != 0 {
+		args = append(args, "expansion", opts.Expansion)
+	}
+	if opts.NonScaling {
+		args = append(args, "nonscaling")
+	}
+
+	go func() {
+		status, err := c.redisClient.Do(c.context(), args...).Text()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(status)
+	}()
+
+	return promise
+}
+
+// BfAdd adds `item` to the Bloom filter stored at `key`, creating it with
+// the server's default capacity and error rate if it does not exist.
+//
+// The promise resolves with true if the item was added, or false if it
+// may have been added already.
+func (c *Client) BfAdd(key string, item interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	if err := c.isSupportedType(1, item); err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		added, err := c.redisClient.Do(c.context(), "bf.add", key, item).Bool()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(added)
+	}()
+
+	return promise
+}
+
+// BfMAdd is like BfAdd, except that it adds all the provided items.
+//
+// The promise resolves with an array holding, for each item, in order,
+// whether it was added.
+func (c *Client) BfMAdd(key string, items ...interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	if len(items) == 0 {
+		reject(errors.New("at least one item must be provided to bfMAdd"))
+		return promise
+	}
+
+	if err := c.isSupportedType(1, items...); err != nil {
+		reject(err)
+		return promise
+	}
+
+	args := append([]interface{}{"bf.madd", key}, items...)
+
+	go func() {
+		added, err := c.redisClient.Do(c.context(), args...).BoolSlice()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(added)
+	}()
+
+	return promise
+}
+
+// BfExists returns whether `item` may have been added to the Bloom filter
+// stored at `key`.
+//
+// The promise resolves with false if the item was certainly not added, or
+// if `key` does not exist, and true otherwise.
+func (c *Client) BfExists(key string, item interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	if err := c.isSupportedType(1, item); err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		exists, err := c.redisClient.Do(c.context(), "bf.exists", key, item).Bool()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(exists)
+	}()
+
+	return promise
+}
+
+// BfMExists is like BfExists, except that it checks all the provided
+// items.
+//
+// The promise resolves with an array holding, for each item, in order,
+// whether it may have been added.
+func (c *Client) BfMExists(key string, items ...interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	if len(items) == 0 {
+		reject(errors.New("at least one item must be provided to bfMExists"))
+		return promise
+	}
+
+	if err := c.isSupportedType(1, items...); err != nil {
+		reject(err)
+		return promise
+	}
+
+	args := append([]interface{}{"bf.mexists", key}, items...)
+
+	go func() {
+		exists, err := c.redisClient.Do(c.context(), args...).BoolSlice()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(exists)
+	}()
+
+	return promise
+}
+
+// cfReserveOptions holds the options of the Client's cfReserve method.
+type cfReserveOptions struct {
+	// BucketSize is the number of items in each bucket.
+	BucketSize int64 `json:"bucketSize,omitempty"`
+
+	// MaxIterations is the number of attempts at swapping items between
+	// buckets, before the filter is considered full.
+	MaxIterations int64 `json:"maxIterations,omitempty"`
+
+	// Expansion is the factor the capacity of the sub-filters created once
+	// the filter is full grows by.
+	Expansion int64 `json:"expansion,omitempty"`
+}
+
+// CfReserve creates an empty Cuckoo filter at `key`, sized to hold
+// `capacity` items.
+//
+// The promise resolves with "OK". If `key` already exists, the promise is
+// rejected with an error.
+func (c *Client) CfReserve(key string, capacity int64, options map[string]interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	var opts cfReserveOptions
+	if err := decodeOptions(options, &opts); err != nil {
+		reject(fmt.Errorf("invalid cfReserve options; reason: %w", err))
+		return promise
+	}
+
+	args := []interface{}{"cf.reserve", key, capacity}
+	if opts.BucketSize != 0 {
+		args = append(args, "bucketsize", opts.BucketSize)
+	}
+	if opts.MaxIterations != 0 {
+		args = append(args, "maxiterations", opts.MaxIterations)
+	}
+	if opts.Expansion != 0 {
+		args = append(args, "expansion", opts.Expansion)
+	}
+
+	go func() {
+		status, err := c.redisClient.Do(c.context(), args...).Text()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(status)
+	}()
+
+	return promise
+}
+
+// CfAdd adds `item` to the Cuckoo filter stored at `key`, creating it with
+// the server's default capacity if it does not exist. Unlike Bloom
+// filters, Cuckoo filters hold duplicate items, each of which can be
+// deleted.
+//
+// The promise resolves with true. If the filter is full, the promise is
+// rejected with an error.
+func (c *Client) CfAdd(key string, item interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	if err := c.isSupportedType(1, item); err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		added, err := c.redisClient.Do(c.context(), "cf.add", key, item).Bool()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(added)
+	}()
+
+	return promise
+}
+
+// CfAddNx is like CfAdd, except that it only adds `item` if it wasn't
+// added already.
+//
+// The promise resolves with true if the item was added, or false if it
+// may have been added already.
+func (c *Client) CfAddNx(key string, item interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	if err := c.isSupportedType(1, item); err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		added, err := c.redisClient.Do(c.context(), "cf.addnx", key, item).Bool()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(added)
+	}()
+
+	return promise
+}
+
+// CfExists returns whether `item` may have been added to the Cuckoo filter
+// stored at `key`.
+//
+// The promise resolves with false if the item was certainly not added, or
+// if `key` does not exist, and true otherwise.
+func (c *Client) CfExists(key string, item interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	if err := c.isSupportedType(1, item); err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		exists, err := c.redisClient.Do(c.context(), "cf.exists", key, item).Bool()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(exists)
+	}()
+
+	return promise
+}
+
+// CfDel deletes one occurrence of `item` from the Cuckoo filter stored at
+// `key`.
+//
+// The promise resolves with true if the item was deleted, or false if it
+// wasn't found. If `key` does not exist, the promise is rejected with an
+// error.
+func (c *Client) CfDel(key string, item interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	if err := c.isSupportedType(1, item); err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		deleted, err := c.redisClient.Do(c.context(), "cf.del", key, item).Bool()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(deleted)
+	}()
+
+	return promise
+}
+
+// CfCount returns the number of times `item` may have been added to the
+// Cuckoo filter stored at `key`.
+//
+// The promise resolves with the count, which may exceed the actual one,
+// or 0 if `key` does not exist.
+func (c *Client) CfCount(key string, item interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	if err := c.isSupportedType(1, item); err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		count, err := c.redisClient.Do(c.context(), "cf.count", key, item).Int64()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(count)
+	}()
+
+	return promise
+}
+
+// topkReserveOptions holds the options of the Client's topkReserve method.
+// They must be set together, or not at all.
+type topkReserveOptions struct {
+	// Width is the number of counters kept in each array.
+	Width int64 `json:"width,omitempty"`
+
+	// Depth is the number of arrays.
+	Depth int64 `json:"depth,omitempty"`
+
+	// Decay is the probability of decreasing a counter in an occupied
+	// bucket, between 0 and 1.
+	Decay float64 `json:"decay,omitempty"`
+}
+
+// TopkReserve creates an empty Top-K sketch at `key`, keeping track of the
+// `topk` most frequent items.
+//
+// The promise resolves with "OK". If `key` already exists, the promise is
+// rejected with an error.
+func (c *Client) TopkReserve(key string, topk int64, options map[string]interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	var opts topkReserveOptions
+	if err := decodeOptions(options, &opts); err != nil {
+		reject(fmt.Errorf("invalid topkReserve options; reason: %w", err))
+		return promise
+	}
+
+	args := []interface{}{"topk.reserve", key, topk}
+	switch {
+	case opts.Width != 0 && opts.Depth != 0 && opts.Decay != 0:
+		args = append(args, opts.Width, opts.Depth, opts.Decay)
+	case opts.Width != 0 || opts.Depth != 0 || opts.Decay != 0:
+		reject(errors.New("invalid topkReserve options; width, depth, and decay must be set together"))
+		return promise
+	}
+
+	go func() {
+		status, err := c.redisClient.Do(c.context(), args...).Text()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(status)
+	}()
+
+	return promise
+}
+
+// TopkAdd adds the provided items to the Top-K sketch stored at `key`.
+//
+// The promise resolves with an array holding the items expelled from the
+// top-k list by the added items, which is empty if none were.
+func (c *Client) TopkAdd(key string, items ...interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	if len(items) == 0 {
+		reject(errors.New("at least one item must be provided to topkAdd"))
+		return promise
+	}
+
+	if err := c.isSupportedType(1, items...); err != nil {
+		reject(err)
+		return promise
+	}
+
+	args := append([]interface{}{"topk.add", key}, items...)
+
+	go func() {
+		replies, err := c.redisClient.Do(c.context(), args...).Slice()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(expelledItems(replies))
+	}()
+
+	return promise
+}
+
+// TopkIncrBy increments the count of the items of the Top-K sketch stored
+// at `key` by their value in `increments`.
+//
+// The promise resolves with an array holding the items expelled from the
+// top-k list by the incremented items, which is empty if none were.
+func (c *Client) TopkIncrBy(key string, increments map[string]int64) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	if len(increments) == 0 {
+		reject(errors.New("at least one item must be provided to topkIncrBy"))
+		return promise
+	}
+
+	args := []interface{}{"topk.incrby", key}
+	for _, item := range sortedKeys(increments) {
+		args = append(args, item, increments[item])
+	}
+
+	go func() {
+		replies, err := c.redisClient.Do(c.context(), args...).Slice()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(expelledItems(replies))
+	}()
+
+	return promise
+}
+
+// expelledItems returns the items of the reply of TOPK.ADD, or
+// TOPK.INCRBY, leaving out the nulls standing for the items which didn't
+// expel any.
+func expelledItems(replies []interface{}) []interface{} {
+	expelled := make([]interface{}, 0, len(replies))
+	for _, reply := range replies {
+		if reply != nil {
+			expelled = append(expelled, reply)
+		}
+	}
+
+	return expelled
+}
+
+// TopkQuery returns whether the provided items are in the top-k list of
+// the Top-K sketch stored at `key`.
+//
+// The promise resolves with an array holding, for each item, in order,
+// whether it is in the list.
+func (c *Client) TopkQuery(key string, items ...interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	if len(items) == 0 {
+		reject(errors.New("at least one item must be provided to topkQuery"))
+		return promise
+	}
+
+	if err := c.isSupportedType(1, items...); err != nil {
+		reject(err)
+		return promise
+	}
+
+	args := append([]interface{}{"topk.query", key}, items...)
+
+	go func() {
+		found, err := c.redisClient.Do(c.context(), args...).BoolSlice()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(found)
+	}()
+
+	return promise
+}
+
+// topkListOptions holds the options of the Client's topkList method.
+type topkListOptions struct {
+	// WithCount returns the count of each item along with it.
+	WithCount bool `json:"withCount,omitempty"`
+}
+
+// TopkList returns the top-k list of the Top-K sketch stored at `key`.
+//
+// The promise resolves with an array holding the items of the list, most
+// frequent first, or with the `withCount` option set, an array of objects
+// holding each `item`, and its `count`.
+func (c *Client) TopkList(key string, options map[string]interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	var opts topkListOptions
+	if err := decodeOptions(options, &opts); err != nil {
+		reject(fmt.Errorf("invalid topkList options; reason: %w", err))
+		return promise
+	}
+
+	args := []interface{}{"topk.list", key}
+	if opts.WithCount {
+		args = append(args, "withcount")
+	}
+
+	go func() {
+		replies, err := c.redisClient.Do(c.context(), args...).Slice()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		if !opts.WithCount {
+			resolve(replies)
+			return
+		}
+
+		if len(replies)%2 != 0 {
+			reject(fmt.Errorf("invalid topkList reply for key %q; expected item and count pairs", key))
+			return
+		}
+
+		items := make([]map[string]interface{}, 0, len(replies)/2)
+		for idx := 0; idx < len(replies); idx += 2 {
+			items = append(items, map[string]interface{}{"item": replies[idx], "count": replies[idx+1]})
+		}
+
+		resolve(items)
+	}()
+
+	return promise
+}
+
+// CmsInitByDim creates an empty Count-Min sketch at `key`, `width`
+// counters wide, and `depth` counters deep.
+//
+// The promise resolves with "OK". If `key` already exists, the promise is
+// rejected with an error.
+func (c *Client) CmsInitByDim(key string, width, depth int64) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		status, err := c.redisClient.Do(c.context(), "cms.initbydim", key, width, depth).Text()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(status)
+	}()
+
+	return promise
+}
+
+// CmsInitByProb creates an empty Count-Min sketch at `key`, sized for the
+// counts to overestimate by at most `errorRate` of the total count, with a
+// probability of `probability`, both between 0 and 1.
+//
+// The promise resolves with "OK". If `key` already exists, the promise is
+// rejected with an error.
+func (c *Client) CmsInitByProb(key string, errorRate, probability float64) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		status, err := c.redisClient.Do(c.context(), "cms.initbyprob", key, errorRate, probability).Text()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(status)
+	}()
+
+	return promise
+}
+
+// CmsIncrBy increments the count of the items of the Count-Min sketch
+// stored at `key` by their value in `increments`.
+//
+// The promise resolves with an object mapping each item to its count. If
+// `key` does not exist, the promise is rejected with an error.
+func (c *Client) CmsIncrBy(key string, increments map[string]int64) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	if len(increments) == 0 {
+		reject(errors.New("at least one item must be provided to cmsIncrBy"))
+		return promise
+	}
+
+	items := sortedKeys(increments)
+	args := []interface{}{"cms.incrby", key}
+	for _, item := range items {
+		args = append(args, item, increments[item])
+	}
+
+	go func() {
+		counts, err := c.redisClient.Do(c.context(), args...).Int64Slice()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(itemCounts(items, counts))
+	}()
+
+	return promise
+}
+
+// CmsQuery returns the count of the provided items in the Count-Min
+// sketch stored at `key`.
+//
+// The promise resolves with an object mapping each item to its count,
+// which may exceed the actual one. If `key` does not exist, the promise is
+// rejected with an error.
+func (c *Client) CmsQuery(key string, items ...string) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	if len(items) == 0 {
+		reject(errors.New("at least one item must be provided to cmsQuery"))
+		return promise
+	}
+
+	args := append([]interface{}{"cms.query", key}, stringsToArgs(items)...)
+
+	go func() {
+		counts, err := c.redisClient.Do(c.context(), args...).Int64Slice()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(itemCounts(items, counts))
+	}()
+
+	return promise
+}
+
+// itemCounts returns an object mapping each of `items` to its count in
+// `counts`, in the same order.
+func itemCounts(items []string, counts []int64) map[string]int64 {
+	result := make(map[string]int64, len(items))
+	for idx, item := range items {
+		if idx < len(counts) {
+			result[item] = counts[idx]
+		}
+	}
+
+	return result
+}
+
+// sortedKeys returns the keys of `m`, sorted, so that the commands built
+// from JS objects are deterministic.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,144 @@
+package redis
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientRedisBloom(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunT(t)
+	for _, command := range []string{"BF.RESERVE", "CF.RESERVE", "TOPK.RESERVE", "CMS.INITBYDIM", "CMS.INITBYPROB"} {
+		rs.RegisterCommandHandler(command, func(c *Connection, _ []string) {
+			c.WriteOK()
+		})
+	}
+	for _, command := range []string{"BF.ADD", "BF.EXISTS", "CF.ADD", "CF.EXISTS", "CF.DEL"} {
+		rs.RegisterCommandHandler(command, func(c *Connection, _ []string) {
+			c.WriteInteger(1)
+		})
+	}
+	for _, command := range []string{"BF.MADD", "BF.MEXISTS", "TOPK.QUERY"} {
+		rs.RegisterCommandHandler(command, func(c *Connection, _ []string) {
+			c.WriteValue([]interface{}{1, 0})
+		})
+	}
+	rs.RegisterCommandHandler("CF.ADDNX", func(c *Connection, _ []string) {
+		c.WriteInteger(0)
+	})
+	rs.RegisterCommandHandler("CF.COUNT", func(c *Connection, _ []string) {
+		c.WriteInteger(2)
+	})
+	rs.RegisterCommandHandler("TOPK.ADD", func(c *Connection, _ []string) {
+		c.WriteValue([]interface{}{nil, "old"})
+	})
+	rs.RegisterCommandHandler("TOPK.INCRBY", func(c *Connection, _ []string) {
+		c.WriteValue([]interface{}{nil})
+	})
+	rs.RegisterCommandHandler("TOPK.LIST", func(c *Connection, args []string) {
+		if len(args) > 1 {
+			c.WriteValue([]interface{}{"a", 3, "b", 1})
+			return
+		}
+
+		c.WriteArray("a", "b")
+	})
+	rs.RegisterCommandHandler("CMS.INCRBY", func(c *Connection, _ []string) {
+		c.WriteValue([]interface{}{5, 1})
+	})
+	rs.RegisterCommandHandler("CMS.QUERY", func(c *Connection, _ []string) {
+		c.WriteValue([]interface{}{5})
+	})
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client('redis://%s');
+
+			const expect = (method, got, want) => {
+				if (JSON.stringify(got) !== JSON.stringify(want)) {
+					throw 'unexpected value for ' + method + ' result: ' + JSON.stringify(got)
+				}
+			};
+
+			redis.bfReserve("bf", 0.01, 1000, { expansion: 2 })
+				.then(res => expect("bfReserve", res, "OK"))
+				.then(() => redis.bfAdd("bf", "a"))
+				.then(res => expect("bfAdd", res, true))
+				.then(() => redis.bfMAdd("bf", "a", "b"))
+				.then(res => expect("bfMAdd", res, [true, false]))
+				.then(() => redis.bfExists("bf", "a"))
+				.then(res => expect("bfExists", res, true))
+				.then(() => redis.bfMExists("bf", "a", "b"))
+				.then(res => expect("bfMExists", res, [true, false]))
+				.then(() => redis.cfReserve("cf", 1000, { bucketSize: 4 }))
+				.then(res => expect("cfReserve", res, "OK"))
+				.then(() => redis.cfAdd("cf", "a"))
+				.then(res => expect("cfAdd", res, true))
+				.then(() => redis.cfAddNx("cf", "a"))
+				.then(res => expect("cfAddNx", res, false))
+				.then(() => redis.cfExists("cf", "a"))
+				.then(res => expect("cfExists", res, true))
+				.then(() => redis.cfCount("cf", "a"))
+				.then(res => expect("cfCount", res, 2))
+				.then(() => redis.cfDel("cf", "a"))
+				.then(res => expect("cfDel", res, true))
+				.then(() => redis.topkReserve("topk", 10, { width: 50, depth: 4, decay: 0.9 }))
+				.then(res => expect("topkReserve", res, "OK"))
+				.then(() => redis.topkAdd("topk", "a", "b"))
+				.then(res => expect("topkAdd", res, ["old"]))
+				.then(() => redis.topkIncrBy("topk", { a: 2 }))
+				.then(res => expect("topkIncrBy", res, []))
+				.then(() => redis.topkQuery("topk", "a", "z"))
+				.then(res => expect("topkQuery", res, [true, false]))
+				.then(() => redis.topkList("topk"))
+				.then(res => expect("topkList", res, ["a", "b"]))
+				.then(() => redis.topkList("topk", { withCount: true }))
+				.then(res => expect("topkList", res.map(({ item, count }) => [item, count]), [["a", 3], ["b", 1]]))
+				.then(() => redis.cmsInitByDim("cms", 2000, 5))
+				.then(res => expect("cmsInitByDim", res, "OK"))
+				.then(() => redis.cmsInitByProb("cms2", 0.001, 0.01))
+				.then(res => expect("cmsInitByProb", res, "OK"))
+				.then(() => redis.cmsIncrBy("cms", { b: 1, a: 5 }))
+				.then(res => { if (res.a !== 5 || res.b !== 1) { throw 'unexpected value for cmsIncrBy result: ' + JSON.stringify(res) } })
+				.then(() => redis.cmsQuery("cms", "a"))
+				.then(res => expect("cmsQuery", res, { a: 5 }))
+				.then(() => redis.topkReserve("topk", 10, { width: 50 }))
+				.then(
+					res => { throw 'expected topkReserve to fail' },
+					err => { if (!err.error().includes('must be set together')) { throw 'unexpected error: ' + err.error() } }
+				)
+		`, rs.Addr()))
+
+		return err
+	})
+
+	assert.NoError(t, gotScriptErr)
+	assert.Equal(t, [][]string{
+		{"HELLO", "2"},
+		{"BF.RESERVE", "bf", "0.01", "1000", "expansion", "2"},
+		{"BF.ADD", "bf", "a"},
+		{"BF.MADD", "bf", "a", "b"},
+		{"BF.EXISTS", "bf", "a"},
+		{"BF.MEXISTS", "bf", "a", "b"},
+		{"CF.RESERVE", "cf", "1000", "bucketsize", "4"},
+		{"CF.ADD", "cf", "a"},
+		{"CF.ADDNX", "cf", "a"},
+		{"CF.EXISTS", "cf", "a"},
+		{"CF.COUNT", "cf", "a"},
+		{"CF.DEL", "cf", "a"},
+		{"TOPK.RESERVE", "topk", "10", "50", "4", "0.9"},
+		{"TOPK.ADD", "topk", "a", "b"},
+		{"TOPK.INCRBY", "topk", "a", "2"},
+		{"TOPK.QUERY", "topk", "a", "z"},
+		{"TOPK.LIST", "topk"},
+		{"TOPK.LIST", "topk", "withcount"},
+		{"CMS.INITBYDIM", "cms", "2000", "5"},
+		{"CMS.INITBYPROB", "cms2", "0.001", "0.01"},
+		{"CMS.INCRBY", "cms", "a", "5", "b", "1"},
+		{"CMS.QUERY", "cms", "a"},
+	}, rs.GotCommands())
+}
@@ -0,0 +1,117 @@
+package redis
+
+import (
+	"sync"
+	"time"
+)
+
+// getOptions holds the options of the Client's get method.
+type getOptions struct {
+	// CacheMs is the duration, in milliseconds, the value read is cached
+	// for by the client. Zero disables caching.
+	CacheMs int64 `json:"cacheMs,omitempty"`
+}
+
+// resultCache is a client-side cache of command results, used to serve
+// repeated reads of hot keys without hitting the server.
+//
+// Entries expire after the duration they were stored for. Unless the cache
+// is that of an invalidationTracker, they are never invalidated by writes:
+// those of other clients, or of the client itself.
+//
+// The zero value is an empty cache ready to use.
+type resultCache struct {
+	mu      sync.Mutex
+	entries map[string]cachedResult
+
+	// generation is incremented by each invalidation, so that results read
+	// while an invalidation was in flight are not cached.
+	generation uint64
+
+	// disabled caches never hold any entry.
+	disabled bool
+}
+
+// cachedResult is a resultCache entry.
+type cachedResult struct {
+	value     string
+	expiresAt time.Time
+}
+
+// load returns the value cached for `key`, if any and not expired yet.
+func (rc *resultCache) load(key string) (string, bool) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	entry, ok := rc.entries[key]
+	if !ok {
+		return "", false
+	}
+
+	if time.Now().After(entry.expiresAt) {
+		delete(rc.entries, key)
+		return "", false
+	}
+
+	return entry.value, true
+}
+
+// currentGeneration returns the cache's generation, to be passed to store
+// once the result to cache is read.
+func (rc *resultCache) currentGeneration() uint64 {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	return rc.generation
+}
+
+// store caches `value` for `key`, during the provided duration, unless the
+// cache was invalidated since `generation`, as the value might be stale.
+func (rc *resultCache) store(key, value string, ttl time.Duration, generation uint64) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	if rc.disabled || rc.generation != generation {
+		return
+	}
+
+	if rc.entries == nil {
+		rc.entries = make(map[string]cachedResult)
+	}
+
+	rc.entries[key] = cachedResult{value: value, expiresAt: time.Now().Add(ttl)}
+}
+
+// invalidate removes the entries of the provided keys.
+func (rc *resultCache) invalidate(keys ...string) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	rc.generation++
+	for _, key := range keys {
+		delete(rc.entries, key)
+	}
+}
+
+// flush removes all the entries, and disables the cache if `disable` is
+// set.
+func (rc *resultCache) flush(disable bool) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	rc.generation++
+	rc.entries = nil
+	rc.disabled = rc.disabled || disable
+}
+
+// readCache returns the cache of the reads performed with the cacheMs
+// option. With the clientTracking option, it is that of the client's
+// invalidationTracker, shared by all the VUs using the same client options,
+// whose entries are invalidated by Redis. Otherwise, it is the Client's own.
+func (c *Client) readCache() *resultCache {
+	if c.redisOptions != nil && c.redisOptions.tracker != nil {
+		return &c.redisOptions.tracker.cache
+	}
+
+	return &c.cache
+}
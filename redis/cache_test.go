@@ -0,0 +1,189 @@
+package redis
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientGetCache(t *testing.T) {
+	t.Parallel()
+
+	t.Run("cached reads do not hit the server", func(t *testing.T) {
+		t.Parallel()
+
+		ts := newTestSetup(t)
+		rs := RunT(t)
+		rs.RegisterCommandHandler("GET", func(c *Connection, _ []string) {
+			c.WriteBulkString("bar")
+		})
+
+		gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+			_, err := ts.rt.RunString(fmt.Sprintf(`
+				const redis = new Client('redis://%s');
+				const expectBar = (res) => { if (res !== "bar") { throw 'unexpected value for get result: ' + res } };
+
+				redis.get("foo", { cacheMs: 60000 })
+					.then(expectBar)
+					.then(() => redis.get("foo", { cacheMs: 60000 }))
+					.then(expectBar)
+					.then(() => redis.get("foo"))
+					.then(expectBar)
+			`, rs.Addr()))
+
+			return err
+		})
+
+		assert.NoError(t, gotScriptErr)
+
+		// The second get is served from the cache, while the
+		// third one, not using the cache, hits the server.
+		assert.Equal(t, 2, rs.HandledCommandsCount())
+	})
+
+	t.Run("invalid options are rejected", func(t *testing.T) {
+		t.Parallel()
+
+		ts := newTestSetup(t)
+		rs := RunT(t)
+
+		gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+			_, err := ts.rt.RunString(fmt.Sprintf(`
+				const redis = new Client('redis://%s');
+
+				redis.get("foo", { cacheMs: -1 })
+					.then(
+						res => { throw 'expected get to fail' },
+						err => { if (!err.error().startsWith('invalid cacheMs option')) { throw 'unexpected error: ' + err } }
+					)
+					.then(() => redis.get("foo", { cacheSeconds: 1 }))
+					.then(
+						res => { throw 'expected get to fail' },
+						err => { if (!err.error().startsWith('invalid get options')) { throw 'unexpected error: ' + err } }
+					)
+			`, rs.Addr()))
+
+			return err
+		})
+
+		assert.NoError(t, gotScriptErr)
+		assert.Equal(t, 0, rs.HandledCommandsCount())
+	})
+}
+
+func TestResultCache(t *testing.T) {
+	t.Parallel()
+
+	var rc resultCache
+
+	_, ok := rc.load("foo")
+	assert.False(t, ok)
+
+	rc.store("foo", "bar", time.Minute, rc.currentGeneration())
+	value, ok := rc.load("foo")
+	assert.True(t, ok)
+	assert.Equal(t, "bar", value)
+
+	rc.store("foo", "baz", -time.Millisecond, rc.currentGeneration())
+	_, ok = rc.load("foo")
+	assert.False(t, ok)
+
+	// Results read before an invalidation are not cached.
+	generation := rc.currentGeneration()
+	rc.invalidate("foo")
+	rc.store("foo", "bar", time.Minute, generation)
+	_, ok = rc.load("foo")
+	assert.False(t, ok)
+
+	rc.store("foo", "bar", time.Minute, rc.currentGeneration())
+	rc.invalidate("foo")
+	_, ok = rc.load("foo")
+	assert.False(t, ok)
+
+	rc.flush(true)
+	rc.store("foo", "bar", time.Minute, rc.currentGeneration())
+	_, ok = rc.load("foo")
+	assert.False(t, ok)
+}
+
+func TestClientTracking(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunT(t)
+	registerPubSubHandlers(rs)
+	rs.RegisterCommandHandler("CLIENT", func(c *Connection, args []string) {
+		if args[0] == "id" {
+			c.WriteInteger(7)
+			return
+		}
+
+		c.WriteOK()
+	})
+
+	var gets int
+	rs.RegisterCommandHandler("GET", func(c *Connection, _ []string) {
+		gets++
+		c.WriteBulkString(fmt.Sprintf("v%d", gets))
+	})
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client({
+				socket: {
+					host: '%s',
+					port: %d,
+				},
+				protocol: 3,
+				clientTracking: true,
+			});
+
+			redis.get("foo", { cacheMs: 60000 })
+				.then(() => redis.get("foo", { cacheMs: 60000 }))
+				.then(res => { if (res !== "v1") { throw 'unexpected value for cached get result: ' + res } })
+				.then(() => redis.publish("__redis__:invalidate", "foo"))
+		`, rs.Addr().IP.String(), rs.Addr().Port))
+
+		return err
+	})
+	assert.NoError(t, gotScriptErr)
+
+	client, err := ts.rt.RunString("redis")
+	assert.NoError(t, err)
+	cache := client.Export().(*Client).readCache()
+	assert.Eventually(t, func() bool {
+		_, ok := cache.load("foo")
+		return !ok
+	}, time.Second, 10*time.Millisecond, "expected the invalidation to evict the cached value")
+
+	gotScriptErr = ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(`
+			redis.get("foo", { cacheMs: 60000 })
+				.then(res => { if (res !== "v2") { throw 'unexpected value for get result after invalidation: ' + res } })
+		`)
+
+		return err
+	})
+	assert.NoError(t, gotScriptErr)
+
+	commands := rs.GotCommands()
+	assert.Contains(t, commands, []string{"HELLO", "3"})
+	assert.Contains(t, commands, []string{"CLIENT", "id"})
+	assert.Contains(t, commands, []string{"SUBSCRIBE", "__redis__:invalidate"})
+	assert.Contains(t, commands, []string{"CLIENT", "tracking", "on", "redirect", "7"})
+	assert.Equal(t, 2, gets)
+
+	var hits, misses int
+	for _, sample := range drainSamples(ts.samples) {
+		switch sample.Metric.Name {
+		case "redis_cache_hits":
+			hits += int(sample.Value)
+		case "redis_cache_misses":
+			misses += int(sample.Value)
+		}
+	}
+	assert.Equal(t, 1, hits)
+	assert.Equal(t, 2, misses)
+}
@@ -0,0 +1,167 @@
+package redis
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"strings"
+	"time"
+
+	"github.com/grafana/sobek"
+)
+
+// The algorithms setChecksummed checksums values with.
+const (
+	checksumCRC32  = "crc32"
+	checksumSHA256 = "sha256"
+)
+
+// checksumSeparator separates the values stored by setChecksummed from
+// their checksum, which is appended to them as `|<algorithm>:<hex digest>`.
+// Neither the algorithm nor the digest holds it, so the checksum starts
+// after its last occurrence, whatever the value holds.
+const checksumSeparator = "|"
+
+// checksumOptions holds the options of the Client's setChecksummed method.
+type checksumOptions struct {
+	// Expiration is the time to live of the key, in seconds. The key
+	// doesn't expire when it isn't set.
+	Expiration int64 `json:"expiration,omitempty"`
+
+	// Algorithm is the algorithm the value is checksummed with, crc32,
+	// the default, or sha256.
+	Algorithm string `json:"algorithm,omitempty"`
+}
+
+// SetChecksummed sets `key` to `value`, followed by its checksum, so that
+// getVerified can tell whether the value read back is the one written, such
+// as to use Redis as a correctness probe during soak tests. The checksum is
+// computed by the client, with the algorithm option, crc32 by default, or
+// sha256, and the key expires after the expiration option, in seconds, if
+// set.
+//
+// If the provided value is not a supported type, or the options are
+// invalid, the promise is rejected with an error.
+func (c *Client) SetChecksummed(key string, value interface{}, options map[string]interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	values, err := c.binaryFields(1, value)
+	if err != nil {
+		reject(err)
+		return promise
+	}
+
+	opts := checksumOptions{Algorithm: checksumCRC32}
+	if err := decodeOptions(options, &opts); err != nil {
+		reject(fmt.Errorf("invalid setChecksummed options; reason: %w", err))
+		return promise
+	}
+
+	if opts.Expiration < 0 {
+		reject(fmt.Errorf("invalid expiration option: %d; expected a positive number", opts.Expiration))
+		return promise
+	}
+
+	sum, err := checksum(opts.Algorithm, values[0])
+	if err != nil {
+		reject(fmt.Errorf("invalid setChecksummed options; reason: %w", err))
+		return promise
+	}
+
+	stored := values[0] + checksumSeparator + opts.Algorithm + ":" + sum
+	expiration := time.Duration(opts.Expiration) * time.Second
+
+	go func() {
+		result, err := c.redisClient.Set(c.context(), key, stored, expiration).Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(result)
+	}()
+
+	return promise
+}
+
+// GetVerified returns the value of `key`, as set by setChecksummed, once
+// its checksum is verified.
+//
+// Each value verified is recorded by the redis_data_corruption rate metric:
+// as corrupted if its checksum doesn't match, or is missing, in which case
+// the promise is rejected with an error, or as intact otherwise. If the key
+// does not exist, the promise is rejected with an error, and nothing is
+// recorded, as no value was read.
+func (c *Client) GetVerified(key string) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		stored, err := c.redisClient.Get(c.context(), key).Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		value, err := verifyChecksum(stored)
+		if err != nil {
+			c.pushMetric(c.metrics.DataCorruption, 1)
+			reject(fmt.Errorf("data corruption detected for key %q; reason: %w", key, err))
+			return
+		}
+
+		c.pushMetric(c.metrics.DataCorruption, 0)
+		resolve(c.typedValue(value))
+	}()
+
+	return promise
+}
+
+// checksum returns the hex digest of `value` with `algorithm`.
+func checksum(algorithm, value string) (string, error) {
+	switch algorithm {
+	case checksumCRC32:
+		return fmt.Sprintf("%08x", crc32.ChecksumIEEE([]byte(value))), nil
+	case checksumSHA256:
+		sum := sha256.Sum256([]byte(value))
+		return hex.EncodeToString(sum[:]), nil
+	default:
+		return "", fmt.Errorf("unsupported algorithm %q; expected %q, or %q", algorithm, checksumCRC32, checksumSHA256)
+	}
+}
+
+// verifyChecksum returns the value `stored` by setChecksummed, without its
+// checksum, or an error if the checksum is missing, or doesn't match.
+func verifyChecksum(stored string) (string, error) {
+	idx := strings.LastIndex(stored, checksumSeparator)
+	if idx < 0 {
+		return "", errors.New("the value holds no checksum")
+	}
+
+	value, trailer := stored[:idx], stored[idx+len(checksumSeparator):]
+	algorithm, want, ok := strings.Cut(trailer, ":")
+	if !ok {
+		return "", errors.New("the value holds no checksum")
+	}
+
+	got, err := checksum(algorithm, value)
+	if err != nil {
+		return "", err
+	}
+	if got != want {
+		return "", fmt.Errorf("the %s checksum of the value is %s, rather than %s", algorithm, got, want)
+	}
+
+	return value, nil
+}
@@ -0,0 +1,134 @@
+package redis
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientChecksummed(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+
+	rs := RunT(t)
+	var mu sync.Mutex
+	stored := map[string]string{
+		"corrupted": "value|crc32:00000000",
+		"plain":     "value",
+	}
+	rs.RegisterCommandHandler("SET", func(c *Connection, args []string) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		stored[args[0]] = args[1]
+		c.WriteOK()
+	})
+	rs.RegisterCommandHandler("GET", func(c *Connection, args []string) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		value, ok := stored[args[0]]
+		if !ok {
+			c.WriteNull()
+			return
+		}
+		c.WriteBulkString(value)
+	})
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client({ socket: { host: "%s", port: %d } });
+
+			redis.setChecksummed("crc", "a|b")
+				.then(res => { if (res !== "OK") { throw 'unexpected value for setChecksummed result: ' + res } })
+				.then(() => redis.getVerified("crc"))
+				.then(res => { if (res !== "a|b") { throw 'unexpected value for getVerified result: ' + res } })
+				.then(() => redis.setChecksummed("sha", 42, { algorithm: "sha256" }))
+				.then(() => redis.getVerified("sha"))
+				.then(res => { if (res !== "42") { throw 'unexpected value for getVerified result: ' + res } })
+				.then(() => redis.getVerified("corrupted"))
+				.then(
+					res => { throw 'expected corrupted values to be rejected' },
+					err => { if (!err.error().startsWith('data corruption detected for key "corrupted"')) { throw 'unexpected error: ' + err.error() } }
+				)
+				.then(() => redis.getVerified("plain"))
+				.then(
+					res => { throw 'expected values without checksum to be rejected' },
+					err => { if (!err.error().includes('the value holds no checksum')) { throw 'unexpected error: ' + err.error() } }
+				)
+				.then(() => redis.getVerified("missing"))
+				.then(
+					res => { throw 'expected missing keys to be rejected' },
+					err => { if (err.error() !== 'redis: nil') { throw 'unexpected error: ' + err.error() } }
+				)
+				.then(() => redis.setChecksummed("md5", "value", { algorithm: "md5" }))
+				.then(
+					res => { throw 'expected unsupported algorithms to be rejected' },
+					err => { if (!err.error().startsWith('invalid setChecksummed options')) { throw 'unexpected error: ' + err.error() } }
+				)
+		`, rs.Addr().IP.String(), rs.Addr().Port))
+
+		return err
+	})
+	require.NoError(t, gotScriptErr)
+
+	mu.Lock()
+	assert.Equal(t, "a|b|crc32:"+crcOf(t, "a|b"), stored["crc"])
+	mu.Unlock()
+
+	corruption := []float64{}
+	for _, sample := range drainSamples(ts.samples) {
+		if sample.Metric.Name == "redis_data_corruption" {
+			corruption = append(corruption, sample.Value)
+		}
+	}
+	assert.Equal(t, []float64{0, 0, 1, 1}, corruption)
+}
+
+func TestVerifyChecksum(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		stored  string
+		want    string
+		wantErr bool
+	}{
+		{name: "crc32", stored: "value|crc32:" + crcOf(t, "value"), want: "value"},
+		{name: "empty value", stored: "|crc32:" + crcOf(t, ""), want: ""},
+		{name: "mismatch", stored: "valve|crc32:" + crcOf(t, "value"), wantErr: true},
+		{name: "no checksum", stored: "value", wantErr: true},
+		{name: "no algorithm", stored: "value|" + crcOf(t, "value"), wantErr: true},
+		{name: "unknown algorithm", stored: "value|md5:2063c1608d6e0baf80249c42e2be5804", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := verifyChecksum(tt.stored)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+// crcOf returns the crc32 checksum of `value`, as setChecksummed appends it.
+func crcOf(t *testing.T, value string) string {
+	t.Helper()
+
+	sum, err := checksum(checksumCRC32, value)
+	require.NoError(t, err)
+
+	return sum
+}
@@ -0,0 +1,63 @@
+package redis
+
+import (
+	"context"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// sumChunks sends the command built by `cmd` for the provided arguments,
+// and returns its integer reply.
+//
+// When the commandChunkSize option is set, and exceeded by the number of
+// arguments, the arguments are split in chunks of at most that size, and a
+// command is sent for each of them. Those commands are pipelined, and the
+// sum of their integer replies is returned. This avoids building, and
+// sending, huge commands, for instance when adding tens of thousands of
+// members to a set.
+func sumChunks[T any](
+	ctx context.Context, c *Client, args []T, cmd func(redis.Cmdable, []T) *redis.IntCmd,
+) (int64, error) {
+	cmds, err := execChunks(ctx, c, args, cmd)
+	if err != nil {
+		return 0, err
+	}
+
+	var sum int64
+	for _, chunkCmd := range cmds {
+		sum += chunkCmd.Val()
+	}
+
+	return sum, nil
+}
+
+// execChunks sends the command built by `cmd` for the provided arguments,
+// split in pipelined chunks as sumChunks does, and returns the commands
+// sent. It serves the commands whose replies can't be summed, such as the
+// status reply of MSET.
+func execChunks[T any, C redis.Cmder](
+	ctx context.Context, c *Client, args []T, cmd func(redis.Cmdable, []T) C,
+) ([]C, error) {
+	size := c.redisOptions.CommandChunkSize
+	if size == 0 || len(args) <= size {
+		single := cmd(c.redisClient, args)
+		return []C{single}, single.Err()
+	}
+
+	pipe := c.redisClient.Pipeline()
+	cmds := make([]C, 0, (len(args)+size-1)/size)
+	for start := 0; start < len(args); start += size {
+		end := start + size
+		if end > len(args) {
+			end = len(args)
+		}
+
+		cmds = append(cmds, cmd(pipe, args[start:end]))
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	return cmds, nil
+}
@@ -0,0 +1,63 @@
+package redis
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientCommandChunkSize(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunT(t)
+	rs.RegisterCommandHandler("SADD", func(c *Connection, args []string) {
+		c.WriteInteger(len(args) - 1)
+	})
+	rs.RegisterCommandHandler("DEL", func(c *Connection, args []string) {
+		c.WriteInteger(len(args))
+	})
+	rs.RegisterCommandHandler("MSET", func(c *Connection, _ []string) {
+		c.WriteOK()
+	})
+	rs.RegisterCommandHandler("HMSET", func(c *Connection, _ []string) {
+		c.WriteOK()
+	})
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client({
+				socket: {
+					host: '%s',
+					port: %d,
+				},
+				commandChunkSize: 2,
+			});
+
+			redis.sadd("set", "a", "b", "c", "d", "e")
+				.then(res => { if (res !== 5) { throw 'unexpected value for sadd result: ' + res } })
+				.then(() => redis.del("k1", "k2"))
+				.then(res => { if (res !== 2) { throw 'unexpected value for del result: ' + res } })
+				.then(() => redis.mset({ k1: "1", k2: "2", k3: "3" }))
+				.then(res => { if (res !== "OK") { throw 'unexpected value for mset result: ' + res } })
+				.then(() => redis.hmset("hash", { f1: "1", f2: "2", f3: "3" }))
+				.then(res => { if (res !== "OK") { throw 'unexpected value for hmset result: ' + res } })
+		`, rs.Addr().IP.String(), rs.Addr().Port))
+
+		return err
+	})
+
+	assert.NoError(t, gotScriptErr)
+	assert.Equal(t, [][]string{
+		{"HELLO", "2"},
+		{"SADD", "set", "a", "b"},
+		{"SADD", "set", "c", "d"},
+		{"SADD", "set", "e"},
+		{"DEL", "k1", "k2"},
+		{"MSET", "k1", "1", "k2", "2"},
+		{"MSET", "k3", "3"},
+		{"HMSET", "hash", "f1", "1", "f2", "2"},
+		{"HMSET", "hash", "f3", "3"},
+	}, rs.GotCommands())
+}
@@ -3,15 +3,19 @@ package redis
 import (
 	"context"
 	"crypto/tls"
+	"errors"
 	"fmt"
+	"math/rand"
 	"net"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/grafana/sobek"
 	"github.com/redis/go-redis/v9"
 	"go.k6.io/k6/js/common"
 	"go.k6.io/k6/js/modules"
-	"go.k6.io/k6/js/promises"
 	"go.k6.io/k6/lib"
 )
 
@@ -19,31 +23,263 @@ import (
 // returns a new Redis client object.
 type Client struct {
 	vu             modules.VU
-	redisOptions   *redis.UniversalOptions
+	redisOptions   *universalOptions
 	redisClient    redis.UniversalClient
 	getRedisClient GetRedisClientFunc
+	metrics        *redisMetrics
+
+	// closeRedisClient releases the redisClient the Client got from
+	// getRedisClient, which it owns then, as opposed to the clients
+	// returned by withTimeout, sharing the redisClient of their parent.
+	closeRedisClient CloseRedisClientFunc
+	ownsRedisClient  bool
+
+	// cache holds the results of reads performed with the cacheMs option.
+	cache resultCache
+
+	// activeSubscription is the Client's pub/sub subscription, if any.
+	activeSubscription *subscription
+	subscriptionMu     sync.Mutex
+
+	// lagSamplers holds the streams whose consumer lag is being sampled.
+	lagSamplers   map[string]struct{}
+	lagSamplersMu sync.Mutex
+
+	// statsSampling is set while the server's statistics are sampled.
+	statsSampling  bool
+	statsSamplerMu sync.Mutex
+
+	// commandTally counts the commands sent when the
+	// collectCommandHistogram option is set.
+	commandTally commandTally
+
+	// stats holds the latencies returned by the stats method, shared with
+	// the clients derived from this one.
+	stats *latencyStats
+
+	// timeout overrides the commandTimeout option, for the clients
+	// returned by withTimeout.
+	timeout time.Duration
+
+	// tags are added to the tags of the metrics emitted on behalf of the
+	// clients returned by withTags, on top of the tags option.
+	tags map[string]string
+
+	// keyRand picks the keys of pickKey and getRandom. It is created on
+	// first use.
+	keyRand *rand.Rand
+
+	// hooks holds the callbacks added with addHook, shared with the
+	// clients derived from this one.
+	hooks *commandHooks
+
+	// trackedKeys holds the keys written with the trackKeys option set,
+	// shared with the clients derived from this one.
+	trackedKeys *trackedKeys
+
+	// syncCalls is set for the clients created with the blocking option,
+	// whose methods return the results of their commands, rather than
+	// promises.
+	syncCalls *syncCalls
+}
+
+// WithTimeout returns a client sending its commands through the same
+// connection pool as c, whose commands fail if they don't complete within
+// `timeoutMs` milliseconds, regardless of the commandTimeout option.
+//
+// Commands which time out, whether waiting for a connection or for their
+// reply, are rejected with an error of the "deadline" kind.
+func (c *Client) WithTimeout(timeoutMs int64) *Client {
+	if timeoutMs <= 0 {
+		common.Throw(c.vu.Runtime(), fmt.Errorf("invalid timeout: %d; expected a positive number", timeoutMs))
+	}
+
+	return &Client{
+		vu:               c.vu,
+		redisOptions:     c.redisOptions,
+		redisClient:      c.redisClient,
+		getRedisClient:   c.getRedisClient,
+		closeRedisClient: c.closeRedisClient,
+		metrics:          c.metrics,
+		timeout:          time.Duration(timeoutMs) * time.Millisecond,
+		tags:             c.tags,
+		stats:            c.stats,
+		hooks:            c.hooks,
+		trackedKeys:      c.trackedKeys,
+		syncCalls:        c.syncCalls,
+	}
+}
+
+// WithTags returns a client sending its commands through the same
+// connection pool as c, whose metrics are tagged with `tags`, on top of
+// c's tags, such as {operation: "session-read"}, so that the latency of
+// Redis commands can be sliced by business operation in thresholds and
+// dashboards.
+//
+// The tags override those of the tags option, and of c, with the same
+// name, but not the command and address tags of the module's metrics.
+func (c *Client) WithTags(tags map[string]string) *Client {
+	merged := make(map[string]string, len(c.tags)+len(tags))
+	for name, value := range c.tags {
+		merged[name] = value
+	}
+	for name, value := range tags {
+		if name == "" {
+			common.Throw(c.vu.Runtime(), errors.New("invalid tags; tag names must not be empty"))
+		}
+		merged[name] = value
+	}
+
+	return &Client{
+		vu:               c.vu,
+		redisOptions:     c.redisOptions,
+		redisClient:      c.redisClient,
+		getRedisClient:   c.getRedisClient,
+		closeRedisClient: c.closeRedisClient,
+		metrics:          c.metrics,
+		timeout:          c.timeout,
+		tags:             merged,
+		stats:            c.stats,
+		hooks:            c.hooks,
+		trackedKeys:      c.trackedKeys,
+		syncCalls:        c.syncCalls,
+	}
+}
+
+// setOptions holds the options of the Client's set method.
+type setOptions struct {
+	// EX, PX, EXAT, and PXAT set the key's time to live, in seconds, and
+	// milliseconds, or the absolute Unix time it expires at, in seconds,
+	// and milliseconds, respectively.
+	EX   int64 `json:"ex,omitempty"`
+	PX   int64 `json:"px,omitempty"`
+	EXAT int64 `json:"exat,omitempty"`
+	PXAT int64 `json:"pxat,omitempty"`
+
+	// KeepTTL retains the key's current time to live.
+	KeepTTL bool `json:"keepTtl,omitempty"`
+
+	// NX only sets the key if it does not exist yet.
+	NX bool `json:"nx,omitempty"`
+
+	// XX only sets the key if it already exists.
+	XX bool `json:"xx,omitempty"`
+
+	// Get returns the key's previous value.
+	Get bool `json:"get,omitempty"`
+}
+
+// args returns the arguments of the SET command the options translate to.
+func (o setOptions) args() ([]interface{}, error) {
+	var args []interface{}
+	for _, expiration := range []struct {
+		name  string
+		value int64
+	}{{"ex", o.EX}, {"px", o.PX}, {"exat", o.EXAT}, {"pxat", o.PXAT}} {
+		if expiration.value < 0 {
+			return nil, fmt.Errorf("invalid %s option: %d; expected a positive number", expiration.name, expiration.value)
+		}
+		if expiration.value > 0 {
+			args = append(args, expiration.name, expiration.value)
+		}
+	}
+	if o.KeepTTL {
+		args = append(args, "keepttl")
+	}
+	if len(args) > 2 || (o.KeepTTL && len(args) > 1) {
+		return nil, errors.New("ex, px, exat, pxat, and keepTtl are mutually exclusive")
+	}
+
+	if o.NX && o.XX {
+		return nil, errors.New("nx and xx are mutually exclusive")
+	}
+	if o.NX {
+		args = append(args, "nx")
+	}
+	if o.XX {
+		args = append(args, "xx")
+	}
+
+	if o.Get {
+		args = append(args, "get")
+	}
+
+	return args, nil
+}
+
+// setArgs returns the arguments of the SET command following the value,
+// from the third argument of set: the expiration, in seconds, or an object
+// of setOptions. Non-positive expirations are ignored, as they always have
+// been.
+func setArgs(expirationOrOptions interface{}) ([]interface{}, error) {
+	var opts setOptions
+	switch v := expirationOrOptions.(type) {
+	case nil:
+	case int64:
+		if v > 0 {
+			opts.EX = v
+		}
+	case float64:
+		if v > 0 {
+			opts.EX = int64(v)
+		}
+	case map[string]interface{}:
+		if err := decodeOptions(v, &opts); err != nil {
+			return nil, fmt.Errorf("invalid set options; reason: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("invalid set expiration or options: %v; expected a number or an object", v)
+	}
+
+	args, err := opts.args()
+	if err != nil {
+		return nil, fmt.Errorf("invalid set options; %w", err)
+	}
+
+	return args, nil
 }
 
 // Set the given key with the given value.
 //
 // If the provided value is not a supported type, the promise is rejected with an error.
+// The value can be binary: ArrayBuffer or Uint8Array.
 //
-// The value for `expiration` is interpreted as seconds.
-func (c *Client) Set(key string, value interface{}, expiration int) *sobek.Promise {
-	promise, resolve, reject := promises.New(c.vu)
+// The third argument is either the expiration, interpreted as seconds, or
+// an object of {ex, px, exat, pxat, keepTtl, nx, xx, get} options.
+//
+// The promise resolves with "OK", or with null if the nx or xx option
+// prevented the key from being set. With the get option, it resolves with
+// the key's previous value instead, or null if it did not exist.
+func (c *Client) Set(key string, value interface{}, expirationOrOptions interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
 
 	if err := c.connect(); err != nil {
 		reject(err)
 		return promise
 	}
 
-	if err := c.isSupportedType(1, value); err != nil {
+	values, err := c.binaryArgs(1, value)
+	if err != nil {
+		reject(err)
+		return promise
+	}
+
+	optArgs, err := setArgs(expirationOrOptions)
+	if err != nil {
 		reject(err)
 		return promise
 	}
 
 	go func() {
-		result, err := c.redisClient.Set(c.vu.Context(), key, value, time.Duration(expiration)*time.Second).Result()
+		ctx := c.context()
+		cmd := redis.NewStatusCmd(ctx, append([]interface{}{"set", key, values[0]}, optArgs...)...)
+		_ = c.redisClient.Process(ctx, cmd)
+
+		result, err := cmd.Result()
+		if errors.Is(err, redis.Nil) {
+			resolve(nil)
+			return
+		}
 		if err != nil {
 			reject(err)
 			return
@@ -59,23 +295,58 @@ func (c *Client) Set(key string, value interface{}, expiration int) *sobek.Promi
 //
 // If the key does not exist, the promise is rejected with an error.
 //
-// If the key does not exist, the promise is rejected with an error.
-func (c *Client) Get(key string) *sobek.Promise {
-	promise, resolve, reject := promises.New(c.vu)
+// When the `cacheMs` option is set, the value read is cached by the client
+// for that many milliseconds, and subsequent gets of the same key, with the
+// `cacheMs` option set, are served from the cache without hitting Redis.
+// The cache is specific to the client, and thus to the VU, and is not
+// invalidated by writes to the key, unless the clientTracking option is
+// set, see readCache.
+func (c *Client) Get(key string, options map[string]interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
 
 	if err := c.connect(); err != nil {
 		reject(err)
 		return promise
 	}
 
+	var opts getOptions
+	if err := decodeOptions(options, &opts); err != nil {
+		reject(fmt.Errorf("invalid get options; reason: %w", err))
+		return promise
+	}
+
+	if opts.CacheMs < 0 {
+		reject(fmt.Errorf("invalid cacheMs option: %d; expected a positive number", opts.CacheMs))
+		return promise
+	}
+
+	// Values are cached by the name of their key in Redis, which client
+	// tracking reports the invalidations of.
+	cacheKey := c.redisOptions.KeyPrefix + key
+	cache := c.readCache()
+	generation := cache.currentGeneration()
+	if opts.CacheMs > 0 {
+		if value, ok := cache.load(cacheKey); ok {
+			c.pushMetric(c.metrics.CacheHits, 1)
+			resolve(c.typedValue(value))
+			return promise
+		}
+
+		c.pushMetric(c.metrics.CacheMisses, 1)
+	}
+
 	go func() {
-		value, err := c.redisClient.Get(c.vu.Context(), key).Result()
+		value, err := c.redisClient.Get(c.context(), key).Result()
 		if err != nil {
 			reject(err)
 			return
 		}
 
-		resolve(value)
+		if opts.CacheMs > 0 {
+			cache.store(cacheKey, value, time.Duration(opts.CacheMs)*time.Millisecond, generation)
+		}
+
+		resolve(c.typedValue(value))
 	}()
 
 	return promise
@@ -84,43 +355,82 @@ func (c *Client) Get(key string) *sobek.Promise {
 // GetSet sets the value of key to value and returns the old value stored
 //
 // If the provided value is not a supported type, the promise is rejected with an error.
+// The value can be binary: ArrayBuffer or Uint8Array.
 func (c *Client) GetSet(key string, value interface{}) *sobek.Promise {
-	promise, resolve, reject := promises.New(c.vu)
+	promise, resolve, reject := c.newPromise()
 
 	if err := c.connect(); err != nil {
 		reject(err)
 		return promise
 	}
 
-	if err := c.isSupportedType(1, value); err != nil {
+	values, err := c.binaryArgs(1, value)
+	if err != nil {
 		reject(err)
 		return promise
 	}
 
 	go func() {
-		oldValue, err := c.redisClient.GetSet(c.vu.Context(), key, value).Result()
+		oldValue, err := c.redisClient.GetSet(c.context(), key, values[0]).Result()
 		if err != nil {
 			reject(err)
 			return
 		}
 
-		resolve(oldValue)
+		resolve(c.typedValue(oldValue))
 	}()
 
 	return promise
 }
 
 // Del removes the specified keys. A key is ignored if it does not exist
-func (c *Client) Del(keys ...string) *sobek.Promise {
-	promise, resolve, reject := promises.New(c.vu)
+//
+// Keys can be provided as separate arguments, arrays, or both.
+// Calls exceeding the commandChunkSize option are split in pipelined chunks.
+func (c *Client) Del(keys ...interface{}) *sobek.Promise {
+	return c.countKeys("del", keys, redis.Cmdable.Del)
+}
+
+// Unlink is like Del, except that the memory of the values is reclaimed in
+// the background, so that removing large values doesn't block the server.
+func (c *Client) Unlink(keys ...interface{}) *sobek.Promise {
+	return c.countKeys("unlink", keys, redis.Cmdable.Unlink)
+}
+
+// Touch updates the last access time of the specified keys, as reading
+// them would, and returns the number of keys that exist.
+func (c *Client) Touch(keys ...interface{}) *sobek.Promise {
+	return c.countKeys("touch", keys, redis.Cmdable.Touch)
+}
+
+// countKeys sends the multi-key command `cmd` for the provided keys, and
+// resolves with the sum of its integer replies.
+func (c *Client) countKeys(
+	name string, keys []interface{}, cmd func(redis.Cmdable, context.Context, ...string) *redis.IntCmd,
+) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
 
 	if err := c.connect(); err != nil {
 		reject(err)
 		return promise
 	}
 
+	flattened, err := flattenKeys(keys)
+	if err != nil {
+		reject(fmt.Errorf("invalid %s keys; reason: %w", name, err))
+		return promise
+	}
+
+	if len(flattened) == 0 {
+		reject(fmt.Errorf("%s requires at least one key", name))
+		return promise
+	}
+
 	go func() {
-		n, err := c.redisClient.Del(c.vu.Context(), keys...).Result()
+		ctx := c.context()
+		n, err := sumChunks(ctx, c, flattened, func(client redis.Cmdable, chunk []string) *redis.IntCmd {
+			return cmd(client, ctx, chunk...)
+		})
 		if err != nil {
 			reject(err)
 			return
@@ -136,7 +446,7 @@ func (c *Client) Del(keys ...string) *sobek.Promise {
 //
 // If the key does not exist, the promise is rejected with an error.
 func (c *Client) GetDel(key string) *sobek.Promise {
-	promise, resolve, reject := promises.New(c.vu)
+	promise, resolve, reject := c.newPromise()
 
 	if err := c.connect(); err != nil {
 		reject(err)
@@ -144,13 +454,13 @@ func (c *Client) GetDel(key string) *sobek.Promise {
 	}
 
 	go func() {
-		value, err := c.redisClient.GetDel(c.vu.Context(), key).Result()
+		value, err := c.redisClient.GetDel(c.context(), key).Result()
 		if err != nil {
 			reject(err)
 			return
 		}
 
-		resolve(value)
+		resolve(c.typedValue(value))
 	}()
 
 	return promise
@@ -159,25 +469,10 @@ func (c *Client) GetDel(key string) *sobek.Promise {
 // Exists returns the number of key arguments that exist.
 // Note that if the same existing key is mentioned in the argument
 // multiple times, it will be counted multiple times.
-func (c *Client) Exists(keys ...string) *sobek.Promise {
-	promise, resolve, reject := promises.New(c.vu)
-
-	if err := c.connect(); err != nil {
-		reject(err)
-		return promise
-	}
-
-	go func() {
-		n, err := c.redisClient.Exists(c.vu.Context(), keys...).Result()
-		if err != nil {
-			reject(err)
-			return
-		}
-
-		resolve(n)
-	}()
-
-	return promise
+//
+// Like Del, it accepts keys as separate arguments, arrays, or both.
+func (c *Client) Exists(keys ...interface{}) *sobek.Promise {
+	return c.countKeys("exists", keys, redis.Cmdable.Exists)
 }
 
 // Incr increments the number stored at `key` by one. If the key does
@@ -185,7 +480,7 @@ func (c *Client) Exists(keys ...string) *sobek.Promise {
 // error is returned if the key contains a value of the wrong type, or
 // contains a string that cannot be represented as an integer.
 func (c *Client) Incr(key string) *sobek.Promise {
-	promise, resolve, reject := promises.New(c.vu)
+	promise, resolve, reject := c.newPromise()
 
 	if err := c.connect(); err != nil {
 		reject(err)
@@ -193,7 +488,7 @@ func (c *Client) Incr(key string) *sobek.Promise {
 	}
 
 	go func() {
-		newValue, err := c.redisClient.Incr(c.vu.Context(), key).Result()
+		newValue, err := c.redisClient.Incr(c.context(), key).Result()
 		if err != nil {
 			reject(err)
 			return
@@ -210,7 +505,7 @@ func (c *Client) Incr(key string) *sobek.Promise {
 // error is returned if the key contains a value of the wrong type, or
 // contains a string that cannot be represented as an integer.
 func (c *Client) IncrBy(key string, increment int64) *sobek.Promise {
-	promise, resolve, reject := promises.New(c.vu)
+	promise, resolve, reject := c.newPromise()
 
 	if err := c.connect(); err != nil {
 		reject(err)
@@ -218,7 +513,7 @@ func (c *Client) IncrBy(key string, increment int64) *sobek.Promise {
 	}
 
 	go func() {
-		newValue, err := c.redisClient.IncrBy(c.vu.Context(), key, increment).Result()
+		newValue, err := c.redisClient.IncrBy(c.context(), key, increment).Result()
 		if err != nil {
 			reject(err)
 			return
@@ -235,7 +530,7 @@ func (c *Client) IncrBy(key string, increment int64) *sobek.Promise {
 // error is returned if the key contains a value of the wrong type, or
 // contains a string that cannot be represented as an integer.
 func (c *Client) Decr(key string) *sobek.Promise {
-	promise, resolve, reject := promises.New(c.vu)
+	promise, resolve, reject := c.newPromise()
 
 	if err := c.connect(); err != nil {
 		reject(err)
@@ -243,7 +538,7 @@ func (c *Client) Decr(key string) *sobek.Promise {
 	}
 
 	go func() {
-		newValue, err := c.redisClient.Decr(c.vu.Context(), key).Result()
+		newValue, err := c.redisClient.Decr(c.context(), key).Result()
 		if err != nil {
 			reject(err)
 			return
@@ -260,7 +555,7 @@ func (c *Client) Decr(key string) *sobek.Promise {
 // error is returned if the key contains a value of the wrong type, or
 // contains a string that cannot be represented as an integer.
 func (c *Client) DecrBy(key string, decrement int64) *sobek.Promise {
-	promise, resolve, reject := promises.New(c.vu)
+	promise, resolve, reject := c.newPromise()
 
 	if err := c.connect(); err != nil {
 		reject(err)
@@ -268,7 +563,7 @@ func (c *Client) DecrBy(key string, decrement int64) *sobek.Promise {
 	}
 
 	go func() {
-		newValue, err := c.redisClient.DecrBy(c.vu.Context(), key, decrement).Result()
+		newValue, err := c.redisClient.DecrBy(c.context(), key, decrement).Result()
 		if err != nil {
 			reject(err)
 			return
@@ -284,7 +579,7 @@ func (c *Client) DecrBy(key string, decrement int64) *sobek.Promise {
 //
 // If the database is empty, the promise is rejected with an error.
 func (c *Client) RandomKey() *sobek.Promise {
-	promise, resolve, reject := promises.New(c.vu)
+	promise, resolve, reject := c.newPromise()
 
 	if err := c.connect(); err != nil {
 		reject(err)
@@ -292,7 +587,7 @@ func (c *Client) RandomKey() *sobek.Promise {
 	}
 
 	go func() {
-		key, err := c.redisClient.RandomKey(c.vu.Context()).Result()
+		key, err := c.redisClient.RandomKey(c.context()).Result()
 		if err != nil {
 			reject(err)
 			return
@@ -304,42 +599,247 @@ func (c *Client) RandomKey() *sobek.Promise {
 	return promise
 }
 
+// mgetOptions holds the options of the Client's mget method.
+type mgetOptions struct {
+	multiKeyOptions
+
+	// AsObject resolves an object mapping each key to its value, rather
+	// than an array of values.
+	AsObject bool `json:"asObject,omitempty"`
+
+	// OmitNulls leaves out the null values, of the keys that do not exist,
+	// or do not hold a string value.
+	OmitNulls bool `json:"omitNulls,omitempty"`
+}
+
 // Mget returns the values associated with the specified keys.
-func (c *Client) Mget(keys ...string) *sobek.Promise {
-	promise, resolve, reject := promises.New(c.vu)
+//
+// The keys can be provided as arguments, or as a single array, and be
+// followed by an options object. With the `asObject` option set, the
+// values are resolved as an object keyed by key, and with the `omitNulls`
+// option set, the null values are left out. With the `partial` option set,
+// the keys are fetched with a command per cluster hash slot, and those that
+// could not be fetched are reported, rather than failing the whole call,
+// see mgetPartial.
+func (c *Client) Mget(args ...interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
 
 	if err := c.connect(); err != nil {
 		reject(err)
 		return promise
 	}
 
+	var opts mgetOptions
+	keys, err := multiKeyArgs(args, &opts)
+	if err != nil {
+		reject(fmt.Errorf("invalid mget options; reason: %w", err))
+		return promise
+	}
+
 	go func() {
-		values, err := c.redisClient.MGet(c.vu.Context(), keys...).Result()
+		if opts.Partial {
+			result := c.mgetPartial(c.context(), keys, opts.OmitNulls)
+			result["results"] = c.typedValue(result["results"])
+			resolve(result)
+			return
+		}
+
+		values, err := c.redisClient.MGet(c.context(), keys...).Result()
 		if err != nil {
 			reject(err)
 			return
 		}
 
-		resolve(values)
+		resolve(c.typedValue(mgetResult(keys, values, opts)))
+	}()
+
+	return promise
+}
+
+// mgetResult shapes the `values` of the provided keys, as returned by the
+// MGET command, according to the asObject and omitNulls options.
+func mgetResult(keys []string, values []interface{}, opts mgetOptions) interface{} {
+	if opts.AsObject {
+		result := make(map[string]interface{}, len(values))
+		for idx, value := range values {
+			if value == nil && opts.OmitNulls {
+				continue
+			}
+			result[keys[idx]] = value
+		}
+
+		return result
+	}
+
+	if !opts.OmitNulls {
+		return values
+	}
+
+	result := make([]interface{}, 0, len(values))
+	for _, value := range values {
+		if value != nil {
+			result = append(result, value)
+		}
+	}
+
+	return result
+}
+
+// Mset sets the provided keys to their respective values, provided as an
+// object, or as a Map.
+//
+// If any of the provided values is not a supported type, the promise is
+// rejected with an error. Values can be binary: ArrayBuffer or Uint8Array.
+// Calls exceeding the commandChunkSize option are split in pipelined
+// chunks. With the `partial` option set, the keys are set
+// with a command per cluster hash slot, and those that could not be set
+// are reported, rather than failing the whole call, see msetPartial.
+func (c *Client) Mset(values map[string]interface{}, options map[string]interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	if len(values) == 0 {
+		reject(errors.New("at least one key must be provided to mset"))
+		return promise
+	}
+
+	for key, value := range values {
+		converted, err := c.binaryArgs(1, value)
+		if err != nil {
+			reject(err)
+			return promise
+		}
+
+		values[key] = converted[0]
+	}
+
+	var opts multiKeyOptions
+	if err := decodeOptions(options, &opts); err != nil {
+		reject(fmt.Errorf("invalid mset options; reason: %w", err))
+		return promise
+	}
+
+	go func() {
+		if opts.Partial {
+			resolve(c.msetPartial(c.context(), values))
+			return
+		}
+
+		keys := make([]string, 0, len(values))
+		for key := range values {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		ctx := c.context()
+		_, err := execChunks(ctx, c, keys, func(cmd redis.Cmdable, chunk []string) *redis.StatusCmd {
+			args := make([]interface{}, 0, 2*len(chunk))
+			for _, key := range chunk {
+				args = append(args, key, values[key])
+			}
+
+			return cmd.MSet(ctx, args...)
+		})
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve("OK")
 	}()
 
 	return promise
 }
 
+// expireOptions holds the options of the Client's expire, pexpire, and
+// expireat methods.
+type expireOptions struct {
+	// NX only sets the timeout if the key has none.
+	NX bool `json:"nx,omitempty"`
+
+	// XX only sets the timeout if the key already has one.
+	XX bool `json:"xx,omitempty"`
+
+	// GT only sets the timeout if it is greater than the current one.
+	GT bool `json:"gt,omitempty"`
+
+	// LT only sets the timeout if it is less than the current one.
+	LT bool `json:"lt,omitempty"`
+}
+
+// args returns the condition argument the options translate to, if any.
+func (o expireOptions) args() ([]interface{}, error) {
+	var args []interface{}
+	for flag, set := range map[string]bool{"nx": o.NX, "xx": o.XX, "gt": o.GT, "lt": o.LT} {
+		if set {
+			args = append(args, flag)
+		}
+	}
+
+	if len(args) > 1 {
+		return nil, errors.New("nx, xx, gt, and lt are mutually exclusive")
+	}
+
+	return args, nil
+}
+
 // Expire sets a timeout on key, after which the key will automatically
 // be deleted.
 // Note that calling Expire with a non-positive timeout will result in
 // the key being deleted rather than expired.
-func (c *Client) Expire(key string, seconds int) *sobek.Promise {
-	promise, resolve, reject := promises.New(c.vu)
+//
+// The nx, xx, gt, and lt options only set the timeout under the matching
+// condition, and require Redis 7.
+func (c *Client) Expire(key string, seconds int, options map[string]interface{}) *sobek.Promise {
+	return c.expire("expire", key, int64(seconds), options)
+}
+
+// Pexpire is like Expire, except that the timeout is set in milliseconds.
+func (c *Client) Pexpire(key string, milliseconds int64, options map[string]interface{}) *sobek.Promise {
+	return c.expire("pexpire", key, milliseconds, options)
+}
+
+// Expireat is like Expire, except that the key expires at the provided
+// absolute Unix time, in seconds. A time in the past deletes the key.
+func (c *Client) Expireat(key string, timestamp int64, options map[string]interface{}) *sobek.Promise {
+	return c.expire("expireat", key, timestamp, options)
+}
+
+// expire sends the expiration `command` for `key`, with the provided value
+// and options.
+//
+// The promise resolves with true if the timeout was set, and false
+// otherwise.
+func (c *Client) expire(command, key string, value int64, options map[string]interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
 
 	if err := c.connect(); err != nil {
 		reject(err)
 		return promise
 	}
 
+	var opts expireOptions
+	if err := decodeOptions(options, &opts); err != nil {
+		reject(fmt.Errorf("invalid %s options; reason: %w", command, err))
+		return promise
+	}
+
+	condArgs, err := opts.args()
+	if err != nil {
+		reject(fmt.Errorf("invalid %s options; %w", command, err))
+		return promise
+	}
+
 	go func() {
-		ok, err := c.redisClient.Expire(c.vu.Context(), key, time.Duration(seconds)*time.Second).Result()
+		ctx := c.context()
+		cmd := redis.NewBoolCmd(ctx, append([]interface{}{command, key, value}, condArgs...)...)
+		_ = c.redisClient.Process(ctx, cmd)
+
+		ok, err := cmd.Result()
 		if err != nil {
 			reject(err)
 			return
@@ -355,7 +855,7 @@ func (c *Client) Expire(key string, seconds int) *sobek.Promise {
 //
 //nolint:revive,stylecheck
 func (c *Client) Ttl(key string) *sobek.Promise {
-	promise, resolve, reject := promises.New(c.vu)
+	promise, resolve, reject := c.newPromise()
 
 	if err := c.connect(); err != nil {
 		reject(err)
@@ -363,7 +863,7 @@ func (c *Client) Ttl(key string) *sobek.Promise {
 	}
 
 	go func() {
-		duration, err := c.redisClient.TTL(c.vu.Context(), key).Result()
+		duration, err := c.redisClient.TTL(c.context(), key).Result()
 		if err != nil {
 			reject(err)
 			return
@@ -377,7 +877,7 @@ func (c *Client) Ttl(key string) *sobek.Promise {
 
 // Persist removes the existing timeout on key.
 func (c *Client) Persist(key string) *sobek.Promise {
-	promise, resolve, reject := promises.New(c.vu)
+	promise, resolve, reject := c.newPromise()
 
 	if err := c.connect(); err != nil {
 		reject(err)
@@ -385,7 +885,7 @@ func (c *Client) Persist(key string) *sobek.Promise {
 	}
 
 	go func() {
-		ok, err := c.redisClient.Persist(c.vu.Context(), key).Result()
+		ok, err := c.redisClient.Persist(c.context(), key).Result()
 		if err != nil {
 			reject(err)
 			return
@@ -397,96 +897,851 @@ func (c *Client) Persist(key string) *sobek.Promise {
 	return promise
 }
 
-// Lpush inserts all the specified values at the head of the list stored
-// at `key`. If `key` does not exist, it is created as empty list before
-// performing the push operations. When `key` holds a value that is not
-// a list, and error is returned.
-func (c *Client) Lpush(key string, values ...interface{}) *sobek.Promise {
-	promise, resolve, reject := promises.New(c.vu)
+// Pttl returns the remaining time to live, in milliseconds, of a key that
+// has a timeout.
+//
+// The promise resolves with the time to live, -1 if the key has no
+// timeout, or -2 if the key does not exist.
+func (c *Client) Pttl(key string) *sobek.Promise {
+	return c.expirationInt("pttl", key)
+}
+
+// Expiretime returns the absolute Unix time, in seconds, at which `key`
+// expires. It requires Redis 7.
+//
+// The promise resolves with the expiration time, -1 if the key has no
+// timeout, or -2 if the key does not exist.
+func (c *Client) Expiretime(key string) *sobek.Promise {
+	return c.expirationInt("expiretime", key)
+}
+
+// expirationInt sends the expiration `command` for `key`, whose reply is
+// an integer.
+func (c *Client) expirationInt(command, key string) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
 
 	if err := c.connect(); err != nil {
 		reject(err)
 		return promise
 	}
 
-	if err := c.isSupportedType(1, values...); err != nil {
+	go func() {
+		ctx := c.context()
+		cmd := redis.NewIntCmd(ctx, command, key)
+		_ = c.redisClient.Process(ctx, cmd)
+
+		value, err := cmd.Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(value)
+	}()
+
+	return promise
+}
+
+// AppendLog appends `entry` at the end of the string log stored at `key`,
+// using the APPEND command. If `key` does not exist, it is created holding
+// `entry`. As APPEND is atomic, concurrent appends never overwrite each other.
+//
+// The promise resolves with the new total length of the log, in bytes.
+func (c *Client) AppendLog(key string, entry string) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
 		reject(err)
 		return promise
 	}
 
 	go func() {
-		listLength, err := c.redisClient.LPush(c.vu.Context(), key, values...).Result()
+		length, err := c.redisClient.Append(c.context(), key, entry).Result()
 		if err != nil {
 			reject(err)
 			return
 		}
 
-		resolve(listLength)
+		resolve(length)
 	}()
 
 	return promise
 }
 
-// Rpush inserts all the specified values at the tail of the list stored
-// at `key`. If `key` does not exist, it is created as empty list before
-// performing the push operations.
-func (c *Client) Rpush(key string, values ...interface{}) *sobek.Promise {
-	promise, resolve, reject := promises.New(c.vu)
+// TailLog returns the last `bytes` bytes of the string log stored at `key`,
+// using the GETRANGE command, without transferring the whole value.
+//
+// If the log is shorter than `bytes`, it is returned in its entirety. If `key`
+// does not exist, the promise resolves with an empty string.
+func (c *Client) TailLog(key string, bytes int64) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
 
 	if err := c.connect(); err != nil {
 		reject(err)
 		return promise
 	}
 
-	if err := c.isSupportedType(1, values...); err != nil {
-		reject(err)
+	if bytes <= 0 {
+		reject(fmt.Errorf("invalid bytes count %d; expected a positive number", bytes))
 		return promise
 	}
 
 	go func() {
-		listLength, err := c.redisClient.RPush(c.vu.Context(), key, values...).Result()
+		tail, err := c.redisClient.GetRange(c.context(), key, -bytes, -1).Result()
 		if err != nil {
 			reject(err)
 			return
 		}
 
-		resolve(listLength)
+		resolve(tail)
 	}()
 
 	return promise
 }
 
-// Lpop removes and returns the first element of the list stored at `key`.
+// Append appends `value` at the end of the string stored at `key`. If `key`
+// does not exist, it is created holding `value`.
 //
-// If the list does not exist, this command rejects the promise with an error.
-func (c *Client) Lpop(key string) *sobek.Promise {
-	// TODO: redis supports indicating the amount of values to pop
-	promise, resolve, reject := promises.New(c.vu)
+// The value can be binary: ArrayBuffer or Uint8Array. The promise resolves
+// with the length of the string after the append, in bytes.
+func (c *Client) Append(key string, value interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
 
 	if err := c.connect(); err != nil {
 		reject(err)
 		return promise
 	}
 
+	values, err := c.binaryFields(1, value)
+	if err != nil {
+		reject(err)
+		return promise
+	}
+
 	go func() {
-		value, err := c.redisClient.LPop(c.vu.Context(), key).Result()
+		length, err := c.redisClient.Append(c.context(), key, values[0]).Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(length)
+	}()
+
+	return promise
+}
+
+// Strlen returns the length, in bytes, of the string stored at `key`, or 0
+// if the key does not exist.
+func (c *Client) Strlen(key string) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		length, err := c.redisClient.StrLen(c.context(), key).Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(length)
+	}()
+
+	return promise
+}
+
+// Getrange returns the substring of the string stored at `key` between the
+// `start` and `end` offsets, both included. Negative offsets count from the
+// end of the string.
+//
+// The promise resolves with an empty string if the key does not exist.
+func (c *Client) Getrange(key string, start, end int64) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		value, err := c.redisClient.GetRange(c.context(), key, start, end).Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(value)
+	}()
+
+	return promise
+}
+
+// Setrange overwrites the string stored at `key` with `value`, starting at
+// `offset`. The string is padded with zero bytes if it is shorter than
+// `offset`, and created if the key does not exist.
+//
+// The value can be binary: ArrayBuffer or Uint8Array. The promise resolves
+// with the length of the string after it was modified, in bytes.
+func (c *Client) Setrange(key string, offset int64, value interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	if offset < 0 {
+		reject(fmt.Errorf("invalid offset %d; expected a non-negative number", offset))
+		return promise
+	}
+
+	values, err := c.binaryFields(2, value)
+	if err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		length, err := c.redisClient.SetRange(c.context(), key, offset, values[0]).Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(length)
+	}()
+
+	return promise
+}
+
+// getexOptions holds the options of the Client's getex method.
+type getexOptions struct {
+	// EX, PX, EXAT, and PXAT set the key's time to live, as they do for
+	// set.
+	EX   int64 `json:"ex,omitempty"`
+	PX   int64 `json:"px,omitempty"`
+	EXAT int64 `json:"exat,omitempty"`
+	PXAT int64 `json:"pxat,omitempty"`
+
+	// Persist removes the key's time to live.
+	Persist bool `json:"persist,omitempty"`
+}
+
+// args returns the arguments of the GETEX command the options translate to.
+func (o getexOptions) args() ([]interface{}, error) {
+	var args []interface{}
+	for _, expiration := range []struct {
+		name  string
+		value int64
+	}{{"ex", o.EX}, {"px", o.PX}, {"exat", o.EXAT}, {"pxat", o.PXAT}} {
+		if expiration.value < 0 {
+			return nil, fmt.Errorf("invalid %s option: %d; expected a positive number", expiration.name, expiration.value)
+		}
+		if expiration.value > 0 {
+			args = append(args, expiration.name, expiration.value)
+		}
+	}
+	if o.Persist {
+		args = append(args, "persist")
+	}
+	if len(args) > 2 || (o.Persist && len(args) > 1) {
+		return nil, errors.New("ex, px, exat, pxat, and persist are mutually exclusive")
+	}
+
+	return args, nil
+}
+
+// Getex returns the value of `key`, and atomically updates its time to
+// live according to the options: the ex, px, exat, and pxat options set it
+// as they do for set, and persist removes it. Without options, it behaves
+// like Get.
+//
+// If the key does not exist, the promise is rejected with an error.
+func (c *Client) Getex(key string, options map[string]interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	var opts getexOptions
+	if err := decodeOptions(options, &opts); err != nil {
+		reject(fmt.Errorf("invalid getex options; reason: %w", err))
+		return promise
+	}
+
+	optArgs, err := opts.args()
+	if err != nil {
+		reject(fmt.Errorf("invalid getex options; %w", err))
+		return promise
+	}
+
+	go func() {
+		ctx := c.context()
+		cmd := redis.NewStringCmd(ctx, append([]interface{}{"getex", key}, optArgs...)...)
+		_ = c.redisClient.Process(ctx, cmd)
+
+		value, err := cmd.Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(value)
+	}()
+
+	return promise
+}
+
+// Lpush inserts all the specified values at the head of the list stored
+// at `key`. If `key` does not exist, it is created as empty list before
+// performing the push operations. When `key` holds a value that is not
+// a list, and error is returned.
+//
+// Values can be binary: ArrayBuffer or Uint8Array.
+func (c *Client) Lpush(key string, values ...interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	valueArgs, err := c.binaryArgs(1, values...)
+	if err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		listLength, err := c.redisClient.LPush(c.context(), key, valueArgs...).Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(listLength)
+	}()
+
+	return promise
+}
+
+// Rpush inserts all the specified values at the tail of the list stored
+// at `key`. If `key` does not exist, it is created as empty list before
+// performing the push operations.
+//
+// Values can be binary: ArrayBuffer or Uint8Array.
+func (c *Client) Rpush(key string, values ...interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	valueArgs, err := c.binaryArgs(1, values...)
+	if err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		listLength, err := c.redisClient.RPush(c.context(), key, valueArgs...).Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(listLength)
+	}()
+
+	return promise
+}
+
+// Lpop removes and returns the first element of the list stored at `key`.
+//
+// If the list does not exist, this command rejects the promise with an error.
+func (c *Client) Lpop(key string) *sobek.Promise {
+	// TODO: redis supports indicating the amount of values to pop
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		value, err := c.redisClient.LPop(c.context(), key).Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(value)
+	}()
+
+	return promise
+}
+
+// Rpop removes and returns the last element of the list stored at `key`.
+//
+// If the list does not exist, this command rejects the promise with an error.
+func (c *Client) Rpop(key string) *sobek.Promise {
+	// TODO: redis supports indicating the amount of values to pop
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		value, err := c.redisClient.RPop(c.context(), key).Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(value)
+	}()
+
+	return promise
+}
+
+// Lrange returns the specified elements of the list stored at `key`. The
+// offsets start and stop are zero-based indexes. These offsets can be
+// negative numbers, where they indicate offsets starting at the end of
+// the list.
+func (c *Client) Lrange(key string, start, stop int64) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		values, err := c.redisClient.LRange(c.context(), key, start, stop).Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(values)
+	}()
+
+	return promise
+}
+
+// Lindex returns the specified element of the list stored at `key`.
+// The index is zero-based. Negative indices can be used to designate
+// elements starting at the tail of the list.
+//
+// If the list does not exist, this command rejects the promise with an error.
+func (c *Client) Lindex(key string, index int64) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		value, err := c.redisClient.LIndex(c.context(), key, index).Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(value)
+	}()
+
+	return promise
+}
+
+// Lset sets the list element at `index` to `element`.
+//
+// If the list does not exist, this command rejects the promise with an error.
+func (c *Client) Lset(key string, index int64, element string) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		value, err := c.redisClient.LSet(c.context(), key, index, element).Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(value)
+	}()
+
+	return promise
+}
+
+// Lrem removes the first `count` occurrences of `value` from the list stored
+// at `key`. If `count` is positive, elements are removed from the beginning of the list.
+// If `count` is negative, elements are removed from the end of the list.
+// If `count` is zero, all elements matching `value` are removed.
+//
+// If the list does not exist, this command rejects the promise with an error.
+func (c *Client) Lrem(key string, count int64, value string) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		n, err := c.redisClient.LRem(c.context(), key, count, value).Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(n)
+	}()
+
+	return promise
+}
+
+// Llen returns the length of the list stored at `key`. If `key`
+// does not exist, it is interpreted as an empty list and 0 is returned.
+//
+// If the list does not exist, this command rejects the promise with an error.
+func (c *Client) Llen(key string) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		length, err := c.redisClient.LLen(c.context(), key).Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(length)
+	}()
+
+	return promise
+}
+
+// checkListEnds returns an error if any of the provided list ends of
+// `command` is neither "left" nor "right".
+func checkListEnds(command string, ends ...string) error {
+	for _, end := range ends {
+		if !strings.EqualFold(end, "left") && !strings.EqualFold(end, "right") {
+			return fmt.Errorf("invalid %s list end %q; expected %q or %q", command, end, "left", "right")
+		}
+	}
+
+	return nil
+}
+
+// popCount returns the optional count of the multi-key pop `command`, 1 if
+// it is omitted.
+func popCount(command string, count []int64) (int64, error) {
+	switch {
+	case len(count) == 0:
+		return 1, nil
+	case len(count) > 1:
+		return 0, fmt.Errorf("%s accepts a single count; got %d", command, len(count))
+	case count[0] <= 0:
+		return 0, fmt.Errorf("invalid %s count %d; expected a positive number", command, count[0])
+	default:
+		return count[0], nil
+	}
+}
+
+// Lmpop pops up to `count` elements, 1 by default, from the `from` end,
+// "left" or "right", of the first non-empty list among `keys`. It requires
+// Redis 7.
+//
+// The promise resolves with an object holding the `key` the elements were
+// popped from and its `values`, or null if all the lists are empty.
+func (c *Client) Lmpop(keys []string, from string, count ...int64) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	if len(keys) == 0 {
+		reject(errors.New("at least one key must be provided to lmpop"))
+		return promise
+	}
+
+	if err := checkListEnds("lmpop", from); err != nil {
+		reject(err)
+		return promise
+	}
+
+	n, err := popCount("lmpop", count)
+	if err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		key, values, err := c.redisClient.LMPop(c.context(), from, n, keys...).Result()
+		if errors.Is(err, redis.Nil) {
+			resolve(nil)
+			return
+		}
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(map[string]interface{}{"key": key, "values": values})
+	}()
+
+	return promise
+}
+
+// lposOptions holds the options of the Client's lpos method.
+type lposOptions struct {
+	// Rank is the rank of the match to return: 2 returns the second one.
+	// Negative ranks search from the tail of the list.
+	Rank int64 `json:"rank,omitempty"`
+
+	// Count returns the indexes of up to that many matches, or all of
+	// them if 0, rather than the index of a single match.
+	Count *int64 `json:"count,omitempty"`
+
+	// MaxLen limits the search to that many elements.
+	MaxLen int64 `json:"maxlen,omitempty"`
+}
+
+// Lpos returns the index of `element` in the list stored at `key`.
+//
+// The promise resolves with the index, or null if `element` is not found.
+// With the count option set, it resolves with an array of indexes instead.
+func (c *Client) Lpos(key string, element string, options map[string]interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	var opts lposOptions
+	if err := decodeOptions(options, &opts); err != nil {
+		reject(fmt.Errorf("invalid lpos options; reason: %w", err))
+		return promise
+	}
+
+	if opts.Count != nil && *opts.Count < 0 {
+		reject(fmt.Errorf("invalid lpos options; count must not be negative; got %d", *opts.Count))
+		return promise
+	}
+
+	if opts.MaxLen < 0 {
+		reject(fmt.Errorf("invalid lpos options; maxlen must not be negative; got %d", opts.MaxLen))
+		return promise
+	}
+
+	args := redis.LPosArgs{Rank: opts.Rank, MaxLen: opts.MaxLen}
+
+	go func() {
+		if opts.Count != nil {
+			indexes, err := c.redisClient.LPosCount(c.context(), key, element, *opts.Count, args).Result()
+			if err != nil {
+				reject(err)
+				return
+			}
+
+			resolve(indexes)
+			return
+		}
+
+		index, err := c.redisClient.LPos(c.context(), key, element, args).Result()
+		if errors.Is(err, redis.Nil) {
+			resolve(nil)
+			return
+		}
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(index)
+	}()
+
+	return promise
+}
+
+// Lmove atomically removes the element at the `from` end, "left" or
+// "right", of the list stored at `source`, and pushes it at the `to` end of
+// the list stored at `destination`. It is the non-blocking variant of
+// Blmove.
+//
+// The promise resolves with the moved element, or null if `source` is
+// empty.
+func (c *Client) Lmove(source, destination, from, to string) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	if err := checkListEnds("lmove", from, to); err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		moved, err := c.redisClient.LMove(c.context(), source, destination, from, to).Result()
+		if errors.Is(err, redis.Nil) {
+			resolve(nil)
+			return
+		}
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(moved)
+	}()
+
+	return promise
+}
+
+// Hset sets the specified field in the hash stored at `key` to `value`.
+// If the `key` does not exist, a new key holding a hash is created.
+// If `field` already exists in the hash, it is overwritten.
+//
+// If the hash does not exist, this command rejects the promise with an error.
+//
+// Both `field` and `value` can be binary: ArrayBuffer or Uint8Array.
+//
+// Several fields can be set at once by passing an object mapping each
+// field to its value as `field`, and no `value`. Calls exceeding the
+// commandChunkSize option are then split in pipelined chunks.
+func (c *Client) Hset(key string, field interface{}, value interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	if obj, ok := field.(map[string]interface{}); ok {
+		pairs, err := c.hashFieldValues(obj)
+		if err != nil {
+			reject(err)
+			return promise
+		}
+
+		go func() {
+			ctx := c.context()
+			n, err := sumChunks(ctx, c, pairs, func(cmd redis.Cmdable, chunk [][2]interface{}) *redis.IntCmd {
+				args := make([]interface{}, 0, 2*len(chunk))
+				for _, pair := range chunk {
+					args = append(args, pair[0], pair[1])
+				}
+
+				return cmd.HSet(ctx, key, args...)
+			})
+			if err != nil {
+				reject(err)
+				return
+			}
+
+			resolve(n)
+		}()
+
+		return promise
+	}
+
+	fields, err := c.binaryFields(1, field)
+	if err != nil {
+		reject(err)
+		return promise
+	}
+
+	values, err := c.binaryArgs(2, value)
+	if err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		n, err := c.redisClient.HSet(c.context(), key, fields[0], values[0]).Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(n)
+	}()
+
+	return promise
+}
+
+// Hmset sets the fields of the hash stored at `key` to the values of the
+// `values` object. If the `key` does not exist, a new key holding a hash is
+// created. Fields that already exist in the hash are overwritten.
+//
+// Values can be binary: ArrayBuffer or Uint8Array. Calls exceeding the
+// commandChunkSize option are split in pipelined chunks.
+func (c *Client) Hmset(key string, values map[string]interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	pairs, err := c.hashFieldValues(values)
+	if err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		ctx := c.context()
+		_, err := execChunks(ctx, c, pairs, func(cmd redis.Cmdable, chunk [][2]interface{}) *redis.BoolCmd {
+			args := make([]interface{}, 0, 2*len(chunk))
+			for _, pair := range chunk {
+				args = append(args, pair[0], pair[1])
+			}
+
+			return cmd.HMSet(ctx, key, args...)
+		})
 		if err != nil {
 			reject(err)
 			return
 		}
 
-		resolve(value)
+		resolve("OK")
 	}()
 
 	return promise
 }
 
-// Rpop removes and returns the last element of the list stored at `key`.
-//
-// If the list does not exist, this command rejects the promise with an error.
-func (c *Client) Rpop(key string) *sobek.Promise {
-	// TODO: redis supports indicating the amount of values to pop
-	promise, resolve, reject := promises.New(c.vu)
+// Hsetnx sets the specified field in the hash stored at `key` to `value`,
+// only if `field` does not yet exist. If `key` does not exist, a new key
+// holding a hash is created. If `field` already exists, this operation
+// has no effect.
+func (c *Client) Hsetnx(key, field, value string) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
 
 	if err := c.connect(); err != nil {
 		reject(err)
@@ -494,74 +1749,85 @@ func (c *Client) Rpop(key string) *sobek.Promise {
 	}
 
 	go func() {
-		value, err := c.redisClient.RPop(c.vu.Context(), key).Result()
+		ok, err := c.redisClient.HSetNX(c.context(), key, field, value).Result()
 		if err != nil {
 			reject(err)
 			return
 		}
 
-		resolve(value)
+		resolve(ok)
 	}()
 
 	return promise
 }
 
-// Lrange returns the specified elements of the list stored at `key`. The
-// offsets start and stop are zero-based indexes. These offsets can be
-// negative numbers, where they indicate offsets starting at the end of
-// the list.
-func (c *Client) Lrange(key string, start, stop int64) *sobek.Promise {
-	promise, resolve, reject := promises.New(c.vu)
+// Hget returns the value associated with `field` in the hash stored at `key`.
+//
+// If the hash does not exist, this command rejects the promise with an error.
+//
+// `field` can be binary: ArrayBuffer or Uint8Array.
+func (c *Client) Hget(key string, field interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
 
 	if err := c.connect(); err != nil {
 		reject(err)
 		return promise
 	}
 
+	fields, err := c.binaryFields(1, field)
+	if err != nil {
+		reject(err)
+		return promise
+	}
+
 	go func() {
-		values, err := c.redisClient.LRange(c.vu.Context(), key, start, stop).Result()
+		value, err := c.redisClient.HGet(c.context(), key, fields[0]).Result()
 		if err != nil {
 			reject(err)
 			return
 		}
 
-		resolve(values)
+		resolve(c.typedValue(value))
 	}()
 
 	return promise
 }
 
-// Lindex returns the specified element of the list stored at `key`.
-// The index is zero-based. Negative indices can be used to designate
-// elements starting at the tail of the list.
+// Hdel deletes the specified fields from the hash stored at `key`.
 //
-// If the list does not exist, this command rejects the promise with an error.
-func (c *Client) Lindex(key string, index int64) *sobek.Promise {
-	promise, resolve, reject := promises.New(c.vu)
+// Fields can be binary: ArrayBuffer or Uint8Array.
+func (c *Client) Hdel(key string, fields ...interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
 
 	if err := c.connect(); err != nil {
 		reject(err)
 		return promise
 	}
 
+	fieldArgs, err := c.binaryFields(1, fields...)
+	if err != nil {
+		reject(err)
+		return promise
+	}
+
 	go func() {
-		value, err := c.redisClient.LIndex(c.vu.Context(), key, index).Result()
+		n, err := c.redisClient.HDel(c.context(), key, fieldArgs...).Result()
 		if err != nil {
 			reject(err)
 			return
 		}
 
-		resolve(value)
+		resolve(n)
 	}()
 
 	return promise
 }
 
-// Lset sets the list element at `index` to `element`.
+// Hgetall returns all fields and values of the hash stored at `key`.
 //
-// If the list does not exist, this command rejects the promise with an error.
-func (c *Client) Lset(key string, index int64, element string) *sobek.Promise {
-	promise, resolve, reject := promises.New(c.vu)
+// If the hash does not exist, this command rejects the promise with an error.
+func (c *Client) Hgetall(key string) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
 
 	if err := c.connect(); err != nil {
 		reject(err)
@@ -569,26 +1835,23 @@ func (c *Client) Lset(key string, index int64, element string) *sobek.Promise {
 	}
 
 	go func() {
-		value, err := c.redisClient.LSet(c.vu.Context(), key, index, element).Result()
+		hashMap, err := c.redisClient.HGetAll(c.context(), key).Result()
 		if err != nil {
 			reject(err)
 			return
 		}
 
-		resolve(value)
+		resolve(c.typedValue(hashMap))
 	}()
 
 	return promise
 }
 
-// Lrem removes the first `count` occurrences of `value` from the list stored
-// at `key`. If `count` is positive, elements are removed from the beginning of the list.
-// If `count` is negative, elements are removed from the end of the list.
-// If `count` is zero, all elements matching `value` are removed.
+// Hkeys returns all fields of the hash stored at `key`.
 //
-// If the list does not exist, this command rejects the promise with an error.
-func (c *Client) Lrem(key string, count int64, value string) *sobek.Promise {
-	promise, resolve, reject := promises.New(c.vu)
+// If the hash does not exist, this command rejects the promise with an error.
+func (c *Client) Hkeys(key string) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
 
 	if err := c.connect(); err != nil {
 		reject(err)
@@ -596,24 +1859,23 @@ func (c *Client) Lrem(key string, count int64, value string) *sobek.Promise {
 	}
 
 	go func() {
-		n, err := c.redisClient.LRem(c.vu.Context(), key, count, value).Result()
+		keys, err := c.redisClient.HKeys(c.context(), key).Result()
 		if err != nil {
 			reject(err)
 			return
 		}
 
-		resolve(n)
+		resolve(keys)
 	}()
 
 	return promise
 }
 
-// Llen returns the length of the list stored at `key`. If `key`
-// does not exist, it is interpreted as an empty list and 0 is returned.
+// Hvals returns all values of the hash stored at `key`.
 //
-// If the list does not exist, this command rejects the promise with an error.
-func (c *Client) Llen(key string) *sobek.Promise {
-	promise, resolve, reject := promises.New(c.vu)
+// If the hash does not exist, this command rejects the promise with an error.
+func (c *Client) Hvals(key string) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
 
 	if err := c.connect(); err != nil {
 		reject(err)
@@ -621,38 +1883,31 @@ func (c *Client) Llen(key string) *sobek.Promise {
 	}
 
 	go func() {
-		length, err := c.redisClient.LLen(c.vu.Context(), key).Result()
+		values, err := c.redisClient.HVals(c.context(), key).Result()
 		if err != nil {
 			reject(err)
 			return
 		}
 
-		resolve(length)
+		resolve(c.typedValue(values))
 	}()
 
 	return promise
 }
 
-// Hset sets the specified field in the hash stored at `key` to `value`.
-// If the `key` does not exist, a new key holding a hash is created.
-// If `field` already exists in the hash, it is overwritten.
+// Hlen returns the number of fields in the hash stored at `key`.
 //
 // If the hash does not exist, this command rejects the promise with an error.
-func (c *Client) Hset(key string, field string, value interface{}) *sobek.Promise {
-	promise, resolve, reject := promises.New(c.vu)
+func (c *Client) Hlen(key string) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
 
 	if err := c.connect(); err != nil {
 		reject(err)
 		return promise
 	}
 
-	if err := c.isSupportedType(2, value); err != nil {
-		reject(err)
-		return promise
-	}
-
 	go func() {
-		n, err := c.redisClient.HSet(c.vu.Context(), key, field, value).Result()
+		n, err := c.redisClient.HLen(c.context(), key).Result()
 		if err != nil {
 			reject(err)
 			return
@@ -664,12 +1919,12 @@ func (c *Client) Hset(key string, field string, value interface{}) *sobek.Promis
 	return promise
 }
 
-// Hsetnx sets the specified field in the hash stored at `key` to `value`,
-// only if `field` does not yet exist. If `key` does not exist, a new key
-// holding a hash is created. If `field` already exists, this operation
-// has no effect.
-func (c *Client) Hsetnx(key, field, value string) *sobek.Promise {
-	promise, resolve, reject := promises.New(c.vu)
+// Hincrby increments the integer value of `field` in the hash stored at `key`
+// by `increment`. If `key` does not exist, a new key holding a hash is created.
+// If `field` does not exist the value is set to 0 before the operation is
+// set to 0 before the operation is performed.
+func (c *Client) Hincrby(key, field string, increment int64) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
 
 	if err := c.connect(); err != nil {
 		reject(err)
@@ -677,53 +1932,151 @@ func (c *Client) Hsetnx(key, field, value string) *sobek.Promise {
 	}
 
 	go func() {
-		ok, err := c.redisClient.HSetNX(c.vu.Context(), key, field, value).Result()
+		newValue, err := c.redisClient.HIncrBy(c.context(), key, field, increment).Result()
 		if err != nil {
 			reject(err)
 			return
 		}
 
-		resolve(ok)
+		resolve(newValue)
 	}()
 
 	return promise
 }
 
-// Hget returns the value associated with `field` in the hash stored at `key`.
+// Hscan iterates over the fields of the hash stored at `key`, starting
+// from `cursor`: 0 to start a new iteration, or the cursor returned by the
+// previous call. The `match` and `count` options are passed to HSCAN as the
+// MATCH and COUNT arguments.
 //
-// If the hash does not exist, this command rejects the promise with an error.
-func (c *Client) Hget(key, field string) *sobek.Promise {
-	promise, resolve, reject := promises.New(c.vu)
+// The promise resolves with an object holding the `cursor` to continue the
+// iteration from, as a string, which is "0" once the iteration is
+// complete, and the `entries` read, mapping fields to their values.
+func (c *Client) Hscan(key string, cursor interface{}, options map[string]interface{}) *sobek.Promise {
+	return c.scanElements("hscan", cursor, options, func(ctx context.Context, cursor uint64, opts elementScanOptions) *redis.ScanCmd {
+		return c.redisClient.HScan(ctx, key, cursor, opts.Match, opts.Count)
+	}, func(elements []string) (string, interface{}, error) {
+		return "entries", hashEntries(elements), nil
+	})
+}
+
+// hrandfieldOptions holds the options of the Client's hrandfield method.
+type hrandfieldOptions struct {
+	// Count is the number of fields returned. When negative, the same field
+	// may be returned several times.
+	Count *int `json:"count,omitempty"`
+
+	// WithValues returns the fields along with their value.
+	WithValues bool `json:"withValues,omitempty"`
+}
+
+// Hrandfield returns random fields of the hash stored at `key`: one by
+// default, or up to `count` distinct fields, or exactly `-count` possibly
+// repeated fields when `count` is negative.
+//
+// The promise resolves with an array of fields, or, with the `withValues`
+// option set, of {field, value} objects. The array is empty if the hash
+// does not exist.
+func (c *Client) Hrandfield(key string, options map[string]interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
 
 	if err := c.connect(); err != nil {
 		reject(err)
 		return promise
 	}
 
+	var opts hrandfieldOptions
+	if err := decodeOptions(options, &opts); err != nil {
+		reject(fmt.Errorf("invalid hrandfield options; reason: %w", err))
+		return promise
+	}
+
+	count := 1
+	if opts.Count != nil {
+		count = *opts.Count
+	}
+
 	go func() {
-		value, err := c.redisClient.HGet(c.vu.Context(), key, field).Result()
+		if opts.WithValues {
+			pairs, err := c.redisClient.HRandFieldWithValues(c.context(), key, count).Result()
+			if err != nil {
+				reject(err)
+				return
+			}
+
+			entries := make([]map[string]interface{}, len(pairs))
+			for idx, pair := range pairs {
+				entries[idx] = map[string]interface{}{"field": pair.Key, "value": pair.Value}
+			}
+
+			resolve(entries)
+			return
+		}
+
+		fields, err := c.redisClient.HRandField(c.context(), key, count).Result()
 		if err != nil {
 			reject(err)
 			return
 		}
 
-		resolve(value)
+		resolve(fields)
 	}()
 
 	return promise
 }
 
-// Hdel deletes the specified fields from the hash stored at `key`.
-func (c *Client) Hdel(key string, fields ...string) *sobek.Promise {
-	promise, resolve, reject := promises.New(c.vu)
+// hashFieldValues returns the field/value pairs of the provided object,
+// sorted by field, so that the commands sent are deterministic. Values can
+// be binary: ArrayBuffer or Uint8Array.
+func (c *Client) hashFieldValues(obj map[string]interface{}) ([][2]interface{}, error) {
+	if len(obj) == 0 {
+		return nil, errors.New("at least one field must be provided")
+	}
+
+	fields := make([]string, 0, len(obj))
+	for field := range obj {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	pairs := make([][2]interface{}, len(fields))
+	for idx, field := range fields {
+		values, err := c.binaryArgs(1, obj[field])
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for field %q; reason: %w", field, err)
+		}
+
+		pairs[idx] = [2]interface{}{field, values[0]}
+	}
+
+	return pairs, nil
+}
+
+// Sadd adds the specified members to the set stored at key.
+// Specified members that are already a member of this set are ignored.
+// If key does not exist, a new set is created before adding the specified members.
+//
+// Members can be binary: ArrayBuffer or Uint8Array. Calls exceeding the
+// commandChunkSize option are split in pipelined chunks.
+func (c *Client) Sadd(key string, members ...interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
 
 	if err := c.connect(); err != nil {
 		reject(err)
 		return promise
 	}
 
+	memberArgs, err := c.binaryArgs(1, members...)
+	if err != nil {
+		reject(err)
+		return promise
+	}
+
 	go func() {
-		n, err := c.redisClient.HDel(c.vu.Context(), key, fields...).Result()
+		ctx := c.context()
+		n, err := sumChunks(ctx, c, memberArgs, func(cmd redis.Cmdable, chunk []interface{}) *redis.IntCmd {
+			return cmd.SAdd(ctx, key, chunk...)
+		})
 		if err != nil {
 			reject(err)
 			return
@@ -735,59 +2088,75 @@ func (c *Client) Hdel(key string, fields ...string) *sobek.Promise {
 	return promise
 }
 
-// Hgetall returns all fields and values of the hash stored at `key`.
+// Srem removes the specified members from the set stored at key.
+// Specified members that are not a member of this set are ignored.
+// If key does not exist, it is treated as an empty set and this command returns 0.
 //
-// If the hash does not exist, this command rejects the promise with an error.
-func (c *Client) Hgetall(key string) *sobek.Promise {
-	promise, resolve, reject := promises.New(c.vu)
+// Members can be binary: ArrayBuffer or Uint8Array. Calls exceeding the
+// commandChunkSize option are split in pipelined chunks.
+func (c *Client) Srem(key string, members ...interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
 
 	if err := c.connect(); err != nil {
 		reject(err)
 		return promise
 	}
 
+	memberArgs, err := c.binaryArgs(1, members...)
+	if err != nil {
+		reject(err)
+		return promise
+	}
+
 	go func() {
-		hashMap, err := c.redisClient.HGetAll(c.vu.Context(), key).Result()
+		ctx := c.context()
+		n, err := sumChunks(ctx, c, memberArgs, func(cmd redis.Cmdable, chunk []interface{}) *redis.IntCmd {
+			return cmd.SRem(ctx, key, chunk...)
+		})
 		if err != nil {
 			reject(err)
 			return
 		}
 
-		resolve(hashMap)
+		resolve(n)
 	}()
 
 	return promise
 }
 
-// Hkeys returns all fields of the hash stored at `key`.
+// Sismember returns if member is a member of the set stored at key.
 //
-// If the hash does not exist, this command rejects the promise with an error.
-func (c *Client) Hkeys(key string) *sobek.Promise {
-	promise, resolve, reject := promises.New(c.vu)
+// `member` can be binary: ArrayBuffer or Uint8Array.
+func (c *Client) Sismember(key string, member interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
 
 	if err := c.connect(); err != nil {
 		reject(err)
 		return promise
 	}
 
+	memberArgs, err := c.binaryArgs(1, member)
+	if err != nil {
+		reject(err)
+		return promise
+	}
+
 	go func() {
-		keys, err := c.redisClient.HKeys(c.vu.Context(), key).Result()
+		ok, err := c.redisClient.SIsMember(c.context(), key, memberArgs[0]).Result()
 		if err != nil {
 			reject(err)
 			return
 		}
 
-		resolve(keys)
+		resolve(ok)
 	}()
 
 	return promise
 }
 
-// Hvals returns all values of the hash stored at `key`.
-//
-// If the hash does not exist, this command rejects the promise with an error.
-func (c *Client) Hvals(key string) *sobek.Promise {
-	promise, resolve, reject := promises.New(c.vu)
+// Smembers returns all members of the set stored at key.
+func (c *Client) Smembers(key string) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
 
 	if err := c.connect(); err != nil {
 		reject(err)
@@ -795,23 +2164,23 @@ func (c *Client) Hvals(key string) *sobek.Promise {
 	}
 
 	go func() {
-		values, err := c.redisClient.HVals(c.vu.Context(), key).Result()
+		members, err := c.redisClient.SMembers(c.context(), key).Result()
 		if err != nil {
 			reject(err)
 			return
 		}
 
-		resolve(values)
+		resolve(members)
 	}()
 
 	return promise
 }
 
-// Hlen returns the number of fields in the hash stored at `key`.
+// Srandmember returns a random element from the set value stored at key.
 //
-// If the hash does not exist, this command rejects the promise with an error.
-func (c *Client) Hlen(key string) *sobek.Promise {
-	promise, resolve, reject := promises.New(c.vu)
+// If the set does not exist, the promise is rejected with an error.
+func (c *Client) Srandmember(key string) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
 
 	if err := c.connect(); err != nil {
 		reject(err)
@@ -819,24 +2188,23 @@ func (c *Client) Hlen(key string) *sobek.Promise {
 	}
 
 	go func() {
-		n, err := c.redisClient.HLen(c.vu.Context(), key).Result()
+		element, err := c.redisClient.SRandMember(c.context(), key).Result()
 		if err != nil {
 			reject(err)
 			return
 		}
 
-		resolve(n)
+		resolve(element)
 	}()
 
 	return promise
 }
 
-// Hincrby increments the integer value of `field` in the hash stored at `key`
-// by `increment`. If `key` does not exist, a new key holding a hash is created.
-// If `field` does not exist the value is set to 0 before the operation is
-// set to 0 before the operation is performed.
-func (c *Client) Hincrby(key, field string, increment int64) *sobek.Promise {
-	promise, resolve, reject := promises.New(c.vu)
+// Spop removes and returns a random element from the set value stored at key.
+//
+// If the set does not exist, the promise is rejected with an error.
+func (c *Client) Spop(key string) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
 
 	if err := c.connect(); err != nil {
 		reject(err)
@@ -844,36 +2212,30 @@ func (c *Client) Hincrby(key, field string, increment int64) *sobek.Promise {
 	}
 
 	go func() {
-		newValue, err := c.redisClient.HIncrBy(c.vu.Context(), key, field, increment).Result()
+		element, err := c.redisClient.SPop(c.context(), key).Result()
 		if err != nil {
 			reject(err)
 			return
 		}
 
-		resolve(newValue)
+		resolve(element)
 	}()
 
 	return promise
 }
 
-// Sadd adds the specified members to the set stored at key.
-// Specified members that are already a member of this set are ignored.
-// If key does not exist, a new set is created before adding the specified members.
-func (c *Client) Sadd(key string, members ...interface{}) *sobek.Promise {
-	promise, resolve, reject := promises.New(c.vu)
+// Scard returns the number of members of the set stored at `key`, or 0 if
+// it doesn't exist.
+func (c *Client) Scard(key string) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
 
 	if err := c.connect(); err != nil {
 		reject(err)
 		return promise
 	}
 
-	if err := c.isSupportedType(1, members...); err != nil {
-		reject(err)
-		return promise
-	}
-
 	go func() {
-		n, err := c.redisClient.SAdd(c.vu.Context(), key, members...).Result()
+		n, err := c.redisClient.SCard(c.context(), key).Result()
 		if err != nil {
 			reject(err)
 			return
@@ -885,73 +2247,113 @@ func (c *Client) Sadd(key string, members ...interface{}) *sobek.Promise {
 	return promise
 }
 
-// Srem removes the specified members from the set stored at key.
-// Specified members that are not a member of this set are ignored.
-// If key does not exist, it is treated as an empty set and this command returns 0.
-func (c *Client) Srem(key string, members ...interface{}) *sobek.Promise {
-	promise, resolve, reject := promises.New(c.vu)
+// Smismember returns whether each of `members` is a member of the set
+// stored at `key`.
+//
+// Members can be binary: ArrayBuffer or Uint8Array.
+func (c *Client) Smismember(key string, members ...interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
 
 	if err := c.connect(); err != nil {
 		reject(err)
 		return promise
 	}
 
-	if err := c.isSupportedType(1, members...); err != nil {
+	if len(members) == 0 {
+		reject(errors.New("at least one member must be provided to smismember"))
+		return promise
+	}
+
+	memberArgs, err := c.binaryArgs(1, members...)
+	if err != nil {
 		reject(err)
 		return promise
 	}
 
 	go func() {
-		n, err := c.redisClient.SRem(c.vu.Context(), key, members...).Result()
+		found, err := c.redisClient.SMIsMember(c.context(), key, memberArgs...).Result()
 		if err != nil {
 			reject(err)
 			return
 		}
 
-		resolve(n)
+		resolve(found)
 	}()
 
 	return promise
 }
 
-// Sismember returns if member is a member of the set stored at key.
-func (c *Client) Sismember(key string, member interface{}) *sobek.Promise {
-	promise, resolve, reject := promises.New(c.vu)
+// Smove moves `member` from the set stored at `source` to the set stored at
+// `destination`.
+//
+// The promise resolves with true if the member was moved, or false if it
+// isn't a member of `source`. `member` can be binary: ArrayBuffer or
+// Uint8Array.
+func (c *Client) Smove(source, destination string, member interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
 
 	if err := c.connect(); err != nil {
 		reject(err)
 		return promise
 	}
 
-	if err := c.isSupportedType(1, member); err != nil {
+	memberArgs, err := c.binaryArgs(1, member)
+	if err != nil {
 		reject(err)
 		return promise
 	}
 
 	go func() {
-		ok, err := c.redisClient.SIsMember(c.vu.Context(), key, member).Result()
+		moved, err := c.redisClient.SMove(c.context(), source, destination, memberArgs[0]).Result()
 		if err != nil {
 			reject(err)
 			return
 		}
 
-		resolve(ok)
+		resolve(moved)
 	}()
 
 	return promise
 }
 
-// Smembers returns all members of the set stored at key.
-func (c *Client) Smembers(key string) *sobek.Promise {
-	promise, resolve, reject := promises.New(c.vu)
+// Sinter returns the members of the intersection of the sets stored at
+// `keys`.
+func (c *Client) Sinter(keys []string) *sobek.Promise {
+	return c.setOperation("sinter", keys, redis.UniversalClient.SInter)
+}
+
+// Sunion returns the members of the union of the sets stored at `keys`.
+func (c *Client) Sunion(keys []string) *sobek.Promise {
+	return c.setOperation("sunion", keys, redis.UniversalClient.SUnion)
+}
+
+// Sdiff returns the members of the set stored at the first of `keys` that
+// aren't members of the sets stored at the other ones.
+func (c *Client) Sdiff(keys []string) *sobek.Promise {
+	return c.setOperation("sdiff", keys, redis.UniversalClient.SDiff)
+}
+
+// setOperation implements Sinter, Sunion, and Sdiff, using the provided
+// go-redis `operation` method.
+func (c *Client) setOperation(
+	command string,
+	keys []string,
+	operation func(client redis.UniversalClient, ctx context.Context, keys ...string) *redis.StringSliceCmd,
+) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
 
 	if err := c.connect(); err != nil {
 		reject(err)
 		return promise
 	}
 
+	if len(keys) == 0 {
+		reject(fmt.Errorf("at least one key must be provided to %s", command))
+		return promise
+	}
+
 	go func() {
-		members, err := c.redisClient.SMembers(c.vu.Context(), key).Result()
+		members, err := operation(c.redisClient, c.context(), keys...).Result()
 		if err != nil {
 			reject(err)
 			return
@@ -963,74 +2365,153 @@ func (c *Client) Smembers(key string) *sobek.Promise {
 	return promise
 }
 
-// Srandmember returns a random element from the set value stored at key.
+// Sinterstore stores the intersection of the sets stored at `keys` in the
+// set stored at `destination`, overwriting it, so that it is computed
+// server-side, without transferring it.
 //
-// If the set does not exist, the promise is rejected with an error.
-func (c *Client) Srandmember(key string) *sobek.Promise {
-	promise, resolve, reject := promises.New(c.vu)
+// The promise resolves with the number of members of the resulting set.
+func (c *Client) Sinterstore(destination string, keys []string) *sobek.Promise {
+	return c.setOperationStore("sinterstore", destination, keys, redis.UniversalClient.SInterStore)
+}
+
+// Sunionstore stores the union of the sets stored at `keys` in the set
+// stored at `destination`, as Sinterstore does.
+func (c *Client) Sunionstore(destination string, keys []string) *sobek.Promise {
+	return c.setOperationStore("sunionstore", destination, keys, redis.UniversalClient.SUnionStore)
+}
+
+// Sdiffstore stores the difference of the sets stored at `keys`, as
+// returned by Sdiff, in the set stored at `destination`, as Sinterstore
+// does.
+func (c *Client) Sdiffstore(destination string, keys []string) *sobek.Promise {
+	return c.setOperationStore("sdiffstore", destination, keys, redis.UniversalClient.SDiffStore)
+}
+
+// setOperationStore implements Sinterstore, Sunionstore, and Sdiffstore,
+// using the provided go-redis `store` method.
+func (c *Client) setOperationStore(
+	command string,
+	destination string,
+	keys []string,
+	store func(client redis.UniversalClient, ctx context.Context, destination string, keys ...string) *redis.IntCmd,
+) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
 
 	if err := c.connect(); err != nil {
 		reject(err)
 		return promise
 	}
 
+	if len(keys) == 0 {
+		reject(fmt.Errorf("at least one key must be provided to %s", command))
+		return promise
+	}
+
 	go func() {
-		element, err := c.redisClient.SRandMember(c.vu.Context(), key).Result()
+		n, err := store(c.redisClient, c.context(), destination, keys...).Result()
 		if err != nil {
 			reject(err)
 			return
 		}
 
-		resolve(element)
+		resolve(n)
 	}()
 
 	return promise
 }
 
-// Spop removes and returns a random element from the set value stored at key.
-//
-// If the set does not exist, the promise is rejected with an error.
-func (c *Client) Spop(key string) *sobek.Promise {
-	promise, resolve, reject := promises.New(c.vu)
+// Sintercard returns the number of members of the intersection of the sets
+// stored at `keys`, without transferring it. With a positive `limit`, the
+// computation stops once the intersection reaches that many members. It
+// requires Redis 7.
+func (c *Client) Sintercard(keys []string, limit ...int64) *sobek.Promise {
+	return c.intercard("sintercard", keys, limit, redis.UniversalClient.SInterCard)
+}
+
+// intercard implements Sintercard and Zintercard, using the provided
+// go-redis `intercard` method.
+func (c *Client) intercard(
+	command string,
+	keys []string,
+	limit []int64,
+	intercard func(client redis.UniversalClient, ctx context.Context, limit int64, keys ...string) *redis.IntCmd,
+) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
 
 	if err := c.connect(); err != nil {
 		reject(err)
 		return promise
 	}
 
+	if len(keys) == 0 {
+		reject(fmt.Errorf("at least one key must be provided to %s", command))
+		return promise
+	}
+
+	n, err := intercardLimit(command, limit)
+	if err != nil {
+		reject(err)
+		return promise
+	}
+
 	go func() {
-		element, err := c.redisClient.SPop(c.vu.Context(), key).Result()
+		count, err := intercard(c.redisClient, c.context(), n, keys...).Result()
 		if err != nil {
 			reject(err)
 			return
 		}
 
-		resolve(element)
+		resolve(count)
 	}()
 
 	return promise
 }
 
+// intercardLimit returns the limit of the `command` intercard command,
+// SINTERCARD or ZINTERCARD, from its optional `limit` argument, zero
+// standing for no limit.
+func intercardLimit(command string, limit []int64) (int64, error) {
+	switch {
+	case len(limit) > 1:
+		return 0, fmt.Errorf("%s accepts a single limit; got %d", command, len(limit))
+	case len(limit) == 1 && limit[0] < 0:
+		return 0, fmt.Errorf("invalid %s limit %d; expected a non-negative number", command, limit[0])
+	case len(limit) == 1:
+		return limit[0], nil
+	default:
+		return 0, nil
+	}
+}
+
 // SendCommand sends a command to the redis server.
+//
+// It allows using any command, such as the ones of Redis modules, or of
+// newer servers, without waiting for the client to support them. The
+// arguments can be binary: ArrayBuffer or Uint8Array. The promise resolves
+// with the raw reply: arrays resolve as JS arrays, and nil replies as null.
 func (c *Client) SendCommand(command string, args ...interface{}) *sobek.Promise {
-	var doArgs []interface{}
-	doArgs = append(doArgs, command)
-	doArgs = append(doArgs, args...)
-
-	promise, resolve, reject := promises.New(c.vu)
+	promise, resolve, reject := c.newPromise()
 
 	if err := c.connect(); err != nil {
 		reject(err)
 		return promise
 	}
 
-	if err := c.isSupportedType(1, args...); err != nil {
+	cmdArgs, err := c.binaryArgs(1, args...)
+	if err != nil {
 		reject(err)
 		return promise
 	}
 
+	doArgs := append([]interface{}{command}, cmdArgs...)
+
 	go func() {
-		cmd, err := c.redisClient.Do(c.vu.Context(), doArgs...).Result()
+		cmd, err := c.redisClient.Do(c.context(), doArgs...).Result()
+		if errors.Is(err, redis.Nil) {
+			resolve(nil)
+			return
+		}
+
 		if err != nil {
 			reject(err)
 			return
@@ -1060,13 +2541,24 @@ func (c *Client) connect() error {
 		return nil
 	}
 
+	// k6's dialer only dials host and port addresses, and applies the
+	// options of the test, such as blocked hostnames, which don't apply
+	// to Unix domain sockets.
+	var dialer lib.DialContexter = vuState.Dialer
+	switch c.redisOptions.DialNetwork {
+	case "unix":
+		dialer = &net.Dialer{}
+	case "tcp4", "tcp6":
+		dialer = newFamilyDialer(dialer, c.redisOptions.DialNetwork)
+	}
+
 	tlsCfg := c.redisOptions.TLSConfig
 	if tlsCfg != nil && vuState.TLSConfig != nil {
 		// Merge k6 TLS configuration with the one we received from the
 		// Client constructor. This will need adjusting depending on which
 		// options we want to expose in the Redis module, and how we want
 		// the override to work.
-		tlsCfg.InsecureSkipVerify = vuState.TLSConfig.InsecureSkipVerify
+		tlsCfg.InsecureSkipVerify = tlsCfg.InsecureSkipVerify || vuState.TLSConfig.InsecureSkipVerify
 		tlsCfg.CipherSuites = vuState.TLSConfig.CipherSuites
 		tlsCfg.MinVersion = vuState.TLSConfig.MinVersion
 		tlsCfg.MaxVersion = vuState.TLSConfig.MaxVersion
@@ -1088,14 +2580,15 @@ func (c *Client) connect() error {
 		// See Pull Request's #17 [discussion] for more details.
 		//
 		// [discussion]: https://github.com/grafana/xk6-redis/pull/17#discussion_r1369707388
-		c.redisOptions.Dialer = c.upgradeDialerToTLS(vuState.Dialer, tlsCfg)
+		c.redisOptions.Dialer = c.upgradeDialerToTLS(dialer, tlsCfg)
 	} else {
-		c.redisOptions.Dialer = vuState.Dialer.DialContext
+		c.redisOptions.Dialer = dialer.DialContext
 	}
 
 	// Replace the internal redis client instance with a new
 	// one using our custom options.
 	c.redisClient = c.getRedisClient(c.redisOptions)
+	c.ownsRedisClient = true
 
 	return nil
 }
@@ -1133,6 +2626,35 @@ func (c *Client) isSupportedType(offset int, args ...interface{}) error {
 	return nil
 }
 
+// flattenKeys returns the provided keys as strings, expanding the arrays
+// of keys. Numbers are formatted the way JS would coerce them to strings.
+func flattenKeys(keys []interface{}) ([]string, error) {
+	flattened := make([]string, 0, len(keys))
+	for idx, key := range keys {
+		switch v := key.(type) {
+		case string:
+			flattened = append(flattened, v)
+		case int64, float64:
+			flattened = append(flattened, fmt.Sprint(v))
+		case []string:
+			flattened = append(flattened, v...)
+		case []interface{}:
+			for _, item := range v {
+				s, ok := item.(string)
+				if !ok {
+					return nil, fmt.Errorf("the array at index %d holds a non-string key", idx)
+				}
+				flattened = append(flattened, s)
+			}
+		default:
+			return nil, fmt.Errorf(
+				"unsupported type provided for key at index %d, supported types are string, number, and array of strings", idx)
+		}
+	}
+
+	return flattened, nil
+}
+
 // DialContextFunc is a function that can be used to dial a connection to a redis server.
 type DialContextFunc func(ctx context.Context, network, addr string) (net.Conn, error)
 
@@ -4,7 +4,10 @@ import (
 	"crypto/tls"
 	"errors"
 	"fmt"
+	"io"
 	"net"
+	"os"
+	"path/filepath"
 	"strconv"
 	"testing"
 
@@ -108,10 +111,55 @@ func TestClientConstructor(t *testing.T) {
 				sentinelPassword: 'sentinelpass',
 			}`,
 		},
+		{
+			name: "ok/object/sentinel_read_preference",
+			arg: `{
+				socket: {
+					host: 'localhost',
+					port: 6379,
+				},
+				masterName: 'masterhost',
+				readPreference: 'preferReplica',
+			}`,
+		},
+		{
+			name: "ok/object/sentinel_replica_reads",
+			arg: `{
+				socket: {
+					host: 'localhost',
+					port: 6379,
+				},
+				masterName: 'masterhost',
+				readPreference: 'replica',
+				writeToMaster: false,
+			}`,
+		},
+		{
+			name: "ok/object/sentinel_replica_only",
+			arg: `{
+				socket: {
+					host: 'localhost',
+					port: 6379,
+				},
+				masterName: 'masterhost',
+				replicaOnly: true,
+				useDisconnectedReplicas: true,
+				writeToMaster: false,
+			}`,
+		},
+		{
+			name: "ok/object/cluster_read_preference",
+			arg: `{
+				readPreference: 'replica',
+				cluster: {
+					nodes: ['redis://host1:6379', 'redis://host2:6379']
+				}
+			}`,
+		},
 		{
 			name:   "err/empty",
 			arg:    "",
-			expErr: "must specify one argument",
+			expErr: "no options provided, and the REDIS_URL environment variable is not set",
 		},
 		{
 			name:   "err/url/missing_scheme",
@@ -189,6 +237,190 @@ func TestClientConstructor(t *testing.T) {
 			}`,
 			expErr: `invalid options; reason: inconsistent username option: user1 != user2`,
 		},
+		{
+			name: "err/object/invalid_read_preference",
+			arg: `{
+				socket: {
+					host: 'localhost',
+					port: 6379,
+				},
+				masterName: 'masterhost',
+				readPreference: 'nearest',
+			}`,
+			expErr: `invalid options; reason: invalid readPreference option: "nearest"`,
+		},
+		{
+			name: "err/object/single_read_preference",
+			arg: `{
+				socket: {
+					host: 'localhost',
+					port: 6379,
+				},
+				readPreference: 'replica',
+			}`,
+			expErr: `invalid options; reason: the replica readPreference option is only supported in cluster and sentinel modes`,
+		},
+		{
+			name: "err/object/sentinel_replica_reads_write_to_master",
+			arg: `{
+				socket: {
+					host: 'localhost',
+					port: 6379,
+				},
+				masterName: 'masterhost',
+				readPreference: 'replica',
+			}`,
+			expErr: `invalid options; reason: the replica readPreference option requires writeToMaster to be false`,
+		},
+		{
+			name: "err/object/sentinel_replica_only_write_to_master",
+			arg: `{
+				socket: {
+					host: 'localhost',
+					port: 6379,
+				},
+				masterName: 'masterhost',
+				replicaOnly: true,
+			}`,
+			expErr: `invalid options; reason: the replicaOnly option requires writeToMaster to be false`,
+		},
+		{
+			name: "err/object/negative_max_commands_per_second",
+			arg: `{
+				socket: {
+					host: 'localhost',
+					port: 6379,
+				},
+				maxCommandsPerSecond: -1,
+			}`,
+			expErr: `invalid options; reason: invalid maxCommandsPerSecond option: -1`,
+		},
+		{
+			name: "err/object/negative_command_chunk_size",
+			arg: `{
+				socket: {
+					host: 'localhost',
+					port: 6379,
+				},
+				commandChunkSize: -1,
+			}`,
+			expErr: `invalid options; reason: invalid commandChunkSize option: -1`,
+		},
+		{
+			name: "err/object/negative_reconnect_jitter",
+			arg: `{
+				socket: {
+					host: 'localhost',
+					port: 6379,
+				},
+				reconnectJitterMs: -1,
+			}`,
+			expErr: `invalid options; reason: invalid reconnectJitterMs option: -1`,
+		},
+		{
+			name: "err/object/invalid_dial_network",
+			arg: `{
+				socket: {
+					host: 'localhost',
+					port: 6379,
+				},
+				dialNetwork: 'udp',
+			}`,
+			expErr: `invalid options; reason: invalid dialNetwork option: "udp"`,
+		},
+		{
+			name: "err/object/invalid_compression",
+			arg: `{
+				socket: {
+					host: 'localhost',
+					port: 6379,
+				},
+				compression: 'zstd',
+			}`,
+			expErr: `invalid options; reason: invalid compression option: "zstd"`,
+		},
+		{
+			name: "err/object/invalid_return_types",
+			arg: `{
+				socket: {
+					host: 'localhost',
+					port: 6379,
+				},
+				returnTypes: 'number',
+			}`,
+			expErr: `invalid options; reason: invalid returnTypes option: "number"`,
+		},
+		{
+			name: "err/object/negative_log_value_length",
+			arg: `{
+				socket: {
+					host: 'localhost',
+					port: 6379,
+				},
+				logCommands: true,
+				logValueLength: -1,
+			}`,
+			expErr: `invalid options; reason: invalid logValueLength option: -1`,
+		},
+		{
+			name: "err/object/empty_blocked_command",
+			arg: `{
+				socket: {
+					host: 'localhost',
+					port: 6379,
+				},
+				blockedCommands: ['keys', ' '],
+			}`,
+			expErr: `invalid options; reason: invalid blockedCommands option; command names must not be empty`,
+		},
+		{
+			name: "err/object/tls_cert_without_key",
+			arg: `{
+				socket: {
+					host: 'localhost',
+					port: 6379,
+					tls: {
+						cert: 'cert',
+					},
+				},
+			}`,
+			expErr: "invalid options; reason: the tls cert and key options must be set together",
+		},
+		{
+			name: "err/object/pool_aliased_options",
+			arg: `{
+				socket: {
+					host: 'localhost',
+					port: 6379,
+					idleTimeout: 1000,
+					connMaxIdleTime: 2000,
+				},
+			}`,
+			expErr: "invalid options; reason: the connMaxIdleTime and idleTimeout options are aliases, and cannot be set together",
+		},
+		{
+			name: "err/object/pool_min_idle_exceeds_max_idle",
+			arg: `{
+				socket: {
+					host: 'localhost',
+					port: 6379,
+					minIdleConns: 4,
+					maxIdleConns: 2,
+				},
+			}`,
+			expErr: "invalid options; reason: the minIdleConns option (4) cannot exceed the maxIdleConns option (2)",
+		},
+		{
+			name: "err/object/cluster_read_preference_conflict",
+			arg: `{
+				readPreference: 'preferReplica',
+				cluster: {
+					routeRandomly: true,
+					nodes: ['redis://host1:6379', 'redis://host2:6379']
+				}
+			}`,
+			expErr: `invalid options; reason: the readPreference option cannot be combined with`,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -264,6 +496,61 @@ func TestClientSet(t *testing.T) {
 	}, rs.GotCommands())
 }
 
+func TestClientSetOptions(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunT(t)
+	rs.RegisterCommandHandler("SET", func(c *Connection, args []string) {
+		switch args[0] {
+		case "lock":
+			c.WriteNull()
+		case "previous":
+			c.WriteBulkString("old_value")
+		default:
+			c.WriteOK()
+		}
+	})
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client('redis://%s');
+
+			redis.set("lock", "owner", { nx: true, px: 3000 })
+				.then(res => { if (res !== null) { throw 'unexpected value for set result: ' + res } })
+				.then(() => redis.set("previous", "new_value", { get: true, keepTtl: true }))
+				.then(res => { if (res !== "old_value") { throw 'unexpected value for set result: ' + res } })
+				.then(() => redis.set("session", "value", { xx: true, exat: 1700000000 }))
+				.then(res => { if (res !== "OK") { throw 'unexpected value for set result: ' + res } })
+				.then(() => redis.set("session", "value", { ex: 10, px: 10000 }))
+				.then(
+					res => { throw 'expected set to fail' },
+					err => { if (!err.error().includes('mutually exclusive')) { throw 'unexpected error: ' + err.error() } }
+				)
+				.then(() => redis.set("session", "value", { nx: true, xx: true }))
+				.then(
+					res => { throw 'expected set to fail' },
+					err => { if (!err.error().includes('mutually exclusive')) { throw 'unexpected error: ' + err.error() } }
+				)
+				.then(() => redis.set("session", "value", { ttl: 10 }))
+				.then(
+					res => { throw 'expected set to fail' },
+					err => { if (!err.error().startsWith('invalid set options')) { throw 'unexpected error: ' + err.error() } }
+				)
+		`, rs.Addr()))
+
+		return err
+	})
+
+	assert.NoError(t, gotScriptErr)
+	assert.Equal(t, [][]string{
+		{"HELLO", "2"},
+		{"SET", "lock", "owner", "px", "3000", "nx"},
+		{"SET", "previous", "new_value", "keepttl", "get"},
+		{"SET", "session", "value", "exat", "1700000000", "xx"},
+	}, rs.GotCommands())
+}
+
 func TestClientGet(t *testing.T) {
 	t.Parallel()
 
@@ -451,16 +738,62 @@ func TestClientExists(t *testing.T) {
 
 			redis.exists("existing_key", "nonexisting_key")
 				.then(res => { if (res !== 1) { throw 'unexpected value for exists result: ' + res } })
+				.then(() => redis.exists(["existing_key", "nonexisting_key"], "other_key"))
+				.then(res => { if (res !== 1) { throw 'unexpected value for exists result: ' + res } })
 		`, rs.Addr()))
 
 		return err
 	})
 
 	assert.NoError(t, gotScriptErr)
-	assert.Equal(t, 1, rs.HandledCommandsCount())
+	assert.Equal(t, 2, rs.HandledCommandsCount())
 	assert.Equal(t, [][]string{
 		{"HELLO", "2"},
 		{"EXISTS", "existing_key", "nonexisting_key"},
+		{"EXISTS", "existing_key", "nonexisting_key", "other_key"},
+	}, rs.GotCommands())
+}
+
+func TestClientUnlinkAndTouch(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunT(t)
+	rs.RegisterCommandHandler("UNLINK", func(c *Connection, args []string) {
+		c.WriteInteger(len(args))
+	})
+	rs.RegisterCommandHandler("TOUCH", func(c *Connection, args []string) {
+		c.WriteInteger(len(args) - 1)
+	})
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client('redis://%s');
+
+			redis.unlink(["key1", "key2"], "key3")
+				.then(res => { if (res !== 3) { throw 'unexpected value for unlink result: ' + res } })
+				.then(() => redis.touch("key1", "key2"))
+				.then(res => { if (res !== 1) { throw 'unexpected value for touch result: ' + res } })
+				.then(() => redis.unlink([]))
+				.then(
+					res => { throw 'expected unlink to fail without keys' },
+					err => { if (err.error() !== "unlink requires at least one key") { throw 'unexpected error: ' + err.error() } }
+				)
+				.then(() => redis.touch([1, 2]))
+				.then(
+					res => { throw 'expected touch to fail with non-string keys' },
+					err => { if (!err.error().includes("non-string key")) { throw 'unexpected error: ' + err.error() } }
+				)
+		`, rs.Addr()))
+
+		return err
+	})
+
+	assert.NoError(t, gotScriptErr)
+	assert.Equal(t, [][]string{
+		{"HELLO", "2"},
+		{"UNLINK", "key1", "key2", "key3"},
+		{"TOUCH", "key1", "key2"},
 	}, rs.GotCommands())
 }
 
@@ -721,16 +1054,42 @@ func TestClientMget(t *testing.T) {
 						}
 					}
 				)
+				.then(() => redis.mget(["existing_key", "non_existing_key"], { asObject: true }))
+				.then(res => {
+					if (Object.keys(res).length !== 2 || res.existing_key !== "old_value" || res.non_existing_key !== null) {
+						throw 'unexpected value for mget result: ' + JSON.stringify(res)
+					}
+				})
+				.then(() => redis.mget(["existing_key", "non_existing_key"], { asObject: true, omitNulls: true }))
+				.then(res => {
+					if (Object.keys(res).length !== 1 || res.existing_key !== "old_value") {
+						throw 'unexpected value for mget result: ' + JSON.stringify(res)
+					}
+				})
+				.then(() => redis.mget("existing_key", "non_existing_key", { omitNulls: true }))
+				.then(res => {
+					if (res.length !== 1 || res[0] !== "old_value") {
+						throw 'unexpected value for mget result: ' + JSON.stringify(res)
+					}
+				})
+				.then(() => redis.mget("existing_key", { asObject: "yes" }))
+				.then(
+					res => { throw 'expected mget to fail' },
+					err => { if (!err.error().startsWith('invalid mget options')) { throw 'unexpected error: ' + err.error() } }
+				)
 		`, rs.Addr()))
 
 		return err
 	})
 
 	assert.NoError(t, gotScriptErr)
-	assert.Equal(t, 1, rs.HandledCommandsCount())
+	assert.Equal(t, 4, rs.HandledCommandsCount())
 	assert.Equal(t, [][]string{
 		{"HELLO", "2"},
 		{"MGET", "existing_key", "non_existing_key"},
+		{"MGET", "existing_key", "non_existing_key"},
+		{"MGET", "existing_key", "non_existing_key"},
+		{"MGET", "existing_key", "non_existing_key"},
 	}, rs.GotCommands())
 }
 
@@ -857,19 +1216,239 @@ func TestClientPersist(t *testing.T) {
 	}, rs.GotCommands())
 }
 
-func TestClientLPush(t *testing.T) {
+func TestClientExpirationVariants(t *testing.T) {
 	t.Parallel()
 
 	ts := newTestSetup(t)
 	rs := RunT(t)
-	rs.RegisterCommandHandler("LPUSH", func(c *Connection, args []string) {
-		if len(args) < 2 {
-			c.WriteError(errors.New("ERR unexpected number of arguments for 'LPUSH' command"))
+	for _, command := range []string{"EXPIRE", "PEXPIRE", "EXPIREAT"} {
+		rs.RegisterCommandHandler(command, func(c *Connection, args []string) {
+			if len(args) > 2 && args[2] == "nx" {
+				c.WriteInteger(0)
+				return
+			}
+
+			c.WriteInteger(1)
+		})
+	}
+	rs.RegisterCommandHandler("PTTL", func(c *Connection, args []string) {
+		if args[0] == "non_existing_key" {
+			c.WriteInteger(-2)
 			return
 		}
 
-		existingList := []string{"existing_key"}
-
+		c.WriteInteger(1500)
+	})
+	rs.RegisterCommandHandler("EXPIRETIME", func(c *Connection, _ []string) {
+		c.WriteInteger(-1)
+	})
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client('redis://%s');
+
+			redis.expire("expires_key", 10, { gt: true })
+				.then(res => { if (res !== true) { throw 'unexpected value for expire result: ' + res } })
+				.then(() => redis.pexpire("expires_key", 1500, { nx: true }))
+				.then(res => { if (res !== false) { throw 'unexpected value for pexpire result: ' + res } })
+				.then(() => redis.expireat("expires_key", 1700000000))
+				.then(res => { if (res !== true) { throw 'unexpected value for expireat result: ' + res } })
+				.then(() => redis.pttl("expires_key"))
+				.then(res => { if (res !== 1500) { throw 'unexpected value for pttl result: ' + res } })
+				.then(() => redis.pttl("non_existing_key"))
+				.then(res => { if (res !== -2) { throw 'unexpected value for pttl result: ' + res } })
+				.then(() => redis.expiretime("persistent_key"))
+				.then(res => { if (res !== -1) { throw 'unexpected value for expiretime result: ' + res } })
+				.then(() => redis.expire("expires_key", 10, { nx: true, xx: true }))
+				.then(
+					res => { throw 'expected expire to fail' },
+					err => { if (!err.error().includes('mutually exclusive')) { throw 'unexpected error: ' + err.error() } }
+				)
+		`, rs.Addr()))
+
+		return err
+	})
+
+	assert.NoError(t, gotScriptErr)
+	assert.Equal(t, [][]string{
+		{"HELLO", "2"},
+		{"EXPIRE", "expires_key", "10", "gt"},
+		{"PEXPIRE", "expires_key", "1500", "nx"},
+		{"EXPIREAT", "expires_key", "1700000000"},
+		{"PTTL", "expires_key"},
+		{"PTTL", "non_existing_key"},
+		{"EXPIRETIME", "persistent_key"},
+	}, rs.GotCommands())
+}
+
+func TestClientAppendLog(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunT(t)
+	rs.RegisterCommandHandler("APPEND", func(c *Connection, args []string) {
+		if len(args) != 2 {
+			c.WriteError(errors.New("ERR unexpected number of arguments for 'APPEND' command"))
+			return
+		}
+
+		switch args[0] {
+		case "existing_log":
+			c.WriteInteger(11 + len(args[1]))
+		case "non_existing_log":
+			c.WriteInteger(len(args[1]))
+		}
+	})
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client('redis://%s');
+
+			redis.appendLog("existing_log", "entry\n")
+				.then(res => { if (res !== 17) { throw 'unexpected value for appendLog result: ' + res } })
+				.then(() => redis.appendLog("non_existing_log", "entry\n"))
+				.then(res => { if (res !== 6) { throw 'unexpected value for appendLog result: ' + res } })
+		`, rs.Addr()))
+
+		return err
+	})
+
+	assert.NoError(t, gotScriptErr)
+	assert.Equal(t, 2, rs.HandledCommandsCount())
+	assert.Equal(t, [][]string{
+		{"HELLO", "2"},
+		{"APPEND", "existing_log", "entry\n"},
+		{"APPEND", "non_existing_log", "entry\n"},
+	}, rs.GotCommands())
+}
+
+func TestClientTailLog(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunT(t)
+	rs.RegisterCommandHandler("GETRANGE", func(c *Connection, args []string) {
+		if len(args) != 3 {
+			c.WriteError(errors.New("ERR unexpected number of arguments for 'GETRANGE' command"))
+			return
+		}
+
+		switch args[0] {
+		case "existing_log":
+			c.WriteBulkString("last entry")
+		case "non_existing_log":
+			c.WriteBulkString("")
+		}
+	})
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client('redis://%s');
+
+			redis.tailLog("existing_log", 10)
+				.then(res => { if (res !== "last entry") { throw 'unexpected value for tailLog result: ' + res } })
+				.then(() => redis.tailLog("non_existing_log", 10))
+				.then(res => { if (res !== "") { throw 'unexpected value for tailLog result: ' + res } })
+				.then(() => redis.tailLog("existing_log", 0))
+				.then(
+					res => { throw 'expected to fail tailing zero bytes' },
+					err => { if (!err.error().startsWith('invalid bytes count')) { throw 'unexpected error: ' + err } }
+				)
+		`, rs.Addr()))
+
+		return err
+	})
+
+	assert.NoError(t, gotScriptErr)
+	assert.Equal(t, 2, rs.HandledCommandsCount())
+	assert.Equal(t, [][]string{
+		{"HELLO", "2"},
+		{"GETRANGE", "existing_log", "-10", "-1"},
+		{"GETRANGE", "non_existing_log", "-10", "-1"},
+	}, rs.GotCommands())
+}
+
+func TestClientStringCommands(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunT(t)
+	rs.RegisterCommandHandler("APPEND", func(c *Connection, args []string) {
+		c.WriteInteger(len(args[1]))
+	})
+	rs.RegisterCommandHandler("STRLEN", func(c *Connection, _ []string) {
+		c.WriteInteger(5)
+	})
+	rs.RegisterCommandHandler("GETRANGE", func(c *Connection, _ []string) {
+		c.WriteBulkString("ell")
+	})
+	rs.RegisterCommandHandler("SETRANGE", func(c *Connection, _ []string) {
+		c.WriteInteger(11)
+	})
+	rs.RegisterCommandHandler("GETEX", func(c *Connection, args []string) {
+		if args[0] == "non_existing_key" {
+			c.WriteNull()
+			return
+		}
+		c.WriteBulkString("token")
+	})
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client('redis://%s');
+
+			redis.append("key", new Uint8Array([104, 105]))
+				.then(res => { if (res !== 2) { throw 'unexpected value for append result: ' + res } })
+				.then(() => redis.strlen("key"))
+				.then(res => { if (res !== 5) { throw 'unexpected value for strlen result: ' + res } })
+				.then(() => redis.getrange("key", 1, -2))
+				.then(res => { if (res !== "ell") { throw 'unexpected value for getrange result: ' + res } })
+				.then(() => redis.setrange("key", 6, "world"))
+				.then(res => { if (res !== 11) { throw 'unexpected value for setrange result: ' + res } })
+				.then(() => redis.getex("session", { px: 1500 }))
+				.then(res => { if (res !== "token") { throw 'unexpected value for getex result: ' + res } })
+				.then(() => redis.getex("session", { persist: true }))
+				.then(() => redis.getex("session", { ex: 10, persist: true }))
+				.then(
+					res => { throw 'expected getex to fail with conflicting options' },
+					err => { if (!err.error().includes('mutually exclusive')) { throw 'unexpected error: ' + err.error() } }
+				)
+				.then(() => redis.getex("non_existing_key"))
+				.then(
+					res => { throw 'expected getex to fail for a non-existing key' },
+					err => { if (err.error() !== 'redis: nil') { throw 'unexpected error: ' + err.error() } }
+				)
+		`, rs.Addr()))
+
+		return err
+	})
+
+	assert.NoError(t, gotScriptErr)
+	assert.Equal(t, [][]string{
+		{"HELLO", "2"},
+		{"APPEND", "key", "hi"},
+		{"STRLEN", "key"},
+		{"GETRANGE", "key", "1", "-2"},
+		{"SETRANGE", "key", "6", "world"},
+		{"GETEX", "session", "px", "1500"},
+		{"GETEX", "session", "persist"},
+		{"GETEX", "non_existing_key"},
+	}, rs.GotCommands())
+}
+
+func TestClientLPush(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunT(t)
+	rs.RegisterCommandHandler("LPUSH", func(c *Connection, args []string) {
+		if len(args) < 2 {
+			c.WriteError(errors.New("ERR unexpected number of arguments for 'LPUSH' command"))
+			return
+		}
+
+		existingList := []string{"existing_key"}
+
 		switch args[0] {
 		case "existing_list": //nolint:goconst
 			existingList = append(args[1:], existingList...)
@@ -1369,6 +1948,49 @@ func TestClientHSet(t *testing.T) {
 	}, rs.GotCommands())
 }
 
+func TestClientHSetObject(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunT(t)
+	rs.RegisterCommandHandler("HSET", func(c *Connection, args []string) {
+		c.WriteInteger(len(args[1:]) / 2)
+	})
+	rs.RegisterCommandHandler("HMSET", func(c *Connection, _ []string) {
+		c.WriteOK()
+	})
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client('redis://%s');
+
+			redis.hset("session", { user: "alice", visits: 3 })
+				.then(res => { if (res !== 2) { throw 'unexpected value for hset result: ' + res } })
+				.then(() => redis.hmset("session", { user: "bob", cart: "empty" }))
+				.then(res => { if (res !== "OK") { throw 'unexpected value for hmset result: ' + res } })
+				.then(() => redis.hmset("session", {}))
+				.then(
+					res => { throw 'expected hmset to fail' },
+					err => { if (!err.error().includes('at least one field')) { throw 'unexpected error: ' + err.error() } }
+				)
+				.then(() => redis.hset("session", { user: {} }))
+				.then(
+					res => { throw 'expected hset to fail' },
+					err => { if (!err.error().startsWith('invalid value for field "user"')) { throw 'unexpected error: ' + err.error() } }
+				)
+			`, rs.Addr()))
+
+		return err
+	})
+
+	assert.NoError(t, gotScriptErr)
+	assert.Equal(t, [][]string{
+		{"HELLO", "2"},
+		{"HSET", "session", "user", "alice", "visits", "3"},
+		{"HMSET", "session", "cart", "empty", "user", "bob"},
+	}, rs.GotCommands())
+}
+
 func TestClientHsetnx(t *testing.T) {
 	t.Parallel()
 
@@ -1737,6 +2359,94 @@ func TestClientHincrby(t *testing.T) {
 	}, rs.GotCommands())
 }
 
+func TestClientHscan(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunT(t)
+	rs.RegisterCommandHandler("HSCAN", func(c *Connection, args []string) {
+		if args[1] == "0" {
+			c.WriteValue([]interface{}{"18446744073709551615", []interface{}{"user", "alice"}})
+			return
+		}
+
+		c.WriteValue([]interface{}{"0", []interface{}{"visits", "3"}})
+	})
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client('redis://%s');
+
+			redis.hscan("session", 0, { match: "*", count: 10 })
+				.then(res => {
+					if (res.cursor !== "18446744073709551615" || res.entries.user !== "alice") {
+						throw 'unexpected value for hscan result: ' + JSON.stringify(res)
+					}
+
+					return redis.hscan("session", res.cursor)
+				})
+				.then(res => {
+					if (res.cursor !== "0" || res.entries.visits !== "3") {
+						throw 'unexpected value for hscan result: ' + JSON.stringify(res)
+					}
+				})
+				.then(() => redis.hscan("session", "nope"))
+				.then(
+					res => { throw 'expected hscan to fail' },
+					err => { if (!err.error().startsWith('invalid cursor nope')) { throw 'unexpected error: ' + err.error() } }
+				)
+			`, rs.Addr()))
+
+		return err
+	})
+
+	assert.NoError(t, gotScriptErr)
+	assert.Equal(t, [][]string{
+		{"HELLO", "2"},
+		{"HSCAN", "session", "0", "match", "*", "count", "10"},
+		{"HSCAN", "session", "18446744073709551615"},
+	}, rs.GotCommands())
+}
+
+func TestClientHrandfield(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunT(t)
+	rs.RegisterCommandHandler("HRANDFIELD", func(c *Connection, args []string) {
+		if args[len(args)-1] == "withvalues" {
+			c.WriteArray("user", "alice", "user", "alice")
+			return
+		}
+
+		c.WriteArray("user")
+	})
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client('redis://%s');
+
+			redis.hrandfield("session")
+				.then(res => { if (res.length !== 1 || res[0] !== "user") { throw 'unexpected value for hrandfield result: ' + JSON.stringify(res) } })
+				.then(() => redis.hrandfield("session", { count: -2, withValues: true }))
+				.then(res => {
+					if (res.length !== 2 || res[1].field !== "user" || res[1].value !== "alice") {
+						throw 'unexpected value for hrandfield result: ' + JSON.stringify(res)
+					}
+				})
+			`, rs.Addr()))
+
+		return err
+	})
+
+	assert.NoError(t, gotScriptErr)
+	assert.Equal(t, [][]string{
+		{"HELLO", "2"},
+		{"HRANDFIELD", "session", "1"},
+		{"HRANDFIELD", "session", "-2", "withvalues"},
+	}, rs.GotCommands())
+}
+
 func TestClientSadd(t *testing.T) {
 	t.Parallel()
 
@@ -2020,6 +2730,85 @@ func TestClientSpop(t *testing.T) {
 	}, rs.GotCommands())
 }
 
+func TestClientSetAlgebra(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunT(t)
+	rs.RegisterCommandHandler("SCARD", func(c *Connection, args []string) {
+		c.WriteInteger(3)
+	})
+	rs.RegisterCommandHandler("SMISMEMBER", func(c *Connection, args []string) {
+		c.WriteValue([]interface{}{1, 0})
+	})
+	rs.RegisterCommandHandler("SMOVE", func(c *Connection, args []string) {
+		c.WriteInteger(1)
+	})
+	members := func(c *Connection, args []string) {
+		c.WriteArray("a", "b")
+	}
+	rs.RegisterCommandHandler("SINTER", members)
+	rs.RegisterCommandHandler("SUNION", members)
+	rs.RegisterCommandHandler("SDIFF", members)
+	stored := func(c *Connection, args []string) {
+		c.WriteInteger(len(args) - 1)
+	}
+	rs.RegisterCommandHandler("SINTERSTORE", stored)
+	rs.RegisterCommandHandler("SUNIONSTORE", stored)
+	rs.RegisterCommandHandler("SDIFFSTORE", stored)
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client('redis://%s');
+
+			redis.scard("s1")
+				.then(res => { if (res !== 3) { throw 'unexpected value for scard result: ' + res } })
+				.then(() => redis.smismember("s1", "a", "z"))
+				.then(res => { if (res.join(",") !== "true,false") { throw 'unexpected value for smismember result: ' + JSON.stringify(res) } })
+				.then(() => redis.smove("s1", "s2", "a"))
+				.then(res => { if (res !== true) { throw 'unexpected value for smove result: ' + res } })
+				.then(() => redis.sinter(["s1", "s2"]))
+				.then(res => { if (res.join(",") !== "a,b") { throw 'unexpected value for sinter result: ' + JSON.stringify(res) } })
+				.then(() => redis.sunion(["s1", "s2"]))
+				.then(res => { if (res.join(",") !== "a,b") { throw 'unexpected value for sunion result: ' + JSON.stringify(res) } })
+				.then(() => redis.sdiff(["s1", "s2"]))
+				.then(res => { if (res.join(",") !== "a,b") { throw 'unexpected value for sdiff result: ' + JSON.stringify(res) } })
+				.then(() => redis.sinterstore("dest", ["s1", "s2"]))
+				.then(res => { if (res !== 2) { throw 'unexpected value for sinterstore result: ' + res } })
+				.then(() => redis.sunionstore("dest", ["s1", "s2", "s3"]))
+				.then(res => { if (res !== 3) { throw 'unexpected value for sunionstore result: ' + res } })
+				.then(() => redis.sdiffstore("dest", ["s1"]))
+				.then(res => { if (res !== 1) { throw 'unexpected value for sdiffstore result: ' + res } })
+				.then(() => redis.sinterstore("dest", []))
+				.then(
+					res => { throw 'expected sinterstore to fail' },
+					err => { if (err.error() !== 'at least one key must be provided to sinterstore') { throw 'unexpected error: ' + err.error() } }
+				)
+				.then(() => redis.smismember("s1"))
+				.then(
+					res => { throw 'expected smismember to fail' },
+					err => { if (err.error() !== 'at least one member must be provided to smismember') { throw 'unexpected error: ' + err.error() } }
+				)
+		`, rs.Addr()))
+
+		return err
+	})
+
+	assert.NoError(t, gotScriptErr)
+	assert.Equal(t, [][]string{
+		{"HELLO", "2"},
+		{"SCARD", "s1"},
+		{"SMISMEMBER", "s1", "a", "z"},
+		{"SMOVE", "s1", "s2", "a"},
+		{"SINTER", "s1", "s2"},
+		{"SUNION", "s1", "s2"},
+		{"SDIFF", "s1", "s2"},
+		{"SINTERSTORE", "dest", "s1", "s2"},
+		{"SUNIONSTORE", "dest", "s1", "s2", "s3"},
+		{"SDIFFSTORE", "dest", "s1"},
+	}, rs.GotCommands())
+}
+
 func TestClientSendCommand(t *testing.T) {
 	t.Parallel()
 
@@ -2040,6 +2829,14 @@ func TestClientSendCommand(t *testing.T) {
 
 		c.WriteInteger(0)
 	})
+	rs.RegisterCommandHandler("JSON.GET", func(c *Connection, args []string) {
+		if args[0] == "missing" {
+			c.WriteNull()
+			return
+		}
+
+		c.WriteValue([]interface{}{args[1], []interface{}{"nested", 1}})
+	})
 
 	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
 		_, err := ts.rt.RunString(fmt.Sprintf(`
@@ -2049,6 +2846,12 @@ func TestClientSendCommand(t *testing.T) {
 				.then(res => { if (res !== 1) { throw 'unexpected value for sadd result: ' + res } })
 				.then(() => redis.sendCommand("sadd", "existing_set", "foo"))
 				.then(res => { if (res !== 0) { throw 'unexpected value for sadd result: ' + res } })
+				.then(() => redis.sendCommand("JSON.GET", "doc", new Uint8Array([36])))
+				.then(res => {
+					if (JSON.stringify(res) !== '["$",["nested",1]]') { throw 'unexpected value for JSON.GET result: ' + JSON.stringify(res) }
+				})
+				.then(() => redis.sendCommand("JSON.GET", "missing", "$"))
+				.then(res => { if (res !== null) { throw 'unexpected value for JSON.GET result on a missing key: ' + res } })
 
 			`, rs.Addr()))
 
@@ -2056,11 +2859,13 @@ func TestClientSendCommand(t *testing.T) {
 	})
 
 	assert.NoError(t, gotScriptErr)
-	assert.Equal(t, 2, rs.HandledCommandsCount())
+	assert.Equal(t, 4, rs.HandledCommandsCount())
 	assert.Equal(t, [][]string{
 		{"HELLO", "2"},
 		{"SADD", "existing_set", "foo"},
 		{"SADD", "existing_set", "foo"},
+		{"JSON.GET", "doc", "$"},
+		{"JSON.GET", "missing", "$"},
 	}, rs.GotCommands())
 }
 
@@ -2232,11 +3037,635 @@ func TestClientCommandsInInitContext(t *testing.T) {
 			statement: "redis.spop('shouldfail')",
 		},
 		{
-			name:      "sendCommand should fail when used in the init context",
-			statement: "redis.sendCommand('GET', 'shouldfail')",
+			name:      "scard should fail when used in the init context",
+			statement: "redis.scard('shouldfail')",
 		},
-	}
-
+		{
+			name:      "smismember should fail when used in the init context",
+			statement: "redis.smismember('should', 'fail')",
+		},
+		{
+			name:      "smove should fail when used in the init context",
+			statement: "redis.smove('should', 'fail', 'member')",
+		},
+		{
+			name:      "sinter should fail when used in the init context",
+			statement: "redis.sinter(['should', 'fail'])",
+		},
+		{
+			name:      "sunion should fail when used in the init context",
+			statement: "redis.sunion(['should', 'fail'])",
+		},
+		{
+			name:      "sdiff should fail when used in the init context",
+			statement: "redis.sdiff(['should', 'fail'])",
+		},
+		{
+			name:      "sinterstore should fail when used in the init context",
+			statement: "redis.sinterstore('should', ['fail'])",
+		},
+		{
+			name:      "sunionstore should fail when used in the init context",
+			statement: "redis.sunionstore('should', ['fail'])",
+		},
+		{
+			name:      "sdiffstore should fail when used in the init context",
+			statement: "redis.sdiffstore('should', ['fail'])",
+		},
+		{
+			name:      "appendLog should fail when used in the init context",
+			statement: "redis.appendLog('should', 'fail')",
+		},
+		{
+			name:      "tailLog should fail when used in the init context",
+			statement: "redis.tailLog('shouldfail', 10)",
+		},
+		{
+			name:      "connectionCount should fail when used in the init context",
+			statement: "redis.connectionCount()",
+		},
+		{
+			name:      "xadd should fail when used in the init context",
+			statement: "redis.xadd('events', '*', { kind: 'click' })",
+		},
+		{
+			name:      "xread should fail when used in the init context",
+			statement: "redis.xread({ events: '0' })",
+		},
+		{
+			name:      "hgetBuffer should fail when used in the init context",
+			statement: "redis.hgetBuffer('shouldfail', 'field')",
+		},
+		{
+			name:      "hkeysBuffer should fail when used in the init context",
+			statement: "redis.hkeysBuffer('shouldfail')",
+		},
+		{
+			name:      "smembersBuffer should fail when used in the init context",
+			statement: "redis.smembersBuffer('shouldfail')",
+		},
+		{
+			name:      "subscribe should fail when used in the init context",
+			statement: "redis.subscribe('shouldfail', () => {})",
+		},
+		{
+			name:      "unsubscribe should fail when used in the init context",
+			statement: "redis.unsubscribe('shouldfail')",
+		},
+		{
+			name:      "quorumGet should fail when used in the init context",
+			statement: "redis.quorumGet('shouldfail')",
+		},
+		{
+			name:      "runOnNode should fail when used in the init context",
+			statement: "redis.runOnNode('127.0.0.1:6379', 'INFO')",
+		},
+		{
+			name:      "clusterNodes should fail when used in the init context",
+			statement: "redis.clusterNodes()",
+		},
+		{
+			name:      "clusterSlots should fail when used in the init context",
+			statement: "redis.clusterSlots()",
+		},
+		{
+			name:      "clusterShards should fail when used in the init context",
+			statement: "redis.clusterShards()",
+		},
+		{
+			name:      "clusterKeyslot should fail when used in the init context",
+			statement: "redis.clusterKeyslot('shouldfail')",
+		},
+		{
+			name:      "expect should fail when used in the init context",
+			statement: "redis.expect('GET', ['shouldfail'], { maxDuration: 10 })",
+		},
+		{
+			name:      "setChecksummed should fail when used in the init context",
+			statement: "redis.setChecksummed('shouldfail', 'value')",
+		},
+		{
+			name:      "getVerified should fail when used in the init context",
+			statement: "redis.getVerified('shouldfail')",
+		},
+		{
+			name:      "signal should fail when used in the init context",
+			statement: "redis.signal('shouldfail')",
+		},
+		{
+			name:      "waitFor should fail when used in the init context",
+			statement: "redis.waitFor('shouldfail', 100)",
+		},
+		{
+			name:      "watchLeaderboard should fail when used in the init context",
+			statement: "redis.watchLeaderboard('board', 'board:updates', () => {})",
+		},
+		{
+			name:      "scanShard should fail when used in the init context",
+			statement: "redis.scanShard(0, 2)",
+		},
+		{
+			name:      "encodings should fail when used in the init context",
+			statement: "redis.encodings('a', 'b')",
+		},
+		{
+			name:      "estimateSize should fail when used in the init context",
+			statement: "redis.estimateSize('a')",
+		},
+		{
+			name:      "sampleConsumerLag should fail when used in the init context",
+			statement: "redis.sampleConsumerLag('jobs')",
+		},
+		{
+			name:      "mset should fail when used in the init context",
+			statement: "redis.mset({ should: 'fail' })",
+		},
+		{
+			name:      "pubSubRoundTrip should fail when used in the init context",
+			statement: "redis.pubSubRoundTrip('echo')",
+		},
+		{
+			name:      "tsAppend should fail when used in the init context",
+			statement: "redis.tsAppend('cpu', 1, 'a')",
+		},
+		{
+			name:      "xreadgroup should fail when used in the init context",
+			statement: "redis.xreadgroup('g', 'c', { s: '>' })",
+		},
+		{
+			name:      "xrange should fail when used in the init context",
+			statement: "redis.xrange('s', '-', '+')",
+		},
+		{
+			name:      "xack should fail when used in the init context",
+			statement: "redis.xack('s', 'g', '1-0')",
+		},
+		{
+			name:      "xgroupCreate should fail when used in the init context",
+			statement: "redis.xgroupCreate('s', 'g', '$')",
+		},
+		{
+			name:      "xpending should fail when used in the init context",
+			statement: "redis.xpending('s', 'g')",
+		},
+		{
+			name:      "psubscribe should fail when used in the init context",
+			statement: "redis.psubscribe('news.*', () => {})",
+		},
+		{
+			name:      "punsubscribe should fail when used in the init context",
+			statement: "redis.punsubscribe('news.*')",
+		},
+		{
+			name:      "publish should fail when used in the init context",
+			statement: "redis.publish('news', 'hello')",
+		},
+		{
+			name:      "pipeline should fail when used in the init context",
+			statement: "redis.pipeline().set('foo', 'bar', 0).exec()",
+		},
+		{
+			name:      "multi should fail when used in the init context",
+			statement: "redis.multi().incr('counter').exec()",
+		},
+		{
+			name:      "watch should fail when used in the init context",
+			statement: "redis.watch(['counter'], (values, tx) => { tx.incr('counter') })",
+		},
+		{
+			name:      "eval should fail when used in the init context",
+			statement: "redis.eval('return 1', [])",
+		},
+		{
+			name:      "evalsha should fail when used in the init context",
+			statement: "redis.evalsha('e0e1f9fabfc9d4800c877a703b823ac0578ff8db', [])",
+		},
+		{
+			name:      "scriptLoad should fail when used in the init context",
+			statement: "redis.scriptLoad('return 1')",
+		},
+		{
+			name:      "script should fail when used in the init context",
+			statement: "redis.script('return 1').run([])",
+		},
+		{
+			name:      "functionLoad should fail when used in the init context",
+			statement: "redis.functionLoad('#!lua name=mylib')",
+		},
+		{
+			name:      "fcall should fail when used in the init context",
+			statement: "redis.fcall('myfunc', [])",
+		},
+		{
+			name:      "zadd should fail when used in the init context",
+			statement: "redis.zadd('scores', [{ score: 1, member: 'alice' }])",
+		},
+		{
+			name:      "zrange should fail when used in the init context",
+			statement: "redis.zrange('scores', 0, -1)",
+		},
+		{
+			name:      "zrangebyscore should fail when used in the init context",
+			statement: "redis.zrangebyscore('scores', '-inf', '+inf')",
+		},
+		{
+			name:      "zincrby should fail when used in the init context",
+			statement: "redis.zincrby('scores', 1, 'alice')",
+		},
+		{
+			name:      "zrem should fail when used in the init context",
+			statement: "redis.zrem('scores', 'alice')",
+		},
+		{
+			name:      "zscore should fail when used in the init context",
+			statement: "redis.zscore('scores', 'alice')",
+		},
+		{
+			name:      "zcard should fail when used in the init context",
+			statement: "redis.zcard('scores')",
+		},
+		{
+			name:      "hmset should fail when used in the init context",
+			statement: "redis.hmset('session', { user: 'alice' })",
+		},
+		{
+			name:      "hscan should fail when used in the init context",
+			statement: "redis.hscan('session', 0)",
+		},
+		{
+			name:      "hrandfield should fail when used in the init context",
+			statement: "redis.hrandfield('session')",
+		},
+		{
+			name:      "scan should fail when used in the init context",
+			statement: "redis.scan(0)",
+		},
+		{
+			name:      "scanAll should fail when used in the init context",
+			statement: "redis.scanAll()",
+		},
+		{
+			name:      "sscanAll should fail when used in the init context",
+			statement: "redis.sscanAll('set')",
+		},
+		{
+			name:      "zscanAll should fail when used in the init context",
+			statement: "redis.zscanAll('zset')",
+		},
+		{
+			name:      "poolStats should fail when used in the init context",
+			statement: "redis.poolStats()",
+		},
+		{
+			name:      "geoadd should fail when used in the init context",
+			statement: "redis.geoadd('cities', [{ longitude: 13.361389, latitude: 38.115556, member: 'palermo' }])",
+		},
+		{
+			name:      "geosearch should fail when used in the init context",
+			statement: "redis.geosearch('cities', { member: 'palermo', radius: 100 })",
+		},
+		{
+			name:      "geodist should fail when used in the init context",
+			statement: "redis.geodist('cities', 'palermo', 'catania')",
+		},
+		{
+			name:      "geopos should fail when used in the init context",
+			statement: "redis.geopos('cities', 'palermo')",
+		},
+		{
+			name:      "pfadd should fail when used in the init context",
+			statement: "redis.pfadd('visitors', 'alice')",
+		},
+		{
+			name:      "pfcount should fail when used in the init context",
+			statement: "redis.pfcount('visitors')",
+		},
+		{
+			name:      "pfmerge should fail when used in the init context",
+			statement: "redis.pfmerge('all', 'visitors')",
+		},
+		{
+			name:      "setbit should fail when used in the init context",
+			statement: "redis.setbit('flags', 7, 1)",
+		},
+		{
+			name:      "getbit should fail when used in the init context",
+			statement: "redis.getbit('flags', 7)",
+		},
+		{
+			name:      "bitcount should fail when used in the init context",
+			statement: "redis.bitcount('flags')",
+		},
+		{
+			name:      "bitpos should fail when used in the init context",
+			statement: "redis.bitpos('flags', 1)",
+		},
+		{
+			name:      "bitop should fail when used in the init context",
+			statement: "redis.bitop('and', 'all', 'flags')",
+		},
+		{
+			name:      "bitfield should fail when used in the init context",
+			statement: "redis.bitfield('counters', [{ op: 'get', type: 'u8', offset: 0 }])",
+		},
+		{
+			name:      "getBuffer should fail when used in the init context",
+			statement: "redis.getBuffer('key')",
+		},
+		{
+			name:      "getDelBuffer should fail when used in the init context",
+			statement: "redis.getDelBuffer('key')",
+		},
+		{
+			name:      "lpopBuffer should fail when used in the init context",
+			statement: "redis.lpopBuffer('list')",
+		},
+		{
+			name:      "rpopBuffer should fail when used in the init context",
+			statement: "redis.rpopBuffer('list')",
+		},
+		{
+			name:      "lrangeBuffer should fail when used in the init context",
+			statement: "redis.lrangeBuffer('list', 0, -1)",
+		},
+		{
+			name:      "setJSON should fail when used in the init context",
+			statement: "redis.setJSON('key', {a: 1}, 0)",
+		},
+		{
+			name:      "getJSON should fail when used in the init context",
+			statement: "redis.getJSON('key')",
+		},
+		{
+			name:      "jsonSet should fail when used in the init context",
+			statement: "redis.jsonSet('key', '$', {a: 1})",
+		},
+		{
+			name:      "jsonGet should fail when used in the init context",
+			statement: "redis.jsonGet('key')",
+		},
+		{
+			name:      "jsonMGet should fail when used in the init context",
+			statement: "redis.jsonMGet(['key'], '$')",
+		},
+		{
+			name:      "jsonDel should fail when used in the init context",
+			statement: "redis.jsonDel('key', '$')",
+		},
+		{
+			name:      "jsonNumIncrBy should fail when used in the init context",
+			statement: "redis.jsonNumIncrBy('key', '$.a', 1)",
+		},
+		{
+			name:      "ftCreate should fail when used in the init context",
+			statement: "redis.ftCreate('idx', [{name: 'title', type: 'text'}])",
+		},
+		{
+			name:      "ftSearch should fail when used in the init context",
+			statement: "redis.ftSearch('idx', '*')",
+		},
+		{
+			name:      "ftAggregate should fail when used in the init context",
+			statement: "redis.ftAggregate('idx', '*', [])",
+		},
+		{
+			name:      "ftDropIndex should fail when used in the init context",
+			statement: "redis.ftDropIndex('idx')",
+		},
+		{
+			name:      "objectEncoding should fail when used in the init context",
+			statement: "redis.objectEncoding('key')",
+		},
+		{
+			name:      "objectFreq should fail when used in the init context",
+			statement: "redis.objectFreq('key')",
+		},
+		{
+			name:      "objectIdletime should fail when used in the init context",
+			statement: "redis.objectIdletime('key')",
+		},
+		{
+			name:      "memoryUsage should fail when used in the init context",
+			statement: "redis.memoryUsage('key')",
+		},
+		{
+			name:      "memoryProfile should fail when used in the init context",
+			statement: "redis.memoryProfile('key:*')",
+		},
+		{
+			name:      "cleanupIteration should fail when used in the init context",
+			statement: "redis.cleanupIteration()",
+		},
+		{
+			name:      "debugSleep should fail when used in the init context",
+			statement: "redis.debugSleep(0)",
+		},
+		{
+			name:      "pexpire should fail when used in the init context",
+			statement: "redis.pexpire('shouldfail', 10)",
+		},
+		{
+			name:      "expireat should fail when used in the init context",
+			statement: "redis.expireat('shouldfail', 10)",
+		},
+		{
+			name:      "pttl should fail when used in the init context",
+			statement: "redis.pttl('shouldfail')",
+		},
+		{
+			name:      "expiretime should fail when used in the init context",
+			statement: "redis.expiretime('shouldfail')",
+		},
+		{
+			name:      "lock should fail when used in the init context",
+			statement: "redis.lock('shouldfail')",
+		},
+		{
+			name:      "rateLimit should fail when used in the init context",
+			statement: "redis.rateLimit('shouldfail', { limit: 1, window: 1000 })",
+		},
+		{
+			name:      "pushJob should fail when used in the init context",
+			statement: "redis.pushJob('shouldfail', 'job')",
+		},
+		{
+			name:      "popJob should fail when used in the init context",
+			statement: "redis.popJob('shouldfail', 1)",
+		},
+		{
+			name:      "jobCount should fail when used in the init context",
+			statement: "redis.jobCount('shouldfail')",
+		},
+		{
+			name:      "blpop should fail when used in the init context",
+			statement: "redis.blpop(['shouldfail'], 1)",
+		},
+		{
+			name:      "brpop should fail when used in the init context",
+			statement: "redis.brpop(['shouldfail'], 1)",
+		},
+		{
+			name:      "blmove should fail when used in the init context",
+			statement: "redis.blmove('shouldfail', 'destination', 'left', 'right', 1)",
+		},
+		{
+			name:      "bzpopmin should fail when used in the init context",
+			statement: "redis.bzpopmin(['shouldfail'], 1)",
+		},
+		{
+			name:      "xreadBlock should fail when used in the init context",
+			statement: "redis.xreadBlock({ shouldfail: '$' }, 0, 1)",
+		},
+		{
+			name:      "swapdb should fail when used in the init context",
+			statement: "redis.swapdb(0, 1)",
+		},
+		{
+			name:      "wait should fail when used in the init context",
+			statement: "redis.wait(1, 100)",
+		},
+		{
+			name:      "failover should fail when used in the init context",
+			statement: "redis.failover()",
+		},
+		{
+			name:      "onKeyEvent should fail when used in the init context",
+			statement: "redis.onKeyEvent('expired', () => {})",
+		},
+		{
+			name:      "info should fail when used in the init context",
+			statement: "redis.info()",
+		},
+		{
+			name:      "configGet should fail when used in the init context",
+			statement: "redis.configGet('maxmemory')",
+		},
+		{
+			name:      "configSet should fail when used in the init context",
+			statement: "redis.configSet('maxmemory', '0')",
+		},
+		{
+			name:      "clientList should fail when used in the init context",
+			statement: "redis.clientList()",
+		},
+		{
+			name:      "clientKill should fail when used in the init context",
+			statement: "redis.clientKill({ type: 'normal' })",
+		},
+		{
+			name:      "clientPause should fail when used in the init context",
+			statement: "redis.clientPause(100)",
+		},
+		{
+			name:      "clientUnpause should fail when used in the init context",
+			statement: "redis.clientUnpause()",
+		},
+		{
+			name:      "clientNoEvict should fail when used in the init context",
+			statement: "redis.clientNoEvict(true)",
+		},
+		{
+			name:      "sampleServerStats should fail when used in the init context",
+			statement: "redis.sampleServerStats()",
+		},
+		{
+			name:      "slowlogGet should fail when used in the init context",
+			statement: "redis.slowlogGet()",
+		},
+		{
+			name:      "latencyLatest should fail when used in the init context",
+			statement: "redis.latencyLatest()",
+		},
+		{
+			name:      "copy should fail when used in the init context",
+			statement: "redis.copy('foo', 'bar')",
+		},
+		{
+			name:      "rename should fail when used in the init context",
+			statement: "redis.rename('foo', 'bar')",
+		},
+		{
+			name:      "renamenx should fail when used in the init context",
+			statement: "redis.renamenx('foo', 'bar')",
+		},
+		{
+			name:      "dump should fail when used in the init context",
+			statement: "redis.dump('foo')",
+		},
+		{
+			name:      "restore should fail when used in the init context",
+			statement: "redis.restore('foo', 0, new ArrayBuffer(8))",
+		},
+		{
+			name:      "migrate should fail when used in the init context",
+			statement: "redis.migrate('localhost', 6380, ['foo'])",
+		},
+		{
+			name:      "unlink should fail when used in the init context",
+			statement: "redis.unlink('should', 'fail')",
+		},
+		{
+			name:      "touch should fail when used in the init context",
+			statement: "redis.touch('should', 'fail')",
+		},
+		{
+			name:      "append should fail when used in the init context",
+			statement: "redis.append('key', 'value')",
+		},
+		{
+			name:      "strlen should fail when used in the init context",
+			statement: "redis.strlen('key')",
+		},
+		{
+			name:      "getrange should fail when used in the init context",
+			statement: "redis.getrange('key', 0, -1)",
+		},
+		{
+			name:      "setrange should fail when used in the init context",
+			statement: "redis.setrange('key', 0, 'value')",
+		},
+		{
+			name:      "getex should fail when used in the init context",
+			statement: "redis.getex('key')",
+		},
+		{
+			name:      "lmpop should fail when used in the init context",
+			statement: "redis.lmpop(['key'], 'left')",
+		},
+		{
+			name:      "zmpop should fail when used in the init context",
+			statement: "redis.zmpop(['key'], 'min')",
+		},
+		{
+			name:      "lpos should fail when used in the init context",
+			statement: "redis.lpos('key', 'element')",
+		},
+		{
+			name:      "lmove should fail when used in the init context",
+			statement: "redis.lmove('source', 'destination', 'left', 'right')",
+		},
+		{
+			name:      "sintercard should fail when used in the init context",
+			statement: "redis.sintercard(['key1', 'key2'])",
+		},
+		{
+			name:      "seed should fail when used in the init context",
+			statement: "redis.seed({ count: 1 })",
+		},
+		{
+			name:      "deleteByPattern should fail when used in the init context",
+			statement: "redis.deleteByPattern('loadtest:*')",
+		},
+		{
+			name:      "getRandom should fail when used in the init context",
+			statement: "redis.getRandom({ pattern: 'user:{i}', count: 10 })",
+		},
+		{
+			name:      "sendCommand should fail when used in the init context",
+			statement: "redis.sendCommand('GET', 'shouldfail')",
+		},
+	}
+
 	for _, tc := range testCases {
 		tc := tc
 
@@ -2268,164 +3697,784 @@ func TestClientCommandsAgainstUnreachableServer(t *testing.T) {
 		statement string
 	}{
 		{
-			name:      "set should fail when server is unreachable",
-			statement: "redis.set('should', 'fail')",
+			name:      "set should fail when server is unreachable",
+			statement: "redis.set('should', 'fail')",
+		},
+		{
+			name:      "get should fail when server is unreachable",
+			statement: "redis.get('shouldfail')",
+		},
+		{
+			name:      "getSet should fail when server is unreachable",
+			statement: "redis.getSet('should', 'fail')",
+		},
+		{
+			name:      "del should fail when server is unreachable",
+			statement: "redis.del('should', 'fail')",
+		},
+		{
+			name:      "getDel should fail when server is unreachable",
+			statement: "redis.getDel('shouldfail')",
+		},
+		{
+			name:      "exists should fail when server is unreachable",
+			statement: "redis.exists('should', 'fail')",
+		},
+		{
+			name:      "incr should fail when server is unreachable",
+			statement: "redis.incr('shouldfail')",
+		},
+		{
+			name:      "incrBy should fail when server is unreachable",
+			statement: "redis.incrBy('shouldfail', 10)",
+		},
+		{
+			name:      "decr should fail when server is unreachable",
+			statement: "redis.decr('shouldfail')",
+		},
+		{
+			name:      "decrBy should fail when server is unreachable",
+			statement: "redis.decrBy('shouldfail', 10)",
+		},
+		{
+			name:      "randomKey should fail when server is unreachable",
+			statement: "redis.randomKey()",
+		},
+		{
+			name:      "mget should fail when server is unreachable",
+			statement: "redis.mget('should', 'fail')",
+		},
+		{
+			name:      "expire should fail when server is unreachable",
+			statement: "redis.expire('shouldfail', 10)",
+		},
+		{
+			name:      "ttl should fail when server is unreachable",
+			statement: "redis.ttl('shouldfail')",
+		},
+		{
+			name:      "persist should fail when server is unreachable",
+			statement: "redis.persist('shouldfail')",
+		},
+		{
+			name:      "lpush should fail when server is unreachable",
+			statement: "redis.lpush('should', 'fail', 'indeed')",
+		},
+		{
+			name:      "rpush should fail when server is unreachable",
+			statement: "redis.rpush('should', 'fail', 'indeed')",
+		},
+		{
+			name:      "lpop should fail when server is unreachable",
+			statement: "redis.lpop('shouldfail')",
+		},
+		{
+			name:      "rpop should fail when server is unreachable",
+			statement: "redis.rpop('shouldfail')",
+		},
+		{
+			name:      "lrange should fail when server is unreachable",
+			statement: "redis.lrange('shouldfail', 0, 5)",
+		},
+		{
+			name:      "lindex should fail when server is unreachable",
+			statement: "redis.lindex('shouldfail', 1)",
+		},
+		{
+			name:      "lset should fail when server is unreachable",
+			statement: "redis.lset('shouldfail', 1, 'fail')",
+		},
+		{
+			name:      "lrem should fail when server is unreachable",
+			statement: "redis.lrem('should', 1, 'fail')",
+		},
+		{
+			name:      "llen should fail when server is unreachable",
+			statement: "redis.llen('shouldfail')",
+		},
+		{
+			name:      "hset should fail when server is unreachable",
+			statement: "redis.hset('shouldfail', 'foo', 'bar')",
+		},
+		{
+			name:      "hsetnx should fail when server is unreachable",
+			statement: "redis.hsetnx('shouldfail', 'foo', 'bar')",
+		},
+		{
+			name:      "hget should fail when server is unreachable",
+			statement: "redis.hget('should', 'fail')",
+		},
+		{
+			name:      "hdel should fail when server is unreachable",
+			statement: "redis.hdel('should', 'fail', 'indeed')",
+		},
+		{
+			name:      "hgetall should fail when server is unreachable",
+			statement: "redis.hgetall('shouldfail')",
+		},
+		{
+			name:      "hkeys should fail when server is unreachable",
+			statement: "redis.hkeys('shouldfail')",
+		},
+		{
+			name:      "hvals should fail when server is unreachable",
+			statement: "redis.hvals('shouldfail')",
+		},
+		{
+			name:      "hlen should fail when server is unreachable",
+			statement: "redis.hlen('shouldfail')",
+		},
+		{
+			name:      "hincrby should fail when server is unreachable",
+			statement: "redis.hincrby('should', 'fail', 10)",
+		},
+		{
+			name:      "sadd should fail when server is unreachable",
+			statement: "redis.sadd('should', 'fail', 'indeed')",
+		},
+		{
+			name:      "srem should fail when server is unreachable",
+			statement: "redis.srem('should', 'fail', 'indeed')",
+		},
+		{
+			name:      "sismember should fail when server is unreachable",
+			statement: "redis.sismember('should', 'fail')",
+		},
+		{
+			name:      "smembers should fail when server is unreachable",
+			statement: "redis.smembers('shouldfail')",
+		},
+		{
+			name:      "srandmember should fail when server is unreachable",
+			statement: "redis.srandmember('shouldfail')",
+		},
+		{
+			name:      "persist should fail when server is unreachable",
+			statement: "redis.persist('shouldfail')",
+		},
+		{
+			name:      "spop should fail when server is unreachable",
+			statement: "redis.spop('shouldfail')",
+		},
+		{
+			name:      "scard should fail when server is unreachable",
+			statement: "redis.scard('shouldfail')",
+		},
+		{
+			name:      "smismember should fail when server is unreachable",
+			statement: "redis.smismember('should', 'fail')",
+		},
+		{
+			name:      "smove should fail when server is unreachable",
+			statement: "redis.smove('should', 'fail', 'member')",
+		},
+		{
+			name:      "sinter should fail when server is unreachable",
+			statement: "redis.sinter(['should', 'fail'])",
+		},
+		{
+			name:      "sunion should fail when server is unreachable",
+			statement: "redis.sunion(['should', 'fail'])",
+		},
+		{
+			name:      "sdiff should fail when server is unreachable",
+			statement: "redis.sdiff(['should', 'fail'])",
+		},
+		{
+			name:      "sinterstore should fail when server is unreachable",
+			statement: "redis.sinterstore('should', ['fail'])",
+		},
+		{
+			name:      "sunionstore should fail when server is unreachable",
+			statement: "redis.sunionstore('should', ['fail'])",
+		},
+		{
+			name:      "sdiffstore should fail when server is unreachable",
+			statement: "redis.sdiffstore('should', ['fail'])",
+		},
+		{
+			name:      "appendLog should fail when server is unreachable",
+			statement: "redis.appendLog('should', 'fail')",
+		},
+		{
+			name:      "tailLog should fail when server is unreachable",
+			statement: "redis.tailLog('shouldfail', 10)",
+		},
+		{
+			name:      "connectionCount should fail when server is unreachable",
+			statement: "redis.connectionCount()",
+		},
+		{
+			name:      "xadd should fail when server is unreachable",
+			statement: "redis.xadd('events', '*', { kind: 'click' })",
+		},
+		{
+			name:      "xread should fail when server is unreachable",
+			statement: "redis.xread({ events: '0' })",
+		},
+		{
+			name:      "hgetBuffer should fail when server is unreachable",
+			statement: "redis.hgetBuffer('shouldfail', 'field')",
+		},
+		{
+			name:      "hkeysBuffer should fail when server is unreachable",
+			statement: "redis.hkeysBuffer('shouldfail')",
+		},
+		{
+			name:      "smembersBuffer should fail when server is unreachable",
+			statement: "redis.smembersBuffer('shouldfail')",
+		},
+		{
+			name:      "subscribe should fail when server is unreachable",
+			statement: "redis.subscribe('shouldfail', () => {})",
+		},
+		{
+			name:      "unsubscribe should fail when server is unreachable",
+			statement: "redis.unsubscribe('shouldfail')",
+		},
+		{
+			name:      "quorumGet should fail when server is unreachable",
+			statement: "redis.quorumGet('shouldfail')",
+		},
+		{
+			name:      "runOnNode should fail when server is unreachable",
+			statement: "redis.runOnNode('127.0.0.1:6379', 'INFO')",
+		},
+		{
+			name:      "clusterNodes should fail when server is unreachable",
+			statement: "redis.clusterNodes()",
+		},
+		{
+			name:      "clusterSlots should fail when server is unreachable",
+			statement: "redis.clusterSlots()",
+		},
+		{
+			name:      "clusterShards should fail when server is unreachable",
+			statement: "redis.clusterShards()",
+		},
+		{
+			name:      "clusterKeyslot should fail when server is unreachable",
+			statement: "redis.clusterKeyslot('shouldfail')",
+		},
+		{
+			name:      "expect should fail when server is unreachable",
+			statement: "redis.expect('GET', ['shouldfail'], { maxDuration: 10 })",
+		},
+		{
+			name:      "setChecksummed should fail when server is unreachable",
+			statement: "redis.setChecksummed('shouldfail', 'value')",
+		},
+		{
+			name:      "getVerified should fail when server is unreachable",
+			statement: "redis.getVerified('shouldfail')",
+		},
+		{
+			name:      "signal should fail when server is unreachable",
+			statement: "redis.signal('shouldfail')",
+		},
+		{
+			name:      "waitFor should fail when server is unreachable",
+			statement: "redis.waitFor('shouldfail', 100)",
+		},
+		{
+			name:      "watchLeaderboard should fail when server is unreachable",
+			statement: "redis.watchLeaderboard('board', 'board:updates', () => {})",
+		},
+		{
+			name:      "scanShard should fail when server is unreachable",
+			statement: "redis.scanShard(0, 2)",
+		},
+		{
+			name:      "encodings should fail when server is unreachable",
+			statement: "redis.encodings('a', 'b')",
+		},
+		{
+			name:      "estimateSize should fail when server is unreachable",
+			statement: "redis.estimateSize('a')",
+		},
+		{
+			name:      "sampleConsumerLag should fail when server is unreachable",
+			statement: "redis.sampleConsumerLag('jobs')",
+		},
+		{
+			name:      "mset should fail when server is unreachable",
+			statement: "redis.mset({ should: 'fail' })",
+		},
+		{
+			name:      "pubSubRoundTrip should fail when server is unreachable",
+			statement: "redis.pubSubRoundTrip('echo')",
+		},
+		{
+			name:      "tsAppend should fail when server is unreachable",
+			statement: "redis.tsAppend('cpu', 1, 'a')",
+		},
+		{
+			name:      "xreadgroup should fail when server is unreachable",
+			statement: "redis.xreadgroup('g', 'c', { s: '>' })",
+		},
+		{
+			name:      "xrange should fail when server is unreachable",
+			statement: "redis.xrange('s', '-', '+')",
+		},
+		{
+			name:      "xack should fail when server is unreachable",
+			statement: "redis.xack('s', 'g', '1-0')",
+		},
+		{
+			name:      "xgroupCreate should fail when server is unreachable",
+			statement: "redis.xgroupCreate('s', 'g', '$')",
+		},
+		{
+			name:      "xpending should fail when server is unreachable",
+			statement: "redis.xpending('s', 'g')",
+		},
+		{
+			name:      "psubscribe should fail when server is unreachable",
+			statement: "redis.psubscribe('news.*', () => {})",
+		},
+		{
+			name:      "punsubscribe should fail when server is unreachable",
+			statement: "redis.punsubscribe('news.*')",
+		},
+		{
+			name:      "publish should fail when server is unreachable",
+			statement: "redis.publish('news', 'hello')",
+		},
+		{
+			name:      "pipeline should fail when server is unreachable",
+			statement: "redis.pipeline().set('foo', 'bar', 0).exec()",
+		},
+		{
+			name:      "multi should fail when server is unreachable",
+			statement: "redis.multi().incr('counter').exec()",
+		},
+		{
+			name:      "watch should fail when server is unreachable",
+			statement: "redis.watch(['counter'], (values, tx) => { tx.incr('counter') })",
+		},
+		{
+			name:      "eval should fail when server is unreachable",
+			statement: "redis.eval('return 1', [])",
+		},
+		{
+			name:      "evalsha should fail when server is unreachable",
+			statement: "redis.evalsha('e0e1f9fabfc9d4800c877a703b823ac0578ff8db', [])",
+		},
+		{
+			name:      "scriptLoad should fail when server is unreachable",
+			statement: "redis.scriptLoad('return 1')",
+		},
+		{
+			name:      "script should fail when server is unreachable",
+			statement: "redis.script('return 1').run([])",
+		},
+		{
+			name:      "functionLoad should fail when server is unreachable",
+			statement: "redis.functionLoad('#!lua name=mylib')",
+		},
+		{
+			name:      "fcall should fail when server is unreachable",
+			statement: "redis.fcall('myfunc', [])",
+		},
+		{
+			name:      "zadd should fail when server is unreachable",
+			statement: "redis.zadd('scores', [{ score: 1, member: 'alice' }])",
+		},
+		{
+			name:      "zrange should fail when server is unreachable",
+			statement: "redis.zrange('scores', 0, -1)",
+		},
+		{
+			name:      "zrangebyscore should fail when server is unreachable",
+			statement: "redis.zrangebyscore('scores', '-inf', '+inf')",
+		},
+		{
+			name:      "zincrby should fail when server is unreachable",
+			statement: "redis.zincrby('scores', 1, 'alice')",
+		},
+		{
+			name:      "zrem should fail when server is unreachable",
+			statement: "redis.zrem('scores', 'alice')",
+		},
+		{
+			name:      "zscore should fail when server is unreachable",
+			statement: "redis.zscore('scores', 'alice')",
+		},
+		{
+			name:      "zcard should fail when server is unreachable",
+			statement: "redis.zcard('scores')",
+		},
+		{
+			name:      "hmset should fail when server is unreachable",
+			statement: "redis.hmset('session', { user: 'alice' })",
+		},
+		{
+			name:      "hscan should fail when server is unreachable",
+			statement: "redis.hscan('session', 0)",
+		},
+		{
+			name:      "hrandfield should fail when server is unreachable",
+			statement: "redis.hrandfield('session')",
+		},
+		{
+			name:      "scan should fail when server is unreachable",
+			statement: "redis.scan(0)",
+		},
+		{
+			name:      "scanAll should fail when server is unreachable",
+			statement: "redis.scanAll()",
+		},
+		{
+			name:      "sscanAll should fail when server is unreachable",
+			statement: "redis.sscanAll('set')",
+		},
+		{
+			name:      "zscanAll should fail when server is unreachable",
+			statement: "redis.zscanAll('zset')",
+		},
+		{
+			name:      "poolStats should fail when server is unreachable",
+			statement: "redis.poolStats()",
+		},
+		{
+			name:      "geoadd should fail when server is unreachable",
+			statement: "redis.geoadd('cities', [{ longitude: 13.361389, latitude: 38.115556, member: 'palermo' }])",
+		},
+		{
+			name:      "geosearch should fail when server is unreachable",
+			statement: "redis.geosearch('cities', { member: 'palermo', radius: 100 })",
+		},
+		{
+			name:      "geodist should fail when server is unreachable",
+			statement: "redis.geodist('cities', 'palermo', 'catania')",
+		},
+		{
+			name:      "geopos should fail when server is unreachable",
+			statement: "redis.geopos('cities', 'palermo')",
+		},
+		{
+			name:      "pfadd should fail when server is unreachable",
+			statement: "redis.pfadd('visitors', 'alice')",
+		},
+		{
+			name:      "pfcount should fail when server is unreachable",
+			statement: "redis.pfcount('visitors')",
+		},
+		{
+			name:      "pfmerge should fail when server is unreachable",
+			statement: "redis.pfmerge('all', 'visitors')",
+		},
+		{
+			name:      "setbit should fail when server is unreachable",
+			statement: "redis.setbit('flags', 7, 1)",
+		},
+		{
+			name:      "getbit should fail when server is unreachable",
+			statement: "redis.getbit('flags', 7)",
+		},
+		{
+			name:      "bitcount should fail when server is unreachable",
+			statement: "redis.bitcount('flags')",
+		},
+		{
+			name:      "bitpos should fail when server is unreachable",
+			statement: "redis.bitpos('flags', 1)",
+		},
+		{
+			name:      "bitop should fail when server is unreachable",
+			statement: "redis.bitop('and', 'all', 'flags')",
+		},
+		{
+			name:      "bitfield should fail when server is unreachable",
+			statement: "redis.bitfield('counters', [{ op: 'get', type: 'u8', offset: 0 }])",
+		},
+		{
+			name:      "getBuffer should fail when server is unreachable",
+			statement: "redis.getBuffer('key')",
+		},
+		{
+			name:      "getDelBuffer should fail when server is unreachable",
+			statement: "redis.getDelBuffer('key')",
+		},
+		{
+			name:      "lpopBuffer should fail when server is unreachable",
+			statement: "redis.lpopBuffer('list')",
+		},
+		{
+			name:      "rpopBuffer should fail when server is unreachable",
+			statement: "redis.rpopBuffer('list')",
+		},
+		{
+			name:      "lrangeBuffer should fail when server is unreachable",
+			statement: "redis.lrangeBuffer('list', 0, -1)",
+		},
+		{
+			name:      "setJSON should fail when server is unreachable",
+			statement: "redis.setJSON('key', {a: 1}, 0)",
+		},
+		{
+			name:      "getJSON should fail when server is unreachable",
+			statement: "redis.getJSON('key')",
+		},
+		{
+			name:      "jsonSet should fail when server is unreachable",
+			statement: "redis.jsonSet('key', '$', {a: 1})",
+		},
+		{
+			name:      "jsonGet should fail when server is unreachable",
+			statement: "redis.jsonGet('key')",
+		},
+		{
+			name:      "jsonMGet should fail when server is unreachable",
+			statement: "redis.jsonMGet(['key'], '$')",
+		},
+		{
+			name:      "jsonDel should fail when server is unreachable",
+			statement: "redis.jsonDel('key', '$')",
+		},
+		{
+			name:      "jsonNumIncrBy should fail when server is unreachable",
+			statement: "redis.jsonNumIncrBy('key', '$.a', 1)",
+		},
+		{
+			name:      "ftCreate should fail when server is unreachable",
+			statement: "redis.ftCreate('idx', [{name: 'title', type: 'text'}])",
 		},
 		{
-			name:      "get should fail when server is unreachable",
-			statement: "redis.get('shouldfail')",
+			name:      "ftSearch should fail when server is unreachable",
+			statement: "redis.ftSearch('idx', '*')",
 		},
 		{
-			name:      "getSet should fail when server is unreachable",
-			statement: "redis.getSet('should', 'fail')",
+			name:      "ftAggregate should fail when server is unreachable",
+			statement: "redis.ftAggregate('idx', '*', [])",
 		},
 		{
-			name:      "del should fail when server is unreachable",
-			statement: "redis.del('should', 'fail')",
+			name:      "ftDropIndex should fail when server is unreachable",
+			statement: "redis.ftDropIndex('idx')",
 		},
 		{
-			name:      "getDel should fail when server is unreachable",
-			statement: "redis.getDel('shouldfail')",
+			name:      "objectEncoding should fail when server is unreachable",
+			statement: "redis.objectEncoding('key')",
 		},
 		{
-			name:      "exists should fail when server is unreachable",
-			statement: "redis.exists('should', 'fail')",
+			name:      "objectFreq should fail when server is unreachable",
+			statement: "redis.objectFreq('key')",
 		},
 		{
-			name:      "incr should fail when server is unreachable",
-			statement: "redis.incr('shouldfail')",
+			name:      "objectIdletime should fail when server is unreachable",
+			statement: "redis.objectIdletime('key')",
 		},
 		{
-			name:      "incrBy should fail when server is unreachable",
-			statement: "redis.incrBy('shouldfail', 10)",
+			name:      "memoryUsage should fail when server is unreachable",
+			statement: "redis.memoryUsage('key')",
 		},
 		{
-			name:      "decr should fail when server is unreachable",
-			statement: "redis.decr('shouldfail')",
+			name:      "memoryProfile should fail when server is unreachable",
+			statement: "redis.memoryProfile('key:*')",
 		},
 		{
-			name:      "decrBy should fail when server is unreachable",
-			statement: "redis.decrBy('shouldfail', 10)",
+			name:      "debugSleep should fail when server is unreachable",
+			statement: "redis.debugSleep(0)",
 		},
 		{
-			name:      "randomKey should fail when server is unreachable",
-			statement: "redis.randomKey()",
+			name:      "pexpire should fail when server is unreachable",
+			statement: "redis.pexpire('shouldfail', 10)",
 		},
 		{
-			name:      "mget should fail when server is unreachable",
-			statement: "redis.mget('should', 'fail')",
+			name:      "expireat should fail when server is unreachable",
+			statement: "redis.expireat('shouldfail', 10)",
 		},
 		{
-			name:      "expire should fail when server is unreachable",
-			statement: "redis.expire('shouldfail', 10)",
+			name:      "pttl should fail when server is unreachable",
+			statement: "redis.pttl('shouldfail')",
 		},
 		{
-			name:      "ttl should fail when server is unreachable",
-			statement: "redis.ttl('shouldfail')",
+			name:      "expiretime should fail when server is unreachable",
+			statement: "redis.expiretime('shouldfail')",
 		},
 		{
-			name:      "persist should fail when server is unreachable",
-			statement: "redis.persist('shouldfail')",
+			name:      "lock should fail when server is unreachable",
+			statement: "redis.lock('shouldfail')",
 		},
 		{
-			name:      "lpush should fail when server is unreachable",
-			statement: "redis.lpush('should', 'fail', 'indeed')",
+			name:      "rateLimit should fail when server is unreachable",
+			statement: "redis.rateLimit('shouldfail', { limit: 1, window: 1000 })",
 		},
 		{
-			name:      "rpush should fail when server is unreachable",
-			statement: "redis.rpush('should', 'fail', 'indeed')",
+			name:      "pushJob should fail when server is unreachable",
+			statement: "redis.pushJob('shouldfail', 'job')",
 		},
 		{
-			name:      "lpop should fail when server is unreachable",
-			statement: "redis.lpop('shouldfail')",
+			name:      "popJob should fail when server is unreachable",
+			statement: "redis.popJob('shouldfail', 1)",
 		},
 		{
-			name:      "rpop should fail when server is unreachable",
-			statement: "redis.rpop('shouldfail')",
+			name:      "jobCount should fail when server is unreachable",
+			statement: "redis.jobCount('shouldfail')",
 		},
 		{
-			name:      "lrange should fail when server is unreachable",
-			statement: "redis.lrange('shouldfail', 0, 5)",
+			name:      "blpop should fail when server is unreachable",
+			statement: "redis.blpop(['shouldfail'], 1)",
 		},
 		{
-			name:      "lindex should fail when server is unreachable",
-			statement: "redis.lindex('shouldfail', 1)",
+			name:      "brpop should fail when server is unreachable",
+			statement: "redis.brpop(['shouldfail'], 1)",
 		},
 		{
-			name:      "lset should fail when server is unreachable",
-			statement: "redis.lset('shouldfail', 1, 'fail')",
+			name:      "blmove should fail when server is unreachable",
+			statement: "redis.blmove('shouldfail', 'destination', 'left', 'right', 1)",
 		},
 		{
-			name:      "lrem should fail when server is unreachable",
-			statement: "redis.lrem('should', 1, 'fail')",
+			name:      "bzpopmin should fail when server is unreachable",
+			statement: "redis.bzpopmin(['shouldfail'], 1)",
 		},
 		{
-			name:      "llen should fail when server is unreachable",
-			statement: "redis.llen('shouldfail')",
+			name:      "xreadBlock should fail when server is unreachable",
+			statement: "redis.xreadBlock({ shouldfail: '$' }, 0, 1)",
 		},
 		{
-			name:      "hset should fail when server is unreachable",
-			statement: "redis.hset('shouldfail', 'foo', 'bar')",
+			name:      "swapdb should fail when server is unreachable",
+			statement: "redis.swapdb(0, 1)",
 		},
 		{
-			name:      "hsetnx should fail when server is unreachable",
-			statement: "redis.hsetnx('shouldfail', 'foo', 'bar')",
+			name:      "wait should fail when server is unreachable",
+			statement: "redis.wait(1, 100)",
 		},
 		{
-			name:      "hget should fail when server is unreachable",
-			statement: "redis.hget('should', 'fail')",
+			name:      "failover should fail when server is unreachable",
+			statement: "redis.failover()",
 		},
 		{
-			name:      "hdel should fail when server is unreachable",
-			statement: "redis.hdel('should', 'fail', 'indeed')",
+			name:      "onKeyEvent should fail when server is unreachable",
+			statement: "redis.onKeyEvent('expired', () => {})",
 		},
 		{
-			name:      "hgetall should fail when server is unreachable",
-			statement: "redis.hgetall('shouldfail')",
+			name:      "info should fail when server is unreachable",
+			statement: "redis.info()",
 		},
 		{
-			name:      "hkeys should fail when server is unreachable",
-			statement: "redis.hkeys('shouldfail')",
+			name:      "configGet should fail when server is unreachable",
+			statement: "redis.configGet('maxmemory')",
 		},
 		{
-			name:      "hvals should fail when server is unreachable",
-			statement: "redis.hvals('shouldfail')",
+			name:      "configSet should fail when server is unreachable",
+			statement: "redis.configSet('maxmemory', '0')",
 		},
 		{
-			name:      "hlen should fail when server is unreachable",
-			statement: "redis.hlen('shouldfail')",
+			name:      "clientList should fail when server is unreachable",
+			statement: "redis.clientList()",
 		},
 		{
-			name:      "hincrby should fail when server is unreachable",
-			statement: "redis.hincrby('should', 'fail', 10)",
+			name:      "clientKill should fail when server is unreachable",
+			statement: "redis.clientKill({ type: 'normal' })",
 		},
 		{
-			name:      "sadd should fail when server is unreachable",
-			statement: "redis.sadd('should', 'fail', 'indeed')",
+			name:      "clientPause should fail when server is unreachable",
+			statement: "redis.clientPause(100)",
 		},
 		{
-			name:      "srem should fail when server is unreachable",
-			statement: "redis.srem('should', 'fail', 'indeed')",
+			name:      "clientUnpause should fail when server is unreachable",
+			statement: "redis.clientUnpause()",
 		},
 		{
-			name:      "sismember should fail when server is unreachable",
-			statement: "redis.sismember('should', 'fail')",
+			name:      "clientNoEvict should fail when server is unreachable",
+			statement: "redis.clientNoEvict(true)",
 		},
 		{
-			name:      "smembers should fail when server is unreachable",
-			statement: "redis.smembers('shouldfail')",
+			name:      "sampleServerStats should fail when server is unreachable",
+			statement: "redis.sampleServerStats()",
 		},
 		{
-			name:      "srandmember should fail when server is unreachable",
-			statement: "redis.srandmember('shouldfail')",
+			name:      "slowlogGet should fail when server is unreachable",
+			statement: "redis.slowlogGet()",
 		},
 		{
-			name:      "persist should fail when server is unreachable",
-			statement: "redis.persist('shouldfail')",
+			name:      "latencyLatest should fail when server is unreachable",
+			statement: "redis.latencyLatest()",
 		},
 		{
-			name:      "spop should fail when server is unreachable",
-			statement: "redis.spop('shouldfail')",
+			name:      "copy should fail when server is unreachable",
+			statement: "redis.copy('foo', 'bar')",
+		},
+		{
+			name:      "rename should fail when server is unreachable",
+			statement: "redis.rename('foo', 'bar')",
+		},
+		{
+			name:      "renamenx should fail when server is unreachable",
+			statement: "redis.renamenx('foo', 'bar')",
+		},
+		{
+			name:      "dump should fail when server is unreachable",
+			statement: "redis.dump('foo')",
+		},
+		{
+			name:      "restore should fail when server is unreachable",
+			statement: "redis.restore('foo', 0, new ArrayBuffer(8))",
+		},
+		{
+			name:      "migrate should fail when server is unreachable",
+			statement: "redis.migrate('localhost', 6380, ['foo'])",
+		},
+		{
+			name:      "unlink should fail when server is unreachable",
+			statement: "redis.unlink('should', 'fail')",
+		},
+		{
+			name:      "touch should fail when server is unreachable",
+			statement: "redis.touch('should', 'fail')",
+		},
+		{
+			name:      "append should fail when server is unreachable",
+			statement: "redis.append('key', 'value')",
+		},
+		{
+			name:      "strlen should fail when server is unreachable",
+			statement: "redis.strlen('key')",
+		},
+		{
+			name:      "getrange should fail when server is unreachable",
+			statement: "redis.getrange('key', 0, -1)",
+		},
+		{
+			name:      "setrange should fail when server is unreachable",
+			statement: "redis.setrange('key', 0, 'value')",
+		},
+		{
+			name:      "getex should fail when server is unreachable",
+			statement: "redis.getex('key')",
+		},
+		{
+			name:      "lmpop should fail when server is unreachable",
+			statement: "redis.lmpop(['key'], 'left')",
+		},
+		{
+			name:      "zmpop should fail when server is unreachable",
+			statement: "redis.zmpop(['key'], 'min')",
+		},
+		{
+			name:      "lpos should fail when server is unreachable",
+			statement: "redis.lpos('key', 'element')",
+		},
+		{
+			name:      "lmove should fail when server is unreachable",
+			statement: "redis.lmove('source', 'destination', 'left', 'right')",
+		},
+		{
+			name:      "sintercard should fail when server is unreachable",
+			statement: "redis.sintercard(['key1', 'key2'])",
+		},
+		{
+			name:      "seed should fail when server is unreachable",
+			statement: "redis.seed({ count: 1 })",
+		},
+		{
+			name:      "deleteByPattern should fail when server is unreachable",
+			statement: "redis.deleteByPattern('loadtest:*')",
+		},
+		{
+			name:      "getRandom should fail when server is unreachable",
+			statement: "redis.getRandom({ pattern: 'user:{i}', count: 10 })",
 		},
 		{
 			name:      "sendCommand should fail when server is unreachable",
@@ -2555,6 +4604,72 @@ func TestClientIsSupportedType(t *testing.T) {
 // testSetup is a helper struct holding components
 // necessary to test the redis client, in the context
 // of the execution of a k6 script.
+
+func TestClientUnixSocket(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunT(t)
+	rs.RegisterCommandHandler("GET", func(c *Connection, _ []string) {
+		c.WriteBulkString("bar")
+	})
+
+	// The stub server only listens on TCP: a Unix domain socket forwards
+	// the connections to it.
+	dir, err := os.MkdirTemp("", "redis")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+
+	path := filepath.Join(dir, "redis.sock")
+	l, err := net.Listen("unix", path)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = l.Close() })
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+
+			upstream, err := net.Dial("tcp", rs.Addr().String())
+			if err != nil {
+				_ = conn.Close()
+				return
+			}
+
+			go func() {
+				_, _ = io.Copy(upstream, conn)
+				_ = upstream.Close()
+			}()
+			go func() {
+				_, _ = io.Copy(conn, upstream)
+				_ = conn.Close()
+			}()
+		}
+	}()
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client({ socket: { path: %q } });
+			const fromURL = new Client('unix://%s');
+
+			redis.get("foo")
+				.then(res => { if (res !== "bar") { throw 'unexpected value for get result: ' + res } })
+				.then(() => fromURL.get("foo"))
+				.then(res => { if (res !== "bar") { throw 'unexpected value for get result: ' + res } })
+		`, path, path))
+
+		return err
+	})
+	require.NoError(t, gotScriptErr)
+	assert.Equal(t, [][]string{
+		{"HELLO", "2"},
+		{"GET", "foo"},
+		{"GET", "foo"},
+	}, rs.GotCommands())
+}
+
 type testSetup struct {
 	runtime *modulestest.Runtime
 	rt      *sobek.Runtime
@@ -2603,8 +4718,9 @@ func newInitContextTestSetup(t testing.TB) testSetup {
 	samples := make(chan metrics.SampleContainer, 1000)
 
 	rt := runtime.VU.RuntimeField
-	m := new(RootModule).NewModuleInstance(runtime.VU)
+	m := New().NewModuleInstance(runtime.VU)
 	require.NoError(t, rt.Set("Client", m.Exports().Named["Client"]))
+	require.NoError(t, rt.Set("ClientGroup", m.Exports().Named["ClientGroup"]))
 
 	return testSetup{
 		runtime: runtime,
@@ -2694,6 +4810,74 @@ func TestClientTLSAuth(t *testing.T) {
 	}, rs.GotCommands())
 }
 
+func TestClientTLSServerName(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunTSecure(t, nil)
+
+	err := ts.rt.Set("caCert", string(rs.TLSCertificate()))
+	require.NoError(t, err)
+
+	// The server's certificate is only valid for localhost, while the
+	// client connects to its IP address.
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client({
+				socket: {
+					host: '%s',
+					port: %d,
+					tls: {
+						ca: [caCert],
+						serverName: 'localhost',
+					}
+				}
+			});
+
+			redis.sendCommand("PING");
+		`, rs.Addr().IP.String(), rs.Addr().Port))
+
+		return err
+	})
+
+	require.NoError(t, gotScriptErr)
+	assert.Equal(t, [][]string{
+		{"HELLO", "2"},
+		{"PING"},
+	}, rs.GotCommands())
+}
+
+func TestClientTLSInsecureSkipVerify(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunTSecure(t, nil)
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client({
+				socket: {
+					host: '%s',
+					port: %d,
+					tls: {
+						insecureSkipVerify: true,
+					}
+				}
+			});
+
+			redis.sendCommand("PING");
+		`, rs.Addr().IP.String(), rs.Addr().Port))
+
+		return err
+	})
+
+	require.NoError(t, gotScriptErr)
+	assert.Equal(t, [][]string{
+		{"HELLO", "2"},
+		{"PING"},
+	}, rs.GotCommands())
+}
+
 func TestClientTLSRespectsNetworkOPtions(t *testing.T) {
 	t.Parallel()
 
@@ -2742,3 +4926,102 @@ func TestClientTLSRespectsNetworkOPtions(t *testing.T) {
 	assert.ErrorContains(t, gotScriptErr, "IP ("+rs.Addr().IP.String()+") is in a blacklisted range")
 	assert.Equal(t, 0, rs.HandledCommandsCount())
 }
+
+func TestClientRedis7Commands(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunT(t)
+	rs.RegisterCommandHandler("LMPOP", func(c *Connection, args []string) {
+		if args[1] == "empty" {
+			c.WriteNull()
+			return
+		}
+		c.WriteValue([]interface{}{args[1], []interface{}{"a", "b"}})
+	})
+	rs.RegisterCommandHandler("BLMPOP", func(c *Connection, args []string) {
+		c.WriteValue([]interface{}{args[2], []interface{}{"job"}})
+	})
+	rs.RegisterCommandHandler("ZMPOP", func(c *Connection, args []string) {
+		c.WriteValue([]interface{}{args[1], []interface{}{[]interface{}{"alice", "1.5"}}})
+	})
+	rs.RegisterCommandHandler("LPOS", func(c *Connection, args []string) {
+		switch {
+		case len(args) > 2 && args[2] == "count":
+			c.WriteValue([]interface{}{int64(1), int64(4)})
+		case args[1] == "missing":
+			c.WriteNull()
+		default:
+			c.WriteInteger(4)
+		}
+	})
+	rs.RegisterCommandHandler("LMOVE", func(c *Connection, _ []string) {
+		c.WriteBulkString("moved")
+	})
+	rs.RegisterCommandHandler("SINTERCARD", func(c *Connection, _ []string) {
+		c.WriteInteger(3)
+	})
+	rs.RegisterCommandHandler("ZINTERCARD", func(c *Connection, _ []string) {
+		c.WriteInteger(2)
+	})
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client('redis://%s');
+
+			redis.lmpop(["jobs", "other"], "left", 2)
+				.then(res => {
+					if (res.key !== "jobs" || res.values.join(",") !== "a,b") { throw 'unexpected value for lmpop result: ' + JSON.stringify(res) }
+				})
+				.then(() => redis.lmpop(["empty"], "right"))
+				.then(res => { if (res !== null) { throw 'unexpected value for lmpop result: ' + JSON.stringify(res) } })
+				.then(() => redis.blmpop(["jobs"], "right", 1))
+				.then(res => { if (res.key !== "jobs" || res.values[0] !== "job") { throw 'unexpected value for blmpop result: ' + JSON.stringify(res) } })
+				.then(() => redis.zmpop(["scores"], "min"))
+				.then(res => {
+					if (res.key !== "scores" || res.members[0].member !== "alice" || res.members[0].score !== 1.5) {
+						throw 'unexpected value for zmpop result: ' + JSON.stringify(res)
+					}
+				})
+				.then(() => redis.lpos("jobs", "b", { rank: -1 }))
+				.then(res => { if (res !== 4) { throw 'unexpected value for lpos result: ' + res } })
+				.then(() => redis.lpos("jobs", "missing"))
+				.then(res => { if (res !== null) { throw 'unexpected value for lpos result: ' + res } })
+				.then(() => redis.lpos("jobs", "b", { count: 0, maxlen: 100 }))
+				.then(res => { if (res.join(",") !== "1,4") { throw 'unexpected value for lpos result: ' + JSON.stringify(res) } })
+				.then(() => redis.lmove("jobs", "done", "left", "right"))
+				.then(res => { if (res !== "moved") { throw 'unexpected value for lmove result: ' + res } })
+				.then(() => redis.sintercard(["s1", "s2"], 10))
+				.then(res => { if (res !== 3) { throw 'unexpected value for sintercard result: ' + res } })
+				.then(() => redis.zintercard(["z1", "z2"]))
+				.then(res => { if (res !== 2) { throw 'unexpected value for zintercard result: ' + res } })
+				.then(() => redis.zmpop(["scores"], "lowest"))
+				.then(
+					res => { throw 'expected zmpop to fail' },
+					err => { if (err.error() !== 'invalid zmpop order "lowest"; expected "min" or "max"') { throw 'unexpected error: ' + err.error() } }
+				)
+				.then(() => redis.lmpop(["jobs"], "left", 0))
+				.then(
+					res => { throw 'expected lmpop to fail' },
+					err => { if (err.error() !== 'invalid lmpop count 0; expected a positive number') { throw 'unexpected error: ' + err.error() } }
+				)
+		`, rs.Addr()))
+
+		return err
+	})
+
+	assert.NoError(t, gotScriptErr)
+	assert.Equal(t, [][]string{
+		{"HELLO", "2"},
+		{"LMPOP", "2", "jobs", "other", "left", "count", "2"},
+		{"LMPOP", "1", "empty", "right", "count", "1"},
+		{"BLMPOP", "1", "1", "jobs", "right", "count", "1"},
+		{"ZMPOP", "1", "scores", "min", "count", "1"},
+		{"LPOS", "jobs", "b", "rank", "-1"},
+		{"LPOS", "jobs", "missing"},
+		{"LPOS", "jobs", "b", "count", "0", "maxlen", "100"},
+		{"LMOVE", "jobs", "done", "left", "right"},
+		{"SINTERCARD", "2", "s1", "s2", "limit", "10"},
+		{"ZINTERCARD", "2", "z1", "z2", "limit", "0"},
+	}, rs.GotCommands())
+}
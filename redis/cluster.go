@@ -0,0 +1,615 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/grafana/sobek"
+	"github.com/redis/go-redis/v9"
+)
+
+// clusterSlotsCount is the number of hash slots of a Redis cluster.
+const clusterSlotsCount = 16384
+
+// keySlot returns the hash slot of `key`, as computed by Redis cluster:
+// the CRC16 of the key, or of its hash tag if it has one, modulo the
+// number of slots.
+func keySlot(key string) int {
+	if start := strings.IndexByte(key, '{'); start > -1 {
+		if end := strings.IndexByte(key[start+1:], '}'); end > 0 {
+			key = key[start+1 : start+1+end]
+		}
+	}
+
+	return int(crc16(key)) % clusterSlotsCount
+}
+
+// crc16 returns the CRC16 (XMODEM) checksum of `s`, the variant used by
+// Redis cluster.
+func crc16(s string) uint16 {
+	var crc uint16
+	for i := 0; i < len(s); i++ {
+		crc ^= uint16(s[i]) << 8
+		for bit := 0; bit < 8; bit++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+
+	return crc
+}
+
+// clusterClient returns the Client's underlying go-redis cluster client,
+// or an error mentioning `method` if the Client isn't a cluster client.
+func (c *Client) clusterClient(method string) (*redis.ClusterClient, error) {
+	cluster, ok := c.redisClient.(*redis.ClusterClient)
+	if !ok {
+		return nil, fmt.Errorf("%s is only supported by cluster clients", method)
+	}
+
+	return cluster, nil
+}
+
+// slotNodes returns the addresses of the master, and of the replicas,
+// serving `slot` in the cluster.
+func slotNodes(ctx context.Context, cluster *redis.ClusterClient, slot int) (string, []string, error) {
+	slots, err := cluster.ClusterSlots(ctx).Result()
+	if err != nil {
+		return "", nil, err
+	}
+
+	for _, s := range slots {
+		if slot < s.Start || slot > s.End || len(s.Nodes) == 0 {
+			continue
+		}
+
+		replicas := make([]string, 0, len(s.Nodes)-1)
+		for _, node := range s.Nodes[1:] {
+			replicas = append(replicas, node.Addr)
+		}
+
+		return s.Nodes[0].Addr, replicas, nil
+	}
+
+	return "", nil, fmt.Errorf("no cluster node serves slot %d", slot)
+}
+
+// nodeClient returns the go-redis client connected to the cluster node
+// at `addr`, through which commands are sent to that node only.
+func nodeClient(ctx context.Context, cluster *redis.ClusterClient, addr string) (*redis.Client, error) {
+	var (
+		mu     sync.Mutex
+		client *redis.Client
+	)
+
+	err := cluster.ForEachShard(ctx, func(_ context.Context, node *redis.Client) error {
+		if node.Options().Addr == addr {
+			mu.Lock()
+			client = node
+			mu.Unlock()
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if client == nil {
+		return nil, fmt.Errorf("no cluster node found at address %q", addr)
+	}
+
+	return client, nil
+}
+
+// RunOnNode sends a command to the cluster node at `addr`, bypassing the
+// slot-based routing of commands. It allows node-level introspection, such
+// as running INFO or CONFIG GET against each node, or reading from a
+// specific replica.
+//
+// The node must be part of the cluster, as listed by ClusterNodes.
+//
+// RunOnNode is only supported by cluster clients.
+func (c *Client) RunOnNode(addr, command string, args ...interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	cluster, err := c.clusterClient("runOnNode")
+	if err != nil {
+		reject(err)
+		return promise
+	}
+
+	if err := c.isSupportedType(2, args...); err != nil {
+		reject(err)
+		return promise
+	}
+
+	doArgs := append([]interface{}{command}, args...)
+
+	go func() {
+		node, err := nodeClient(c.context(), cluster, addr)
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		reply, err := node.Do(c.context(), doArgs...).Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(reply)
+	}()
+
+	return promise
+}
+
+// ClusterSlots returns the layout of the cluster's hash slots, as reported
+// by CLUSTER SLOTS.
+//
+// The promise resolves with an array of objects holding the `start` and
+// `end` slots of each range, both inclusive, and the `master` and
+// `replicas` nodes serving it, as {address, id} objects.
+//
+// ClusterSlots is only supported by cluster clients.
+func (c *Client) ClusterSlots() *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	cluster, err := c.clusterClient("clusterSlots")
+	if err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		slots, err := cluster.ClusterSlots(c.context()).Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		node := func(n redis.ClusterNode) map[string]interface{} {
+			return map[string]interface{}{"address": n.Addr, "id": n.ID}
+		}
+
+		ranges := make([]map[string]interface{}, 0, len(slots))
+		for _, s := range slots {
+			r := map[string]interface{}{
+				"start":    s.Start,
+				"end":      s.End,
+				"master":   nil,
+				"replicas": []map[string]interface{}{},
+			}
+
+			if len(s.Nodes) > 0 {
+				r["master"] = node(s.Nodes[0])
+
+				replicas := make([]map[string]interface{}, 0, len(s.Nodes)-1)
+				for _, n := range s.Nodes[1:] {
+					replicas = append(replicas, node(n))
+				}
+				r["replicas"] = replicas
+			}
+
+			ranges = append(ranges, r)
+		}
+
+		resolve(ranges)
+	}()
+
+	return promise
+}
+
+// ClusterShards returns the shards of the cluster, as reported by CLUSTER
+// SHARDS, available since Redis 7.
+//
+// The promise resolves with an array of objects holding the `slots` ranges
+// served by each shard, as {start, end} objects, and its `nodes`, as
+// {id, address, endpoint, ip, hostname, port, tlsPort, role,
+// replicationOffset, health} objects, the address being the "ip:port" one
+// the nodes are known by, such as by runOnNode.
+//
+// ClusterShards is only supported by cluster clients.
+func (c *Client) ClusterShards() *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	cluster, err := c.clusterClient("clusterShards")
+	if err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		shards, err := cluster.ClusterShards(c.context()).Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		result := make([]map[string]interface{}, 0, len(shards))
+		for _, shard := range shards {
+			slots := make([]map[string]interface{}, 0, len(shard.Slots))
+			for _, r := range shard.Slots {
+				slots = append(slots, map[string]interface{}{"start": r.Start, "end": r.End})
+			}
+
+			nodes := make([]map[string]interface{}, 0, len(shard.Nodes))
+			for _, n := range shard.Nodes {
+				port := n.Port
+				if port == 0 {
+					port = n.TLSPort
+				}
+
+				nodes = append(nodes, map[string]interface{}{
+					"id":                n.ID,
+					"address":           net.JoinHostPort(n.IP, strconv.FormatInt(port, 10)),
+					"endpoint":          n.Endpoint,
+					"ip":                n.IP,
+					"hostname":          n.Hostname,
+					"port":              n.Port,
+					"tlsPort":           n.TLSPort,
+					"role":              n.Role,
+					"replicationOffset": n.ReplicationOffset,
+					"health":            n.Health,
+				})
+			}
+
+			result = append(result, map[string]interface{}{"slots": slots, "nodes": nodes})
+		}
+
+		resolve(result)
+	}()
+
+	return promise
+}
+
+// ClusterNodes lists the nodes of the cluster, as reported by CLUSTER
+// NODES.
+//
+// The promise resolves with an array of objects holding, for each node,
+// its `id` and `address`, its `role`: either "master" or "replica", its
+// `flags`, such as "myself" or "fail", the `masterId` of replicas, the
+// `pingSent` and `pongReceived` Unix times, in milliseconds, its
+// `configEpoch` and `linkState`, and the `slots` ranges it serves, as
+// {start, end} objects. The slots being resharded are listed as
+// `migrating` to, or `importing` from, another node, as {slot, node}
+// objects. The nodes are sorted by address.
+//
+// ClusterNodes is only supported by cluster clients.
+func (c *Client) ClusterNodes() *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	cluster, err := c.clusterClient("clusterNodes")
+	if err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		reply, err := cluster.ClusterNodes(c.context()).Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		nodes, err := parseClusterNodes(reply)
+		if err != nil {
+			reject(fmt.Errorf("invalid clusterNodes reply; %w", err))
+			return
+		}
+
+		resolve(nodes)
+	}()
+
+	return promise
+}
+
+// parseClusterNodes parses the reply of CLUSTER NODES, a line per node, to
+// the objects ClusterNodes resolves with, sorted by address.
+func parseClusterNodes(reply string) ([]map[string]interface{}, error) {
+	var nodes []map[string]interface{}
+
+	for _, line := range strings.Split(reply, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 8 {
+			return nil, fmt.Errorf("unexpected node line: %q", line)
+		}
+
+		// The address is followed by the cluster bus port, and the
+		// hostname, as in ip:port@cport,hostname.
+		address, _, _ := strings.Cut(fields[1], "@")
+		flags := strings.Split(fields[2], ",")
+
+		role := "master"
+		if slices.Contains(flags, "slave") {
+			role = "replica"
+		}
+
+		var masterID interface{}
+		if fields[3] != "-" {
+			masterID = fields[3]
+		}
+
+		var ints [3]int64
+		for idx, field := range fields[4:7] {
+			n, err := strconv.ParseInt(field, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("unexpected node line: %q; %w", line, err)
+			}
+			ints[idx] = n
+		}
+
+		slots := []map[string]interface{}{}
+		migrating := []map[string]interface{}{}
+		importing := []map[string]interface{}{}
+		for _, field := range fields[8:] {
+			if strings.HasPrefix(field, "[") {
+				// Slots being resharded, as [slot->-node] or [slot-<-node].
+				entry := strings.Trim(field, "[]")
+				if slot, node, ok := strings.Cut(entry, "->-"); ok {
+					migrating = append(migrating, map[string]interface{}{"slot": slotNumber(slot), "node": node})
+				} else if slot, node, ok := strings.Cut(entry, "-<-"); ok {
+					importing = append(importing, map[string]interface{}{"slot": slotNumber(slot), "node": node})
+				}
+				continue
+			}
+
+			first, last, _ := strings.Cut(field, "-")
+			if last == "" {
+				last = first
+			}
+			slots = append(slots, map[string]interface{}{"start": slotNumber(first), "end": slotNumber(last)})
+		}
+
+		nodes = append(nodes, map[string]interface{}{
+			"id":           fields[0],
+			"address":      address,
+			"role":         role,
+			"flags":        flags,
+			"masterId":     masterID,
+			"pingSent":     ints[0],
+			"pongReceived": ints[1],
+			"configEpoch":  ints[2],
+			"linkState":    fields[7],
+			"slots":        slots,
+			"migrating":    migrating,
+			"importing":    importing,
+		})
+	}
+
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i]["address"].(string) < nodes[j]["address"].(string)
+	})
+
+	return nodes, nil
+}
+
+// slotNumber parses a slot number of the reply of CLUSTER NODES, which
+// Redis formats itself, returning -1 if it is invalid.
+func slotNumber(s string) int {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return -1
+	}
+
+	return n
+}
+
+// ClusterKeyslot returns the hash slot of `key`, as computed by the
+// cluster with CLUSTER KEYSLOT. Under the keyPrefix option, the slot is
+// the one of the prefixed key, which the key's commands are routed by.
+//
+// The promise resolves with the slot, a number between 0 and 16383.
+//
+// ClusterKeyslot is only supported by cluster clients.
+func (c *Client) ClusterKeyslot(key string) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	cluster, err := c.clusterClient("clusterKeyslot")
+	if err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		slot, err := cluster.ClusterKeySlot(c.context(), key).Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(slot)
+	}()
+
+	return promise
+}
+
+// quorumGetOptions holds the options of the Client's quorumGet method.
+type quorumGetOptions struct {
+	// Replicas is the number of replicas read from, on top of the master.
+	// All the replicas are read from when unset.
+	Replicas *int `json:"replicas,omitempty"`
+}
+
+// QuorumGet reads the value of `key` from the master serving it, and from
+// its replicas, concurrently, and reports whether they agree. It is meant
+// to measure replication consistency windows in cluster tests.
+//
+// The `replicas` option limits the number of replicas read from. Replicas
+// are read from in READONLY mode, regardless of the client's options.
+//
+// The promise resolves with an object holding the address of the `primary`
+// node, the `values` read from each node, indexed by address, null
+// standing for a missing key, and an `agreed` flag, true when all the
+// values are equal. It is rejected if any of the reads fails.
+//
+// QuorumGet is only supported by cluster clients.
+func (c *Client) QuorumGet(key string, options map[string]interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	cluster, err := c.clusterClient("quorumGet")
+	if err != nil {
+		reject(err)
+		return promise
+	}
+
+	var opts quorumGetOptions
+	if err := decodeOptions(options, &opts); err != nil {
+		reject(fmt.Errorf("invalid quorumGet options; reason: %w", err))
+		return promise
+	}
+
+	if opts.Replicas != nil && *opts.Replicas < 0 {
+		reject(fmt.Errorf("invalid replicas option: %d; expected a positive number", *opts.Replicas))
+		return promise
+	}
+
+	go func() {
+		ctx := c.context()
+
+		// The key is sent prefixed with the keyPrefix option, which the
+		// slot serving it depends on.
+		primary, replicas, err := slotNodes(ctx, cluster, keySlot(c.redisOptions.KeyPrefix+key))
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		if opts.Replicas != nil && *opts.Replicas < len(replicas) {
+			replicas = replicas[:*opts.Replicas]
+		}
+
+		values, err := quorumRead(ctx, cluster, key, append([]string{primary}, replicas...))
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		agreed := true
+		for _, value := range values {
+			if value != values[primary] {
+				agreed = false
+				break
+			}
+		}
+
+		resolve(map[string]interface{}{
+			"primary": primary,
+			"values":  values,
+			"agreed":  agreed,
+		})
+	}()
+
+	return promise
+}
+
+// quorumRead concurrently reads the value of `key` from each of the nodes
+// at `addrs`. Missing keys are reported as nil values.
+func quorumRead(ctx context.Context, cluster *redis.ClusterClient, key string, addrs []string) (map[string]interface{}, error) {
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		values = make(map[string]interface{}, len(addrs))
+		errs   []error
+	)
+
+	for _, addr := range addrs {
+		addr := addr
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			value, err := readFromNode(ctx, cluster, addr, key)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if err != nil {
+				errs = append(errs, fmt.Errorf("reading %q from node %s failed; reason: %w", key, addr, err))
+				return
+			}
+			values[addr] = value
+		}()
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return values, nil
+}
+
+// readFromNode reads the value of `key` from the cluster node at `addr`.
+// As the node may be a replica, the connection is switched to READONLY
+// mode first. It returns nil if the key doesn't exist.
+func readFromNode(ctx context.Context, cluster *redis.ClusterClient, addr, key string) (interface{}, error) {
+	node, err := nodeClient(ctx, cluster, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	var get *redis.StringCmd
+	_, err = node.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ReadOnly(ctx)
+		get = pipe.Get(ctx, key)
+		return nil
+	})
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+
+	value, err := get.Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+
+	return value, err
+}
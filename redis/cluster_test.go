@@ -0,0 +1,289 @@
+package redis
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeySlot(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, 12739, keySlot("123456789"))
+	assert.Equal(t, 12182, keySlot("foo"))
+	assert.Equal(t, 5061, keySlot("bar"))
+	assert.Equal(t, keySlot("bar"), keySlot("{bar}foo"))
+	assert.Equal(t, keySlot("{user1000}.following"), keySlot("{user1000}.followers"))
+
+	// Empty hash tags are not hash tags: the whole key is hashed.
+	assert.Equal(t, int(crc16("foo{}{bar}"))%clusterSlotsCount, keySlot("foo{}{bar}"))
+}
+
+func TestClientQuorumGet(t *testing.T) {
+	t.Parallel()
+
+	t.Run("values are read from the master and its replicas", func(t *testing.T) {
+		t.Parallel()
+
+		ts := newTestSetup(t)
+		master, upToDate, lagging := RunT(t), RunT(t), RunT(t)
+		registerClusterSlotsHandler(stubClusterShard{master: master, replicas: []*StubServer{upToDate, lagging}})
+
+		for rs, value := range map[*StubServer]string{master: "new", upToDate: "new", lagging: "old"} {
+			value := value
+			rs.RegisterCommandHandler("GET", func(c *Connection, _ []string) {
+				c.WriteBulkString(value)
+			})
+		}
+
+		gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+			_, err := ts.rt.RunString(fmt.Sprintf(`
+				const redis = new Client({
+					cluster: {
+						nodes: ['redis://%s', 'redis://%s'],
+					},
+				});
+
+				redis.quorumGet("foo")
+					.then(res => {
+						if (res.primary !== "%s" || res.agreed !== false || Object.keys(res.values).length !== 3) {
+							throw 'unexpected quorumGet result: ' + JSON.stringify(res)
+						}
+
+						if (res.values["%s"] !== "new" || res.values["%s"] !== "new" || res.values["%s"] !== "old") {
+							throw 'unexpected quorumGet values: ' + JSON.stringify(res.values)
+						}
+					})
+					.then(() => redis.quorumGet("foo", { replicas: 1 }))
+					.then(res => {
+						if (res.agreed !== true || Object.keys(res.values).length !== 2) {
+							throw 'unexpected quorumGet result: ' + JSON.stringify(res)
+						}
+					})
+			`, master.Addr(), upToDate.Addr(), master.Addr(), master.Addr(), upToDate.Addr(), lagging.Addr()))
+
+			return err
+		})
+
+		assert.NoError(t, gotScriptErr)
+		assert.Contains(t, lagging.GotCommands(), []string{"READONLY"})
+	})
+
+	t.Run("missing keys are reported as null", func(t *testing.T) {
+		t.Parallel()
+
+		ts := newTestSetup(t)
+		master, replica := RunT(t), RunT(t)
+		registerClusterSlotsHandler(stubClusterShard{master: master, replicas: []*StubServer{replica}})
+
+		for _, rs := range []*StubServer{master, replica} {
+			rs.RegisterCommandHandler("GET", func(c *Connection, _ []string) {
+				c.WriteNull()
+			})
+		}
+
+		gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+			_, err := ts.rt.RunString(fmt.Sprintf(`
+				const redis = new Client({
+					cluster: {
+						nodes: ['redis://%s', 'redis://%s'],
+					},
+				});
+
+				redis.quorumGet("foo")
+					.then(res => {
+						if (res.agreed !== true || res.values["%s"] !== null || res.values["%s"] !== null) {
+							throw 'unexpected quorumGet result: ' + JSON.stringify(res)
+						}
+					})
+			`, master.Addr(), replica.Addr(), master.Addr(), replica.Addr()))
+
+			return err
+		})
+
+		assert.NoError(t, gotScriptErr)
+	})
+
+	t.Run("non-cluster clients are rejected", func(t *testing.T) {
+		t.Parallel()
+
+		ts := newTestSetup(t)
+		rs := RunT(t)
+
+		gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+			_, err := ts.rt.RunString(fmt.Sprintf(`
+				const redis = new Client('redis://%s');
+
+				redis.quorumGet("foo")
+					.then(
+						res => { throw 'expected quorumGet to fail' },
+						err => { if (err.error() !== 'quorumGet is only supported by cluster clients') { throw 'unexpected error: ' + err } }
+					)
+			`, rs.Addr()))
+
+			return err
+		})
+
+		assert.NoError(t, gotScriptErr)
+		assert.Equal(t, 0, rs.HandledCommandsCount())
+	})
+}
+
+func TestClientRunOnNode(t *testing.T) {
+	t.Parallel()
+
+	t.Run("commands are sent to the targeted node", func(t *testing.T) {
+		t.Parallel()
+
+		ts := newTestSetup(t)
+		rs1, rs2 := RunT(t), RunT(t)
+		registerClusterSlotsHandler(stubClusterShard{master: rs1}, stubClusterShard{master: rs2})
+
+		for _, rs := range []*StubServer{rs1, rs2} {
+			addr := rs.Addr().String()
+			rs.RegisterCommandHandler("INFO", func(c *Connection, _ []string) {
+				c.WriteBulkString("# Server\r\naddr:" + addr)
+			})
+		}
+
+		gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+			_, err := ts.rt.RunString(fmt.Sprintf(`
+				const redis = new Client({
+					cluster: {
+						nodes: ['redis://%s', 'redis://%s'],
+					},
+				});
+
+				redis.runOnNode("%s", "INFO", "server")
+					.then(res => { if (!res.endsWith("addr:%s")) { throw 'unexpected runOnNode result: ' + res } })
+					.then(() => redis.runOnNode("127.0.0.1:1", "INFO"))
+					.then(
+						res => { throw 'expected runOnNode to fail' },
+						err => { if (err.error() !== 'no cluster node found at address "127.0.0.1:1"') { throw 'unexpected error: ' + err } }
+					)
+			`, rs1.Addr(), rs2.Addr(), rs2.Addr(), rs2.Addr()))
+
+			return err
+		})
+
+		assert.NoError(t, gotScriptErr)
+		assert.Contains(t, rs2.GotCommands(), []string{"INFO", "server"})
+		assert.NotContains(t, rs1.GotCommands(), []string{"INFO", "server"})
+	})
+
+	t.Run("non-cluster clients are rejected", func(t *testing.T) {
+		t.Parallel()
+
+		ts := newTestSetup(t)
+		rs := RunT(t)
+
+		gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+			_, err := ts.rt.RunString(fmt.Sprintf(`
+				const redis = new Client('redis://%s');
+
+				redis.runOnNode("%s", "INFO")
+					.then(
+						res => { throw 'expected runOnNode to fail' },
+						err => { if (err.error() !== 'runOnNode is only supported by cluster clients') { throw 'unexpected error: ' + err } }
+					)
+			`, rs.Addr(), rs.Addr()))
+
+			return err
+		})
+
+		assert.NoError(t, gotScriptErr)
+		assert.Equal(t, 0, rs.HandledCommandsCount())
+	})
+}
+
+func TestClientClusterNodes(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	master, replica := RunT(t), RunT(t)
+	registerClusterSlotsHandler(stubClusterShard{master: master, replicas: []*StubServer{replica}})
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client({
+				cluster: {
+					nodes: ['redis://%s', 'redis://%s'],
+				},
+			});
+
+			redis.clusterNodes()
+				.then(nodes => {
+					const byAddress = {};
+					nodes.forEach(node => { byAddress[node.address] = node });
+
+					const m = byAddress["%s"], r = byAddress["%s"];
+					if (nodes.length !== 2 || m.role !== "master" || r.role !== "replica" || r.masterId !== m.id) {
+						throw 'unexpected clusterNodes result: ' + JSON.stringify(nodes)
+					}
+					if (m.slots.length !== 1 || m.slots[0].start !== 0 || m.slots[0].end !== 16383 || r.slots.length !== 0) {
+						throw 'unexpected clusterNodes slots: ' + JSON.stringify(nodes)
+					}
+				})
+				.then(() => redis.clusterSlots())
+				.then(slots => {
+					if (slots.length !== 1 || slots[0].end !== 16383 || slots[0].master.address !== "%s" || slots[0].replicas[0].address !== "%s") {
+						throw 'unexpected clusterSlots result: ' + JSON.stringify(slots)
+					}
+				})
+				.then(() => redis.clusterShards())
+				.then(shards => {
+					if (shards.length !== 1 || shards[0].slots[0].end !== 16383 || shards[0].nodes.length !== 2 ||
+						shards[0].nodes[1].address !== "%s" || shards[0].nodes[1].role !== "replica") {
+						throw 'unexpected clusterShards result: ' + JSON.stringify(shards)
+					}
+				})
+				.then(() => redis.clusterKeyslot("{user1000}.following"))
+				.then(slot => { if (slot !== %d) { throw 'unexpected clusterKeyslot result: ' + slot } })
+		`, master.Addr(), replica.Addr(), master.Addr(), replica.Addr(), master.Addr(), replica.Addr(), replica.Addr(),
+			keySlot("user1000")))
+
+		return err
+	})
+
+	assert.NoError(t, gotScriptErr)
+}
+
+func TestParseClusterNodes(t *testing.T) {
+	t.Parallel()
+
+	nodes, err := parseClusterNodes(
+		"e7d1eecce10fd6bb5eb35b9f99a514335d9ba9ca 127.0.0.1:30002@31002,node-2 master - 0 1426238316232 2 connected 5461-10922 [77->-292f8b365bb7edb5e285caf0b7e6ddc7265d2f4f]\n" +
+			"292f8b365bb7edb5e285caf0b7e6ddc7265d2f4f 127.0.0.1:30001@31001 myself,master - 0 0 1 connected 0-5460 77 [93-<-e7d1eecce10fd6bb5eb35b9f99a514335d9ba9ca]\n" +
+			"6ec23923021cf3ffec47632106199cb7f496ce01 127.0.0.1:30005@31005 slave,fail 292f8b365bb7edb5e285caf0b7e6ddc7265d2f4f 1426238316232 0 1 disconnected\n",
+	)
+	require.NoError(t, err)
+	require.Len(t, nodes, 3)
+
+	assert.Equal(t, map[string]interface{}{
+		"id":           "292f8b365bb7edb5e285caf0b7e6ddc7265d2f4f",
+		"address":      "127.0.0.1:30001",
+		"role":         "master",
+		"flags":        []string{"myself", "master"},
+		"masterId":     nil,
+		"pingSent":     int64(0),
+		"pongReceived": int64(0),
+		"configEpoch":  int64(1),
+		"linkState":    "connected",
+		"slots":        []map[string]interface{}{{"start": 0, "end": 5460}, {"start": 77, "end": 77}},
+		"migrating":    []map[string]interface{}{},
+		"importing":    []map[string]interface{}{{"slot": 93, "node": "e7d1eecce10fd6bb5eb35b9f99a514335d9ba9ca"}},
+	}, nodes[0])
+
+	assert.Equal(t, "127.0.0.1:30002", nodes[1]["address"])
+	assert.Equal(t, []map[string]interface{}{{"slot": 77, "node": "292f8b365bb7edb5e285caf0b7e6ddc7265d2f4f"}}, nodes[1]["migrating"])
+
+	assert.Equal(t, "replica", nodes[2]["role"])
+	assert.Equal(t, []string{"slave", "fail"}, nodes[2]["flags"])
+	assert.Equal(t, "292f8b365bb7edb5e285caf0b7e6ddc7265d2f4f", nodes[2]["masterId"])
+	assert.Equal(t, "disconnected", nodes[2]["linkState"])
+
+	_, err = parseClusterNodes("invalid line\n")
+	assert.Error(t, err)
+}
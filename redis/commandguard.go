@@ -0,0 +1,151 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// commandNotAllowedError is the error the commands excluded by the
+// allowedCommands, or blockedCommands, options are failed with.
+type commandNotAllowedError struct {
+	command string
+	option  string
+}
+
+// Error implements the error interface.
+func (e *commandNotAllowedError) Error() string {
+	if e.option == "blockedCommands" {
+		return fmt.Sprintf("the %s command is not allowed, as it is listed in the blockedCommands option", e.command)
+	}
+
+	return fmt.Sprintf("the %s command is not allowed, as it isn't listed in the allowedCommands option", e.command)
+}
+
+// commandGuardHook is the go-redis hook implementing the allowedCommands,
+// and blockedCommands, options: it fails the commands sent on behalf of a
+// Client that its options exclude, before they reach the server. Pipelines,
+// and transactions, holding such a command are failed as a whole.
+//
+// As writeGuardHook, the hook is installed on the go-redis client, and on
+// the nodes of cluster clients. The hooks of the nodes let the CLUSTER
+// SLOTS commands through, as go-redis sends them on its own to discover
+// the cluster's topology, while those sent by the Client go through the
+// hook of the go-redis client first.
+type commandGuardHook struct {
+	node bool
+}
+
+var _ redis.Hook = commandGuardHook{}
+
+// DialHook implements the redis.Hook interface.
+func (commandGuardHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+// ProcessHook implements the redis.Hook interface.
+func (h commandGuardHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		if err := h.checkCommandsAllowed(ctx, cmd); err != nil {
+			cmd.SetErr(err)
+			return err
+		}
+
+		return next(ctx, cmd)
+	}
+}
+
+// ProcessPipelineHook implements the redis.Hook interface.
+func (h commandGuardHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		if err := h.checkCommandsAllowed(ctx, cmds...); err != nil {
+			for _, cmd := range cmds {
+				cmd.SetErr(err)
+			}
+			return err
+		}
+
+		return next(ctx, cmds)
+	}
+}
+
+// checkCommandsAllowed returns a commandNotAllowedError if any of `cmds` is
+// excluded by the options of the Client carried by the context.
+func (h commandGuardHook) checkCommandsAllowed(ctx context.Context, cmds ...redis.Cmder) error {
+	c, ok := clientFromContext(ctx)
+	if !ok || c.redisOptions == nil {
+		return nil
+	}
+
+	allowed, blocked := c.redisOptions.AllowedCommands, c.redisOptions.BlockedCommands
+	if len(allowed) == 0 && len(blocked) == 0 {
+		return nil
+	}
+
+	for _, cmd := range cmds {
+		name, subcommand := commandNames(cmd)
+		if h.node && subcommand == "cluster slots" {
+			continue
+		}
+
+		if listed, ok := listedCommand(blocked, name, subcommand); ok {
+			return &commandNotAllowedError{command: listed, option: "blockedCommands"}
+		}
+
+		if len(allowed) == 0 {
+			continue
+		}
+		if _, ok := listedCommand(allowed, name, subcommand); !ok {
+			// The subcommand is reported when only some of the command's
+			// subcommands are allowed, such as "config get".
+			command := name
+			if _, ok := listedCommand(allowed, "", name+" "); ok && subcommand != "" {
+				command = subcommand
+			}
+
+			return &commandNotAllowedError{command: command, option: "allowedCommands"}
+		}
+	}
+
+	return nil
+}
+
+// commandNames returns the lowercase name of `cmd`, and, if its second
+// argument is a string, the name along with it, as the name of its
+// subcommand, such as "config set".
+func commandNames(cmd redis.Cmder) (string, string) {
+	name := strings.ToLower(cmd.Name())
+
+	args := cmd.Args()
+	if len(args) < 2 {
+		return name, ""
+	}
+
+	subcommand, ok := args[1].(string)
+	if !ok {
+		return name, ""
+	}
+
+	return name, name + " " + strings.ToLower(subcommand)
+}
+
+// listedCommand returns the entry of `commands`, the value of the
+// allowedCommands or blockedCommands option, listing the command `name`,
+// or its `subcommand`, regardless of their case, and whether there is one.
+// A `subcommand` ending with a space matches any subcommand of the command.
+func listedCommand(commands []string, name, subcommand string) (string, bool) {
+	for _, command := range commands {
+		command = strings.ToLower(strings.Join(strings.Fields(command), " "))
+		switch {
+		case command == name:
+			return command, true
+		case subcommand == "":
+		case command == subcommand, strings.HasSuffix(subcommand, " ") && strings.HasPrefix(command, subcommand):
+			return command, true
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,103 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientCommandGuard(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunT(t)
+	rs.RegisterCommandHandler("GET", func(c *Connection, _ []string) {
+		c.WriteBulkString("bar")
+	})
+	rs.RegisterCommandHandler("CONFIG", func(c *Connection, _ []string) {
+		c.WriteValue([]interface{}{"maxmemory", "0"})
+	})
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client({
+				socket: { host: "%s", port: %d },
+				allowedCommands: ["GET", "set", "config get", "keys"],
+				blockedCommands: ["keys"],
+			});
+
+			if (redis.options().allowedCommands.length !== 4 || redis.options().blockedCommands[0] !== "keys") {
+				throw 'expected the allowedCommands and blockedCommands options to be reported';
+			}
+
+			redis.get("foo")
+				.then(res => { if (res !== "bar") { throw 'unexpected value for get result: ' + res } })
+				.then(() => redis.sendCommand("CONFIG", "GET", "maxmemory"))
+				.then(res => { if (res[0] !== "maxmemory") { throw 'unexpected value for config get result: ' + res } })
+				.then(() => redis.sendCommand("KEYS", "*"))
+				.then(
+					res => { throw 'expected keys to fail' },
+					err => {
+						if (err.kind !== "command_not_allowed") { throw 'unexpected error kind: ' + err.kind }
+						if (err.error() !== "the keys command is not allowed, as it is listed in the blockedCommands option") {
+							throw 'unexpected error: ' + err.error()
+						}
+					}
+				)
+				.then(() => redis.sendCommand("CONFIG", "SET", "maxmemory", "1gb"))
+				.then(
+					res => { throw 'expected config set to fail' },
+					err => {
+						if (err.error() !== "the config set command is not allowed, as it isn't listed in the allowedCommands option") {
+							throw 'unexpected error: ' + err.error()
+						}
+					}
+				)
+				.then(() => redis.pipeline().get("foo").sendCommand("FLUSHALL").exec())
+				.then(
+					res => { throw 'expected the pipeline to fail' },
+					err => {
+						if (err.error() !== "the flushall command is not allowed, as it isn't listed in the allowedCommands option") {
+							throw 'unexpected error: ' + err.error()
+						}
+					}
+				)
+		`, rs.Addr().IP, rs.Addr().Port))
+
+		return err
+	})
+	require.NoError(t, gotScriptErr)
+
+	assert.Equal(t, [][]string{
+		{"HELLO", "2"},
+		{"GET", "foo"},
+		{"CONFIG", "GET", "maxmemory"},
+	}, rs.GotCommands())
+}
+
+func TestListedCommand(t *testing.T) {
+	t.Parallel()
+
+	commands := []string{"GET", "config  Get", "client"}
+	for _, tc := range []struct {
+		args   []interface{}
+		listed string
+		ok     bool
+	}{
+		{[]interface{}{"get", "foo"}, "get", true},
+		{[]interface{}{"SET", "foo", "bar"}, "", false},
+		{[]interface{}{"config", "get", "maxmemory"}, "config get", true},
+		{[]interface{}{"config", "set", "maxmemory", "1gb"}, "", false},
+		{[]interface{}{"client", "list"}, "client", true},
+		{[]interface{}{"config"}, "", false},
+	} {
+		name, subcommand := commandNames(redis.NewCmd(context.Background(), tc.args...))
+		listed, ok := listedCommand(commands, name, subcommand)
+		assert.Equal(t, tc.ok, ok, tc.args)
+		assert.Equal(t, tc.listed, listed, tc.args)
+	}
+}
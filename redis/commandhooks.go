@@ -0,0 +1,310 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/grafana/sobek"
+	"github.com/mstoykov/k6-taskqueue-lib/taskqueue"
+	"github.com/redis/go-redis/v9"
+	"go.k6.io/k6/js/common"
+)
+
+// commandHook is a pair of callbacks added with the Client's addHook
+// method. Either of them may be unset.
+type commandHook struct {
+	before sobek.Callable
+	after  sobek.Callable
+}
+
+// commandHooks holds the hooks added to a Client, and to the clients
+// derived from it with withTimeout and withDatabase.
+//
+// The hooks are JS functions, which can only be called on the event loop,
+// while commands are sent from goroutines. Hence, the hooks are called
+// through a task queue, which is kept open while the Client has pending
+// promises, so that the event loop keeps running until their commands
+// have completed.
+type commandHooks struct {
+	mu      sync.Mutex
+	hooks   []commandHook
+	queue   *taskqueue.TaskQueue
+	pending int
+}
+
+// add adds `hook`. It must be called on the event loop.
+func (h *commandHooks) add(hook commandHook) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.hooks = append(h.hooks, hook)
+}
+
+// hold opens the task queue the hooks are called through, if there are
+// hooks, until the returned function is called. It must be called on the
+// event loop, when a promise is created.
+func (h *commandHooks) hold(register func() func(func() error)) func() {
+	if h == nil {
+		return func() {}
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.hooks) == 0 {
+		return func() {}
+	}
+
+	if h.queue == nil {
+		h.queue = taskqueue.New(register)
+	}
+	h.pending++
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+
+			h.pending--
+			if h.pending == 0 {
+				h.queue.Close()
+				h.queue = nil
+			}
+		})
+	}
+}
+
+// call calls `fn` with the hooks on the event loop, and waits for it to
+// return. It does nothing if there are no hooks, or if the task queue
+// isn't open, as for commands which aren't sent on behalf of a promise.
+func (h *commandHooks) call(ctx context.Context, fn func(hooks []commandHook) error) error {
+	h.mu.Lock()
+	if len(h.hooks) == 0 || h.queue == nil {
+		h.mu.Unlock()
+		return nil
+	}
+
+	hooks := h.hooks
+	done := make(chan error, 1)
+	h.queue.Queue(func() error {
+		done <- fn(hooks)
+		return nil
+	})
+	h.mu.Unlock()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// AddHook adds callbacks called around each command sent by the client,
+// and the clients derived from it with withTimeout and withDatabase, such
+// as to log, tag, audit, or measure specific commands, without wrapping
+// every call site. The `hook` object holds either, or both, of the callbacks:
+//   - beforeCommand is called with the command, an object holding its
+//     `name`, and its `args`, before it is sent. The callback may modify
+//     the arguments in place, such as to prefix keys, but not their number.
+//   - afterCommand is called with the command, once its reply is received,
+//     along with its `durationMs`, and its `error` message, or null if it
+//     succeeded.
+//
+// The commands of pipelines and transactions are passed to the callbacks
+// one by one. An exception thrown by a callback fails the command, with
+// the exception's message.
+//
+// The callbacks are called on the event loop, which delays the commands
+// until it is available. Hence, blocking clients don't support hooks.
+func (c *Client) AddHook(hook sobek.Value) {
+	rt := c.vu.Runtime()
+
+	if c.syncCalls != nil {
+		common.Throw(rt, errors.New("addHook is not supported by blocking clients"))
+	}
+
+	if c.hooks == nil {
+		common.Throw(rt, errors.New("addHook is not supported by this client"))
+	}
+
+	if common.IsNullish(hook) {
+		common.Throw(rt, errors.New("addHook requires an object with a beforeCommand or afterCommand function"))
+	}
+
+	obj := hook.ToObject(rt)
+
+	var h commandHook
+	for name, callback := range map[string]*sobek.Callable{"beforeCommand": &h.before, "afterCommand": &h.after} {
+		value := obj.Get(name)
+		if common.IsNullish(value) {
+			continue
+		}
+
+		fn, ok := sobek.AssertFunction(value)
+		if !ok {
+			common.Throw(rt, fmt.Errorf("invalid hook; %s must be a function", name))
+		}
+		*callback = fn
+	}
+
+	if h.before == nil && h.after == nil {
+		common.Throw(rt, errors.New("addHook requires an object with a beforeCommand or afterCommand function"))
+	}
+
+	c.hooks.add(h)
+}
+
+// callbackHook is the go-redis hook calling the hooks added to the Client
+// carried by the command's context.
+type callbackHook struct{}
+
+var _ redis.Hook = callbackHook{}
+
+// DialHook implements the redis.Hook interface.
+func (callbackHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+// ProcessHook implements the redis.Hook interface.
+func (h callbackHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		c, ok := clientFromContext(ctx)
+		if !ok || c.hooks == nil {
+			return next(ctx, cmd)
+		}
+
+		if err := c.hooks.call(ctx, func(hooks []commandHook) error {
+			return c.beforeCommand(hooks, cmd)
+		}); err != nil {
+			return err
+		}
+
+		start := time.Now()
+		err := next(ctx, cmd)
+		duration := time.Since(start)
+
+		if hookErr := c.hooks.call(ctx, func(hooks []commandHook) error {
+			return c.afterCommand(hooks, duration, cmd, err)
+		}); hookErr != nil {
+			return hookErr
+		}
+
+		return err
+	}
+}
+
+// ProcessPipelineHook implements the redis.Hook interface.
+func (h callbackHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		c, ok := clientFromContext(ctx)
+		if !ok || c.hooks == nil {
+			return next(ctx, cmds)
+		}
+
+		if err := c.hooks.call(ctx, func(hooks []commandHook) error {
+			return c.beforeCommand(hooks, cmds...)
+		}); err != nil {
+			return err
+		}
+
+		start := time.Now()
+		err := next(ctx, cmds)
+		duration := time.Since(start)
+
+		if hookErr := c.hooks.call(ctx, func(hooks []commandHook) error {
+			for _, cmd := range cmds {
+				if err := c.afterCommand(hooks, duration, cmd, cmd.Err()); err != nil {
+					return err
+				}
+			}
+			return nil
+		}); hookErr != nil {
+			return hookErr
+		}
+
+		return err
+	}
+}
+
+// beforeCommand calls the beforeCommand callbacks of `hooks` with each of
+// `cmds`, and applies the changes they made to the commands' arguments. It
+// must be called on the event loop.
+func (c *Client) beforeCommand(hooks []commandHook, cmds ...redis.Cmder) error {
+	rt := c.vu.Runtime()
+
+	for _, cmd := range cmds {
+		args := cmd.Args()
+
+		for _, hook := range hooks {
+			if hook.before == nil {
+				continue
+			}
+
+			obj := rt.NewObject()
+			_ = obj.Set("name", cmd.Name())
+			_ = obj.Set("args", rt.NewArray(args...))
+
+			if _, err := hook.before(sobek.Undefined(), obj); err != nil {
+				return err
+			}
+
+			var modified []interface{}
+			if err := rt.ExportTo(obj.Get("args"), &modified); err != nil {
+				return fmt.Errorf("beforeCommand set invalid args for %s; reason: %w", cmd.Name(), err)
+			}
+
+			if len(modified) != len(args) {
+				return fmt.Errorf(
+					"beforeCommand must not change the number of arguments of %s; got %d, expected %d",
+					cmd.Name(), len(modified), len(args),
+				)
+			}
+
+			// The arguments left untouched are kept as is, rather than
+			// replaced with their JS conversion.
+			for idx, arg := range modified {
+				if !reflect.DeepEqual(arg, args[idx]) {
+					args[idx] = arg
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// afterCommand calls the afterCommand callbacks of `hooks` with `cmd`, which
+// completed with `err` after `duration`. It must be called on the event
+// loop.
+func (c *Client) afterCommand(hooks []commandHook, duration time.Duration, cmd redis.Cmder, err error) error {
+	rt := c.vu.Runtime()
+
+	var message interface{}
+	if isCommandError(err) {
+		message = err.Error()
+	}
+
+	for _, hook := range hooks {
+		if hook.after == nil {
+			continue
+		}
+
+		obj := rt.NewObject()
+		_ = obj.Set("name", cmd.Name())
+		_ = obj.Set("args", rt.NewArray(cmd.Args()...))
+		_ = obj.Set("durationMs", float64(duration)/float64(time.Millisecond))
+		_ = obj.Set("error", message)
+
+		if _, err := hook.after(sobek.Undefined(), obj); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,112 @@
+package redis
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientAddHook(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunT(t)
+	rs.RegisterCommandHandler("GET", func(c *Connection, _ []string) {
+		c.WriteBulkString("bar")
+	})
+	rs.RegisterCommandHandler("SET", func(c *Connection, _ []string) {
+		c.WriteOK()
+	})
+	rs.RegisterCommandHandler("LPUSH", func(c *Connection, _ []string) {
+		c.WriteError(errors.New("WRONGTYPE Operation against a key holding the wrong kind of value"))
+	})
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client('redis://%s');
+
+			var seen = [];
+			redis.addHook({
+				beforeCommand: (cmd) => {
+					if (cmd.name === 'get' || cmd.name === 'set') {
+						cmd.args[1] = 'test:' + cmd.args[1];
+					}
+					if (cmd.name === 'del') {
+						throw 'del is forbidden';
+					}
+				},
+			});
+			redis.addHook({
+				afterCommand: (cmd) => {
+					if (typeof cmd.durationMs !== 'number') {
+						throw 'expected durationMs to be a number';
+					}
+					seen.push(cmd.name + ' ' + cmd.args[1] + ' ' + (cmd.error ? 'failed' : 'succeeded'));
+				},
+			});
+
+			redis.get("foo")
+				.then(res => { if (res !== "bar") { throw 'unexpected value for get result: ' + res } })
+				.then(() => redis.lpush("foo", "bar"))
+				.then(
+					res => { throw 'expected lpush to fail' },
+					err => {}
+				)
+				.then(() => redis.del("foo"))
+				.then(
+					res => { throw 'expected del to fail' },
+					err => { if (!err.error().includes('del is forbidden')) { throw 'unexpected error: ' + err.error() } }
+				)
+				.then(() => redis.pipeline().set("foo", "bar").get("foo").exec())
+		`, rs.Addr()))
+
+		return err
+	})
+	require.NoError(t, gotScriptErr)
+
+	var seen []string
+	require.NoError(t, ts.rt.ExportTo(ts.rt.Get("seen"), &seen))
+	assert.Equal(t, []string{
+		"get test:foo succeeded",
+		"lpush foo failed",
+		"set test:foo succeeded",
+		"get test:foo succeeded",
+	}, seen)
+
+	assert.Equal(t, [][]string{
+		{"HELLO", "2"},
+		{"GET", "test:foo"},
+		{"LPUSH", "foo", "bar"},
+		{"SET", "test:foo", "bar"},
+		{"GET", "test:foo"},
+	}, rs.GotCommands())
+}
+
+func TestClientAddHookInvalid(t *testing.T) {
+	t.Parallel()
+
+	for name, script := range map[string]string{
+		"no callbacks":    `redis.addHook({})`,
+		"not a function":  `redis.addHook({ beforeCommand: 'foo' })`,
+		"nullish hook":    `redis.addHook(null)`,
+		"blocking client": `new Client({ socket: { host: 'localhost', port: 6379 }, blocking: true }).addHook({ afterCommand: () => {} })`,
+	} {
+		script := script
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ts := newTestSetup(t)
+			gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+				_, err := ts.rt.RunString(`
+					const redis = new Client('redis://localhost:6379');
+					` + script)
+
+				return err
+			})
+			assert.Error(t, gotScriptErr)
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+// defaultLogValueLength is the length, in bytes, the arguments of the
+// commands logged with the logCommands option are truncated to, unless the
+// logValueLength option says otherwise.
+const defaultLogValueLength = 64
+
+// redacted replaces the secrets of the commands logged with the
+// logCommands option.
+const redacted = "[redacted]"
+
+// logCommand logs `cmd`, which completed with `err` after `duration`, at
+// debug level, through the logger of the VU of the Client sending it, if it
+// has the logCommands option set.
+//
+// The command is logged as it is sent, keys prefixed, and values
+// compressed, included, with its secrets redacted, see redactedArgs, and
+// its arguments truncated to the logValueLength option.
+func logCommand(ctx context.Context, duration time.Duration, cmd redis.Cmder, err error) {
+	c, ok := clientFromContext(ctx)
+	if !ok || c.redisOptions == nil || !c.redisOptions.LogCommands {
+		return
+	}
+
+	state := c.vu.State()
+	if state == nil || state.Logger == nil {
+		return
+	}
+
+	// Building the entry is skipped when debug logs are discarded anyway.
+	if leveled, ok := state.Logger.(interface{ IsLevelEnabled(logrus.Level) bool }); ok &&
+		!leveled.IsLevelEnabled(logrus.DebugLevel) {
+		return
+	}
+
+	fields := logrus.Fields{
+		"command":  strings.ToLower(cmd.Name()),
+		"args":     c.loggedArgs(cmd),
+		"duration": float64(duration) / float64(time.Millisecond),
+		"outcome":  commandOutcome(err),
+	}
+	if keys := commandKeys(cmd); len(keys) > 0 {
+		fields["keys"] = keys
+	}
+	if isCommandError(err) {
+		fields["error"] = err.Error()
+	}
+
+	state.Logger.WithFields(fields).Debug("Sent a redis command")
+}
+
+// commandOutcome returns the outcome of a command which completed with
+// `err`: "ok", "nil" for nil replies, or "error".
+func commandOutcome(err error) string {
+	switch {
+	case err == nil:
+		return "ok"
+	case errors.Is(err, redis.Nil):
+		return "nil"
+	default:
+		return "error"
+	}
+}
+
+// commandKeys returns the keys of `cmd`, see keyIndexes.
+func commandKeys(cmd redis.Cmder) []string {
+	args := cmd.Args()
+	indexes := keyIndexes(cmd)
+
+	keys := make([]string, 0, len(indexes))
+	for _, idx := range indexes {
+		keys = append(keys, argString(args[idx]))
+	}
+
+	return keys
+}
+
+// loggedArgs returns the arguments of `cmd`, following its name, as logged:
+// redacted, and truncated to the Client's logValueLength option.
+func (c *Client) loggedArgs(cmd redis.Cmder) []string {
+	cmdArgs := cmd.Args()
+	limit := c.redisOptions.logValueLength()
+
+	logged := redactedArgs(cmd)
+	for idx, arg := range logged {
+		// The redacted arguments are left as they are replaced.
+		if limit > 0 && len(arg) > limit && arg == argString(cmdArgs[idx+1]) {
+			logged[idx] = fmt.Sprintf("%s...(%d bytes)", arg[:limit], len(arg))
+		}
+	}
+
+	return logged
+}
+
+// passwordArgs lists, by command name, the arguments followed by a
+// password, and the number of arguments the password comes after, such as
+// the username of HELLO's AUTH argument.
+var passwordArgs = map[string]map[string]int{
+	"hello":   {"auth": 2},
+	"migrate": {"auth": 1, "auth2": 2},
+}
+
+// redactedArgs returns the arguments of `cmd`, following its name, with the
+// passwords they hold replaced: those of AUTH, of HELLO's and MIGRATE's AUTH
+// arguments, of ACL SETUSER's password rules, and of the password
+// parameters of CONFIG SET.
+func redactedArgs(cmd redis.Cmder) []string {
+	name := strings.ToLower(cmd.Name())
+	cmdArgs := cmd.Args()
+
+	args := make([]string, 0, len(cmdArgs))
+	for _, arg := range cmdArgs[1:] {
+		args = append(args, argString(arg))
+	}
+
+	switch name {
+	case "auth":
+		// AUTH takes the password, preceded by the username, if any.
+		if len(args) > 0 {
+			args[len(args)-1] = redacted
+		}
+	case "hello", "migrate":
+		for idx := 0; idx < len(args); idx++ {
+			offset, ok := passwordArgs[name][strings.ToLower(args[idx])]
+			if ok && idx+offset < len(args) {
+				idx += offset
+				args[idx] = redacted
+			}
+		}
+	case "acl":
+		if len(args) < 2 || !strings.EqualFold(args[0], "setuser") {
+			break
+		}
+		for idx := 2; idx < len(args); idx++ {
+			// The rules adding, or removing, passwords, or their hashes.
+			if rule := args[idx]; rule != "" && strings.ContainsRune("><#!", rune(rule[0])) {
+				args[idx] = rule[:1] + redacted
+			}
+		}
+	case "config":
+		if len(args) < 1 || !strings.EqualFold(args[0], "set") {
+			break
+		}
+		for idx := 1; idx+1 < len(args); idx += 2 {
+			switch strings.ToLower(args[idx]) {
+			case "requirepass", "masterauth":
+				args[idx+1] = redacted
+			}
+		}
+	}
+
+	return args
+}
@@ -0,0 +1,134 @@
+package redis
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	logtest "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientLogCommands(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	logger, hook := logtest.NewNullLogger()
+	logger.SetLevel(logrus.DebugLevel)
+	ts.state.Logger = logger
+
+	rs := RunT(t)
+	rs.RegisterCommandHandler("SET", func(c *Connection, _ []string) {
+		c.WriteOK()
+	})
+	rs.RegisterCommandHandler("GET", func(c *Connection, _ []string) {
+		c.WriteNull()
+	})
+	rs.RegisterCommandHandler("AUTH", func(c *Connection, _ []string) {
+		c.WriteError(errors.New("WRONGPASS invalid username-password pair"))
+	})
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client({ socket: { host: "%s", port: %d }, logCommands: true, logValueLength: 8, keyPrefix: "test:" });
+			const quiet = new Client({ socket: { host: "%s", port: %d } });
+
+			if (redis.options().logCommands !== true || redis.options().logValueLength !== 8) {
+				throw 'expected the logCommands options to be reported';
+			}
+
+			redis.set("foo", "%s", 0)
+				.then(() => redis.get("missing"))
+				.then(
+					res => { throw 'expected get to fail' },
+					err => { if (err.error() !== 'redis: nil') { throw 'unexpected error: ' + err.error() } }
+				)
+				.then(() => redis.sendCommand("AUTH", "user", "secret"))
+				.then(
+					res => { throw 'expected auth to fail' },
+					err => {}
+				)
+				.then(() => quiet.set("foo", "bar", 0))
+		`, rs.Addr().IP.String(), rs.Addr().Port, rs.Addr().IP.String(), rs.Addr().Port, strings.Repeat("x", 20)))
+
+		return err
+	})
+	require.NoError(t, gotScriptErr)
+
+	entries := hook.AllEntries()
+	require.Len(t, entries, 3)
+	for _, entry := range entries {
+		assert.Equal(t, logrus.DebugLevel, entry.Level)
+		assert.Equal(t, "Sent a redis command", entry.Message)
+		assert.Contains(t, entry.Data, "duration")
+	}
+
+	assert.Equal(t, "set", entries[0].Data["command"])
+	assert.Equal(t, []string{"test:foo"}, entries[0].Data["keys"])
+	assert.Equal(t, []string{"test:foo", "xxxxxxxx...(20 bytes)"}, entries[0].Data["args"])
+	assert.Equal(t, "ok", entries[0].Data["outcome"])
+
+	assert.Equal(t, "get", entries[1].Data["command"])
+	assert.Equal(t, "nil", entries[1].Data["outcome"])
+	assert.NotContains(t, entries[1].Data, "error")
+
+	assert.Equal(t, "auth", entries[2].Data["command"])
+	assert.NotContains(t, entries[2].Data, "keys")
+	assert.Equal(t, []string{"user", redacted}, entries[2].Data["args"])
+	assert.Equal(t, "error", entries[2].Data["outcome"])
+	assert.Equal(t, "WRONGPASS invalid username-password pair", entries[2].Data["error"])
+}
+
+func TestRedactedArgs(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		args []interface{}
+		want []string
+	}{
+		{
+			name: "auth",
+			args: []interface{}{"auth", "secret"},
+			want: []string{redacted},
+		},
+		{
+			name: "hello",
+			args: []interface{}{"hello", "3", "AUTH", "user", "secret", "setname", "name"},
+			want: []string{"3", "AUTH", "user", redacted, "setname", "name"},
+		},
+		{
+			name: "migrate",
+			args: []interface{}{"migrate", "host", "6379", "", "0", "5000", "auth2", "user", "secret", "keys", "a"},
+			want: []string{"host", "6379", "", "0", "5000", "auth2", "user", redacted, "keys", "a"},
+		},
+		{
+			name: "acl setuser",
+			args: []interface{}{"acl", "SETUSER", "alice", "on", ">secret", "~cache:*", "<old", "+get"},
+			want: []string{"SETUSER", "alice", "on", ">" + redacted, "~cache:*", "<" + redacted, "+get"},
+		},
+		{
+			name: "config set",
+			args: []interface{}{"config", "set", "maxmemory", "1mb", "requirepass", "secret"},
+			want: []string{"set", "maxmemory", "1mb", "requirepass", redacted},
+		},
+		{
+			name: "other",
+			args: []interface{}{"set", "auth", "secret"},
+			want: []string{"auth", "secret"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, redactedArgs(redis.NewCmd(nil, tt.args...)))
+		})
+	}
+}
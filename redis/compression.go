@@ -0,0 +1,289 @@
+package redis
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/klauspost/compress/snappy"
+	"github.com/redis/go-redis/v9"
+)
+
+// defaultCompressionThreshold is the size, in bytes, from which values are
+// compressed when the compression option is set, unless the
+// compressionThreshold option says otherwise.
+const defaultCompressionThreshold = 1024
+
+// Compressed values are told apart from the other ones by the magic bytes
+// their format starts with: the gzip header, and the stream identifier of
+// the Snappy framing format.
+const (
+	gzipMagic   = "\x1f\x8b\x08"
+	snappyMagic = "\xff\x06\x00\x00sNaPpY"
+)
+
+// compressionHook is the go-redis hook implementing the compression option:
+// it compresses the values written by the string and hash commands sent on
+// behalf of a Client with the option set, if they are at least
+// compressionThreshold bytes long, and decompresses the compressed values
+// read by those commands, whatever the format they were compressed with.
+//
+// Each compressed value is measured by the redis_uncompressed_bytes, and
+// redis_compressed_bytes, metrics.
+//
+// As values are routed by their key, the hook is only installed on the
+// go-redis client, and not on the nodes of cluster clients.
+type compressionHook struct{}
+
+var _ redis.Hook = compressionHook{}
+
+// DialHook implements the redis.Hook interface.
+func (compressionHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+// ProcessHook implements the redis.Hook interface.
+func (h compressionHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		c, ok := compressingClient(ctx)
+		if !ok {
+			return next(ctx, cmd)
+		}
+
+		if err := c.compressValues(cmd); err != nil {
+			cmd.SetErr(err)
+			return err
+		}
+
+		err := next(ctx, cmd)
+		if err == nil {
+			if err = decompressReply(cmd); err != nil {
+				cmd.SetErr(err)
+			}
+		}
+
+		return err
+	}
+}
+
+// ProcessPipelineHook implements the redis.Hook interface.
+func (h compressionHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		c, ok := compressingClient(ctx)
+		if !ok {
+			return next(ctx, cmds)
+		}
+
+		for _, cmd := range cmds {
+			if err := c.compressValues(cmd); err != nil {
+				for _, cmd := range cmds {
+					cmd.SetErr(err)
+				}
+				return err
+			}
+		}
+
+		err := next(ctx, cmds)
+		for _, cmd := range cmds {
+			if cmd.Err() != nil {
+				continue
+			}
+			if derr := decompressReply(cmd); derr != nil {
+				cmd.SetErr(derr)
+			}
+		}
+
+		return err
+	}
+}
+
+// compressingClient returns the Client carried by the context, if it has
+// the compression option set.
+func compressingClient(ctx context.Context) (*Client, bool) {
+	c, ok := clientFromContext(ctx)
+	if !ok || c.redisOptions == nil || c.redisOptions.Compression == "" {
+		return nil, false
+	}
+
+	return c, true
+}
+
+// compressedValues locates the values written by the string and hash
+// commands, as keySpecs does for keys: from the argument at index `first`,
+// to the last one, every `step` arguments.
+var compressedValues = map[string]keySpec{
+	"set": {2, 2, 1}, "setnx": {2, 2, 1}, "getset": {2, 2, 1}, "setex": {3, 3, 1}, "psetex": {3, 3, 1},
+	"mset": {2, -1, 2}, "msetnx": {2, -1, 2}, "hset": {3, -1, 2}, "hmset": {3, -1, 2}, "hsetnx": {3, 3, 1},
+}
+
+// decompressedReplies lists the commands whose replies hold the values
+// written by the commands of compressedValues.
+var decompressedReplies = map[string]struct{}{
+	"get": {}, "getdel": {}, "getex": {}, "getset": {}, "set": {}, "mget": {},
+	"hget": {}, "hmget": {}, "hgetall": {}, "hvals": {},
+}
+
+// compressValues compresses the values written by `cmd`, in place, with
+// the Client's compression option, if they reach its compressionThreshold.
+func (c *Client) compressValues(cmd redis.Cmder) error {
+	name := strings.ToLower(cmd.Name())
+	spec, ok := compressedValues[name]
+	if !ok {
+		return nil
+	}
+
+	args := cmd.Args()
+	last := spec.last
+	if last < 0 {
+		last += len(args)
+	}
+
+	threshold := c.redisOptions.compressionThreshold()
+	for idx := spec.first; idx <= last && idx < len(args); idx += spec.step {
+		var value []byte
+		switch v := args[idx].(type) {
+		case string:
+			value = []byte(v)
+		case []byte:
+			value = v
+		default:
+			continue
+		}
+
+		if len(value) < threshold {
+			continue
+		}
+
+		compressed, err := compress(c.redisOptions.Compression, value)
+		if err != nil {
+			return fmt.Errorf("unable to compress the value of the %s command; reason: %w", name, err)
+		}
+		args[idx] = string(compressed)
+
+		tags := map[string]string{"command": name}
+		c.pushTaggedMetric(c.metrics.UncompressedBytes, float64(len(value)), tags)
+		c.pushTaggedMetric(c.metrics.CompressedBytes, float64(len(compressed)), tags)
+	}
+
+	return nil
+}
+
+// compress compresses `value` with the `algorithm` of the compression
+// option: "gzip", or "snappy", in the Snappy framing format.
+func compress(algorithm string, value []byte) ([]byte, error) {
+	var (
+		buf bytes.Buffer
+		w   io.WriteCloser
+	)
+	switch algorithm {
+	case "gzip":
+		w = gzip.NewWriter(&buf)
+	case "snappy":
+		w = snappy.NewBufferedWriter(&buf)
+	default:
+		return nil, fmt.Errorf("unsupported compression: %q", algorithm)
+	}
+
+	if _, err := w.Write(value); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// decompress returns the decompressed `value`, and true, if it starts with
+// the magic bytes of a compression format, or false otherwise.
+func decompress(value string) (string, bool, error) {
+	var (
+		r   io.Reader
+		err error
+	)
+	switch {
+	case strings.HasPrefix(value, gzipMagic):
+		if r, err = gzip.NewReader(strings.NewReader(value)); err != nil {
+			return "", true, err
+		}
+	case strings.HasPrefix(value, snappyMagic):
+		r = snappy.NewReader(strings.NewReader(value))
+	default:
+		return value, false, nil
+	}
+
+	decompressed, err := io.ReadAll(r)
+	if err != nil {
+		return "", true, err
+	}
+
+	return string(decompressed), true, nil
+}
+
+// decompressReply decompresses the compressed values of the reply of
+// `cmd`, in place.
+func decompressReply(cmd redis.Cmder) error {
+	name := strings.ToLower(cmd.Name())
+	if _, ok := decompressedReplies[name]; !ok {
+		return nil
+	}
+
+	var failed error
+	value := func(v string) string {
+		decompressed, ok, err := decompress(v)
+		if err != nil {
+			failed = fmt.Errorf("unable to decompress the value read by the %s command; reason: %w", name, err)
+			return v
+		}
+		if !ok {
+			return v
+		}
+		return decompressed
+	}
+
+	switch cmd := cmd.(type) {
+	case *redis.StringCmd:
+		cmd.SetVal(value(cmd.Val()))
+	case *redis.StatusCmd:
+		cmd.SetVal(value(cmd.Val()))
+	case *redis.Cmd:
+		cmd.SetVal(decompressValue(cmd.Val(), value))
+	case *redis.SliceCmd:
+		values := cmd.Val()
+		for idx, v := range values {
+			values[idx] = decompressValue(v, value)
+		}
+	case *redis.StringSliceCmd:
+		values := cmd.Val()
+		for idx, v := range values {
+			values[idx] = value(v)
+		}
+	case *redis.MapStringStringCmd:
+		for field, v := range cmd.Val() {
+			cmd.Val()[field] = value(v)
+		}
+	}
+
+	return failed
+}
+
+// decompressValue applies `value` to the strings of a generic reply, such
+// as those of sendCommand: a string, or an array of strings.
+func decompressValue(reply interface{}, value func(string) string) interface{} {
+	switch v := reply.(type) {
+	case string:
+		return value(v)
+	case []interface{}:
+		for idx, item := range v {
+			if s, ok := item.(string); ok {
+				v[idx] = value(s)
+			}
+		}
+		return v
+	default:
+		return reply
+	}
+}
@@ -0,0 +1,124 @@
+package redis
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompress(t *testing.T) {
+	t.Parallel()
+
+	value := []byte(strings.Repeat("compressible ", 100))
+
+	for _, algorithm := range []string{"gzip", "snappy"} {
+		compressed, err := compress(algorithm, value)
+		require.NoError(t, err)
+		assert.Less(t, len(compressed), len(value))
+
+		decompressed, ok, err := decompress(string(compressed))
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, string(value), decompressed)
+	}
+
+	decompressed, ok, err := decompress("plain")
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, "plain", decompressed)
+
+	_, ok, err = decompress(gzipMagic + "corrupted")
+	assert.True(t, ok)
+	assert.Error(t, err)
+}
+
+func TestClientCompression(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunT(t)
+
+	var (
+		mu     sync.Mutex
+		values = make(map[string]string)
+		hashes = make(map[string][]interface{})
+	)
+	rs.RegisterCommandHandler("SET", func(c *Connection, args []string) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		values[args[0]] = args[1]
+		c.WriteOK()
+	})
+	rs.RegisterCommandHandler("GET", func(c *Connection, args []string) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		c.WriteBulkString(values[args[0]])
+	})
+	rs.RegisterCommandHandler("HSET", func(c *Connection, args []string) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		hashes[args[0]] = append(hashes[args[0]], args[1], args[2])
+		c.WriteInteger(1)
+	})
+	rs.RegisterCommandHandler("HGETALL", func(c *Connection, args []string) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		c.WriteValue(hashes[args[0]])
+	})
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const gzipped = new Client({ socket: { host: "%[1]s", port: %[2]d }, compression: "gzip", compressionThreshold: 64 });
+			const snappy = new Client({ socket: { host: "%[1]s", port: %[2]d }, compression: "snappy", compressionThreshold: 64 });
+			const large = "compressible ".repeat(100);
+
+			if (gzipped.options().compression !== "gzip" || gzipped.options().compressionThreshold !== 64) {
+				throw 'expected the compression options to be reported';
+			}
+
+			gzipped.set("large", large, 0)
+				.then(() => gzipped.set("small", "tiny", 0))
+				.then(() => gzipped.get("large"))
+				.then(res => { if (res !== large) { throw 'unexpected gzipped get result: ' + res } })
+				.then(() => gzipped.get("small"))
+				.then(res => { if (res !== "tiny") { throw 'unexpected get result: ' + res } })
+				.then(() => snappy.hset("hash", "field", large))
+				.then(() => snappy.hgetall("hash"))
+				.then(res => { if (res.field !== large) { throw 'unexpected snappy hgetall result: ' + JSON.stringify(res) } })
+				.then(() => snappy.get("large"))
+				.then(res => { if (res !== large) { throw 'expected gzipped values to be read by snappy clients: ' + res } })
+		`, rs.Addr().IP, rs.Addr().Port))
+
+		return err
+	})
+
+	assert.NoError(t, gotScriptErr)
+
+	mu.Lock()
+	assert.True(t, strings.HasPrefix(values["large"], gzipMagic))
+	assert.Equal(t, "tiny", values["small"])
+	assert.True(t, strings.HasPrefix(hashes["hash"][1].(string), snappyMagic))
+	mu.Unlock()
+
+	var uncompressed, compressed float64
+	for _, sample := range drainSamples(ts.samples) {
+		switch sample.Metric.Name {
+		case "redis_uncompressed_bytes":
+			uncompressed += sample.Value
+		case "redis_compressed_bytes":
+			compressed += sample.Value
+		}
+	}
+
+	assert.Equal(t, float64(2*1300), uncompressed)
+	assert.Greater(t, compressed, float64(0))
+	assert.Less(t, compressed, uncompressed)
+}
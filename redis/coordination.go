@@ -0,0 +1,262 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/grafana/sobek"
+	"github.com/redis/go-redis/v9"
+)
+
+// Barrier waits until `participants` callers, possibly running in distinct VUs or
+// k6 instances, have reached the barrier identified by `channel`, so they can all
+// proceed together.
+//
+// Each participant subscribes to `channel`, increments the arrivals counter
+// stored at the key of the same name, and publishes the resulting count. The
+// barrier is met once the count reaches `participants`. As the counter keeps
+// growing, the same barrier can be reused: each group of `participants`
+// successive arrivals is released together.
+//
+// If the barrier is not met within `timeoutMs` milliseconds, the promise is
+// rejected with an error. The subscription is closed either way.
+func (c *Client) Barrier(channel string, participants int64, timeoutMs int64) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	if participants < 1 {
+		reject(fmt.Errorf("invalid participants count %d; expected a positive number", participants))
+		return promise
+	}
+
+	if timeoutMs <= 0 {
+		reject(fmt.Errorf("invalid timeout %d; expected a positive number of milliseconds", timeoutMs))
+		return promise
+	}
+
+	go func() {
+		if err := c.awaitBarrier(channel, participants, time.Duration(timeoutMs)*time.Millisecond); err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(nil)
+	}()
+
+	return promise
+}
+
+// awaitBarrier registers the arrival of the caller at the barrier identified by
+// `channel`, and blocks until the group it belongs to is complete.
+func (c *Client) awaitBarrier(channel string, participants int64, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(c.context(), timeout)
+	defer cancel()
+
+	pubsub := c.redisClient.Subscribe(ctx, channel)
+	defer pubsub.Close() //nolint:errcheck
+
+	// Wait for the subscription to be confirmed before registering our
+	// arrival, so that we can't miss the arrival of the last participant.
+	if _, err := pubsub.Receive(ctx); err != nil {
+		return barrierError(channel, timeout, err)
+	}
+
+	arrived, err := c.redisClient.Incr(ctx, channel).Result()
+	if err != nil {
+		return barrierError(channel, timeout, err)
+	}
+
+	// Make sure an abandoned barrier doesn't linger around forever.
+	if err = c.redisClient.PExpire(ctx, channel, timeout).Err(); err != nil {
+		return barrierError(channel, timeout, err)
+	}
+
+	if err = c.redisClient.Publish(ctx, channel, arrived).Err(); err != nil {
+		return barrierError(channel, timeout, err)
+	}
+
+	// The group we belong to is released once the arrivals counter reaches
+	// the next multiple of the participants count.
+	release := ((arrived-1)/participants + 1) * participants
+	if arrived >= release {
+		return nil
+	}
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case msg, ok := <-messages:
+			if !ok {
+				return fmt.Errorf("subscription to barrier channel %q closed unexpectedly", channel)
+			}
+
+			count, err := strconv.ParseInt(msg.Payload, 10, 64)
+			if err == nil && count >= release {
+				return nil
+			}
+		case <-ctx.Done():
+			return barrierError(channel, timeout, ctx.Err())
+		}
+	}
+}
+
+// barrierError returns a descriptive error when the barrier could not be met
+// because of the provided error.
+func barrierError(channel string, timeout time.Duration, err error) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("barrier %q was not met within %s", channel, timeout)
+	}
+
+	return err
+}
+
+// signalOptions holds the options of the Client's signal method.
+type signalOptions struct {
+	// Value is the value the signal carries, "1" by default.
+	Value *string `json:"value,omitempty"`
+
+	// TTLMs is the time, in milliseconds, the signal is kept for, so that
+	// it doesn't linger around beyond the test. It is kept forever when
+	// unset.
+	TTLMs int64 `json:"ttlMs,omitempty"`
+}
+
+// Signal raises the signal identified by `name`, releasing the callers of
+// waitFor, possibly running in distinct VUs or k6 instances, waiting for
+// it, such as to start a phase of the test on every runner at once.
+//
+// The signal's value is stored at the key of the same name, so that the
+// callers of waitFor arriving late see it, and published to the channel
+// of the same name, for those waiting already. A signal stays raised until
+// its key expires, or is deleted.
+//
+// The promise resolves with the number of callers of waitFor, as counted by
+// Redis, the signal was delivered to.
+func (c *Client) Signal(name string, options map[string]interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	var opts signalOptions
+	if err := decodeOptions(options, &opts); err != nil {
+		reject(fmt.Errorf("invalid signal options; reason: %w", err))
+		return promise
+	}
+
+	if opts.TTLMs < 0 {
+		reject(fmt.Errorf("invalid signal options; invalid ttlMs: %d; expected a positive number", opts.TTLMs))
+		return promise
+	}
+
+	value := "1"
+	if opts.Value != nil {
+		value = *opts.Value
+	}
+
+	go func() {
+		ctx := c.context()
+
+		if err := c.redisClient.Set(ctx, name, value, time.Duration(opts.TTLMs)*time.Millisecond).Err(); err != nil {
+			reject(err)
+			return
+		}
+
+		received, err := c.redisClient.Publish(ctx, name, value).Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(received)
+	}()
+
+	return promise
+}
+
+// WaitFor waits for the signal identified by `name` to be raised with
+// signal, by any caller, possibly running in a distinct VU or k6 instance.
+// If the signal was raised already, it returns right away.
+//
+// The promise resolves with the value of the signal. If it isn't raised
+// within `timeoutMs` milliseconds, the promise is rejected with an error.
+func (c *Client) WaitFor(name string, timeoutMs int64) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	if timeoutMs <= 0 {
+		reject(fmt.Errorf("invalid timeout %d; expected a positive number of milliseconds", timeoutMs))
+		return promise
+	}
+
+	go func() {
+		value, err := c.awaitSignal(name, time.Duration(timeoutMs)*time.Millisecond)
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(value)
+	}()
+
+	return promise
+}
+
+// awaitSignal blocks until the signal identified by `name` is raised, and
+// returns its value.
+func (c *Client) awaitSignal(name string, timeout time.Duration) (string, error) {
+	ctx, cancel := context.WithTimeout(c.context(), timeout)
+	defer cancel()
+
+	pubsub := c.redisClient.Subscribe(ctx, name)
+	defer pubsub.Close() //nolint:errcheck
+
+	// Wait for the subscription to be confirmed before checking whether
+	// the signal was raised already, so that we can't miss it being raised
+	// in between.
+	if _, err := pubsub.Receive(ctx); err != nil {
+		return "", signalError(name, timeout, err)
+	}
+
+	value, err := c.redisClient.Get(ctx, name).Result()
+	if err == nil {
+		return value, nil
+	}
+	if !errors.Is(err, redis.Nil) {
+		return "", signalError(name, timeout, err)
+	}
+
+	select {
+	case msg, ok := <-pubsub.Channel():
+		if !ok {
+			return "", fmt.Errorf("subscription to signal channel %q closed unexpectedly", name)
+		}
+
+		return msg.Payload, nil
+	case <-ctx.Done():
+		return "", signalError(name, timeout, ctx.Err())
+	}
+}
+
+// signalError returns a descriptive error when the signal could not be
+// waited for because of the provided error.
+func signalError(name string, timeout time.Duration, err error) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("signal %q was not raised within %s", name, timeout)
+	}
+
+	return err
+}
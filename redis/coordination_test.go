@@ -0,0 +1,311 @@
+package redis
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// registerPubSubHandlers registers SUBSCRIBE, UNSUBSCRIBE, PSUBSCRIBE,
+// PUNSUBSCRIBE, and PUBLISH command handlers on the provided stub server,
+// delivering published messages to the connections subscribed to the
+// channel, or to a pattern matching it.
+func registerPubSubHandlers(rs *StubServer) {
+	var (
+		mu           sync.Mutex
+		subscribers  = make(map[string][]*Connection)
+		psubscribers = make(map[string][]*Connection)
+	)
+
+	subscribe := func(kind string, targets map[string][]*Connection) func(*Connection, []string) {
+		return func(c *Connection, args []string) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			for idx, target := range args {
+				targets[target] = append(targets[target], c)
+				c.WriteValue([]interface{}{kind, target, idx + 1})
+			}
+		}
+	}
+
+	unsubscribe := func(kind string, targets map[string][]*Connection) func(*Connection, []string) {
+		return func(c *Connection, args []string) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			for _, target := range args {
+				conns := targets[target]
+				for idx, conn := range conns {
+					if conn == c {
+						targets[target] = append(conns[:idx], conns[idx+1:]...)
+						break
+					}
+				}
+				c.WriteValue([]interface{}{kind, target, 0})
+			}
+		}
+	}
+
+	rs.RegisterCommandHandler("SUBSCRIBE", subscribe("subscribe", subscribers))
+	rs.RegisterCommandHandler("UNSUBSCRIBE", unsubscribe("unsubscribe", subscribers))
+	rs.RegisterCommandHandler("PSUBSCRIBE", subscribe("psubscribe", psubscribers))
+	rs.RegisterCommandHandler("PUNSUBSCRIBE", unsubscribe("punsubscribe", psubscribers))
+
+	rs.RegisterCommandHandler("PUBLISH", func(c *Connection, args []string) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		channel, payload := args[0], args[1]
+		received := 0
+		for _, conn := range subscribers[channel] {
+			conn.WriteValue([]interface{}{"message", channel, payload})
+			conn.Flush()
+			received++
+		}
+
+		for pattern, conns := range psubscribers {
+			if matched, _ := path.Match(pattern, channel); !matched {
+				continue
+			}
+
+			for _, conn := range conns {
+				conn.WriteValue([]interface{}{"pmessage", pattern, channel, payload})
+				conn.Flush()
+				received++
+			}
+		}
+
+		c.WriteInteger(received)
+	})
+}
+
+// registerCounterHandlers registers INCR and PEXPIRE command handlers on the
+// provided stub server, backed by an in-memory counters map.
+func registerCounterHandlers(rs *StubServer) {
+	var (
+		mu       sync.Mutex
+		counters = make(map[string]int)
+	)
+
+	rs.RegisterCommandHandler("INCR", func(c *Connection, args []string) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		counters[args[0]]++
+		c.WriteInteger(counters[args[0]])
+	})
+
+	rs.RegisterCommandHandler("PEXPIRE", func(c *Connection, args []string) {
+		if _, err := strconv.Atoi(args[1]); err != nil {
+			c.WriteError(err)
+			return
+		}
+
+		c.WriteInteger(1)
+	})
+}
+
+func TestClientBarrier(t *testing.T) {
+	t.Parallel()
+
+	t.Run("all participants are released together", func(t *testing.T) {
+		t.Parallel()
+
+		ts := newTestSetup(t)
+		rs := RunT(t)
+		registerPubSubHandlers(rs)
+		registerCounterHandlers(rs)
+
+		gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+			_, err := ts.rt.RunString(fmt.Sprintf(`
+				const redis = new Client('redis://%s');
+
+				Promise.all([
+					redis.barrier("start", 3, 5000),
+					redis.barrier("start", 3, 5000),
+					redis.barrier("start", 3, 5000),
+				])
+			`, rs.Addr()))
+
+			return err
+		})
+
+		assert.NoError(t, gotScriptErr)
+	})
+
+	t.Run("barrier is rejected when not met in time", func(t *testing.T) {
+		t.Parallel()
+
+		ts := newTestSetup(t)
+		rs := RunT(t)
+		registerPubSubHandlers(rs)
+		registerCounterHandlers(rs)
+
+		gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+			_, err := ts.rt.RunString(fmt.Sprintf(`
+				const redis = new Client('redis://%s');
+
+				redis.barrier("start", 2, 100)
+					.then(
+						res => { throw 'expected barrier to time out' },
+						err => {
+							if (err.error() !== 'barrier "start" was not met within 100ms') {
+								throw 'unexpected error: ' + err
+							}
+						}
+					)
+			`, rs.Addr()))
+
+			return err
+		})
+
+		assert.NoError(t, gotScriptErr)
+	})
+
+	t.Run("invalid participants count is rejected", func(t *testing.T) {
+		t.Parallel()
+
+		ts := newTestSetup(t)
+		rs := RunT(t)
+
+		gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+			_, err := ts.rt.RunString(fmt.Sprintf(`
+				const redis = new Client('redis://%s');
+
+				redis.barrier("start", 0, 100)
+					.then(
+						res => { throw 'expected barrier to be rejected' },
+						err => { if (!err.error().startsWith('invalid participants count')) { throw 'unexpected error: ' + err } }
+					)
+			`, rs.Addr()))
+
+			return err
+		})
+
+		assert.NoError(t, gotScriptErr)
+		assert.Equal(t, 0, rs.HandledCommandsCount())
+	})
+}
+
+// registerSignalHandlers registers SET and GET command handlers on the
+// provided stub server, backed by an in-memory values map, and returns the
+// expiration option SET was sent with, such as "ex 60", by key. As the callers of waitFor read the
+// signal's key once subscribed, the stub-specific MISSES command replies
+// with the number of reads of missing keys, that is of waiting callers.
+func registerSignalHandlers(rs *StubServer) (ttls func() map[string]string) {
+	var (
+		mu          sync.Mutex
+		values      = make(map[string]string)
+		expirations = make(map[string]string)
+		misses      int
+	)
+
+	rs.RegisterCommandHandler("SET", func(c *Connection, args []string) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		values[args[0]] = args[1]
+		if len(args) == 4 {
+			expirations[args[0]] = args[2] + " " + args[3]
+		}
+		c.WriteOK()
+	})
+
+	rs.RegisterCommandHandler("GET", func(c *Connection, args []string) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		value, ok := values[args[0]]
+		if !ok {
+			misses++
+			c.WriteNull()
+			return
+		}
+		c.WriteBulkString(value)
+	})
+
+	rs.RegisterCommandHandler("MISSES", func(c *Connection, _ []string) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		c.WriteInteger(misses)
+	})
+
+	return func() map[string]string {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return expirations
+	}
+}
+
+func TestClientSignal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("waiting callers are released by the signal", func(t *testing.T) {
+		t.Parallel()
+
+		ts := newTestSetup(t)
+		rs := RunT(t)
+		registerPubSubHandlers(rs)
+		ttls := registerSignalHandlers(rs)
+
+		gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+			_, err := ts.rt.RunString(fmt.Sprintf(`
+				const redis = new Client('redis://%s');
+
+				const waiting = Promise.all([redis.waitFor("spike", 5000), redis.waitFor("spike", 5000)]);
+
+				// Raise the signal once both callers are waiting for it.
+				const waiters = () => redis.sendCommand("MISSES").then(n => n < 2 ? waiters() : n);
+
+				waiters()
+					.then(() => redis.signal("spike", { value: "go", ttlMs: 60000 }))
+					.then(received => { if (received !== 2) { throw 'unexpected signal result: ' + received } })
+					.then(() => waiting)
+					.then(values => { if (values.join() !== "go,go") { throw 'unexpected waitFor results: ' + values } })
+					.then(() => redis.waitFor("spike", 5000))
+					.then(value => { if (value !== "go") { throw 'unexpected waitFor result for a raised signal: ' + value } })
+			`, rs.Addr()))
+
+			return err
+		})
+
+		assert.NoError(t, gotScriptErr)
+		assert.Equal(t, map[string]string{"spike": "ex 60"}, ttls())
+	})
+
+	t.Run("waitFor is rejected when the signal is not raised in time", func(t *testing.T) {
+		t.Parallel()
+
+		ts := newTestSetup(t)
+		rs := RunT(t)
+		registerPubSubHandlers(rs)
+		registerSignalHandlers(rs)
+
+		gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+			_, err := ts.rt.RunString(fmt.Sprintf(`
+				const redis = new Client('redis://%s');
+
+				redis.waitFor("spike", 100)
+					.then(
+						res => { throw 'expected waitFor to time out' },
+						err => {
+							if (err.error() !== 'signal "spike" was not raised within 100ms') {
+								throw 'unexpected error: ' + err
+							}
+						}
+					)
+			`, rs.Addr()))
+
+			return err
+		})
+
+		assert.NoError(t, gotScriptErr)
+	})
+}
@@ -0,0 +1,148 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/grafana/sobek"
+	"github.com/redis/go-redis/v9"
+	"go.k6.io/k6/js/common"
+)
+
+// WithDatabase returns a client sending its commands to the logical database
+// `db` of the same server as c, with the same options otherwise.
+//
+// As SELECT changes the database of a single connection, rather than that
+// of the whole connection pool, databases are rather selected by the
+// client's options: the returned client uses the connection pool of the
+// clients created with the `database` option set to `db`.
+func (c *Client) WithDatabase(db int) *Client {
+	if db < 0 {
+		common.Throw(c.vu.Runtime(), fmt.Errorf("invalid database: %d; expected a positive number", db))
+	}
+
+	if c.redisOptions.isCluster() {
+		common.Throw(c.vu.Runtime(), errors.New("cluster clients only support database 0"))
+	}
+
+	uopts := *c.redisOptions.UniversalOptions
+	uopts.DB = db
+
+	opts := *c.redisOptions
+	opts.UniversalOptions = &uopts
+
+	return &Client{
+		vu:               c.vu,
+		redisOptions:     &opts,
+		getRedisClient:   c.getRedisClient,
+		closeRedisClient: c.closeRedisClient,
+		metrics:          c.metrics,
+		timeout:          c.timeout,
+		tags:             c.tags,
+		stats:            c.stats,
+		hooks:            c.hooks,
+		trackedKeys:      c.trackedKeys,
+		syncCalls:        c.syncCalls,
+	}
+}
+
+// Swapdb swaps the logical databases `index1` and `index2`, so that the
+// clients connected to either database immediately see the keys of the
+// other one.
+//
+// The promise resolves with "OK".
+func (c *Client) Swapdb(index1, index2 int) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		ctx := c.context()
+		cmd := redis.NewStatusCmd(ctx, "swapdb", index1, index2)
+		_ = c.redisClient.Process(ctx, cmd)
+
+		status, err := cmd.Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(status)
+	}()
+
+	return promise
+}
+
+// flushOptions holds the options of the Client's flushDb and flushAll
+// methods.
+type flushOptions struct {
+	// Async frees the memory of the flushed keys in the background.
+	Async bool `json:"async,omitempty"`
+}
+
+// FlushDb deletes all the keys of the client's logical database, such as to
+// isolate tests from each other. Cluster clients flush every master node.
+//
+// As flushing is dangerous, flushDb throws unless the allowFlush option is
+// set, and with the keyPrefix option, whose namespace it would escape.
+//
+// The promise resolves with "OK".
+func (c *Client) FlushDb(options map[string]interface{}) *sobek.Promise {
+	return c.flush("flushDb", options)
+}
+
+// FlushAll is like FlushDb, except that it deletes the keys of all the
+// logical databases of the server.
+func (c *Client) FlushAll(options map[string]interface{}) *sobek.Promise {
+	return c.flush("flushAll", options)
+}
+
+// flush sends the `command` flushing command, on behalf of FlushDb and
+// FlushAll, named `method` in JS.
+func (c *Client) flush(method string, options map[string]interface{}) *sobek.Promise {
+	if c.redisOptions != nil && !c.redisOptions.AllowFlush {
+		common.Throw(c.vu.Runtime(), fmt.Errorf("%s requires the allowFlush option to be set", method))
+	}
+
+	if c.redisOptions != nil && c.redisOptions.KeyPrefix != "" {
+		common.Throw(c.vu.Runtime(), fmt.Errorf(
+			"%s is not supported with the keyPrefix option, as it would delete the keys of other prefixes; "+
+				"use deleteByPattern instead", method))
+	}
+
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	var opts flushOptions
+	if err := decodeOptions(options, &opts); err != nil {
+		reject(fmt.Errorf("invalid %s options; reason: %w", method, err))
+		return promise
+	}
+
+	flush := map[string]map[bool]func(redis.Cmdable, context.Context) *redis.StatusCmd{
+		"flushDb":  {false: redis.Cmdable.FlushDB, true: redis.Cmdable.FlushDBAsync},
+		"flushAll": {false: redis.Cmdable.FlushAll, true: redis.Cmdable.FlushAllAsync},
+	}[method][opts.Async]
+
+	go func() {
+		err := c.forEachScanClient(c.context(), func(ctx context.Context, client redis.UniversalClient) error {
+			return flush(client, ctx).Err()
+		})
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve("OK")
+	}()
+
+	return promise
+}
@@ -0,0 +1,120 @@
+package redis
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientDatabases(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunT(t)
+	rs.RegisterCommandHandler("SELECT", func(c *Connection, _ []string) {
+		c.WriteOK()
+	})
+	rs.RegisterCommandHandler("GET", func(c *Connection, args []string) {
+		c.WriteBulkString(args[0])
+	})
+	rs.RegisterCommandHandler("SWAPDB", func(c *Connection, _ []string) {
+		c.WriteOK()
+	})
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client('redis://%s');
+			const other = redis.withDatabase(2);
+
+			if (other.options().db !== 2 || other.options().hash === redis.options().hash) {
+				throw 'expected the client to use its own connection pool: ' + JSON.stringify(other.options())
+			}
+
+			redis.get("foo")
+				.then(() => other.get("bar"))
+				.then(res => { if (res !== "bar") { throw 'unexpected value for get result: ' + res } })
+				.then(() => redis.swapdb(0, 2))
+				.then(res => { if (res !== "OK") { throw 'unexpected value for swapdb result: ' + res } })
+		`, rs.Addr()))
+
+		return err
+	})
+
+	assert.NoError(t, gotScriptErr)
+	assert.Equal(t, [][]string{
+		{"HELLO", "2"},
+		{"GET", "foo"},
+		{"HELLO", "2"},
+		{"SELECT", "2"},
+		{"GET", "bar"},
+		{"SWAPDB", "0", "2"},
+	}, rs.GotCommands())
+}
+
+func TestDatabaseOptions(t *testing.T) {
+	t.Parallel()
+
+	opts, err := readOptions("redis://localhost:6379/3")
+	require.NoError(t, err)
+	assert.Equal(t, 3, opts.DB)
+
+	other, err := readOptions(map[string]interface{}{
+		"socket":   map[string]interface{}{"host": "localhost", "port": 6379},
+		"database": 4,
+	})
+	require.NoError(t, err)
+	assert.NotEqual(t, opts.report()["hash"], other.report()["hash"],
+		"clients of distinct databases must not share the same go-redis client")
+
+	_, err = readOptions(map[string]interface{}{
+		"cluster": map[string]interface{}{"nodes": []interface{}{"redis://host1:6379/1"}},
+	})
+	assert.ErrorContains(t, err, "cluster clients only support database 0")
+}
+
+func TestClientFlush(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunT(t)
+	rs.RegisterCommandHandler("FLUSHDB", func(c *Connection, _ []string) {
+		c.WriteOK()
+	})
+	rs.RegisterCommandHandler("FLUSHALL", func(c *Connection, _ []string) {
+		c.WriteOK()
+	})
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const unsafe = new Client({ socket: { host: "%[1]s", port: %[2]d }, allowFlush: true });
+			const safe = new Client({ socket: { host: "%[1]s", port: %[2]d } });
+			const prefixed = new Client({ socket: { host: "%[1]s", port: %[2]d }, allowFlush: true, keyPrefix: "test:" });
+
+			for (const [client, method] of [[safe, "flushDb"], [safe, "flushAll"], [prefixed, "flushDb"]]) {
+				let threw = false;
+				try {
+					client[method]();
+				} catch (e) {
+					threw = true;
+				}
+				if (!threw) { throw 'expected ' + method + ' to throw' }
+			}
+
+			unsafe.flushDb()
+				.then(res => { if (res !== "OK") { throw 'unexpected value for flushDb result: ' + res } })
+				.then(() => unsafe.flushAll({ async: true }))
+				.then(res => { if (res !== "OK") { throw 'unexpected value for flushAll result: ' + res } })
+		`, rs.Addr().IP, rs.Addr().Port))
+
+		return err
+	})
+
+	assert.NoError(t, gotScriptErr)
+	assert.Equal(t, [][]string{
+		{"HELLO", "2"},
+		{"FLUSHDB"},
+		{"FLUSHALL", "async"},
+	}, rs.GotCommands())
+}
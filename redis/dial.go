@@ -0,0 +1,87 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"go.k6.io/k6/lib"
+	"go.k6.io/k6/lib/netext"
+)
+
+// familyDialer dials connections with the address family of the dialNetwork
+// option, "tcp4" or "tcp6".
+//
+// k6's dialer resolves host names to a single address, of the family its
+// DNS options select, so that dialing with a network of the other family
+// fails. The familyDialer resolves host names to an address of its family
+// itself, instead, and has k6's dialer dial that address, which still
+// applies the options of the test, such as blocked IP ranges.
+type familyDialer struct {
+	dialer  lib.DialContexter
+	network string
+
+	// lookupIP resolves host names to the addresses of a network, "ip4" or
+	// "ip6", as net.Resolver.LookupIP does.
+	lookupIP func(ctx context.Context, network, host string) ([]net.IP, error)
+}
+
+var _ lib.DialContexter = &familyDialer{}
+
+// newFamilyDialer returns a familyDialer dialing through `dialer` with
+// `network`, "tcp4" or "tcp6".
+func newFamilyDialer(dialer lib.DialContexter, network string) *familyDialer {
+	return &familyDialer{
+		dialer:   dialer,
+		network:  network,
+		lookupIP: net.DefaultResolver.LookupIP,
+	}
+}
+
+// DialContext dials `addr` with the dialer's network, whatever the provided
+// one.
+func (d *familyDialer) DialContext(ctx context.Context, _, addr string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	// Addresses need no resolution, and the host names k6 blocks, or maps to
+	// addresses with its hosts option, are handled by k6's dialer.
+	if net.ParseIP(host) != nil || d.handledByK6(addr, host) {
+		return d.dialer.DialContext(ctx, d.network, addr)
+	}
+
+	ipNetwork := "ip4"
+	if d.network == "tcp6" {
+		ipNetwork = "ip6"
+	}
+
+	ips, err := d.lookupIP(ctx, ipNetwork, host)
+	if err != nil {
+		return nil, err
+	}
+	if len(ips) == 0 {
+		return nil, fmt.Errorf("lookup %s: no %s address", host, ipNetwork)
+	}
+
+	return d.dialer.DialContext(ctx, d.network, net.JoinHostPort(ips[0].String(), port))
+}
+
+// handledByK6 returns whether the host name `host`, of the address `addr`,
+// is blocked by the blockHostnames option of the test, or mapped to an
+// address by its hosts option.
+func (d *familyDialer) handledByK6(addr, host string) bool {
+	k6Dialer, ok := d.dialer.(*netext.Dialer)
+	if !ok {
+		return false
+	}
+
+	if k6Dialer.BlockedHostnames != nil {
+		if _, blocked := k6Dialer.BlockedHostnames.Contains(host); blocked {
+			return true
+		}
+	}
+
+	return k6Dialer.Hosts != nil && (k6Dialer.Hosts.Match(addr) != nil || k6Dialer.Hosts.Match(host) != nil)
+}
@@ -0,0 +1,125 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.k6.io/k6/lib/netext"
+	"go.k6.io/k6/lib/types"
+)
+
+// ipv6Resolver is a k6 resolver resolving every host name to the IPv6
+// loopback address, as k6 does for dual-stack hosts with its dns option
+// preferring IPv6.
+type ipv6Resolver struct{}
+
+func (ipv6Resolver) LookupIP(string) (net.IP, error) {
+	return net.IPv6loopback, nil
+}
+
+// dualStackLookup resolves every host name to the loopback addresses of
+// the requested network, as net.Resolver.LookupIP does for dual-stack
+// hosts.
+func dualStackLookup(_ context.Context, network, _ string) ([]net.IP, error) {
+	if network == "ip6" {
+		return []net.IP{net.IPv6loopback}, nil
+	}
+
+	return []net.IP{net.IPv4(127, 0, 0, 1)}, nil
+}
+
+// recordingDialer records the networks, and addresses, it is asked to dial.
+type recordingDialer struct {
+	networks []string
+	addrs    []string
+}
+
+func (d *recordingDialer) DialContext(_ context.Context, network, addr string) (net.Conn, error) {
+	d.networks = append(d.networks, network)
+	d.addrs = append(d.addrs, addr)
+
+	return nil, errors.New("not dialed")
+}
+
+func TestFamilyDialer(t *testing.T) {
+	t.Parallel()
+
+	t.Run("dual-stack hosts are dialed with the address of the family", func(t *testing.T) {
+		t.Parallel()
+
+		rs := RunT(t)
+		addr := net.JoinHostPort("dualstack.test", strconv.Itoa(rs.Addr().Port))
+		k6Dialer := netext.NewDialer(net.Dialer{}, ipv6Resolver{})
+
+		// k6's dialer resolves the host to its IPv6 address, which can't be
+		// dialed with tcp4.
+		_, err := k6Dialer.DialContext(context.Background(), "tcp4", addr)
+		require.Error(t, err)
+
+		dialer := newFamilyDialer(k6Dialer, "tcp4")
+		dialer.lookupIP = dualStackLookup
+
+		conn, err := dialer.DialContext(context.Background(), "tcp", addr)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = conn.Close() })
+
+		assert.Equal(t, rs.Addr().String(), conn.RemoteAddr().String())
+	})
+
+	t.Run("host names are resolved to an address of the family", func(t *testing.T) {
+		t.Parallel()
+
+		for network, want := range map[string]string{"tcp4": "127.0.0.1:6379", "tcp6": "[::1]:6379"} {
+			recorder := &recordingDialer{}
+			dialer := newFamilyDialer(recorder, network)
+			dialer.lookupIP = dualStackLookup
+
+			_, err := dialer.DialContext(context.Background(), "tcp", "dualstack.test:6379")
+
+			assert.Error(t, err)
+			assert.Equal(t, []string{network}, recorder.networks)
+			assert.Equal(t, []string{want}, recorder.addrs)
+		}
+	})
+
+	t.Run("addresses are dialed as they are", func(t *testing.T) {
+		t.Parallel()
+
+		recorder := &recordingDialer{}
+		dialer := newFamilyDialer(recorder, "tcp6")
+		dialer.lookupIP = func(context.Context, string, string) ([]net.IP, error) {
+			t.Error("addresses must not be resolved")
+			return nil, nil
+		}
+
+		_, _ = dialer.DialContext(context.Background(), "tcp", "[::1]:6379")
+
+		assert.Equal(t, []string{"[::1]:6379"}, recorder.addrs)
+	})
+
+	t.Run("blocked host names are rejected by k6", func(t *testing.T) {
+		t.Parallel()
+
+		blocked, err := types.NewHostnameTrie([]string{"dualstack.test"})
+		require.NoError(t, err)
+
+		k6Dialer := netext.NewDialer(net.Dialer{}, ipv6Resolver{})
+		k6Dialer.BlockedHostnames = blocked
+
+		dialer := newFamilyDialer(k6Dialer, "tcp4")
+		dialer.lookupIP = func(context.Context, string, string) ([]net.IP, error) {
+			t.Error("blocked host names must not be resolved")
+			return nil, nil
+		}
+
+		_, err = dialer.DialContext(context.Background(), "tcp", "dualstack.test:6379")
+
+		var blockedErr netext.BlockedHostError
+		assert.ErrorAs(t, err, &blockedErr)
+	})
+}
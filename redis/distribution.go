@@ -0,0 +1,178 @@
+package redis
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/sobek"
+	"github.com/redis/go-redis/v9"
+	"go.k6.io/k6/js/common"
+)
+
+const (
+	defaultZipfianSkew     = 1.1
+	defaultHotspotFraction = 0.2
+	defaultHotspotRate     = 0.8
+)
+
+// keyDistributionOptions holds the options of the Client's methods picking
+// keys following a distribution.
+type keyDistributionOptions struct {
+	// Pattern is the name of the keys, where {i} is replaced by the index
+	// of the key, as for seed.
+	Pattern string `json:"pattern"`
+
+	// Count is the number of keys to pick from, with indexes from 0 to
+	// count - 1.
+	Count int64 `json:"count"`
+
+	// Distribution is the distribution of the picked indexes: "uniform",
+	// "zipfian", or "hotspot".
+	Distribution string `json:"distribution,omitempty"`
+
+	// Skew is the exponent of the zipfian distribution. The greater it is,
+	// the more the lowest indexes are picked.
+	Skew float64 `json:"skew,omitempty"`
+
+	// HotspotFraction is the fraction of the keys, with the lowest indexes,
+	// which are hot in the hotspot distribution, and HotspotRate the
+	// fraction of the picks which are hot keys.
+	HotspotFraction float64 `json:"hotspotFraction,omitempty"`
+	HotspotRate     float64 `json:"hotspotRate,omitempty"`
+}
+
+// validate checks the options, and sets the defaults of the omitted ones.
+func (o *keyDistributionOptions) validate() error {
+	if !strings.Contains(o.Pattern, seedIndexPlaceholder) {
+		return fmt.Errorf("pattern %q must contain the %s placeholder", o.Pattern, seedIndexPlaceholder)
+	}
+
+	if o.Count <= 0 {
+		return fmt.Errorf("count must be a positive number; got %d", o.Count)
+	}
+
+	switch o.Distribution {
+	case "":
+		o.Distribution = "uniform"
+	case "uniform":
+	case "zipfian":
+		if o.Skew == 0 {
+			o.Skew = defaultZipfianSkew
+		}
+		if o.Skew <= 1 {
+			return fmt.Errorf("skew must be greater than 1; got %g", o.Skew)
+		}
+	case "hotspot":
+		if o.HotspotFraction == 0 {
+			o.HotspotFraction = defaultHotspotFraction
+		}
+		if o.HotspotRate == 0 {
+			o.HotspotRate = defaultHotspotRate
+		}
+		if o.HotspotFraction <= 0 || o.HotspotFraction > 1 || o.HotspotRate < 0 || o.HotspotRate > 1 {
+			return errors.New("hotspotFraction and hotspotRate must be between 0 and 1")
+		}
+	default:
+		return fmt.Errorf("unsupported distribution %q; expected one of uniform, zipfian, or hotspot", o.Distribution)
+	}
+
+	return nil
+}
+
+// index picks the index of a key, using `r`.
+func (o keyDistributionOptions) index(r *rand.Rand) int64 {
+	switch o.Distribution {
+	case "zipfian":
+		return int64(rand.NewZipf(r, o.Skew, 1, uint64(o.Count-1)).Uint64())
+	case "hotspot":
+		hot := int64(o.HotspotFraction * float64(o.Count))
+		if hot < 1 {
+			hot = 1
+		}
+		if hot == o.Count || r.Float64() < o.HotspotRate {
+			return r.Int63n(hot)
+		}
+		return hot + r.Int63n(o.Count-hot)
+	default:
+		return r.Int63n(o.Count)
+	}
+}
+
+// pickKey returns a key picked following the distribution of `options`.
+func (c *Client) pickKey(options map[string]interface{}) (string, error) {
+	var opts keyDistributionOptions
+	if err := decodeOptions(options, &opts); err != nil {
+		return "", err
+	}
+
+	if err := opts.validate(); err != nil {
+		return "", err
+	}
+
+	// Keys are picked on the event loop, hence the generator needs no
+	// locking.
+	if c.keyRand == nil {
+		c.keyRand = rand.New(rand.NewSource(time.Now().UnixNano())) //nolint:gosec
+	}
+
+	index := opts.index(c.keyRand)
+
+	return strings.ReplaceAll(opts.Pattern, seedIndexPlaceholder, strconv.FormatInt(index, 10)), nil
+}
+
+// PickKey returns the name of a key picked following a distribution, such
+// as to access the keys created by seed the way a cache's clients would.
+//
+// The keys are named after the pattern option, where {i} is replaced by an
+// index between 0 and count - 1. With the default "uniform" distribution,
+// all the indexes are equally likely. With the "zipfian" distribution, the
+// probability of an index decreases as a power, skew, of its rank, so that
+// a few keys get most of the accesses. With the "hotspot" distribution, the
+// hotspotFraction of the keys with the lowest indexes get the hotspotRate
+// of the accesses.
+func (c *Client) PickKey(options map[string]interface{}) string {
+	key, err := c.pickKey(options)
+	if err != nil {
+		common.Throw(c.vu.Runtime(), fmt.Errorf("invalid pickKey options; reason: %w", err))
+	}
+
+	return key
+}
+
+// GetRandom gets the value of a key picked as PickKey does.
+//
+// The promise resolves with an object holding the `key` picked, and its
+// `value`, or null if the key doesn't exist, so that scripts can account
+// for hits and misses.
+func (c *Client) GetRandom(options map[string]interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	key, err := c.pickKey(options)
+	if err != nil {
+		reject(fmt.Errorf("invalid getRandom options; reason: %w", err))
+		return promise
+	}
+
+	go func() {
+		value, err := c.redisClient.Get(c.context(), key).Result()
+		switch {
+		case errors.Is(err, redis.Nil):
+			resolve(map[string]interface{}{"key": key, "value": nil})
+		case err != nil:
+			reject(err)
+		default:
+			resolve(map[string]interface{}{"key": key, "value": value})
+		}
+	}()
+
+	return promise
+}
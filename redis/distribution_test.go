@@ -0,0 +1,106 @@
+package redis
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientGetRandom(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunT(t)
+	rs.RegisterCommandHandler("GET", func(c *Connection, args []string) {
+		if args[0] == "user:0" {
+			c.WriteBulkString("alice")
+			return
+		}
+		c.WriteNull()
+	})
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client('redis://%s');
+
+			if (redis.pickKey({ pattern: "user:{i}", count: 1, distribution: "zipfian" }) !== "user:0") {
+				throw 'unexpected value for pickKey result'
+			}
+
+			redis.getRandom({ pattern: "user:{i}", count: 1 })
+				.then(res => { if (res.key !== "user:0" || res.value !== "alice") { throw 'unexpected value for getRandom result: ' + JSON.stringify(res) } })
+				.then(() => redis.getRandom({ pattern: "missing:{i}", count: 1, distribution: "hotspot" }))
+				.then(res => { if (res.key !== "missing:0" || res.value !== null) { throw 'unexpected value for getRandom result: ' + JSON.stringify(res) } })
+				.then(() => redis.getRandom({ pattern: "user:{i}", count: 10, distribution: "gaussian" }))
+				.then(
+					res => { throw 'expected getRandom to fail' },
+					err => { if (!err.error().includes('unsupported distribution "gaussian"')) { throw 'unexpected error: ' + err.error() } }
+				)
+		`, rs.Addr()))
+
+		return err
+	})
+
+	assert.NoError(t, gotScriptErr)
+	assert.Equal(t, [][]string{
+		{"HELLO", "2"},
+		{"GET", "user:0"},
+		{"GET", "missing:0"},
+	}, rs.GotCommands())
+}
+
+func TestKeyDistributionIndex(t *testing.T) {
+	t.Parallel()
+
+	const picks = 10000
+
+	// tally returns the number of picks of each index.
+	tally := func(t *testing.T, opts keyDistributionOptions) []int {
+		t.Helper()
+		require.NoError(t, opts.validate())
+
+		r := rand.New(rand.NewSource(1)) //nolint:gosec
+		counts := make([]int, opts.Count)
+		for i := 0; i < picks; i++ {
+			counts[opts.index(r)]++
+		}
+
+		return counts
+	}
+
+	t.Run("uniform", func(t *testing.T) {
+		t.Parallel()
+
+		for _, count := range tally(t, keyDistributionOptions{Pattern: "k{i}", Count: 10}) {
+			assert.InDelta(t, picks/10, count, picks/50)
+		}
+	})
+
+	t.Run("zipfian", func(t *testing.T) {
+		t.Parallel()
+
+		counts := tally(t, keyDistributionOptions{Pattern: "k{i}", Count: 100, Distribution: "zipfian"})
+		assert.Greater(t, counts[0], counts[1])
+		assert.Greater(t, counts[1], counts[10])
+		assert.Greater(t, counts[0], picks/10)
+	})
+
+	t.Run("hotspot", func(t *testing.T) {
+		t.Parallel()
+
+		counts := tally(t, keyDistributionOptions{Pattern: "k{i}", Count: 10, Distribution: "hotspot"})
+		assert.InDelta(t, picks*8/10, counts[0]+counts[1], picks/50)
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Error(t, (&keyDistributionOptions{Pattern: "k", Count: 10}).validate())
+		assert.Error(t, (&keyDistributionOptions{Pattern: "k{i}"}).validate())
+		assert.Error(t, (&keyDistributionOptions{Pattern: "k{i}", Count: 10, Distribution: "zipfian", Skew: 0.9}).validate())
+		assert.Error(t, (&keyDistributionOptions{Pattern: "k{i}", Count: 10, Distribution: "hotspot", HotspotRate: 2}).validate())
+	})
+}
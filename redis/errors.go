@@ -0,0 +1,233 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+
+	"github.com/grafana/sobek"
+	"github.com/redis/go-redis/v9"
+	"go.k6.io/k6/js/promises"
+)
+
+// The kinds of failures commands can be rejected with an error of.
+const (
+	// errorKindPoolTimeout indicates that no connection could be obtained
+	// from the connection pool in time: the pool is exhausted.
+	errorKindPoolTimeout = "pool_timeout"
+
+	// errorKindNetworkTimeout indicates that reading from, or writing to,
+	// the connection timed out: the server is slow to respond.
+	errorKindNetworkTimeout = "network_timeout"
+
+	// errorKindDeadline indicates that the command's deadline was exceeded.
+	errorKindDeadline = "deadline"
+
+	// errorKindConnection indicates that the connection to the server
+	// failed: the node is unreachable.
+	errorKindConnection = "connection"
+
+	// errorKindClusterRedirect indicates that the server replied with a
+	// MOVED, or ASK, redirection the client didn't follow, such as after
+	// exhausting the maxRedirects cluster option.
+	errorKindClusterRedirect = "cluster_redirect"
+
+	// errorKindWrongType indicates that the command was run against a key
+	// holding a value of another type.
+	errorKindWrongType = "wrongtype"
+
+	// errorKindNoScript indicates that the script to evaluate isn't cached
+	// by the server.
+	errorKindNoScript = "noscript"
+
+	// errorKindNoPerm indicates that the ACL user the client is
+	// authenticated as isn't allowed to run the command, or to access its
+	// keys.
+	errorKindNoPerm = "noperm"
+
+	// errorKindAuth indicates that the client failed to authenticate, such
+	// as with a wrong password, or didn't while the server requires it.
+	errorKindAuth = "auth"
+
+	// errorKindWriteNotAllowed indicates that the command modifies data,
+	// while the allowWriteCommands option is false. It wasn't sent.
+	errorKindWriteNotAllowed = "write_not_allowed"
+
+	// errorKindCommandNotAllowed indicates that the command is excluded by
+	// the allowedCommands, or blockedCommands, options. It wasn't sent.
+	errorKindCommandNotAllowed = "command_not_allowed"
+
+	// errorKindCommand indicates that the server replied to the command
+	// with any other error.
+	errorKindCommand = "command"
+)
+
+// commandErrorName is the name of the errors commands are rejected with,
+// once classified, exposed to JS as their `name` property.
+const commandErrorName = "RedisError"
+
+// poolTimeoutMessage is the message of the error go-redis fails commands
+// with when its connection pool timed out. As go-redis doesn't export that
+// error, it is identified by its message.
+const poolTimeoutMessage = "redis: connection pool timeout"
+
+// commandError is the error commands are rejected with, when the cause of
+// their failure is identified. It exposes the kind of failure to JS as its
+// `kind` property, and the error code of the server's error replies, such
+// as "WRONGTYPE", as its `code` property, so that scripts can tell failures
+// apart.
+type commandError struct {
+	Name string `js:"name"`
+	Kind string `js:"kind"`
+	Code string `js:"code"`
+
+	err error
+}
+
+// Error implements the error interface.
+func (e *commandError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *commandError) Unwrap() error {
+	return e.err
+}
+
+// deadlineError is the error commands are failed with when their deadline
+// was exceeded while waiting for their reply. As go-redis sets the deadline
+// of the connection to the one of the command's context, it reports those
+// as network timeouts instead, which deadlineError is reported as
+// context.DeadlineExceeded in place of.
+type deadlineError struct {
+	err error
+}
+
+// Error implements the error interface.
+func (e *deadlineError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *deadlineError) Unwrap() error {
+	return e.err
+}
+
+// Is reports the error as context.DeadlineExceeded.
+func (e *deadlineError) Is(target error) bool {
+	return target == context.DeadlineExceeded
+}
+
+// withDeadlineError returns the provided error wrapped in a deadlineError,
+// if it is a network timeout caused by the deadline of the command's
+// context being exceeded. Otherwise, the error is returned as is.
+func withDeadlineError(ctx context.Context, err error) error {
+	var netErr net.Error
+	if err == nil || !errors.Is(ctx.Err(), context.DeadlineExceeded) || errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+	if !errors.As(err, &netErr) || !netErr.Timeout() {
+		return err
+	}
+
+	return &deadlineError{err: err}
+}
+
+// classifyError returns the provided error wrapped in a commandError of
+// the matching kind, if the cause of the failure is identified. Otherwise,
+// the error is returned as is.
+func classifyError(err error) error {
+	var kind, code string
+
+	// Note that context.DeadlineExceeded is a net.Error too, which is why
+	// deadlines are checked for first.
+	var (
+		netErr   net.Error
+		redisErr redis.Error
+	)
+	switch {
+	case err == nil:
+		return nil
+	case errors.As(err, new(*commandError)):
+		return err
+	case errors.As(err, new(*writeNotAllowedError)):
+		kind = errorKindWriteNotAllowed
+	case errors.As(err, new(*commandNotAllowedError)):
+		kind = errorKindCommandNotAllowed
+	case errors.Is(err, context.DeadlineExceeded):
+		kind = errorKindDeadline
+	case err.Error() == poolTimeoutMessage:
+		kind = errorKindPoolTimeout
+	case errors.As(err, &netErr) && netErr.Timeout():
+		kind = errorKindNetworkTimeout
+	case errors.As(err, &netErr):
+		kind = errorKindConnection
+	case errors.Is(err, redis.Nil):
+		return err
+	case errors.As(err, &redisErr):
+		code, _, _ = strings.Cut(redisErr.Error(), " ")
+		kind = replyErrorKind(code)
+	default:
+		return err
+	}
+
+	return &commandError{Name: commandErrorName, Kind: kind, Code: code, err: err}
+}
+
+// replyErrorKind returns the kind of the server's error replies with the
+// provided error code.
+func replyErrorKind(code string) string {
+	switch code {
+	case "MOVED", "ASK":
+		return errorKindClusterRedirect
+	case "WRONGTYPE":
+		return errorKindWrongType
+	case "NOSCRIPT":
+		return errorKindNoScript
+	case "NOPERM":
+		return errorKindNoPerm
+	case "WRONGPASS", "NOAUTH":
+		return errorKindAuth
+	default:
+		return errorKindCommand
+	}
+}
+
+// newPromise is like promises.New, except that the errors the promise is
+// rejected with are classified by classifyError.
+func (c *Client) newPromise() (*sobek.Promise, func(result interface{}), func(reason interface{})) {
+	if c.syncCalls != nil {
+		return c.syncCalls.newPromise(nil)
+	}
+
+	promise, resolve, reject := promises.New(c.vu)
+	release := c.hooks.hold(c.vu.RegisterCallback)
+
+	resolveFunc := func(result interface{}) {
+		release()
+		resolve(result)
+	}
+
+	rejectFunc := func(reason interface{}) {
+		release()
+		if err, ok := reason.(error); ok {
+			reason = classifyError(err)
+		}
+
+		reject(reason)
+	}
+
+	return promise, resolveFunc, rejectFunc
+}
+
+// errorKind returns the kind of the provided error, as identified by
+// classifyError, or nil if it isn't identified.
+func errorKind(err error) interface{} {
+	var cmdErr *commandError
+	if errors.As(classifyError(err), &cmdErr) {
+		return cmdErr.Kind
+	}
+
+	return nil
+}
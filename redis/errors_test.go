@@ -0,0 +1,365 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientTimeoutErrors(t *testing.T) {
+	t.Parallel()
+
+	t.Run("pool timeouts are rejected with the pool_timeout kind", func(t *testing.T) {
+		t.Parallel()
+
+		ts := newTestSetup(t)
+		rs := RunT(t)
+		rs.RegisterCommandHandler("GET", func(c *Connection, _ []string) {
+			time.Sleep(300 * time.Millisecond)
+			c.WriteBulkString("bar")
+		})
+
+		gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+			_, err := ts.rt.RunString(fmt.Sprintf(`
+				const redis = new Client({
+					socket: {
+						host: '%s',
+						port: %d,
+						poolSize: 1,
+						poolTimeout: 50,
+						readTimeout: 1000,
+					},
+				});
+
+				// One of the gets holds the pool's only connection, while
+				// the other one waits for it, and times out.
+				Promise.allSettled([redis.get("foo"), redis.get("foo")])
+					.then(results => {
+						const fulfilled = results.filter(r => r.status === "fulfilled")
+						const rejected = results.filter(r => r.status === "rejected")
+						if (fulfilled.length !== 1 || rejected.length !== 1) {
+							throw 'expected exactly one get to time out'
+						}
+
+						const err = rejected[0].reason
+						if (err.kind !== "pool_timeout") {
+							throw 'unexpected error: ' + err.error() + ' (' + err.kind + ')'
+						}
+					})
+			`, rs.Addr().IP.String(), rs.Addr().Port))
+
+			return err
+		})
+
+		assert.NoError(t, gotScriptErr)
+	})
+
+	t.Run("read timeouts are rejected with the network_timeout kind", func(t *testing.T) {
+		t.Parallel()
+
+		ts := newTestSetup(t)
+		rs := RunT(t)
+
+		// The reply is only sent after the client gave up on it. Make sure
+		// it is sent before the server is stopped.
+		replied := make(chan struct{})
+		rs.RegisterCommandHandler("GET", func(c *Connection, _ []string) {
+			defer close(replied)
+
+			time.Sleep(200 * time.Millisecond)
+			c.WriteBulkString("bar")
+			c.Flush()
+		})
+
+		gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+			_, err := ts.rt.RunString(fmt.Sprintf(`
+				const redis = new Client({
+					socket: {
+						host: '%s',
+						port: %d,
+						readTimeout: 50,
+					},
+					maxRetries: -1,
+				});
+
+				redis.get("foo").then(
+					res => { throw 'expected get to time out' },
+					err => { if (err.kind !== "network_timeout") { throw 'unexpected error: ' + err.error() + ' (' + err.kind + ')' } }
+				)
+			`, rs.Addr().IP.String(), rs.Addr().Port))
+
+			return err
+		})
+
+		assert.NoError(t, gotScriptErr)
+		<-replied
+	})
+
+	t.Run("read timeouts are capped to the VU's deadline", func(t *testing.T) {
+		t.Parallel()
+
+		ts := newTestSetup(t)
+		rs := RunT(t)
+
+		ctx, cancel := context.WithTimeout(ts.runtime.VU.CtxField, 200*time.Millisecond)
+		defer cancel()
+		ts.runtime.VU.CtxField = ctx
+
+		// The reply is only sent after the client gave up on it. Make sure
+		// it is sent before the server is stopped.
+		replied := make(chan struct{})
+		rs.RegisterCommandHandler("GET", func(c *Connection, _ []string) {
+			defer close(replied)
+
+			time.Sleep(600 * time.Millisecond)
+			c.WriteBulkString("bar")
+			c.Flush()
+		})
+
+		start := time.Now()
+		gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+			_, err := ts.rt.RunString(fmt.Sprintf(`
+				const redis = new Client({
+					socket: {
+						host: '%s',
+						port: %d,
+						readTimeout: 5000,
+					},
+					maxRetries: -1,
+					capToVUDeadline: true,
+				});
+
+				redis.get("foo").then(
+					res => { throw 'expected get to time out' },
+					err => { if (err.kind !== "deadline") { throw 'unexpected error: ' + err.error() + ' (' + err.kind + ')' } }
+				)
+			`, rs.Addr().IP.String(), rs.Addr().Port))
+
+			return err
+		})
+		elapsed := time.Since(start)
+
+		assert.NoError(t, gotScriptErr)
+		assert.Less(t, elapsed, 500*time.Millisecond)
+		<-replied
+	})
+
+	t.Run("the VU's deadline is ignored without capToVUDeadline", func(t *testing.T) {
+		t.Parallel()
+
+		ts := newTestSetup(t)
+		rs := RunT(t)
+
+		ctx, cancel := context.WithTimeout(ts.runtime.VU.CtxField, 200*time.Millisecond)
+		defer cancel()
+		ts.runtime.VU.CtxField = ctx
+
+		rs.RegisterCommandHandler("GET", func(c *Connection, _ []string) {
+			time.Sleep(400 * time.Millisecond)
+			c.WriteBulkString("bar")
+		})
+
+		gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+			_, err := ts.rt.RunString(fmt.Sprintf(`
+				const redis = new Client('redis://%s');
+
+				redis.get("foo").then(res => {
+					if (res !== "bar") { throw 'unexpected value for get result: ' + res }
+				})
+			`, rs.Addr()))
+
+			return err
+		})
+
+		assert.NoError(t, gotScriptErr)
+	})
+
+	for _, tc := range []struct {
+		name, options, get string
+	}{
+		{name: "commands time out after the commandTimeout option", options: "commandTimeout: 200,", get: `redis.get("foo")`},
+		{name: "commands time out after the timeout passed to withTimeout", get: `redis.withTimeout(200).get("foo")`},
+		{name: "pipelines time out after the commandTimeout option", options: "commandTimeout: 200,", get: `redis.pipeline().get("foo").exec()`},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ts := newTestSetup(t)
+			rs := RunT(t)
+
+			replied := make(chan struct{})
+			rs.RegisterCommandHandler("GET", func(c *Connection, _ []string) {
+				defer close(replied)
+
+				time.Sleep(600 * time.Millisecond)
+				c.WriteBulkString("bar")
+				c.Flush()
+			})
+
+			start := time.Now()
+			gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+				_, err := ts.rt.RunString(fmt.Sprintf(`
+					const redis = new Client({
+						socket: {
+							host: '%s',
+							port: %d,
+							readTimeout: 5000,
+						},
+						maxRetries: -1,
+						%s
+					});
+
+					%s.then(
+						res => { throw 'expected get to time out' },
+						err => { if (err.kind !== "deadline") { throw 'unexpected error: ' + err.error() + ' (' + err.kind + ')' } }
+					)
+				`, rs.Addr().IP.String(), rs.Addr().Port, tc.options, tc.get))
+
+				return err
+			})
+			elapsed := time.Since(start)
+
+			assert.NoError(t, gotScriptErr)
+			assert.Less(t, elapsed, 500*time.Millisecond)
+			<-replied
+		})
+	}
+}
+
+func TestClientReplyErrors(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunT(t)
+	rs.RegisterCommandHandler("LPUSH", func(c *Connection, _ []string) {
+		c.WriteError(errors.New("WRONGTYPE Operation against a key holding the wrong kind of value"))
+	})
+	rs.RegisterCommandHandler("EVALSHA", func(c *Connection, _ []string) {
+		c.WriteError(errors.New("NOSCRIPT No matching script. Please use EVAL."))
+	})
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client('redis://%s');
+
+			redis.lpush("string", "value")
+				.then(
+					res => { throw 'expected lpush to fail' },
+					err => {
+						if (err.name !== "RedisError" || err.kind !== "wrongtype" || err.code !== "WRONGTYPE") {
+							throw 'unexpected error: ' + err.error() + ' (' + err.kind + ', ' + err.code + ')'
+						}
+					}
+				)
+				.then(() => redis.evalsha("abc", []))
+				.then(
+					res => { throw 'expected evalsha to fail' },
+					err => { if (err.kind !== "noscript") { throw 'unexpected error kind: ' + err.kind } }
+				)
+		`, rs.Addr()))
+
+		return err
+	})
+
+	assert.NoError(t, gotScriptErr)
+}
+
+// replyError is a server's error reply, as go-redis reports them.
+type replyError string
+
+func (e replyError) Error() string { return string(e) }
+
+func (replyError) RedisError() {}
+
+func TestClassifyError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		err     error
+		expKind string
+	}{
+		{
+			name:    "context deadline",
+			err:     context.DeadlineExceeded,
+			expKind: errorKindDeadline,
+		},
+		{
+			name:    "wrapped context deadline",
+			err:     fmt.Errorf("get failed: %w", context.DeadlineExceeded),
+			expKind: errorKindDeadline,
+		},
+		{
+			name:    "pool timeout",
+			err:     errors.New("redis: connection pool timeout"),
+			expKind: errorKindPoolTimeout,
+		},
+		{
+			name:    "network timeout",
+			err:     &net.OpError{Op: "read", Net: "tcp", Err: os.ErrDeadlineExceeded},
+			expKind: errorKindNetworkTimeout,
+		},
+		{
+			name:    "network timeout past the command's deadline",
+			err:     &deadlineError{err: &net.OpError{Op: "read", Net: "tcp", Err: os.ErrDeadlineExceeded}},
+			expKind: errorKindDeadline,
+		},
+		{
+			name:    "connection failure",
+			err:     &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)},
+			expKind: errorKindConnection,
+		},
+		{
+			name:    "cluster redirection",
+			err:     replyError("MOVED 3999 127.0.0.1:6381"),
+			expKind: errorKindClusterRedirect,
+		},
+		{
+			name:    "wrong type",
+			err:     replyError("WRONGTYPE Operation against a key holding the wrong kind of value"),
+			expKind: errorKindWrongType,
+		},
+		{
+			name:    "other error replies",
+			err:     replyError("ERR unknown command"),
+			expKind: errorKindCommand,
+		},
+		{
+			name: "nil replies are left untouched",
+			err:  redis.Nil,
+		},
+		{
+			name: "other errors are left untouched",
+			err:  errors.New("ERR unknown command"),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := classifyError(tt.err)
+			assert.ErrorIs(t, got, tt.err)
+
+			var cmdErr *commandError
+			if tt.expKind == "" {
+				assert.False(t, errors.As(got, &cmdErr))
+				return
+			}
+
+			assert.True(t, errors.As(got, &cmdErr))
+			assert.Equal(t, tt.expKind, cmdErr.Kind)
+			assert.Equal(t, tt.err.Error(), got.Error())
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package redis
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grafana/sobek"
+	"github.com/redis/go-redis/v9"
+)
+
+// expectOptions holds the expectations of the Client's expect method.
+type expectOptions struct {
+	// MaxDuration is the latency budget of the command, in milliseconds.
+	MaxDuration float64 `json:"maxDuration,omitempty"`
+
+	// Equals is the expected reply. It is kept raw, so that an expected
+	// null reply can be told apart from a missing expectation.
+	Equals json.RawMessage `json:"equals,omitempty"`
+
+	// Exists tells whether the reply is expected to be non-null, as that
+	// of a read command for an existing key, or null.
+	Exists *bool `json:"exists,omitempty"`
+}
+
+// Expect sends a command, as sendCommand does, and checks its outcome
+// against `expectations`: that it completes within the maxDuration budget,
+// in milliseconds, that its reply equals the equals value, and whether it
+// exists, that is isn't null. A failed command fails its expectations.
+//
+// The outcome is recorded by the redis_expectations rate metric, and, when
+// maxDuration is set, whether the budget was exceeded by the
+// redis_budget_exceeded one, both tagged with the command, so that Redis
+// SLOs can be enforced by thresholds.
+//
+// The promise resolves with {ok, duration, reply, failures}: whether all
+// the expectations were met, the duration of the command, in milliseconds,
+// including waiting for a connection and its retries, its reply, and a
+// description of each unmet expectation.
+func (c *Client) Expect(command string, args []interface{}, expectations map[string]interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	var opts expectOptions
+	if err := decodeOptions(expectations, &opts); err != nil {
+		reject(fmt.Errorf("invalid expect expectations; reason: %w", err))
+		return promise
+	}
+
+	if opts.MaxDuration < 0 {
+		reject(fmt.Errorf("invalid expect expectations; invalid maxDuration: %v; expected a positive number", opts.MaxDuration))
+		return promise
+	}
+
+	var equals interface{}
+	if opts.Equals != nil {
+		if err := json.Unmarshal(opts.Equals, &equals); err != nil {
+			reject(fmt.Errorf("invalid expect expectations; reason: %w", err))
+			return promise
+		}
+	}
+
+	cmdArgs, err := c.binaryArgs(0, args...)
+	if err != nil {
+		reject(fmt.Errorf("invalid expect args; %w", err))
+		return promise
+	}
+
+	doArgs := append([]interface{}{command}, cmdArgs...)
+
+	go func() {
+		start := time.Now()
+		reply, err := c.redisClient.Do(c.context(), doArgs...).Result()
+		elapsed := time.Since(start)
+		duration := float64(elapsed) / float64(time.Millisecond)
+
+		failures := []string{}
+		if errors.Is(err, redis.Nil) {
+			reply, err = nil, nil
+		}
+		if err != nil {
+			reply = nil
+			failures = append(failures, fmt.Sprintf("command failed: %s", err))
+		}
+
+		exceeded := opts.MaxDuration > 0 && duration > opts.MaxDuration
+		if exceeded {
+			failures = append(failures, fmt.Sprintf("duration %.3fms exceeded maxDuration %vms", duration, opts.MaxDuration))
+		}
+
+		if err == nil {
+			if opts.Equals != nil && !replyEquals(reply, equals) {
+				failures = append(failures, fmt.Sprintf("reply %s does not equal %s", formatReply(reply), formatReply(equals)))
+			}
+
+			if opts.Exists != nil && *opts.Exists != (reply != nil) {
+				if *opts.Exists {
+					failures = append(failures, "expected a reply, got null")
+				} else {
+					failures = append(failures, fmt.Sprintf("expected a null reply, got %s", formatReply(reply)))
+				}
+			}
+		}
+
+		tags := map[string]string{"command": strings.ToLower(command)}
+		if len(failures) == 0 {
+			c.pushTaggedMetric(c.metrics.Expectations, 1, tags)
+		} else {
+			c.pushTaggedMetric(c.metrics.Expectations, 0, tags)
+		}
+		if opts.MaxDuration > 0 {
+			if exceeded {
+				c.pushTaggedMetric(c.metrics.BudgetExceeded, 1, tags)
+			} else {
+				c.pushTaggedMetric(c.metrics.BudgetExceeded, 0, tags)
+			}
+		}
+
+		resolve(map[string]interface{}{
+			"ok":       len(failures) == 0,
+			"duration": duration,
+			"reply":    reply,
+			"failures": failures,
+		})
+	}()
+
+	return promise
+}
+
+// replyEquals returns whether the `reply` of a command equals the
+// `expected` value, as decoded from JSON. Arrays are compared element by
+// element, and other values by their string form, as Redis replies with
+// strings for numeric values, such as those of GET.
+func replyEquals(reply, expected interface{}) bool {
+	switch e := expected.(type) {
+	case nil:
+		return reply == nil
+	case []interface{}:
+		r, ok := reply.([]interface{})
+		if !ok || len(r) != len(e) {
+			return false
+		}
+		for idx := range e {
+			if !replyEquals(r[idx], e[idx]) {
+				return false
+			}
+		}
+		return true
+	default:
+		if reply == nil {
+			return false
+		}
+		if _, ok := reply.([]interface{}); ok {
+			return false
+		}
+		return fmt.Sprint(reply) == fmt.Sprint(expected)
+	}
+}
+
+// formatReply formats a reply, or an expected one, for failure messages.
+func formatReply(v interface{}) string {
+	if v == nil {
+		return "null"
+	}
+
+	if b, err := json.Marshal(v); err == nil {
+		return string(b)
+	}
+
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,98 @@
+package redis
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientExpect(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunT(t)
+	rs.RegisterCommandHandler("GET", func(c *Connection, args []string) {
+		switch args[0] {
+		case "slow":
+			time.Sleep(50 * time.Millisecond)
+			c.WriteBulkString("1")
+		case "missing":
+			c.WriteNull()
+		default:
+			c.WriteBulkString("bar")
+		}
+	})
+	rs.RegisterCommandHandler("LRANGE", func(c *Connection, _ []string) {
+		c.WriteArray("a", "b")
+	})
+	rs.RegisterCommandHandler("INCR", func(c *Connection, _ []string) {
+		c.WriteError(errors.New("ERR value is not an integer or out of range"))
+	})
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client('redis://%s');
+
+			redis.expect("GET", ["foo"], { maxDuration: 1000, equals: "bar", exists: true })
+				.then(res => {
+					if (!res.ok || res.reply !== "bar" || res.failures.length !== 0 || !(res.duration >= 0)) {
+						throw 'unexpected expect result: ' + JSON.stringify(res)
+					}
+				})
+				.then(() => redis.expect("get", ["slow"], { maxDuration: 10, equals: 1 }))
+				.then(res => {
+					if (res.ok || res.reply !== "1" || res.failures.length !== 1 || !res.failures[0].includes("exceeded maxDuration 10ms")) {
+						throw 'unexpected expect result: ' + JSON.stringify(res)
+					}
+				})
+				.then(() => redis.expect("GET", ["missing"], { equals: null, exists: false }))
+				.then(res => { if (!res.ok || res.reply !== null) { throw 'unexpected expect result: ' + JSON.stringify(res) } })
+				.then(() => redis.expect("GET", ["missing"], { exists: true }))
+				.then(res => {
+					if (res.ok || res.failures[0] !== "expected a reply, got null") {
+						throw 'unexpected expect result: ' + JSON.stringify(res)
+					}
+				})
+				.then(() => redis.expect("LRANGE", ["list", 0, -1], { equals: ["a", "c"] }))
+				.then(res => {
+					if (res.ok || res.failures[0] !== 'reply ["a","b"] does not equal ["a","c"]') {
+						throw 'unexpected expect result: ' + JSON.stringify(res)
+					}
+				})
+				.then(() => redis.expect("INCR", ["foo"], {}))
+				.then(res => {
+					if (res.ok || res.failures[0] !== "command failed: ERR value is not an integer or out of range") {
+						throw 'unexpected expect result: ' + JSON.stringify(res)
+					}
+				})
+				.then(() => redis.expect("GET", ["foo"], { maxDuration: -1 }))
+				.then(
+					res => { throw 'expected expect to fail' },
+					err => { if (!err.error().includes('invalid maxDuration')) { throw 'unexpected error: ' + err.error() } }
+				)
+		`, rs.Addr()))
+
+		return err
+	})
+
+	assert.NoError(t, gotScriptErr)
+
+	expectations := map[string][]float64{}
+	exceeded := map[string][]float64{}
+	for _, sample := range drainSamples(ts.samples) {
+		command, _ := sample.Tags.Get("command")
+
+		switch sample.Metric.Name {
+		case "redis_expectations":
+			expectations[command] = append(expectations[command], sample.Value)
+		case "redis_budget_exceeded":
+			exceeded[command] = append(exceeded[command], sample.Value)
+		}
+	}
+
+	assert.Equal(t, map[string][]float64{"get": {1, 0, 1, 0}, "lrange": {0}, "incr": {0}}, expectations)
+	assert.Equal(t, map[string][]float64{"get": {0, 1}}, exceeded)
+}
@@ -0,0 +1,369 @@
+package redis
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/grafana/sobek"
+	"github.com/redis/go-redis/v9"
+)
+
+// defaultGeoUnit is the unit of the distances passed to, and returned by,
+// the geo commands, when their unit option is unset.
+const defaultGeoUnit = "m"
+
+// Geoadd adds the provided locations, as {longitude, latitude, member}
+// objects, to the geospatial index stored at `key`, or updates the
+// coordinates of the members already in it.
+//
+// The promise resolves with the number of members added. Calls exceeding
+// the commandChunkSize option are split in pipelined chunks.
+func (c *Client) Geoadd(key string, locations []interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	geoLocations, err := geoLocations(locations)
+	if err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		ctx := c.context()
+		n, err := sumChunks(ctx, c, geoLocations, func(cmd redis.Cmdable, chunk []*redis.GeoLocation) *redis.IntCmd {
+			return cmd.GeoAdd(ctx, key, chunk...)
+		})
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(n)
+	}()
+
+	return promise
+}
+
+// geoSearchQuery holds the query of the Client's geosearch method.
+type geoSearchQuery struct {
+	// Member searches around the member of the index, as its center.
+	Member string `json:"member,omitempty"`
+
+	// Longitude and Latitude search around the provided coordinates, as
+	// an alternative to Member.
+	Longitude *float64 `json:"longitude,omitempty"`
+	Latitude  *float64 `json:"latitude,omitempty"`
+
+	// Radius searches within a circle of that radius.
+	Radius float64 `json:"radius,omitempty"`
+
+	// Width and Height search within a box of those dimensions, as an
+	// alternative to Radius.
+	Width  float64 `json:"width,omitempty"`
+	Height float64 `json:"height,omitempty"`
+
+	// Unit is the unit of the radius, or box dimensions, and of the
+	// returned distances: "m", "km", "ft", or "mi". It defaults to "m".
+	Unit string `json:"unit,omitempty"`
+
+	// Sort sorts the members by their distance to the center: "asc", or
+	// "desc". They are unsorted by default.
+	Sort string `json:"sort,omitempty"`
+
+	// Count caps the number of members returned.
+	Count int `json:"count,omitempty"`
+
+	// Any returns the first `count` members found, rather than the closest
+	// ones.
+	Any bool `json:"any,omitempty"`
+
+	// WithCoord returns the coordinates of the members.
+	WithCoord bool `json:"withCoord,omitempty"`
+
+	// WithDist returns the distance of the members to the center.
+	WithDist bool `json:"withDist,omitempty"`
+
+	// WithHash returns the geohash of the members.
+	WithHash bool `json:"withHash,omitempty"`
+}
+
+// Geosearch returns the members of the geospatial index stored at `key`
+// within the area described by `query`: around a `member` of the index, or
+// `longitude` and `latitude` coordinates, within a `radius`, or a box of
+// `width` and `height`.
+//
+// The promise resolves with an array of members, or, when any of the
+// `withCoord`, `withDist`, and `withHash` options is set, with an array of
+// {member, distance, coordinates: {longitude, latitude}, hash} objects,
+// holding the requested properties.
+func (c *Client) Geosearch(key string, query map[string]interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	var q geoSearchQuery
+	if err := decodeOptions(query, &q); err != nil {
+		reject(fmt.Errorf("invalid geosearch query; reason: %w", err))
+		return promise
+	}
+
+	searchQuery, err := q.toRedisQuery()
+	if err != nil {
+		reject(fmt.Errorf("invalid geosearch query; %w", err))
+		return promise
+	}
+
+	go func() {
+		if !q.WithCoord && !q.WithDist && !q.WithHash {
+			members, err := c.redisClient.GeoSearch(c.context(), key, searchQuery).Result()
+			if err != nil {
+				reject(err)
+				return
+			}
+
+			resolve(members)
+			return
+		}
+
+		locations, err := c.redisClient.GeoSearchLocation(c.context(), key, &redis.GeoSearchLocationQuery{
+			GeoSearchQuery: *searchQuery,
+			WithCoord:      q.WithCoord,
+			WithDist:       q.WithDist,
+			WithHash:       q.WithHash,
+		}).Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(q.locationsToJS(locations))
+	}()
+
+	return promise
+}
+
+// toRedisQuery validates the query, and converts it to its go-redis
+// counterpart.
+func (q geoSearchQuery) toRedisQuery() (*redis.GeoSearchQuery, error) {
+	hasCoordinates := q.Longitude != nil || q.Latitude != nil
+	switch {
+	case q.Member != "" && hasCoordinates:
+		return nil, errors.New("member and longitude/latitude are mutually exclusive")
+	case q.Member == "" && (q.Longitude == nil || q.Latitude == nil):
+		return nil, errors.New("either member, or both longitude and latitude, must be set")
+	}
+
+	hasBox := q.Width != 0 || q.Height != 0
+	switch {
+	case q.Radius != 0 && hasBox:
+		return nil, errors.New("radius and width/height are mutually exclusive")
+	case q.Radius < 0:
+		return nil, fmt.Errorf("invalid radius: %v; expected a positive number", q.Radius)
+	case q.Radius == 0 && (q.Width <= 0 || q.Height <= 0):
+		return nil, errors.New("either radius, or both width and height, must be set to positive numbers")
+	}
+
+	unit, err := geoUnit(q.Unit)
+	if err != nil {
+		return nil, err
+	}
+
+	switch q.Sort {
+	case "", "asc", "desc":
+	default:
+		return nil, fmt.Errorf("invalid sort: %q; expected %q or %q", q.Sort, "asc", "desc")
+	}
+
+	if q.Any && q.Count <= 0 {
+		return nil, errors.New("any requires count")
+	}
+
+	rq := &redis.GeoSearchQuery{
+		Member:     q.Member,
+		Radius:     q.Radius,
+		RadiusUnit: unit,
+		BoxWidth:   q.Width,
+		BoxHeight:  q.Height,
+		BoxUnit:    unit,
+		Sort:       q.Sort,
+		Count:      q.Count,
+		CountAny:   q.Any,
+	}
+	if hasCoordinates {
+		rq.Longitude, rq.Latitude = *q.Longitude, *q.Latitude
+	}
+
+	return rq, nil
+}
+
+// locationsToJS converts the provided search results to an array of
+// objects holding the properties requested by the query.
+func (q geoSearchQuery) locationsToJS(locations []redis.GeoLocation) []map[string]interface{} {
+	converted := make([]map[string]interface{}, len(locations))
+	for idx, loc := range locations {
+		obj := map[string]interface{}{"member": loc.Name}
+		if q.WithDist {
+			obj["distance"] = loc.Dist
+		}
+		if q.WithCoord {
+			obj["coordinates"] = map[string]interface{}{
+				"longitude": loc.Longitude,
+				"latitude":  loc.Latitude,
+			}
+		}
+		if q.WithHash {
+			obj["hash"] = loc.GeoHash
+		}
+
+		converted[idx] = obj
+	}
+
+	return converted
+}
+
+// Geodist returns the distance between `member1` and `member2` in the
+// geospatial index stored at `key`, in `unit`: "m", "km", "ft", or "mi".
+// It defaults to meters.
+//
+// The promise resolves with the distance, or null if any of the members
+// does not exist.
+func (c *Client) Geodist(key string, member1, member2 string, unit string) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	unit, err := geoUnit(unit)
+	if err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		dist, err := c.redisClient.GeoDist(c.context(), key, member1, member2, unit).Result()
+		if errors.Is(err, redis.Nil) {
+			resolve(nil)
+			return
+		}
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(dist)
+	}()
+
+	return promise
+}
+
+// Geopos returns the coordinates of the provided members of the geospatial
+// index stored at `key`.
+//
+// The promise resolves with an array of {longitude, latitude} objects, in
+// the order of the members, null standing for a missing member.
+func (c *Client) Geopos(key string, members ...string) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	if len(members) == 0 {
+		reject(errors.New("at least one member must be provided to geopos"))
+		return promise
+	}
+
+	go func() {
+		positions, err := c.redisClient.GeoPos(c.context(), key, members...).Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		converted := make([]interface{}, len(positions))
+		for idx, pos := range positions {
+			if pos == nil {
+				continue
+			}
+
+			converted[idx] = map[string]interface{}{
+				"longitude": pos.Longitude,
+				"latitude":  pos.Latitude,
+			}
+		}
+
+		resolve(converted)
+	}()
+
+	return promise
+}
+
+// geoLocations converts the provided {longitude, latitude, member} objects
+// to the locations of a GEOADD command.
+func geoLocations(locations []interface{}) ([]*redis.GeoLocation, error) {
+	if len(locations) == 0 {
+		return nil, errors.New("at least one location must be provided")
+	}
+
+	converted := make([]*redis.GeoLocation, len(locations))
+	for idx, l := range locations {
+		obj, ok := l.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid location at index %d; expected a {longitude, latitude, member} object", idx)
+		}
+
+		longitude, ok := toFloat(obj["longitude"])
+		if !ok {
+			return nil, fmt.Errorf("invalid longitude for location at index %d; expected a number", idx)
+		}
+
+		latitude, ok := toFloat(obj["latitude"])
+		if !ok {
+			return nil, fmt.Errorf("invalid latitude for location at index %d; expected a number", idx)
+		}
+
+		member, ok := obj["member"].(string)
+		if !ok || member == "" {
+			return nil, fmt.Errorf("invalid member for location at index %d; expected a non-empty string", idx)
+		}
+
+		converted[idx] = &redis.GeoLocation{Name: member, Longitude: longitude, Latitude: latitude}
+	}
+
+	return converted, nil
+}
+
+// geoUnit validates the provided distance unit, defaulting to meters.
+func geoUnit(unit string) (string, error) {
+	switch unit {
+	case "":
+		return defaultGeoUnit, nil
+	case "m", "km", "ft", "mi":
+		return unit, nil
+	default:
+		return "", fmt.Errorf("invalid unit: %q; expected one of %q, %q, %q, or %q", unit, "m", "km", "ft", "mi")
+	}
+}
+
+// toFloat returns the provided number, as exported from sobek.Runtime, as
+// a float64.
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	default:
+		return 0, false
+	}
+}
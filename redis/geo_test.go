@@ -0,0 +1,99 @@
+package redis
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientGeo(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunT(t)
+	rs.RegisterCommandHandler("GEOADD", func(c *Connection, args []string) {
+		c.WriteInteger(len(args[1:]) / 3)
+	})
+	rs.RegisterCommandHandler("GEOSEARCH", func(c *Connection, args []string) {
+		if args[len(args)-1] != "withdist" && args[len(args)-1] != "withcoord" {
+			c.WriteArray("palermo", "catania")
+			return
+		}
+
+		c.WriteValue([]interface{}{
+			[]interface{}{"palermo", "0.5", []interface{}{"13.361389", "38.115556"}},
+		})
+	})
+	rs.RegisterCommandHandler("GEODIST", func(c *Connection, args []string) {
+		if args[2] != "catania" {
+			c.WriteNull()
+			return
+		}
+
+		c.WriteBulkString("166.2742")
+	})
+	rs.RegisterCommandHandler("GEOPOS", func(c *Connection, _ []string) {
+		c.WriteValue([]interface{}{
+			[]interface{}{"13.361389", "38.115556"},
+			nil,
+		})
+	})
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client('redis://%s');
+
+			redis.geoadd("cities", [
+				{ longitude: 13.361389, latitude: 38.115556, member: "palermo" },
+				{ longitude: 15, latitude: 37.502669, member: "catania" },
+			])
+				.then(res => { if (res !== 2) { throw 'unexpected value for geoadd result: ' + res } })
+				.then(() => redis.geosearch("cities", { longitude: 15, latitude: 37, radius: 200, unit: "km", sort: "asc" }))
+				.then(res => { if (JSON.stringify(res) !== '["palermo","catania"]') { throw 'unexpected value for geosearch result: ' + JSON.stringify(res) } })
+				.then(() => redis.geosearch("cities", { member: "catania", width: 400, height: 400, withCoord: true, withDist: true }))
+				.then(res => {
+					if (res.length !== 1 || res[0].member !== "palermo" || res[0].distance !== 0.5 || "hash" in res[0]) {
+						throw 'unexpected value for geosearch result: ' + JSON.stringify(res)
+					}
+					if (res[0].coordinates.longitude !== 13.361389 || res[0].coordinates.latitude !== 38.115556) {
+						throw 'unexpected coordinates in geosearch result: ' + JSON.stringify(res)
+					}
+				})
+				.then(() => redis.geodist("cities", "palermo", "catania", "km"))
+				.then(res => { if (res !== 166.2742) { throw 'unexpected value for geodist result: ' + res } })
+				.then(() => redis.geodist("cities", "palermo", "rome"))
+				.then(res => { if (res !== null) { throw 'unexpected value for geodist result: ' + res } })
+				.then(() => redis.geopos("cities", "palermo", "rome"))
+				.then(res => {
+					if (res.length !== 2 || res[0].longitude !== 13.361389 || res[0].latitude !== 38.115556 || res[1] !== null) {
+						throw 'unexpected value for geopos result: ' + JSON.stringify(res)
+					}
+				})
+				.then(() => redis.geosearch("cities", { member: "catania", longitude: 15, latitude: 37, radius: 10 }))
+				.then(
+					res => { throw 'expected geosearch to fail' },
+					err => { if (!err.error().includes('mutually exclusive')) { throw 'unexpected error: ' + err.error() } }
+				)
+				.then(() => redis.geodist("cities", "palermo", "catania", "parsecs"))
+				.then(
+					res => { throw 'expected geodist to fail' },
+					err => { if (!err.error().startsWith('invalid unit')) { throw 'unexpected error: ' + err.error() } }
+				)
+		`, rs.Addr()))
+
+		return err
+	})
+
+	assert.NoError(t, gotScriptErr)
+	assert.Equal(t, 6, rs.HandledCommandsCount())
+	assert.Equal(t, [][]string{
+		{"HELLO", "2"},
+		{"GEOADD", "cities", "13.361389", "38.115556", "palermo", "15", "37.502669", "catania"},
+		{"GEOSEARCH", "cities", "fromlonlat", "15", "37", "byradius", "200", "km", "asc"},
+		{"GEOSEARCH", "cities", "frommember", "catania", "bybox", "400", "400", "m", "withcoord", "withdist"},
+		{"GEODIST", "cities", "palermo", "catania", "km"},
+		{"GEODIST", "cities", "palermo", "rome", "m"},
+		{"GEOPOS", "cities", "palermo", "rome"},
+	}, rs.GotCommands())
+}
@@ -0,0 +1,298 @@
+package redis
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/grafana/sobek"
+	"go.k6.io/k6/js/common"
+	"go.k6.io/k6/js/modules"
+	"go.k6.io/k6/js/promises"
+	"go.k6.io/k6/metrics"
+)
+
+// ClientGroup calls the same client methods on several clients, its
+// targets, such as those of the old and new deployments of a migration, or
+// of the two sides of an A/B benchmark, so that scripts don't duplicate
+// every call for each of them.
+//
+// Each target is derived from the provided client with withTags, so that
+// the metrics of its commands are tagged with its name, as the target tag.
+type ClientGroup struct {
+	vu      modules.VU
+	metrics *redisMetrics
+
+	// names holds the names of the targets, sorted.
+	names []string
+
+	// targets holds the clients derived from the provided ones, by name.
+	targets map[string]*sobek.Object
+
+	// compare makes Call compare the results of the targets.
+	compare bool
+}
+
+// clientGroupOptions holds the options of the ClientGroup constructor.
+type clientGroupOptions struct {
+	// Compare makes call report whether the targets' results match, and
+	// record it with the redis_target_matches metric.
+	Compare bool `json:"compare,omitempty"`
+}
+
+// NewClientGroup is the JS constructor for the ClientGroup. It expects an
+// object holding the clients to target, by name, such as {old: oldClient,
+// new: newClient}, and an optional options object.
+func (mi *ModuleInstance) NewClientGroup(call sobek.ConstructorCall) *sobek.Object {
+	rt := mi.vu.Runtime()
+
+	if len(call.Arguments) == 0 || len(call.Arguments) > 2 {
+		common.Throw(rt, errors.New("must specify the targets, and at most an options object"))
+	}
+
+	var opts clientGroupOptions
+	if len(call.Arguments) > 1 {
+		options, _ := call.Arguments[1].Export().(map[string]interface{})
+		if err := decodeOptions(options, &opts); err != nil {
+			common.Throw(rt, fmt.Errorf("invalid ClientGroup options; reason: %w", err))
+		}
+	}
+
+	group := &ClientGroup{
+		vu:      mi.vu,
+		metrics: mi.metrics,
+		targets: make(map[string]*sobek.Object),
+		compare: opts.Compare,
+	}
+
+	if sobek.IsUndefined(call.Arguments[0]) || sobek.IsNull(call.Arguments[0]) {
+		common.Throw(rt, errors.New("invalid ClientGroup targets; expected an object holding clients by name"))
+	}
+	targets := call.Arguments[0].ToObject(rt)
+	for _, name := range targets.Keys() {
+		target, err := targetClient(rt, name, targets.Get(name))
+		if err != nil {
+			common.Throw(rt, fmt.Errorf("invalid ClientGroup targets; reason: %w", err))
+		}
+
+		group.names = append(group.names, name)
+		group.targets[name] = target
+	}
+	if len(group.names) == 0 {
+		common.Throw(rt, errors.New("invalid ClientGroup targets; at least one client must be provided"))
+	}
+	sort.Strings(group.names)
+
+	return rt.ToValue(group).ToObject(rt)
+}
+
+// targetClient returns the client the target `name` sends its commands
+// through: the provided `client`, tagged with the target's name.
+func targetClient(rt *sobek.Runtime, name string, client sobek.Value) (*sobek.Object, error) {
+	if name == "" {
+		return nil, errors.New("target names must not be empty")
+	}
+
+	obj, ok := client.(*sobek.Object)
+	if !ok {
+		return nil, fmt.Errorf("the %q target isn't a Client", name)
+	}
+	withTags, ok := sobek.AssertFunction(obj.Get("withTags"))
+	if !ok {
+		return nil, fmt.Errorf("the %q target isn't a Client", name)
+	}
+
+	tagged, err := withTags(client, rt.ToValue(map[string]string{"target": name}))
+	if err != nil {
+		return nil, err
+	}
+
+	return tagged.ToObject(rt), nil
+}
+
+// Targets returns the names of the group's targets, sorted.
+func (g *ClientGroup) Targets() []string {
+	return append([]string{}, g.names...)
+}
+
+// Call calls the client method named `method`, such as "get", with `args`,
+// on each of the group's targets, without waiting for one to complete
+// before calling the next.
+//
+// The promise resolves once the calls completed on every target, with an
+// object holding their outcome, by target name, as its `targets` property:
+// the `value` its promise resolved with, or its `error`, and its
+// `duration`, in milliseconds. The promise isn't rejected when the call
+// fails on a target, but if `method` isn't a client method.
+//
+// With the compare option, the object also holds whether the targets
+// agreed, as its `match` property: whether their values, and errors, are
+// equal. The outcome is recorded by the redis_target_matches rate metric,
+// tagged with the `method`.
+func (g *ClientGroup) Call(method string, args ...sobek.Value) *sobek.Promise {
+	rt := g.vu.Runtime()
+	promise, resolve, reject := promises.New(g.vu)
+
+	methods := make(map[string]sobek.Callable, len(g.names))
+	for _, name := range g.names {
+		fn, ok := sobek.AssertFunction(g.targets[name].Get(method))
+		if !ok {
+			reject(fmt.Errorf("unknown client method %q", method))
+			return promise
+		}
+		methods[name] = fn
+	}
+
+	outcomes := make(map[string]*targetOutcome, len(g.names))
+	pending := len(g.names)
+	settle := func(name string, outcome *targetOutcome) {
+		outcomes[name] = outcome
+		if pending--; pending == 0 {
+			resolve(g.report(method, outcomes))
+		}
+	}
+
+	for _, name := range g.names {
+		name := name
+		start := time.Now()
+		done := func(value sobek.Value, err error) {
+			settle(name, &targetOutcome{value: value, err: err, duration: time.Since(start)})
+		}
+
+		target := g.targets[name]
+		ret, err := methods[name](target, args...)
+		if err != nil {
+			done(nil, err)
+			continue
+		}
+
+		// Blocking clients return their results, rather than a promise.
+		if _, ok := ret.Export().(*sobek.Promise); !ok {
+			done(ret, nil)
+			continue
+		}
+
+		then, _ := sobek.AssertFunction(ret.ToObject(rt).Get("then"))
+		_, err = then(ret,
+			rt.ToValue(func(value sobek.Value) { done(value, nil) }),
+			rt.ToValue(func(reason sobek.Value) { done(nil, rejectionError(reason)) }),
+		)
+		if err != nil {
+			done(nil, err)
+		}
+	}
+
+	return promise
+}
+
+// targetOutcome is the outcome of a call on one of the group's targets.
+type targetOutcome struct {
+	value    sobek.Value
+	err      error
+	duration time.Duration
+}
+
+// rejectionError returns the error a promise was rejected with, `reason`.
+func rejectionError(reason sobek.Value) error {
+	if err, ok := reason.Export().(error); ok {
+		return err
+	}
+
+	return errors.New(reason.String())
+}
+
+// report returns the object Call resolves with, from the `outcomes` of the
+// call of `method` on the targets, by name, and records whether they
+// match, with the compare option.
+func (g *ClientGroup) report(method string, outcomes map[string]*targetOutcome) map[string]interface{} {
+	targets := make(map[string]interface{}, len(outcomes))
+	for name, outcome := range outcomes {
+		var value, reason interface{}
+		if outcome.err != nil {
+			reason = outcome.err.Error()
+		} else {
+			value = outcome.value
+		}
+
+		targets[name] = map[string]interface{}{
+			"value":    value,
+			"error":    reason,
+			"duration": float64(outcome.duration) / float64(time.Millisecond),
+		}
+	}
+
+	report := map[string]interface{}{"targets": targets}
+	if !g.compare {
+		return report
+	}
+
+	match := g.outcomesMatch(outcomes)
+	report["match"] = match
+
+	sample := 0.0
+	if match {
+		sample = 1
+	}
+	g.pushMetric(g.metrics.TargetMatches, sample, map[string]string{"method": method})
+
+	return report
+}
+
+// outcomesMatch returns whether the targets' `outcomes` are equal: whether
+// their values, and error messages, are.
+func (g *ClientGroup) outcomesMatch(outcomes map[string]*targetOutcome) bool {
+	first := outcomes[g.names[0]]
+	for _, name := range g.names[1:] {
+		outcome := outcomes[name]
+		if (first.err == nil) != (outcome.err == nil) {
+			return false
+		}
+		if first.err != nil {
+			if first.err.Error() != outcome.err.Error() {
+				return false
+			}
+			continue
+		}
+		if !reflect.DeepEqual(exportValue(first.value), exportValue(outcome.value)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// exportValue returns the Go value of `value`, which may be nil.
+func exportValue(value sobek.Value) interface{} {
+	if value == nil {
+		return nil
+	}
+
+	return value.Export()
+}
+
+// pushMetric emits a sample of the provided metric, tagged with the VU's
+// current tags, and the provided ones.
+func (g *ClientGroup) pushMetric(metric *metrics.Metric, value float64, tags map[string]string) {
+	state := g.vu.State()
+	if state == nil || metric == nil {
+		return
+	}
+
+	ctm := state.Tags.GetCurrentValues()
+	tagSet := ctm.Tags
+	for key, val := range tags {
+		tagSet = tagSet.With(key, val)
+	}
+
+	metrics.PushIfNotDone(g.vu.Context(), state.Samples, metrics.Sample{
+		TimeSeries: metrics.TimeSeries{
+			Metric: metric,
+			Tags:   tagSet,
+		},
+		Time:     time.Now(),
+		Metadata: ctm.Metadata,
+		Value:    value,
+	})
+}
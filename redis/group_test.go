@@ -0,0 +1,164 @@
+package redis
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientGroupCall(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+
+	oldServer := RunT(t)
+	oldServer.RegisterCommandHandler("GET", func(c *Connection, args []string) {
+		switch args[0] {
+		case "foo":
+			c.WriteBulkString("bar")
+		case "counter":
+			c.WriteBulkString("1")
+		default:
+			c.WriteError(errors.New("ERR unavailable"))
+		}
+	})
+
+	newServer := RunT(t)
+	newServer.RegisterCommandHandler("GET", func(c *Connection, args []string) {
+		switch args[0] {
+		case "foo":
+			c.WriteBulkString("bar")
+		case "counter":
+			c.WriteBulkString("2")
+		default:
+			c.WriteNull()
+		}
+	})
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const group = new ClientGroup({
+				old: new Client({ socket: { host: "%s", port: %d } }),
+				new: new Client({ socket: { host: "%s", port: %d } }),
+			}, { compare: true });
+
+			const targets = group.targets();
+			if (targets.length !== 2 || targets[0] !== "new" || targets[1] !== "old") {
+				throw 'unexpected targets: ' + JSON.stringify(targets);
+			}
+
+			group.call("get", "foo")
+				.then(res => {
+					if (res.match !== true) { throw 'expected foo to match' }
+					if (res.targets.old.value !== "bar" || res.targets.new.value !== "bar") {
+						throw 'unexpected foo values: ' + JSON.stringify(res.targets);
+					}
+					if (res.targets.old.error !== null || typeof res.targets.new.duration !== "number") {
+						throw 'unexpected foo outcomes: ' + JSON.stringify(res.targets);
+					}
+				})
+				.then(() => group.call("get", "counter"))
+				.then(res => {
+					if (res.match !== false) { throw 'expected counter not to match' }
+					if (res.targets.old.value !== "1" || res.targets.new.value !== "2") {
+						throw 'unexpected counter values: ' + JSON.stringify(res.targets);
+					}
+				})
+				.then(() => group.call("get", "missing"))
+				.then(res => {
+					if (res.match !== false) { throw 'expected missing not to match' }
+					if (res.targets.old.error !== "ERR unavailable" || res.targets.old.value !== null) {
+						throw 'unexpected old outcome: ' + JSON.stringify(res.targets.old);
+					}
+					if (res.targets.new.error !== "redis: nil") {
+						throw 'unexpected new outcome: ' + JSON.stringify(res.targets.new);
+					}
+				})
+				.then(() => group.call("nope"))
+				.then(
+					res => { throw 'expected unknown methods to be rejected' },
+					err => { if (err.error() !== 'unknown client method "nope"') { throw 'unexpected error: ' + err.error() } }
+				)
+		`, oldServer.Addr().IP.String(), oldServer.Addr().Port, newServer.Addr().IP.String(), newServer.Addr().Port))
+
+		return err
+	})
+	require.NoError(t, gotScriptErr)
+
+	matches := []float64{}
+	ops := map[string]int{}
+	for _, sample := range drainSamples(ts.samples) {
+		switch sample.Metric.Name {
+		case "redis_target_matches":
+			method, _ := sample.Tags.Get("method")
+			assert.Equal(t, "get", method)
+			matches = append(matches, sample.Value)
+		case "redis_ops":
+			target, _ := sample.Tags.Get("target")
+			ops[target] += int(sample.Value)
+		}
+	}
+
+	assert.Equal(t, []float64{1, 0, 0}, matches)
+	assert.Equal(t, map[string]int{"old": 3, "new": 3}, ops)
+}
+
+func TestNewClientGroup(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		script  string
+		wantErr string
+	}{
+		{
+			name:   "targets",
+			script: `new ClientGroup({ a: new Client("redis://localhost:6379") })`,
+		},
+		{
+			name:   "options",
+			script: `new ClientGroup({ a: new Client("redis://localhost:6379") }, { compare: true })`,
+		},
+		{
+			name:    "no targets",
+			script:  `new ClientGroup()`,
+			wantErr: "must specify the targets",
+		},
+		{
+			name:    "empty targets",
+			script:  `new ClientGroup({})`,
+			wantErr: "at least one client must be provided",
+		},
+		{
+			name:    "not a client",
+			script:  `new ClientGroup({ a: "redis://localhost:6379" })`,
+			wantErr: `the "a" target isn't a Client`,
+		},
+		{
+			name:    "unknown option",
+			script:  `new ClientGroup({ a: new Client("redis://localhost:6379") }, { unknown: true })`,
+			wantErr: "invalid ClientGroup options",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ts := newInitContextTestSetup(t)
+
+			_, err := ts.rt.RunString(tt.script)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
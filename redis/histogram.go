@@ -0,0 +1,140 @@
+package redis
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+	"go.k6.io/k6/js/common"
+)
+
+// commandSizeBuckets are the upper bounds, in bytes, of the buckets the
+// size of commands' arguments is tallied in. Larger commands are tallied
+// in an additional, unbounded, bucket.
+var commandSizeBuckets = []int{64, 256, 1024, 4096, 16384, 65536}
+
+// commandTally counts the commands sent by a Client, by name and by size
+// of their arguments, when the collectCommandHistogram option is set.
+//
+// The zero value is an empty tally ready to use.
+type commandTally struct {
+	mu       sync.Mutex
+	total    int64
+	commands map[string]*commandCount
+}
+
+// commandCount is the tally of a single command.
+type commandCount struct {
+	count int64
+
+	// sizes holds the count of each of commandSizeBuckets, followed by
+	// the count of the unbounded bucket.
+	sizes []int64
+}
+
+// record tallies the provided command.
+func (ct *commandTally) record(cmd redis.Cmder) {
+	name := cmd.Name()
+	bucket := sizeBucket(argsSize(cmd.Args()))
+
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+
+	if ct.commands == nil {
+		ct.commands = make(map[string]*commandCount)
+	}
+
+	count, ok := ct.commands[name]
+	if !ok {
+		count = &commandCount{sizes: make([]int64, len(commandSizeBuckets)+1)}
+		ct.commands[name] = count
+	}
+
+	ct.total++
+	count.count++
+	count.sizes[bucket]++
+}
+
+// histogram returns the tally as an object holding the `total` number of
+// commands, and for each command name, its `count`, its `share` of the
+// total, and the count of each bucket of argument `sizes`, indexed by the
+// bucket's upper bound.
+func (ct *commandTally) histogram() map[string]interface{} {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+
+	commands := make(map[string]interface{}, len(ct.commands))
+	for name, count := range ct.commands {
+		sizes := make(map[string]int64, len(count.sizes))
+		for idx, n := range count.sizes {
+			le := "+Inf"
+			if idx < len(commandSizeBuckets) {
+				le = strconv.Itoa(commandSizeBuckets[idx])
+			}
+			sizes[le] = n
+		}
+
+		commands[name] = map[string]interface{}{
+			"count": count.count,
+			"share": float64(count.count) / float64(ct.total),
+			"sizes": sizes,
+		}
+	}
+
+	return map[string]interface{}{
+		"total":    ct.total,
+		"commands": commands,
+	}
+}
+
+// argsSize returns the size, in bytes, of the arguments of a command,
+// excluding its name. To keep it cheap, only strings and byte slices are
+// measured; other values are accounted for as 8 bytes.
+func argsSize(args []interface{}) int {
+	size := 0
+	for idx, arg := range args {
+		if idx == 0 {
+			continue
+		}
+
+		switch v := arg.(type) {
+		case string:
+			size += len(v)
+		case []byte:
+			size += len(v)
+		default:
+			size += 8
+		}
+	}
+
+	return size
+}
+
+// sizeBucket returns the index of the bucket `size` is tallied in.
+func sizeBucket(size int) int {
+	for idx, bound := range commandSizeBuckets {
+		if size <= bound {
+			return idx
+		}
+	}
+
+	return len(commandSizeBuckets)
+}
+
+// CommandHistogram returns the distribution of the commands sent by the
+// Client so far, by name and size of their arguments, to help confirming
+// the generated load matches the intended command mix and payload sizes.
+//
+// As the tally is kept by each Client, the distribution only covers the
+// commands sent by the VU it belongs to.
+//
+// It requires the collectCommandHistogram option, so that tallying
+// commands doesn't cost anything to the clients that don't use it.
+func (c *Client) CommandHistogram() map[string]interface{} {
+	if c.redisOptions == nil || !c.redisOptions.CollectCommandHistogram {
+		common.Throw(c.vu.Runtime(), errors.New("commandHistogram requires the collectCommandHistogram option"))
+	}
+
+	return c.commandTally.histogram()
+}
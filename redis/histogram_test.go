@@ -0,0 +1,82 @@
+package redis
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientCommandHistogram(t *testing.T) {
+	t.Parallel()
+
+	t.Run("commands are tallied by name and size", func(t *testing.T) {
+		t.Parallel()
+
+		ts := newTestSetup(t)
+		rs := RunT(t)
+		rs.RegisterCommandHandler("GET", func(c *Connection, _ []string) {
+			c.WriteBulkString("bar")
+		})
+		rs.RegisterCommandHandler("SET", func(c *Connection, _ []string) {
+			c.WriteOK()
+		})
+
+		gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+			_, err := ts.rt.RunString(fmt.Sprintf(`
+				const redis = new Client({
+					socket: {
+						host: '%s',
+						port: %d,
+					},
+					collectCommandHistogram: true,
+				});
+
+				Promise.all([redis.get("foo"), redis.get("foo"), redis.get("foo"), redis.set("foo", "x".repeat(300), 0)])
+					.then(() => {
+						const histogram = redis.commandHistogram();
+						if (histogram.total !== 4) {
+							throw 'unexpected total: ' + JSON.stringify(histogram)
+						}
+
+						const get = histogram.commands.get;
+						if (get.count !== 3 || get.share !== 0.75 || get.sizes["64"] !== 3) {
+							throw 'unexpected get tally: ' + JSON.stringify(get)
+						}
+
+						const set = histogram.commands.set;
+						if (set.count !== 1 || set.sizes["1024"] !== 1 || set.sizes["256"] !== 0) {
+							throw 'unexpected set tally: ' + JSON.stringify(set)
+						}
+					})
+			`, rs.Addr().IP.String(), rs.Addr().Port))
+
+			return err
+		})
+
+		assert.NoError(t, gotScriptErr)
+	})
+
+	t.Run("the option is required", func(t *testing.T) {
+		t.Parallel()
+
+		ts := newTestSetup(t)
+		rs := RunT(t)
+
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client('redis://%s');
+			redis.commandHistogram();
+		`, rs.Addr()))
+
+		assert.ErrorContains(t, err, "commandHistogram requires the collectCommandHistogram option")
+	})
+}
+
+func TestSizeBucket(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, 0, sizeBucket(0))
+	assert.Equal(t, 0, sizeBucket(64))
+	assert.Equal(t, 1, sizeBucket(65))
+	assert.Equal(t, len(commandSizeBuckets), sizeBucket(1<<20))
+}
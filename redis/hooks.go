@@ -0,0 +1,512 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.k6.io/k6/metrics"
+	"golang.org/x/time/rate"
+)
+
+// clientContextKey is the context key under which the Client issuing a
+// command is stored.
+type clientContextKey struct{}
+
+// context returns the context commands are executed with: the VU's context,
+// carrying the Client, so that the go-redis hooks can reach it.
+//
+// Unless the capToVUDeadline option is set, the VU's deadline is hidden from
+// go-redis, which would otherwise cap the commands' timeouts to it.
+func (c *Client) context() context.Context {
+	ctx := c.vu.Context()
+	if c.redisOptions == nil || !c.redisOptions.CapToVUDeadline {
+		ctx = withoutDeadline{ctx}
+	}
+
+	return context.WithValue(ctx, clientContextKey{}, c)
+}
+
+// withoutDeadline is a context hiding the deadline of its parent, while
+// still being canceled along with it.
+type withoutDeadline struct {
+	context.Context
+}
+
+// Deadline implements the context.Context interface.
+func (withoutDeadline) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+// commandTimeout returns the time commands sent by the Client are given to
+// complete, or zero if they have no timeout.
+func (c *Client) commandTimeout() time.Duration {
+	if c.timeout > 0 {
+		return c.timeout
+	}
+
+	if c.redisOptions == nil {
+		return 0
+	}
+
+	return time.Duration(c.redisOptions.CommandTimeout) * time.Millisecond
+}
+
+// withCommandTimeout returns a copy of the provided command context, which
+// expires after the timeout of the Client carried by the context, if any.
+// Blocking commands are given their own timeout on top of it.
+func withCommandTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	c, ok := clientFromContext(ctx)
+	if !ok || c.commandTimeout() <= 0 {
+		return ctx, func() {}
+	}
+
+	timeout := c.commandTimeout()
+	if blocking, ok := ctx.Value(blockingTimeoutContextKey{}).(time.Duration); ok {
+		timeout += blocking
+	}
+
+	return context.WithTimeout(ctx, timeout)
+}
+
+// clientFromContext returns the Client stored in the provided context, if any.
+func clientFromContext(ctx context.Context) (*Client, bool) {
+	c, ok := ctx.Value(clientContextKey{}).(*Client)
+	return c, ok
+}
+
+// clientHook is the go-redis hook installed on every go-redis client
+// instantiated by the RootModule.
+//
+// As go-redis clients are shared by all the VUs using the same options,
+// the hook's state is shared by those VUs too. VU-specific behavior relies
+// on the Client carried by the command's context.
+type clientHook struct {
+	// limiter paces the commands sent through the go-redis client,
+	// when the maxCommandsPerSecond option is set.
+	limiter *rate.Limiter
+
+	// dialJitter is the maximum random delay waited before dialing new
+	// connections, when the reconnectJitterMs option is set.
+	dialJitter time.Duration
+
+	// maxRetries is the number of times failed commands are retried.
+	maxRetries int
+
+	// dials counts the connections dialed.
+	dials atomic.Int64
+
+	// minRetryBackoff and maxRetryBackoff bound the time waited before
+	// retrying commands.
+	minRetryBackoff time.Duration
+	maxRetryBackoff time.Duration
+}
+
+// The defaults of the retry options, as in go-redis.
+const (
+	defaultMaxRetries      = 3
+	defaultMinRetryBackoff = 8 * time.Millisecond
+	defaultMaxRetryBackoff = 512 * time.Millisecond
+)
+
+var _ redis.Hook = &clientHook{}
+
+// newClientHook returns a new clientHook configured according to the
+// provided options.
+func newClientHook(opts *universalOptions) *clientHook {
+	hook := &clientHook{}
+
+	if opts.MaxCommandsPerSecond > 0 {
+		hook.limiter = rate.NewLimiter(rate.Limit(opts.MaxCommandsPerSecond), 1)
+	}
+
+	hook.dialJitter = time.Duration(opts.ReconnectJitterMs) * time.Millisecond
+
+	// As in go-redis, -1 disables retries, and backoffs, while zero values
+	// stand for the defaults.
+	retries := &redis.UniversalOptions{}
+	if opts.UniversalOptions != nil {
+		retries = opts.UniversalOptions
+	}
+	hook.maxRetries = retryOption(retries.MaxRetries, defaultMaxRetries)
+	hook.minRetryBackoff = retryOption(retries.MinRetryBackoff, defaultMinRetryBackoff)
+	hook.maxRetryBackoff = retryOption(retries.MaxRetryBackoff, defaultMaxRetryBackoff)
+
+	return hook
+}
+
+// retryOption returns the value of a retry option, `value`, or `def` if it
+// is zero, or zero if it is -1.
+func retryOption[T int | time.Duration](value, def T) T {
+	switch value {
+	case -1:
+		return 0
+	case 0:
+		return def
+	default:
+		return value
+	}
+}
+
+// DialHook implements the redis.Hook interface.
+func (h *clientHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if err := h.jitter(ctx); err != nil {
+			return nil, err
+		}
+
+		conn, err := next(ctx, network, addr)
+		if err == nil {
+			h.dials.Add(1)
+		}
+
+		return conn, err
+	}
+}
+
+// ProcessHook implements the redis.Hook interface.
+func (h *clientHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		ctx, cancel := withCommandTimeout(ctx)
+		defer cancel()
+
+		if err := h.throttle(ctx, 1); err != nil {
+			return err
+		}
+
+		recordCommands(ctx, cmd)
+
+		start := time.Now()
+		err := h.retry(ctx, cmd.Name(), func() error {
+			return next(ctx, cmd)
+		})
+		duration := time.Since(start)
+		err = withDeadlineError(ctx, err)
+		recordLatency(ctx, duration, cmd, err)
+		logCommand(ctx, duration, cmd, err)
+
+		return err
+	}
+}
+
+// ProcessPipelineHook implements the redis.Hook interface.
+func (h *clientHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		ctx, cancel := withCommandTimeout(ctx)
+		defer cancel()
+
+		if err := h.throttle(ctx, len(cmds)); err != nil {
+			return err
+		}
+
+		recordCommands(ctx, cmds...)
+
+		start := time.Now()
+		err := h.retry(ctx, "pipeline", func() error {
+			return next(ctx, cmds)
+		})
+		duration := time.Since(start)
+		err = withDeadlineError(ctx, err)
+		for _, cmd := range cmds {
+			if cmdErr := cmd.Err(); cmdErr != nil {
+				cmd.SetErr(withDeadlineError(ctx, cmdErr))
+			}
+			recordLatency(ctx, duration, cmd, cmd.Err())
+			logCommand(ctx, duration, cmd, cmd.Err())
+		}
+
+		return err
+	}
+}
+
+// retry calls `process` until it succeeds, fails with an error which isn't
+// worth retrying, or maxRetries retries failed too. Retries are delayed by
+// an exponential backoff, and counted by the redis_retries metric, tagged
+// with the name of the `command`.
+//
+// Commands are retried by the hook rather than by go-redis, whose own
+// retries are disabled by newUniversalClient, as go-redis doesn't report
+// them.
+func (h *clientHook) retry(ctx context.Context, command string, process func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := process()
+		if attempt > h.maxRetries || !shouldRetry(ctx, err) {
+			return err
+		}
+
+		if err := sleep(ctx, h.retryBackoff(attempt)); err != nil {
+			return err
+		}
+
+		if c, ok := clientFromContext(ctx); ok {
+			c.pushTaggedMetric(c.metrics.Retries, 1, map[string]string{"command": command})
+		}
+	}
+}
+
+// retryBackoff returns the time waited before the provided retry attempt,
+// growing exponentially, with jitter, from minRetryBackoff to
+// maxRetryBackoff, as in go-redis.
+func (h *clientHook) retryBackoff(attempt int) time.Duration {
+	if h.minRetryBackoff <= 0 {
+		return 0
+	}
+
+	backoff := h.minRetryBackoff << uint(attempt)
+	if backoff < h.minRetryBackoff {
+		// The backoff overflowed.
+		return h.maxRetryBackoff
+	}
+
+	backoff = h.minRetryBackoff + time.Duration(rand.Int63n(int64(backoff))) //nolint:gosec
+	if backoff > h.maxRetryBackoff {
+		return h.maxRetryBackoff
+	}
+
+	return backoff
+}
+
+// shouldRetry returns whether a command which failed with `err` is worth
+// retrying, as go-redis does: network failures, and transient server
+// errors, such as a replica loading its dataset, are.
+//
+// Timeouts aren't retried for blocking commands, which would otherwise wait
+// for longer than their own timeout.
+func shouldRetry(ctx context.Context, err error) bool {
+	var netErr net.Error
+	switch {
+	case err == nil, errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return false
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return true
+	case err.Error() == poolTimeoutMessage:
+		return false
+	case errors.As(err, &netErr):
+		return !netErr.Timeout() || ctx.Value(blockingTimeoutContextKey{}) == nil
+	}
+
+	msg := err.Error()
+	for _, prefix := range []string{"LOADING ", "READONLY ", "CLUSTERDOWN ", "TRYAGAIN "} {
+		if strings.HasPrefix(msg, prefix) {
+			return true
+		}
+	}
+
+	return msg == "ERR max number of clients reached"
+}
+
+// sleep blocks for `d`, or until the context is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// jitter blocks for a random delay of up to dialJitter, or until the
+// context is done. It spreads the dials of many connections, such as all
+// the VUs reconnecting after a failover, over time, on top of go-redis'
+// retry backoff.
+func (h *clientHook) jitter(ctx context.Context) error {
+	if h.dialJitter <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(time.Duration(rand.Int63n(int64(h.dialJitter)))) //nolint:gosec
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// recordCommands tallies the provided commands in the tally of the Client
+// sending them, when its collectCommandHistogram option is set.
+func recordCommands(ctx context.Context, cmds ...redis.Cmder) {
+	c, ok := clientFromContext(ctx)
+	if !ok || c.redisOptions == nil || !c.redisOptions.CollectCommandHistogram {
+		return
+	}
+
+	for _, cmd := range cmds {
+		c.commandTally.record(cmd)
+	}
+}
+
+// recordLatency records the latency of `cmd`, which completed with `err`
+// after `duration`, in the stats of the Client sending it.
+func recordLatency(ctx context.Context, duration time.Duration, cmd redis.Cmder, err error) {
+	c, ok := clientFromContext(ctx)
+	if !ok || c.stats == nil {
+		return
+	}
+
+	c.stats.record(cmd.Name(), duration, err)
+}
+
+// throttle blocks until the limiter lets `n` commands through, or the
+// context is done. The time spent waiting is emitted as the
+// redis_throttle_wait metric.
+func (h *clientHook) throttle(ctx context.Context, n int) error {
+	if h.limiter == nil {
+		return nil
+	}
+
+	start := time.Now()
+	for i := 0; i < n; i++ {
+		if err := h.limiter.Wait(ctx); err != nil {
+			return err
+		}
+	}
+
+	if c, ok := clientFromContext(ctx); ok {
+		c.pushMetric(c.metrics.ThrottleWait, metrics.D(time.Since(start)))
+	}
+
+	return nil
+}
+
+// commandMetricsHook is the go-redis hook emitting the redis_ops,
+// redis_op_duration, and redis_errors metrics, tagged with the address of
+// the node the commands are sent to.
+type commandMetricsHook struct {
+	address string
+}
+
+var _ redis.Hook = &commandMetricsHook{}
+
+// nodeDialHook applies the dial hook of a clientHook to the nodes of a
+// cluster client: they dial their connections themselves, and go-redis
+// never calls the dial hooks of the cluster client.
+type nodeDialHook struct {
+	*clientHook
+}
+
+var _ redis.Hook = nodeDialHook{}
+
+// ProcessHook implements the redis.Hook interface. The commands are handled
+// by the clientHook of the cluster client.
+func (h nodeDialHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return next
+}
+
+// ProcessPipelineHook implements the redis.Hook interface.
+func (h nodeDialHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return next
+}
+
+// addNodeHooks installs a commandMetricsHook, and a tracingHook, on the
+// provided go-redis client. Cluster clients get them for each node, so that
+// commands are tagged with the address of the node serving them, while
+// sentinel-backed clients are tagged with the name of their master. The
+// nodes of cluster clients also get the dial hook of `hook`, the client's
+// clientHook.
+func addNodeHooks(client redis.UniversalClient, opts *universalOptions, hook *clientHook) {
+	switch cl := client.(type) {
+	case *redis.ClusterClient:
+		cl.OnNewNode(func(node *redis.Client) {
+			node.AddHook(nodeDialHook{hook})
+			node.AddHook(keyPrefixHook{})
+			node.AddHook(writeGuardHook{})
+			node.AddHook(commandGuardHook{node: true})
+			node.AddHook(&commandMetricsHook{address: node.Options().Addr})
+			node.AddHook(&tracingHook{address: node.Options().Addr})
+		})
+	case *redis.Client:
+		address := cl.Options().Addr
+		if opts.MasterName != "" {
+			address = opts.MasterName
+		}
+
+		cl.AddHook(&commandMetricsHook{address: address})
+		cl.AddHook(&tracingHook{address: address})
+	}
+}
+
+// DialHook implements the redis.Hook interface.
+func (h *commandMetricsHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+// ProcessHook implements the redis.Hook interface.
+func (h *commandMetricsHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmd)
+
+		// go-redis only sets the error of single commands once the hooks
+		// returned.
+		var failed []string
+		if isCommandError(err) {
+			failed = append(failed, cmd.Name())
+		}
+
+		h.record(ctx, cmd.Name(), time.Since(start), failed)
+
+		return err
+	}
+}
+
+// ProcessPipelineHook implements the redis.Hook interface.
+//
+// Pipelines, and transactions, are measured as a single "pipeline"
+// operation, while each of their failed commands counts as an error.
+func (h *commandMetricsHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmds)
+
+		var failed []string
+		for _, cmd := range cmds {
+			if isCommandError(cmd.Err()) {
+				failed = append(failed, cmd.Name())
+			}
+		}
+
+		h.record(ctx, "pipeline", time.Since(start), failed)
+
+		return err
+	}
+}
+
+// record emits the metrics of an operation, along with an error for each of
+// the `failed` commands, on behalf of the Client carried by the context, if
+// any.
+func (h *commandMetricsHook) record(ctx context.Context, name string, elapsed time.Duration, failed []string) {
+	c, ok := clientFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	tags := map[string]string{"command": name, "address": h.address}
+	c.pushTaggedMetric(c.metrics.Ops, 1, tags)
+	c.pushTaggedMetric(c.metrics.OpDuration, metrics.D(elapsed), tags)
+
+	for _, command := range failed {
+		c.pushTaggedMetric(c.metrics.Errors, 1, map[string]string{"command": command, "address": h.address})
+	}
+}
+
+// isCommandError returns whether the provided error denotes a failed
+// command. A missing key is reported by a nil reply, which isn't an error.
+func isCommandError(err error) bool {
+	return err != nil && !errors.Is(err, redis.Nil)
+}
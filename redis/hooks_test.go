@@ -0,0 +1,341 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.k6.io/k6/metrics"
+)
+
+func TestClientMaxCommandsPerSecond(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunT(t)
+	rs.RegisterCommandHandler("INCR", func(c *Connection, _ []string) {
+		c.WriteInteger(1)
+	})
+
+	start := time.Now()
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client({
+				socket: {
+					host: '%s',
+					port: %d,
+				},
+				maxCommandsPerSecond: 20,
+			});
+
+			Promise.all([
+				redis.incr("counter"),
+				redis.incr("counter"),
+				redis.incr("counter"),
+				redis.incr("counter"),
+				redis.incr("counter"),
+			])
+		`, rs.Addr().IP.String(), rs.Addr().Port))
+
+		return err
+	})
+	elapsed := time.Since(start)
+
+	assert.NoError(t, gotScriptErr)
+	assert.Equal(t, 5, rs.HandledCommandsCount())
+
+	// The first command goes through immediately, while the following
+	// ones are paced at 50ms intervals.
+	assert.GreaterOrEqual(t, elapsed, 200*time.Millisecond)
+
+	throttleWaits := 0
+	for _, sample := range drainSamples(ts.samples) {
+		if sample.Metric.Name == "redis_throttle_wait" {
+			throttleWaits++
+		}
+	}
+	assert.Equal(t, 5, throttleWaits)
+}
+
+func TestClientHookDialJitter(t *testing.T) {
+	t.Parallel()
+
+	opts := &universalOptions{clientOptions: clientOptions{ReconnectJitterMs: 50}}
+
+	t.Run("dials are delayed by up to the jitter", func(t *testing.T) {
+		t.Parallel()
+
+		dialed := false
+		dial := newClientHook(opts).DialHook(func(context.Context, string, string) (net.Conn, error) {
+			dialed = true
+			return nil, nil
+		})
+
+		start := time.Now()
+		_, err := dial(context.Background(), "tcp", "127.0.0.1:6379")
+
+		assert.NoError(t, err)
+		assert.True(t, dialed)
+		assert.Less(t, time.Since(start), 50*time.Millisecond+25*time.Millisecond)
+	})
+
+	t.Run("dials are aborted when the context is done", func(t *testing.T) {
+		t.Parallel()
+
+		dialed := false
+		dial := newClientHook(opts).DialHook(func(context.Context, string, string) (net.Conn, error) {
+			dialed = true
+			return nil, nil
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := dial(ctx, "tcp", "127.0.0.1:6379")
+
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.False(t, dialed)
+	})
+}
+
+func TestClientHookDialJitterCluster(t *testing.T) {
+	t.Parallel()
+
+	rs1, rs2 := RunT(t), RunT(t)
+	registerClusterSlotsHandler(stubClusterShard{master: rs1}, stubClusterShard{master: rs2})
+
+	opts, err := readOptions(map[string]interface{}{
+		"cluster":           map[string]interface{}{"nodes": []interface{}{"redis://" + rs1.Addr().String(), "redis://" + rs2.Addr().String()}},
+		"reconnectJitterMs": 1,
+	})
+	require.NoError(t, err)
+	opts.Dialer = (&net.Dialer{}).DialContext
+
+	rm := New()
+	client := rm.GetRedisClient(opts)
+	t.Cleanup(func() { _ = client.Close() })
+
+	cluster, ok := client.(*redis.ClusterClient)
+	require.True(t, ok)
+	require.NoError(t, cluster.ForEachMaster(context.Background(), func(ctx context.Context, node *redis.Client) error {
+		return node.Ping(ctx).Err()
+	}))
+
+	// The nodes dial their connections through the client's dial hook, and
+	// its jitter, rather than bypassing it.
+	assert.GreaterOrEqual(t, rm.hooks[optsToHash(opts)].dials.Load(), int64(2))
+}
+
+func TestClientCommandMetrics(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunT(t)
+	rs.RegisterCommandHandler("SET", func(c *Connection, _ []string) {
+		c.WriteOK()
+	})
+	rs.RegisterCommandHandler("GET", func(c *Connection, _ []string) {
+		c.WriteNull()
+	})
+	rs.RegisterCommandHandler("INCR", func(c *Connection, _ []string) {
+		c.WriteError(errors.New("ERR value is not an integer or out of range"))
+	})
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client('redis://%s');
+
+			redis.set("foo", "bar", 0)
+				.then(() => redis.get("missing"))
+				.catch(() => {})
+				.then(() => redis.incr("foo"))
+				.catch(() => {})
+				.then(() => redis.pipeline().set("foo", "bar", 0).incr("foo").exec())
+				.catch(() => {})
+		`, rs.Addr()))
+
+		return err
+	})
+
+	assert.NoError(t, gotScriptErr)
+
+	ops := map[string]int{}
+	durations := map[string]int{}
+	errs := map[string]int{}
+	for _, sample := range drainSamples(ts.samples) {
+		command, _ := sample.Tags.Get("command")
+		address, _ := sample.Tags.Get("address")
+
+		switch sample.Metric.Name {
+		case "redis_ops":
+			assert.Equal(t, rs.Addr().String(), address)
+			ops[command]++
+		case "redis_op_duration":
+			durations[command]++
+		case "redis_errors":
+			errs[command]++
+		}
+	}
+
+	assert.Equal(t, map[string]int{"set": 1, "get": 1, "incr": 1, "pipeline": 1}, ops)
+	assert.Equal(t, ops, durations)
+	assert.Equal(t, map[string]int{"incr": 2}, errs)
+}
+
+func TestClientMetricTags(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunT(t)
+	rs.RegisterCommandHandler("GET", func(c *Connection, _ []string) {
+		c.WriteBulkString("bar")
+	})
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client({ socket: { host: "%s", port: %d }, tags: { service: "sessions" } });
+
+			if (redis.options().tags.service !== "sessions") {
+				throw 'expected the tags option to be reported';
+			}
+
+			const reads = redis.withTags({ operation: "session-read", service: "auth" });
+
+			redis.get("foo")
+				.then(() => reads.get("foo"))
+				.then(() => reads.withTimeout(1000).get("foo"))
+		`, rs.Addr().IP, rs.Addr().Port))
+
+		return err
+	})
+
+	assert.NoError(t, gotScriptErr)
+
+	var tagged []map[string]string
+	for _, sample := range drainSamples(ts.samples) {
+		if sample.Metric.Name != "redis_ops" {
+			continue
+		}
+
+		tags := sample.Tags.Map()
+		assert.Equal(t, "get", tags["command"])
+		assert.Equal(t, rs.Addr().String(), tags["address"])
+		tagged = append(tagged, map[string]string{"service": tags["service"], "operation": tags["operation"]})
+	}
+
+	assert.Equal(t, []map[string]string{
+		{"service": "sessions", "operation": ""},
+		{"service": "auth", "operation": "session-read"},
+		{"service": "auth", "operation": "session-read"},
+	}, tagged)
+}
+
+func TestClientRetries(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunT(t)
+
+	var gets int
+	rs.RegisterCommandHandler("GET", func(c *Connection, args []string) {
+		gets++
+		if args[0] == "loading" && gets < 3 {
+			c.WriteError(errors.New("LOADING Redis is loading the dataset in memory"))
+			return
+		}
+
+		c.WriteBulkString("bar")
+	})
+	rs.RegisterCommandHandler("INCR", func(c *Connection, _ []string) {
+		c.WriteError(errors.New("READONLY You can't write against a read only replica."))
+	})
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client({
+				socket: {
+					host: '%s',
+					port: %d,
+				},
+				maxRetries: 1,
+				minRetryBackoff: 1,
+				maxRetryBackoff: 2,
+			});
+
+			redis.get("loading")
+				.then(
+					res => { throw 'expected get to fail once retries are exhausted' },
+					err => { if (!err.error().startsWith('LOADING')) { throw 'unexpected error: ' + err.error() } }
+				)
+				.then(() => redis.get("loading"))
+				.then(res => { if (res !== "bar") { throw 'unexpected value for get result: ' + res } })
+				.then(() => new Client({ socket: { host: '%s', port: %d }, maxRetries: -1 }).incr("foo"))
+				.then(
+					res => { throw 'expected incr to fail' },
+					err => { if (!err.error().startsWith('READONLY')) { throw 'unexpected error: ' + err.error() } }
+				)
+		`, rs.Addr().IP.String(), rs.Addr().Port, rs.Addr().IP.String(), rs.Addr().Port))
+
+		return err
+	})
+
+	assert.NoError(t, gotScriptErr)
+	assert.Equal(t, 3, gets)
+
+	var retries int
+	for _, sample := range drainSamples(ts.samples) {
+		if sample.Metric.Name == "redis_retries" {
+			command, _ := sample.Tags.Get("command")
+			assert.Equal(t, "get", command)
+			retries += int(sample.Value)
+		}
+	}
+	assert.Equal(t, 1, retries)
+
+	var incrs int
+	for _, cmd := range rs.GotCommands() {
+		if cmd[0] == "INCR" {
+			incrs++
+		}
+	}
+	assert.Equal(t, 1, incrs)
+}
+
+func TestRetryBackoff(t *testing.T) {
+	t.Parallel()
+
+	hook := newClientHook(&universalOptions{UniversalOptions: &redis.UniversalOptions{}})
+	assert.Equal(t, defaultMaxRetries, hook.maxRetries)
+	for attempt := 1; attempt < 100; attempt++ {
+		backoff := hook.retryBackoff(attempt)
+		assert.GreaterOrEqual(t, backoff, defaultMinRetryBackoff)
+		assert.LessOrEqual(t, backoff, defaultMaxRetryBackoff)
+	}
+
+	hook = newClientHook(&universalOptions{UniversalOptions: &redis.UniversalOptions{
+		MaxRetries:      -1,
+		MinRetryBackoff: -1,
+	}})
+	assert.Equal(t, 0, hook.maxRetries)
+	assert.Equal(t, time.Duration(0), hook.retryBackoff(1))
+}
+
+// drainSamples returns the samples buffered in the provided channel,
+// without blocking.
+func drainSamples(samples chan metrics.SampleContainer) []metrics.Sample {
+	var drained []metrics.Sample
+	for {
+		select {
+		case container := <-samples:
+			drained = append(drained, container.GetSamples()...)
+		default:
+			return drained
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package redis
+
+import (
+	"errors"
+
+	"github.com/grafana/sobek"
+	"github.com/redis/go-redis/v9"
+)
+
+// Pfadd adds the provided members to the HyperLogLog stored at `key`,
+// creating it if it does not exist.
+//
+// The promise resolves with 1 if the HyperLogLog's estimated cardinality
+// changed, and 0 otherwise. Members can be binary: ArrayBuffer or
+// Uint8Array. Calls exceeding the commandChunkSize option are split in
+// pipelined chunks.
+func (c *Client) Pfadd(key string, members ...interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	memberArgs, err := c.binaryArgs(1, members...)
+	if err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		ctx := c.context()
+		n, err := sumChunks(ctx, c, memberArgs, func(cmd redis.Cmdable, chunk []interface{}) *redis.IntCmd {
+			return cmd.PFAdd(ctx, key, chunk...)
+		})
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		// Each chunk replies with 1 if it altered the HyperLogLog.
+		if n > 0 {
+			n = 1
+		}
+
+		resolve(n)
+	}()
+
+	return promise
+}
+
+// Pfcount returns the approximate cardinality of the HyperLogLog stored at
+// `key`, or of the union of the HyperLogLogs stored at the provided keys.
+//
+// The promise resolves with the approximate cardinality, or 0 if none of
+// the keys exist.
+func (c *Client) Pfcount(keys ...string) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	if len(keys) == 0 {
+		reject(errors.New("at least one key must be provided to pfcount"))
+		return promise
+	}
+
+	go func() {
+		count, err := c.redisClient.PFCount(c.context(), keys...).Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(count)
+	}()
+
+	return promise
+}
+
+// Pfmerge merges the HyperLogLogs stored at the `sources` keys into the
+// HyperLogLog stored at `destination`, creating it if it does not exist.
+//
+// The promise resolves with "OK".
+func (c *Client) Pfmerge(destination string, sources ...string) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		status, err := c.redisClient.PFMerge(c.context(), destination, sources...).Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(status)
+	}()
+
+	return promise
+}
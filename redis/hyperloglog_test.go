@@ -0,0 +1,67 @@
+package redis
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientHyperLogLog(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunT(t)
+	rs.RegisterCommandHandler("PFADD", func(c *Connection, args []string) {
+		if args[0] == "unchanged" {
+			c.WriteInteger(0)
+			return
+		}
+
+		c.WriteInteger(1)
+	})
+	rs.RegisterCommandHandler("PFCOUNT", func(c *Connection, args []string) {
+		c.WriteInteger(3 * len(args))
+	})
+	rs.RegisterCommandHandler("PFMERGE", func(c *Connection, _ []string) {
+		c.WriteOK()
+	})
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client({
+				socket: {
+					host: '%s',
+					port: %d,
+				},
+				commandChunkSize: 2,
+			});
+
+			redis.pfadd("visitors", "alice", "bob", "carol")
+				.then(res => { if (res !== 1) { throw 'unexpected value for pfadd result: ' + res } })
+				.then(() => redis.pfadd("unchanged", "alice"))
+				.then(res => { if (res !== 0) { throw 'unexpected value for pfadd result: ' + res } })
+				.then(() => redis.pfcount("visitors", "unchanged"))
+				.then(res => { if (res !== 6) { throw 'unexpected value for pfcount result: ' + res } })
+				.then(() => redis.pfmerge("all", "visitors", "unchanged"))
+				.then(res => { if (res !== "OK") { throw 'unexpected value for pfmerge result: ' + res } })
+				.then(() => redis.pfcount())
+				.then(
+					res => { throw 'expected pfcount to fail without keys' },
+					err => { if (!err.error().startsWith('at least one key')) { throw 'unexpected error: ' + err.error() } }
+				)
+		`, rs.Addr().IP.String(), rs.Addr().Port))
+
+		return err
+	})
+
+	assert.NoError(t, gotScriptErr)
+	assert.Equal(t, [][]string{
+		{"HELLO", "2"},
+		{"PFADD", "visitors", "alice", "bob"},
+		{"PFADD", "visitors", "carol"},
+		{"PFADD", "unchanged", "alice"},
+		{"PFCOUNT", "visitors", "unchanged"},
+		{"PFMERGE", "all", "visitors", "unchanged"},
+	}, rs.GotCommands())
+}
@@ -0,0 +1,589 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+
+	"github.com/grafana/sobek"
+	"github.com/redis/go-redis/v9"
+)
+
+// sizeSampleCount is the number of elements sampled to estimate the size
+// of collections.
+const sizeSampleCount = 32
+
+// scoreSize is the number of bytes accounted for each sorted set score.
+const scoreSize = 8
+
+// Encodings returns the internal encoding Redis uses to store the value of
+// each of the provided keys, as reported by OBJECT ENCODING.
+//
+// The OBJECT ENCODING commands are pipelined, so that auditing the encodings
+// of a large dataset doesn't take a round-trip per key.
+//
+// The promise resolves with an object mapping each key to its encoding,
+// or to null if the key doesn't exist.
+func (c *Client) Encodings(keys ...string) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		ctx := c.context()
+
+		cmds := make(map[string]*redis.StringCmd, len(keys))
+		_, err := c.redisClient.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+			for _, key := range keys {
+				cmds[key] = pipe.ObjectEncoding(ctx, key)
+			}
+			return nil
+		})
+		if err != nil && !errors.Is(err, redis.Nil) {
+			reject(err)
+			return
+		}
+
+		encodings := make(map[string]interface{}, len(cmds))
+		for key, cmd := range cmds {
+			encoding, err := cmd.Result()
+			switch {
+			case errors.Is(err, redis.Nil):
+				encodings[key] = nil
+			case err != nil:
+				reject(err)
+				return
+			default:
+				encodings[key] = encoding
+			}
+		}
+
+		resolve(encodings)
+	}()
+
+	return promise
+}
+
+// EstimateSize approximates the number of bytes taken by the value of
+// `key`, using type-specific commands rather than MEMORY USAGE, which may
+// be disabled, or slow, on some servers.
+//
+// The size of strings is their length. The size of collections is
+// extrapolated from a sample of their elements: the average size of the
+// sampled elements is multiplied by the number of elements. Only the
+// payload is accounted for, not the overhead of Redis' internal encodings,
+// so the estimate is a rough sizing signal, and not a measure of the
+// server's memory usage.
+//
+// The promise resolves with the estimated `bytes`, and the `method` used
+// to estimate them, or with null if the key doesn't exist.
+func (c *Client) EstimateSize(key string) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		ctx := c.context()
+
+		typ, err := c.redisClient.Type(ctx, key).Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		if typ == "none" {
+			resolve(nil)
+			return
+		}
+
+		bytes, method, err := c.estimateSize(ctx, key, typ)
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(map[string]interface{}{
+			"bytes":  bytes,
+			"method": method,
+		})
+	}()
+
+	return promise
+}
+
+// estimateSize approximates the number of bytes taken by the value of
+// `key`, holding a value of type `typ`. It returns the estimate, and the
+// name of the method used.
+func (c *Client) estimateSize(ctx context.Context, key, typ string) (int64, string, error) {
+	var (
+		count   *redis.IntCmd
+		samples []int64
+	)
+
+	switch typ {
+	case "string":
+		length, err := c.redisClient.StrLen(ctx, key).Result()
+		return length, "strlen", err
+	case "hash":
+		var sample *redis.KeyValueSliceCmd
+		_, err := c.redisClient.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+			count = pipe.HLen(ctx, key)
+			sample = pipe.HRandFieldWithValues(ctx, key, sizeSampleCount)
+			return nil
+		})
+		if err != nil {
+			return 0, "", err
+		}
+
+		for _, kv := range sample.Val() {
+			samples = append(samples, int64(len(kv.Key)+len(kv.Value)))
+		}
+	case "list":
+		var sample *redis.StringSliceCmd
+		_, err := c.redisClient.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+			count = pipe.LLen(ctx, key)
+			sample = pipe.LRange(ctx, key, 0, sizeSampleCount-1)
+			return nil
+		})
+		if err != nil {
+			return 0, "", err
+		}
+
+		for _, element := range sample.Val() {
+			samples = append(samples, int64(len(element)))
+		}
+	case "set":
+		var sample *redis.StringSliceCmd
+		_, err := c.redisClient.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+			count = pipe.SCard(ctx, key)
+			sample = pipe.SRandMemberN(ctx, key, sizeSampleCount)
+			return nil
+		})
+		if err != nil {
+			return 0, "", err
+		}
+
+		for _, member := range sample.Val() {
+			samples = append(samples, int64(len(member)))
+		}
+	case "zset":
+		var sample *redis.StringSliceCmd
+		_, err := c.redisClient.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+			count = pipe.ZCard(ctx, key)
+			sample = pipe.ZRange(ctx, key, 0, sizeSampleCount-1)
+			return nil
+		})
+		if err != nil {
+			return 0, "", err
+		}
+
+		for _, member := range sample.Val() {
+			samples = append(samples, int64(len(member)+scoreSize))
+		}
+	case "stream":
+		var sample *redis.XMessageSliceCmd
+		_, err := c.redisClient.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+			count = pipe.XLen(ctx, key)
+			sample = pipe.XRangeN(ctx, key, "-", "+", sizeSampleCount)
+			return nil
+		})
+		if err != nil {
+			return 0, "", err
+		}
+
+		for _, msg := range sample.Val() {
+			size := len(msg.ID)
+			for field, value := range msg.Values {
+				size += len(field) + len(fmt.Sprint(value))
+			}
+			samples = append(samples, int64(size))
+		}
+	default:
+		return 0, "", fmt.Errorf("unable to estimate the size of %q; unsupported type %q", key, typ)
+	}
+
+	return extrapolateSize(samples, count.Val()), typ + "_sample", nil
+}
+
+// extrapolateSize returns the total size of `count` elements, based on the
+// sizes of a sample of them.
+func extrapolateSize(samples []int64, count int64) int64 {
+	if len(samples) == 0 {
+		return 0
+	}
+
+	var sum int64
+	for _, size := range samples {
+		sum += size
+	}
+
+	if int64(len(samples)) >= count {
+		return sum
+	}
+
+	return sum * count / int64(len(samples))
+}
+
+// ObjectEncoding returns the internal encoding Redis uses to store the value
+// of `key`, such as "listpack" or "hashtable", so that encoding transitions
+// can be asserted on as the value grows.
+//
+// The promise resolves with the encoding, or null if the key doesn't exist.
+func (c *Client) ObjectEncoding(key string) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		encoding, err := c.redisClient.ObjectEncoding(c.context(), key).Result()
+		switch {
+		case errors.Is(err, redis.Nil):
+			resolve(nil)
+		case err != nil:
+			reject(err)
+		default:
+			resolve(encoding)
+		}
+	}()
+
+	return promise
+}
+
+// ObjectFreq returns the logarithmic access frequency counter of `key`, as
+// reported by OBJECT FREQ. It requires the server's maxmemory-policy to be
+// one of the LFU policies.
+//
+// The promise resolves with the counter, or null if the key doesn't exist.
+func (c *Client) ObjectFreq(key string) *sobek.Promise {
+	return c.objectInt("freq", key)
+}
+
+// ObjectIdletime returns the number of seconds elapsed since `key` was
+// last accessed, as reported by OBJECT IDLETIME. It is unavailable with the
+// LFU maxmemory policies.
+//
+// The promise resolves with the idle time, or null if the key doesn't
+// exist.
+func (c *Client) ObjectIdletime(key string) *sobek.Promise {
+	return c.objectInt("idletime", key)
+}
+
+// objectInt sends the OBJECT `subcommand` for `key`, whose reply is an
+// integer.
+func (c *Client) objectInt(subcommand, key string) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		ctx := c.context()
+		cmd := redis.NewIntCmd(ctx, "object", subcommand, key)
+		_ = c.redisClient.Process(ctx, cmd)
+
+		value, err := cmd.Result()
+		switch {
+		case errors.Is(err, redis.Nil):
+			resolve(nil)
+		case err != nil:
+			reject(err)
+		default:
+			resolve(value)
+		}
+	}()
+
+	return promise
+}
+
+// memoryUsageOptions holds the options of the Client's memoryUsage method.
+type memoryUsageOptions struct {
+	// Samples is the number of elements of collections sampled to
+	// estimate their size. Zero samples all of them.
+	Samples *int `json:"samples,omitempty"`
+}
+
+// MemoryUsage returns the number of bytes the value of `key`, and the key
+// itself, take in the server's memory, as reported by MEMORY USAGE.
+//
+// The promise resolves with the number of bytes, or null if the key doesn't
+// exist.
+func (c *Client) MemoryUsage(key string, options map[string]interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	var opts memoryUsageOptions
+	if err := decodeOptions(options, &opts); err != nil {
+		reject(fmt.Errorf("invalid memoryUsage options; reason: %w", err))
+		return promise
+	}
+
+	var samples []int
+	if opts.Samples != nil {
+		if *opts.Samples < 0 {
+			reject(fmt.Errorf("invalid samples option: %d; expected a positive number", *opts.Samples))
+			return promise
+		}
+		samples = append(samples, *opts.Samples)
+	}
+
+	go func() {
+		bytes, err := c.redisClient.MemoryUsage(c.context(), key, samples...).Result()
+		switch {
+		case errors.Is(err, redis.Nil):
+			resolve(nil)
+		case err != nil:
+			reject(err)
+		default:
+			resolve(bytes)
+		}
+	}()
+
+	return promise
+}
+
+// defaultProfileSampleSize is the default number of keys memoryProfile
+// measures.
+const defaultProfileSampleSize = 1000
+
+// memoryProfileOptions holds the options of the Client's memoryProfile
+// method.
+type memoryProfileOptions struct {
+	// SampleSize is the number of keys measured, picked at random among
+	// those matching the pattern. It defaults to defaultProfileSampleSize.
+	SampleSize *int `json:"sampleSize,omitempty"`
+
+	// Count is the number of keys each SCAN call is hinted to return.
+	Count int64 `json:"count,omitempty"`
+}
+
+// MemoryProfile reports the memory taken by the keys matching the
+// glob-style `pattern`, such as to size a dataset generated by a test.
+//
+// The keyspace is scanned with SCAN, and a uniform sample of sampleSize
+// matching keys is measured with MEMORY USAGE, and TYPE, which are
+// pipelined. The promise resolves with the number of matching `keys`, the
+// number of `sampled` keys, their total `bytes`, and `avgBytes`, the
+// `estimatedBytes` of all the matching keys, extrapolated from the sample,
+// and the `sampled`, `bytes`, and `avgBytes` of each type of value of the
+// sample, as `types`.
+func (c *Client) MemoryProfile(pattern string, options map[string]interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	var opts memoryProfileOptions
+	if err := decodeOptions(options, &opts); err != nil {
+		reject(fmt.Errorf("invalid memoryProfile options; reason: %w", err))
+		return promise
+	}
+
+	sampleSize := defaultProfileSampleSize
+	if opts.SampleSize != nil {
+		if *opts.SampleSize < 1 {
+			reject(fmt.Errorf("invalid sampleSize option: %d; expected a strictly positive number", *opts.SampleSize))
+			return promise
+		}
+		sampleSize = *opts.SampleSize
+	}
+
+	go func() {
+		ctx := c.context()
+
+		count, sample, err := c.sampleKeys(ctx, scanOptions{Match: pattern, Count: opts.Count}, sampleSize)
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		types := make([]*redis.StatusCmd, len(sample))
+		usages := make([]*redis.IntCmd, len(sample))
+		// The commands' errors are checked individually below.
+		_, _ = c.redisClient.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+			for idx, key := range sample {
+				types[idx] = pipe.Type(ctx, key)
+				usages[idx] = pipe.MemoryUsage(ctx, key)
+			}
+			return nil
+		})
+
+		profile := newMemoryProfile()
+		for idx := range sample {
+			typ, err := types[idx].Result()
+			if err != nil {
+				reject(err)
+				return
+			}
+
+			// Keys expiring, or deleted, once sampled, are skipped.
+			bytes, err := usages[idx].Result()
+			if errors.Is(err, redis.Nil) || typ == "none" {
+				continue
+			}
+			if err != nil {
+				reject(err)
+				return
+			}
+
+			profile.add(typ, bytes)
+		}
+
+		resolve(profile.report(count))
+	}()
+
+	return promise
+}
+
+// sampleKeys scans the keys matching the provided options, and returns
+// their number, and a uniform sample of up to `size` of them, picked with
+// reservoir sampling, so that the keys don't need to be kept in memory.
+func (c *Client) sampleKeys(ctx context.Context, opts scanOptions, size int) (int64, []string, error) {
+	var (
+		mu     sync.Mutex
+		seen   = make(map[string]struct{})
+		sample = make([]string, 0, size)
+		count  int64
+	)
+
+	err := c.scanKeys(ctx, opts, func(key string) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		// SCAN may return the same key several times.
+		if _, ok := seen[key]; ok {
+			return
+		}
+		seen[key] = struct{}{}
+		count++
+
+		if len(sample) < size {
+			sample = append(sample, key)
+			return
+		}
+		if idx := rand.Int63n(count); idx < int64(size) { //nolint:gosec
+			sample[idx] = key
+		}
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return count, sample, nil
+}
+
+// memoryStats aggregates the memory usage of a set of keys.
+type memoryStats struct {
+	keys  int64
+	bytes int64
+}
+
+// report returns the stats as reported by memoryProfile.
+func (s memoryStats) report() map[string]interface{} {
+	var avg float64
+	if s.keys > 0 {
+		avg = float64(s.bytes) / float64(s.keys)
+	}
+
+	return map[string]interface{}{
+		"sampled":  s.keys,
+		"bytes":    s.bytes,
+		"avgBytes": avg,
+	}
+}
+
+// memoryProfile aggregates the memory usage of the keys sampled by the
+// Client's memoryProfile method, as a whole, and by type.
+type memoryProfile struct {
+	total memoryStats
+	types map[string]*memoryStats
+}
+
+// newMemoryProfile returns an empty memoryProfile.
+func newMemoryProfile() *memoryProfile {
+	return &memoryProfile{types: make(map[string]*memoryStats)}
+}
+
+// add accounts for a key holding a value of type `typ`, taking `bytes`.
+func (p *memoryProfile) add(typ string, bytes int64) {
+	p.total.keys++
+	p.total.bytes += bytes
+
+	stats, ok := p.types[typ]
+	if !ok {
+		stats = &memoryStats{}
+		p.types[typ] = stats
+	}
+	stats.keys++
+	stats.bytes += bytes
+}
+
+// report returns the profile as resolved by memoryProfile, `count` being
+// the number of keys matching its pattern.
+func (p *memoryProfile) report(count int64) map[string]interface{} {
+	report := p.total.report()
+	report["keys"] = count
+
+	var estimated int64
+	if p.total.keys > 0 {
+		estimated = int64(float64(p.total.bytes) / float64(p.total.keys) * float64(count))
+	}
+	report["estimatedBytes"] = estimated
+
+	types := make(map[string]interface{}, len(p.types))
+	for typ, stats := range p.types {
+		types[typ] = stats.report()
+	}
+	report["types"] = types
+
+	return report
+}
+
+// DebugSleep makes the server sleep for `seconds`, which can be fractional,
+// blocking all of its clients, so that tests can simulate a stalled server.
+// The DEBUG command is disabled by default since Redis 7.
+//
+// The promise resolves with "OK" once the server wakes up.
+func (c *Client) DebugSleep(seconds float64) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	if seconds < 0 {
+		reject(fmt.Errorf("invalid sleep duration: %v; expected a positive number", seconds))
+		return promise
+	}
+
+	go func() {
+		status, err := c.redisClient.Do(c.context(), "debug", "sleep", seconds).Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(status)
+	}()
+
+	return promise
+}
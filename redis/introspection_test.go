@@ -0,0 +1,257 @@
+package redis
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientEncodings(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunT(t)
+	rs.RegisterCommandHandler("OBJECT", func(c *Connection, args []string) {
+		switch args[1] {
+		case "small":
+			c.WriteBulkString("listpack")
+		case "counter":
+			c.WriteBulkString("int")
+		default:
+			c.WriteNull()
+		}
+	})
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client('redis://%s');
+
+			redis.encodings("small", "counter", "missing")
+				.then(res => {
+					if (res.small !== "listpack" || res.counter !== "int" || res.missing !== null) {
+						throw 'unexpected encodings: ' + JSON.stringify(res)
+					}
+				})
+		`, rs.Addr()))
+
+		return err
+	})
+
+	assert.NoError(t, gotScriptErr)
+	assert.Equal(t, [][]string{
+		{"HELLO", "2"},
+		{"OBJECT", "encoding", "small"},
+		{"OBJECT", "encoding", "counter"},
+		{"OBJECT", "encoding", "missing"},
+	}, rs.GotCommands())
+}
+
+func TestClientEstimateSize(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunT(t)
+	rs.RegisterCommandHandler("TYPE", func(c *Connection, args []string) {
+		switch args[0] {
+		case "greeting":
+			c.WriteSimpleString("string")
+		case "user":
+			c.WriteSimpleString("hash")
+		case "document":
+			c.WriteSimpleString("ReJSON-RL")
+		default:
+			c.WriteSimpleString("none")
+		}
+	})
+	rs.RegisterCommandHandler("STRLEN", func(c *Connection, _ []string) {
+		c.WriteInteger(11)
+	})
+	rs.RegisterCommandHandler("HLEN", func(c *Connection, _ []string) {
+		c.WriteInteger(100)
+	})
+	rs.RegisterCommandHandler("HRANDFIELD", func(c *Connection, _ []string) {
+		c.WriteArray("f1", "value1", "f2", "value22")
+	})
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client('redis://%s');
+
+			redis.estimateSize("greeting")
+				.then(res => {
+					if (res.bytes !== 11 || res.method !== "strlen") {
+						throw 'unexpected string estimate: ' + JSON.stringify(res)
+					}
+				})
+				.then(() => redis.estimateSize("user"))
+				.then(res => {
+					// The 2 sampled fields take 8 and 9 bytes.
+					if (res.bytes !== 850 || res.method !== "hash_sample") {
+						throw 'unexpected hash estimate: ' + JSON.stringify(res)
+					}
+				})
+				.then(() => redis.estimateSize("missing"))
+				.then(res => {
+					if (res !== null) {
+						throw 'unexpected missing key estimate: ' + JSON.stringify(res)
+					}
+				})
+				.then(() => redis.estimateSize("document"))
+				.then(
+					res => { throw 'expected estimateSize to fail' },
+					err => {
+						if (!err.error().includes('unsupported type "ReJSON-RL"')) {
+							throw 'unexpected error: ' + err.error()
+						}
+					}
+				)
+		`, rs.Addr()))
+
+		return err
+	})
+
+	assert.NoError(t, gotScriptErr)
+	assert.Contains(t, rs.GotCommands(), []string{"HRANDFIELD", "user", "32", "withvalues"})
+}
+
+func TestClientObjectIntrospection(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunT(t)
+	rs.RegisterCommandHandler("OBJECT", func(c *Connection, args []string) {
+		if args[1] == "missing" {
+			c.WriteNull()
+			return
+		}
+
+		switch args[0] {
+		case "encoding":
+			c.WriteBulkString("hashtable")
+		case "freq":
+			c.WriteInteger(5)
+		default:
+			c.WriteInteger(42)
+		}
+	})
+	rs.RegisterCommandHandler("MEMORY", func(c *Connection, args []string) {
+		if args[1] == "missing" {
+			c.WriteNull()
+			return
+		}
+
+		c.WriteInteger(72)
+	})
+	rs.RegisterCommandHandler("DEBUG", func(c *Connection, _ []string) {
+		c.WriteOK()
+	})
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client('redis://%s');
+
+			redis.objectEncoding("user")
+				.then(res => { if (res !== "hashtable") { throw 'unexpected value for objectEncoding result: ' + res } })
+				.then(() => redis.objectEncoding("missing"))
+				.then(res => { if (res !== null) { throw 'unexpected value for objectEncoding result: ' + res } })
+				.then(() => redis.objectFreq("user"))
+				.then(res => { if (res !== 5) { throw 'unexpected value for objectFreq result: ' + res } })
+				.then(() => redis.objectIdletime("missing"))
+				.then(res => { if (res !== null) { throw 'unexpected value for objectIdletime result: ' + res } })
+				.then(() => redis.objectIdletime("user"))
+				.then(res => { if (res !== 42) { throw 'unexpected value for objectIdletime result: ' + res } })
+				.then(() => redis.memoryUsage("user", { samples: 0 }))
+				.then(res => { if (res !== 72) { throw 'unexpected value for memoryUsage result: ' + res } })
+				.then(() => redis.memoryUsage("missing"))
+				.then(res => { if (res !== null) { throw 'unexpected value for memoryUsage result: ' + res } })
+				.then(() => redis.debugSleep(0.5))
+				.then(res => { if (res !== "OK") { throw 'unexpected value for debugSleep result: ' + res } })
+				.then(() => redis.debugSleep(-1))
+				.then(
+					res => { throw 'expected debugSleep to fail' },
+					err => { if (!err.error().startsWith('invalid sleep duration')) { throw 'unexpected error: ' + err.error() } }
+				)
+		`, rs.Addr()))
+
+		return err
+	})
+
+	assert.NoError(t, gotScriptErr)
+	assert.Equal(t, [][]string{
+		{"HELLO", "2"},
+		{"OBJECT", "encoding", "user"},
+		{"OBJECT", "encoding", "missing"},
+		{"OBJECT", "freq", "user"},
+		{"OBJECT", "idletime", "missing"},
+		{"OBJECT", "idletime", "user"},
+		{"MEMORY", "usage", "user", "SAMPLES", "0"},
+		{"MEMORY", "usage", "missing"},
+		{"DEBUG", "sleep", "0.5"},
+	}, rs.GotCommands())
+}
+
+func TestClientMemoryProfile(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunT(t)
+	rs.RegisterCommandHandler("SCAN", func(c *Connection, args []string) {
+		if args[0] == "0" {
+			c.WriteValue([]interface{}{"5", []string{"k1", "k2"}})
+			return
+		}
+
+		// SCAN may return the same key several times.
+		c.WriteValue([]interface{}{"0", []string{"k3", "k4", "k1"}})
+	})
+	types := map[string]string{"k1": "string", "k2": "string", "k3": "hash", "k4": "none"}
+	rs.RegisterCommandHandler("TYPE", func(c *Connection, args []string) {
+		c.WriteSimpleString(types[args[0]])
+	})
+	usages := map[string]int{"k1": 100, "k2": 200, "k3": 300}
+	rs.RegisterCommandHandler("MEMORY", func(c *Connection, args []string) {
+		usage, ok := usages[args[1]]
+		if !ok {
+			c.WriteNull()
+			return
+		}
+
+		c.WriteInteger(usage)
+	})
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client('redis://%s');
+
+			redis.memoryProfile("k*")
+				.then(res => {
+					if (res.keys !== 4 || res.sampled !== 3 || res.bytes !== 600 || res.avgBytes !== 200 || res.estimatedBytes !== 800) {
+						throw 'unexpected value for memoryProfile result: ' + JSON.stringify(res)
+					}
+
+					const strings = res.types.string, hashes = res.types.hash;
+					if (Object.keys(res.types).length !== 2 || strings.sampled !== 2 || strings.bytes !== 300 || strings.avgBytes !== 150 || hashes.avgBytes !== 300) {
+						throw 'unexpected types for memoryProfile result: ' + JSON.stringify(res.types)
+					}
+				})
+				.then(() => redis.memoryProfile("k*", { sampleSize: 2, count: 100 }))
+				.then(res => {
+					if (res.keys !== 4 || res.sampled < 1 || res.sampled > 2) {
+						throw 'unexpected value for memoryProfile result: ' + JSON.stringify(res)
+					}
+				})
+				.then(() => redis.memoryProfile("k*", { sampleSize: 0 }))
+				.then(
+					res => { throw 'expected memoryProfile to fail' },
+					err => { if (!err.error().startsWith('invalid sampleSize option')) { throw 'unexpected error: ' + err.error() } }
+				)
+		`, rs.Addr()))
+
+		return err
+	})
+
+	assert.NoError(t, gotScriptErr)
+	assert.Contains(t, rs.GotCommands(), []string{"SCAN", "0", "match", "k*", "count", "100"})
+	assert.Contains(t, rs.GotCommands(), []string{"MEMORY", "usage", "k3"})
+}
@@ -0,0 +1,171 @@
+package redis
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/sobek"
+	"github.com/redis/go-redis/v9"
+)
+
+// Keyspace notifications are published, by the server, to two families of
+// channels: __keyevent@<db>__:<event> channels, whose messages are the keys
+// affected by the event, and __keyspace@<db>__:<key> channels, whose
+// messages are the events affecting the key.
+const (
+	keyeventChannelPrefix = "__keyevent@"
+	keyspaceChannelPrefix = "__keyspace@"
+)
+
+// onKeyEventOptions holds the options of the Client's onKeyEvent method.
+type onKeyEventOptions struct {
+	// Keyspace makes the patterns match keys, through the __keyspace@
+	// channels, rather than events, through the __keyevent@ channels.
+	Keyspace bool `json:"keyspace,omitempty"`
+
+	// NotifyKeyspaceEvents, if set, is the value the server's
+	// notify-keyspace-events configuration parameter is set to, with
+	// CONFIG SET, before subscribing.
+	NotifyKeyspaceEvents string `json:"notifyKeyspaceEvents,omitempty"`
+}
+
+// OnKeyEvent subscribes to the keyspace notifications of the client's
+// database, and calls `callback` with an object holding the `event`, the
+// `key` it affected, and the `database` of each notification.
+//
+// The glob-style `patterns` match the names of the events, such as
+// "expired" or "evicted", or, with the keyspace option, the keys. The
+// server only publishes the notifications enabled by its
+// notify-keyspace-events parameter, which the notifyKeyspaceEvents option
+// sets beforehand.
+//
+// Notifications share the client's subscription, and its connection. The
+// promise resolves with the channel patterns subscribed to, once the server
+// confirmed the subscription, so that they can be passed to punsubscribe.
+//
+// Cluster nodes only publish the notifications of their own keys, so
+// OnKeyEvent isn't supported by cluster clients.
+func (c *Client) OnKeyEvent(patterns interface{}, callback sobek.Callable, options map[string]interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	if c.redisOptions.isCluster() {
+		reject(errors.New("onKeyEvent is not supported by cluster clients"))
+		return promise
+	}
+
+	names, err := channelsArg(patterns)
+	if err != nil {
+		reject(err)
+		return promise
+	}
+
+	if callback == nil {
+		reject(errors.New("a callback function must be provided to onKeyEvent"))
+		return promise
+	}
+
+	var opts onKeyEventOptions
+	if err := decodeOptions(options, &opts); err != nil {
+		reject(fmt.Errorf("invalid onKeyEvent options; reason: %w", err))
+		return promise
+	}
+
+	prefix := keyeventChannelPrefix
+	if opts.Keyspace {
+		prefix = keyspaceChannelPrefix
+	}
+
+	channels := make([]string, len(names))
+	for idx, name := range names {
+		channels[idx] = prefix + strconv.Itoa(c.redisOptions.DB) + "__:" + name
+	}
+
+	sub := c.subscription()
+	confirmed := sub.register(true, channels, sub.keyEventHandler(c, callback))
+
+	go func() {
+		if opts.NotifyKeyspaceEvents != "" {
+			err := c.redisClient.ConfigSet(c.context(), "notify-keyspace-events", opts.NotifyKeyspaceEvents).Err()
+			if err != nil {
+				if remaining := sub.unregister(true, channels); remaining == 0 {
+					c.closeSubscription(sub)
+				}
+
+				reject(fmt.Errorf("unable to enable keyspace notifications; reason: %w", err))
+				return
+			}
+		}
+
+		if err := c.awaitSubscription(sub, true, channels, confirmed); err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(channels)
+	}()
+
+	return promise
+}
+
+// keyEventHandler returns a messageHandler calling the provided JS function,
+// on the event loop, with an object holding the event, key, and database of
+// each keyspace notification.
+func (s *subscription) keyEventHandler(c *Client, callback sobek.Callable) messageHandler {
+	rt := c.vu.Runtime()
+
+	return func(msg *redis.Message) {
+		event, err := parseKeyEvent(msg)
+
+		s.queue.Queue(func() error {
+			// The pattern may have been unsubscribed from in the meantime.
+			if s.handler(messageTarget(msg)) == nil {
+				return nil
+			}
+
+			if err != nil {
+				return err
+			}
+
+			_, err := callback(sobek.Undefined(), rt.ToValue(event))
+			return err
+		})
+	}
+}
+
+// parseKeyEvent returns the event, key, and database of the provided
+// keyspace notification.
+func parseKeyEvent(msg *redis.Message) (map[string]interface{}, error) {
+	keyspace := strings.HasPrefix(msg.Channel, keyspaceChannelPrefix)
+	if !keyspace && !strings.HasPrefix(msg.Channel, keyeventChannelPrefix) {
+		return nil, fmt.Errorf("unexpected keyspace notification channel %q", msg.Channel)
+	}
+
+	// Both prefixes have the same length.
+	db, name, ok := strings.Cut(msg.Channel[len(keyeventChannelPrefix):], "__:")
+	if !ok {
+		return nil, fmt.Errorf("unexpected keyspace notification channel %q", msg.Channel)
+	}
+
+	database, err := strconv.Atoi(db)
+	if err != nil {
+		return nil, fmt.Errorf("unexpected keyspace notification channel %q", msg.Channel)
+	}
+
+	event, key := name, msg.Payload
+	if keyspace {
+		event, key = msg.Payload, name
+	}
+
+	return map[string]interface{}{
+		"event":    event,
+		"key":      key,
+		"database": database,
+	}, nil
+}
@@ -0,0 +1,67 @@
+package redis
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientOnKeyEvent(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunT(t)
+	registerPubSubHandlers(rs)
+	rs.RegisterCommandHandler("CONFIG", func(c *Connection, _ []string) {
+		c.WriteOK()
+	})
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client('redis://%s');
+			const received = [];
+
+			const handler = (event) => {
+				received.push(event.database + ":" + event.event + ":" + event.key);
+				if (received.length === 2) {
+					redis.punsubscribe().then(() => {
+						if (received.join(",") !== "0:expired:session:1,0:evicted:cache:2") {
+							throw 'unexpected received events: ' + received
+						}
+					})
+				}
+			};
+
+			redis.onKeyEvent(["expired"], handler, { notifyKeyspaceEvents: "Ex" })
+				.then(channels => {
+					if (channels.length !== 1 || channels[0] !== "__keyevent@0__:expired") {
+						throw 'unexpected value for onKeyEvent result: ' + channels
+					}
+				})
+				.then(() => redis.onKeyEvent("cache:*", handler, { keyspace: true }))
+				.then(() => redis.publish("__keyevent@0__:expired", "session:1"))
+				.then(() => redis.publish("__keyevent@0__:del", "ignored"))
+				.then(() => redis.publish("__keyspace@0__:cache:2", "evicted"))
+		`, rs.Addr()))
+
+		return err
+	})
+
+	assert.NoError(t, gotScriptErr)
+	assert.Contains(t, rs.GotCommands(), []string{"CONFIG", "set", "notify-keyspace-events", "Ex"})
+	assert.Contains(t, rs.GotCommands(), []string{"PSUBSCRIBE", "__keyspace@0__:cache:*"})
+}
+
+func TestParseKeyEvent(t *testing.T) {
+	t.Parallel()
+
+	event, err := parseKeyEvent(&redis.Message{Channel: "__keyspace@12__:user:{1}", Payload: "expire"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"event": "expire", "key": "user:{1}", "database": 12}, event)
+
+	_, err = parseKeyEvent(&redis.Message{Channel: "__keyevent@x__:expired", Payload: "key"})
+	assert.ErrorContains(t, err, "unexpected keyspace notification channel")
+}
@@ -0,0 +1,299 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// keyPrefixHook is the go-redis hook implementing the keyPrefix option: it
+// prefixes the keys of the commands sent on behalf of a Client with the
+// option set, and removes the prefix from the key names SCAN, KEYS, and
+// RANDOMKEY reply with, so that scripts only see their own namespace.
+//
+// The hook is installed on the go-redis client, so that cluster clients
+// route commands by their prefixed keys, and on the nodes of cluster
+// clients, for the commands sent to a node directly, such as SCAN. Keys
+// are only prefixed once, by the first hook processing the command.
+type keyPrefixHook struct{}
+
+// keysPrefixedContextKey marks the contexts of commands whose keys were
+// prefixed already.
+type keysPrefixedContextKey struct{}
+
+var _ redis.Hook = keyPrefixHook{}
+
+// DialHook implements the redis.Hook interface.
+func (keyPrefixHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+// ProcessHook implements the redis.Hook interface.
+func (h keyPrefixHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		prefix, ok := keyPrefix(ctx)
+		if !ok {
+			return next(ctx, cmd)
+		}
+
+		prefixKeys(prefix, cmd)
+		err := next(context.WithValue(ctx, keysPrefixedContextKey{}, true), cmd)
+		trimKeyPrefix(prefix, cmd)
+
+		return err
+	}
+}
+
+// ProcessPipelineHook implements the redis.Hook interface.
+func (h keyPrefixHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		prefix, ok := keyPrefix(ctx)
+		if !ok {
+			return next(ctx, cmds)
+		}
+
+		for _, cmd := range cmds {
+			prefixKeys(prefix, cmd)
+		}
+
+		err := next(context.WithValue(ctx, keysPrefixedContextKey{}, true), cmds)
+		for _, cmd := range cmds {
+			trimKeyPrefix(prefix, cmd)
+		}
+
+		return err
+	}
+}
+
+// keyPrefix returns the keyPrefix option of the Client carried by the
+// context, unless the keys were prefixed already.
+func keyPrefix(ctx context.Context) (string, bool) {
+	if prefixed, _ := ctx.Value(keysPrefixedContextKey{}).(bool); prefixed {
+		return "", false
+	}
+
+	c, ok := clientFromContext(ctx)
+	if !ok || c.redisOptions == nil || c.redisOptions.KeyPrefix == "" {
+		return "", false
+	}
+
+	return c.redisOptions.KeyPrefix, true
+}
+
+// keySpec locates the keys of a command's arguments: from the argument at
+// index `first`, to the one at index `last`, every `step` arguments. A
+// negative `last` counts from the end of the arguments, -1 being the last
+// one.
+type keySpec struct {
+	first, last, step int
+}
+
+// keySpecs holds the key layout of the commands not operating on their
+// first argument only, which is the default.
+var keySpecs = map[string]keySpec{
+	// Commands whose arguments are all keys.
+	"del": {1, -1, 1}, "exists": {1, -1, 1}, "unlink": {1, -1, 1}, "touch": {1, -1, 1}, "mget": {1, -1, 1},
+	"watch": {1, -1, 1}, "sinter": {1, -1, 1}, "sunion": {1, -1, 1}, "sdiff": {1, -1, 1},
+	"sinterstore": {1, -1, 1}, "sunionstore": {1, -1, 1}, "sdiffstore": {1, -1, 1},
+	"pfcount": {1, -1, 1}, "pfmerge": {1, -1, 1},
+
+	// Commands operating on two keys.
+	"rename": {1, 2, 1}, "renamenx": {1, 2, 1}, "copy": {1, 2, 1}, "smove": {1, 2, 1}, "rpoplpush": {1, 2, 1},
+	"lmove": {1, 2, 1}, "blmove": {1, 2, 1}, "brpoplpush": {1, 2, 1}, "lcs": {1, 2, 1},
+	"geosearchstore": {1, 2, 1}, "zrangestore": {1, 2, 1},
+
+	// Blocking commands ending with their timeout.
+	"blpop": {1, -2, 1}, "brpop": {1, -2, 1}, "bzpopmin": {1, -2, 1}, "bzpopmax": {1, -2, 1},
+
+	// Commands whose keys are followed by a path.
+	"json.mget": {1, -2, 1},
+
+	// Commands alternating keys and values.
+	"mset": {1, -1, 2}, "msetnx": {1, -1, 2}, "ts.madd": {1, -1, 3}, "json.mset": {1, -1, 3},
+	"ts.createrule": {1, 2, 1},
+
+	// Commands whose keys follow a subcommand, or an operation.
+	"bitop": {2, -1, 1}, "object": {2, 2, 1}, "memory": {2, 2, 1}, "xinfo": {2, 2, 1}, "xgroup": {2, 2, 1},
+}
+
+// numkeysPositions holds the index of the numkeys argument of the commands
+// whose keys follow it.
+var numkeysPositions = map[string]int{
+	"eval": 2, "evalsha": 2, "eval_ro": 2, "evalsha_ro": 2, "fcall": 2, "fcall_ro": 2,
+	"zunion": 1, "zinter": 1, "zdiff": 1, "sintercard": 1, "zintercard": 1, "lmpop": 1, "zmpop": 1,
+	"zunionstore": 2, "zinterstore": 2, "zdiffstore": 2, "blmpop": 2, "bzmpop": 2,
+}
+
+// unprefixedCommands lists the commands whose arguments aren't keys, on top
+// of the keylessCommands, or whose keys are prefixed differently.
+var unprefixedCommands = map[string]struct{}{
+	"acl": {}, "lolwut": {}, "module": {}, "monitor": {}, "pubsub": {}, "punsubscribe": {}, "replicaof": {},
+	"shutdown": {}, "slaveof": {}, "ssubscribe": {}, "sunsubscribe": {}, "unsubscribe": {}, "waitaof": {},
+}
+
+// prefixKeys prefixes the keys of `cmd` with `prefix`, in place.
+//
+// Commands are identified by name, see keyIndexes. The glob-style patterns
+// of KEYS, and of SCAN's MATCH argument, are prefixed too. The index names
+// of RediSearch commands, and the patterns of SORT's BY and GET arguments,
+// aren't.
+func prefixKeys(prefix string, cmd redis.Cmder) {
+	args := cmd.Args()
+	for _, idx := range keyIndexes(cmd) {
+		switch arg := args[idx].(type) {
+		case string:
+			args[idx] = prefix + arg
+		case []byte:
+			args[idx] = append([]byte(prefix), arg...)
+		}
+	}
+}
+
+// keyIndexes returns the indexes of the arguments of `cmd` that are keys,
+// or key patterns, as prefixed by prefixKeys.
+//
+// Commands are identified by name, see keySpecs.
+func keyIndexes(cmd redis.Cmder) []int {
+	var (
+		args    = cmd.Args()
+		name    = strings.ToLower(cmd.Name())
+		indexes []int
+	)
+
+	keyArg := func(idx int) {
+		if idx > 0 && idx < len(args) {
+			indexes = append(indexes, idx)
+		}
+	}
+
+	// keysAfter adds the arguments following the `option` argument.
+	keysAfter := func(option string, count int) {
+		for idx := 1; idx < len(args); idx++ {
+			if s, ok := args[idx].(string); ok && strings.EqualFold(s, option) {
+				for n := 1; n <= count; n++ {
+					keyArg(idx + n)
+				}
+				return
+			}
+		}
+	}
+
+	if spec, ok := keySpecs[name]; ok {
+		last := spec.last
+		if last < 0 {
+			last += len(args)
+		}
+		for idx := spec.first; idx <= last && idx < len(args); idx += spec.step {
+			keyArg(idx)
+		}
+		return indexes
+	}
+
+	if pos, ok := numkeysPositions[name]; ok {
+		// The destination key of the *store commands precedes numkeys.
+		if strings.HasSuffix(name, "store") {
+			keyArg(1)
+		}
+
+		if pos < len(args) {
+			numkeys, _ := strconv.Atoi(argString(args[pos]))
+			for idx := pos + 1; idx <= pos+numkeys; idx++ {
+				keyArg(idx)
+			}
+		}
+		return indexes
+	}
+
+	switch name {
+	case "keys":
+		keyArg(1)
+	case "scan":
+		keysAfter("match", 1)
+	case "xread", "xreadgroup":
+		for idx := 1; idx < len(args); idx++ {
+			if s, ok := args[idx].(string); ok && strings.EqualFold(s, "streams") {
+				streams := (len(args) - idx - 1) / 2
+				for n := 1; n <= streams; n++ {
+					keyArg(idx + n)
+				}
+				break
+			}
+		}
+	case "migrate":
+		// The key argument is left empty when the keys follow KEYS.
+		if len(args) > 3 && argString(args[3]) != "" {
+			keyArg(3)
+		}
+		keysAfter("keys", len(args))
+	case "sort", "sort_ro":
+		keyArg(1)
+		keysAfter("store", 1)
+	case "georadius", "georadiusbymember":
+		keyArg(1)
+		keysAfter("store", 1)
+		keysAfter("storedist", 1)
+	case "cluster":
+		// The key of CLUSTER KEYSLOT is prefixed, so that its slot is the
+		// one the key's commands are routed by.
+		if len(args) > 2 && strings.EqualFold(argString(args[1]), "keyslot") {
+			keyArg(2)
+		}
+	default:
+		if _, ok := keylessCommands[name]; ok {
+			return nil
+		}
+		if _, ok := unprefixedCommands[name]; ok || strings.HasPrefix(name, "ft.") {
+			return nil
+		}
+		keyArg(1)
+	}
+
+	return indexes
+}
+
+// argString returns the string form of a command argument.
+func argString(arg interface{}) string {
+	switch v := arg.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// trimKeyPrefix removes `prefix` from the key names of the replies of SCAN,
+// KEYS, and RANDOMKEY. The keys outside of the prefix's namespace, which
+// SCAN returns when not given a pattern, are left out of SCAN's replies.
+func trimKeyPrefix(prefix string, cmd redis.Cmder) {
+	trim := func(keys []string) []string {
+		trimmed := keys[:0]
+		for _, key := range keys {
+			if strings.HasPrefix(key, prefix) {
+				trimmed = append(trimmed, strings.TrimPrefix(key, prefix))
+			}
+		}
+		return trimmed
+	}
+
+	switch cmd := cmd.(type) {
+	case *redis.ScanCmd:
+		// SSCAN, HSCAN, and ZSCAN reply with members, rather than keys.
+		if strings.EqualFold(cmd.Name(), "scan") {
+			keys, cursor := cmd.Val()
+			cmd.SetVal(trim(keys), cursor)
+		}
+	case *redis.StringSliceCmd:
+		if strings.EqualFold(cmd.Name(), "keys") {
+			cmd.SetVal(trim(cmd.Val()))
+		}
+	case *redis.StringCmd:
+		if strings.EqualFold(cmd.Name(), "randomkey") {
+			cmd.SetVal(strings.TrimPrefix(cmd.Val(), prefix))
+		}
+	}
+}
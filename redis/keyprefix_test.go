@@ -0,0 +1,123 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientKeyPrefix(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunT(t)
+	rs.RegisterCommandHandler("SET", func(c *Connection, _ []string) {
+		c.WriteOK()
+	})
+	rs.RegisterCommandHandler("MGET", func(c *Connection, args []string) {
+		c.WriteArray(args...)
+	})
+	rs.RegisterCommandHandler("JSON.MGET", func(c *Connection, _ []string) {
+		c.WriteArray(`[1]`, `[2]`)
+	})
+	rs.RegisterCommandHandler("DEL", func(c *Connection, args []string) {
+		c.WriteInteger(len(args))
+	})
+	rs.RegisterCommandHandler("EVALSHA", func(c *Connection, args []string) {
+		c.WriteArray(args[2:4]...)
+	})
+	rs.RegisterCommandHandler("SCAN", func(c *Connection, _ []string) {
+		c.WriteValue([]interface{}{"0", []string{"test:a", "test:b", "other:c"}})
+	})
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client({ socket: { host: "%s", port: %d }, keyPrefix: "test:" });
+
+			if (redis.options().keyPrefix !== "test:") {
+				throw 'expected the keyPrefix option to be reported';
+			}
+
+			redis.set("a", "1", 0)
+				.then(() => redis.mget("a", "b"))
+				.then(res => {
+					if (res.join(",") !== "test:a,test:b") { throw 'unexpected mget result: ' + res }
+				})
+				.then(() => redis.jsonMGet(["a", "b"], "$.n"))
+				.then(res => {
+					if (JSON.stringify(res) !== "[[1],[2]]") { throw 'unexpected jsonMGet result: ' + JSON.stringify(res) }
+				})
+				.then(() => redis.del("a", "b"))
+				.then(() => redis.sendCommand("EVALSHA", "abc", 1, "a", "arg"))
+				.then(res => {
+					if (res.join(",") !== "test:a,arg") { throw 'unexpected evalsha result: ' + res }
+				})
+				.then(() => redis.scanAll({ match: "*" }))
+				.then(keys => {
+					if (keys.sort().join(",") !== "a,b") { throw 'unexpected scanAll result: ' + keys }
+				})
+		`, rs.Addr().IP, rs.Addr().Port))
+
+		return err
+	})
+	require.NoError(t, gotScriptErr)
+
+	assert.Equal(t, [][]string{
+		{"HELLO", "2"},
+		{"SET", "test:a", "1"},
+		{"MGET", "test:a", "test:b"},
+		{"JSON.MGET", "test:a", "test:b", "$.n"},
+		{"DEL", "test:a", "test:b"},
+		{"EVALSHA", "abc", "1", "test:a", "arg"},
+		{"SCAN", "0", "match", "test:*"},
+	}, rs.GotCommands())
+}
+
+func TestPrefixKeys(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		args []interface{}
+		want []interface{}
+	}{
+		{[]interface{}{"get", "foo"}, []interface{}{"get", "p:foo"}},
+		{[]interface{}{"ping"}, []interface{}{"ping"}},
+		{[]interface{}{"mset", "a", "1", "b", "2"}, []interface{}{"mset", "p:a", "1", "p:b", "2"}},
+		{[]interface{}{"ts.madd", "a", "*", "1", "b", "*", "2"}, []interface{}{"ts.madd", "p:a", "*", "1", "p:b", "*", "2"}},
+		{[]interface{}{"json.mget", "a", "b", "$"}, []interface{}{"json.mget", "p:a", "p:b", "$"}},
+		{
+			[]interface{}{"json.mset", "a", "$", `{"a":1}`, "b", "$.c", "2"},
+			[]interface{}{"json.mset", "p:a", "$", `{"a":1}`, "p:b", "$.c", "2"},
+		},
+		{[]interface{}{"json.get", "a", "$"}, []interface{}{"json.get", "p:a", "$"}},
+		{[]interface{}{"ts.mrange", "-", "+", "filter", "a=b"}, []interface{}{"ts.mrange", "-", "+", "filter", "a=b"}},
+		{[]interface{}{"cluster", "keyslot", "a"}, []interface{}{"cluster", "keyslot", "p:a"}},
+		{[]interface{}{"cluster", "countkeysinslot", "1"}, []interface{}{"cluster", "countkeysinslot", "1"}},
+		{[]interface{}{"blpop", "a", "b", 0}, []interface{}{"blpop", "p:a", "p:b", 0}},
+		{[]interface{}{"copy", "a", "b", "replace"}, []interface{}{"copy", "p:a", "p:b", "replace"}},
+		{[]interface{}{"eval", "return 1", 0, "arg"}, []interface{}{"eval", "return 1", 0, "arg"}},
+		{[]interface{}{"zunionstore", "d", 2, "a", "b"}, []interface{}{"zunionstore", "p:d", 2, "p:a", "p:b"}},
+		{[]interface{}{"lmpop", 1, "a", "left"}, []interface{}{"lmpop", 1, "p:a", "left"}},
+		{
+			[]interface{}{"xread", "count", 1, "streams", "a", "b", "0", "0"},
+			[]interface{}{"xread", "count", 1, "streams", "p:a", "p:b", "0", "0"},
+		},
+		{[]interface{}{"object", "encoding", "a"}, []interface{}{"object", "encoding", "p:a"}},
+		{[]interface{}{"bitop", "and", "d", "a"}, []interface{}{"bitop", "and", "p:d", "p:a"}},
+		{
+			[]interface{}{"migrate", "host", 6379, "", 0, 5000, "keys", "a", "b"},
+			[]interface{}{"migrate", "host", 6379, "", 0, 5000, "keys", "p:a", "p:b"},
+		},
+		{[]interface{}{"sort", "a", "store", "d"}, []interface{}{"sort", "p:a", "store", "p:d"}},
+		{[]interface{}{"scan", 0, "match", "k*"}, []interface{}{"scan", 0, "match", "p:k*"}},
+		{[]interface{}{"ft.search", "idx", "*"}, []interface{}{"ft.search", "idx", "*"}},
+	} {
+		cmd := redis.NewCmd(context.Background(), tc.args...)
+		prefixKeys("p:", cmd)
+		assert.Equal(t, tc.want, cmd.Args(), "%v", tc.args)
+	}
+}
@@ -0,0 +1,273 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/grafana/sobek"
+	"github.com/redis/go-redis/v9"
+	"go.k6.io/k6/event"
+)
+
+// keyTrackingHook is the go-redis hook implementing the trackKeys option:
+// it records the keys written by the commands sent on behalf of a Client
+// with the option set, once they succeed, so that cleanupIteration can
+// delete them.
+//
+// The hook is installed on the go-redis client, before keyPrefixHook, so
+// that it records the keys as the script named them, which are prefixed
+// again when deleted.
+type keyTrackingHook struct{}
+
+var _ redis.Hook = keyTrackingHook{}
+
+// DialHook implements the redis.Hook interface.
+func (keyTrackingHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+// ProcessHook implements the redis.Hook interface.
+func (h keyTrackingHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		c, ok := trackingClient(ctx)
+		if !ok {
+			return next(ctx, cmd)
+		}
+
+		// The keys are read before the command is sent, as the other
+		// hooks modify its arguments.
+		keys := writtenKeys(cmd)
+		err := next(ctx, cmd)
+		if !isCommandError(err) {
+			c.trackedKeys.add(c, keys)
+		}
+
+		return err
+	}
+}
+
+// ProcessPipelineHook implements the redis.Hook interface.
+func (h keyTrackingHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		c, ok := trackingClient(ctx)
+		if !ok {
+			return next(ctx, cmds)
+		}
+
+		keys := make([][]string, len(cmds))
+		for idx, cmd := range cmds {
+			keys[idx] = writtenKeys(cmd)
+		}
+
+		err := next(ctx, cmds)
+		for idx, cmd := range cmds {
+			if !isCommandError(cmd.Err()) {
+				c.trackedKeys.add(c, keys[idx])
+			}
+		}
+
+		return err
+	}
+}
+
+// trackingClient returns the Client carried by the context, if it has the
+// trackKeys option set.
+func trackingClient(ctx context.Context) (*Client, bool) {
+	c, ok := clientFromContext(ctx)
+	if !ok || c.trackedKeys == nil || c.redisOptions == nil || !c.redisOptions.tracksKeys() {
+		return nil, false
+	}
+
+	return c, true
+}
+
+// untrackedCommands lists the write commands which don't create the keys
+// they operate on, but remove them, or some of their content, whose keys
+// are not recorded by keyTrackingHook.
+var untrackedCommands = map[string]struct{}{
+	"del": {}, "unlink": {}, "getdel": {}, "move": {}, "migrate": {}, "persist": {}, "expire": {},
+	"expireat": {}, "pexpire": {}, "pexpireat": {}, "hdel": {}, "hexpire": {}, "hpexpire": {}, "hpersist": {},
+	"lpop": {}, "rpop": {}, "lrem": {}, "ltrim": {}, "blpop": {}, "brpop": {}, "lmpop": {}, "blmpop": {},
+	"spop": {}, "srem": {}, "zpopmin": {}, "zpopmax": {}, "bzpopmin": {}, "bzpopmax": {}, "zmpop": {},
+	"bzmpop": {}, "zrem": {}, "zremrangebylex": {}, "zremrangebyrank": {}, "zremrangebyscore": {}, "xack": {},
+	"xdel": {}, "xtrim": {}, "json.del": {}, "json.forget": {}, "ts.del": {}, "cf.del": {},
+}
+
+// destinationKeyCommands lists the write commands whose second key is the
+// one they write, the first one being their source.
+var destinationKeyCommands = map[string]struct{}{
+	"rename": {}, "renamenx": {}, "copy": {}, "smove": {}, "rpoplpush": {}, "lmove": {}, "blmove": {},
+	"brpoplpush": {}, "geosearchstore": {}, "zrangestore": {},
+}
+
+// writtenKeys returns the keys `cmd` writes, if it is a write command, see
+// writeCommand. The source keys of the commands writing their result to
+// another key, such as SINTERSTORE, aren't returned.
+func writtenKeys(cmd redis.Cmder) []string {
+	name, write := writeCommand(cmd)
+	if !write {
+		return nil
+	}
+	if _, ok := untrackedCommands[name]; ok {
+		return nil
+	}
+
+	indexes := keyIndexes(cmd)
+	if len(indexes) == 0 {
+		return nil
+	}
+
+	switch {
+	case name == "bitop" || name == "pfmerge" || strings.HasSuffix(name, "store"):
+		indexes = indexes[:1]
+	case name == "sort" || name == "georadius" || name == "georadiusbymember":
+		// The destination keys follow the source key.
+		indexes = indexes[1:]
+	default:
+		if _, ok := destinationKeyCommands[name]; ok && len(indexes) > 1 {
+			indexes = indexes[1:2]
+		}
+	}
+
+	args := cmd.Args()
+	keys := make([]string, len(indexes))
+	for idx, argIdx := range indexes {
+		keys[idx] = argString(args[argIdx])
+	}
+
+	return keys
+}
+
+// trackedKeys holds the keys recorded by keyTrackingHook, by the Client
+// that wrote them, shared with the clients derived from a Client, which
+// may write to another database.
+type trackedKeys struct {
+	mu   sync.Mutex
+	keys map[*Client]map[string]struct{}
+}
+
+// add records the provided keys, written by `c`.
+func (t *trackedKeys) add(c *Client, keys []string) {
+	if len(keys) == 0 {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.keys == nil {
+		t.keys = make(map[*Client]map[string]struct{})
+	}
+
+	written, ok := t.keys[c]
+	if !ok {
+		written = make(map[string]struct{})
+		t.keys[c] = written
+	}
+
+	for _, key := range keys {
+		written[key] = struct{}{}
+	}
+}
+
+// take returns the recorded keys, by the Client that wrote them, and
+// forgets them.
+func (t *trackedKeys) take() map[*Client]map[string]struct{} {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	keys := t.keys
+	t.keys = nil
+
+	return keys
+}
+
+// CleanupIteration deletes the keys written by the client, and the clients
+// derived from it, since the previous call, such as at the end of each
+// iteration of a soak test, so that the dataset doesn't grow over time.
+// It requires the trackKeys option.
+//
+// The promise resolves with the number of keys deleted.
+func (c *Client) CleanupIteration() *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	if !c.redisOptions.tracksKeys() {
+		reject(errors.New("cleanupIteration requires the trackKeys option"))
+		return promise
+	}
+
+	go func() {
+		deleted, err := c.deleteTrackedKeys()
+		if err != nil {
+			reject(fmt.Errorf("cleanupIteration failed after deleting %d keys; reason: %w", deleted, err))
+			return
+		}
+
+		resolve(deleted)
+	}()
+
+	return promise
+}
+
+// deleteTrackedKeys unlinks the recorded keys, in batches, through the
+// Client that wrote them, and returns the number of keys deleted.
+func (c *Client) deleteTrackedKeys() (int64, error) {
+	var deleted int64
+	for writer, written := range c.trackedKeys.take() {
+		if err := writer.connect(); err != nil {
+			return deleted, err
+		}
+
+		keys := make([]string, 0, len(written))
+		for key := range written {
+			keys = append(keys, key)
+		}
+
+		_, cluster := writer.redisClient.(*redis.ClusterClient)
+		for len(keys) > 0 {
+			batch := keys
+			if len(batch) > defaultDeleteBatchSize {
+				batch = batch[:defaultDeleteBatchSize]
+			}
+			keys = keys[len(batch):]
+
+			n, err := unlinkBatch(writer.context(), writer.redisClient, batch, cluster)
+			deleted += n
+			if err != nil {
+				return deleted, err
+			}
+		}
+	}
+
+	return deleted, nil
+}
+
+// cleanupOnIterationEnd deletes the keys recorded for the Client at the end
+// of each of its VU's iterations, for the autoCleanup option.
+func (c *Client) cleanupOnIterationEnd() {
+	events := c.vu.Events().Local
+	if events == nil {
+		return
+	}
+
+	_, iterations := events.Subscribe(event.IterEnd)
+
+	go func() {
+		for ev := range iterations {
+			if deleted, err := c.deleteTrackedKeys(); err != nil {
+				if state := c.vu.State(); state != nil {
+					state.Logger.WithError(err).Warnf("Failed to clean up the iteration's keys after deleting %d", deleted)
+				}
+			}
+			ev.Done()
+		}
+	}()
+}
@@ -0,0 +1,133 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.k6.io/k6/event"
+)
+
+func TestClientCleanupIteration(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunT(t)
+	for _, command := range []string{"SET", "HSET", "LPUSH", "SINTERSTORE", "RENAME"} {
+		rs.RegisterCommandHandler(command, func(c *Connection, _ []string) {
+			c.WriteInteger(1)
+		})
+	}
+	rs.RegisterCommandHandler("GET", func(c *Connection, _ []string) {
+		c.WriteBulkString("bar")
+	})
+	rs.RegisterCommandHandler("UNLINK", func(c *Connection, args []string) {
+		c.WriteInteger(len(args))
+	})
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client({ socket: { host: "%s", port: %d }, trackKeys: true, keyPrefix: "test:" });
+			const untracked = new Client({ socket: { host: "%s", port: %d } });
+
+			if (redis.options().trackKeys !== true) {
+				throw 'expected the trackKeys option to be reported';
+			}
+
+			redis.sendCommand("SET", "a", "1")
+				.then(() => redis.get("b"))
+				.then(() => redis.withTimeout(1000).sendCommand("HSET", "c", "field", "1"))
+				.then(() => redis.pipeline().sendCommand("LPUSH", "d", "1").sendCommand("SINTERSTORE", "e", "x", "y").exec())
+				.then(() => redis.sendCommand("RENAME", "x", "f"))
+				.then(() => untracked.sendCommand("SET", "g", "1"))
+				.then(() => redis.cleanupIteration())
+				.then(res => { if (res !== 5) { throw 'unexpected value for cleanupIteration result: ' + res } })
+				.then(() => redis.cleanupIteration())
+				.then(res => { if (res !== 0) { throw 'unexpected value for cleanupIteration result: ' + res } })
+				.then(() => untracked.cleanupIteration())
+				.then(
+					res => { throw 'expected cleanupIteration to fail' },
+					err => { if (!err.error().includes('requires the trackKeys option')) { throw 'unexpected error: ' + err.error() } }
+				)
+		`, rs.Addr().IP, rs.Addr().Port, rs.Addr().IP, rs.Addr().Port))
+
+		return err
+	})
+	require.NoError(t, gotScriptErr)
+
+	var unlinked []string
+	for _, cmd := range rs.GotCommands() {
+		if cmd[0] == "UNLINK" {
+			unlinked = append(unlinked, cmd[1:]...)
+		}
+	}
+	assert.ElementsMatch(t, []string{"test:a", "test:c", "test:d", "test:e", "test:f"}, unlinked)
+}
+
+func TestClientAutoCleanup(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	events := event.NewEventSystem(10, logrus.New())
+	ts.runtime.VU.EventsField.Local = events
+
+	rs := RunT(t)
+	rs.RegisterCommandHandler("SET", func(c *Connection, _ []string) {
+		c.WriteOK()
+	})
+	rs.RegisterCommandHandler("UNLINK", func(c *Connection, args []string) {
+		c.WriteInteger(len(args))
+	})
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client({ socket: { host: "%s", port: %d }, autoCleanup: true });
+
+			redis.set("session", "1", 0)
+		`, rs.Addr().IP, rs.Addr().Port))
+
+		return err
+	})
+	require.NoError(t, gotScriptErr)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, events.Emit(&event.Event{Type: event.IterEnd, Data: event.IterData{}})(ctx))
+
+	assert.Equal(t, [][]string{
+		{"HELLO", "2"},
+		{"SET", "session", "1"},
+		{"UNLINK", "session"},
+	}, rs.GotCommands())
+}
+
+func TestWrittenKeys(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		args []interface{}
+		keys []string
+	}{
+		{[]interface{}{"get", "a"}, nil},
+		{[]interface{}{"set", "a", "1"}, []string{"a"}},
+		{[]interface{}{"mset", "a", "1", "b", "2"}, []string{"a", "b"}},
+		{[]interface{}{"del", "a"}, nil},
+		{[]interface{}{"lpop", "a"}, nil},
+		{[]interface{}{"sinterstore", "dst", "a", "b"}, []string{"dst"}},
+		{[]interface{}{"zunionstore", "dst", 2, "a", "b"}, []string{"dst"}},
+		{[]interface{}{"bitop", "and", "dst", "a", "b"}, []string{"dst"}},
+		{[]interface{}{"lmove", "src", "dst", "left", "right"}, []string{"dst"}},
+		{[]interface{}{"sort", "a", "store", "dst"}, []string{"dst"}},
+		{[]interface{}{"sort", "a"}, nil},
+		{[]interface{}{"eval", "return 1", 2, "a", "b"}, []string{"a", "b"}},
+		{[]interface{}{"publish", "channel", "message"}, nil},
+		{[]interface{}{"config", "set", "maxmemory", "1gb"}, nil},
+	} {
+		assert.Equal(t, tc.keys, writtenKeys(redis.NewCmd(context.Background(), tc.args...)), tc.args)
+	}
+}
@@ -0,0 +1,202 @@
+package redis
+
+import (
+	"math"
+	"math/bits"
+	"sync"
+	"time"
+)
+
+// Latencies are recorded in microseconds, in the buckets of an HDR-style
+// histogram: values below latencySubBuckets each get a bucket of their own,
+// while larger values are bucketed by their highest latencySubBucketBits
+// bits, which bounds the relative error of percentiles to 1/64, that is
+// about 1.6%, regardless of the magnitude of the latencies.
+const (
+	latencySubBucketBits = 7
+	latencySubBuckets    = 1 << latencySubBucketBits
+	latencyHalfBuckets   = latencySubBuckets / 2
+)
+
+// latencyPercentiles are the percentiles reported by the stats method, by
+// name.
+var latencyPercentiles = []struct {
+	name     string
+	quantile float64
+}{
+	{"p50", 0.5},
+	{"p90", 0.9},
+	{"p99", 0.99},
+}
+
+// latencyStats holds the latency histogram of each command sent by a
+// Client, and the clients derived from it, by name.
+//
+// The zero value is empty stats ready to use.
+type latencyStats struct {
+	mu       sync.Mutex
+	commands map[string]*latencyHistogram
+}
+
+// latencyHistogram is the latency histogram of a single command.
+type latencyHistogram struct {
+	count  int64
+	errors int64
+	max    int64
+
+	// buckets holds the count of each bucket, see latencyBucket. It only
+	// grows as large as the highest latency recorded requires.
+	buckets []int64
+}
+
+// record records a command named `name`, which completed after `duration`,
+// with `err`.
+func (s *latencyStats) record(name string, duration time.Duration, err error) {
+	us := duration.Microseconds()
+	bucket := latencyBucket(us)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.commands == nil {
+		s.commands = make(map[string]*latencyHistogram)
+	}
+
+	h, ok := s.commands[name]
+	if !ok {
+		h = &latencyHistogram{}
+		s.commands[name] = h
+	}
+
+	if bucket >= len(h.buckets) {
+		buckets := make([]int64, bucket+1)
+		copy(buckets, h.buckets)
+		h.buckets = buckets
+	}
+
+	h.count++
+	h.buckets[bucket]++
+	if isCommandError(err) {
+		h.errors++
+	}
+	if us > h.max {
+		h.max = us
+	}
+}
+
+// reset discards the recorded latencies.
+func (s *latencyStats) reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.commands = nil
+}
+
+// snapshot returns the stats as an object holding, for each command name,
+// its `count`, its count of `errors`, and its p50, p90, p99, and max
+// latencies, in milliseconds.
+func (s *latencyStats) snapshot() map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	commands := make(map[string]interface{}, len(s.commands))
+	for name, h := range s.commands {
+		stats := map[string]interface{}{
+			"count":  h.count,
+			"errors": h.errors,
+			"max":    microsecondsToMs(h.max),
+		}
+		for _, p := range latencyPercentiles {
+			stats[p.name] = microsecondsToMs(h.percentile(p.quantile))
+		}
+
+		commands[name] = stats
+	}
+
+	return commands
+}
+
+// percentile returns the latency, in microseconds, `quantile` of the
+// recorded latencies are lower than, or equal to. As in HDR histograms,
+// it is the highest latency of the bucket holding the percentile, capped
+// to the highest latency recorded.
+func (h *latencyHistogram) percentile(quantile float64) int64 {
+	rank := int64(math.Ceil(quantile * float64(h.count)))
+	if rank < 1 {
+		rank = 1
+	}
+
+	var seen int64
+	for idx, count := range h.buckets {
+		seen += count
+		if seen >= rank {
+			if bound := latencyBucketUpperBound(idx); bound < h.max {
+				return bound
+			}
+			break
+		}
+	}
+
+	return h.max
+}
+
+// latencyBucket returns the index of the bucket a latency of `us`
+// microseconds is recorded in.
+func latencyBucket(us int64) int {
+	if us < latencySubBuckets {
+		if us < 0 {
+			return 0
+		}
+		return int(us)
+	}
+
+	shift := bits.Len64(uint64(us)) - latencySubBucketBits
+	return latencySubBuckets + (shift-1)*latencyHalfBuckets + int(us>>shift) - latencyHalfBuckets
+}
+
+// latencyBucketUpperBound returns the highest latency, in microseconds,
+// recorded in the bucket at index `idx`.
+func latencyBucketUpperBound(idx int) int64 {
+	if idx < latencySubBuckets {
+		return int64(idx)
+	}
+
+	shift := (idx-latencySubBuckets)/latencyHalfBuckets + 1
+	top := int64((idx-latencySubBuckets)%latencyHalfBuckets + latencyHalfBuckets)
+
+	return (top+1)<<shift - 1
+}
+
+// microsecondsToMs converts a latency in microseconds to milliseconds.
+func microsecondsToMs(us int64) float64 {
+	return float64(us) / float64(time.Millisecond/time.Microsecond)
+}
+
+// Stats returns the latency of the commands sent by the client, and the
+// clients derived from it with withTimeout, withDatabase, withUser, and
+// withTags, so far, by command name: their `count`, their count of
+// `errors`, and their `p50`, `p90`, `p99`, and `max` latencies, in
+// milliseconds, such as to report Redis-specific latencies beyond k6's
+// built-in trends.
+//
+// Latencies span from the moment commands are sent, once throttled, until
+// their reply is received, including their retries. Those of the commands
+// of pipelines and transactions are those of the pipeline as a whole.
+//
+// As the stats are kept by each Client, they only cover the commands sent
+// by the VU it belongs to.
+func (c *Client) Stats() map[string]interface{} {
+	if c.stats == nil {
+		return map[string]interface{}{}
+	}
+
+	return c.stats.snapshot()
+}
+
+// StatsReset discards the latencies returned by the stats method, such as
+// to leave those of a warm-up phase out.
+func (c *Client) StatsReset() {
+	if c.stats != nil {
+		c.stats.reset()
+	}
+}
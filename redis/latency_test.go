@@ -0,0 +1,93 @@
+package redis
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientStats(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunT(t)
+	rs.RegisterCommandHandler("GET", func(c *Connection, _ []string) {
+		c.WriteBulkString("bar")
+	})
+	rs.RegisterCommandHandler("SET", func(c *Connection, _ []string) {
+		c.WriteError(fmt.Errorf("ERR some error"))
+	})
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client('redis://%s');
+
+			Promise.all([redis.get("foo"), redis.get("foo"), redis.withTimeout(1000).get("foo")])
+				.then(() => redis.set("foo", "bar", 0))
+				.catch(() => {})
+				.then(() => {
+					const stats = redis.stats();
+
+					const get = stats.get;
+					if (get.count !== 3 || get.errors !== 0) {
+						throw 'unexpected get stats: ' + JSON.stringify(get)
+					}
+					if (!(get.p50 <= get.p90 && get.p90 <= get.p99 && get.p99 <= get.max)) {
+						throw 'unexpected get percentiles: ' + JSON.stringify(get)
+					}
+
+					const set = stats.set;
+					if (set.count !== 1 || set.errors !== 1) {
+						throw 'unexpected set stats: ' + JSON.stringify(set)
+					}
+
+					redis.statsReset();
+					if (Object.keys(redis.stats()).length !== 0) {
+						throw 'unexpected stats after reset: ' + JSON.stringify(redis.stats())
+					}
+				})
+		`, rs.Addr()))
+
+		return err
+	})
+
+	assert.NoError(t, gotScriptErr)
+}
+
+func TestLatencyHistogram(t *testing.T) {
+	t.Parallel()
+
+	t.Run("buckets", func(t *testing.T) {
+		t.Parallel()
+
+		for _, us := range []int64{0, 1, 127, 128, 129, 255, 256, 1000, 12345, 987654321} {
+			bucket := latencyBucket(us)
+			upper := latencyBucketUpperBound(bucket)
+
+			require.GreaterOrEqual(t, upper, us, "latency %d", us)
+			assert.LessOrEqual(t, float64(upper-us), float64(us)/latencyHalfBuckets, "latency %d", us)
+			assert.Equal(t, bucket+1, latencyBucket(upper+1), "latency %d", us)
+		}
+	})
+
+	t.Run("percentiles", func(t *testing.T) {
+		t.Parallel()
+
+		var stats latencyStats
+		for ms := 1; ms <= 100; ms++ {
+			stats.record("get", time.Duration(ms)*time.Millisecond, nil)
+		}
+
+		get, ok := stats.snapshot()["get"].(map[string]interface{})
+		require.True(t, ok)
+		assert.Equal(t, int64(100), get["count"])
+		assert.Equal(t, int64(0), get["errors"])
+		assert.Equal(t, 100.0, get["max"])
+		assert.InEpsilon(t, 50.0, get["p50"], 0.02)
+		assert.InEpsilon(t, 90.0, get["p90"], 0.02)
+		assert.InEpsilon(t, 99.0, get["p99"], 0.02)
+	})
+}
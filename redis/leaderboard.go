@@ -0,0 +1,138 @@
+package redis
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/grafana/sobek"
+	"github.com/redis/go-redis/v9"
+)
+
+// defaultLeaderboardTop is the default number of entries of the rankings
+// reported by WatchLeaderboard.
+const defaultLeaderboardTop = 10
+
+// watchLeaderboardOptions holds the options of the Client's watchLeaderboard
+// method.
+type watchLeaderboardOptions struct {
+	// Top is the number of best ranked members read.
+	Top int64 `json:"top,omitempty"`
+
+	// DebounceMs is the duration, in milliseconds, invalidations are
+	// coalesced for before the ranking is read.
+	DebounceMs int64 `json:"debounceMs,omitempty"`
+}
+
+// WatchLeaderboard models a live leaderboard client: each time an
+// invalidation message is published to `channel`, it reads the top ranked
+// members of the sorted set stored at `key`, and calls `callback` with the
+// fresh ranking, an array of objects holding each `member` and its `score`,
+// from the highest score to the lowest.
+//
+// The `top` option sets the number of members read, 10 by default. The
+// `debounceMs` option coalesces the invalidations received within that many
+// milliseconds of the first one into a single read, to avoid read storms.
+//
+// The watch relies on the client's subscription: it ends when the client
+// unsubscribes from `channel`. If reading the ranking fails, the error is
+// thrown on the event loop, which fails the iteration.
+//
+// The promise resolves once the subscription to `channel` is confirmed.
+func (c *Client) WatchLeaderboard(
+	key, channel string, callback sobek.Callable, options map[string]interface{},
+) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	if callback == nil {
+		reject(errors.New("a callback function must be provided to watchLeaderboard"))
+		return promise
+	}
+
+	opts := watchLeaderboardOptions{Top: defaultLeaderboardTop}
+	if err := decodeOptions(options, &opts); err != nil {
+		reject(fmt.Errorf("invalid watchLeaderboard options; reason: %w", err))
+		return promise
+	}
+
+	if opts.Top <= 0 {
+		reject(fmt.Errorf("invalid top option: %d; expected a positive number", opts.Top))
+		return promise
+	}
+
+	if opts.DebounceMs < 0 {
+		reject(fmt.Errorf("invalid debounceMs option: %d; expected a positive number", opts.DebounceMs))
+		return promise
+	}
+
+	sub := c.subscription()
+	handler := c.leaderboardHandler(sub, key, channel, opts, callback)
+	confirmed := sub.register(false, []string{channel}, handler)
+
+	go func() {
+		if err := c.awaitSubscription(sub, false, []string{channel}, confirmed); err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(nil)
+	}()
+
+	return promise
+}
+
+// leaderboardHandler returns the messageHandler reading, and reporting to
+// `callback`, the ranking of the sorted set stored at `key` upon
+// invalidations published to `channel`.
+func (c *Client) leaderboardHandler(
+	sub *subscription, key, channel string, opts watchLeaderboardOptions, callback sobek.Callable,
+) messageHandler {
+	var (
+		mu        sync.Mutex
+		scheduled bool
+		rt        = c.vu.Runtime()
+		debounce  = time.Duration(opts.DebounceMs) * time.Millisecond
+	)
+
+	read := func() {
+		// Invalidations received from now on call for a new read, as they
+		// may not be reflected by this one.
+		mu.Lock()
+		scheduled = false
+		mu.Unlock()
+
+		ranking, err := c.redisClient.ZRevRangeWithScores(c.context(), key, 0, opts.Top-1).Result()
+
+		sub.queue.Queue(func() error {
+			// The channel may have been unsubscribed from in the meantime.
+			if sub.handler(subscriptionTarget{name: channel}) == nil {
+				return nil
+			}
+
+			if err != nil {
+				return fmt.Errorf("reading the %q leaderboard failed; reason: %w", key, err)
+			}
+
+			_, err := callback(sobek.Undefined(), rt.ToValue(scoredMembersToJS(ranking)))
+			return err
+		})
+	}
+
+	return func(*redis.Message) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if scheduled {
+			return
+		}
+		scheduled = true
+
+		time.AfterFunc(debounce, read)
+	}
+}
@@ -0,0 +1,81 @@
+package redis
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientWatchLeaderboard(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalidations are debounced into a single read", func(t *testing.T) {
+		t.Parallel()
+
+		ts := newTestSetup(t)
+		rs := RunT(t)
+		registerPubSubHandlers(rs)
+		rs.RegisterCommandHandler("ZREVRANGE", func(c *Connection, _ []string) {
+			c.WriteArray("alice", "30", "bob", "20.5")
+		})
+
+		gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+			_, err := ts.rt.RunString(fmt.Sprintf(`
+				const redis = new Client('redis://%s');
+
+				redis.watchLeaderboard("board", "board:updates", (ranking) => {
+					if (ranking.length !== 2 || ranking[0].member !== "alice" || ranking[0].score !== 30 || ranking[1].score !== 20.5) {
+						throw 'unexpected ranking: ' + JSON.stringify(ranking)
+					}
+
+					redis.unsubscribe("board:updates")
+				}, { top: 2, debounceMs: 200 })
+					.then(() => redis.sendCommand("PUBLISH", "board:updates", "1"))
+					.then(() => redis.sendCommand("PUBLISH", "board:updates", "2"))
+					.then(() => redis.sendCommand("PUBLISH", "board:updates", "3"))
+			`, rs.Addr()))
+
+			return err
+		})
+
+		assert.NoError(t, gotScriptErr)
+
+		var reads [][]string
+		for _, cmd := range rs.GotCommands() {
+			if cmd[0] == "ZREVRANGE" {
+				reads = append(reads, cmd)
+			}
+		}
+		assert.Equal(t, [][]string{{"ZREVRANGE", "board", "0", "1", "withscores"}}, reads)
+	})
+
+	t.Run("invalid options are rejected", func(t *testing.T) {
+		t.Parallel()
+
+		ts := newTestSetup(t)
+		rs := RunT(t)
+
+		gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+			_, err := ts.rt.RunString(fmt.Sprintf(`
+				const redis = new Client('redis://%s');
+
+				redis.watchLeaderboard("board", "board:updates", () => {}, { debounceMs: -1 })
+					.then(
+						res => { throw 'expected watchLeaderboard to fail' },
+						err => { if (!err.error().startsWith('invalid debounceMs option')) { throw 'unexpected error: ' + err } }
+					)
+					.then(() => redis.watchLeaderboard("board", "board:updates"))
+					.then(
+						res => { throw 'expected watchLeaderboard to fail' },
+						err => { if (!err.error().startsWith('a callback function must be provided')) { throw 'unexpected error: ' + err } }
+					)
+			`, rs.Addr()))
+
+			return err
+		})
+
+		assert.NoError(t, gotScriptErr)
+		assert.Equal(t, 0, rs.HandledCommandsCount())
+	})
+}
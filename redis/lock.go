@@ -0,0 +1,207 @@
+package redis
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/grafana/sobek"
+	"github.com/redis/go-redis/v9"
+)
+
+// defaultLockTTL is the time to live of locks acquired without the ttl
+// option.
+const defaultLockTTL = 10 * time.Second
+
+// defaultLockRetryDelay is the delay between the attempts to acquire a lock
+// without the retryDelay option.
+const defaultLockRetryDelay = 100 * time.Millisecond
+
+// lockReleaseScript deletes the lock stored at KEYS[1], provided it is still
+// held with the token ARGV[1], and returns 1 if it did, and 0 otherwise.
+// Comparing the token and deleting the key in a script makes it atomic, so
+// that a lock which expired, and was acquired by another holder meanwhile,
+// is never released.
+var lockReleaseScript = redis.NewScript(lockReleaseSource)
+
+// lockReleaseSource is the source of lockReleaseScript.
+const lockReleaseSource = `
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`
+
+// lockExtendScript sets the time to live of the lock stored at KEYS[1] to
+// ARGV[2] milliseconds, provided it is still held with the token ARGV[1],
+// and returns 1 if it did, and 0 otherwise.
+var lockExtendScript = redis.NewScript(lockExtendSource)
+
+// lockExtendSource is the source of lockExtendScript.
+const lockExtendSource = `
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return 0
+`
+
+// lockOptions holds the options of the Client's lock method.
+type lockOptions struct {
+	// TTL is the time, in milliseconds, after which the lock expires if it
+	// isn't released, or extended. It defaults to 10 seconds.
+	TTL int64 `json:"ttl,omitempty"`
+
+	// Retries is the number of attempts made to acquire the lock after the
+	// first one failed.
+	Retries int `json:"retries,omitempty"`
+
+	// RetryDelay is the time, in milliseconds, waited between attempts. It
+	// defaults to 100 milliseconds.
+	RetryDelay int64 `json:"retryDelay,omitempty"`
+}
+
+// Lock is a lock held on a key, as acquired by the Client's lock method.
+type Lock struct {
+	client *Client
+	key    string
+	token  string
+	ttl    time.Duration
+}
+
+// Lock acquires the lock stored at `key`, with SET NX, so that a single
+// holder, across VUs and k6 instances, gets it at a time. The lock is held
+// with a random token, so that only its holder can release, or extend, it.
+//
+// Locks are acquired on the single Redis instance, or cluster shard, `key`
+// belongs to, rather than on several independent masters as Redlock does.
+//
+// The promise resolves with the lock, exposing the release and extend
+// methods. If the lock is still held by another holder after the provided
+// number of retries, the promise is rejected with an error.
+func (c *Client) Lock(key string, options map[string]interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	var opts lockOptions
+	if err := decodeOptions(options, &opts); err != nil {
+		reject(fmt.Errorf("invalid lock options; reason: %w", err))
+		return promise
+	}
+
+	if opts.TTL < 0 || opts.Retries < 0 || opts.RetryDelay < 0 {
+		reject(errors.New("invalid lock options; ttl, retries, and retryDelay must be positive numbers"))
+		return promise
+	}
+
+	ttl, retryDelay := defaultLockTTL, defaultLockRetryDelay
+	if opts.TTL > 0 {
+		ttl = time.Duration(opts.TTL) * time.Millisecond
+	}
+	if opts.RetryDelay > 0 {
+		retryDelay = time.Duration(opts.RetryDelay) * time.Millisecond
+	}
+
+	token, err := randomToken()
+	if err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		ctx := c.context()
+		for attempt := 0; ; attempt++ {
+			acquired, err := c.redisClient.SetNX(ctx, key, token, ttl).Result()
+			if err != nil {
+				reject(err)
+				return
+			}
+
+			if acquired {
+				resolve(&Lock{client: c, key: key, token: token, ttl: ttl})
+				return
+			}
+
+			if attempt == opts.Retries {
+				reject(fmt.Errorf("unable to acquire lock %q after %d attempts", key, attempt+1))
+				return
+			}
+
+			select {
+			case <-time.After(retryDelay):
+			case <-ctx.Done():
+				reject(fmt.Errorf("unable to acquire lock %q; reason: %w", key, ctx.Err()))
+				return
+			}
+		}
+	}()
+
+	return promise
+}
+
+// randomToken returns a random token, such as those identifying the holder
+// of a lock.
+func randomToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("unable to generate random token; reason: %w", err)
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+// Release releases the lock, provided it is still held.
+//
+// The promise resolves with true if the lock was released, and false if it
+// had expired, possibly being acquired by another holder since.
+func (l *Lock) Release() *sobek.Promise {
+	return l.run(func(ctx context.Context) *redis.Cmd {
+		return lockReleaseScript.Run(ctx, l.client.redisClient, []string{l.key}, l.token)
+	})
+}
+
+// Extend sets the time to live of the lock to `ttlMs` milliseconds, or to
+// the ttl it was acquired with if zero, provided it is still held.
+//
+// The promise resolves with true if the lock was extended, and false if it
+// had expired, possibly being acquired by another holder since.
+func (l *Lock) Extend(ttlMs int64) *sobek.Promise {
+	if ttlMs < 0 {
+		promise, _, reject := l.client.newPromise()
+		reject(fmt.Errorf("invalid ttl: %d; expected a positive number", ttlMs))
+		return promise
+	}
+
+	ttl := l.ttl
+	if ttlMs > 0 {
+		ttl = time.Duration(ttlMs) * time.Millisecond
+	}
+
+	return l.run(func(ctx context.Context) *redis.Cmd {
+		return lockExtendScript.Run(ctx, l.client.redisClient, []string{l.key}, l.token, ttl.Milliseconds())
+	})
+}
+
+// run runs one of the lock's scripts, and resolves with whether it
+// affected the lock.
+func (l *Lock) run(script func(ctx context.Context) *redis.Cmd) *sobek.Promise {
+	promise, resolve, reject := l.client.newPromise()
+
+	go func() {
+		affected, err := script(l.client.context()).Int64()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(affected == 1)
+	}()
+
+	return promise
+}
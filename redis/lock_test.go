@@ -0,0 +1,73 @@
+package redis
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientLock(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunT(t)
+	rs.RegisterCommandHandler("SET", func(c *Connection, args []string) {
+		if args[0] == "busy" {
+			c.WriteNull()
+			return
+		}
+
+		c.WriteOK()
+	})
+	rs.RegisterCommandHandler("EVALSHA", func(c *Connection, _ []string) {
+		c.WriteError(errors.New("NOSCRIPT No matching script. Please use EVAL."))
+	})
+	rs.RegisterCommandHandler("EVAL", func(c *Connection, args []string) {
+		if args[0] == lockExtendSource {
+			c.WriteInteger(1)
+			return
+		}
+
+		c.WriteInteger(0)
+	})
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client('redis://%s');
+
+			redis.lock("resource", { ttl: 3000 })
+				.then(lock => lock.extend(5000)
+					.then(res => { if (res !== true) { throw 'unexpected value for extend result: ' + res } })
+					.then(() => lock.release())
+					.then(res => { if (res !== false) { throw 'unexpected value for release result: ' + res } }))
+				.then(() => redis.lock("busy", { retries: 2, retryDelay: 1 }))
+				.then(
+					res => { throw 'expected lock to fail' },
+					err => { if (err.error() !== 'unable to acquire lock "busy" after 3 attempts') { throw 'unexpected error: ' + err.error() } }
+				)
+		`, rs.Addr()))
+
+		return err
+	})
+
+	assert.NoError(t, gotScriptErr)
+
+	commands := rs.GotCommands()
+	require.Len(t, commands, 9)
+	token := commands[1][2]
+	assert.Len(t, token, 32)
+	assert.Equal(t, [][]string{
+		{"HELLO", "2"},
+		{"SET", "resource", token, "ex", "3", "nx"},
+		{"EVALSHA", lockExtendScript.Hash(), "1", "resource", token, "5000"},
+		{"EVAL", lockExtendSource, "1", "resource", token, "5000"},
+		{"EVALSHA", lockReleaseScript.Hash(), "1", "resource", token},
+		{"EVAL", lockReleaseSource, "1", "resource", token},
+	}, commands[:6])
+	for _, cmd := range commands[6:] {
+		assert.Equal(t, "busy", cmd[1])
+	}
+}
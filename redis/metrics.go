@@ -0,0 +1,217 @@
+package redis
+
+import (
+	"fmt"
+	"time"
+
+	"go.k6.io/k6/metrics"
+)
+
+// redisMetrics holds the custom k6 metrics emitted by the module.
+type redisMetrics struct {
+	// ThrottleWait measures the time commands waited for the client-side
+	// throughput limiter to let them through.
+	ThrottleWait *metrics.Metric
+
+	// StreamEntriesAdded counts the entries added to streams.
+	StreamEntriesAdded *metrics.Metric
+
+	// StreamEntriesRead counts the entries read from streams.
+	StreamEntriesRead *metrics.Metric
+
+	// StreamEntryLatency measures the time elapsed between the creation of
+	// stream entries, as encoded in their IDs, and their reading.
+	StreamEntryLatency *metrics.Metric
+
+	// ConsumerLag measures the number of stream entries still waiting to be
+	// delivered to the consumers of a consumer group.
+	ConsumerLag *metrics.Metric
+
+	// Ops counts the commands, and pipelines, sent to Redis.
+	Ops *metrics.Metric
+
+	// OpDuration measures the round-trip time of the commands, and
+	// pipelines, sent to Redis.
+	OpDuration *metrics.Metric
+
+	// Errors counts the commands that failed.
+	Errors *metrics.Metric
+
+	// CacheHits counts the reads served from the client-side cache.
+	CacheHits *metrics.Metric
+
+	// CacheMisses counts the reads which could have been, but weren't,
+	// served from the client-side cache.
+	CacheMisses *metrics.Metric
+
+	// Retries counts the retries of failed commands, and pipelines.
+	Retries *metrics.Metric
+
+	// UsedMemory measures the memory used by the server, as sampled by
+	// sampleServerStats.
+	UsedMemory *metrics.Metric
+
+	// ConnectedClients measures the number of clients connected to the
+	// server, as sampled by sampleServerStats.
+	ConnectedClients *metrics.Metric
+
+	// InstantaneousOpsPerSec measures the number of commands processed per
+	// second by the server, as sampled by sampleServerStats.
+	InstantaneousOpsPerSec *metrics.Metric
+
+	// Expectations measures the rate of the commands sent by expect which
+	// met their expectations.
+	Expectations *metrics.Metric
+
+	// BudgetExceeded measures the rate of the commands sent by expect which
+	// exceeded their maxDuration latency budget.
+	BudgetExceeded *metrics.Metric
+
+	// UncompressedBytes counts the size of the values compressed with the
+	// compression option, before their compression.
+	UncompressedBytes *metrics.Metric
+
+	// CompressedBytes counts the size of the values compressed with the
+	// compression option, once compressed.
+	CompressedBytes *metrics.Metric
+
+	// TargetMatches measures the rate of the calls of client groups with
+	// the compare option whose targets' results matched.
+	TargetMatches *metrics.Metric
+
+	// DataCorruption measures the rate of the values read by getVerified
+	// whose checksum didn't match.
+	DataCorruption *metrics.Metric
+}
+
+// registerMetrics registers the module's custom metrics in the provided registry.
+func registerMetrics(registry *metrics.Registry) (*redisMetrics, error) {
+	var (
+		rm  = &redisMetrics{}
+		err error
+	)
+
+	if rm.ThrottleWait, err = registry.NewMetric("redis_throttle_wait", metrics.Trend, metrics.Time); err != nil {
+		return nil, fmt.Errorf("failed to register redis metrics; reason: %w", err)
+	}
+
+	if rm.StreamEntriesAdded, err = registry.NewMetric("redis_stream_entries_added", metrics.Counter); err != nil {
+		return nil, fmt.Errorf("failed to register redis metrics; reason: %w", err)
+	}
+
+	if rm.StreamEntriesRead, err = registry.NewMetric("redis_stream_entries_read", metrics.Counter); err != nil {
+		return nil, fmt.Errorf("failed to register redis metrics; reason: %w", err)
+	}
+
+	if rm.StreamEntryLatency, err = registry.NewMetric("redis_stream_entry_latency", metrics.Trend, metrics.Time); err != nil {
+		return nil, fmt.Errorf("failed to register redis metrics; reason: %w", err)
+	}
+
+	if rm.ConsumerLag, err = registry.NewMetric("redis_consumer_lag", metrics.Trend); err != nil {
+		return nil, fmt.Errorf("failed to register redis metrics; reason: %w", err)
+	}
+
+	if rm.Ops, err = registry.NewMetric("redis_ops", metrics.Counter); err != nil {
+		return nil, fmt.Errorf("failed to register redis metrics; reason: %w", err)
+	}
+
+	if rm.OpDuration, err = registry.NewMetric("redis_op_duration", metrics.Trend, metrics.Time); err != nil {
+		return nil, fmt.Errorf("failed to register redis metrics; reason: %w", err)
+	}
+
+	if rm.Errors, err = registry.NewMetric("redis_errors", metrics.Counter); err != nil {
+		return nil, fmt.Errorf("failed to register redis metrics; reason: %w", err)
+	}
+
+	if rm.CacheHits, err = registry.NewMetric("redis_cache_hits", metrics.Counter); err != nil {
+		return nil, fmt.Errorf("failed to register redis metrics; reason: %w", err)
+	}
+
+	if rm.CacheMisses, err = registry.NewMetric("redis_cache_misses", metrics.Counter); err != nil {
+		return nil, fmt.Errorf("failed to register redis metrics; reason: %w", err)
+	}
+
+	if rm.Retries, err = registry.NewMetric("redis_retries", metrics.Counter); err != nil {
+		return nil, fmt.Errorf("failed to register redis metrics; reason: %w", err)
+	}
+
+	if rm.UsedMemory, err = registry.NewMetric("redis_used_memory", metrics.Gauge, metrics.Data); err != nil {
+		return nil, fmt.Errorf("failed to register redis metrics; reason: %w", err)
+	}
+
+	if rm.ConnectedClients, err = registry.NewMetric("redis_connected_clients", metrics.Gauge); err != nil {
+		return nil, fmt.Errorf("failed to register redis metrics; reason: %w", err)
+	}
+
+	if rm.InstantaneousOpsPerSec, err = registry.NewMetric("redis_instantaneous_ops_per_sec", metrics.Gauge); err != nil {
+		return nil, fmt.Errorf("failed to register redis metrics; reason: %w", err)
+	}
+
+	if rm.Expectations, err = registry.NewMetric("redis_expectations", metrics.Rate); err != nil {
+		return nil, fmt.Errorf("failed to register redis metrics; reason: %w", err)
+	}
+
+	if rm.BudgetExceeded, err = registry.NewMetric("redis_budget_exceeded", metrics.Rate); err != nil {
+		return nil, fmt.Errorf("failed to register redis metrics; reason: %w", err)
+	}
+
+	if rm.UncompressedBytes, err = registry.NewMetric("redis_uncompressed_bytes", metrics.Counter, metrics.Data); err != nil {
+		return nil, fmt.Errorf("failed to register redis metrics; reason: %w", err)
+	}
+
+	if rm.CompressedBytes, err = registry.NewMetric("redis_compressed_bytes", metrics.Counter, metrics.Data); err != nil {
+		return nil, fmt.Errorf("failed to register redis metrics; reason: %w", err)
+	}
+
+	if rm.TargetMatches, err = registry.NewMetric("redis_target_matches", metrics.Rate); err != nil {
+		return nil, fmt.Errorf("failed to register redis metrics; reason: %w", err)
+	}
+
+	if rm.DataCorruption, err = registry.NewMetric("redis_data_corruption", metrics.Rate); err != nil {
+		return nil, fmt.Errorf("failed to register redis metrics; reason: %w", err)
+	}
+
+	return rm, nil
+}
+
+// pushMetric emits a sample of the provided metric, tagged with the VU's
+// current tags.
+//
+// It is safe to call from any goroutine. It is a no-op in the init context.
+func (c *Client) pushMetric(metric *metrics.Metric, value float64) {
+	c.pushTaggedMetric(metric, value, nil)
+}
+
+// pushTaggedMetric is like pushMetric, except that the sample is tagged
+// with the provided tags, on top of the VU's current tags, and of the
+// Client's tags, see withTags.
+func (c *Client) pushTaggedMetric(metric *metrics.Metric, value float64, tags map[string]string) {
+	state := c.vu.State()
+	if state == nil || metric == nil {
+		return
+	}
+
+	ctm := state.Tags.GetCurrentValues()
+	tagSet := ctm.Tags
+	if c.redisOptions != nil {
+		for key, val := range c.redisOptions.Tags {
+			tagSet = tagSet.With(key, val)
+		}
+	}
+	for key, val := range c.tags {
+		tagSet = tagSet.With(key, val)
+	}
+	for key, val := range tags {
+		tagSet = tagSet.With(key, val)
+	}
+
+	metrics.PushIfNotDone(c.vu.Context(), state.Samples, metrics.Sample{
+		TimeSeries: metrics.TimeSeries{
+			Metric: metric,
+			Tags:   tagSet,
+		},
+		Time:     time.Now(),
+		Metadata: ctm.Metadata,
+		Value:    value,
+	})
+}
@@ -0,0 +1,324 @@
+package redis
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/grafana/sobek"
+	"github.com/redis/go-redis/v9"
+)
+
+// copyOptions holds the options of the Client's copy method.
+type copyOptions struct {
+	// DB is the logical database to copy the key to. It defaults to the
+	// client's database.
+	DB *int64 `json:"db,omitempty"`
+
+	// Replace overwrites the destination key if it already exists.
+	Replace bool `json:"replace,omitempty"`
+}
+
+// Copy copies the value stored at `source` to the `destination` key.
+//
+// The promise resolves with true if the value was copied, and false if
+// the destination key already exists and the replace option isn't set.
+func (c *Client) Copy(source, destination string, options map[string]interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	var opts copyOptions
+	if err := decodeOptions(options, &opts); err != nil {
+		reject(fmt.Errorf("invalid copy options; reason: %w", err))
+		return promise
+	}
+
+	args := []interface{}{"copy", source, destination}
+	if opts.DB != nil {
+		if *opts.DB < 0 {
+			reject(fmt.Errorf("invalid copy options; db must not be negative; got %d", *opts.DB))
+			return promise
+		}
+		args = append(args, "db", *opts.DB)
+	}
+	if opts.Replace {
+		args = append(args, "replace")
+	}
+
+	go func() {
+		ctx := c.context()
+		cmd := redis.NewBoolCmd(ctx, args...)
+		_ = c.redisClient.Process(ctx, cmd)
+
+		copied, err := cmd.Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(copied)
+	}()
+
+	return promise
+}
+
+// Rename renames `key` to `newKey`, overwriting `newKey` if it exists.
+//
+// The promise resolves with "OK", and is rejected if `key` doesn't exist.
+func (c *Client) Rename(key, newKey string) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		result, err := c.redisClient.Rename(c.context(), key, newKey).Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(result)
+	}()
+
+	return promise
+}
+
+// Renamenx is like Rename, except that it doesn't overwrite `newKey`.
+//
+// The promise resolves with true if the key was renamed, and false if
+// `newKey` already exists.
+func (c *Client) Renamenx(key, newKey string) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		renamed, err := c.redisClient.RenameNX(c.context(), key, newKey).Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(renamed)
+	}()
+
+	return promise
+}
+
+// Dump serializes the value stored at `key` in the server's format, for
+// restore to recreate it, possibly on another server.
+//
+// The promise resolves with the serialized value as an ArrayBuffer, as it
+// is binary, or null if the key doesn't exist.
+func (c *Client) Dump(key string) *sobek.Promise {
+	promise, resolve, reject := c.newBufferPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		value, err := c.redisClient.Dump(c.context(), key).Result()
+		switch {
+		case errors.Is(err, redis.Nil):
+			resolve(nil)
+		case err != nil:
+			reject(err)
+		default:
+			resolve(value)
+		}
+	}()
+
+	return promise
+}
+
+// restoreOptions holds the options of the Client's restore method.
+type restoreOptions struct {
+	// Replace overwrites the key if it already exists.
+	Replace bool `json:"replace,omitempty"`
+
+	// AbsTTL interprets the ttl as an absolute Unix time, in milliseconds.
+	AbsTTL bool `json:"absTtl,omitempty"`
+}
+
+// Restore creates `key` holding the `value` serialized by Dump, which is
+// either an ArrayBuffer, or a Uint8Array. The key expires after `ttl`
+// milliseconds, unless it is 0.
+//
+// The promise resolves with "OK", and is rejected if the key already
+// exists and the replace option isn't set.
+func (c *Client) Restore(key string, ttl int64, value interface{}, options map[string]interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	var opts restoreOptions
+	if err := decodeOptions(options, &opts); err != nil {
+		reject(fmt.Errorf("invalid restore options; reason: %w", err))
+		return promise
+	}
+
+	if ttl < 0 {
+		reject(fmt.Errorf("restore's ttl must not be negative; got %d", ttl))
+		return promise
+	}
+
+	values, err := c.binaryArgs(2, value)
+	if err != nil {
+		reject(err)
+		return promise
+	}
+
+	args := []interface{}{"restore", key, ttl, values[0]}
+	if opts.Replace {
+		args = append(args, "replace")
+	}
+	if opts.AbsTTL {
+		args = append(args, "absttl")
+	}
+
+	go func() {
+		ctx := c.context()
+		cmd := redis.NewStatusCmd(ctx, args...)
+		_ = c.redisClient.Process(ctx, cmd)
+
+		result, err := cmd.Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(result)
+	}()
+
+	return promise
+}
+
+// defaultMigrateTimeoutMs is the default maximum idle time, in
+// milliseconds, of the communication between the servers during a
+// migration.
+const defaultMigrateTimeoutMs = 5000
+
+// migrateOptions holds the options of the Client's migrate method.
+type migrateOptions struct {
+	// DB is the logical database of the destination server to move the
+	// keys to.
+	DB int64 `json:"db,omitempty"`
+
+	// TimeoutMs is the maximum idle time, in milliseconds, of the
+	// communication with the destination server.
+	TimeoutMs int64 `json:"timeoutMs,omitempty"`
+
+	// Copy keeps the keys on the source server.
+	Copy bool `json:"copy,omitempty"`
+
+	// Replace overwrites the keys of the destination server.
+	Replace bool `json:"replace,omitempty"`
+
+	// Username and Password authenticate to the destination server.
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+}
+
+// args returns the arguments of MIGRATE the options translate to, for the
+// provided destination and keys.
+func (o migrateOptions) args(host string, port int, keys []string) ([]interface{}, error) {
+	if o.DB < 0 {
+		return nil, fmt.Errorf("db must not be negative; got %d", o.DB)
+	}
+
+	if o.TimeoutMs < 0 {
+		return nil, fmt.Errorf("timeoutMs must not be negative; got %d", o.TimeoutMs)
+	}
+
+	if o.Username != "" && o.Password == "" {
+		return nil, errors.New("username requires password")
+	}
+
+	timeoutMs := o.TimeoutMs
+	if timeoutMs == 0 {
+		timeoutMs = defaultMigrateTimeoutMs
+	}
+
+	// The keys are always provided with the KEYS form, which leaves the
+	// key argument empty.
+	args := []interface{}{"migrate", host, port, "", o.DB, timeoutMs}
+	if o.Copy {
+		args = append(args, "copy")
+	}
+	if o.Replace {
+		args = append(args, "replace")
+	}
+
+	switch {
+	case o.Username != "":
+		args = append(args, "auth2", o.Username, o.Password)
+	case o.Password != "":
+		args = append(args, "auth", o.Password)
+	}
+
+	return append(append(args, "keys"), stringsToArgs(keys)...), nil
+}
+
+// Migrate moves `keys` to the server listening on `host` and `port`, which
+// the server connected to sends them to directly. The keys are deleted
+// from the source server, unless the copy option is set.
+//
+// The promise resolves with "OK", or "NOKEY" if none of the keys exist.
+func (c *Client) Migrate(host string, port int, keys []string, options map[string]interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	if len(keys) == 0 {
+		reject(errors.New("migrate requires at least one key"))
+		return promise
+	}
+
+	var opts migrateOptions
+	if err := decodeOptions(options, &opts); err != nil {
+		reject(fmt.Errorf("invalid migrate options; reason: %w", err))
+		return promise
+	}
+
+	args, err := opts.args(host, port, keys)
+	if err != nil {
+		reject(fmt.Errorf("invalid migrate options; %w", err))
+		return promise
+	}
+
+	go func() {
+		ctx := c.context()
+		cmd := redis.NewStatusCmd(ctx, args...)
+		// Cluster clients route the command to the node serving the first
+		// key, rather than the empty key argument.
+		cmd.SetFirstKeyPos(int8(len(args) - len(keys)))
+		_ = c.redisClient.Process(ctx, cmd)
+
+		result, err := cmd.Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(result)
+	}()
+
+	return promise
+}
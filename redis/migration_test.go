@@ -0,0 +1,101 @@
+package redis
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientMigrationCommands(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunT(t)
+	rs.RegisterCommandHandler("COPY", func(c *Connection, _ []string) {
+		c.WriteInteger(1)
+	})
+	rs.RegisterCommandHandler("RENAME", func(c *Connection, _ []string) {
+		c.WriteOK()
+	})
+	rs.RegisterCommandHandler("RENAMENX", func(c *Connection, _ []string) {
+		c.WriteInteger(0)
+	})
+	rs.RegisterCommandHandler("DUMP", func(c *Connection, args []string) {
+		if args[0] == "missing" {
+			c.WriteNull()
+			return
+		}
+		c.WriteBulkString("\x00\x03bar\x0b\x00\xff")
+	})
+	rs.RegisterCommandHandler("RESTORE", func(c *Connection, args []string) {
+		if args[2] != "\x00\x03bar\x0b\x00\xff" {
+			c.WriteError(errors.New("ERR DUMP payload version or checksum are wrong"))
+			return
+		}
+		c.WriteOK()
+	})
+	rs.RegisterCommandHandler("MIGRATE", func(c *Connection, _ []string) {
+		c.WriteSimpleString("NOKEY")
+	})
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client('redis://%s');
+
+			redis.copy("foo", "bar", { db: 2, replace: true })
+				.then(res => { if (res !== true) { throw 'unexpected value for copy result: ' + res } })
+				.then(() => redis.rename("foo", "baz"))
+				.then(res => { if (res !== "OK") { throw 'unexpected value for rename result: ' + res } })
+				.then(() => redis.renamenx("baz", "bar"))
+				.then(res => { if (res !== false) { throw 'unexpected value for renamenx result: ' + res } })
+				.then(() => redis.dump("missing"))
+				.then(res => { if (res !== null) { throw 'unexpected value for dump result: ' + res } })
+				.then(() => redis.dump("baz"))
+				.then(payload => {
+					if (!(payload instanceof ArrayBuffer) || payload.byteLength !== 8) {
+						throw 'expected dump to resolve with an ArrayBuffer'
+					}
+					return redis.restore("qux", 1500, payload, { replace: true })
+				})
+				.then(res => { if (res !== "OK") { throw 'unexpected value for restore result: ' + res } })
+				.then(() => redis.migrate("10.0.0.2", 6380, ["baz", "qux"], { db: 1, copy: true, username: "mover", password: "secret" }))
+				.then(res => { if (res !== "NOKEY") { throw 'unexpected value for migrate result: ' + res } })
+				.then(() => redis.migrate("10.0.0.2", 6380, []))
+				.then(
+					res => { throw 'expected migrate to fail without keys' },
+					err => { if (err.error() !== "migrate requires at least one key") { throw 'unexpected error: ' + err.error() } }
+				)
+		`, rs.Addr()))
+
+		return err
+	})
+
+	assert.NoError(t, gotScriptErr)
+	assert.Equal(t, [][]string{
+		{"HELLO", "2"},
+		{"COPY", "foo", "bar", "db", "2", "replace"},
+		{"RENAME", "foo", "baz"},
+		{"RENAMENX", "baz", "bar"},
+		{"DUMP", "missing"},
+		{"DUMP", "baz"},
+		{"RESTORE", "qux", "1500", "\x00\x03bar\x0b\x00\xff", "replace"},
+		{"MIGRATE", "10.0.0.2", "6380", "", "1", "5000", "copy", "auth2", "mover", "secret", "keys", "baz", "qux"},
+	}, rs.GotCommands())
+}
+
+func TestMigrateOptionsArgs(t *testing.T) {
+	t.Parallel()
+
+	args, err := migrateOptions{TimeoutMs: 100, Replace: true, Password: "secret"}.args("localhost", 6379, []string{"foo"})
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"migrate", "localhost", 6379, "", int64(0), int64(100), "replace", "auth", "secret", "keys", "foo"}, args)
+
+	_, err = migrateOptions{Username: "mover"}.args("localhost", 6379, []string{"foo"})
+	assert.EqualError(t, err, "username requires password")
+
+	_, err = migrateOptions{TimeoutMs: -1}.args("localhost", 6379, []string{"foo"})
+	assert.Error(t, err)
+}
@@ -2,16 +2,22 @@
 package redis
 
 import (
+	"crypto/hmac"
+	"crypto/rand"
 	"crypto/sha1"
+	"crypto/sha256"
 	"encoding/base64"
 	"errors"
+	"fmt"
 	"slices"
 	"strings"
 	"sync"
 
 	"github.com/grafana/sobek"
 	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
 
+	"go.k6.io/k6/event"
 	"go.k6.io/k6/js/common"
 	"go.k6.io/k6/js/modules"
 )
@@ -22,12 +28,35 @@ type (
 	RootModule struct {
 		cm map[string]redis.UniversalClient
 		mu *sync.RWMutex
+
+		// trackers holds the invalidation trackers of the clients created
+		// with the clientTracking option, by options hash.
+		trackers map[string]*invalidationTracker
+
+		// hooks holds the clientHook of each go-redis client, by options
+		// hash, which counts the connections the client dialed.
+		hooks map[string]*clientHook
+
+		// refs counts the Clients connected through each go-redis client,
+		// by options hash, so that closing a Client only closes the
+		// go-redis client once no other Client uses it.
+		refs map[string]int
+
+		// closeOnExit subscribes to the Exit event, on which the go-redis
+		// clients are closed, once.
+		closeOnExit sync.Once
 	}
 
 	// ModuleInstance represents an instance of the JS module.
 	ModuleInstance struct {
-		vu                 modules.VU
-		getRedisClientFunc GetRedisClientFunc
+		vu                   modules.VU
+		getRedisClientFunc   GetRedisClientFunc
+		closeRedisClientFunc CloseRedisClientFunc
+		metrics              *redisMetrics
+
+		// lookupEnv looks up the test's environment variables, as exposed
+		// to scripts through __ENV.
+		lookupEnv func(key string) (string, bool)
 
 		*Client
 	}
@@ -42,53 +71,270 @@ var (
 // New returns a pointer to a new RootModule instance
 func New() *RootModule {
 	return &RootModule{
-		cm: make(map[string]redis.UniversalClient, 4),
-		mu: &sync.RWMutex{},
+		cm:       make(map[string]redis.UniversalClient, 4),
+		mu:       &sync.RWMutex{},
+		trackers: make(map[string]*invalidationTracker),
+		hooks:    make(map[string]*clientHook),
+		refs:     make(map[string]int),
 	}
 }
 
-type GetRedisClientFunc func(*redis.UniversalOptions) redis.UniversalClient
+type GetRedisClientFunc func(*universalOptions) redis.UniversalClient
 
-func optsToHash(opts *redis.UniversalOptions) string {
+// CloseRedisClientFunc releases the go-redis client returned by a
+// GetRedisClientFunc for the same options. It returns the number of
+// connections the go-redis client dialed, and closed, if it was closed.
+type CloseRedisClientFunc func(*universalOptions) (opened, closed int64, err error)
+
+// credentialKey keys the digests of the passwords mixed in the options
+// hash, which is reported by the options method, along with the other
+// options it is computed from: it is random, and specific to the process,
+// so that the passwords can't be checked against the hash offline.
+var credentialKey = newCredentialKey()
+
+// newCredentialKey returns a random key for credentialDigest.
+func newCredentialKey() []byte {
+	key := make([]byte, sha256.Size)
+	if _, err := rand.Read(key); err != nil {
+		panic(fmt.Sprintf("failed to generate the credential key; reason: %s", err))
+	}
+
+	return key
+}
+
+// credentialDigest returns the HMAC of `secret`, keyed with `key`.
+func credentialDigest(key []byte, secret string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(secret))
+
+	return mac.Sum(nil)
+}
+
+func optsToHash(opts *universalOptions) string {
 	slices.Sort(opts.Addrs)
-	sum := sha1.Sum([]byte(strings.Join(opts.Addrs, ",")))
+	key := strings.Join(opts.Addrs, ",")
+
+	// Clients routing commands, or connecting, differently must not share
+	// the same underlying go-redis client.
+	key += fmt.Sprintf("|%s|%t|%v|%d|%s|%t|%d|%t", opts.ReadPreference, opts.writesToMaster(),
+		opts.MaxCommandsPerSecond, opts.ReconnectJitterMs, opts.DialNetwork, opts.CapToVUDeadline,
+		opts.Protocol, opts.ClientTracking)
+
+	// Connections are bound to the logical database, and to the user,
+	// they were dialed with.
+	key += fmt.Sprintf("|db|%d|auth|%s|%x|%s|tls|%t|%s", opts.DB, opts.Username,
+		credentialDigest(credentialKey, opts.Password), opts.ClientName, opts.TLSConfig != nil, opts.tlsKey)
+
+	// Clients configuring their pool, or timeouts, differently get a pool
+	// of their own.
+	key += fmt.Sprintf("|pool|%d|%d|%d|%d|%d|%d|%d|%d|%d|%d|%d|%d", opts.MaxRetries, opts.MinRetryBackoff,
+		opts.MaxRetryBackoff, opts.DialTimeout, opts.ReadTimeout, opts.WriteTimeout, opts.PoolSize,
+		opts.MinIdleConns, opts.MaxIdleConns, opts.ConnMaxLifetime, opts.PoolTimeout, opts.ConnMaxIdleTime)
+	if opts.MasterName != "" {
+		key += fmt.Sprintf("|sentinel|%s|%s|%x|%t|%t", opts.MasterName, opts.SentinelUsername,
+			credentialDigest(credentialKey, opts.SentinelPassword), opts.failover.ReplicaOnly, opts.failover.UseDisconnectedReplicas)
+	}
+	if opts.isCluster() {
+		key += fmt.Sprintf("|cluster|%d|%t|%t|%t", opts.MaxRedirects,
+			opts.ReadOnly, opts.RouteByLatency, opts.RouteRandomly)
+	}
+
+	sum := sha1.Sum([]byte(key))
 	return base64.RawStdEncoding.EncodeToString(sum[:])
 }
 
-func (r *RootModule) GetRedisClient(opts *redis.UniversalOptions) redis.UniversalClient {
+func (r *RootModule) GetRedisClient(opts *universalOptions) redis.UniversalClient {
 	hash := optsToHash(opts)
 
-	r.mu.RLock()
-	client, found := r.cm[hash]
-	r.mu.RUnlock()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.refs[hash]++
 
+	client, found := r.cm[hash]
 	if found {
+		opts.tracker = r.trackers[hash]
 		return client
 	}
 
+	if opts.ClientTracking {
+		opts.tracker = newInvalidationTracker(opts.Simple())
+		opts.OnConnect = opts.tracker.enableTracking
+		r.trackers[hash] = opts.tracker
+	}
+
+	client = newUniversalClient(opts)
+	client.AddHook(callbackHook{})
+	client.AddHook(keyTrackingHook{})
+	client.AddHook(keyPrefixHook{})
+	client.AddHook(compressionHook{})
+	client.AddHook(writeGuardHook{})
+	client.AddHook(commandGuardHook{})
+	hook := newClientHook(opts)
+	client.AddHook(hook)
+	addNodeHooks(client, opts, hook)
+	r.cm[hash] = client
+	r.hooks[hash] = hook
+
+	return client
+}
+
+// CloseRedisClient releases the go-redis client GetRedisClient returned
+// for the provided options, and closes it, along with its connection pool,
+// once all the Clients which got it released it.
+func (r *RootModule) CloseRedisClient(opts *universalOptions) (opened, closed int64, err error) {
+	hash := optsToHash(opts)
+
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	client, found = r.cm[hash]
-	if found {
-		return client
+	if _, found := r.cm[hash]; !found {
+		return 0, 0, nil
+	}
+
+	r.refs[hash]--
+	if r.refs[hash] > 0 {
+		return 0, 0, nil
+	}
+
+	return r.closeRedisClient(hash)
+}
+
+// closeRedisClient closes the go-redis client of the options hash, and
+// forgets about it, so that Clients using the same options get a new one.
+// It must be called with the lock held.
+func (r *RootModule) closeRedisClient(hash string) (opened, closed int64, err error) {
+	client := r.cm[hash]
+	closed = int64(client.PoolStats().TotalConns)
+	opened = r.hooks[hash].dials.Load()
+
+	if tracker, ok := r.trackers[hash]; ok {
+		tracker.close()
+	}
+
+	delete(r.cm, hash)
+	delete(r.trackers, hash)
+	delete(r.hooks, hash)
+	delete(r.refs, hash)
+
+	return opened, closed, client.Close()
+}
+
+// closeRedisClients closes all the go-redis clients, when the k6 process is
+// about to exit, so that their connections don't outlive the test, such as
+// in the binaries embedding k6 running several tests.
+func (r *RootModule) closeRedisClients(logger logrus.FieldLogger) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for hash := range r.cm {
+		opened, closed, err := r.closeRedisClient(hash)
+		if err != nil {
+			logger.WithError(err).Warn("Failed to close a redis client")
+			continue
+		}
+
+		logger.WithFields(logrus.Fields{
+			"opened": opened,
+			"closed": closed,
+		}).Debug("Closed a redis client's connections")
+	}
+}
+
+// subscribeToExit closes the go-redis clients on the Exit event.
+func (r *RootModule) subscribeToExit(vu modules.VU) {
+	if vu.Events().Global == nil || vu.InitEnv() == nil {
+		return
+	}
+
+	logger := vu.InitEnv().Logger
+	id, events := vu.Events().Global.Subscribe(event.Exit)
+
+	go func() {
+		for ev := range events {
+			r.closeRedisClients(logger)
+			vu.Events().Global.Unsubscribe(id)
+			ev.Done()
+			return
+		}
+	}()
+}
+
+// newUniversalClient returns a new go-redis client matching the provided
+// options. It behaves like redis.NewUniversalClient, except for:
+//   - cluster options with a single seed node, which still get a
+//     ClusterClient, discovering the other nodes from the seed.
+//   - sentinel-backed clients routing read commands to replicas: depending
+//     on the writeToMaster option, either a replica-only FailoverClient, or a
+//     FailoverClusterClient, which routes write commands to the master, is
+//     returned.
+func newUniversalClient(opts *universalOptions) redis.UniversalClient {
+	// Commands are retried by the clientHook, which reports the retries,
+	// rather than by go-redis.
+	uopts := *opts.UniversalOptions
+	uopts.MaxRetries = -1
+	copts := *opts
+	copts.UniversalOptions = &uopts
+	opts = &copts
+
+	if opts.isCluster() {
+		return redis.NewClusterClient(opts.Cluster())
+	}
+
+	if opts.MasterName == "" {
+		return redis.NewUniversalClient(opts.UniversalOptions)
+	}
+
+	fopts := opts.Failover()
+	fopts.ReplicaOnly = opts.failover.ReplicaOnly
+	fopts.UseDisconnectedReplicas = opts.failover.UseDisconnectedReplicas
+	switch opts.ReadPreference {
+	case readPreferenceReplica:
+		fopts.ReplicaOnly = !opts.writesToMaster()
+	case readPreferencePreferReplica:
+		fopts.RouteByLatency = true
+		return redis.NewFailoverClusterClient(fopts)
 	}
 
-	r.cm[hash] = redis.NewUniversalClient(opts)
-	return r.cm[hash]
+	return redis.NewFailoverClient(fopts)
 }
 
 // NewModuleInstance implements the modules.Module interface and returns
 // a new instance for each VU.
 func (r *RootModule) NewModuleInstance(vu modules.VU) modules.Instance {
-	return &ModuleInstance{vu: vu, getRedisClientFunc: r.GetRedisClient, Client: &Client{vu: vu}}
+	m, err := registerMetrics(vu.InitEnv().Registry)
+	if err != nil {
+		common.Throw(vu.Runtime(), err)
+	}
+
+	// The environment is only reachable from the init context, while
+	// clients can be instantiated later on.
+	preInitState := vu.InitEnv().TestPreInitState
+	lookupEnv := func(key string) (string, bool) {
+		value, ok := preInitState.RuntimeOptions.Env[key]
+		return value, ok
+	}
+
+	r.closeOnExit.Do(func() { r.subscribeToExit(vu) })
+
+	return &ModuleInstance{
+		vu:                   vu,
+		getRedisClientFunc:   r.GetRedisClient,
+		closeRedisClientFunc: r.CloseRedisClient,
+		metrics:              m,
+		lookupEnv:            lookupEnv,
+		Client:               &Client{vu: vu, metrics: m},
+	}
 }
 
 // Exports implements the modules.Instance interface and returns
 // the exports of the JS module.
 func (mi *ModuleInstance) Exports() modules.Exports {
 	return modules.Exports{Named: map[string]interface{}{
-		"Client": mi.NewClient,
+		"Client":      mi.NewClient,
+		"ClientGroup": mi.NewClientGroup,
+		"randomValue": mi.RandomValue,
+		"randomKey":   mi.RandomKey,
 	}}
 }
 
@@ -103,9 +349,13 @@ func (mi *ModuleInstance) Exports() modules.Exports {
 // single-node Client is used.
 // Otherwise, an object is expected, and depending on its properties:
 // 1. If the masterName property is defined, a sentinel-backed FailoverClient is used.
-// 2. If the cluster property is defined, a ClusterClient is used.
+// 2. If the cluster property is defined, a ClusterClient is used, even with a
+// single seed node.
 // 3. Otherwise, a single-node Client is used.
 //
+// Without arguments, the options are read from the test's environment
+// variables, see readEnvOptions, so that credentials stay out of scripts.
+//
 // To support being instantiated in the init context, while not
 // producing any IO, as it is the convention in k6, the produced
 // Client is initially configured, but in a disconnected state.
@@ -114,19 +364,41 @@ func (mi *ModuleInstance) Exports() modules.Exports {
 func (mi *ModuleInstance) NewClient(call sobek.ConstructorCall) *sobek.Object {
 	rt := mi.vu.Runtime()
 
-	if len(call.Arguments) != 1 {
-		common.Throw(rt, errors.New("must specify one argument"))
+	if len(call.Arguments) > 1 {
+		common.Throw(rt, errors.New("must specify at most one argument"))
 	}
 
-	opts, err := readOptions(call.Arguments[0].Export())
+	var (
+		opts *universalOptions
+		err  error
+	)
+	if len(call.Arguments) == 0 {
+		opts, err = readEnvOptions(mi.lookupEnv)
+	} else {
+		opts, err = readOptions(call.Arguments[0].Export())
+	}
 	if err != nil {
 		common.Throw(rt, err)
 	}
 
 	client := &Client{
-		vu:             mi.vu,
-		redisOptions:   opts,
-		getRedisClient: mi.getRedisClientFunc,
+		vu:               mi.vu,
+		redisOptions:     opts,
+		getRedisClient:   mi.getRedisClientFunc,
+		closeRedisClient: mi.closeRedisClientFunc,
+		metrics:          mi.metrics,
+		stats:            &latencyStats{},
+		hooks:            &commandHooks{},
+		trackedKeys:      &trackedKeys{},
+	}
+
+	if opts.AutoCleanup {
+		client.cleanupOnIterationEnd()
+	}
+
+	if opts.Blocking {
+		client.syncCalls = &syncCalls{vu: mi.vu}
+		return client.syncCalls.wrap(client).ToObject(rt)
 	}
 
 	return rt.ToValue(client).ToObject(rt)
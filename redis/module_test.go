@@ -0,0 +1,148 @@
+package redis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	logtest "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewUniversalClient(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		options map[string]interface{}
+		wantFn  func(t *testing.T, client redis.UniversalClient)
+	}{
+		{
+			name: "sentinel with primary read preference uses a failover client",
+			options: map[string]interface{}{
+				"socket":     map[string]interface{}{"host": "localhost", "port": 26379},
+				"masterName": "mymaster",
+			},
+			wantFn: func(t *testing.T, client redis.UniversalClient) {
+				assert.IsType(t, &redis.Client{}, client)
+			},
+		},
+		{
+			name: "sentinel with preferReplica read preference routes writes to the master",
+			options: map[string]interface{}{
+				"socket":         map[string]interface{}{"host": "localhost", "port": 26379},
+				"masterName":     "mymaster",
+				"readPreference": "preferReplica",
+			},
+			wantFn: func(t *testing.T, client redis.UniversalClient) {
+				assert.IsType(t, &redis.ClusterClient{}, client)
+			},
+		},
+		{
+			name: "sentinel with replica read preference and writes to replicas uses a failover client",
+			options: map[string]interface{}{
+				"socket":         map[string]interface{}{"host": "localhost", "port": 26379},
+				"masterName":     "mymaster",
+				"readPreference": "replica",
+				"writeToMaster":  false,
+			},
+			wantFn: func(t *testing.T, client redis.UniversalClient) {
+				assert.IsType(t, &redis.Client{}, client)
+			},
+		},
+		{
+			name: "cluster with replica read preference sets the read-only option",
+			options: map[string]interface{}{
+				"readPreference": "replica",
+				"cluster": map[string]interface{}{
+					"nodes": []interface{}{"redis://host1:6379", "redis://host2:6379"},
+				},
+			},
+			wantFn: func(t *testing.T, client redis.UniversalClient) {
+				require.IsType(t, &redis.ClusterClient{}, client)
+				opts := client.(*redis.ClusterClient).Options() //nolint:forcetypeassert
+				assert.True(t, opts.ReadOnly)
+				assert.False(t, opts.RouteByLatency)
+			},
+		},
+		{
+			name: "cluster with a single seed node uses a cluster client",
+			options: map[string]interface{}{
+				"cluster": map[string]interface{}{
+					"nodes":         []interface{}{"redis://host1:6379"},
+					"maxRedirects":  5,
+					"routeRandomly": true,
+				},
+			},
+			wantFn: func(t *testing.T, client redis.UniversalClient) {
+				require.IsType(t, &redis.ClusterClient{}, client)
+				opts := client.(*redis.ClusterClient).Options() //nolint:forcetypeassert
+				assert.Equal(t, []string{"host1:6379"}, opts.Addrs)
+				assert.Equal(t, 5, opts.MaxRedirects)
+				assert.True(t, opts.RouteRandomly)
+				assert.True(t, opts.ReadOnly)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			opts, err := readOptions(tc.options)
+			require.NoError(t, err)
+
+			client := newUniversalClient(opts)
+			t.Cleanup(func() { _ = client.Close() })
+
+			tc.wantFn(t, client)
+		})
+	}
+}
+
+func TestRootModuleCloseRedisClients(t *testing.T) {
+	t.Parallel()
+
+	rs := RunT(t)
+
+	newOptions := func() *universalOptions {
+		opts, err := newOptionsFromString("redis://" + rs.Addr().String())
+		require.NoError(t, err)
+		return opts
+	}
+
+	r := New()
+	first := r.GetRedisClient(newOptions())
+	second := r.GetRedisClient(newOptions())
+	require.Same(t, first, second)
+	require.NoError(t, first.Ping(context.Background()).Err())
+
+	// The go-redis client is closed once all the Clients released it.
+	opened, closed, err := r.CloseRedisClient(newOptions())
+	require.NoError(t, err)
+	assert.Zero(t, opened)
+	assert.Zero(t, closed)
+	require.NoError(t, first.Ping(context.Background()).Err())
+
+	opened, closed, err = r.CloseRedisClient(newOptions())
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), opened)
+	assert.Equal(t, int64(1), closed)
+	assert.ErrorIs(t, first.Ping(context.Background()).Err(), redis.ErrClosed)
+
+	// Exiting closes the remaining go-redis clients.
+	third := r.GetRedisClient(newOptions())
+	require.NotSame(t, first, third)
+
+	logger, hook := logtest.NewNullLogger()
+	logger.SetLevel(logrus.DebugLevel)
+	r.closeRedisClients(logger)
+
+	assert.ErrorIs(t, third.Ping(context.Background()).Err(), redis.ErrClosed)
+	assert.Empty(t, r.cm)
+	require.Len(t, hook.AllEntries(), 1)
+	assert.Equal(t, "Closed a redis client's connections", hook.LastEntry().Message)
+}
@@ -2,16 +2,255 @@ package redis
 
 import (
 	"bytes"
+	"crypto/sha1"
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net"
+	"net/url"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/redis/go-redis/v9"
+	"go.k6.io/k6/js/common"
 )
 
+// clientOptions holds the options applying to the Client as a whole, whatever
+// mode (single-node, cluster, or sentinel) it operates in. They are expected
+// at the top level of the options object, and have no direct counterpart in
+// go-redis' options.
+type clientOptions struct {
+	// WriteToMaster ensures write commands are never routed to a replica.
+	// It defaults to true.
+	WriteToMaster *bool `json:"writeToMaster,omitempty"`
+
+	// ReadPreference determines which nodes read-only commands are routed to.
+	ReadPreference readPreference `json:"readPreference,omitempty"`
+
+	// MaxCommandsPerSecond caps the rate at which commands are sent, across
+	// all the VUs sharing the same client options. Commands wait for their
+	// turn, as long as the VU's context allows.
+	MaxCommandsPerSecond float64 `json:"maxCommandsPerSecond,omitempty"`
+
+	// CommandChunkSize caps the number of elements (keys, members, etc.)
+	// sent in a single variadic command. Larger calls are split in chunks,
+	// sent as pipelined commands. Zero disables chunking.
+	CommandChunkSize int `json:"commandChunkSize,omitempty"`
+
+	// ReconnectJitterMs is the maximum random delay, in milliseconds,
+	// waited before dialing new connections, so that clients reconnecting
+	// at once, for instance after a failover, don't do so in lockstep.
+	ReconnectJitterMs int64 `json:"reconnectJitterMs,omitempty"`
+
+	// DialNetwork restricts the address family connections are dialed
+	// with: "tcp4" for IPv4 only, "tcp6" for IPv6 only, or "tcp" for
+	// either. It defaults to "tcp", and is set to "unix" for the clients
+	// connecting through a Unix domain socket, see setUnixNetwork.
+	DialNetwork string `json:"dialNetwork,omitempty"`
+
+	// CapToVUDeadline caps the read and write timeouts of commands to the
+	// deadline of the VU's context, that is the end of the scenario's
+	// graceful stop, so that commands never outlive the test.
+	CapToVUDeadline bool `json:"capToVUDeadline,omitempty"`
+
+	// CommandTimeout is the time, in milliseconds, commands are given to
+	// complete, waiting for a connection and retries included, after which
+	// they fail. Zero disables it.
+	CommandTimeout int64 `json:"commandTimeout,omitempty"`
+
+	// CollectCommandHistogram makes the Client tally the commands it
+	// sends, by name and size, as reported by its commandHistogram method.
+	CollectCommandHistogram bool `json:"collectCommandHistogram,omitempty"`
+
+	// ProtocolVersion is the version of the RESP protocol connections
+	// negotiate with Redis: 2, the default, or 3.
+	ProtocolVersion int `json:"protocol,omitempty"`
+
+	// ClientTracking enables Redis' client-side caching tracking, so that
+	// the values read with the cacheMs option are invalidated as soon as
+	// their key is modified, rather than only once they expire. It is only
+	// supported by single-node clients.
+	ClientTracking bool `json:"clientTracking,omitempty"`
+
+	// KeyPrefix is prepended to the keys of every command the Client
+	// sends, so that tests sharing a Redis instance don't collide, see
+	// keyPrefixHook.
+	KeyPrefix string `json:"keyPrefix,omitempty"`
+
+	// AllowWriteCommands, when false, makes the Client fail the commands
+	// modifying data, or the server's state, before they are sent, as a
+	// safety net against shared instances. It defaults to true.
+	AllowWriteCommands *bool `json:"allowWriteCommands,omitempty"`
+
+	// AllowedCommands, when not empty, makes the Client fail the commands
+	// it doesn't list, before they are sent, see commandGuardHook. Entries
+	// are command names, such as "get", or a command name along with a
+	// subcommand, such as "config get".
+	AllowedCommands []string `json:"allowedCommands,omitempty"`
+
+	// BlockedCommands makes the Client fail the commands it lists, as
+	// AllowedCommands does for those it doesn't.
+	BlockedCommands []string `json:"blockedCommands,omitempty"`
+
+	// AllowFlush enables the Client's flushDb and flushAll methods, which
+	// throw otherwise, as an explicit opt-in.
+	AllowFlush bool `json:"allowFlush,omitempty"`
+
+	// Blocking makes the Client's methods wait for the results of their
+	// commands, and return them, rather than return promises.
+	Blocking bool `json:"blocking,omitempty"`
+
+	// Tags are added to the tags of the metrics emitted on behalf of the
+	// Client, such as {service: "sessions"}, see withTags.
+	Tags map[string]string `json:"tags,omitempty"`
+
+	// Compression is the algorithm the values written by the string and
+	// hash commands are compressed with, "gzip" or "snappy", see
+	// compressionHook. Values are left uncompressed when unset.
+	Compression string `json:"compression,omitempty"`
+
+	// CompressionThreshold is the size, in bytes, from which values are
+	// compressed. It defaults to defaultCompressionThreshold.
+	CompressionThreshold *int `json:"compressionThreshold,omitempty"`
+
+	// TrackKeys makes the Client record the keys it writes, so that
+	// cleanupIteration deletes them, see keyTrackingHook.
+	TrackKeys bool `json:"trackKeys,omitempty"`
+
+	// AutoCleanup deletes the keys recorded with the TrackKeys option at
+	// the end of each iteration, and implies it.
+	AutoCleanup bool `json:"autoCleanup,omitempty"`
+
+	// ReturnTypes is how the values read by the string and hash methods
+	// are typed: "string", the default, or "auto", see typedValue.
+	ReturnTypes string `json:"returnTypes,omitempty"`
+
+	// LogCommands makes the Client log each command it sends, along with
+	// its keys, duration, and outcome, at debug level, see logCommand.
+	LogCommands bool `json:"logCommands,omitempty"`
+
+	// LogValueLength is the length, in bytes, the arguments of the logged
+	// commands are truncated to. It defaults to defaultLogValueLength, and
+	// zero disables the truncation.
+	LogValueLength *int `json:"logValueLength,omitempty"`
+}
+
+// writesToMaster returns whether write commands are to be routed to the master
+// node exclusively.
+func (o clientOptions) writesToMaster() bool {
+	return o.WriteToMaster == nil || *o.WriteToMaster
+}
+
+// compressionThreshold returns the size, in bytes, from which values are
+// compressed.
+func (o clientOptions) compressionThreshold() int {
+	if o.CompressionThreshold == nil {
+		return defaultCompressionThreshold
+	}
+
+	return *o.CompressionThreshold
+}
+
+// returnTypes returns how the values read by the string and hash methods
+// are typed.
+func (o clientOptions) returnTypes() string {
+	if o.ReturnTypes == "" {
+		return returnTypesString
+	}
+
+	return o.ReturnTypes
+}
+
+// logValueLength returns the length, in bytes, the arguments of the logged
+// commands are truncated to, or zero if they aren't.
+func (o clientOptions) logValueLength() int {
+	if o.LogValueLength == nil {
+		return defaultLogValueLength
+	}
+
+	return *o.LogValueLength
+}
+
+// tracksKeys returns whether the keys written by the Client are recorded.
+func (o clientOptions) tracksKeys() bool {
+	return o.TrackKeys || o.AutoCleanup
+}
+
+// allowsWriteCommands returns whether the commands modifying data are to be
+// sent.
+func (o clientOptions) allowsWriteCommands() bool {
+	return o.AllowWriteCommands == nil || *o.AllowWriteCommands
+}
+
+// commandsReport returns a copy of the allowedCommands, or blockedCommands,
+// option, as reported by the Client's options method.
+func commandsReport(commands []string) []string {
+	return append([]string{}, commands...)
+}
+
+// tagsReport returns a copy of the tags option, as reported by the
+// Client's options method.
+func tagsReport(tags map[string]string) map[string]string {
+	report := make(map[string]string, len(tags))
+	for name, value := range tags {
+		report[name] = value
+	}
+
+	return report
+}
+
+// readPreference determines which nodes read-only commands are routed to, in
+// a deployment with replicas.
+type readPreference string
+
+const (
+	// readPreferencePrimary routes read commands to the master node.
+	readPreferencePrimary readPreference = "primary"
+
+	// readPreferenceReplica routes read commands to replica nodes.
+	readPreferenceReplica readPreference = "replica"
+
+	// readPreferencePreferReplica routes read commands to the closest node,
+	// be it a replica or the master.
+	readPreferencePreferReplica readPreference = "preferReplica"
+)
+
+// universalOptions are the resolved options of a Client: go-redis' universal
+// options, and the client options go-redis has no notion of.
+type universalOptions struct {
+	*redis.UniversalOptions
+	clientOptions
+
+	// failover holds the sentinel options go-redis' universal options have
+	// no counterpart for.
+	failover failoverOptions
+
+	// cluster is set when the options were provided through the cluster
+	// property, so that a cluster client is used even with a single seed
+	// node, from which go-redis discovers the rest of the cluster.
+	cluster bool
+
+	// tlsKey identifies the TLS options the client was created with, as
+	// go-redis' TLS configuration can't be compared.
+	tlsKey string
+
+	// tracker is the invalidation tracker of the underlying go-redis
+	// client, set by RootModule.GetRedisClient with the clientTracking
+	// option.
+	tracker *invalidationTracker
+}
+
+// isCluster returns whether the options are those of a cluster client.
+func (o *universalOptions) isCluster() bool {
+	return o.MasterName == "" && (o.cluster || len(o.Addrs) > 1)
+}
+
 type singleNodeOptions struct {
 	Socket          *socketOptions `json:"socket,omitempty"`
 	Username        string         `json:"username,omitempty"`
@@ -33,26 +272,52 @@ func (opts singleNodeOptions) toRedisOptions() (*redis.Options, error) {
 	ropts.DB = opts.Database
 	ropts.Username = opts.Username
 	ropts.Password = opts.Password
+	if opts.MaxRetries < -1 || opts.MinRetryBackoff < -1 || opts.MaxRetryBackoff < -1 {
+		return nil, errors.New("the maxRetries, minRetryBackoff, and maxRetryBackoff options must be positive numbers, or -1")
+	}
+
 	ropts.MaxRetries = opts.MaxRetries
-	ropts.MinRetryBackoff = time.Duration(opts.MinRetryBackoff)
-	ropts.MaxRetryBackoff = time.Duration(opts.MaxRetryBackoff)
+	ropts.MinRetryBackoff = retryBackoffOption(opts.MinRetryBackoff)
+	ropts.MaxRetryBackoff = retryBackoffOption(opts.MaxRetryBackoff)
 
 	return ropts, nil
 }
 
+// retryBackoffOption converts a retry backoff option, in milliseconds, to a
+// duration, keeping -1, which disables the backoff, as is.
+func retryBackoffOption(ms int64) time.Duration {
+	if ms == -1 {
+		return -1
+	}
+
+	return time.Duration(ms) * time.Millisecond
+}
+
 type socketOptions struct {
-	Host               string      `json:"host,omitempty"`
-	Port               int         `json:"port,omitempty"`
+	Host string `json:"host,omitempty"`
+	Port int    `json:"port,omitempty"`
+
+	// Path is the path of the Unix domain socket to connect to, instead of
+	// the host and port.
+	Path string `json:"path,omitempty"`
+
 	TLS                *tlsOptions `json:"tls,omitempty"`
 	DialTimeout        int64       `json:"dialTimeout,omitempty"`
 	ReadTimeout        int64       `json:"readTimeout,omitempty"`
 	WriteTimeout       int64       `json:"writeTimeout,omitempty"`
 	PoolSize           int         `json:"poolSize,omitempty"`
 	MinIdleConns       int         `json:"minIdleConns,omitempty"`
+	MaxIdleConns       int         `json:"maxIdleConns,omitempty"`
 	MaxConnAge         int64       `json:"maxConnAge,omitempty"`
 	PoolTimeout        int64       `json:"poolTimeout,omitempty"`
 	IdleTimeout        int64       `json:"idleTimeout,omitempty"`
 	IdleCheckFrequency int64       `json:"idleCheckFrequency,omitempty"`
+
+	// ConnMaxLifetime is the go-redis name of MaxConnAge, in milliseconds.
+	ConnMaxLifetime int64 `json:"connMaxLifetime,omitempty"`
+
+	// ConnMaxIdleTime is the go-redis name of IdleTimeout, in milliseconds.
+	ConnMaxIdleTime int64 `json:"connMaxIdleTime,omitempty"`
 }
 
 type tlsOptions struct {
@@ -60,13 +325,32 @@ type tlsOptions struct {
 	CA   []string `json:"ca,omitempty"`
 	Cert string   `json:"cert,omitempty"`
 	Key  string   `json:"key,omitempty"`
+
+	// InsecureSkipVerify disables the verification of the server's
+	// certificate, regardless of k6's insecureSkipTLSVerify option.
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+
+	// ServerName is the name the server's certificate is verified against,
+	// when it differs from the host connected to, such as when connecting
+	// through an IP address, or a tunnel.
+	ServerName string `json:"serverName,omitempty"`
 }
 
 type commonClusterOptions struct {
-	MaxRedirects   int  `json:"maxRedirects,omitempty"`
-	ReadOnly       bool `json:"readOnly,omitempty"`
+	// MaxRedirects caps the number of MOVED and ASK redirections followed
+	// by a command. It defaults to 3, and -1 disables redirections.
+	MaxRedirects int `json:"maxRedirects,omitempty"`
+
+	// ReadOnly routes read-only commands to the replicas of the key's slot.
+	ReadOnly bool `json:"readOnly,omitempty"`
+
+	// RouteByLatency routes read-only commands to the node of the key's
+	// slot, master or replica, with the lowest latency. It implies ReadOnly.
 	RouteByLatency bool `json:"routeByLatency,omitempty"`
-	RouteRandomly  bool `json:"routeRandomly,omitempty"`
+
+	// RouteRandomly routes read-only commands to a random node of the key's
+	// slot, master or replica. It implies ReadOnly.
+	RouteRandomly bool `json:"routeRandomly,omitempty"`
 }
 
 type clusterNodesMapOptions struct {
@@ -81,17 +365,55 @@ type clusterNodesStringOptions struct {
 
 type sentinelOptions struct {
 	singleNodeOptions
+	failoverOptions
 	MasterName       string `json:"masterName,omitempty"`
 	SentinelUsername string `json:"sentinelUsername,omitempty"`
 	SentinelPassword string `json:"sentinelPassword,omitempty"`
 }
 
+// failoverOptions holds the options of sentinel-backed clients which are
+// only known to go-redis' failover options.
+type failoverOptions struct {
+	// ReplicaOnly routes all commands, writes included, to replicas.
+	ReplicaOnly bool `json:"replicaOnly,omitempty"`
+
+	// UseDisconnectedReplicas falls back to the replicas the sentinels
+	// report as disconnected from the master, when none is connected.
+	UseDisconnectedReplicas bool `json:"useDisconnectedReplicas,omitempty"`
+}
+
+// validate checks the failover options are consistent with the client
+// options.
+func (o failoverOptions) validate(copts clientOptions) error {
+	if !o.ReplicaOnly {
+		return nil
+	}
+
+	if copts.ReadPreference != "" {
+		return errors.New("the replicaOnly option cannot be combined with the readPreference option")
+	}
+
+	if copts.writesToMaster() {
+		return errors.New("the replicaOnly option requires writeToMaster to be false")
+	}
+
+	return nil
+}
+
 // newOptionsFromObject validates and instantiates an options struct from its
 // map representation as exported from sobek.Runtime.
-func newOptionsFromObject(obj map[string]interface{}) (*redis.UniversalOptions, error) {
-	var options interface{}
+func newOptionsFromObject(obj map[string]interface{}) (*universalOptions, error) {
+	copts, obj, err := splitClientOptions(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		options   interface{}
+		isCluster bool
+	)
 	if cluster, ok := obj["cluster"].(map[string]interface{}); ok {
-		obj = cluster
+		obj, isCluster = cluster, true
 		nodes, ok := cluster["nodes"].([]interface{})
 		if !ok {
 			return nil, fmt.Errorf("cluster nodes property must be an array; got %T", cluster["nodes"])
@@ -113,9 +435,313 @@ func newOptionsFromObject(obj map[string]interface{}) (*redis.UniversalOptions,
 		options = &singleNodeOptions{}
 	}
 
+	if err = decodeOptions(obj, &options); err != nil {
+		return nil, err
+	}
+
+	uopts, err := toUniversalOptions(options)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = copts.apply(uopts, isCluster); err != nil {
+		return nil, err
+	}
+
+	unix, err := isUnixSocket(options)
+	if err != nil {
+		return nil, err
+	}
+	if err = copts.setUnixNetwork(unix); err != nil {
+		return nil, err
+	}
+
+	if isCluster && uopts.DB != 0 {
+		return nil, fmt.Errorf("invalid database option: %d; cluster clients only support database 0", uopts.DB)
+	}
+
+	var fopts failoverOptions
+	if sopts, ok := options.(*sentinelOptions); ok {
+		fopts = sopts.failoverOptions
+		if err = fopts.validate(copts); err != nil {
+			return nil, err
+		}
+	}
+
+	tlsKey, err := tlsOptionsKey(options)
+	if err != nil {
+		return nil, err
+	}
+
+	return &universalOptions{
+		UniversalOptions: uopts,
+		clientOptions:    copts,
+		failover:         fopts,
+		cluster:          isCluster,
+		tlsKey:           tlsKey,
+	}, nil
+}
+
+// tlsOptionsKey returns a key identifying the TLS options of the nodes
+// described by the provided options, as decoded by newOptionsFromObject.
+func tlsOptionsKey(options interface{}) (string, error) {
+	var nodes []*singleNodeOptions
+	switch o := options.(type) {
+	case *singleNodeOptions:
+		nodes = append(nodes, o)
+	case *sentinelOptions:
+		nodes = append(nodes, &o.singleNodeOptions)
+	case *clusterNodesMapOptions:
+		nodes = o.Nodes
+	}
+
+	tlsOpts := make([]*tlsOptions, 0, len(nodes))
+	for _, node := range nodes {
+		if node.Socket != nil && node.Socket.TLS != nil {
+			tlsOpts = append(tlsOpts, node.Socket.TLS)
+		}
+	}
+	if len(tlsOpts) == 0 {
+		return "", nil
+	}
+
+	serialized, err := json.Marshal(tlsOpts)
+	if err != nil {
+		return "", fmt.Errorf("unable to serialize tls options; reason: %w", err)
+	}
+
+	sum := sha1.Sum(serialized)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// isUnixSocket returns whether the options, as decoded by
+// newOptionsFromObject, are those of a client connecting through a Unix
+// domain socket, which only single-node clients support.
+func isUnixSocket(options interface{}) (bool, error) {
+	errUnsupported := errors.New("unix domain sockets are only supported by single-node clients")
+
+	switch o := options.(type) {
+	case *singleNodeOptions:
+		return o.Socket != nil && o.Socket.Path != "", nil
+	case *sentinelOptions:
+		if o.Socket != nil && o.Socket.Path != "" {
+			return false, errUnsupported
+		}
+	case *clusterNodesMapOptions:
+		for _, node := range o.Nodes {
+			if node.Socket != nil && node.Socket.Path != "" {
+				return false, errUnsupported
+			}
+		}
+	case *clusterNodesStringOptions:
+		for _, node := range o.Nodes {
+			if strings.HasPrefix(node, "unix://") {
+				return false, errUnsupported
+			}
+		}
+	}
+
+	return false, nil
+}
+
+// setUnixNetwork sets the dialNetwork option to "unix" for the clients
+// connecting through a Unix domain socket, as go-redis' universal options
+// have no notion of the network.
+func (o *clientOptions) setUnixNetwork(unix bool) error {
+	if !unix {
+		return nil
+	}
+
+	if o.DialNetwork != "" {
+		return fmt.Errorf("the dialNetwork option (%q) cannot be combined with unix domain sockets", o.DialNetwork)
+	}
+	o.DialNetwork = "unix"
+
+	return nil
+}
+
+// sentinelSchemeSuffix is the suffix of the schemes of the URLs of
+// sentinel-backed clients, such as redis+sentinel.
+const sentinelSchemeSuffix = "+sentinel"
+
+// defaultSentinelPort is the port of the sentinels listed in URLs without
+// one.
+const defaultSentinelPort = "26379"
+
+// newOptionsFromString parses the expected URL into redis.UniversalOptions.
+//
+// Besides the single-node URLs go-redis parses, it accepts URLs listing
+// several comma-separated hosts, for cluster clients, and URLs of the
+// redis+sentinel, or rediss+sentinel, scheme, for sentinel-backed clients:
+//
+//	redis[s]://[[username][:password]@]host1[:port],host2[:port][?options]
+//	redis[s]+sentinel://[[username][:password]@]host1[:port][,host2[:port]]/masterName[/db][?options]
+//
+// Query parameters are the ones go-redis parses, such as db, pool_size, or
+// dial_timeout, along with max_redirects, read_only, route_by_latency, and
+// route_randomly for cluster clients, and sentinel_username and
+// sentinel_password for sentinel-backed ones.
+func newOptionsFromString(rawURL string) (*universalOptions, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	hosts := strings.Split(u.Host, ",")
+	if strings.HasSuffix(u.Scheme, sentinelSchemeSuffix) {
+		return newSentinelOptionsFromURL(u, hosts)
+	}
+	if len(hosts) > 1 {
+		return newClusterOptionsFromURL(u, hosts)
+	}
+
+	opts, err := redis.ParseURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	uopts, err := toUniversalOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var copts clientOptions
+	if err = copts.setUnixNetwork(opts.Network == "unix"); err != nil {
+		return nil, err
+	}
+
+	return &universalOptions{UniversalOptions: uopts, clientOptions: copts}, nil
+}
+
+// newClusterOptionsFromURL returns the options of the cluster client whose
+// seed nodes are the provided `hosts` of the URL `u`.
+func newClusterOptionsFromURL(u *url.URL, hosts []string) (*universalOptions, error) {
+	query := u.Query()
+
+	var (
+		copts commonClusterOptions
+		err   error
+	)
+	if copts.MaxRedirects, err = popIntQueryParam(query, "max_redirects"); err != nil {
+		return nil, err
+	}
+	for name, value := range map[string]*bool{
+		"read_only":        &copts.ReadOnly,
+		"route_by_latency": &copts.RouteByLatency,
+		"route_randomly":   &copts.RouteRandomly,
+	} {
+		if *value, err = popBoolQueryParam(query, name); err != nil {
+			return nil, err
+		}
+	}
+
+	nodes := make([]string, 0, len(hosts))
+	for _, host := range hosts {
+		node := *u
+		node.Host = host
+		node.RawQuery = query.Encode()
+		nodes = append(nodes, node.String())
+	}
+
+	uopts, err := toUniversalOptions(&clusterNodesStringOptions{commonClusterOptions: copts, Nodes: nodes})
+	if err != nil {
+		return nil, err
+	}
+
+	if uopts.DB != 0 {
+		return nil, fmt.Errorf("invalid database option: %d; cluster clients only support database 0", uopts.DB)
+	}
+
+	return &universalOptions{UniversalOptions: uopts, cluster: true}, nil
+}
+
+// newSentinelOptionsFromURL returns the options of the sentinel-backed
+// client whose sentinels are the provided `hosts` of the URL `u`.
+func newSentinelOptionsFromURL(u *url.URL, hosts []string) (*universalOptions, error) {
+	path := strings.FieldsFunc(u.Path, func(r rune) bool { return r == '/' })
+	if len(path) == 0 || len(path) > 2 {
+		return nil, fmt.Errorf("invalid sentinel URL path: %q; expected /masterName[/db]", u.Path)
+	}
+
+	query := u.Query()
+	sentinelUsername, sentinelPassword := query.Get("sentinel_username"), query.Get("sentinel_password")
+	query.Del("sentinel_username")
+	query.Del("sentinel_password")
+
+	// The options other than the sentinels' are parsed by go-redis, from
+	// the URL of the master, as if it was the first sentinel.
+	master := *u
+	master.Scheme = strings.TrimSuffix(u.Scheme, sentinelSchemeSuffix)
+	master.Host = hosts[0]
+	master.Path = "/" + strings.Join(path[1:], "/")
+	master.RawQuery = query.Encode()
+
+	ropts, err := redis.ParseURL(master.String())
+	if err != nil {
+		return nil, err
+	}
+
+	uopts, err := toUniversalOptions(ropts)
+	if err != nil {
+		return nil, err
+	}
+
+	uopts.Addrs = make([]string, 0, len(hosts))
+	for _, host := range hosts {
+		if _, _, err := net.SplitHostPort(host); err != nil {
+			host = net.JoinHostPort(host, defaultSentinelPort)
+		}
+		uopts.Addrs = append(uopts.Addrs, host)
+	}
+	uopts.MasterName = path[0]
+	uopts.SentinelUsername = sentinelUsername
+	uopts.SentinelPassword = sentinelPassword
+
+	return &universalOptions{UniversalOptions: uopts}, nil
+}
+
+// popIntQueryParam removes the integer query parameter `name` from `query`,
+// and returns its value, or zero if it isn't set.
+func popIntQueryParam(query url.Values, name string) (int, error) {
+	value := query.Get(name)
+	query.Del(name)
+	if value == "" {
+		return 0, nil
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s URL parameter: %q; expected an integer", name, value)
+	}
+
+	return parsed, nil
+}
+
+// popBoolQueryParam removes the boolean query parameter `name` from
+// `query`, and returns its value, or false if it isn't set.
+func popBoolQueryParam(query url.Values, name string) (bool, error) {
+	value := query.Get(name)
+	query.Del(name)
+	if value == "" {
+		return false, nil
+	}
+
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s URL parameter: %q; expected a boolean", name, value)
+	}
+
+	return parsed, nil
+}
+
+// decodeOptions decodes the provided options map, as exported from
+// sobek.Runtime, into the value pointed to by v.
+//
+// If the input map contains an unknown option, an error is returned.
+func decodeOptions(obj map[string]interface{}, v interface{}) error {
 	jsonStr, err := json.Marshal(obj)
 	if err != nil {
-		return nil, fmt.Errorf("unable to serialize options to JSON %w", err)
+		return fmt.Errorf("unable to serialize options to JSON %w", err)
 	}
 
 	// Instantiate a JSON decoder which will error on unknown
@@ -124,27 +750,163 @@ func newOptionsFromObject(obj map[string]interface{}) (*redis.UniversalOptions,
 	decoder := json.NewDecoder(bytes.NewReader(jsonStr))
 	decoder.DisallowUnknownFields()
 
-	err = decoder.Decode(&options)
-	if err != nil {
-		return nil, err
+	return decoder.Decode(v)
+}
+
+// splitClientOptions extracts the client options from the top level of the
+// provided options map. It returns them, along with the remaining,
+// mode-specific, options.
+func splitClientOptions(obj map[string]interface{}) (clientOptions, map[string]interface{}, error) {
+	var (
+		copts     clientOptions
+		clientObj = make(map[string]interface{})
+		modeObj   = make(map[string]interface{}, len(obj))
+		names     = jsonFieldNames(copts)
+	)
+
+	for key, value := range obj {
+		if slices.Contains(names, key) {
+			clientObj[key] = value
+		} else {
+			modeObj[key] = value
+		}
 	}
 
-	return toUniversalOptions(options)
+	if err := decodeOptions(clientObj, &copts); err != nil {
+		return copts, nil, err
+	}
+
+	return copts, modeObj, nil
 }
 
-// newOptionsFromString parses the expected URL into redis.UniversalOptions.
-func newOptionsFromString(url string) (*redis.UniversalOptions, error) {
-	opts, err := redis.ParseURL(url)
-	if err != nil {
-		return nil, err
+// jsonFieldNames returns the JSON names of the fields of the provided struct.
+func jsonFieldNames(v interface{}) []string {
+	t := reflect.TypeOf(v)
+	names := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// apply validates the client options, and sets the go-redis options
+// they translate to. `cluster` tells whether the options are those of a
+// cluster client.
+func (o clientOptions) apply(uopts *redis.UniversalOptions, cluster bool) error {
+	if o.MaxCommandsPerSecond < 0 {
+		return fmt.Errorf("invalid maxCommandsPerSecond option: %v; expected a positive number", o.MaxCommandsPerSecond)
+	}
+
+	if o.ReconnectJitterMs < 0 {
+		return fmt.Errorf("invalid reconnectJitterMs option: %d; expected a positive number", o.ReconnectJitterMs)
+	}
+
+	if o.CommandChunkSize < 0 {
+		return fmt.Errorf("invalid commandChunkSize option: %d; expected a positive number", o.CommandChunkSize)
+	}
+
+	if o.CommandTimeout < 0 {
+		return fmt.Errorf("invalid commandTimeout option: %d; expected a positive number", o.CommandTimeout)
+	}
+
+	if _, ok := o.Tags[""]; ok {
+		return errors.New("invalid tags option; tag names must not be empty")
+	}
+
+	for option, commands := range map[string][]string{
+		"allowedCommands": o.AllowedCommands,
+		"blockedCommands": o.BlockedCommands,
+	} {
+		for _, command := range commands {
+			if strings.TrimSpace(command) == "" {
+				return fmt.Errorf("invalid %s option; command names must not be empty", option)
+			}
+		}
+	}
+
+	switch o.Compression {
+	case "", "gzip", "snappy":
+	default:
+		return fmt.Errorf("invalid compression option: %q; expected %q or %q", o.Compression, "gzip", "snappy")
+	}
+
+	switch o.ReturnTypes {
+	case "", returnTypesString, returnTypesAuto:
+	default:
+		return fmt.Errorf("invalid returnTypes option: %q; expected %q or %q", o.ReturnTypes, returnTypesString, returnTypesAuto)
 	}
 
-	return toUniversalOptions(opts)
+	if o.compressionThreshold() < 0 {
+		return fmt.Errorf("invalid compressionThreshold option: %d; expected a positive number", o.compressionThreshold())
+	}
+
+	if o.logValueLength() < 0 {
+		return fmt.Errorf("invalid logValueLength option: %d; expected a positive number, or zero", o.logValueLength())
+	}
+
+	switch o.ProtocolVersion {
+	case 0:
+	case 2, 3:
+		uopts.Protocol = o.ProtocolVersion
+	default:
+		return fmt.Errorf("invalid protocol option: %d; expected 2 or 3", o.ProtocolVersion)
+	}
+
+	if o.ClientTracking && (uopts.MasterName != "" || cluster) {
+		return errors.New("the clientTracking option is only supported by single-node clients")
+	}
+
+	switch o.DialNetwork {
+	case "", "tcp", "tcp4", "tcp6":
+	default:
+		return fmt.Errorf("invalid dialNetwork option: %q; expected one of %q, %q, or %q",
+			o.DialNetwork, "tcp", "tcp4", "tcp6")
+	}
+
+	switch o.ReadPreference {
+	case "", readPreferencePrimary, readPreferenceReplica, readPreferencePreferReplica:
+	default:
+		return fmt.Errorf("invalid readPreference option: %q; expected one of %q, %q, or %q",
+			o.ReadPreference, readPreferencePrimary, readPreferenceReplica, readPreferencePreferReplica)
+	}
+
+	if o.ReadPreference == "" || o.ReadPreference == readPreferencePrimary {
+		return nil
+	}
+
+	switch {
+	case uopts.MasterName != "":
+		// Sentinel-backed clients are instantiated according to the read
+		// preference, see newUniversalClient.
+		if o.ReadPreference == readPreferenceReplica && o.writesToMaster() {
+			return errors.New("the replica readPreference option requires writeToMaster to be false " +
+				"in sentinel mode; use the preferReplica readPreference instead")
+		}
+	case cluster:
+		if uopts.ReadOnly || uopts.RouteByLatency || uopts.RouteRandomly {
+			return errors.New("the readPreference option cannot be combined with " +
+				"the readOnly, routeByLatency, or routeRandomly cluster options")
+		}
+
+		// Cluster clients always route write commands to the master
+		// of the key's slot, regardless of the writeToMaster option.
+		uopts.ReadOnly = true
+		uopts.RouteByLatency = o.ReadPreference == readPreferencePreferReplica
+	default:
+		return fmt.Errorf("the %s readPreference option is only supported in cluster and sentinel modes",
+			o.ReadPreference)
+	}
+
+	return nil
 }
 
-func readOptions(options interface{}) (*redis.UniversalOptions, error) {
+func readOptions(options interface{}) (*universalOptions, error) {
 	var (
-		opts *redis.UniversalOptions
+		opts *universalOptions
 		err  error
 	)
 	switch val := options.(type) {
@@ -163,12 +925,53 @@ func readOptions(options interface{}) (*redis.UniversalOptions, error) {
 	return opts, nil
 }
 
+// The environment variables the options of clients instantiated without
+// arguments are read from.
+const (
+	// envURL holds the URL of the server, as passed to the constructor.
+	envURL = "REDIS_URL"
+
+	// envUsername and envPassword hold the credentials, overriding those
+	// of the URL, if any.
+	envUsername = "REDIS_USERNAME"
+	envPassword = "REDIS_PASSWORD"
+)
+
+// readEnvOptions reads the options of clients instantiated without
+// arguments from the environment variables looked up with `lookupEnv`.
+func readEnvOptions(lookupEnv func(key string) (string, bool)) (*universalOptions, error) {
+	url, ok := lookupEnv(envURL)
+	if !ok || url == "" {
+		return nil, fmt.Errorf("no options provided, and the %s environment variable is not set", envURL)
+	}
+
+	opts, err := readOptions(url)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s environment variable; %w", envURL, err)
+	}
+
+	if username, ok := lookupEnv(envUsername); ok {
+		opts.Username = username
+	}
+	if password, ok := lookupEnv(envPassword); ok {
+		opts.Password = password
+	}
+
+	return opts, nil
+}
+
 func toUniversalOptions(options interface{}) (*redis.UniversalOptions, error) {
-	uopts := &redis.UniversalOptions{Protocol: 2}
+	// go-redis only caps the read and write timeouts of commands to the
+	// deadline of their context when told to. The context is the VU's,
+	// whose deadline is only kept with the capToVUDeadline option, see
+	// Client.context, along with the commandTimeout option's one.
+	uopts := &redis.UniversalOptions{Protocol: 2, ContextTimeoutEnabled: true}
 
 	switch o := options.(type) {
 	case *clusterNodesMapOptions:
-		setClusterOptions(uopts, &o.commonClusterOptions)
+		if err := setClusterOptions(uopts, &o.commonClusterOptions); err != nil {
+			return nil, err
+		}
 
 		for _, n := range o.Nodes {
 			ropts, err := n.toRedisOptions()
@@ -180,7 +983,9 @@ func toUniversalOptions(options interface{}) (*redis.UniversalOptions, error) {
 			}
 		}
 	case *clusterNodesStringOptions:
-		setClusterOptions(uopts, &o.commonClusterOptions)
+		if err := setClusterOptions(uopts, &o.commonClusterOptions); err != nil {
+			return nil, err
+		}
 
 		for _, n := range o.Nodes {
 			ropts, err := redis.ParseURL(n)
@@ -297,6 +1102,11 @@ func setConsistentOptions(uopts *redis.UniversalOptions, opts *redis.Options) er
 	}
 	uopts.MinIdleConns = opts.MinIdleConns
 
+	if uopts.MaxIdleConns != 0 && opts.MaxIdleConns != 0 && uopts.MaxIdleConns != opts.MaxIdleConns {
+		return fmt.Errorf("inconsistent maxIdleConns option: %d != %d", uopts.MaxIdleConns, opts.MaxIdleConns)
+	}
+	uopts.MaxIdleConns = opts.MaxIdleConns
+
 	if uopts.ConnMaxLifetime != 0 && opts.ConnMaxLifetime != 0 && uopts.ConnMaxLifetime != opts.ConnMaxLifetime {
 		return fmt.Errorf("inconsistent maxConnAge option: %d != %d", uopts.ConnMaxLifetime, opts.ConnMaxLifetime)
 	}
@@ -315,26 +1125,74 @@ func setConsistentOptions(uopts *redis.UniversalOptions, opts *redis.Options) er
 	return nil
 }
 
-func setClusterOptions(uopts *redis.UniversalOptions, opts *commonClusterOptions) {
+func setClusterOptions(uopts *redis.UniversalOptions, opts *commonClusterOptions) error {
+	if opts.MaxRedirects < -1 {
+		return fmt.Errorf("invalid maxRedirects option: %d; expected a positive number, or -1", opts.MaxRedirects)
+	}
+
 	uopts.MaxRedirects = opts.MaxRedirects
 	uopts.ReadOnly = opts.ReadOnly
 	uopts.RouteByLatency = opts.RouteByLatency
 	uopts.RouteRandomly = opts.RouteRandomly
+
+	return nil
+}
+
+// aliasedOption returns the value of an option which can be set under two
+// names, failing if it is set under both.
+func aliasedOption(name string, value int64, alias string, aliasValue int64) (int64, error) {
+	if value != 0 && aliasValue != 0 {
+		return 0, fmt.Errorf("the %s and %s options are aliases, and cannot be set together", name, alias)
+	}
+
+	if value != 0 {
+		return value, nil
+	}
+
+	return aliasValue, nil
 }
 
 func setSocketOptions(opts *redis.Options, sopts *socketOptions) error {
 	if sopts == nil {
 		return fmt.Errorf("empty socket options")
 	}
-	opts.Addr = fmt.Sprintf("%s:%d", sopts.Host, sopts.Port)
+	if sopts.Path != "" {
+		if sopts.Host != "" || sopts.Port != 0 {
+			return errors.New("the path socket option cannot be combined with the host and port options")
+		}
+		opts.Network = "unix"
+		opts.Addr = sopts.Path
+	} else {
+		opts.Addr = fmt.Sprintf("%s:%d", sopts.Host, sopts.Port)
+	}
 	opts.DialTimeout = time.Duration(sopts.DialTimeout) * time.Millisecond
 	opts.ReadTimeout = time.Duration(sopts.ReadTimeout) * time.Millisecond
 	opts.WriteTimeout = time.Duration(sopts.WriteTimeout) * time.Millisecond
 	opts.PoolSize = sopts.PoolSize
 	opts.MinIdleConns = sopts.MinIdleConns
-	opts.ConnMaxLifetime = time.Duration(sopts.MaxConnAge) * time.Millisecond
+	opts.MaxIdleConns = sopts.MaxIdleConns
 	opts.PoolTimeout = time.Duration(sopts.PoolTimeout) * time.Millisecond
-	opts.ConnMaxIdleTime = time.Duration(sopts.IdleTimeout) * time.Millisecond
+
+	if sopts.PoolSize < 0 || sopts.MinIdleConns < 0 || sopts.MaxIdleConns < 0 {
+		return errors.New("the poolSize, minIdleConns, and maxIdleConns options must be positive numbers")
+	}
+
+	if sopts.MaxIdleConns > 0 && sopts.MinIdleConns > sopts.MaxIdleConns {
+		return fmt.Errorf("the minIdleConns option (%d) cannot exceed the maxIdleConns option (%d)",
+			sopts.MinIdleConns, sopts.MaxIdleConns)
+	}
+
+	maxLifetime, err := aliasedOption("connMaxLifetime", sopts.ConnMaxLifetime, "maxConnAge", sopts.MaxConnAge)
+	if err != nil {
+		return err
+	}
+	opts.ConnMaxLifetime = time.Duration(maxLifetime) * time.Millisecond
+
+	maxIdleTime, err := aliasedOption("connMaxIdleTime", sopts.ConnMaxIdleTime, "idleTimeout", sopts.IdleTimeout)
+	if err != nil {
+		return err
+	}
+	opts.ConnMaxIdleTime = time.Duration(maxIdleTime) * time.Millisecond
 
 	if sopts.TLS != nil {
 		//nolint: gosec // ignore G402: TLS MinVersion too low
@@ -347,6 +1205,10 @@ func setSocketOptions(opts *redis.Options, sopts *socketOptions) error {
 			tlsCfg.RootCAs = caCertPool
 		}
 
+		if (sopts.TLS.Cert == "") != (sopts.TLS.Key == "") {
+			return errors.New("the tls cert and key options must be set together")
+		}
+
 		if sopts.TLS.Cert != "" && sopts.TLS.Key != "" {
 			clientCertPair, err := tls.X509KeyPair([]byte(sopts.TLS.Cert), []byte(sopts.TLS.Key))
 			if err != nil {
@@ -355,8 +1217,118 @@ func setSocketOptions(opts *redis.Options, sopts *socketOptions) error {
 			tlsCfg.Certificates = []tls.Certificate{clientCertPair}
 		}
 
+		tlsCfg.InsecureSkipVerify = sopts.TLS.InsecureSkipVerify
+		tlsCfg.ServerName = sopts.TLS.ServerName
+
 		opts.TLSConfig = tlsCfg
 	}
 
 	return nil
 }
+
+// redactedOption replaces the value of sensitive options, such as passwords,
+// in the options reported by Client.Options.
+const redactedOption = "[redacted]"
+
+// Options returns the effective options of the Client, as resolved from the
+// ones it was instantiated with, to help diagnosing misconfigurations.
+//
+// Durations are expressed in milliseconds, and zero values stand for
+// go-redis' defaults. Passwords are redacted. The returned `hash` identifies
+// the underlying go-redis client: clients reporting the same hash share the
+// same connection pool.
+func (c *Client) Options() map[string]interface{} {
+	if c.redisOptions == nil {
+		common.Throw(c.vu.Runtime(), errors.New("the client has no options"))
+	}
+
+	return c.redisOptions.report()
+}
+
+// report returns the options as an object, with sensitive values redacted.
+func (o *universalOptions) report() map[string]interface{} {
+	redact := func(secret string) string {
+		if secret == "" {
+			return ""
+		}
+		return redactedOption
+	}
+
+	mode := "single"
+	switch {
+	case o.MasterName != "":
+		mode = "sentinel"
+	case o.isCluster():
+		mode = "cluster"
+	}
+
+	readPreference := o.ReadPreference
+	if readPreference == "" {
+		readPreference = readPreferencePrimary
+	}
+
+	dialNetwork := o.DialNetwork
+	if dialNetwork == "" {
+		dialNetwork = "tcp"
+	}
+
+	return map[string]interface{}{
+		"mode":             mode,
+		"addrs":            append([]string(nil), o.Addrs...),
+		"db":               o.DB,
+		"username":         o.Username,
+		"password":         redact(o.Password),
+		"clientName":       o.ClientName,
+		"masterName":       o.MasterName,
+		"sentinelUsername": o.SentinelUsername,
+		"sentinelPassword": redact(o.SentinelPassword),
+		"protocol":         o.Protocol,
+		"tls":              o.TLSConfig != nil,
+		"maxRetries":       o.MaxRetries,
+		"minRetryBackoff":  o.MinRetryBackoff.Milliseconds(),
+		"maxRetryBackoff":  o.MaxRetryBackoff.Milliseconds(),
+		"dialTimeout":      o.DialTimeout.Milliseconds(),
+		"readTimeout":      o.ReadTimeout.Milliseconds(),
+		"writeTimeout":     o.WriteTimeout.Milliseconds(),
+		"poolSize":         o.PoolSize,
+		"minIdleConns":     o.MinIdleConns,
+		"maxIdleConns":     o.MaxIdleConns,
+		"maxConnAge":       o.ConnMaxLifetime.Milliseconds(),
+		"poolTimeout":      o.PoolTimeout.Milliseconds(),
+		"idleTimeout":      o.ConnMaxIdleTime.Milliseconds(),
+		"maxRedirects":     o.MaxRedirects,
+		"readOnly":         o.ReadOnly,
+		"routeByLatency":   o.RouteByLatency,
+		"routeRandomly":    o.RouteRandomly,
+
+		"replicaOnly":             o.failover.ReplicaOnly,
+		"useDisconnectedReplicas": o.failover.UseDisconnectedReplicas,
+
+		"writeToMaster":           o.writesToMaster(),
+		"readPreference":          string(readPreference),
+		"maxCommandsPerSecond":    o.MaxCommandsPerSecond,
+		"commandChunkSize":        o.CommandChunkSize,
+		"reconnectJitterMs":       o.ReconnectJitterMs,
+		"dialNetwork":             dialNetwork,
+		"capToVUDeadline":         o.CapToVUDeadline,
+		"commandTimeout":          o.CommandTimeout,
+		"collectCommandHistogram": o.CollectCommandHistogram,
+		"clientTracking":          o.ClientTracking,
+		"keyPrefix":               o.KeyPrefix,
+		"allowWriteCommands":      o.allowsWriteCommands(),
+		"allowedCommands":         commandsReport(o.AllowedCommands),
+		"blockedCommands":         commandsReport(o.BlockedCommands),
+		"allowFlush":              o.AllowFlush,
+		"blocking":                o.Blocking,
+		"tags":                    tagsReport(o.Tags),
+		"compression":             o.Compression,
+		"compressionThreshold":    o.compressionThreshold(),
+		"returnTypes":             o.returnTypes(),
+		"trackKeys":               o.tracksKeys(),
+		"autoCleanup":             o.AutoCleanup,
+		"logCommands":             o.LogCommands,
+		"logValueLength":          o.logValueLength(),
+
+		"hash": optsToHash(o),
+	}
+}
@@ -0,0 +1,167 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// multiKeyOptions holds the options of the Client's multi-key commands.
+type multiKeyOptions struct {
+	// Partial makes multi-key commands report the keys they failed for,
+	// rather than failing as a whole.
+	Partial bool `json:"partial,omitempty"`
+}
+
+// multiKeyArgs splits the arguments of a variadic multi-key command into
+// its keys, and its options, provided as a trailing object decoded into
+// `opts`. The keys can also be provided as a single array.
+func multiKeyArgs(args []interface{}, opts interface{}) ([]string, error) {
+	if len(args) > 0 {
+		if options, ok := args[len(args)-1].(map[string]interface{}); ok {
+			if err := decodeOptions(options, opts); err != nil {
+				return nil, err
+			}
+			args = args[:len(args)-1]
+		}
+	}
+
+	if len(args) == 1 {
+		if keys, ok := args[0].([]interface{}); ok {
+			args = keys
+		}
+	}
+
+	keys := make([]string, len(args))
+	for idx, arg := range args {
+		keys[idx] = fmt.Sprint(arg)
+	}
+
+	return keys, nil
+}
+
+// slotGroups groups the provided keys by cluster hash slot, so that each
+// group can be sent to the node serving it in a single command. The slots
+// are the ones of the keys prefixed with `prefix`, the keyPrefix option,
+// as they are once sent.
+func slotGroups(prefix string, keys []string) [][]string {
+	var (
+		groups  [][]string
+		indexes = make(map[int]int)
+	)
+
+	for _, key := range keys {
+		slot := keySlot(prefix + key)
+		idx, ok := indexes[slot]
+		if !ok {
+			idx = len(groups)
+			indexes[slot] = idx
+			groups = append(groups, nil)
+		}
+
+		groups[idx] = append(groups[idx], key)
+	}
+
+	return groups
+}
+
+// partialFailure describes a key a multi-key command failed for.
+func partialFailure(key string, err error) map[string]interface{} {
+	return map[string]interface{}{
+		"key":   key,
+		"kind":  errorKind(err),
+		"error": err.Error(),
+	}
+}
+
+// mgetPartial fetches the values of the provided keys with an MGET command
+// per cluster hash slot. The commands are pipelined, and as cluster clients
+// send each of them to the node serving its slot, the failure of a node
+// only affects the keys it serves.
+//
+// It returns an object holding the `results`, mapping each fetched key to
+// its value, or null if it doesn't exist, unless `omitNulls` is set, and
+// the `failures`, describing each key that could not be fetched, and the
+// kind of error preventing it.
+func (c *Client) mgetPartial(ctx context.Context, keys []string, omitNulls bool) map[string]interface{} {
+	groups := slotGroups(c.redisOptions.KeyPrefix, keys)
+	cmds := make([]*redis.SliceCmd, len(groups))
+
+	// The commands' errors are checked individually below.
+	_, _ = c.redisClient.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for idx, group := range groups {
+			cmds[idx] = pipe.MGet(ctx, group...)
+		}
+		return nil
+	})
+
+	results := make(map[string]interface{}, len(keys))
+	failures := make([]map[string]interface{}, 0)
+	for idx, group := range groups {
+		values, err := cmds[idx].Result()
+		for i, key := range group {
+			if err != nil {
+				failures = append(failures, partialFailure(key, err))
+				continue
+			}
+			if values[i] == nil && omitNulls {
+				continue
+			}
+
+			results[key] = values[i]
+		}
+	}
+
+	return map[string]interface{}{
+		"results":  results,
+		"failures": failures,
+	}
+}
+
+// msetPartial sets the provided keys to their respective values with an
+// MSET command per cluster hash slot, as mgetPartial does.
+//
+// It returns an object holding the `results`, listing the keys that were
+// set, and the `failures`, describing each key that could not be set, and
+// the kind of error preventing it.
+func (c *Client) msetPartial(ctx context.Context, values map[string]interface{}) map[string]interface{} {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	groups := slotGroups(c.redisOptions.KeyPrefix, keys)
+	cmds := make([]*redis.StatusCmd, len(groups))
+
+	// The commands' errors are checked individually below.
+	_, _ = c.redisClient.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for idx, group := range groups {
+			pairs := make([]interface{}, 0, 2*len(group))
+			for _, key := range group {
+				pairs = append(pairs, key, values[key])
+			}
+
+			cmds[idx] = pipe.MSet(ctx, pairs...)
+		}
+		return nil
+	})
+
+	results := make([]string, 0, len(keys))
+	failures := make([]map[string]interface{}, 0)
+	for idx, group := range groups {
+		if err := cmds[idx].Err(); err != nil {
+			for _, key := range group {
+				failures = append(failures, partialFailure(key, err))
+			}
+			continue
+		}
+
+		results = append(results, group...)
+	}
+
+	return map[string]interface{}{
+		"results":  results,
+		"failures": failures,
+	}
+}
@@ -0,0 +1,199 @@
+package redis
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientMset(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunT(t)
+	rs.RegisterCommandHandler("MSET", func(c *Connection, _ []string) {
+		c.WriteOK()
+	})
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client('redis://%s');
+
+			redis.mset({ foo: "bar" })
+				.then(res => { if (res !== "OK") { throw 'unexpected value for mset result: ' + res } })
+				.then(() => redis.mset(new Map([["baz", "qux"]])))
+				.then(res => { if (res !== "OK") { throw 'unexpected value for mset result: ' + res } })
+				.then(() => redis.mset({ foo: {} }))
+				.then(
+					res => { throw 'expected mset to fail' },
+					err => { if (!err.error().includes('unsupported type')) { throw 'unexpected error: ' + err.error() } }
+				)
+		`, rs.Addr()))
+
+		return err
+	})
+
+	assert.NoError(t, gotScriptErr)
+	assert.Equal(t, [][]string{
+		{"HELLO", "2"},
+		{"MSET", "foo", "bar"},
+		{"MSET", "baz", "qux"},
+	}, rs.GotCommands())
+}
+
+func TestClientPartialMultiKeyCommands(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	up, down := RunT(t), RunT(t)
+	registerClusterSlotsHandler(stubClusterShard{master: up}, stubClusterShard{master: down})
+	up.RegisterCommandHandler("MGET", func(c *Connection, args []string) {
+		values := make([]interface{}, len(args))
+		for idx, key := range args {
+			values[idx] = "value of " + key
+		}
+		c.WriteValue(values)
+	})
+	up.RegisterCommandHandler("MSET", func(c *Connection, _ []string) {
+		c.WriteOK()
+	})
+
+	// Find a key served by each shard: the first one serves the lower half
+	// of the slots, and the second one the upper half.
+	var upKey, downKey string
+	for i := 0; upKey == "" || downKey == ""; i++ {
+		key := fmt.Sprintf("key:%d", i)
+		if keySlot(key) < clusterSlotsCount/2 {
+			upKey = key
+		} else {
+			downKey = key
+		}
+	}
+
+	// Let the client learn the cluster's layout from the node that stays up.
+	downAddr := down.Addr()
+	down.Close()
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client({
+				cluster: {
+					nodes: ['redis://%s', 'redis://%s'],
+				},
+				maxRetries: -1,
+			});
+
+			redis.mget("%s", "%s", { partial: true })
+				.then(res => {
+					if (res.results["%s"] !== "value of %s" || Object.keys(res.results).length !== 1) {
+						throw 'unexpected mget results: ' + JSON.stringify(res.results)
+					}
+
+					if (res.failures.length !== 1 || res.failures[0].key !== "%s" || res.failures[0].kind !== "connection") {
+						throw 'unexpected mget failures: ' + JSON.stringify(res.failures)
+					}
+				})
+				.then(() => redis.mset({ "%s": "a", "%s": "b" }, { partial: true }))
+				.then(res => {
+					if (res.results.length !== 1 || res.results[0] !== "%s") {
+						throw 'unexpected mset results: ' + JSON.stringify(res.results)
+					}
+
+					if (res.failures.length !== 1 || res.failures[0].key !== "%s" || res.failures[0].kind !== "connection") {
+						throw 'unexpected mset failures: ' + JSON.stringify(res.failures)
+					}
+				})
+		`, up.Addr(), downAddr, upKey, downKey, upKey, upKey, downKey, upKey, downKey, upKey, downKey))
+
+		return err
+	})
+
+	assert.NoError(t, gotScriptErr)
+}
+
+func TestClientPartialMultiKeyCommandsWithKeyPrefix(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	up, down := RunT(t), RunT(t)
+	registerClusterSlotsHandler(stubClusterShard{master: up}, stubClusterShard{master: down})
+	up.RegisterCommandHandler("MGET", func(c *Connection, args []string) {
+		values := make([]interface{}, len(args))
+		for idx, key := range args {
+			values[idx] = "value of " + key
+		}
+		c.WriteValue(values)
+	})
+
+	// Find two keys sharing a slot, whose prefixed names are served by
+	// different shards, so that grouping them by the slots of their
+	// unprefixed names would send them in a single MGET.
+	const prefix = "app:"
+	var upKey, downKey string
+	keys := make(map[int]string)
+	for i := 0; upKey == ""; i++ {
+		key := fmt.Sprintf("key:%d", i)
+		other, ok := keys[keySlot(key)]
+		if !ok {
+			keys[keySlot(key)] = key
+			continue
+		}
+
+		switch {
+		case keySlot(prefix+key) < clusterSlotsCount/2 && keySlot(prefix+other) >= clusterSlotsCount/2:
+			upKey, downKey = key, other
+		case keySlot(prefix+key) >= clusterSlotsCount/2 && keySlot(prefix+other) < clusterSlotsCount/2:
+			upKey, downKey = other, key
+		}
+	}
+
+	// Let the client learn the cluster's layout from the node that stays up.
+	downAddr := down.Addr()
+	down.Close()
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client({
+				cluster: {
+					nodes: ['redis://%s', 'redis://%s'],
+				},
+				keyPrefix: '%s',
+				maxRetries: -1,
+			});
+
+			redis.mget("%s", "%s", { partial: true })
+				.then(res => {
+					if (res.results["%s"] !== "value of %s%s" || Object.keys(res.results).length !== 1) {
+						throw 'unexpected mget results: ' + JSON.stringify(res.results)
+					}
+
+					if (res.failures.length !== 1 || res.failures[0].key !== "%s" || res.failures[0].kind !== "connection") {
+						throw 'unexpected mget failures: ' + JSON.stringify(res.failures)
+					}
+				})
+		`, up.Addr(), downAddr, prefix, upKey, downKey, upKey, prefix, upKey, downKey))
+
+		return err
+	})
+
+	assert.NoError(t, gotScriptErr)
+}
+
+func TestSlotGroups(t *testing.T) {
+	t.Parallel()
+
+	groups := slotGroups("", []string{"{user:1}:name", "{user:2}:name", "{user:1}:email"})
+
+	assert.Equal(t, [][]string{
+		{"{user:1}:name", "{user:1}:email"},
+		{"{user:2}:name"},
+	}, groups)
+
+	// The keys are grouped by the slots of their prefixed names.
+	groups = slotGroups("{tenant}:", []string{"{user:1}:name", "{user:2}:name"})
+
+	assert.Equal(t, [][]string{
+		{"{user:1}:name", "{user:2}:name"},
+	}, groups)
+}
@@ -0,0 +1,310 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/grafana/sobek"
+	"github.com/redis/go-redis/v9"
+	"go.k6.io/k6/js/common"
+)
+
+// Pipeline buffers commands, and sends them to Redis in a single round-trip
+// when executed.
+//
+// Its methods queue a command and return the pipeline itself, so that calls
+// can be chained. They throw if the command's arguments are not of a
+// supported type, or its options are invalid.
+type Pipeline struct {
+	client *Client
+
+	// cmds holds the commands queued since the pipeline was last executed.
+	cmds []queuedCommand
+
+	// tx makes the pipeline execute its commands in a MULTI/EXEC
+	// transaction.
+	tx bool
+
+	// watched marks the pipelines handed to watch callbacks, whose
+	// commands are executed by watch itself.
+	watched bool
+}
+
+// queuedCommand is a command queued on a pipeline.
+type queuedCommand struct {
+	args []interface{}
+
+	// decode, if set, converts the command's reply to the value the
+	// command resolves with outside of a pipeline, so that pipelined
+	// commands resolve with the same types.
+	decode func(reply interface{}) (interface{}, error)
+}
+
+// Pipeline returns a new, empty, pipeline sending its commands through the
+// client.
+func (c *Client) Pipeline() *Pipeline {
+	return &Pipeline{client: c}
+}
+
+// Set queues a SET command. The third argument is either the expiration,
+// interpreted as seconds, or an object of the options of the client's set
+// method.
+func (p *Pipeline) Set(key string, value interface{}, expirationOrOptions interface{}) *Pipeline {
+	p.checkSupportedType(1, value)
+
+	optArgs, err := setArgs(expirationOrOptions)
+	if err != nil {
+		p.throw(err)
+	}
+
+	return p.queue(append([]interface{}{"set", key, value}, optArgs...)...)
+}
+
+// Get queues a GET command.
+func (p *Pipeline) Get(key string) *Pipeline {
+	return p.queueDecoded(p.typedReply, "get", key)
+}
+
+// Del queues a DEL command.
+func (p *Pipeline) Del(keys ...string) *Pipeline {
+	return p.queue(append([]interface{}{"del"}, stringsToArgs(keys)...)...)
+}
+
+// Incr queues an INCR command.
+func (p *Pipeline) Incr(key string) *Pipeline {
+	return p.queue("incr", key)
+}
+
+// IncrBy queues an INCRBY command.
+func (p *Pipeline) IncrBy(key string, increment int64) *Pipeline {
+	return p.queue("incrby", key, increment)
+}
+
+// Decr queues a DECR command.
+func (p *Pipeline) Decr(key string) *Pipeline {
+	return p.queue("decr", key)
+}
+
+// DecrBy queues a DECRBY command.
+func (p *Pipeline) DecrBy(key string, decrement int64) *Pipeline {
+	return p.queue("decrby", key, decrement)
+}
+
+// Expire queues an EXPIRE command.
+func (p *Pipeline) Expire(key string, seconds int) *Pipeline {
+	return p.queue("expire", key, seconds)
+}
+
+// Hset queues an HSET command.
+func (p *Pipeline) Hset(key string, field interface{}, value interface{}) *Pipeline {
+	p.checkSupportedType(1, field, value)
+
+	return p.queue("hset", key, field, value)
+}
+
+// Hget queues an HGET command.
+func (p *Pipeline) Hget(key string, field interface{}) *Pipeline {
+	p.checkSupportedType(1, field)
+
+	return p.queueDecoded(p.typedReply, "hget", key, field)
+}
+
+// Lpush queues an LPUSH command.
+func (p *Pipeline) Lpush(key string, values ...interface{}) *Pipeline {
+	p.checkSupportedType(1, values...)
+
+	return p.queue(append([]interface{}{"lpush", key}, values...)...)
+}
+
+// Rpush queues an RPUSH command.
+func (p *Pipeline) Rpush(key string, values ...interface{}) *Pipeline {
+	p.checkSupportedType(1, values...)
+
+	return p.queue(append([]interface{}{"rpush", key}, values...)...)
+}
+
+// Sadd queues an SADD command.
+func (p *Pipeline) Sadd(key string, members ...interface{}) *Pipeline {
+	p.checkSupportedType(1, members...)
+
+	return p.queue(append([]interface{}{"sadd", key}, members...)...)
+}
+
+// Zadd queues a ZADD command, with the same arguments, and options, as the
+// client's zadd method. With the incr option set, it resolves with the new
+// score of the member, as a number.
+func (p *Pipeline) Zadd(key string, members []interface{}, options map[string]interface{}) *Pipeline {
+	scored, err := p.client.scoredMembers(members)
+	if err != nil {
+		p.throw(err)
+	}
+
+	var opts zaddOptions
+	if err := decodeOptions(options, &opts); err != nil {
+		p.throw(fmt.Errorf("invalid zadd options; reason: %w", err))
+	}
+
+	flags, err := opts.args()
+	if err != nil {
+		p.throw(fmt.Errorf("invalid zadd options; %w", err))
+	}
+
+	if !opts.Incr {
+		return p.queue(zaddArgs(key, flags, scored)...)
+	}
+
+	if len(scored) != 1 {
+		p.throw(fmt.Errorf("invalid zadd options; incr requires a single member, got %d", len(scored)))
+	}
+
+	return p.queueDecoded(zaddScore, zaddArgs(key, flags, scored)...)
+}
+
+// Sintercard queues a SINTERCARD command, with an optional `limit`, as the
+// client's sintercard method.
+func (p *Pipeline) Sintercard(keys []string, limit ...int64) *Pipeline {
+	if len(keys) == 0 {
+		p.throw(errors.New("at least one key must be provided to sintercard"))
+	}
+
+	n, err := intercardLimit("sintercard", limit)
+	if err != nil {
+		p.throw(err)
+	}
+
+	args := append([]interface{}{"sintercard", len(keys)}, stringsToArgs(keys)...)
+	if n > 0 {
+		args = append(args, "limit", n)
+	}
+
+	return p.queue(args...)
+}
+
+// SendCommand queues an arbitrary command.
+func (p *Pipeline) SendCommand(command string, args ...interface{}) *Pipeline {
+	p.checkSupportedType(1, args...)
+
+	return p.queue(append([]interface{}{command}, args...)...)
+}
+
+// Exec sends the queued commands to Redis in a single round-trip, and
+// resolves to the array of their results, in the order they were queued.
+//
+// The commands of pipelines returned by multi are wrapped in a MULTI/EXEC
+// transaction, so that they are executed atomically.
+//
+// Nil replies, such as those of GET for missing keys, resolve to null. If
+// any of the commands fails, the promise is rejected with the error of the
+// first one that did. The queue is emptied either way, so that the pipeline
+// can be reused.
+func (p *Pipeline) Exec() *sobek.Promise {
+	c := p.client
+	promise, resolve, reject := c.newPromise()
+
+	if p.watched {
+		reject(errors.New("the commands queued in a watch callback are executed by watch"))
+		return promise
+	}
+
+	queued := p.cmds
+	p.cmds = nil
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		pipe := c.redisClient.Pipeline()
+		if p.tx {
+			pipe = c.redisClient.TxPipeline()
+		}
+
+		results, err := execQueued(c.context(), pipe, queued)
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(results)
+	}()
+
+	return promise
+}
+
+// execQueued sends the provided commands through `pipe`, and returns their
+// results, decoded as outside of a pipeline, nil standing for nil replies,
+// or the error of the first command which failed.
+func execQueued(ctx context.Context, pipe redis.Pipeliner, queued []queuedCommand) ([]interface{}, error) {
+	for _, cmd := range queued {
+		pipe.Do(ctx, cmd.args...)
+	}
+
+	// Exec returns the error of the first failed command, which may be a
+	// nil reply, followed by actual failures.
+	cmds, err := pipe.Exec(ctx)
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+
+	results := make([]interface{}, len(cmds))
+	for idx, cmd := range cmds {
+		result, err := cmd.(*redis.Cmd).Result()
+		switch {
+		case errors.Is(err, redis.Nil):
+			results[idx] = nil
+		case err != nil:
+			return nil, err
+		case queued[idx].decode != nil:
+			if results[idx], err = queued[idx].decode(result); err != nil {
+				return nil, err
+			}
+		default:
+			results[idx] = result
+		}
+	}
+
+	return results, nil
+}
+
+// queue appends a command to the pipeline's queue.
+func (p *Pipeline) queue(args ...interface{}) *Pipeline {
+	return p.queueDecoded(nil, args...)
+}
+
+// queueDecoded appends a command, whose reply is converted with `decode`,
+// to the pipeline's queue.
+func (p *Pipeline) queueDecoded(decode func(interface{}) (interface{}, error), args ...interface{}) *Pipeline {
+	p.cmds = append(p.cmds, queuedCommand{args: args, decode: decode})
+	return p
+}
+
+// typedReply decodes the replies of string, and hash, reads, typed
+// according to the client's returnTypes option.
+func (p *Pipeline) typedReply(reply interface{}) (interface{}, error) {
+	return p.client.typedValue(reply), nil
+}
+
+// checkSupportedType throws if the provided arguments are not of a type
+// supported by the redis client.
+func (p *Pipeline) checkSupportedType(offset int, args ...interface{}) {
+	if err := p.client.isSupportedType(offset, args...); err != nil {
+		p.throw(err)
+	}
+}
+
+// throw throws an error telling the command could not be queued.
+func (p *Pipeline) throw(err error) {
+	common.Throw(p.client.vu.Runtime(), fmt.Errorf("unable to queue command; reason: %w", err))
+}
+
+// stringsToArgs converts the provided strings to command arguments.
+func stringsToArgs(values []string) []interface{} {
+	args := make([]interface{}, len(values))
+	for idx, value := range values {
+		args[idx] = value
+	}
+
+	return args
+}
@@ -0,0 +1,168 @@
+package redis
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientPipeline(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunT(t)
+	rs.RegisterCommandHandler("SET", func(c *Connection, _ []string) {
+		c.WriteOK()
+	})
+	rs.RegisterCommandHandler("GET", func(c *Connection, args []string) {
+		if args[0] == "missing" {
+			c.WriteNull()
+			return
+		}
+
+		c.WriteBulkString("bar")
+	})
+	rs.RegisterCommandHandler("INCR", func(c *Connection, _ []string) {
+		c.WriteInteger(1)
+	})
+	rs.RegisterCommandHandler("LPUSH", func(c *Connection, _ []string) {
+		c.WriteError(fmt.Errorf("WRONGTYPE Operation against a key holding the wrong kind of value"))
+	})
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client('redis://%s');
+
+			const pipeline = redis.pipeline();
+			pipeline.set("foo", "bar", 10).get("foo").incr("counter");
+
+			try {
+				pipeline.set("foo", {}, 0);
+				throw 'expected queuing an unsupported type to throw';
+			} catch (err) {
+				if (!String(err).includes('unsupported type')) { throw 'unexpected error: ' + err }
+			}
+
+			pipeline.exec()
+				.then(res => {
+					if (res.length !== 3 || res[0] !== "OK" || res[1] !== "bar" || res[2] !== 1) {
+						throw 'unexpected value for pipeline results: ' + JSON.stringify(res)
+					}
+				})
+				.then(() => pipeline.exec())
+				.then(res => { if (res.length !== 0) { throw 'expected the pipeline to be emptied by exec' } })
+				.then(() => pipeline.get("missing").get("foo").exec())
+				.then(res => {
+					if (res.length !== 2 || res[0] !== null || res[1] !== "bar") {
+						throw 'unexpected value for pipeline results with a missing key: ' + JSON.stringify(res)
+					}
+				})
+				.then(() => pipeline.incr("counter").lpush("counter", "a").exec())
+				.then(
+					res => { throw 'expected pipeline to fail' },
+					err => { if (!err.error().includes('WRONGTYPE')) { throw 'unexpected error: ' + err.error() } }
+				)
+		`, rs.Addr()))
+
+		return err
+	})
+
+	assert.NoError(t, gotScriptErr)
+	assert.Equal(t, [][]string{
+		{"HELLO", "2"},
+		{"SET", "foo", "bar", "ex", "10"},
+		{"GET", "foo"},
+		{"INCR", "counter"},
+		{"GET", "missing"},
+		{"GET", "foo"},
+		{"INCR", "counter"},
+		{"LPUSH", "counter", "a"},
+	}, rs.GotCommands())
+}
+
+func TestClientPipelineOptions(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunT(t)
+	rs.RegisterCommandHandler("SET", func(c *Connection, args []string) {
+		switch args[len(args)-1] {
+		case "nx":
+			c.WriteNull()
+		case "get":
+			c.WriteBulkString("previous")
+		default:
+			c.WriteOK()
+		}
+	})
+	rs.RegisterCommandHandler("GET", func(c *Connection, _ []string) {
+		c.WriteBulkString("42")
+	})
+	rs.RegisterCommandHandler("ZADD", func(c *Connection, args []string) {
+		if args[len(args)-3] == "incr" {
+			c.WriteBulkString("3.5")
+			return
+		}
+
+		c.WriteInteger(1)
+	})
+	rs.RegisterCommandHandler("SINTERCARD", func(c *Connection, _ []string) {
+		c.WriteInteger(2)
+	})
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client({ socket: { host: "%s", port: %d }, returnTypes: "auto" });
+
+			for (const [queue, reason] of [
+				[() => redis.pipeline().set("foo", "bar", { nx: true, xx: true }), 'nx and xx are mutually exclusive'],
+				[() => redis.pipeline().set("foo", {}, 0), 'unsupported type'],
+				[() => redis.pipeline().zadd("scores", [{ score: 1, member: "a" }, { score: 2, member: "b" }], { incr: true }), 'incr requires a single member'],
+				[() => redis.pipeline().zadd("scores", [{ member: "a" }]), 'invalid score'],
+				[() => redis.pipeline().sintercard(["a", "b"], -1), 'expected a non-negative number'],
+			]) {
+				try {
+					queue();
+					throw 'expected queuing to throw: ' + reason;
+				} catch (err) {
+					if (!String(err).includes(reason)) { throw 'unexpected error: ' + err }
+				}
+			}
+
+			redis.pipeline()
+				.set("foo", "bar", 10)
+				.set("foo", "bar", { px: 1500, xx: true })
+				.set("foo", "bar", { nx: true })
+				.set("foo", "bar", { get: true })
+				.get("foo")
+				.zadd("scores", [{ score: 1, member: "alice" }], { gt: true, ch: true })
+				.zadd("scores", [{ score: 1, member: "alice" }], { xx: true, incr: true })
+				.sintercard(["a", "b"], 10)
+				.sintercard(["a", "b"])
+				.exec()
+				.then(res => {
+					const want = ["OK", "OK", null, "previous", 42, 1, 3.5, 2, 2];
+					if (JSON.stringify(res) !== JSON.stringify(want)) {
+						throw 'unexpected value for pipeline results: ' + JSON.stringify(res)
+					}
+				})
+		`, rs.Addr().IP.String(), rs.Addr().Port))
+
+		return err
+	})
+
+	assert.NoError(t, gotScriptErr)
+	assert.Equal(t, [][]string{
+		{"HELLO", "2"},
+		{"SET", "foo", "bar", "ex", "10"},
+		{"SET", "foo", "bar", "px", "1500", "xx"},
+		{"SET", "foo", "bar", "nx"},
+		{"SET", "foo", "bar", "get"},
+		{"GET", "foo"},
+		{"ZADD", "scores", "gt", "ch", "1", "alice"},
+		{"ZADD", "scores", "xx", "incr", "1", "alice"},
+		{"SINTERCARD", "2", "a", "b", "limit", "10"},
+		{"SINTERCARD", "2", "a", "b"},
+	}, rs.GotCommands())
+}
@@ -0,0 +1,311 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/grafana/sobek"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+// ConnectionCount returns the number of connections currently open by the
+// client's connection pool, as an object mapping each node's address to
+// its count of connections. Cluster clients report a count for each of the
+// cluster's nodes, while other clients report a single count.
+//
+// Note that the connection pool is shared by all the VUs using the same
+// client options, and so are the reported counts.
+func (c *Client) ConnectionCount() *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		counts, err := c.connectionCounts(c.context())
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(counts)
+	}()
+
+	return promise
+}
+
+// connectionCounts returns the number of connections open to each node the
+// client is connected to, indexed by the node's address.
+func (c *Client) connectionCounts(ctx context.Context) (map[string]uint32, error) {
+	cluster, ok := c.redisClient.(*redis.ClusterClient)
+	if !ok {
+		return map[string]uint32{c.nodeAddr(): c.redisClient.PoolStats().TotalConns}, nil
+	}
+
+	var (
+		mu     sync.Mutex
+		counts = make(map[string]uint32)
+	)
+
+	err := cluster.ForEachShard(ctx, func(_ context.Context, node *redis.Client) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		counts[node.Options().Addr] = node.PoolStats().TotalConns
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+// nodeAddr returns the address identifying the node a non-cluster client is
+// connected to. For sentinel-backed clients, whose master may change over
+// time, the master's name is returned.
+func (c *Client) nodeAddr() string {
+	if c.redisOptions.MasterName != "" {
+		return c.redisOptions.MasterName
+	}
+
+	return c.redisOptions.Addrs[0]
+}
+
+// PoolStats returns the statistics of the client's connection pool, summed
+// over the cluster's nodes for cluster clients:
+//   - hits: the number of times an idle connection was found in the pool.
+//   - misses: the number of times no idle connection was found in the pool.
+//   - timeouts: the number of times waiting for a connection timed out.
+//   - totalConns: the number of connections in the pool.
+//   - idleConns: the number of idle connections in the pool.
+//   - staleConns: the number of stale connections removed from the pool.
+//
+// As the connectionCount method, it reports on the connection pool shared
+// by all the VUs using the same client options.
+func (c *Client) PoolStats() *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		stats := c.redisClient.PoolStats()
+
+		resolve(map[string]uint32{
+			"hits":       stats.Hits,
+			"misses":     stats.Misses,
+			"timeouts":   stats.Timeouts,
+			"totalConns": stats.TotalConns,
+			"idleConns":  stats.IdleConns,
+			"staleConns": stats.StaleConns,
+		})
+	}()
+
+	return promise
+}
+
+// connectOptions holds the options of the Client's connect method.
+type connectOptions struct {
+	// FillIdleConns waits for the pool of each node to hold the
+	// minIdleConns idle connections.
+	FillIdleConns bool `json:"fillIdleConns,omitempty"`
+}
+
+// Connect establishes the client's connections, which are otherwise
+// established by its first commands, such as in the setup function, so
+// that dialing doesn't skew the latencies of the first iterations. Each of
+// the nodes the client is connected to is sent a PING, to check it is
+// reachable.
+//
+// With the `fillIdleConns` option, the promise also waits for the pool of
+// each node to hold the minIdleConns idle connections, which go-redis opens
+// in the background. As the pool is shared by all the VUs using the same
+// client options, the VUs use the connections opened in setup.
+//
+// The promise resolves once the connections are established.
+func (c *Client) Connect(options map[string]interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	var opts connectOptions
+	if err := decodeOptions(options, &opts); err != nil {
+		reject(fmt.Errorf("invalid connect options; reason: %w", err))
+		return promise
+	}
+
+	if opts.FillIdleConns && c.redisOptions.MinIdleConns <= 0 {
+		reject(errors.New("invalid connect options; fillIdleConns requires the minIdleConns socket option"))
+		return promise
+	}
+
+	go func() {
+		err := c.forEachNode(c.context(), func(ctx context.Context, node *redis.Client) error {
+			if opts.FillIdleConns {
+				return fillIdleConns(ctx, node, c.redisOptions.MinIdleConns)
+			}
+
+			return node.Ping(ctx).Err()
+		})
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(nil)
+	}()
+
+	return promise
+}
+
+// forEachNode calls `fn` concurrently for each of the nodes the client is
+// connected to, the masters and replicas of cluster clients, or for the
+// client itself.
+func (c *Client) forEachNode(ctx context.Context, fn func(ctx context.Context, node *redis.Client) error) error {
+	switch client := c.redisClient.(type) {
+	case *redis.ClusterClient:
+		return client.ForEachShard(ctx, fn)
+	case *redis.Client:
+		return fn(ctx, client)
+	default:
+		return fmt.Errorf("unsupported client type: %T", client)
+	}
+}
+
+// fillIdleConnsPollInterval is the interval at which fillIdleConns checks
+// whether a pool holds enough idle connections.
+const fillIdleConnsPollInterval = 10 * time.Millisecond
+
+// fillIdleConns sends a PING to `node`, and waits for its pool to hold
+// `count` idle connections, up to its size, which go-redis opens in the
+// background. It fails if they aren't all open within the node's dial
+// timeout, such as when some of them failed to be dialed.
+func fillIdleConns(ctx context.Context, node *redis.Client, count int) error {
+	if err := node.Ping(ctx).Err(); err != nil {
+		return err
+	}
+
+	opts := node.Options()
+	if opts.PoolSize > 0 && count > opts.PoolSize {
+		count = opts.PoolSize
+	}
+
+	ticker := time.NewTicker(fillIdleConnsPollInterval)
+	defer ticker.Stop()
+
+	deadline := time.Now().Add(opts.DialTimeout)
+	for {
+		idle := int(node.PoolStats().IdleConns)
+		if idle >= count {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("only %d of the %d idle connections to %s could be opened", idle, count, opts.Addr)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// HealthCheck checks the server is reachable, and responsive, such as in
+// the setup function, to fail fast before the load starts.
+//
+// The promise resolves with an object holding the `latencyMs` of a PING,
+// in milliseconds, and the server's `version`, and `mode`, "standalone",
+// "sentinel", or "cluster", as reported by INFO. The promise is rejected
+// if the server can't be reached.
+func (c *Client) HealthCheck() *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		ctx := c.context()
+
+		start := time.Now()
+		if err := c.redisClient.Ping(ctx).Err(); err != nil {
+			reject(err)
+			return
+		}
+		latency := time.Since(start)
+
+		info, err := c.redisClient.Info(ctx, "server").Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(map[string]interface{}{
+			"latencyMs": float64(latency) / float64(time.Millisecond),
+			"version":   infoField(info, "redis_version"),
+			"mode":      infoField(info, "redis_mode"),
+		})
+	}()
+
+	return promise
+}
+
+// Close releases the client's connection pool, which is closed, along with
+// its connections, once no other client uses it. The pool being shared by
+// all the clients using the same options, such as those of other VUs,
+// closing a client only closes the pool once they are all closed, or the
+// k6 process exits, which closes all the pools.
+//
+// Commands sent by the client afterwards fail, once the pool is closed.
+// Closing a client returned by withTimeout does nothing, as it uses the
+// pool of the client it was derived from.
+func (c *Client) Close() *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if !c.ownsRedisClient || c.closeRedisClient == nil {
+		resolve(nil)
+		return promise
+	}
+
+	opts := c.redisOptions
+	c.ownsRedisClient = false
+
+	var logger logrus.FieldLogger
+	if state := c.vu.State(); state != nil {
+		logger = state.Logger
+	}
+
+	go func() {
+		opened, closed, err := c.closeRedisClient(opts)
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		if logger != nil && (opened > 0 || closed > 0) {
+			logger.WithFields(logrus.Fields{
+				"opened": opened,
+				"closed": closed,
+			}).Debug("Closed a redis client's connections")
+		}
+
+		resolve(nil)
+	}()
+
+	return promise
+}
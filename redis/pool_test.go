@@ -0,0 +1,270 @@
+package redis
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientConnectionCount(t *testing.T) {
+	t.Parallel()
+
+	t.Run("single node client reports its pool's connections", func(t *testing.T) {
+		t.Parallel()
+
+		ts := newTestSetup(t)
+		rs := RunT(t)
+		rs.RegisterCommandHandler("GET", func(c *Connection, _ []string) {
+			c.WriteBulkString("bar")
+		})
+
+		gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+			_, err := ts.rt.RunString(fmt.Sprintf(`
+				const redis = new Client('redis://%s');
+
+				redis.get("foo")
+					.then(res => redis.connectionCount())
+					.then(counts => {
+						if (Object.keys(counts).length !== 1 || counts["%s"] !== 1) {
+							throw 'unexpected connection counts: ' + JSON.stringify(counts)
+						}
+					})
+			`, rs.Addr(), rs.Addr()))
+
+			return err
+		})
+
+		assert.NoError(t, gotScriptErr)
+		assert.Equal(t, 1, rs.HandledCommandsCount())
+	})
+
+	t.Run("cluster client reports each node's connections", func(t *testing.T) {
+		t.Parallel()
+
+		ts := newTestSetup(t)
+		rs1 := RunT(t)
+		rs2 := RunT(t)
+		registerClusterSlotsHandler(stubClusterShard{master: rs1}, stubClusterShard{master: rs2})
+
+		gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+			_, err := ts.rt.RunString(fmt.Sprintf(`
+				const redis = new Client({
+					cluster: {
+						nodes: ['redis://%s', 'redis://%s'],
+					},
+				});
+
+				redis.connectionCount()
+					.then(counts => {
+						if (Object.keys(counts).length !== 2 || !("%s" in counts) || !("%s" in counts)) {
+							throw 'unexpected connection counts: ' + JSON.stringify(counts)
+						}
+
+						// Loading the cluster's layout requires a connection to one of its nodes.
+						if (counts["%s"] + counts["%s"] < 1) {
+							throw 'unexpected connection counts: ' + JSON.stringify(counts)
+						}
+					})
+			`, rs1.Addr(), rs2.Addr(), rs1.Addr(), rs2.Addr(), rs1.Addr(), rs2.Addr()))
+
+			return err
+		})
+
+		assert.NoError(t, gotScriptErr)
+	})
+}
+
+func TestClientPoolStats(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunT(t)
+	rs.RegisterCommandHandler("GET", func(c *Connection, _ []string) {
+		c.WriteBulkString("bar")
+	})
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client({
+				socket: {
+					host: '%s',
+					port: %d,
+					poolSize: 4,
+					maxIdleConns: 2,
+					connMaxIdleTime: 60000,
+				},
+			});
+
+			redis.get("foo")
+				.then(res => redis.get("foo"))
+				.then(res => redis.poolStats())
+				.then(stats => {
+					if (stats.totalConns !== 1 || stats.idleConns !== 1 || stats.timeouts !== 0) {
+						throw 'unexpected pool stats: ' + JSON.stringify(stats)
+					}
+
+					// The first command had to dial a connection, which the
+					// following one reused.
+					if (stats.misses < 1 || stats.hits < 1) {
+						throw 'unexpected pool stats: ' + JSON.stringify(stats)
+					}
+				})
+		`, rs.Addr().IP.String(), rs.Addr().Port))
+
+		return err
+	})
+
+	assert.NoError(t, gotScriptErr)
+}
+
+func TestClientClose(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunT(t)
+	rs.RegisterCommandHandler("GET", func(c *Connection, _ []string) {
+		c.WriteBulkString("bar")
+	})
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const first = new Client('redis://%s');
+			const second = new Client('redis://%s');
+
+			Promise.all([first.get("foo"), second.get("foo")])
+				.then(() => first.close())
+				.then(() => first.close())
+				.then(() => second.get("foo"))
+				.then(res => {
+					if (res !== "bar") {
+						throw 'unexpected value for get result: ' + res
+					}
+				})
+				.then(() => second.close())
+				.then(() => second.get("foo"))
+				.then(
+					res => { throw 'expected get to fail on a closed client' },
+					err => {
+						if (!err.error().includes("client is closed")) {
+							throw 'unexpected error: ' + err.error()
+						}
+					},
+				)
+		`, rs.Addr(), rs.Addr()))
+
+		return err
+	})
+
+	assert.NoError(t, gotScriptErr)
+}
+
+func TestClientConnect(t *testing.T) {
+	t.Parallel()
+
+	t.Run("pings the server", func(t *testing.T) {
+		t.Parallel()
+
+		ts := newTestSetup(t)
+		rs := RunT(t)
+
+		gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+			_, err := ts.rt.RunString(fmt.Sprintf(`
+				const redis = new Client('redis://%s');
+				redis.connect()
+			`, rs.Addr()))
+
+			return err
+		})
+
+		assert.NoError(t, gotScriptErr)
+		assert.Equal(t, [][]string{{"HELLO", "2"}, {"PING"}}, rs.GotCommands())
+	})
+
+	t.Run("fills the pool's idle connections", func(t *testing.T) {
+		t.Parallel()
+
+		ts := newTestSetup(t)
+		rs := RunT(t)
+
+		gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+			_, err := ts.rt.RunString(fmt.Sprintf(`
+				const redis = new Client({
+					socket: {
+						host: '%s',
+						port: %d,
+						minIdleConns: 3,
+					},
+				});
+
+				redis.connect({ fillIdleConns: true })
+					.then(() => redis.poolStats())
+					.then(stats => {
+						if (stats.idleConns < 3) {
+							throw 'unexpected pool stats: ' + JSON.stringify(stats)
+						}
+					})
+			`, rs.Addr().IP.String(), rs.Addr().Port))
+
+			return err
+		})
+
+		assert.NoError(t, gotScriptErr)
+		assert.GreaterOrEqual(t, rs.HandledConnectionsCount(), 3)
+	})
+
+	t.Run("filling the pool requires the minIdleConns option", func(t *testing.T) {
+		t.Parallel()
+
+		ts := newTestSetup(t)
+		rs := RunT(t)
+
+		gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+			_, err := ts.rt.RunString(fmt.Sprintf(`
+				const redis = new Client('redis://%s');
+
+				redis.connect({ fillIdleConns: true })
+					.then(
+						() => { throw 'expected connect to fail' },
+						err => {
+							if (!err.error().includes("fillIdleConns requires the minIdleConns socket option")) {
+								throw 'unexpected error: ' + err.error()
+							}
+						},
+					)
+			`, rs.Addr()))
+
+			return err
+		})
+
+		assert.NoError(t, gotScriptErr)
+	})
+}
+
+func TestClientHealthCheck(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunT(t)
+	rs.RegisterCommandHandler("INFO", func(c *Connection, _ []string) {
+		c.WriteBulkString("# Server\r\nredis_version:7.2.4\r\nredis_mode:standalone\r\n")
+	})
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client('redis://%s');
+
+			redis.healthCheck()
+				.then(health => {
+					if (health.version !== "7.2.4" || health.mode !== "standalone" || !(health.latencyMs >= 0)) {
+						throw 'unexpected health: ' + JSON.stringify(health)
+					}
+				})
+		`, rs.Addr()))
+
+		return err
+	})
+
+	assert.NoError(t, gotScriptErr)
+	assert.Equal(t, [][]string{{"HELLO", "2"}, {"PING"}, {"INFO", "server"}}, rs.GotCommands())
+}
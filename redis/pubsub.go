@@ -0,0 +1,530 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/grafana/sobek"
+	"github.com/mstoykov/k6-taskqueue-lib/taskqueue"
+	"github.com/redis/go-redis/v9"
+)
+
+// subscriptionRetryBackoff is the time waited before receiving messages
+// again, after receiving failed.
+const subscriptionRetryBackoff = 100 * time.Millisecond
+
+// Subscribe subscribes the client to the provided channels, and calls
+// `handler` with an object holding the `channel` and `payload` of each
+// message published to them.
+//
+// All the channels a client subscribes to, over any number of calls, share
+// a single subscription, and thus a single connection. Messages are
+// delivered to the handlers in the order they are received, which preserves
+// their order across channels.
+//
+// As long as the client is subscribed to channels, the VU's iteration keeps
+// running. Use Unsubscribe to end the subscription.
+//
+// The promise resolves once the server confirmed the subscription to all
+// the provided channels.
+func (c *Client) Subscribe(channels interface{}, handler sobek.Callable) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	names, err := channelsArg(channels)
+	if err != nil {
+		reject(err)
+		return promise
+	}
+
+	if handler == nil {
+		reject(errors.New("a message handler function must be provided to subscribe"))
+		return promise
+	}
+
+	sub := c.subscription()
+	confirmed := sub.register(false, names, sub.callbackHandler(c, handler))
+
+	go func() {
+		if err := c.awaitSubscription(sub, false, names, confirmed); err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(nil)
+	}()
+
+	return promise
+}
+
+// Unsubscribe unsubscribes the client from the provided channels, or from
+// all the channels it is subscribed to if none is provided. Once the
+// client isn't subscribed to any channel, nor pattern, anymore, the
+// subscription's connection is closed.
+func (c *Client) Unsubscribe(channels ...string) *sobek.Promise {
+	return c.unsubscribe(false, channels)
+}
+
+// Psubscribe subscribes the client to the provided glob-style patterns,
+// such as "news.*", and calls `handler` with an object holding the
+// `pattern`, `channel`, and `payload` of each message published to a
+// channel matching them.
+//
+// Patterns share the subscription, and thus the connection, of the
+// channels subscribed to with Subscribe. A message published to a channel
+// matching several patterns, or both a pattern and a channel subscribed
+// to, is delivered once for each of them, as Redis does.
+//
+// The promise resolves once the server confirmed the subscription to all
+// the provided patterns.
+func (c *Client) Psubscribe(patterns interface{}, handler sobek.Callable) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	names, err := channelsArg(patterns)
+	if err != nil {
+		reject(err)
+		return promise
+	}
+
+	if handler == nil {
+		reject(errors.New("a message handler function must be provided to psubscribe"))
+		return promise
+	}
+
+	sub := c.subscription()
+	confirmed := sub.register(true, names, sub.callbackHandler(c, handler))
+
+	go func() {
+		if err := c.awaitSubscription(sub, true, names, confirmed); err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(nil)
+	}()
+
+	return promise
+}
+
+// Punsubscribe unsubscribes the client from the provided patterns, or from
+// all the patterns it is subscribed to if none is provided, as Unsubscribe
+// does for channels.
+func (c *Client) Punsubscribe(patterns ...string) *sobek.Promise {
+	return c.unsubscribe(true, patterns)
+}
+
+// Publish publishes `message` to `channel`.
+//
+// The promise resolves with the number of clients that received the
+// message.
+func (c *Client) Publish(channel string, message interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	if err := c.isSupportedType(1, message); err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		received, err := c.redisClient.Publish(c.context(), channel, message).Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(received)
+	}()
+
+	return promise
+}
+
+// unsubscribe unsubscribes the client from the provided channels, or
+// patterns, or from all of them if none is provided.
+func (c *Client) unsubscribe(pattern bool, names []string) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	c.subscriptionMu.Lock()
+	sub := c.activeSubscription
+	c.subscriptionMu.Unlock()
+
+	if sub == nil {
+		resolve(nil)
+		return promise
+	}
+
+	if remaining := sub.unregister(pattern, names); remaining == 0 {
+		c.closeSubscription(sub)
+		resolve(nil)
+		return promise
+	}
+
+	go func() {
+		var err error
+		if pattern {
+			err = sub.pubsub.PUnsubscribe(c.context(), names...)
+		} else {
+			err = sub.pubsub.Unsubscribe(c.context(), names...)
+		}
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(nil)
+	}()
+
+	return promise
+}
+
+// subscription is the pub/sub subscription of a Client, covering all the
+// channels and patterns the Client subscribed to, over a single go-redis
+// PubSub.
+type subscription struct {
+	pubsub *redis.PubSub
+
+	// queue delivers the received messages to the handlers, on the
+	// event loop, in order.
+	queue *taskqueue.TaskQueue
+
+	// done is closed once the subscription is closed.
+	done chan struct{}
+
+	mu       sync.Mutex
+	handlers map[subscriptionTarget]messageHandler
+
+	// pending holds, for each target whose subscription wasn't confirmed
+	// yet, the channels notified upon its confirmation.
+	pending map[subscriptionTarget][]chan struct{}
+}
+
+// subscriptionTarget is a channel, or a pattern, subscribed to. As Redis
+// keeps channels and patterns apart, so do subscriptions.
+type subscriptionTarget struct {
+	name    string
+	pattern bool
+}
+
+// messageTarget returns the target the provided message was received for.
+func messageTarget(msg *redis.Message) subscriptionTarget {
+	if msg.Pattern != "" {
+		return subscriptionTarget{name: msg.Pattern, pattern: true}
+	}
+
+	return subscriptionTarget{name: msg.Channel}
+}
+
+// messageHandler handles the messages received for a target. It is called
+// from the goroutine receiving the messages, and must not block.
+type messageHandler func(msg *redis.Message)
+
+// subscription returns the Client's active subscription, creating it if
+// needed. It must be called from the event loop.
+func (c *Client) subscription() *subscription {
+	c.subscriptionMu.Lock()
+	defer c.subscriptionMu.Unlock()
+
+	if c.activeSubscription != nil {
+		return c.activeSubscription
+	}
+
+	sub := &subscription{
+		pubsub:   c.redisClient.Subscribe(c.context()),
+		queue:    taskqueue.New(c.vu.RegisterCallback),
+		done:     make(chan struct{}),
+		handlers: make(map[subscriptionTarget]messageHandler),
+		pending:  make(map[subscriptionTarget][]chan struct{}),
+	}
+	c.activeSubscription = sub
+
+	go c.receive(sub)
+	go func() {
+		select {
+		case <-c.vu.Context().Done():
+			c.closeSubscription(sub)
+		case <-sub.done:
+		}
+	}()
+
+	return sub
+}
+
+// awaitSubscription subscribes to the provided channels, or patterns, whose
+// handlers were registered, and blocks until the subscription to all of
+// them is confirmed by the server.
+func (c *Client) awaitSubscription(sub *subscription, pattern bool, names []string, confirmed []chan struct{}) error {
+	var err error
+	if pattern {
+		err = sub.pubsub.PSubscribe(c.context(), names...)
+	} else {
+		err = sub.pubsub.Subscribe(c.context(), names...)
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, targetConfirmed := range confirmed {
+		select {
+		case <-targetConfirmed:
+		case <-sub.done:
+			return fmt.Errorf("subscription to %q was closed before being confirmed", names)
+		}
+	}
+
+	return nil
+}
+
+// closeSubscription closes the provided subscription, if it is still the
+// Client's active one.
+func (c *Client) closeSubscription(sub *subscription) {
+	c.subscriptionMu.Lock()
+	defer c.subscriptionMu.Unlock()
+
+	if c.activeSubscription != sub {
+		return
+	}
+	c.activeSubscription = nil
+
+	close(sub.done)
+	_ = sub.pubsub.Close()
+	sub.queue.Close()
+}
+
+// receive receives the messages of the provided subscription, until it is
+// closed, and dispatches them to their handlers.
+func (c *Client) receive(sub *subscription) {
+	for {
+		msg, err := sub.pubsub.Receive(c.context())
+		if err != nil {
+			if errors.Is(err, redis.ErrClosed) {
+				return
+			}
+
+			select {
+			case <-sub.done:
+				return
+			case <-time.After(subscriptionRetryBackoff):
+				continue
+			}
+		}
+
+		switch msg := msg.(type) {
+		case *redis.Subscription:
+			switch msg.Kind {
+			case "subscribe":
+				sub.confirm(subscriptionTarget{name: msg.Channel})
+			case "psubscribe":
+				sub.confirm(subscriptionTarget{name: msg.Channel, pattern: true})
+			}
+		case *redis.Message:
+			if handler := sub.handler(messageTarget(msg)); handler != nil {
+				handler(msg)
+			}
+		}
+	}
+}
+
+// callbackHandler returns a messageHandler calling the provided JS function,
+// on the event loop, with an object holding the message's channel and
+// payload, along with the pattern it matched, for pattern subscriptions.
+// Messages are delivered in the order they are received.
+func (s *subscription) callbackHandler(c *Client, callback sobek.Callable) messageHandler {
+	rt := c.vu.Runtime()
+
+	return func(msg *redis.Message) {
+		s.queue.Queue(func() error {
+			// The target may have been unsubscribed from in the meantime.
+			if s.handler(messageTarget(msg)) == nil {
+				return nil
+			}
+
+			message := map[string]string{
+				"channel": msg.Channel,
+				"payload": msg.Payload,
+			}
+			if msg.Pattern != "" {
+				message["pattern"] = msg.Pattern
+			}
+
+			_, err := callback(sobek.Undefined(), rt.ToValue(message))
+
+			return err
+		})
+	}
+}
+
+// register sets the handler of the provided channels, or patterns, and
+// returns, for each of them, a channel closed once its subscription is
+// confirmed.
+func (s *subscription) register(pattern bool, names []string, handler messageHandler) []chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	confirmed := make([]chan struct{}, len(names))
+	for idx, name := range names {
+		target := subscriptionTarget{name: name, pattern: pattern}
+		s.handlers[target] = handler
+
+		confirmed[idx] = make(chan struct{})
+		s.pending[target] = append(s.pending[target], confirmed[idx])
+	}
+
+	return confirmed
+}
+
+// unregister removes the handlers of the provided channels, or patterns,
+// or of all of them if none is provided. It returns the number of channels
+// and patterns which still have a handler.
+func (s *subscription) unregister(pattern bool, names []string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for target := range s.handlers {
+		if target.pattern == pattern && (len(names) == 0 || slices.Contains(names, target.name)) {
+			delete(s.handlers, target)
+		}
+	}
+
+	return len(s.handlers)
+}
+
+// confirm notifies those waiting for the subscription to `target` that it
+// is confirmed.
+func (s *subscription) confirm(target subscriptionTarget) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, confirmed := range s.pending[target] {
+		close(confirmed)
+	}
+	delete(s.pending, target)
+}
+
+// handler returns the handler of `target`, if any.
+func (s *subscription) handler(target subscriptionTarget) messageHandler {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.handlers[target]
+}
+
+// channelsArg converts the channels argument of the pub/sub methods,
+// either a single channel name, or an array of them, to a slice.
+func channelsArg(channels interface{}) ([]string, error) {
+	switch v := channels.(type) {
+	case string:
+		return []string{v}, nil
+	case []interface{}:
+		if len(v) == 0 {
+			break
+		}
+
+		names := make([]string, len(v))
+		for idx, channel := range v {
+			name, ok := channel.(string)
+			if !ok {
+				return nil, fmt.Errorf("invalid channel at index %d; expected a string, got %T", idx, channel)
+			}
+			names[idx] = name
+		}
+
+		return names, nil
+	}
+
+	return nil, fmt.Errorf("invalid channels; expected a channel name, or a non-empty array of channel names, got %T", channels)
+}
+
+// PubSubRoundTrip measures the time it takes for a message published to
+// `channel` to be delivered back to a subscriber of it.
+//
+// It subscribes to `channel` on a dedicated connection, publishes a
+// timestamped message once the subscription is confirmed, and waits for
+// that very message to be received: other messages published to the
+// channel are ignored. The subscription is closed once the message is
+// received, or the VU's context is done.
+//
+// The promise resolves with the round-trip latency, in milliseconds. Call
+// it repeatedly to build a latency distribution.
+func (c *Client) PubSubRoundTrip(channel string) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		latency, err := c.pubSubRoundTrip(c.context(), channel)
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(float64(latency) / float64(time.Millisecond))
+	}()
+
+	return promise
+}
+
+// pubSubRoundTrip publishes a message to `channel`, and returns the time
+// elapsed until it is received back by a dedicated subscription.
+func (c *Client) pubSubRoundTrip(ctx context.Context, channel string) (time.Duration, error) {
+	pubsub := c.redisClient.Subscribe(ctx, channel)
+	defer pubsub.Close() //nolint:errcheck
+
+	// Wait for the subscription to be confirmed before publishing, so
+	// that the message can't be missed.
+	if _, err := pubsub.Receive(ctx); err != nil {
+		return 0, err
+	}
+
+	// The timestamp makes the message recognizable among the others
+	// published to the channel, while the random suffix tells apart those
+	// of concurrent round-trips.
+	payload := strconv.FormatInt(time.Now().UnixNano(), 10) + "-" + strconv.FormatInt(rand.Int63(), 36) //nolint:gosec
+
+	sent := time.Now()
+	if err := c.redisClient.Publish(ctx, channel, payload).Err(); err != nil {
+		return 0, err
+	}
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case msg, ok := <-messages:
+			if !ok {
+				return 0, fmt.Errorf("subscription to channel %q closed unexpectedly", channel)
+			}
+
+			if msg.Payload == payload {
+				return time.Since(sent), nil
+			}
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
+	}
+}
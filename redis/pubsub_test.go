@@ -0,0 +1,225 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientSubscribe(t *testing.T) {
+	t.Parallel()
+
+	t.Run("channels share a single ordered subscription", func(t *testing.T) {
+		t.Parallel()
+
+		ts := newTestSetup(t)
+		rs := RunT(t)
+		registerPubSubHandlers(rs)
+
+		gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+			_, err := ts.rt.RunString(fmt.Sprintf(`
+				const redis = new Client('redis://%s');
+				const received = [];
+
+				const handler = (msg) => {
+					received.push(msg.channel + ":" + msg.payload);
+					if (received.length === 4) {
+						redis.unsubscribe().then(() => {
+							if (received.join(",") !== "a:1,b:2,c:3,a:4") {
+								throw 'unexpected received messages: ' + received
+							}
+						})
+					}
+				};
+
+				redis.subscribe("a", handler)
+					.then(() => redis.subscribe(["b", "c"], handler))
+					.then(() => redis.sendCommand("PUBLISH", "a", "1"))
+					.then(() => redis.sendCommand("PUBLISH", "b", "2"))
+					.then(() => redis.sendCommand("PUBLISH", "c", "3"))
+					.then(() => redis.sendCommand("PUBLISH", "a", "4"))
+			`, rs.Addr()))
+
+			return err
+		})
+
+		assert.NoError(t, gotScriptErr)
+
+		// One connection is used for commands, and a single one for
+		// the subscription to all channels.
+		connections := 0
+		for _, cmd := range rs.GotCommands() {
+			if cmd[0] == "HELLO" {
+				connections++
+			}
+		}
+		assert.Equal(t, 2, connections)
+	})
+
+	t.Run("unsubscribed channels are not delivered anymore", func(t *testing.T) {
+		t.Parallel()
+
+		ts := newTestSetup(t)
+		rs := RunT(t)
+		registerPubSubHandlers(rs)
+
+		gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+			_, err := ts.rt.RunString(fmt.Sprintf(`
+				const redis = new Client('redis://%s');
+
+				redis.subscribe(["a", "b"], (msg) => {
+					if (msg.channel !== "b") {
+						throw 'unexpected message: ' + JSON.stringify(msg)
+					}
+					redis.unsubscribe("b")
+				})
+					.then(() => redis.unsubscribe("a"))
+					.then(() => redis.sendCommand("PUBLISH", "a", "1"))
+					.then(() => redis.sendCommand("PUBLISH", "b", "2"))
+			`, rs.Addr()))
+
+			return err
+		})
+
+		assert.NoError(t, gotScriptErr)
+		assert.Contains(t, rs.GotCommands(), []string{"UNSUBSCRIBE", "a"})
+	})
+
+	t.Run("invalid arguments are rejected", func(t *testing.T) {
+		t.Parallel()
+
+		ts := newTestSetup(t)
+		rs := RunT(t)
+
+		gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+			_, err := ts.rt.RunString(fmt.Sprintf(`
+				const redis = new Client('redis://%s');
+
+				redis.subscribe([], () => {})
+					.then(
+						res => { throw 'expected subscribe to fail' },
+						err => { if (!err.error().startsWith('invalid channels')) { throw 'unexpected error: ' + err } }
+					)
+					.then(() => redis.subscribe("a"))
+					.then(
+						res => { throw 'expected subscribe to fail' },
+						err => { if (!err.error().startsWith('a message handler function must be provided')) { throw 'unexpected error: ' + err } }
+					)
+			`, rs.Addr()))
+
+			return err
+		})
+
+		assert.NoError(t, gotScriptErr)
+		assert.Equal(t, 0, rs.HandledCommandsCount())
+	})
+}
+
+func TestClientPsubscribe(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunT(t)
+	registerPubSubHandlers(rs)
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client('redis://%s');
+			const received = [];
+
+			const handler = (msg) => {
+				received.push((msg.pattern || "") + "|" + msg.channel + ":" + msg.payload);
+				if (received.length === 3) {
+					redis.punsubscribe()
+						.then(() => redis.unsubscribe())
+						.then(() => {
+							if (received.join(",") !== "news.*|news.sports:1,|weather:2,news.*|news.tech:3") {
+								throw 'unexpected received messages: ' + received
+							}
+						})
+				}
+			};
+
+			redis.psubscribe("news.*", handler)
+				.then(() => redis.subscribe("weather", handler))
+				.then(() => redis.publish("news.sports", "1"))
+				.then(received => { if (received !== 1) { throw 'unexpected value for publish result: ' + received } })
+				.then(() => redis.publish("weather", "2"))
+				.then(() => redis.publish("traffic", "ignored"))
+				.then(() => redis.publish("news.tech", 3))
+		`, rs.Addr()))
+
+		return err
+	})
+
+	assert.NoError(t, gotScriptErr)
+	assert.Contains(t, rs.GotCommands(), []string{"PSUBSCRIBE", "news.*"})
+}
+
+func TestClientPubSubRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	t.Run("round-trips resolve with their latency", func(t *testing.T) {
+		t.Parallel()
+
+		ts := newTestSetup(t)
+		rs := RunT(t)
+		registerPubSubHandlers(rs)
+
+		gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+			_, err := ts.rt.RunString(fmt.Sprintf(`
+				const redis = new Client('redis://%s');
+
+				// Concurrent round-trips on the same channel only wait for
+				// their own message.
+				Promise.all([redis.pubSubRoundTrip("echo"), redis.pubSubRoundTrip("echo")])
+					.then(latencies => {
+						for (const latency of latencies) {
+							if (typeof latency !== "number" || latency < 0) {
+								throw 'unexpected latency: ' + latency
+							}
+						}
+					})
+			`, rs.Addr()))
+
+			return err
+		})
+
+		assert.NoError(t, gotScriptErr)
+	})
+
+	t.Run("round-trips are aborted when the VU's context is done", func(t *testing.T) {
+		t.Parallel()
+
+		ts := newTestSetup(t)
+		rs := RunT(t)
+		registerPubSubHandlers(rs)
+
+		// Messages are never delivered.
+		rs.RegisterCommandHandler("PUBLISH", func(c *Connection, _ []string) {
+			c.WriteInteger(1)
+		})
+
+		ctx, cancel := context.WithTimeout(ts.runtime.VU.CtxField, 100*time.Millisecond)
+		defer cancel()
+		ts.runtime.VU.CtxField = ctx
+
+		gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+			_, err := ts.rt.RunString(fmt.Sprintf(`
+				const redis = new Client('redis://%s');
+
+				redis.pubSubRoundTrip("echo").then(
+					res => { throw 'expected pubSubRoundTrip to fail' },
+					err => { if (err.kind !== "deadline") { throw 'unexpected error: ' + err.error() } }
+				)
+			`, rs.Addr()))
+
+			return err
+		})
+
+		assert.NoError(t, gotScriptErr)
+	})
+}
@@ -0,0 +1,121 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/grafana/sobek"
+	"github.com/redis/go-redis/v9"
+)
+
+// PushJob appends `jobs` to the work queue stored, as a list, at `queue`, so
+// that each of them is popped by a single caller, across VUs and k6
+// instances. It is typically used in the setup function, to distribute
+// unique test data, such as user IDs or tokens.
+//
+// Jobs can be binary: ArrayBuffer or Uint8Array.
+//
+// The promise resolves with the number of jobs in the queue after the push.
+func (c *Client) PushJob(queue string, jobs ...interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	if len(jobs) == 0 {
+		reject(errors.New("at least one job must be provided to pushJob"))
+		return promise
+	}
+
+	jobArgs, err := c.binaryArgs(1, jobs...)
+	if err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		count, err := c.redisClient.RPush(c.context(), queue, jobArgs...).Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(count)
+	}()
+
+	return promise
+}
+
+// PopJob removes and returns the oldest job of the work queue stored at
+// `queue`, waiting up to `timeout` seconds, with BLPOP, for one to be
+// pushed if the queue is empty.
+//
+// The promise resolves with the job, or null if none was pushed within
+// `timeout` seconds.
+func (c *Client) PopJob(queue string, timeout int64) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	// BLPOP blocks indefinitely with a zero timeout, which would keep the
+	// iteration from ever ending if the queue stays empty.
+	if timeout <= 0 {
+		reject(fmt.Errorf("invalid timeout %d; expected a positive number of seconds", timeout))
+		return promise
+	}
+
+	duration := time.Duration(timeout) * time.Second
+
+	go func() {
+		var reply []string
+		err := c.runBlocking("popJob", duration, func(ctx context.Context) (err error) {
+			reply, err = c.redisClient.BLPop(ctx, duration, queue).Result()
+			return err
+		})
+		if errors.Is(err, redis.Nil) {
+			resolve(nil)
+			return
+		}
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		// BLPOP replies with the key the job was popped from, and the job.
+		resolve(reply[1])
+	}()
+
+	return promise
+}
+
+// JobCount returns the number of jobs waiting in the work queue stored at
+// `queue`.
+//
+// The promise resolves with zero if `queue` does not exist.
+func (c *Client) JobCount(queue string) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		count, err := c.redisClient.LLen(c.context(), queue).Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(count)
+	}()
+
+	return promise
+}
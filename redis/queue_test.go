@@ -0,0 +1,66 @@
+package redis
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientWorkQueue(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunT(t)
+
+	var queue []string
+	rs.RegisterCommandHandler("RPUSH", func(c *Connection, args []string) {
+		queue = append(queue, args[1:]...)
+		c.WriteInteger(len(queue))
+	})
+	rs.RegisterCommandHandler("LLEN", func(c *Connection, _ []string) {
+		c.WriteInteger(len(queue))
+	})
+	rs.RegisterCommandHandler("BLPOP", func(c *Connection, args []string) {
+		if len(queue) == 0 {
+			c.WriteNull()
+			return
+		}
+
+		c.WriteArray(args[0], queue[0])
+		queue = queue[1:]
+	})
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client('redis://%s');
+
+			redis.pushJob("users", "user-1", "user-2")
+				.then(res => { if (res !== 2) { throw 'unexpected value for pushJob result: ' + res } })
+				.then(() => redis.popJob("users", 5))
+				.then(res => { if (res !== "user-1") { throw 'unexpected value for popJob result: ' + res } })
+				.then(() => redis.jobCount("users"))
+				.then(res => { if (res !== 1) { throw 'unexpected value for jobCount result: ' + res } })
+				.then(() => redis.popJob("users", 1))
+				.then(() => redis.popJob("users", 1))
+				.then(res => { if (res !== null) { throw 'unexpected value for popJob result on empty queue: ' + res } })
+				.then(() => redis.popJob("users", 0))
+				.then(
+					res => { throw 'expected popJob to fail' },
+					err => { if (err.error() !== 'invalid timeout 0; expected a positive number of seconds') { throw 'unexpected error: ' + err.error() } }
+				)
+		`, rs.Addr()))
+
+		return err
+	})
+
+	assert.NoError(t, gotScriptErr)
+	assert.Equal(t, [][]string{
+		{"HELLO", "2"},
+		{"RPUSH", "users", "user-1", "user-2"},
+		{"BLPOP", "users", "5"},
+		{"LLEN", "users"},
+		{"BLPOP", "users", "1"},
+		{"BLPOP", "users", "1"},
+	}, rs.GotCommands())
+}
@@ -0,0 +1,89 @@
+package redis
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+
+	"github.com/grafana/sobek"
+	"go.k6.io/k6/js/common"
+)
+
+// randomAlphabet holds the characters random strings are made of. Its 64
+// characters allow mapping random bytes to characters without bias.
+const randomAlphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_"
+
+// randomKeyLength is the length of the random part of the keys generated
+// by RandomKey.
+const randomKeyLength = 16
+
+// randomValueOptions holds the options of RandomValue.
+type randomValueOptions struct {
+	// Binary makes RandomValue return raw bytes, as a Uint8Array, rather
+	// than a string.
+	Binary bool `json:"binary,omitempty"`
+}
+
+// RandomValue returns a pseudo-random value of `size` bytes, generated
+// from `seed`. The same seed always produces the same value, across VUs
+// and test runs, so that payloads are reproducible, and benchmarks
+// comparable.
+//
+// By default, the value is a string of URL-safe characters. With the
+// `binary` option set, it is a Uint8Array of arbitrary bytes.
+func (mi *ModuleInstance) RandomValue(size int64, seed int64, options map[string]interface{}) sobek.Value {
+	rt := mi.vu.Runtime()
+
+	if size < 0 {
+		common.Throw(rt, fmt.Errorf("invalid size %d; expected a positive number", size))
+	}
+
+	var opts randomValueOptions
+	if err := decodeOptions(options, &opts); err != nil {
+		common.Throw(rt, fmt.Errorf("invalid randomValue options; reason: %w", err))
+	}
+
+	value := randomBytes(size, seed)
+	if opts.Binary {
+		array, err := rt.New(rt.Get("Uint8Array"), rt.ToValue(rt.NewArrayBuffer(value)))
+		if err != nil {
+			common.Throw(rt, err)
+		}
+
+		return array
+	}
+
+	return rt.ToValue(randomString(value))
+}
+
+// RandomKey returns `prefix` followed by a pseudo-random suffix generated
+// from `seed`. The same prefix and seed always produce the same key.
+func (mi *ModuleInstance) RandomKey(prefix string, seed int64) string {
+	if prefix == "" {
+		common.Throw(mi.vu.Runtime(), errors.New("the prefix of random keys cannot be empty"))
+	}
+
+	return prefix + randomString(randomBytes(randomKeyLength, seed))
+}
+
+// randomBytes returns `size` pseudo-random bytes generated from `seed`.
+//
+// As math/rand's seeded generator is guaranteed to produce the same
+// sequence across Go releases, so are the returned bytes.
+func randomBytes(size int64, seed int64) []byte {
+	b := make([]byte, size)
+	_, _ = rand.New(rand.NewSource(seed)).Read(b) //nolint:gosec
+
+	return b
+}
+
+// randomString maps each of the provided random bytes to a character of
+// randomAlphabet.
+func randomString(b []byte) string {
+	s := make([]byte, len(b))
+	for i, c := range b {
+		s[i] = randomAlphabet[c&63]
+	}
+
+	return string(s)
+}
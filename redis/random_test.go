@@ -0,0 +1,72 @@
+package redis
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRandomValue(t *testing.T) {
+	t.Parallel()
+
+	ts := newInitContextTestSetup(t)
+	exports := New().NewModuleInstance(ts.runtime.VU).Exports().Named
+	require.NoError(t, ts.rt.Set("randomValue", exports["randomValue"]))
+	require.NoError(t, ts.rt.Set("randomKey", exports["randomKey"]))
+
+	// The subtests share the same runtime, and thus can't run in parallel.
+
+	t.Run("values are reproducible", func(t *testing.T) {
+		_, err := ts.rt.RunString(`
+			const value = randomValue(1024, 42);
+			if (typeof value !== "string" || value.length !== 1024) {
+				throw 'unexpected value: ' + value
+			}
+			if (randomValue(1024, 42) !== value) {
+				throw 'expected the same seed to produce the same value'
+			}
+			if (randomValue(1024, 43) === value) {
+				throw 'expected distinct seeds to produce distinct values'
+			}
+		`)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("binary values are returned as Uint8Array", func(t *testing.T) {
+		_, err := ts.rt.RunString(`
+			const bytes = randomValue(64, 42, { binary: true });
+			if (!(bytes instanceof Uint8Array) || bytes.length !== 64) {
+				throw 'unexpected binary value: ' + bytes
+			}
+		`)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("keys are reproducible", func(t *testing.T) {
+		_, err := ts.rt.RunString(`
+			const key = randomKey("user:", 7);
+			if (!key.startsWith("user:") || key.length !== 21 || randomKey("user:", 7) !== key) {
+				throw 'unexpected key: ' + key
+			}
+		`)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("negative sizes are rejected", func(t *testing.T) {
+		_, err := ts.rt.RunString(`randomValue(-1, 42)`)
+
+		assert.ErrorContains(t, err, "invalid size -1")
+	})
+}
+
+func TestRandomBytes(t *testing.T) {
+	t.Parallel()
+
+	// The generated bytes must not change across releases, as scripts
+	// rely on them to produce comparable payloads.
+	assert.Equal(t, "S98H", randomString(randomBytes(4, 1)))
+}
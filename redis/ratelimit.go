@@ -0,0 +1,161 @@
+package redis
+
+import (
+	"fmt"
+
+	"github.com/grafana/sobek"
+	"github.com/redis/go-redis/v9"
+)
+
+// fixedWindowScript counts a request in the current window of the fixed
+// window rate limiter stored at KEYS[1], allowing ARGV[1] requests per
+// window of ARGV[2] milliseconds. The window starts with its first request.
+//
+// It returns {allowed, remaining, resetAfter}: 1 if the request is allowed,
+// and 0 otherwise, the number of requests still allowed in the window, and
+// the number of milliseconds until the window ends.
+var fixedWindowScript = redis.NewScript(fixedWindowSource)
+
+// fixedWindowSource is the source of fixedWindowScript.
+const fixedWindowSource = `
+local limit = tonumber(ARGV[1])
+
+local count = redis.call('INCR', KEYS[1])
+if count == 1 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+
+local allowed = 0
+if count <= limit then
+	allowed = 1
+end
+
+return {allowed, math.max(limit - count, 0), redis.call('PTTL', KEYS[1])}
+`
+
+// slidingWindowScript counts a request, identified by ARGV[3], in the
+// sliding window log rate limiter stored, as a sorted set of the requests
+// allowed by their time, at KEYS[1], allowing ARGV[1] requests in any
+// window of ARGV[2] milliseconds. Rejected requests aren't counted. The
+// time is the server's, so that all the callers share the same clock.
+//
+// It returns {allowed, remaining, resetAfter}, as fixedWindowScript does,
+// resetAfter being the number of milliseconds until the oldest request
+// leaves the window.
+var slidingWindowScript = redis.NewScript(slidingWindowSource)
+
+// slidingWindowSource is the source of slidingWindowScript.
+const slidingWindowSource = `
+local limit = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now - window)
+
+local count = redis.call('ZCARD', KEYS[1])
+local allowed = 0
+if count < limit then
+	redis.call('ZADD', KEYS[1], now, ARGV[3])
+	redis.call('PEXPIRE', KEYS[1], window)
+	count = count + 1
+	allowed = 1
+end
+
+local resetAfter = 0
+local oldest = redis.call('ZRANGE', KEYS[1], 0, 0, 'WITHSCORES')
+if #oldest > 0 then
+	resetAfter = tonumber(oldest[2]) + window - now
+end
+
+return {allowed, math.max(limit - count, 0), resetAfter}
+`
+
+// rateLimitOptions holds the options of the Client's rateLimit method.
+type rateLimitOptions struct {
+	// Limit is the number of requests allowed per window.
+	Limit int64 `json:"limit"`
+
+	// Window is the duration of the window, in milliseconds.
+	Window int64 `json:"window"`
+
+	// Algorithm is the rate limiting algorithm: "fixed", the default, or
+	// "sliding".
+	Algorithm string `json:"algorithm,omitempty"`
+}
+
+// RateLimit counts a request against the rate limiter stored at `key`,
+// which allows `limit` requests per `window` milliseconds, so that VUs,
+// across k6 instances, can pace their requests together.
+//
+// The "fixed" algorithm counts the requests of windows starting with their
+// first request, and the "sliding" one those of the last `window`
+// milliseconds, more accurately but at the cost of storing each request.
+// The limiter runs as a Lua script, so that concurrent requests can't race.
+//
+// The promise resolves with {allowed, remaining, resetAfter}: whether the
+// request is allowed, the number of requests still allowed, and the number
+// of milliseconds until more requests are allowed.
+func (c *Client) RateLimit(key string, options map[string]interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	var opts rateLimitOptions
+	if err := decodeOptions(options, &opts); err != nil {
+		reject(fmt.Errorf("invalid rateLimit options; reason: %w", err))
+		return promise
+	}
+
+	if opts.Limit <= 0 || opts.Window <= 0 {
+		reject(fmt.Errorf("invalid rateLimit options; limit and window must be positive numbers; got %d and %d",
+			opts.Limit, opts.Window))
+		return promise
+	}
+
+	var (
+		script *redis.Script
+		args   = []interface{}{opts.Limit, opts.Window}
+	)
+	switch opts.Algorithm {
+	case "", "fixed":
+		script = fixedWindowScript
+	case "sliding":
+		member, err := randomToken()
+		if err != nil {
+			reject(err)
+			return promise
+		}
+
+		script, args = slidingWindowScript, append(args, member)
+	default:
+		reject(fmt.Errorf("invalid rateLimit options; invalid algorithm: %q; expected %q or %q",
+			opts.Algorithm, "fixed", "sliding"))
+		return promise
+	}
+
+	go func() {
+		reply, err := script.Run(c.context(), c.redisClient, []string{key}, args...).Int64Slice()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		if len(reply) != 3 {
+			reject(fmt.Errorf("unexpected rateLimit reply: %v", reply))
+			return
+		}
+
+		resolve(map[string]interface{}{
+			"allowed":    reply[0] == 1,
+			"remaining":  reply[1],
+			"resetAfter": reply[2],
+		})
+	}()
+
+	return promise
+}
@@ -0,0 +1,68 @@
+package redis
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientRateLimit(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunT(t)
+	rs.RegisterCommandHandler("EVALSHA", func(c *Connection, _ []string) {
+		c.WriteError(errors.New("NOSCRIPT No matching script. Please use EVAL."))
+	})
+	rs.RegisterCommandHandler("EVAL", func(c *Connection, args []string) {
+		if args[0] == slidingWindowSource {
+			c.WriteValue([]interface{}{0, 0, 250})
+			return
+		}
+
+		c.WriteValue([]interface{}{1, 4, 1000})
+	})
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client('redis://%s');
+
+			redis.rateLimit("fixed", { limit: 5, window: 1000 })
+				.then(res => {
+					if (res.allowed !== true || res.remaining !== 4 || res.resetAfter !== 1000) {
+						throw 'unexpected value for fixed rateLimit result: ' + JSON.stringify(res)
+					}
+				})
+				.then(() => redis.rateLimit("sliding", { limit: 5, window: 1000, algorithm: "sliding" }))
+				.then(res => {
+					if (res.allowed !== false || res.remaining !== 0 || res.resetAfter !== 250) {
+						throw 'unexpected value for sliding rateLimit result: ' + JSON.stringify(res)
+					}
+				})
+				.then(() => redis.rateLimit("invalid", { limit: 0, window: 1000 }))
+				.then(
+					res => { throw 'expected rateLimit to fail' },
+					err => { if (!err.error().startsWith('invalid rateLimit options')) { throw 'unexpected error: ' + err.error() } }
+				)
+		`, rs.Addr()))
+
+		return err
+	})
+
+	assert.NoError(t, gotScriptErr)
+
+	commands := rs.GotCommands()
+	require.Len(t, commands, 5)
+	member := commands[3][6]
+	assert.Len(t, member, 32)
+	assert.Equal(t, [][]string{
+		{"HELLO", "2"},
+		{"EVALSHA", fixedWindowScript.Hash(), "1", "fixed", "5", "1000"},
+		{"EVAL", fixedWindowSource, "1", "fixed", "5", "1000"},
+		{"EVALSHA", slidingWindowScript.Hash(), "1", "sliding", "5", "1000", member},
+		{"EVAL", slidingWindowSource, "1", "sliding", "5", "1000", member},
+	}, commands)
+}
@@ -0,0 +1,264 @@
+package redis
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/grafana/sobek"
+	"github.com/redis/go-redis/v9"
+)
+
+// jsonRootPath is the path of the root of RedisJSON documents, used when no
+// path is provided.
+const jsonRootPath = "$"
+
+// jsonSetOptions holds the options of the Client's jsonSet method.
+type jsonSetOptions struct {
+	// NX only sets the value if the path does not exist yet.
+	NX bool `json:"nx,omitempty"`
+
+	// XX only sets the value if the path already exists.
+	XX bool `json:"xx,omitempty"`
+}
+
+// jsonPath returns `path`, or the root path if it is empty.
+func jsonPath(path string) string {
+	if path == "" {
+		return jsonRootPath
+	}
+
+	return path
+}
+
+// parseJSONReply parses a RedisJSON bulk string reply as JSON. Null replies
+// parse as nil.
+func parseJSONReply(reply interface{}) (interface{}, error) {
+	if reply == nil {
+		return nil, nil
+	}
+
+	raw, ok := reply.(string)
+	if !ok {
+		return nil, fmt.Errorf("unexpected reply of type %T; expected a JSON string", reply)
+	}
+
+	var parsed interface{}
+	if err := json.Unmarshal([]byte(raw), &parsed); err != nil {
+		return nil, fmt.Errorf("unable to parse reply as JSON; reason: %w", err)
+	}
+
+	return parsed, nil
+}
+
+// JsonSet sets the JSON value at `path`, "$" by default, in the RedisJSON
+// document stored at `key` to the JSON serialization of `value`. Setting the
+// root path of a missing key creates the document.
+//
+// The promise resolves with "OK", or null if the `nx` or `xx` option
+// prevented the value from being set. If `value` cannot be serialized to
+// JSON, the promise is rejected with an error.
+//
+//nolint:revive,stylecheck
+func (c *Client) JsonSet(key string, path string, value interface{}, options map[string]interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	var opts jsonSetOptions
+	if err := decodeOptions(options, &opts); err != nil {
+		reject(fmt.Errorf("invalid jsonSet options; reason: %w", err))
+		return promise
+	}
+
+	if opts.NX && opts.XX {
+		reject(errors.New("invalid jsonSet options; nx and xx are mutually exclusive"))
+		return promise
+	}
+
+	serialized, err := json.Marshal(value)
+	if err != nil {
+		reject(fmt.Errorf("unable to serialize value of key %q to JSON; reason: %w", key, err))
+		return promise
+	}
+
+	args := []interface{}{"json.set", key, jsonPath(path), serialized}
+	switch {
+	case opts.NX:
+		args = append(args, "nx")
+	case opts.XX:
+		args = append(args, "xx")
+	}
+
+	go func() {
+		status, err := c.redisClient.Do(c.context(), args...).Result()
+		if errors.Is(err, redis.Nil) {
+			resolve(nil)
+			return
+		}
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(status)
+	}()
+
+	return promise
+}
+
+// JsonGet returns the JSON values at the provided `paths` in the RedisJSON
+// document stored at `key`, or the whole document if none are provided.
+//
+// The promise resolves with the parsed value. As RedisJSON replies to paths
+// starting with "$" with the array of values they match, and to several
+// paths with an object mapping each path to its values, so does the
+// resolved value. If `key` does not exist, the promise resolves with null.
+//
+//nolint:revive,stylecheck
+func (c *Client) JsonGet(key string, paths ...string) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	args := []interface{}{"json.get", key}
+	for _, path := range paths {
+		args = append(args, path)
+	}
+
+	go func() {
+		reply, err := c.redisClient.Do(c.context(), args...).Result()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			reject(err)
+			return
+		}
+
+		value, err := parseJSONReply(reply)
+		if err != nil {
+			reject(fmt.Errorf("invalid jsonGet reply for key %q; %w", key, err))
+			return
+		}
+
+		resolve(value)
+	}()
+
+	return promise
+}
+
+// JsonMGet returns the JSON values at `path`, "$" by default, in the
+// RedisJSON documents stored at `keys`.
+//
+// The promise resolves with an array holding the parsed value of each key,
+// in order, null standing for missing keys.
+//
+//nolint:revive,stylecheck
+func (c *Client) JsonMGet(keys []string, path string) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	if len(keys) == 0 {
+		reject(errors.New("at least one key must be provided to jsonMGet"))
+		return promise
+	}
+
+	args := make([]interface{}, 0, len(keys)+2)
+	args = append(args, "json.mget")
+	for _, key := range keys {
+		args = append(args, key)
+	}
+	args = append(args, jsonPath(path))
+
+	go func() {
+		replies, err := c.redisClient.Do(c.context(), args...).Slice()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		values := make([]interface{}, len(replies))
+		for idx, reply := range replies {
+			values[idx], err = parseJSONReply(reply)
+			if err != nil {
+				reject(fmt.Errorf("invalid jsonMGet reply for key %q; %w", keys[idx], err))
+				return
+			}
+		}
+
+		resolve(values)
+	}()
+
+	return promise
+}
+
+// JsonDel deletes the JSON values at `path`, "$" by default, in the
+// RedisJSON document stored at `key`. Deleting the root path deletes the
+// key.
+//
+// The promise resolves with the number of values deleted.
+//
+//nolint:revive,stylecheck
+func (c *Client) JsonDel(key string, path string) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		deleted, err := c.redisClient.Do(c.context(), "json.del", key, jsonPath(path)).Int64()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(deleted)
+	}()
+
+	return promise
+}
+
+// JsonNumIncrBy increments the numbers at `path`, in the RedisJSON document
+// stored at `key`, by `increment`.
+//
+// The promise resolves with the parsed reply: the array of incremented
+// values, null standing for the values matched by `path` which are not
+// numbers, or the incremented value itself for paths not starting with "$".
+//
+//nolint:revive,stylecheck
+func (c *Client) JsonNumIncrBy(key string, path string, increment float64) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		reply, err := c.redisClient.Do(c.context(), "json.numincrby", key, jsonPath(path), increment).Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		value, err := parseJSONReply(reply)
+		if err != nil {
+			reject(fmt.Errorf("invalid jsonNumIncrBy reply for key %q; %w", key, err))
+			return
+		}
+
+		resolve(value)
+	}()
+
+	return promise
+}
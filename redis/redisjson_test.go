@@ -0,0 +1,90 @@
+package redis
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientRedisJSON(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunT(t)
+	rs.RegisterCommandHandler("JSON.SET", func(c *Connection, args []string) {
+		if len(args) > 3 && args[3] == "nx" {
+			c.WriteNull()
+			return
+		}
+
+		c.WriteOK()
+	})
+	rs.RegisterCommandHandler("JSON.GET", func(c *Connection, args []string) {
+		switch {
+		case args[0] == "missing":
+			c.WriteNull()
+		case len(args) == 1:
+			c.WriteBulkString(`{"user":"alice","visits":3}`)
+		default:
+			c.WriteBulkString(`[3]`)
+		}
+	})
+	rs.RegisterCommandHandler("JSON.MGET", func(c *Connection, _ []string) {
+		c.WriteValue([]interface{}{`["alice"]`, nil})
+	})
+	rs.RegisterCommandHandler("JSON.DEL", func(c *Connection, _ []string) {
+		c.WriteInteger(1)
+	})
+	rs.RegisterCommandHandler("JSON.NUMINCRBY", func(c *Connection, _ []string) {
+		c.WriteBulkString(`[5.5]`)
+	})
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client('redis://%s');
+
+			redis.jsonSet("session", "", { user: "alice", visits: 3 })
+				.then(res => { if (res !== "OK") { throw 'unexpected value for jsonSet result: ' + res } })
+				.then(() => redis.jsonSet("session", "$.user", "bob", { nx: true }))
+				.then(res => { if (res !== null) { throw 'unexpected value for jsonSet result: ' + res } })
+				.then(() => redis.jsonGet("session"))
+				.then(res => { if (res.user !== "alice" || res.visits !== 3) { throw 'unexpected value for jsonGet result: ' + JSON.stringify(res) } })
+				.then(() => redis.jsonGet("session", "$.visits"))
+				.then(res => { if (JSON.stringify(res) !== '[3]') { throw 'unexpected value for jsonGet result: ' + JSON.stringify(res) } })
+				.then(() => redis.jsonGet("missing"))
+				.then(res => { if (res !== null) { throw 'unexpected value for jsonGet result: ' + JSON.stringify(res) } })
+				.then(() => redis.jsonMGet(["session", "missing"], "$.user"))
+				.then(res => { if (JSON.stringify(res) !== '[["alice"],null]') { throw 'unexpected value for jsonMGet result: ' + JSON.stringify(res) } })
+				.then(() => redis.jsonNumIncrBy("session", "$.visits", 2.5))
+				.then(res => { if (JSON.stringify(res) !== '[5.5]') { throw 'unexpected value for jsonNumIncrBy result: ' + JSON.stringify(res) } })
+				.then(() => redis.jsonDel("session"))
+				.then(res => { if (res !== 1) { throw 'unexpected value for jsonDel result: ' + res } })
+				.then(() => redis.jsonSet("session", "$", {}, { nx: true, xx: true }))
+				.then(
+					res => { throw 'expected jsonSet to fail' },
+					err => { if (!err.error().includes('mutually exclusive')) { throw 'unexpected error: ' + err.error() } }
+				)
+				.then(() => redis.jsonSet("session", "$", () => {}))
+				.then(
+					res => { throw 'expected jsonSet to fail' },
+					err => { if (!err.error().startsWith('unable to serialize')) { throw 'unexpected error: ' + err.error() } }
+				)
+		`, rs.Addr()))
+
+		return err
+	})
+
+	assert.NoError(t, gotScriptErr)
+	assert.Equal(t, [][]string{
+		{"HELLO", "2"},
+		{"JSON.SET", "session", "$", `{"user":"alice","visits":3}`},
+		{"JSON.SET", "session", "$.user", `"bob"`, "nx"},
+		{"JSON.GET", "session"},
+		{"JSON.GET", "session", "$.visits"},
+		{"JSON.GET", "missing"},
+		{"JSON.MGET", "session", "missing", "$.user"},
+		{"JSON.NUMINCRBY", "session", "$.visits", "2.5"},
+		{"JSON.DEL", "session", "$"},
+	}, rs.GotCommands())
+}
@@ -0,0 +1,288 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/grafana/sobek"
+	"github.com/redis/go-redis/v9"
+)
+
+// Wait waits for the write commands previously sent on the connection to be
+// acknowledged by at least `numReplicas` replicas, or for `timeout`
+// milliseconds to pass, whichever comes first.
+//
+// WAIT only accounts for the writes sent on the connection it is itself
+// sent on. As the client pools its connections, asserting the durability of
+// specific writes requires sending them, along with WAIT, in a pipeline,
+// whose commands share a single connection.
+//
+// The promise resolves with the number of replicas which acknowledged the
+// writes. Wait isn't supported by cluster clients.
+func (c *Client) Wait(numReplicas int, timeout int64) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	if c.redisOptions.isCluster() {
+		reject(errors.New("wait is not supported by cluster clients"))
+		return promise
+	}
+
+	if numReplicas < 0 {
+		reject(fmt.Errorf("invalid number of replicas: %d; expected a positive number", numReplicas))
+		return promise
+	}
+
+	// As for blocking commands, a WAIT without a timeout would block until
+	// enough replicas acknowledge the writes, possibly forever.
+	if timeout <= 0 {
+		reject(fmt.Errorf("invalid wait timeout %d; expected a positive number of milliseconds", timeout))
+		return promise
+	}
+	duration := time.Duration(timeout) * time.Millisecond
+
+	// WAIT isn't part of the go-redis UniversalClient interface, though
+	// all the go-redis clients implement it.
+	waiter, ok := c.redisClient.(interface {
+		Wait(ctx context.Context, numSlaves int, timeout time.Duration) *redis.IntCmd
+	})
+	if !ok {
+		reject(errors.New("wait is not supported by the client"))
+		return promise
+	}
+
+	go func() {
+		var replicas int64
+		err := c.runBlocking("wait", duration, func(ctx context.Context) (err error) {
+			replicas, err = waiter.Wait(ctx, numReplicas, duration).Result()
+			return err
+		})
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(replicas)
+	}()
+
+	return promise
+}
+
+// triggerFailoverOptions holds the options of the Client's failover method.
+// Which options are supported depends on the client's mode.
+type triggerFailoverOptions struct {
+	// To is the address, as host:port, of the replica single-node clients
+	// fail over to. By default, the server picks one.
+	To string `json:"to,omitempty"`
+
+	// Force makes single-node clients fail over to the `to` replica even
+	// if it didn't catch up with the master within the timeout, and cluster
+	// clients fail over without the master's agreement.
+	Force bool `json:"force,omitempty"`
+
+	// Takeover makes cluster clients fail over without the agreement of the
+	// rest of the cluster.
+	Takeover bool `json:"takeover,omitempty"`
+
+	// Abort aborts the ongoing failover of single-node clients.
+	Abort bool `json:"abort,omitempty"`
+
+	// Timeout is the time, in milliseconds, single-node clients wait for
+	// the replica to catch up with the master before aborting the failover.
+	Timeout int64 `json:"timeout,omitempty"`
+
+	// Node is the address, as host:port, of the replica cluster clients
+	// promote.
+	Node string `json:"node,omitempty"`
+}
+
+// Failover triggers a failover, promoting a replica to master, so that the
+// behavior of the system under test can be observed while the load is
+// running. Depending on the client's mode, it sends:
+//   - FAILOVER to the master, for single-node clients.
+//   - SENTINEL FAILOVER to the sentinels, for sentinel clients.
+//   - CLUSTER FAILOVER to the `node` replica, for cluster clients.
+//
+// The promise resolves with "OK" once the failover started. It completes
+// asynchronously.
+func (c *Client) Failover(options map[string]interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	var opts triggerFailoverOptions
+	if err := decodeOptions(options, &opts); err != nil {
+		reject(fmt.Errorf("invalid failover options; reason: %w", err))
+		return promise
+	}
+
+	var failover func(ctx context.Context) (string, error)
+	switch {
+	case c.redisOptions.MasterName != "":
+		if opts != (triggerFailoverOptions{}) {
+			reject(errors.New("invalid failover options; sentinel clients don't support any option"))
+			return promise
+		}
+
+		failover = c.sentinelFailover
+	case c.redisOptions.isCluster():
+		cluster, err := c.clusterClient("failover")
+		if err != nil {
+			reject(err)
+			return promise
+		}
+
+		args, err := clusterFailoverArgs(opts)
+		if err != nil {
+			reject(fmt.Errorf("invalid failover options; %w", err))
+			return promise
+		}
+
+		failover = func(ctx context.Context) (string, error) {
+			node, err := nodeClient(ctx, cluster, opts.Node)
+			if err != nil {
+				return "", err
+			}
+
+			cmd := redis.NewStatusCmd(ctx, args...)
+			_ = node.Process(ctx, cmd)
+			return cmd.Result()
+		}
+	default:
+		args, err := failoverArgs(opts)
+		if err != nil {
+			reject(fmt.Errorf("invalid failover options; %w", err))
+			return promise
+		}
+
+		failover = func(ctx context.Context) (string, error) {
+			cmd := redis.NewStatusCmd(ctx, args...)
+			_ = c.redisClient.Process(ctx, cmd)
+			return cmd.Result()
+		}
+	}
+
+	go func() {
+		status, err := failover(c.context())
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(status)
+	}()
+
+	return promise
+}
+
+// failoverArgs returns the arguments of the FAILOVER command matching the
+// options of a single-node client.
+func failoverArgs(opts triggerFailoverOptions) ([]interface{}, error) {
+	if opts.Node != "" || opts.Takeover {
+		return nil, errors.New("the node and takeover options are only supported by cluster clients")
+	}
+
+	if opts.Timeout < 0 {
+		return nil, fmt.Errorf("invalid timeout: %d; expected a positive number", opts.Timeout)
+	}
+
+	if opts.Abort {
+		if opts != (triggerFailoverOptions{Abort: true}) {
+			return nil, errors.New("the abort option can't be combined with other options")
+		}
+
+		return []interface{}{"failover", "abort"}, nil
+	}
+
+	args := []interface{}{"failover"}
+	if opts.To != "" {
+		host, port, err := net.SplitHostPort(opts.To)
+		if err != nil {
+			return nil, fmt.Errorf("invalid to option: %q; expected host:port", opts.To)
+		}
+		if _, err := strconv.Atoi(port); err != nil {
+			return nil, fmt.Errorf("invalid to option: %q; expected host:port", opts.To)
+		}
+
+		args = append(args, "to", host, port)
+	}
+
+	if opts.Force {
+		if opts.To == "" || opts.Timeout == 0 {
+			return nil, errors.New("the force option requires the to and timeout options")
+		}
+
+		args = append(args, "force")
+	}
+
+	if opts.Timeout > 0 {
+		args = append(args, "timeout", opts.Timeout)
+	}
+
+	return args, nil
+}
+
+// clusterFailoverArgs returns the arguments of the CLUSTER FAILOVER command
+// matching the options of a cluster client.
+func clusterFailoverArgs(opts triggerFailoverOptions) ([]interface{}, error) {
+	if opts.To != "" || opts.Abort || opts.Timeout != 0 {
+		return nil, errors.New("the to, abort, and timeout options are only supported by single-node clients")
+	}
+
+	if opts.Node == "" {
+		return nil, errors.New("the node option, the address of the replica to promote, is required by cluster clients")
+	}
+
+	args := []interface{}{"cluster", "failover"}
+	switch {
+	case opts.Force && opts.Takeover:
+		return nil, errors.New("the force and takeover options can't be combined")
+	case opts.Force:
+		args = append(args, "force")
+	case opts.Takeover:
+		args = append(args, "takeover")
+	}
+
+	return args, nil
+}
+
+// sentinelFailover sends SENTINEL FAILOVER to the sentinels of the client's
+// master, in turn, until one of them accepts it.
+func (c *Client) sentinelFailover(ctx context.Context) (string, error) {
+	fopts := c.redisOptions.Failover()
+
+	var err error
+	for _, addr := range fopts.SentinelAddrs {
+		sentinel := redis.NewSentinelClient(&redis.Options{
+			Addr:         addr,
+			Dialer:       fopts.Dialer,
+			Username:     fopts.SentinelUsername,
+			Password:     fopts.SentinelPassword,
+			Protocol:     fopts.Protocol,
+			DialTimeout:  fopts.DialTimeout,
+			ReadTimeout:  fopts.ReadTimeout,
+			WriteTimeout: fopts.WriteTimeout,
+			TLSConfig:    fopts.TLSConfig,
+		})
+
+		var status string
+		status, err = sentinel.Failover(ctx, fopts.MasterName).Result()
+		_ = sentinel.Close()
+		if err == nil {
+			return status, nil
+		}
+	}
+
+	return "", fmt.Errorf("no sentinel triggered the failover of master %q; reason: %w", fopts.MasterName, err)
+}
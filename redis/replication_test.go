@@ -0,0 +1,109 @@
+package redis
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientReplicationCommands(t *testing.T) {
+	t.Parallel()
+
+	t.Run("wait and failover are sent to the master", func(t *testing.T) {
+		t.Parallel()
+
+		ts := newTestSetup(t)
+		rs := RunT(t)
+		rs.RegisterCommandHandler("WAIT", func(c *Connection, _ []string) {
+			c.WriteInteger(1)
+		})
+		rs.RegisterCommandHandler("FAILOVER", func(c *Connection, _ []string) {
+			c.WriteOK()
+		})
+
+		gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+			_, err := ts.rt.RunString(fmt.Sprintf(`
+				const redis = new Client('redis://%s');
+
+				redis.wait(1, 500)
+					.then(res => { if (res !== 1) { throw 'unexpected value for wait result: ' + res } })
+					.then(() => redis.failover({ to: "replica:6380", force: true, timeout: 1000 }))
+					.then(res => { if (res !== "OK") { throw 'unexpected value for failover result: ' + res } })
+					.then(() => redis.failover({ abort: true }))
+					.then(() => redis.wait(1, 0))
+					.then(
+						res => { throw 'expected wait to fail' },
+						err => { if (err.error() !== 'invalid wait timeout 0; expected a positive number of milliseconds') { throw 'unexpected error: ' + err.error() } }
+					)
+					.then(() => redis.failover({ force: true }))
+					.then(
+						res => { throw 'expected failover to fail' },
+						err => { if (!err.error().includes('requires the to and timeout options')) { throw 'unexpected error: ' + err.error() } }
+					)
+			`, rs.Addr()))
+
+			return err
+		})
+
+		assert.NoError(t, gotScriptErr)
+		assert.Equal(t, [][]string{
+			{"HELLO", "2"},
+			{"WAIT", "1", "500"},
+			{"FAILOVER", "to", "replica", "6380", "force", "timeout", "1000"},
+			{"FAILOVER", "abort"},
+		}, rs.GotCommands())
+	})
+
+	t.Run("sentinel clients ask the sentinels to fail over", func(t *testing.T) {
+		t.Parallel()
+
+		ts := newTestSetup(t)
+		rs := RunT(t)
+		rs.RegisterCommandHandler("SENTINEL", func(c *Connection, _ []string) {
+			c.WriteOK()
+		})
+
+		gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+			_, err := ts.rt.RunString(fmt.Sprintf(`
+				const redis = new Client({ masterName: "mymaster", socket: { host: "%s", port: %d } });
+
+				redis.failover()
+					.then(res => { if (res !== "OK") { throw 'unexpected value for failover result: ' + res } })
+			`, rs.Addr().IP.String(), rs.Addr().Port))
+
+			return err
+		})
+
+		assert.NoError(t, gotScriptErr)
+		assert.Contains(t, rs.GotCommands(), []string{"SENTINEL", "failover", "mymaster"})
+	})
+}
+
+func TestFailoverArgs(t *testing.T) {
+	t.Parallel()
+
+	args, err := failoverArgs(triggerFailoverOptions{To: "replica:6380", Timeout: 500})
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"failover", "to", "replica", "6380", "timeout", int64(500)}, args)
+
+	args, err = clusterFailoverArgs(triggerFailoverOptions{Node: "replica:6380", Takeover: true})
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"cluster", "failover", "takeover"}, args)
+
+	for name, tc := range map[string]struct {
+		args   func(triggerFailoverOptions) ([]interface{}, error)
+		opts   triggerFailoverOptions
+		expErr string
+	}{
+		"abort with to":        {failoverArgs, triggerFailoverOptions{Abort: true, To: "replica:6380"}, "can't be combined"},
+		"invalid to":           {failoverArgs, triggerFailoverOptions{To: "replica"}, "expected host:port"},
+		"node":                 {failoverArgs, triggerFailoverOptions{Node: "replica:6380"}, "only supported by cluster clients"},
+		"cluster without node": {clusterFailoverArgs, triggerFailoverOptions{}, "is required by cluster clients"},
+		"force and takeover":   {clusterFailoverArgs, triggerFailoverOptions{Node: "replica:6380", Force: true, Takeover: true}, "can't be combined"},
+	} {
+		_, err := tc.args(tc.opts)
+		assert.ErrorContains(t, err, tc.expErr, name)
+	}
+}
@@ -0,0 +1,127 @@
+package redis
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// The values of the returnTypes option.
+const (
+	// returnTypesString resolves the values read as they are stored: as
+	// strings.
+	returnTypesString = "string"
+
+	// returnTypesAuto resolves the values read that are numbers as JS
+	// numbers, and those that are "true" or "false" as booleans.
+	returnTypesAuto = "auto"
+)
+
+// maxSafeInteger is the largest integer JS numbers represent exactly.
+const maxSafeInteger = 1<<53 - 1
+
+// typedValue returns `value`, a value read by one of the Client's string,
+// or hash, methods, typed according to its returnTypes option.
+func (c *Client) typedValue(value interface{}) interface{} {
+	if c.redisOptions == nil || c.redisOptions.ReturnTypes != returnTypesAuto {
+		return value
+	}
+
+	switch v := value.(type) {
+	case string:
+		return autoTyped(v)
+	case []string:
+		typed := make([]interface{}, len(v))
+		for idx, s := range v {
+			typed[idx] = autoTyped(s)
+		}
+		return typed
+	case []interface{}:
+		typed := make([]interface{}, len(v))
+		for idx, item := range v {
+			if s, ok := item.(string); ok {
+				typed[idx] = autoTyped(s)
+			} else {
+				typed[idx] = item
+			}
+		}
+		return typed
+	case map[string]string:
+		typed := make(map[string]interface{}, len(v))
+		for field, s := range v {
+			typed[field] = autoTyped(s)
+		}
+		return typed
+	case map[string]interface{}:
+		typed := make(map[string]interface{}, len(v))
+		for field, item := range v {
+			if s, ok := item.(string); ok {
+				typed[field] = autoTyped(s)
+			} else {
+				typed[field] = item
+			}
+		}
+		return typed
+	default:
+		return value
+	}
+}
+
+// autoTyped returns `value` as a number if it is a decimal number, written
+// as Redis writes them, such as "42", "-1", or "0.5", as a boolean if it is
+// "true" or "false", and as is otherwise.
+//
+// Integers beyond the range JS numbers represent exactly, such as large
+// IDs, and numbers written otherwise, such as "007", "1e3", or "inf", are
+// left as strings, as converting them would lose information.
+func autoTyped(value string) interface{} {
+	switch value {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+
+	if !isDecimal(value) {
+		return value
+	}
+
+	if !strings.Contains(value, ".") {
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || n > maxSafeInteger || n < -maxSafeInteger {
+			return value
+		}
+		return n
+	}
+
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsInf(f, 0) {
+		return value
+	}
+
+	return f
+}
+
+// isDecimal returns whether `value` is a decimal number without leading
+// zeros, exponent, or sign other than a leading minus.
+func isDecimal(value string) bool {
+	value = strings.TrimPrefix(value, "-")
+
+	integer, fraction, hasFraction := strings.Cut(value, ".")
+	if integer == "" || (len(integer) > 1 && integer[0] == '0') || !isDigits(integer) {
+		return false
+	}
+
+	return !hasFraction || (fraction != "" && isDigits(fraction))
+}
+
+// isDigits returns whether `s` only holds ASCII digits.
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
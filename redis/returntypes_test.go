@@ -0,0 +1,89 @@
+package redis
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientReturnTypes(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunT(t)
+	rs.RegisterCommandHandler("GET", func(c *Connection, args []string) {
+		c.WriteBulkString(map[string]string{"counter": "42", "ratio": "0.5", "id": "9007199254740993"}[args[0]])
+	})
+	rs.RegisterCommandHandler("MGET", func(c *Connection, _ []string) {
+		c.WriteValue([]interface{}{"-1", nil, "007"})
+	})
+	rs.RegisterCommandHandler("HGETALL", func(c *Connection, _ []string) {
+		c.WriteArray("visits", "3", "active", "true", "name", "alice")
+	})
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client({ socket: { host: "%s", port: %d }, returnTypes: "auto" });
+			const strings = new Client({ socket: { host: "%s", port: %d } });
+
+			if (redis.options().returnTypes !== "auto" || strings.options().returnTypes !== "string") {
+				throw 'expected the returnTypes option to be reported';
+			}
+
+			redis.get("counter")
+				.then(res => { if (res !== 42) { throw 'unexpected value for get result: ' + res } })
+				.then(() => redis.get("ratio"))
+				.then(res => { if (res !== 0.5) { throw 'unexpected value for get result: ' + res } })
+				.then(() => redis.get("id"))
+				.then(res => { if (res !== "9007199254740993") { throw 'unexpected value for get result: ' + res } })
+				.then(() => redis.mget("a", "b", "c"))
+				.then(res => {
+					if (res[0] !== -1 || res[1] !== null || res[2] !== "007") {
+						throw 'unexpected value for mget result: ' + JSON.stringify(res)
+					}
+				})
+				.then(() => redis.hgetall("user"))
+				.then(res => {
+					if (res.visits !== 3 || res.active !== true || res.name !== "alice") {
+						throw 'unexpected value for hgetall result: ' + JSON.stringify(res)
+					}
+				})
+				.then(() => strings.get("counter"))
+				.then(res => { if (res !== "42") { throw 'unexpected value for get result: ' + res } })
+		`, rs.Addr().IP, rs.Addr().Port, rs.Addr().IP, rs.Addr().Port))
+
+		return err
+	})
+
+	assert.NoError(t, gotScriptErr)
+}
+
+func TestAutoTyped(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		value string
+		want  interface{}
+	}{
+		{"42", int64(42)},
+		{"-7", int64(-7)},
+		{"0", int64(0)},
+		{"10.25", 10.25},
+		{"-0.5", -0.5},
+		{"true", true},
+		{"false", false},
+		{"9007199254740991", int64(9007199254740991)},
+		{"9007199254740992", "9007199254740992"},
+		{"007", "007"},
+		{"1e3", "1e3"},
+		{"+1", "+1"},
+		{"1.", "1."},
+		{".5", ".5"},
+		{"inf", "inf"},
+		{"", ""},
+		{"True", "True"},
+	} {
+		assert.Equal(t, tc.want, autoTyped(tc.value), tc.value)
+	}
+}
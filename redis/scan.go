@@ -0,0 +1,590 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"github.com/grafana/sobek"
+	"github.com/redis/go-redis/v9"
+)
+
+// scanOptions holds the options of the Client's scan methods.
+type scanOptions struct {
+	// Match is the glob-style pattern keys must match.
+	Match string `json:"match,omitempty"`
+
+	// Count is the number of keys each SCAN call is hinted to return.
+	Count int64 `json:"count,omitempty"`
+
+	// Type is the type of value keys must hold.
+	Type string `json:"type,omitempty"`
+}
+
+// elementScanOptions holds the options of the Client's methods scanning
+// the elements of a value, such as hscan.
+type elementScanOptions struct {
+	// Match is the glob-style pattern elements must match.
+	Match string `json:"match,omitempty"`
+
+	// Count is the number of elements each call is hinted to return.
+	Count int64 `json:"count,omitempty"`
+}
+
+// parseCursor parses the provided SCAN cursor, either a number, or the
+// string returned by a previous call, as cursors may exceed the precision
+// of JS numbers.
+func parseCursor(cursor interface{}) (uint64, error) {
+	parsed, err := strconv.ParseUint(fmt.Sprint(cursor), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid cursor %v; expected a cursor returned by a previous call, or 0", cursor)
+	}
+
+	return parsed, nil
+}
+
+// ScanShard scans the whole keyspace, and returns the keys assigned to the
+// shard `shardIndex` out of `shardCount`. It allows many VUs to
+// cooperatively process the whole keyspace: each VU scanning a distinct
+// shard index gets a distinct subset of the keys, and together, all the
+// shard indexes cover all the keys.
+//
+// Keys are assigned to shards deterministically, based on the FNV-1a hash
+// of their name, so the assignment doesn't depend on the order keys are
+// scanned in, nor on the VU scanning them. Keys are listed once, even if
+// SCAN returns them several times. Cluster clients scan all the master
+// nodes.
+//
+// The `match`, `count`, and `type` options are passed to SCAN as the
+// MATCH, COUNT, and TYPE arguments.
+//
+// The promise resolves with the array of keys of the shard.
+func (c *Client) ScanShard(shardIndex, shardCount int64, options map[string]interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	if shardCount < 1 {
+		reject(fmt.Errorf("invalid shard count %d; expected a positive number", shardCount))
+		return promise
+	}
+
+	if shardIndex < 0 || shardIndex >= shardCount {
+		reject(fmt.Errorf("invalid shard index %d; expected a number between 0 and %d", shardIndex, shardCount-1))
+		return promise
+	}
+
+	var opts scanOptions
+	if err := decodeOptions(options, &opts); err != nil {
+		reject(fmt.Errorf("invalid scanShard options; reason: %w", err))
+		return promise
+	}
+
+	go func() {
+		keys, err := c.scanUniqueKeys(c.context(), opts, func(key string) bool {
+			return keyShard(key, shardCount) == shardIndex
+		})
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(keys)
+	}()
+
+	return promise
+}
+
+// Scan iterates over the keyspace, starting from `cursor`: 0 to start a new
+// iteration, or the cursor returned by the previous call. The `match`,
+// `count`, and `type` options are passed to SCAN as the MATCH, COUNT, and
+// TYPE arguments.
+//
+// The promise resolves with an object holding the `cursor` to continue the
+// iteration from, as a string, which is "0" once the iteration is
+// complete, and the `keys` read.
+//
+// Scan is not supported by cluster clients, as each node has a cursor of
+// its own: scanAll scans all their nodes.
+func (c *Client) Scan(cursor interface{}, options map[string]interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	if _, ok := c.redisClient.(*redis.ClusterClient); ok {
+		reject(errors.New("scan is not supported by cluster clients, as each node has a cursor of its own; " +
+			"use scanAll instead"))
+		return promise
+	}
+
+	start, err := parseCursor(cursor)
+	if err != nil {
+		reject(err)
+		return promise
+	}
+
+	var opts scanOptions
+	if err := decodeOptions(options, &opts); err != nil {
+		reject(fmt.Errorf("invalid scan options; reason: %w", err))
+		return promise
+	}
+
+	go func() {
+		keys, next, err := c.redisClient.ScanType(c.context(), start, opts.Match, opts.Count, opts.Type).Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(map[string]interface{}{
+			"cursor": strconv.FormatUint(next, 10),
+			"keys":   keys,
+		})
+	}()
+
+	return promise
+}
+
+// ScanAll iterates over the whole keyspace with SCAN, handling the cursors,
+// and returns the keys matching the `match`, `count`, and `type` options.
+// Keys are listed once, even if SCAN returns them several times. Cluster
+// clients scan all the master nodes.
+func (c *Client) ScanAll(options map[string]interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	var opts scanOptions
+	if err := decodeOptions(options, &opts); err != nil {
+		reject(fmt.Errorf("invalid scanAll options; reason: %w", err))
+		return promise
+	}
+
+	go func() {
+		keys, err := c.scanUniqueKeys(c.context(), opts, func(string) bool { return true })
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(keys)
+	}()
+
+	return promise
+}
+
+// HscanAll iterates over all the fields of the hash stored at `key` with
+// HSCAN, handling the cursors. The promise resolves with an object mapping
+// the fields matching the `match` option to their values.
+func (c *Client) HscanAll(key string, options map[string]interface{}) *sobek.Promise {
+	return c.scanAllElements("hscanAll", options, func(ctx context.Context, cursor uint64, opts elementScanOptions) *redis.ScanCmd {
+		return c.redisClient.HScan(ctx, key, cursor, opts.Match, opts.Count)
+	}, func(elements []string) (interface{}, error) {
+		return hashEntries(elements), nil
+	})
+}
+
+// Sscan iterates over the members of the set stored at `key`, starting
+// from `cursor`, as hscan does over the fields of a hash.
+//
+// The promise resolves with an object holding the `cursor` to continue the
+// iteration from, and the `members` read.
+func (c *Client) Sscan(key string, cursor interface{}, options map[string]interface{}) *sobek.Promise {
+	return c.scanElements("sscan", cursor, options, func(ctx context.Context, cursor uint64, opts elementScanOptions) *redis.ScanCmd {
+		return c.redisClient.SScan(ctx, key, cursor, opts.Match, opts.Count)
+	}, func(elements []string) (string, interface{}, error) {
+		return "members", elements, nil
+	})
+}
+
+// SscanAll iterates over all the members of the set stored at `key` with
+// SSCAN, handling the cursors. The promise resolves with the members
+// matching the `match` option, listed once each.
+func (c *Client) SscanAll(key string, options map[string]interface{}) *sobek.Promise {
+	return c.scanAllElements("sscanAll", options, func(ctx context.Context, cursor uint64, opts elementScanOptions) *redis.ScanCmd {
+		return c.redisClient.SScan(ctx, key, cursor, opts.Match, opts.Count)
+	}, func(elements []string) (interface{}, error) {
+		seen := make(map[string]struct{}, len(elements))
+		members := make([]string, 0, len(elements))
+		for _, member := range elements {
+			if _, ok := seen[member]; ok {
+				continue
+			}
+			seen[member] = struct{}{}
+			members = append(members, member)
+		}
+
+		return members, nil
+	})
+}
+
+// Zscan iterates over the members of the sorted set stored at `key`,
+// starting from `cursor`, as hscan does over the fields of a hash.
+//
+// The promise resolves with an object holding the `cursor` to continue the
+// iteration from, and the `members` read, as {member, score} objects.
+func (c *Client) Zscan(key string, cursor interface{}, options map[string]interface{}) *sobek.Promise {
+	return c.scanElements("zscan", cursor, options, func(ctx context.Context, cursor uint64, opts elementScanOptions) *redis.ScanCmd {
+		return c.redisClient.ZScan(ctx, key, cursor, opts.Match, opts.Count)
+	}, func(elements []string) (string, interface{}, error) {
+		members, err := scannedScoredMembers(elements)
+		return "members", members, err
+	})
+}
+
+// ZscanAll iterates over all the members of the sorted set stored at `key`
+// with ZSCAN, handling the cursors. The promise resolves with the members
+// matching the `match` option, listed once each, as {member, score}
+// objects.
+func (c *Client) ZscanAll(key string, options map[string]interface{}) *sobek.Promise {
+	return c.scanAllElements("zscanAll", options, func(ctx context.Context, cursor uint64, opts elementScanOptions) *redis.ScanCmd {
+		return c.redisClient.ZScan(ctx, key, cursor, opts.Match, opts.Count)
+	}, func(elements []string) (interface{}, error) {
+		return scannedScoredMembers(elements)
+	})
+}
+
+// elementScanFunc sends the HSCAN, SSCAN, or ZSCAN command reading the
+// elements following `cursor`.
+type elementScanFunc func(ctx context.Context, cursor uint64, opts elementScanOptions) *redis.ScanCmd
+
+// scanElements sends a single element scan command, from `cursor`, and
+// resolves with the next cursor, along with the elements read, converted
+// by `convert`, under the property it names.
+func (c *Client) scanElements(
+	name string, cursor interface{}, options map[string]interface{}, scan elementScanFunc,
+	convert func(elements []string) (string, interface{}, error),
+) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	start, err := parseCursor(cursor)
+	if err != nil {
+		reject(err)
+		return promise
+	}
+
+	var opts elementScanOptions
+	if err := decodeOptions(options, &opts); err != nil {
+		reject(fmt.Errorf("invalid %s options; reason: %w", name, err))
+		return promise
+	}
+
+	go func() {
+		elements, next, err := scan(c.context(), start, opts).Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		property, converted, err := convert(elements)
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(map[string]interface{}{
+			"cursor": strconv.FormatUint(next, 10),
+			property: converted,
+		})
+	}()
+
+	return promise
+}
+
+// scanAllElements sends element scan commands until the whole value is
+// scanned, and resolves with all the elements read, converted by
+// `convert`.
+func (c *Client) scanAllElements(
+	name string, options map[string]interface{}, scan elementScanFunc,
+	convert func(elements []string) (interface{}, error),
+) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	var opts elementScanOptions
+	if err := decodeOptions(options, &opts); err != nil {
+		reject(fmt.Errorf("invalid %s options; reason: %w", name, err))
+		return promise
+	}
+
+	go func() {
+		var (
+			ctx      = c.context()
+			cursor   uint64
+			elements []string
+		)
+		for {
+			batch, next, err := scan(ctx, cursor, opts).Result()
+			if err != nil {
+				reject(err)
+				return
+			}
+
+			elements = append(elements, batch...)
+			if next == 0 {
+				break
+			}
+			cursor = next
+		}
+
+		converted, err := convert(elements)
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(converted)
+	}()
+
+	return promise
+}
+
+// hashEntries converts the flat field and value pairs returned by HSCAN to
+// an object mapping fields to their values.
+func hashEntries(elements []string) map[string]interface{} {
+	entries := make(map[string]interface{}, len(elements)/2)
+	for idx := 0; idx+1 < len(elements); idx += 2 {
+		entries[elements[idx]] = elements[idx+1]
+	}
+
+	return entries
+}
+
+// scannedScoredMembers converts the flat member and score pairs returned by
+// ZSCAN to {member, score} objects, listing each member once.
+func scannedScoredMembers(elements []string) ([]map[string]interface{}, error) {
+	seen := make(map[string]struct{}, len(elements)/2)
+	members := make([]redis.Z, 0, len(elements)/2)
+	for idx := 0; idx+1 < len(elements); idx += 2 {
+		if _, ok := seen[elements[idx]]; ok {
+			continue
+		}
+		seen[elements[idx]] = struct{}{}
+
+		score, err := strconv.ParseFloat(elements[idx+1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid score %q for member %q", elements[idx+1], elements[idx])
+		}
+
+		members = append(members, redis.Z{Member: elements[idx], Score: score})
+	}
+
+	return scoredMembersToJS(members), nil
+}
+
+// scanUniqueKeys iterates over the whole keyspace with SCAN, and returns the
+// keys `keep` returns true for, listing each of them once.
+func (c *Client) scanUniqueKeys(ctx context.Context, opts scanOptions, keep func(key string) bool) ([]string, error) {
+	var (
+		mu   sync.Mutex
+		seen = make(map[string]struct{})
+		keys = make([]string, 0)
+	)
+
+	err := c.scanKeys(ctx, opts, func(key string) {
+		if !keep(key) {
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		if _, ok := seen[key]; ok {
+			return
+		}
+		seen[key] = struct{}{}
+		keys = append(keys, key)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// keyShard returns the shard `key` is assigned to, out of `shardCount`.
+func keyShard(key string, shardCount int64) int64 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+
+	return int64(h.Sum32() % uint32(shardCount))
+}
+
+// scanKeys iterates over the whole keyspace with SCAN, calling `fn` with
+// each key matching the provided options. Cluster clients scan all their
+// master nodes concurrently, hence `fn` must be safe for concurrent use.
+//
+// As SCAN does, scanKeys may call `fn` several times with the same key.
+func (c *Client) scanKeys(ctx context.Context, opts scanOptions, fn func(key string)) error {
+	return c.forEachScanClient(ctx, func(ctx context.Context, client redis.UniversalClient) error {
+		var cursor uint64
+		for {
+			keys, next, err := client.ScanType(ctx, cursor, opts.Match, opts.Count, opts.Type).Result()
+			if err != nil {
+				return err
+			}
+
+			for _, key := range keys {
+				fn(key)
+			}
+
+			if next == 0 {
+				return nil
+			}
+			cursor = next
+		}
+	})
+}
+
+// forEachScanClient calls `fn` with the clients to reach the whole keyspace
+// through, such as to scan it: each master node of cluster clients,
+// concurrently, or the client itself otherwise.
+func (c *Client) forEachScanClient(
+	ctx context.Context, fn func(ctx context.Context, client redis.UniversalClient) error,
+) error {
+	if cluster, ok := c.redisClient.(*redis.ClusterClient); ok {
+		return cluster.ForEachMaster(ctx, func(ctx context.Context, node *redis.Client) error {
+			return fn(ctx, node)
+		})
+	}
+
+	return fn(ctx, c.redisClient)
+}
+
+// defaultDeleteBatchSize is the default number of keys deleteByPattern
+// scans, and unlinks, per round-trip.
+const defaultDeleteBatchSize = 500
+
+// deleteByPatternOptions holds the options of the Client's deleteByPattern
+// method.
+type deleteByPatternOptions struct {
+	// BatchSize is the number of keys each SCAN call is hinted to return,
+	// and unlinked at once.
+	BatchSize int64 `json:"batchSize,omitempty"`
+
+	// Type is the type of value keys must hold to be deleted.
+	Type string `json:"type,omitempty"`
+}
+
+// DeleteByPattern deletes all the keys matching the glob-style `pattern`,
+// such as to clean up the keys created by a test in its teardown. The keys
+// are scanned, and unlinked, in batches, in Go, so that the whole keyspace
+// is covered, without transferring the keys to JS. Cluster clients delete
+// the keys of every master node.
+//
+// The promise resolves with the number of keys deleted.
+func (c *Client) DeleteByPattern(pattern string, options map[string]interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	if pattern == "" {
+		reject(errors.New("deleteByPattern requires a pattern; use \"*\" to delete all the keys"))
+		return promise
+	}
+
+	var opts deleteByPatternOptions
+	if err := decodeOptions(options, &opts); err != nil {
+		reject(fmt.Errorf("invalid deleteByPattern options; reason: %w", err))
+		return promise
+	}
+
+	if opts.BatchSize < 0 {
+		reject(fmt.Errorf("invalid deleteByPattern options; batchSize must be a positive number; got %d", opts.BatchSize))
+		return promise
+	}
+	if opts.BatchSize == 0 {
+		opts.BatchSize = defaultDeleteBatchSize
+	}
+
+	_, cluster := c.redisClient.(*redis.ClusterClient)
+
+	go func() {
+		var deleted atomic.Int64
+
+		err := c.forEachScanClient(c.context(), func(ctx context.Context, client redis.UniversalClient) error {
+			var cursor uint64
+			for {
+				keys, next, err := client.ScanType(ctx, cursor, pattern, opts.BatchSize, opts.Type).Result()
+				if err != nil {
+					return err
+				}
+
+				n, err := unlinkBatch(ctx, client, keys, cluster)
+				if err != nil {
+					return err
+				}
+				deleted.Add(n)
+
+				if next == 0 {
+					return nil
+				}
+				cursor = next
+			}
+		})
+		if err != nil {
+			reject(fmt.Errorf("deleteByPattern failed after deleting %d keys; reason: %w", deleted.Load(), err))
+			return
+		}
+
+		resolve(deleted.Load())
+	}()
+
+	return promise
+}
+
+// unlinkBatch unlinks the provided keys through `client`, and returns the
+// number of keys deleted. As the keys of a cluster node may belong to
+// different hash slots, they are unlinked one by one, in a pipeline, rather
+// than with a single command.
+func unlinkBatch(ctx context.Context, client redis.Cmdable, keys []string, cluster bool) (int64, error) {
+	if len(keys) == 0 {
+		return 0, nil
+	}
+
+	if !cluster {
+		return client.Unlink(ctx, keys...).Result()
+	}
+
+	pipe := client.Pipeline()
+	cmds := make([]*redis.IntCmd, len(keys))
+	for idx, key := range keys {
+		cmds[idx] = pipe.Unlink(ctx, key)
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+
+	var deleted int64
+	for _, cmd := range cmds {
+		deleted += cmd.Val()
+	}
+
+	return deleted, nil
+}
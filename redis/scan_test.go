@@ -0,0 +1,223 @@
+package redis
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientScanShard(t *testing.T) {
+	t.Parallel()
+
+	t.Run("shards cover all the keys without overlapping", func(t *testing.T) {
+		t.Parallel()
+
+		ts := newTestSetup(t)
+		rs := RunT(t)
+		rs.RegisterCommandHandler("SCAN", func(c *Connection, args []string) {
+			if args[0] == "0" {
+				c.WriteValue([]interface{}{"5", []string{"k1", "k2", "k3", "k4", "k5"}})
+				return
+			}
+
+			// SCAN may return the same key several times.
+			c.WriteValue([]interface{}{"0", []string{"k6", "k7", "k8", "k9", "k10", "k1"}})
+		})
+
+		gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+			_, err := ts.rt.RunString(fmt.Sprintf(`
+				const redis = new Client('redis://%s');
+
+				Promise.all([0, 1, 2].map(shard => redis.scanShard(shard, 3, { match: "k*", count: 100 })))
+					.then(shards => {
+						const all = shards.flat();
+						if (all.length !== 10 || new Set(all).size !== 10) {
+							throw 'unexpected scanShard results: ' + JSON.stringify(shards)
+						}
+					})
+			`, rs.Addr()))
+
+			return err
+		})
+
+		assert.NoError(t, gotScriptErr)
+		assert.Contains(t, rs.GotCommands(), []string{"SCAN", "0", "match", "k*", "count", "100"})
+	})
+
+	t.Run("invalid shards are rejected", func(t *testing.T) {
+		t.Parallel()
+
+		ts := newTestSetup(t)
+		rs := RunT(t)
+
+		gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+			_, err := ts.rt.RunString(fmt.Sprintf(`
+				const redis = new Client('redis://%s');
+
+				redis.scanShard(3, 3)
+					.then(
+						res => { throw 'expected scanShard to fail' },
+						err => { if (!err.error().startsWith('invalid shard index 3')) { throw 'unexpected error: ' + err } }
+					)
+					.then(() => redis.scanShard(0, 0))
+					.then(
+						res => { throw 'expected scanShard to fail' },
+						err => { if (!err.error().startsWith('invalid shard count 0')) { throw 'unexpected error: ' + err } }
+					)
+			`, rs.Addr()))
+
+			return err
+		})
+
+		assert.NoError(t, gotScriptErr)
+		assert.Equal(t, 0, rs.HandledCommandsCount())
+	})
+}
+
+func TestClientScan(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunT(t)
+	rs.RegisterCommandHandler("SCAN", func(c *Connection, args []string) {
+		if args[0] == "0" {
+			c.WriteValue([]interface{}{"7", []string{"k1", "k2"}})
+			return
+		}
+
+		c.WriteValue([]interface{}{"0", []string{"k3", "k1"}})
+	})
+	rs.RegisterCommandHandler("SSCAN", func(c *Connection, args []string) {
+		if args[1] == "0" {
+			c.WriteValue([]interface{}{"3", []string{"a", "b"}})
+			return
+		}
+
+		c.WriteValue([]interface{}{"0", []string{"b", "c"}})
+	})
+	rs.RegisterCommandHandler("ZSCAN", func(c *Connection, args []string) {
+		if args[1] == "0" {
+			c.WriteValue([]interface{}{"3", []string{"alice", "1"}})
+			return
+		}
+
+		c.WriteValue([]interface{}{"0", []string{"bob", "2.5", "alice", "1"}})
+	})
+	rs.RegisterCommandHandler("HSCAN", func(c *Connection, args []string) {
+		if args[1] == "0" {
+			c.WriteValue([]interface{}{"3", []string{"user", "alice"}})
+			return
+		}
+
+		c.WriteValue([]interface{}{"0", []string{"visits", "3"}})
+	})
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client('redis://%s');
+
+			redis.scan(0, { match: "k*", type: "string" })
+				.then(res => { if (res.cursor !== "7" || res.keys.join() !== "k1,k2") { throw 'unexpected value for scan result: ' + JSON.stringify(res) } })
+				.then(() => redis.scanAll({ count: 100 }))
+				.then(res => { if (res.join() !== "k1,k2,k3") { throw 'unexpected value for scanAll result: ' + JSON.stringify(res) } })
+				.then(() => redis.sscan("set", 3))
+				.then(res => { if (res.cursor !== "0" || res.members.join() !== "b,c") { throw 'unexpected value for sscan result: ' + JSON.stringify(res) } })
+				.then(() => redis.sscanAll("set"))
+				.then(res => { if (res.join() !== "a,b,c") { throw 'unexpected value for sscanAll result: ' + JSON.stringify(res) } })
+				.then(() => redis.zscan("zset", 0))
+				.then(res => {
+					if (res.cursor !== "3" || res.members.length !== 1 || res.members[0].member !== "alice" || res.members[0].score !== 1) {
+						throw 'unexpected value for zscan result: ' + JSON.stringify(res)
+					}
+				})
+				.then(() => redis.zscanAll("zset"))
+				.then(res => {
+					if (res.length !== 2 || res[1].member !== "bob" || res[1].score !== 2.5) {
+						throw 'unexpected value for zscanAll result: ' + JSON.stringify(res)
+					}
+				})
+				.then(() => redis.hscanAll("hash", { match: "*" }))
+				.then(res => { if (res.user !== "alice" || res.visits !== "3") { throw 'unexpected value for hscanAll result: ' + JSON.stringify(res) } })
+		`, rs.Addr()))
+
+		return err
+	})
+
+	assert.NoError(t, gotScriptErr)
+	assert.Equal(t, [][]string{
+		{"HELLO", "2"},
+		{"SCAN", "0", "match", "k*", "type", "string"},
+		{"SCAN", "0", "count", "100"},
+		{"SCAN", "7", "count", "100"},
+		{"SSCAN", "set", "3"},
+		{"SSCAN", "set", "0"},
+		{"SSCAN", "set", "3"},
+		{"ZSCAN", "zset", "0"},
+		{"ZSCAN", "zset", "0"},
+		{"ZSCAN", "zset", "3"},
+		{"HSCAN", "hash", "0", "match", "*"},
+		{"HSCAN", "hash", "3", "match", "*"},
+	}, rs.GotCommands())
+}
+
+func TestClientDeleteByPattern(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunT(t)
+	rs.RegisterCommandHandler("SCAN", func(c *Connection, args []string) {
+		if args[0] == "0" {
+			c.WriteValue([]interface{}{"7", []string{"loadtest:1", "loadtest:2"}})
+			return
+		}
+
+		c.WriteValue([]interface{}{"0", []string{"loadtest:3"}})
+	})
+	rs.RegisterCommandHandler("UNLINK", func(c *Connection, args []string) {
+		c.WriteInteger(len(args))
+	})
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client('redis://%s');
+
+			redis.deleteByPattern("loadtest:*", { batchSize: 2 })
+				.then(res => { if (res !== 3) { throw 'unexpected value for deleteByPattern result: ' + res } })
+				.then(() => redis.deleteByPattern(""))
+				.then(
+					res => { throw 'expected deleteByPattern to fail' },
+					err => { if (!err.error().startsWith('deleteByPattern requires a pattern')) { throw 'unexpected error: ' + err.error() } }
+				)
+		`, rs.Addr()))
+
+		return err
+	})
+
+	assert.NoError(t, gotScriptErr)
+	assert.Equal(t, [][]string{
+		{"HELLO", "2"},
+		{"SCAN", "0", "match", "loadtest:*", "count", "2"},
+		{"UNLINK", "loadtest:1", "loadtest:2"},
+		{"SCAN", "7", "match", "loadtest:*", "count", "2"},
+		{"UNLINK", "loadtest:3"},
+	}, rs.GotCommands())
+}
+
+func TestKeyShard(t *testing.T) {
+	t.Parallel()
+
+	counts := make([]int, 4)
+	for i := 0; i < 1000; i++ {
+		key := fmt.Sprintf("user:%d", i)
+
+		shard := keyShard(key, 4)
+		assert.Equal(t, shard, keyShard(key, 4), "key assignment should be deterministic")
+		counts[shard]++
+	}
+
+	// Keys should be reasonably well spread across the shards.
+	for _, count := range counts {
+		assert.Greater(t, count, 150)
+	}
+}
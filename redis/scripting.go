@@ -0,0 +1,178 @@
+package redis
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/grafana/sobek"
+	"github.com/redis/go-redis/v9"
+)
+
+// Eval evaluates the Lua `script` server-side, with the provided keys and
+// arguments, made available to the script as the KEYS and ARGV arrays.
+//
+// The promise resolves with the script's reply, null standing for a nil
+// reply.
+func (c *Client) Eval(script string, keys []string, args ...interface{}) *sobek.Promise {
+	return c.scriptCommand(2, args, func() *redis.Cmd {
+		return c.redisClient.Eval(c.context(), script, keys, args...)
+	})
+}
+
+// Evalsha evaluates the Lua script cached by the server under the `sha1`
+// digest, as returned by scriptLoad, with the provided keys and arguments.
+//
+// The promise is rejected with a NOSCRIPT error if the server doesn't know
+// the script.
+func (c *Client) Evalsha(sha1 string, keys []string, args ...interface{}) *sobek.Promise {
+	return c.scriptCommand(2, args, func() *redis.Cmd {
+		return c.redisClient.EvalSha(c.context(), sha1, keys, args...)
+	})
+}
+
+// ScriptLoad loads the Lua `script` in the server's scripts cache, without
+// executing it. The promise resolves with the script's SHA1 digest, to be
+// passed to evalsha.
+func (c *Client) ScriptLoad(script string) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		sha1, err := c.redisClient.ScriptLoad(c.context(), script).Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(sha1)
+	}()
+
+	return promise
+}
+
+// Script is a Lua script, executed with EVALSHA, so that its source is only
+// sent to the server when it isn't in the server's scripts cache yet.
+type Script struct {
+	client *Client
+	script *redis.Script
+}
+
+// Script returns a new Script of the provided Lua source.
+func (c *Client) Script(source string) *Script {
+	return &Script{client: c, script: redis.NewScript(source)}
+}
+
+// Hash returns the SHA1 digest of the script's source.
+func (s *Script) Hash() string {
+	return s.script.Hash()
+}
+
+// Run evaluates the script with the provided keys and arguments. It sends
+// EVALSHA, and falls back to EVAL, which caches the script, when the server
+// replies with a NOSCRIPT error.
+//
+// The promise resolves with the script's reply, null standing for a nil
+// reply.
+func (s *Script) Run(keys []string, args ...interface{}) *sobek.Promise {
+	c := s.client
+
+	return c.scriptCommand(1, args, func() *redis.Cmd {
+		return s.script.Run(c.context(), c.redisClient, keys, args...)
+	})
+}
+
+// functionLoadOptions holds the options of the Client's functionLoad method.
+type functionLoadOptions struct {
+	// Replace replaces the library if it already exists.
+	Replace bool `json:"replace,omitempty"`
+}
+
+// FunctionLoad loads the Redis 7 functions library whose source is `code`.
+//
+// The promise resolves with the name of the library. It is rejected if the
+// library already exists, unless the `replace` option is set.
+func (c *Client) FunctionLoad(code string, options map[string]interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	var opts functionLoadOptions
+	if err := decodeOptions(options, &opts); err != nil {
+		reject(fmt.Errorf("invalid functionLoad options; reason: %w", err))
+		return promise
+	}
+
+	go func() {
+		load := c.redisClient.FunctionLoad
+		if opts.Replace {
+			load = c.redisClient.FunctionLoadReplace
+		}
+
+		library, err := load(c.context(), code).Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(library)
+	}()
+
+	return promise
+}
+
+// Fcall calls the Redis 7 `function`, loaded with functionLoad, with the
+// provided keys and arguments.
+//
+// The promise resolves with the function's reply, null standing for a nil
+// reply.
+func (c *Client) Fcall(function string, keys []string, args ...interface{}) *sobek.Promise {
+	return c.scriptCommand(2, args, func() *redis.Cmd {
+		return c.redisClient.FCall(c.context(), function, keys, args...)
+	})
+}
+
+// FcallRo is like Fcall, for functions flagged as read-only, which can be
+// called on replicas.
+func (c *Client) FcallRo(function string, keys []string, args ...interface{}) *sobek.Promise {
+	return c.scriptCommand(2, args, func() *redis.Cmd {
+		return c.redisClient.FCallRo(c.context(), function, keys, args...)
+	})
+}
+
+// scriptCommand sends the script, or function, command built by `cmd`,
+// once its arguments, found at `offset` in the command, are validated.
+//
+// As scripts commonly return nothing, a nil reply resolves the promise
+// with null, instead of rejecting it.
+func (c *Client) scriptCommand(offset int, args []interface{}, cmd func() *redis.Cmd) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	if err := c.isSupportedType(offset, args...); err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		reply, err := cmd().Result()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			reject(err)
+			return
+		}
+
+		resolve(reply)
+	}()
+
+	return promise
+}
@@ -0,0 +1,150 @@
+package redis
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientEval(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunT(t)
+	rs.RegisterCommandHandler("EVAL", func(c *Connection, args []string) {
+		if args[0] == "return nil" {
+			c.WriteNull()
+			return
+		}
+
+		c.WriteValue([]interface{}{args[2], args[3]})
+	})
+	rs.RegisterCommandHandler("SCRIPT", func(c *Connection, _ []string) {
+		c.WriteBulkString("e0e1f9fabfc9d4800c877a703b823ac0578ff8db")
+	})
+	rs.RegisterCommandHandler("EVALSHA", func(c *Connection, _ []string) {
+		c.WriteInteger(1)
+	})
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client('redis://%s');
+
+			redis.eval("return {KEYS[1], ARGV[1]}", ["foo"], "bar")
+				.then(res => {
+					if (res.length !== 2 || res[0] !== "foo" || res[1] !== "bar") {
+						throw 'unexpected value for eval result: ' + JSON.stringify(res)
+					}
+				})
+				.then(() => redis.eval("return nil", []))
+				.then(res => { if (res !== null) { throw 'unexpected value for nil eval result: ' + res } })
+				.then(() => redis.scriptLoad("return 1"))
+				.then(sha1 => redis.evalsha(sha1, []))
+				.then(res => { if (res !== 1) { throw 'unexpected value for evalsha result: ' + res } })
+				.then(() => redis.eval("return 1", [], {}))
+				.then(
+					res => { throw 'expected eval to fail' },
+					err => { if (!err.error().includes('unsupported type')) { throw 'unexpected error: ' + err.error() } }
+				)
+		`, rs.Addr()))
+
+		return err
+	})
+
+	assert.NoError(t, gotScriptErr)
+	assert.Equal(t, [][]string{
+		{"HELLO", "2"},
+		{"EVAL", "return {KEYS[1], ARGV[1]}", "1", "foo", "bar"},
+		{"EVAL", "return nil", "0"},
+		{"SCRIPT", "load", "return 1"},
+		{"EVALSHA", "e0e1f9fabfc9d4800c877a703b823ac0578ff8db", "0"},
+	}, rs.GotCommands())
+}
+
+func TestClientScript(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunT(t)
+	rs.RegisterCommandHandler("EVALSHA", func(c *Connection, _ []string) {
+		c.WriteError(errors.New("NOSCRIPT No matching script. Please use EVAL."))
+	})
+	rs.RegisterCommandHandler("EVAL", func(c *Connection, _ []string) {
+		c.WriteInteger(42)
+	})
+
+	source := "return tonumber(ARGV[1]) * 2"
+	hash := redis.NewScript(source).Hash()
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client('redis://%s');
+
+			const script = redis.script(%q);
+			if (script.hash() !== %q) { throw 'unexpected script hash: ' + script.hash() }
+
+			script.run([], 21)
+				.then(res => { if (res !== 42) { throw 'unexpected value for script result: ' + res } })
+		`, rs.Addr(), source, hash))
+
+		return err
+	})
+
+	assert.NoError(t, gotScriptErr)
+	assert.Equal(t, [][]string{
+		{"HELLO", "2"},
+		{"EVALSHA", hash, "0", "21"},
+		{"EVAL", source, "0", "21"},
+	}, rs.GotCommands())
+}
+
+func TestClientFunctions(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunT(t)
+	rs.RegisterCommandHandler("FUNCTION", func(c *Connection, _ []string) {
+		c.WriteBulkString("mylib")
+	})
+	rs.RegisterCommandHandler("FCALL", func(c *Connection, args []string) {
+		c.WriteBulkString(args[2])
+	})
+	rs.RegisterCommandHandler("FCALL_RO", func(c *Connection, args []string) {
+		c.WriteBulkString(args[2])
+	})
+
+	code := "#!lua name=mylib\nredis.register_function('get', function(keys) return redis.call('GET', keys[1]) end)"
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client('redis://%s');
+
+			redis.functionLoad(%q)
+				.then(res => { if (res !== "mylib") { throw 'unexpected value for functionLoad result: ' + res } })
+				.then(() => redis.functionLoad(%q, { replace: true }))
+				.then(() => redis.fcall("get", ["foo"]))
+				.then(res => { if (res !== "foo") { throw 'unexpected value for fcall result: ' + res } })
+				.then(() => redis.fcallRo("get", ["bar"]))
+				.then(res => { if (res !== "bar") { throw 'unexpected value for fcallRo result: ' + res } })
+				.then(() => redis.functionLoad(%q, { unknown: true }))
+				.then(
+					res => { throw 'expected functionLoad to fail' },
+					err => { if (!err.error().startsWith('invalid functionLoad options')) { throw 'unexpected error: ' + err.error() } }
+				)
+		`, rs.Addr(), code, code, code))
+
+		return err
+	})
+
+	assert.NoError(t, gotScriptErr)
+	assert.Equal(t, [][]string{
+		{"HELLO", "2"},
+		{"FUNCTION", "load", code},
+		{"FUNCTION", "load", "replace", code},
+		{"FCALL", "get", "1", "foo"},
+		{"FCALL_RO", "get", "1", "bar"},
+	}, rs.GotCommands())
+}
@@ -0,0 +1,782 @@
+package redis
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/sobek"
+)
+
+// ftCreateOptions holds the options of the Client's ftCreate method.
+type ftCreateOptions struct {
+	// On is the type of the indexed keys: "hash", the default, or "json".
+	On string `json:"on,omitempty"`
+
+	// Prefix restricts the index to the keys starting with one of the
+	// prefixes.
+	Prefix []string `json:"prefix,omitempty"`
+}
+
+// ftSchemaField is a field of the schema of the Client's ftCreate method.
+type ftSchemaField struct {
+	// Name is the name of the hash field, or the JSON path, indexed.
+	Name string `json:"name"`
+
+	// As is the alias the field is referred to by in queries.
+	As string `json:"as,omitempty"`
+
+	// Type is the type of the field: "text", "tag", "numeric", "geo", or
+	// "vector".
+	Type string `json:"type"`
+
+	// Weight is the importance of text fields when ranking results.
+	Weight float64 `json:"weight,omitempty"`
+
+	// Separator is the character splitting the values of tag fields.
+	Separator string `json:"separator,omitempty"`
+
+	// Sortable makes the field usable to sort results by.
+	Sortable bool `json:"sortable,omitempty"`
+
+	// NoIndex leaves the field out of the index, for sortable fields only
+	// used to sort results by.
+	NoIndex bool `json:"noIndex,omitempty"`
+
+	// Algorithm is the indexing algorithm of vector fields: "flat", for
+	// exact searches, or "hnsw", for approximate ones.
+	Algorithm string `json:"algorithm,omitempty"`
+
+	// VectorType is the type of the elements of vector fields: "float32",
+	// the default, or "float64".
+	VectorType string `json:"vectorType,omitempty"`
+
+	// Dim is the number of elements of vector fields.
+	Dim int64 `json:"dim,omitempty"`
+
+	// DistanceMetric is the distance between vectors of vector fields:
+	// "l2", "ip", or "cosine".
+	DistanceMetric string `json:"distanceMetric,omitempty"`
+
+	// M and EfConstruction tune the graph of hnsw vector fields.
+	M              int64 `json:"m,omitempty"`
+	EfConstruction int64 `json:"efConstruction,omitempty"`
+}
+
+// args returns the arguments of the SCHEMA clause the field translates to.
+func (f ftSchemaField) args() ([]interface{}, error) {
+	if f.Name == "" {
+		return nil, errors.New("name must be set")
+	}
+
+	args := []interface{}{f.Name}
+	if f.As != "" {
+		args = append(args, "as", f.As)
+	}
+
+	fieldType := strings.ToLower(f.Type)
+	switch fieldType {
+	case "text", "tag", "numeric", "geo":
+		args = append(args, fieldType)
+	case "vector":
+		vectorArgs, err := f.vectorArgs()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, vectorArgs...)
+	default:
+		return nil, fmt.Errorf("invalid type: %q; expected one of %q, %q, %q, %q, or %q",
+			f.Type, "text", "tag", "numeric", "geo", "vector")
+	}
+
+	if fieldType != "vector" && (f.Algorithm != "" || f.VectorType != "" || f.Dim != 0 ||
+		f.DistanceMetric != "" || f.M != 0 || f.EfConstruction != 0) {
+		return nil, errors.New("algorithm, vectorType, dim, distanceMetric, m, and efConstruction only apply to vector fields")
+	}
+
+	if f.Weight != 0 {
+		if fieldType != "text" {
+			return nil, errors.New("weight only applies to text fields")
+		}
+		args = append(args, "weight", f.Weight)
+	}
+
+	if f.Separator != "" {
+		if fieldType != "tag" {
+			return nil, errors.New("separator only applies to tag fields")
+		}
+		args = append(args, "separator", f.Separator)
+	}
+
+	if f.Sortable {
+		args = append(args, "sortable")
+	}
+	if f.NoIndex {
+		args = append(args, "noindex")
+	}
+
+	return args, nil
+}
+
+// vectorArgs returns the arguments of the SCHEMA clause a vector field
+// translates to, from its type on: the algorithm, followed by the number
+// of its attributes, and the attributes.
+func (f ftSchemaField) vectorArgs() ([]interface{}, error) {
+	algorithm := strings.ToUpper(f.Algorithm)
+	if algorithm != "FLAT" && algorithm != "HNSW" {
+		return nil, fmt.Errorf("invalid algorithm: %q; expected %q or %q", f.Algorithm, "flat", "hnsw")
+	}
+
+	vectorType := strings.ToUpper(f.VectorType)
+	switch vectorType {
+	case "":
+		vectorType = "FLOAT32"
+	case "FLOAT32", "FLOAT64":
+	default:
+		return nil, fmt.Errorf("invalid vectorType: %q; expected %q or %q", f.VectorType, "float32", "float64")
+	}
+
+	if f.Dim <= 0 {
+		return nil, errors.New("dim must be positive")
+	}
+
+	metric := strings.ToUpper(f.DistanceMetric)
+	if metric != "L2" && metric != "IP" && metric != "COSINE" {
+		return nil, fmt.Errorf("invalid distanceMetric: %q; expected one of %q, %q, or %q",
+			f.DistanceMetric, "l2", "ip", "cosine")
+	}
+
+	attrs := []interface{}{"type", vectorType, "dim", f.Dim, "distance_metric", metric}
+	if f.M != 0 || f.EfConstruction != 0 {
+		if algorithm != "HNSW" {
+			return nil, errors.New("m and efConstruction only apply to hnsw vector fields")
+		}
+		if f.M != 0 {
+			attrs = append(attrs, "m", f.M)
+		}
+		if f.EfConstruction != 0 {
+			attrs = append(attrs, "ef_construction", f.EfConstruction)
+		}
+	}
+
+	return append([]interface{}{"vector", algorithm, len(attrs)}, attrs...), nil
+}
+
+// FtCreate creates the RediSearch `index`, indexing the fields described by
+// `schema`, as {name, type, as, weight, separator, sortable, noIndex}
+// objects, of the hashes, or JSON documents, whose key starts with one of
+// the `prefix` option's prefixes. Vector fields are described by their
+// algorithm, vectorType, dim, and distanceMetric instead.
+//
+// The promise resolves with "OK".
+func (c *Client) FtCreate(index string, schema []interface{}, options map[string]interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	var opts ftCreateOptions
+	if err := decodeOptions(options, &opts); err != nil {
+		reject(fmt.Errorf("invalid ftCreate options; reason: %w", err))
+		return promise
+	}
+
+	args := []interface{}{"ft.create", index}
+	switch on := strings.ToLower(opts.On); on {
+	case "":
+	case "hash", "json":
+		args = append(args, "on", on)
+	default:
+		reject(fmt.Errorf("invalid ftCreate options; invalid on: %q; expected %q or %q", opts.On, "hash", "json"))
+		return promise
+	}
+
+	if len(opts.Prefix) > 0 {
+		args = append(args, "prefix", len(opts.Prefix))
+		for _, prefix := range opts.Prefix {
+			args = append(args, prefix)
+		}
+	}
+
+	if len(schema) == 0 {
+		reject(errors.New("at least one schema field must be provided to ftCreate"))
+		return promise
+	}
+
+	args = append(args, "schema")
+	for idx, field := range schema {
+		obj, ok := field.(map[string]interface{})
+		if !ok {
+			reject(fmt.Errorf("invalid schema field at index %d; expected a {name, type} object", idx))
+			return promise
+		}
+
+		var f ftSchemaField
+		if err := decodeOptions(obj, &f); err != nil {
+			reject(fmt.Errorf("invalid schema field at index %d; reason: %w", idx, err))
+			return promise
+		}
+
+		fieldArgs, err := f.args()
+		if err != nil {
+			reject(fmt.Errorf("invalid schema field at index %d; %w", idx, err))
+			return promise
+		}
+
+		args = append(args, fieldArgs...)
+	}
+
+	go func() {
+		status, err := c.redisClient.Do(c.context(), args...).Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(status)
+	}()
+
+	return promise
+}
+
+// ftLimit is the range of results returned by the Client's ftSearch and
+// ftAggregate methods.
+type ftLimit struct {
+	// Offset is the number of results skipped.
+	Offset int64 `json:"offset,omitempty"`
+
+	// Num is the maximum number of results returned.
+	Num int64 `json:"num"`
+}
+
+// args returns the arguments of the LIMIT clause the range translates to.
+func (l *ftLimit) args() ([]interface{}, error) {
+	if l.Offset < 0 || l.Num < 0 {
+		return nil, errors.New("limit offset and num must be non-negative")
+	}
+
+	return []interface{}{"limit", l.Offset, l.Num}, nil
+}
+
+// ftSearchOptions holds the options of the Client's ftSearch method.
+type ftSearchOptions struct {
+	// NoContent only returns the ids of the matching documents.
+	NoContent bool `json:"noContent,omitempty"`
+
+	// Return restricts the fields returned for each document.
+	Return []string `json:"return,omitempty"`
+
+	// SortBy is the sortable field the results are sorted by, in the
+	// SortOrder order: "asc", the default, or "desc".
+	SortBy    string `json:"sortBy,omitempty"`
+	SortOrder string `json:"sortOrder,omitempty"`
+
+	// Limit is the range of results returned, the first 10 by default.
+	Limit *ftLimit `json:"limit,omitempty"`
+
+	// Params are the values of the parameters referenced, as $name, by the
+	// query. They are decoded by ftParams, rather than from JSON, as vectors
+	// are sent as binary values.
+	Params map[string]interface{} `json:"-"`
+
+	// Dialect is the version of the query syntax.
+	Dialect int64 `json:"dialect,omitempty"`
+
+	// ScoreField is the field holding the distance of the documents
+	// returned by KNN queries to the queried vector, as named by their AS
+	// clause, such as "__vec_score" by default for a "vec" vector field. It
+	// is parsed as a number and returned as the documents' score.
+	ScoreField string `json:"scoreField,omitempty"`
+}
+
+// args returns the arguments the search options translate to.
+func (o ftSearchOptions) args() ([]interface{}, error) {
+	var args []interface{}
+
+	if o.NoContent {
+		args = append(args, "nocontent")
+	}
+
+	if len(o.Return) > 0 {
+		args = append(args, "return", len(o.Return))
+		for _, field := range o.Return {
+			args = append(args, field)
+		}
+	}
+
+	if o.SortBy != "" {
+		args = append(args, "sortby", o.SortBy)
+	}
+	switch order := strings.ToLower(o.SortOrder); order {
+	case "":
+	case "asc", "desc":
+		if o.SortBy == "" {
+			return nil, errors.New("sortOrder requires sortBy")
+		}
+		args = append(args, order)
+	default:
+		return nil, fmt.Errorf("invalid sortOrder: %q; expected %q or %q", o.SortOrder, "asc", "desc")
+	}
+
+	if o.Limit != nil {
+		limitArgs, err := o.Limit.args()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, limitArgs...)
+	}
+
+	if len(o.Params) > 0 {
+		// Parameters are sorted by name, so that the command is the
+		// same across calls.
+		names := make([]string, 0, len(o.Params))
+		for name := range o.Params {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		args = append(args, "params", 2*len(names))
+		for _, name := range names {
+			args = append(args, name, o.Params[name])
+		}
+	}
+
+	if o.Dialect != 0 {
+		args = append(args, "dialect", o.Dialect)
+	}
+
+	return args, nil
+}
+
+// FtSearch runs the RediSearch `query` against `index`.
+//
+// The promise resolves with {total, documents}: the total number of
+// matching documents, and the documents returned, as {id, fields} objects.
+// With the noContent option set, the documents only hold their id.
+//
+// Vector similarity queries, such as "*=>[KNN 10 @vec $vector AS dist]",
+// are given their vector as a param, as a Float32Array, or Float64Array,
+// and, with the scoreField option set to the name of the distance field,
+// "dist", the documents also hold their distance, as a number, as score.
+func (c *Client) FtSearch(index string, query string, options map[string]interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	// Params are left out of the options decoded from JSON, which would
+	// mangle binary vectors.
+	rest := make(map[string]interface{}, len(options))
+	for name, value := range options {
+		if name != "params" {
+			rest[name] = value
+		}
+	}
+
+	var opts ftSearchOptions
+	if err := decodeOptions(rest, &opts); err != nil {
+		reject(fmt.Errorf("invalid ftSearch options; reason: %w", err))
+		return promise
+	}
+
+	params, err := ftParams(options["params"])
+	if err != nil {
+		reject(fmt.Errorf("invalid ftSearch options; %w", err))
+		return promise
+	}
+	opts.Params = params
+
+	if opts.ScoreField != "" && opts.NoContent {
+		reject(errors.New("invalid ftSearch options; scoreField requires the documents' fields, which noContent leaves out"))
+		return promise
+	}
+
+	optArgs, err := opts.args()
+	if err != nil {
+		reject(fmt.Errorf("invalid ftSearch options; %w", err))
+		return promise
+	}
+
+	go func() {
+		reply, err := c.redisClient.Do(c.context(), append([]interface{}{"ft.search", index, query}, optArgs...)...).Slice()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		result, err := ftSearchResult(reply, !opts.NoContent, opts.ScoreField)
+		if err != nil {
+			reject(fmt.Errorf("invalid ftSearch reply; %w", err))
+			return
+		}
+
+		resolve(result)
+	}()
+
+	return promise
+}
+
+// ftSearchResult converts an FT.SEARCH reply, the total number of matching
+// documents followed by the id, and the fields if `withFields` is set, of
+// each document returned, to a {total, documents} object. If `scoreField`
+// is set, the value of the field is parsed as the score of the documents.
+func ftSearchResult(reply []interface{}, withFields bool, scoreField string) (map[string]interface{}, error) {
+	if len(reply) == 0 {
+		return nil, errors.New("empty reply")
+	}
+
+	total, ok := reply[0].(int64)
+	if !ok {
+		return nil, fmt.Errorf("unexpected total of type %T", reply[0])
+	}
+
+	step := 1
+	if withFields {
+		step = 2
+	}
+
+	documents := make([]interface{}, 0, len(reply[1:])/step)
+	for idx := 1; idx+step-1 < len(reply); idx += step {
+		doc := map[string]interface{}{"id": reply[idx]}
+		if withFields {
+			fields, err := ftPairs(reply[idx+1])
+			if err != nil {
+				return nil, err
+			}
+			doc["fields"] = fields
+
+			if raw, ok := fields[scoreField]; ok && scoreField != "" {
+				score, err := strconv.ParseFloat(fmt.Sprint(raw), 64)
+				if err != nil {
+					return nil, fmt.Errorf("invalid score of document %v; %w", reply[idx], err)
+				}
+				doc["score"] = score
+			}
+		}
+
+		documents = append(documents, doc)
+	}
+
+	return map[string]interface{}{
+		"total":     total,
+		"documents": documents,
+	}, nil
+}
+
+// ftParams converts the params option of the Client's ftSearch method to
+// the values of the PARAMS clause. Vectors, provided as Float32Array, or
+// Float64Array, are encoded as the binary blobs RediSearch expects: their
+// elements, in little-endian order. ArrayBuffer and Uint8Array values are
+// sent verbatim, and other values as they are.
+func ftParams(raw interface{}) (map[string]interface{}, error) {
+	if raw == nil {
+		return nil, nil
+	}
+
+	obj, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid params of type %T; expected an object", raw)
+	}
+
+	params := make(map[string]interface{}, len(obj))
+	for name, value := range obj {
+		switch v := value.(type) {
+		case []float32:
+			blob := make([]byte, 4*len(v))
+			for idx, f := range v {
+				binary.LittleEndian.PutUint32(blob[4*idx:], math.Float32bits(f))
+			}
+			params[name] = string(blob)
+		case []float64:
+			blob := make([]byte, 8*len(v))
+			for idx, f := range v {
+				binary.LittleEndian.PutUint64(blob[8*idx:], math.Float64bits(f))
+			}
+			params[name] = string(blob)
+		case sobek.ArrayBuffer:
+			params[name] = string(v.Bytes())
+		case []byte:
+			params[name] = string(v)
+		case string, int64, float64, bool:
+			params[name] = v
+		default:
+			return nil, fmt.Errorf("invalid param %q of type %T; expected a string, number, boolean, "+
+				"Float32Array, Float64Array, ArrayBuffer, or Uint8Array", name, value)
+		}
+	}
+
+	return params, nil
+}
+
+// ftPairs converts a flat array of field names and values to an object.
+func ftPairs(reply interface{}) (map[string]interface{}, error) {
+	if reply == nil {
+		return map[string]interface{}{}, nil
+	}
+
+	values, ok := reply.([]interface{})
+	if !ok || len(values)%2 != 0 {
+		return nil, fmt.Errorf("unexpected fields of type %T; expected an array of field and value pairs", reply)
+	}
+
+	pairs := make(map[string]interface{}, len(values)/2)
+	for idx := 0; idx < len(values); idx += 2 {
+		pairs[fmt.Sprint(values[idx])] = values[idx+1]
+	}
+
+	return pairs, nil
+}
+
+// ftReducer is a reducer of a groupBy stage of the Client's ftAggregate
+// method.
+type ftReducer struct {
+	// Fn is the reduce function, such as "count", "sum", or "avg".
+	Fn string `json:"fn"`
+
+	// Args are the arguments of the reduce function.
+	Args []string `json:"args,omitempty"`
+
+	// As is the name of the reduced value in the results.
+	As string `json:"as,omitempty"`
+}
+
+// ftSortField is a field of a sortBy stage of the Client's ftAggregate
+// method.
+type ftSortField struct {
+	// Field is the property the rows are sorted by, such as "@price".
+	Field string `json:"field"`
+
+	// Order is the order of the rows: "asc", the default, or "desc".
+	Order string `json:"order,omitempty"`
+}
+
+// ftAggregateStage is a stage of the pipeline of the Client's ftAggregate
+// method. Exactly one of GroupBy, SortBy, Apply, Filter, and Limit must be
+// set.
+type ftAggregateStage struct {
+	// GroupBy groups the rows by the provided properties, and reduces each
+	// group using Reduce.
+	GroupBy []string    `json:"groupBy,omitempty"`
+	Reduce  []ftReducer `json:"reduce,omitempty"`
+
+	// SortBy sorts the rows, keeping the first Max ones if set.
+	SortBy []ftSortField `json:"sortBy,omitempty"`
+	Max    int64         `json:"max,omitempty"`
+
+	// Apply adds the result of the expression to the rows, as As.
+	Apply string `json:"apply,omitempty"`
+	As    string `json:"as,omitempty"`
+
+	// Filter removes the rows for which the expression is false.
+	Filter string `json:"filter,omitempty"`
+
+	// Limit restricts the rows to the range.
+	Limit *ftLimit `json:"limit,omitempty"`
+}
+
+// args returns the arguments of the FT.AGGREGATE clause the stage
+// translates to.
+func (s ftAggregateStage) args() ([]interface{}, error) {
+	var kinds []string
+	if s.GroupBy != nil {
+		kinds = append(kinds, "groupBy")
+	}
+	if s.SortBy != nil {
+		kinds = append(kinds, "sortBy")
+	}
+	if s.Apply != "" {
+		kinds = append(kinds, "apply")
+	}
+	if s.Filter != "" {
+		kinds = append(kinds, "filter")
+	}
+	if s.Limit != nil {
+		kinds = append(kinds, "limit")
+	}
+	if len(kinds) != 1 {
+		return nil, fmt.Errorf("expected exactly one of groupBy, sortBy, apply, filter, or limit; got %d", len(kinds))
+	}
+
+	if s.Reduce != nil && s.GroupBy == nil {
+		return nil, errors.New("reduce requires groupBy")
+	}
+	if s.Max != 0 && s.SortBy == nil {
+		return nil, errors.New("max requires sortBy")
+	}
+	if (s.As != "") != (s.Apply != "") {
+		return nil, errors.New("apply and as must be set together")
+	}
+
+	switch kinds[0] {
+	case "groupBy":
+		args := []interface{}{"groupby", len(s.GroupBy)}
+		for _, property := range s.GroupBy {
+			args = append(args, property)
+		}
+		for _, reducer := range s.Reduce {
+			if reducer.Fn == "" {
+				return nil, errors.New("reducer fn must be set")
+			}
+			args = append(args, "reduce", reducer.Fn, len(reducer.Args))
+			for _, arg := range reducer.Args {
+				args = append(args, arg)
+			}
+			if reducer.As != "" {
+				args = append(args, "as", reducer.As)
+			}
+		}
+
+		return args, nil
+	case "sortBy":
+		var sortArgs []interface{}
+		for _, field := range s.SortBy {
+			sortArgs = append(sortArgs, field.Field)
+			switch order := strings.ToLower(field.Order); order {
+			case "":
+			case "asc", "desc":
+				sortArgs = append(sortArgs, order)
+			default:
+				return nil, fmt.Errorf("invalid sortBy order: %q; expected %q or %q", field.Order, "asc", "desc")
+			}
+		}
+
+		args := append([]interface{}{"sortby", len(sortArgs)}, sortArgs...)
+		if s.Max != 0 {
+			args = append(args, "max", s.Max)
+		}
+
+		return args, nil
+	case "apply":
+		return []interface{}{"apply", s.Apply, "as", s.As}, nil
+	case "filter":
+		return []interface{}{"filter", s.Filter}, nil
+	default:
+		return s.Limit.args()
+	}
+}
+
+// FtAggregate runs the RediSearch `query` against `index`, and processes
+// the matching documents through the pipeline of `stages`, as {groupBy,
+// reduce}, {sortBy, max}, {apply, as}, {filter}, or {limit} objects.
+//
+// The promise resolves with {total, rows}: the number of rows reported by
+// RediSearch, and the resulting rows, as objects mapping each property to
+// its value.
+func (c *Client) FtAggregate(index string, query string, stages []interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	args := []interface{}{"ft.aggregate", index, query}
+	for idx, stage := range stages {
+		obj, ok := stage.(map[string]interface{})
+		if !ok {
+			reject(fmt.Errorf("invalid ftAggregate stage at index %d; expected an object", idx))
+			return promise
+		}
+
+		var s ftAggregateStage
+		if err := decodeOptions(obj, &s); err != nil {
+			reject(fmt.Errorf("invalid ftAggregate stage at index %d; reason: %w", idx, err))
+			return promise
+		}
+
+		stageArgs, err := s.args()
+		if err != nil {
+			reject(fmt.Errorf("invalid ftAggregate stage at index %d; %w", idx, err))
+			return promise
+		}
+
+		args = append(args, stageArgs...)
+	}
+
+	go func() {
+		reply, err := c.redisClient.Do(c.context(), args...).Slice()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		if len(reply) == 0 {
+			reject(errors.New("invalid ftAggregate reply; empty reply"))
+			return
+		}
+
+		total, ok := reply[0].(int64)
+		if !ok {
+			reject(fmt.Errorf("invalid ftAggregate reply; unexpected total of type %T", reply[0]))
+			return
+		}
+
+		rows := make([]interface{}, 0, len(reply)-1)
+		for _, row := range reply[1:] {
+			pairs, err := ftPairs(row)
+			if err != nil {
+				reject(fmt.Errorf("invalid ftAggregate reply; %w", err))
+				return
+			}
+
+			rows = append(rows, pairs)
+		}
+
+		resolve(map[string]interface{}{
+			"total": total,
+			"rows":  rows,
+		})
+	}()
+
+	return promise
+}
+
+// ftDropIndexOptions holds the options of the Client's ftDropIndex method.
+type ftDropIndexOptions struct {
+	// DeleteDocuments also deletes the indexed hashes, or JSON documents.
+	DeleteDocuments bool `json:"deleteDocuments,omitempty"`
+}
+
+// FtDropIndex drops the RediSearch `index`. The indexed documents are kept,
+// unless the deleteDocuments option is set.
+//
+// The promise resolves with "OK".
+func (c *Client) FtDropIndex(index string, options map[string]interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	var opts ftDropIndexOptions
+	if err := decodeOptions(options, &opts); err != nil {
+		reject(fmt.Errorf("invalid ftDropIndex options; reason: %w", err))
+		return promise
+	}
+
+	args := []interface{}{"ft.dropindex", index}
+	if opts.DeleteDocuments {
+		args = append(args, "dd")
+	}
+
+	go func() {
+		status, err := c.redisClient.Do(c.context(), args...).Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(status)
+	}()
+
+	return promise
+}
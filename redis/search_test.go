@@ -0,0 +1,180 @@
+package redis
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientSearch(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunT(t)
+	rs.RegisterCommandHandler("FT.CREATE", func(c *Connection, _ []string) {
+		c.WriteOK()
+	})
+	rs.RegisterCommandHandler("FT.SEARCH", func(c *Connection, args []string) {
+		if len(args) > 2 && args[2] == "nocontent" {
+			c.WriteValue([]interface{}{2, "product:1", "product:2"})
+			return
+		}
+
+		c.WriteValue([]interface{}{
+			2,
+			"product:1", []interface{}{"title", "shoes", "price", "80"},
+			"product:2", []interface{}{"title", "socks", "price", "5"},
+		})
+	})
+	rs.RegisterCommandHandler("FT.AGGREGATE", func(c *Connection, _ []string) {
+		c.WriteValue([]interface{}{
+			1,
+			[]interface{}{"category", "clothing", "count", "2"},
+		})
+	})
+	rs.RegisterCommandHandler("FT.DROPINDEX", func(c *Connection, _ []string) {
+		c.WriteOK()
+	})
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client('redis://%s');
+
+			redis.ftCreate("products", [
+				{ name: "title", type: "text", weight: 2 },
+				{ name: "price", type: "numeric", sortable: true },
+				{ name: "category", type: "tag", separator: ";" },
+			], { on: "hash", prefix: ["product:"] })
+				.then(res => { if (res !== "OK") { throw 'unexpected value for ftCreate result: ' + res } })
+				.then(() => redis.ftSearch("products", "@price:[0 $max]", {
+					sortBy: "price",
+					sortOrder: "desc",
+					limit: { offset: 0, num: 2 },
+					params: { max: 100 },
+					dialect: 2,
+				}))
+				.then(res => {
+					if (res.total !== 2 || res.documents.length !== 2 || res.documents[0].id !== "product:1" || res.documents[1].fields.title !== "socks") {
+						throw 'unexpected value for ftSearch result: ' + JSON.stringify(res)
+					}
+				})
+				.then(() => redis.ftSearch("products", "*", { noContent: true }))
+				.then(res => {
+					if (res.total !== 2 || res.documents[1].id !== "product:2" || "fields" in res.documents[1]) {
+						throw 'unexpected value for ftSearch result: ' + JSON.stringify(res)
+					}
+				})
+				.then(() => redis.ftAggregate("products", "*", [
+					{ groupBy: ["@category"], reduce: [{ fn: "count", as: "count" }] },
+					{ sortBy: [{ field: "@count", order: "desc" }], max: 10 },
+					{ apply: "@count * 2", as: "double" },
+					{ filter: "@count > 1" },
+					{ limit: { num: 5 } },
+				]))
+				.then(res => {
+					if (res.total !== 1 || res.rows.length !== 1 || res.rows[0].category !== "clothing" || res.rows[0].count !== "2") {
+						throw 'unexpected value for ftAggregate result: ' + JSON.stringify(res)
+					}
+				})
+				.then(() => redis.ftDropIndex("products", { deleteDocuments: true }))
+				.then(res => { if (res !== "OK") { throw 'unexpected value for ftDropIndex result: ' + res } })
+				.then(() => redis.ftCreate("products", [{ name: "price", type: "numeric", weight: 2 }]))
+				.then(
+					res => { throw 'expected ftCreate to fail' },
+					err => { if (!err.error().includes('weight only applies to text fields')) { throw 'unexpected error: ' + err.error() } }
+				)
+				.then(() => redis.ftAggregate("products", "*", [{ groupBy: [], filter: "@count > 1" }]))
+				.then(
+					res => { throw 'expected ftAggregate to fail' },
+					err => { if (!err.error().startsWith('invalid ftAggregate stage at index 0')) { throw 'unexpected error: ' + err.error() } }
+				)
+		`, rs.Addr()))
+
+		return err
+	})
+
+	assert.NoError(t, gotScriptErr)
+	assert.Equal(t, [][]string{
+		{"HELLO", "2"},
+		{
+			"FT.CREATE", "products", "on", "hash", "prefix", "1", "product:", "schema",
+			"title", "text", "weight", "2", "price", "numeric", "sortable", "category", "tag", "separator", ";",
+		},
+		{
+			"FT.SEARCH", "products", "@price:[0 $max]", "sortby", "price", "desc", "limit", "0", "2",
+			"params", "2", "max", "100", "dialect", "2",
+		},
+		{"FT.SEARCH", "products", "*", "nocontent"},
+		{
+			"FT.AGGREGATE", "products", "*", "groupby", "1", "@category", "reduce", "count", "0", "as", "count",
+			"sortby", "2", "@count", "desc", "max", "10", "apply", "@count * 2", "as", "double",
+			"filter", "@count > 1", "limit", "0", "5",
+		},
+		{"FT.DROPINDEX", "products", "dd"},
+	}, rs.GotCommands())
+}
+
+func TestClientSearchVector(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunT(t)
+	rs.RegisterCommandHandler("FT.CREATE", func(c *Connection, _ []string) {
+		c.WriteOK()
+	})
+	rs.RegisterCommandHandler("FT.SEARCH", func(c *Connection, _ []string) {
+		c.WriteValue([]interface{}{
+			2,
+			"doc:1", []interface{}{"dist", "0.025", "title", "shoes"},
+			"doc:2", []interface{}{"dist", "1.5", "title", "socks"},
+		})
+	})
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client('redis://%s');
+
+			redis.ftCreate("docs", [
+				{ name: "title", type: "text" },
+				{ name: "vec", type: "vector", algorithm: "hnsw", dim: 2, distanceMetric: "cosine", m: 16 },
+			])
+				.then(() => redis.ftSearch("docs", "*=>[KNN 2 @vec $vector AS dist]", {
+					params: { vector: new Float32Array([1, 0.5]) },
+					sortBy: "dist",
+					dialect: 2,
+					scoreField: "dist",
+				}))
+				.then(res => {
+					if (res.total !== 2 || res.documents[0].score !== 0.025 || res.documents[1].score !== 1.5 || res.documents[1].fields.title !== "socks") {
+						throw 'unexpected value for ftSearch result: ' + JSON.stringify(res)
+					}
+				})
+				.then(() => redis.ftCreate("docs", [{ name: "vec", type: "vector", algorithm: "flat", dim: 2 }]))
+				.then(
+					res => { throw 'expected ftCreate to fail' },
+					err => { if (!err.error().includes('invalid distanceMetric')) { throw 'unexpected error: ' + err.error() } }
+				)
+				.then(() => redis.ftSearch("docs", "*", { params: { vector: [1, 0.5] } }))
+				.then(
+					res => { throw 'expected ftSearch to fail' },
+					err => { if (!err.error().includes('invalid param "vector"')) { throw 'unexpected error: ' + err.error() } }
+				)
+		`, rs.Addr()))
+
+		return err
+	})
+
+	assert.NoError(t, gotScriptErr)
+	assert.Equal(t, [][]string{
+		{"HELLO", "2"},
+		{
+			"FT.CREATE", "docs", "schema", "title", "text",
+			"vec", "vector", "HNSW", "8", "type", "FLOAT32", "dim", "2", "distance_metric", "COSINE", "m", "16",
+		},
+		{
+			"FT.SEARCH", "docs", "*=>[KNN 2 @vec $vector AS dist]", "sortby", "dist",
+			"params", "2", "vector", "\x00\x00\x80\x3f\x00\x00\x00\x3f", "dialect", "2",
+		},
+	}, rs.GotCommands())
+}
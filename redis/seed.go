@@ -0,0 +1,190 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/sobek"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// seedIndexPlaceholder is replaced by the index of each key in the
+	// keyPattern option of seed.
+	seedIndexPlaceholder = "{i}"
+
+	defaultSeedKeyPattern   = "seed:" + seedIndexPlaceholder
+	defaultSeedValueSize    = 64
+	defaultSeedElements     = 10
+	defaultSeedPipelineSize = 1000
+)
+
+// seedOptions holds the options of the Client's seed method.
+type seedOptions struct {
+	// Count is the number of keys to create.
+	Count int64 `json:"count"`
+
+	// KeyPattern is the name of the keys, where {i} is replaced by the
+	// index of each key, from 0 to count - 1.
+	KeyPattern string `json:"keyPattern,omitempty"`
+
+	// ValueSize is the size, in bytes, of the generated values.
+	ValueSize *int64 `json:"valueSize,omitempty"`
+
+	// Type is the type of the keys: "string", "hash", or "zset".
+	Type string `json:"type,omitempty"`
+
+	// Elements is the number of fields of hashes, or members of sorted
+	// sets, created for each key.
+	Elements int64 `json:"elements,omitempty"`
+
+	// PipelineSize is the number of keys created per round-trip.
+	PipelineSize int64 `json:"pipelineSize,omitempty"`
+}
+
+// validate checks the options, and sets the defaults of the omitted ones.
+func (o *seedOptions) validate() error {
+	if o.Count <= 0 {
+		return fmt.Errorf("count must be a positive number; got %d", o.Count)
+	}
+
+	if o.KeyPattern == "" {
+		o.KeyPattern = defaultSeedKeyPattern
+	}
+	if !strings.Contains(o.KeyPattern, seedIndexPlaceholder) {
+		return fmt.Errorf("keyPattern %q must contain the %s placeholder", o.KeyPattern, seedIndexPlaceholder)
+	}
+
+	if o.ValueSize == nil {
+		size := int64(defaultSeedValueSize)
+		o.ValueSize = &size
+	}
+	if *o.ValueSize < 0 {
+		return fmt.Errorf("valueSize must not be negative; got %d", *o.ValueSize)
+	}
+
+	switch o.Type {
+	case "":
+		o.Type = "string"
+	case "string", "hash", "zset":
+	default:
+		return fmt.Errorf("unsupported type %q; expected one of string, hash, or zset", o.Type)
+	}
+
+	if o.Elements < 0 {
+		return fmt.Errorf("elements must be a positive number; got %d", o.Elements)
+	}
+	if o.Elements == 0 {
+		o.Elements = defaultSeedElements
+	}
+
+	if o.PipelineSize < 0 {
+		return fmt.Errorf("pipelineSize must be a positive number; got %d", o.PipelineSize)
+	}
+	if o.PipelineSize == 0 {
+		o.PipelineSize = defaultSeedPipelineSize
+	}
+
+	return nil
+}
+
+// key returns the name of the key at `index`.
+func (o seedOptions) key(index int64) string {
+	return strings.ReplaceAll(o.KeyPattern, seedIndexPlaceholder, strconv.FormatInt(index, 10))
+}
+
+// value returns the generated value of the element at `index`.
+func (o seedOptions) value(index int64) string {
+	return randomString(randomBytes(*o.ValueSize, index))
+}
+
+// queue queues the command creating the key at `index` in `pipe`.
+//
+// Values are generated from the index of the key, and of the element, so
+// that seeding the same options always produces the same dataset.
+func (o seedOptions) queue(ctx context.Context, pipe redis.Pipeliner, index int64) {
+	key := o.key(index)
+
+	switch o.Type {
+	case "hash":
+		fields := make([]interface{}, 0, 2*o.Elements)
+		for elem := int64(0); elem < o.Elements; elem++ {
+			fields = append(fields, "field:"+strconv.FormatInt(elem, 10), o.value(index*o.Elements+elem))
+		}
+		pipe.HSet(ctx, key, fields...)
+	case "zset":
+		members := make([]redis.Z, o.Elements)
+		for elem := int64(0); elem < o.Elements; elem++ {
+			members[elem] = redis.Z{Score: float64(elem), Member: o.value(index*o.Elements + elem)}
+		}
+		pipe.ZAdd(ctx, key, members...)
+	default:
+		pipe.Set(ctx, key, o.value(index), 0)
+	}
+}
+
+// Seed populates the database with generated keys, such as to prepare the
+// dataset of a test in its setup function. The keys are created in Go, in
+// pipelines of pipelineSize keys, which is much faster than looping over
+// commands in JS.
+//
+// The keys are named after the keyPattern option, where {i} is replaced
+// by the index of each key, and hold strings, hashes, or sorted sets, of
+// `elements` fields or members, depending on the type option. Values are
+// random strings of valueSize bytes, generated deterministically, as
+// randomValue does, so that seeding is reproducible. Existing keys are
+// overwritten.
+//
+// The promise resolves with the number of keys created, once all of them
+// were.
+func (c *Client) Seed(options map[string]interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	var opts seedOptions
+	if err := decodeOptions(options, &opts); err != nil {
+		reject(fmt.Errorf("invalid seed options; reason: %w", err))
+		return promise
+	}
+
+	if err := opts.validate(); err != nil {
+		reject(fmt.Errorf("invalid seed options; %w", err))
+		return promise
+	}
+
+	go func() {
+		ctx := c.context()
+
+		for start := int64(0); start < opts.Count; start += opts.PipelineSize {
+			if err := ctx.Err(); err != nil {
+				reject(fmt.Errorf("seeding was interrupted after %d keys; reason: %w", start, err))
+				return
+			}
+
+			end := start + opts.PipelineSize
+			if end > opts.Count {
+				end = opts.Count
+			}
+
+			pipe := c.redisClient.Pipeline()
+			for index := start; index < end; index++ {
+				opts.queue(ctx, pipe, index)
+			}
+
+			if _, err := pipe.Exec(ctx); err != nil {
+				reject(fmt.Errorf("seeding failed after %d keys; reason: %w", start, err))
+				return
+			}
+		}
+
+		resolve(opts.Count)
+	}()
+
+	return promise
+}
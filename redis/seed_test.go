@@ -0,0 +1,73 @@
+package redis
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientSeed(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunT(t)
+	rs.RegisterCommandHandler("SET", func(c *Connection, _ []string) {
+		c.WriteOK()
+	})
+	rs.RegisterCommandHandler("HSET", func(c *Connection, args []string) {
+		c.WriteInteger((len(args) - 1) / 2)
+	})
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client('redis://%s');
+
+			redis.seed({ count: 5, keyPattern: "user:{i}", valueSize: 8, pipelineSize: 2 })
+				.then(res => { if (res !== 5) { throw 'unexpected value for seed result: ' + res } })
+				.then(() => redis.seed({ count: 1, type: "hash", elements: 2, valueSize: 4 }))
+				.then(res => { if (res !== 1) { throw 'unexpected value for seed result: ' + res } })
+				.then(() => redis.seed({ count: 1, keyPattern: "user" }))
+				.then(
+					res => { throw 'expected seed to fail' },
+					err => { if (!err.error().includes('must contain the {i} placeholder')) { throw 'unexpected error: ' + err.error() } }
+				)
+				.then(() => redis.seed({ count: 1, type: "list" }))
+				.then(
+					res => { throw 'expected seed to fail' },
+					err => { if (!err.error().includes('unsupported type "list"')) { throw 'unexpected error: ' + err.error() } }
+				)
+		`, rs.Addr()))
+
+		return err
+	})
+
+	assert.NoError(t, gotScriptErr)
+
+	valueSize := int64(8)
+	opts := seedOptions{Count: 5, KeyPattern: "user:{i}", ValueSize: &valueSize}
+	require.NoError(t, opts.validate())
+
+	got := rs.GotCommands()
+	require.Len(t, got, 7)
+	assert.Equal(t, []string{"SET", "user:0", opts.value(0)}, got[1])
+	assert.Equal(t, "user:4", got[5][1])
+	assert.Equal(t, "HSET", got[6][0])
+	assert.Equal(t, []string{"seed:0", "field:0", "field:1"}, []string{got[6][1], got[6][2], got[6][4]})
+}
+
+func TestSeedOptionsValidate(t *testing.T) {
+	t.Parallel()
+
+	opts := seedOptions{Count: 10}
+	require.NoError(t, opts.validate())
+	assert.Equal(t, "seed:3", opts.key(3))
+	assert.Equal(t, "string", opts.Type)
+	assert.Equal(t, int64(defaultSeedPipelineSize), opts.PipelineSize)
+	assert.Len(t, opts.value(3), defaultSeedValueSize)
+	assert.Equal(t, opts.value(3), opts.value(3))
+
+	assert.Error(t, (&seedOptions{}).validate())
+	assert.Error(t, (&seedOptions{Count: 1, PipelineSize: -1}).validate())
+}
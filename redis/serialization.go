@@ -0,0 +1,79 @@
+package redis
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/grafana/sobek"
+)
+
+// SetJSON sets `key` to hold the JSON serialization of `value`, with a time
+// to live equal to `expiration` seconds, as set does.
+//
+// The promise resolves with "OK". If `value` cannot be serialized to JSON,
+// as is the case of functions, the promise is rejected with an error, and
+// nothing is sent to Redis.
+func (c *Client) SetJSON(key string, value interface{}, expiration int) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	if value == nil {
+		reject(errors.New("invalid value for setJSON; expected a value other than undefined or null"))
+		return promise
+	}
+
+	serialized, err := json.Marshal(value)
+	if err != nil {
+		reject(fmt.Errorf("unable to serialize value of key %q to JSON; reason: %w", key, err))
+		return promise
+	}
+
+	go func() {
+		result, err := c.redisClient.Set(c.context(), key, serialized, time.Duration(expiration)*time.Second).Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(result)
+	}()
+
+	return promise
+}
+
+// GetJSON returns the value of `key`, parsed as JSON.
+//
+// The promise resolves with the parsed value. If the key does not exist,
+// or its value is not valid JSON, the promise is rejected with an error.
+func (c *Client) GetJSON(key string) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		value, err := c.redisClient.Get(c.context(), key).Bytes()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		var parsed interface{}
+		if err := json.Unmarshal(value, &parsed); err != nil {
+			reject(fmt.Errorf("unable to parse value of key %q as JSON; reason: %w", key, err))
+			return
+		}
+
+		resolve(parsed)
+	}()
+
+	return promise
+}
@@ -0,0 +1,68 @@
+package redis
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientJSONValues(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunT(t)
+	rs.RegisterCommandHandler("SET", func(c *Connection, _ []string) {
+		c.WriteOK()
+	})
+	rs.RegisterCommandHandler("GET", func(c *Connection, args []string) {
+		switch args[0] {
+		case "session":
+			c.WriteBulkString(`{"user":"alice","roles":["admin"],"visits":3}`)
+		case "plain":
+			c.WriteBulkString("not json")
+		default:
+			c.WriteNull()
+		}
+	})
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client('redis://%s');
+
+			redis.setJSON("session", { user: "alice", roles: ["admin"] }, 60)
+				.then(res => { if (res !== "OK") { throw 'unexpected value for setJSON result: ' + res } })
+				.then(() => redis.getJSON("session"))
+				.then(res => {
+					if (res.user !== "alice" || res.roles.length !== 1 || res.roles[0] !== "admin" || res.visits !== 3) {
+						throw 'unexpected value for getJSON result: ' + JSON.stringify(res)
+					}
+				})
+				.then(() => redis.getJSON("plain"))
+				.then(
+					res => { throw 'expected getJSON to fail on invalid JSON' },
+					err => { if (!err.error().startsWith('unable to parse value of key "plain"')) { throw 'unexpected error: ' + err.error() } }
+				)
+				.then(() => redis.setJSON("session", () => {}, 0))
+				.then(
+					res => { throw 'expected setJSON to fail on a function' },
+					err => { if (!err.error().startsWith('unable to serialize value of key "session"')) { throw 'unexpected error: ' + err.error() } }
+				)
+				.then(() => redis.setJSON("session", undefined, 0))
+				.then(
+					res => { throw 'expected setJSON to fail on undefined' },
+					err => { if (!err.error().startsWith('invalid value for setJSON')) { throw 'unexpected error: ' + err.error() } }
+				)
+		`, rs.Addr()))
+
+		return err
+	})
+
+	assert.NoError(t, gotScriptErr)
+	assert.Equal(t, [][]string{
+		{"HELLO", "2"},
+		{"SET", "session", `{"roles":["admin"],"user":"alice"}`, "ex", "60"},
+		{"GET", "session"},
+		{"GET", "plain"},
+	}, rs.GotCommands())
+}
@@ -0,0 +1,551 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/grafana/sobek"
+	"github.com/redis/go-redis/v9"
+	"go.k6.io/k6/metrics"
+)
+
+// defaultServerStatsSampleInterval is the interval at which the server
+// statistics are sampled without the intervalMs option.
+const defaultServerStatsSampleInterval = 5 * time.Second
+
+// Info returns the server's information and statistics, as reported by
+// INFO for the provided sections, or for the default ones if none is
+// provided.
+//
+// The promise resolves with an object mapping each field to its value.
+// Numeric values are converted to numbers, and values made of
+// comma-separated key=value pairs, such as those of the keyspace section,
+// to objects.
+func (c *Client) Info(sections ...string) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		info, err := c.redisClient.Info(c.context(), sections...).Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(parseInfo(info))
+	}()
+
+	return promise
+}
+
+// ConfigGet returns the server's configuration parameters matching the
+// glob-style `parameter`.
+//
+// The promise resolves with an object mapping each parameter to its value.
+func (c *Client) ConfigGet(parameter string) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		config, err := c.redisClient.ConfigGet(c.context(), parameter).Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(config)
+	}()
+
+	return promise
+}
+
+// ConfigSet sets the server's configuration `parameter` to `value`.
+//
+// The promise resolves with "OK".
+func (c *Client) ConfigSet(parameter, value string) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		status, err := c.redisClient.ConfigSet(c.context(), parameter, value).Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(status)
+	}()
+
+	return promise
+}
+
+// ClientList returns the connections of the server's clients, as reported
+// by CLIENT LIST.
+//
+// The promise resolves with an array holding an object for each
+// connection, mapping its properties, such as `addr`, `name`, or `age`,
+// to their values. Numeric values are converted to numbers.
+func (c *Client) ClientList() *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		list, err := c.redisClient.ClientList(c.context()).Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(parseClientList(list))
+	}()
+
+	return promise
+}
+
+// clientKillOptions holds the filters of the Client's clientKill method.
+type clientKillOptions struct {
+	// ID is the ID of the connection to close, as listed by CLIENT LIST.
+	ID *int64 `json:"id,omitempty"`
+
+	// Addr is the address of the connections to close, as host:port.
+	Addr string `json:"addr,omitempty"`
+
+	// Laddr is the local address of the connections to close, that is the
+	// address of the server's socket they are connected to.
+	Laddr string `json:"laddr,omitempty"`
+
+	// Type is the type of the connections to close: normal, master,
+	// replica, or pubsub.
+	Type string `json:"type,omitempty"`
+
+	// User is the ACL user the connections to close are authenticated as.
+	User string `json:"user,omitempty"`
+
+	// SkipMe, when false, allows the connection sending CLIENT KILL to be
+	// closed too. It defaults to true.
+	SkipMe *bool `json:"skipMe,omitempty"`
+}
+
+// args returns the CLIENT KILL filters matching the options.
+func (o clientKillOptions) args() ([]string, error) {
+	var args []string
+	if o.ID != nil {
+		args = append(args, "id", strconv.FormatInt(*o.ID, 10))
+	}
+	if o.Addr != "" {
+		args = append(args, "addr", o.Addr)
+	}
+	if o.Laddr != "" {
+		args = append(args, "laddr", o.Laddr)
+	}
+	if o.Type != "" {
+		switch o.Type {
+		case "normal", "master", "replica", "pubsub":
+		default:
+			return nil, fmt.Errorf("invalid type: %q; expected normal, master, replica, or pubsub", o.Type)
+		}
+		args = append(args, "type", o.Type)
+	}
+	if o.User != "" {
+		args = append(args, "user", o.User)
+	}
+
+	if len(args) == 0 {
+		return nil, errors.New("at least one of the id, addr, laddr, type, or user filters is required")
+	}
+
+	if o.SkipMe != nil && !*o.SkipMe {
+		args = append(args, "skipme", "no")
+	}
+
+	return args, nil
+}
+
+// ClientKill closes the connections of the server's clients matching the
+// provided filters, with CLIENT KILL, such as to inject disruptions in the
+// system under test. Cluster clients close the matching connections of
+// every master node.
+//
+// The promise resolves with the number of connections closed.
+func (c *Client) ClientKill(filters map[string]interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	var opts clientKillOptions
+	if err := decodeOptions(filters, &opts); err != nil {
+		reject(fmt.Errorf("invalid clientKill filters; reason: %w", err))
+		return promise
+	}
+
+	args, err := opts.args()
+	if err != nil {
+		reject(fmt.Errorf("invalid clientKill filters; %w", err))
+		return promise
+	}
+
+	go func() {
+		var killed atomic.Int64
+
+		err := c.forEachScanClient(c.context(), func(ctx context.Context, client redis.UniversalClient) error {
+			n, err := client.ClientKillByFilter(ctx, args...).Result()
+			killed.Add(n)
+			return err
+		})
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(killed.Load())
+	}()
+
+	return promise
+}
+
+// ClientPause suspends the server's clients for `timeoutMs` milliseconds,
+// with CLIENT PAUSE, such as to simulate a stalled server. With the "write"
+// `mode`, only the commands writing data are suspended; the "all" mode, the
+// default, suspends all of them. Cluster clients pause every master node.
+//
+// The promise resolves with "OK".
+func (c *Client) ClientPause(timeoutMs int64, mode string) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	if timeoutMs < 0 {
+		reject(fmt.Errorf("invalid clientPause timeout: %d; expected a positive number", timeoutMs))
+		return promise
+	}
+
+	args := []interface{}{"client", "pause", timeoutMs}
+	switch strings.ToLower(mode) {
+	case "":
+	case "all", "write":
+		args = append(args, strings.ToUpper(mode))
+	default:
+		reject(fmt.Errorf("invalid clientPause mode: %q; expected %q or %q", mode, "all", "write"))
+		return promise
+	}
+
+	go func() {
+		err := c.forEachScanClient(c.context(), func(ctx context.Context, client redis.UniversalClient) error {
+			return client.Do(ctx, args...).Err()
+		})
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve("OK")
+	}()
+
+	return promise
+}
+
+// ClientUnpause resumes the server's clients suspended by clientPause,
+// with CLIENT UNPAUSE, before its timeout. Cluster clients unpause every
+// master node.
+//
+// The promise resolves with "OK".
+func (c *Client) ClientUnpause() *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		err := c.forEachScanClient(c.context(), func(ctx context.Context, client redis.UniversalClient) error {
+			return client.ClientUnpause(ctx).Err()
+		})
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve("OK")
+	}()
+
+	return promise
+}
+
+// ClientNoEvict excludes the connection it is sent on from the server's
+// client eviction, with CLIENT NO-EVICT, when `enabled`, or includes it
+// back otherwise. As the connections of the Client are pooled, it only
+// applies to one of them: to apply it to the commands it is meant for,
+// send it along with them in a pipeline, with sendCommand.
+//
+// The promise resolves with "OK".
+func (c *Client) ClientNoEvict(enabled bool) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	value := "off"
+	if enabled {
+		value = "on"
+	}
+
+	go func() {
+		status, err := c.redisClient.Do(c.context(), "client", "no-evict", value).Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(status)
+	}()
+
+	return promise
+}
+
+// sampleServerStatsOptions holds the options of SampleServerStats.
+type sampleServerStatsOptions struct {
+	// IntervalMs is the interval, in milliseconds, at which the statistics
+	// are sampled.
+	IntervalMs int64 `json:"intervalMs,omitempty"`
+}
+
+// SampleServerStats periodically samples the server's statistics, as
+// reported by INFO, and emits its used memory, number of connected
+// clients, and instantaneous operations per second, as the
+// redis_used_memory, redis_connected_clients, and
+// redis_instantaneous_ops_per_sec metrics, so that the server's state can
+// be correlated with the load. Cluster clients sample each master, and tag
+// the samples with its `address`.
+//
+// Sampling runs in the background, until the VU's context is done, at the
+// interval set by the `intervalMs` option, which defaults to five seconds.
+// Sampling errors following the first sample are ignored, as
+// SampleConsumerLag does.
+//
+// The promise resolves once the first sample is emitted. If the server's
+// statistics are already being sampled by the Client, or the first sample
+// fails, the promise is rejected with an error.
+func (c *Client) SampleServerStats(options map[string]interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	var opts sampleServerStatsOptions
+	if err := decodeOptions(options, &opts); err != nil {
+		reject(fmt.Errorf("invalid sampleServerStats options; reason: %w", err))
+		return promise
+	}
+
+	if opts.IntervalMs < 0 {
+		reject(fmt.Errorf("invalid intervalMs option: %d; expected a positive number", opts.IntervalMs))
+		return promise
+	}
+
+	interval := defaultServerStatsSampleInterval
+	if opts.IntervalMs > 0 {
+		interval = time.Duration(opts.IntervalMs) * time.Millisecond
+	}
+
+	c.statsSamplerMu.Lock()
+	defer c.statsSamplerMu.Unlock()
+
+	if c.statsSampling {
+		reject(errors.New("the server statistics are already being sampled"))
+		return promise
+	}
+	c.statsSampling = true
+
+	go func() {
+		ctx := c.context()
+
+		if err := c.sampleServerStats(ctx); err != nil {
+			c.statsSamplerMu.Lock()
+			c.statsSampling = false
+			c.statsSamplerMu.Unlock()
+
+			reject(err)
+			return
+		}
+
+		resolve(nil)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				_ = c.sampleServerStats(ctx)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return promise
+}
+
+// sampleServerStats emits the current statistics of the server, or of each
+// master of the cluster, as the server metrics.
+func (c *Client) sampleServerStats(ctx context.Context) error {
+	cluster, ok := c.redisClient.(*redis.ClusterClient)
+	if !ok {
+		return c.pushServerStats(ctx, c.redisClient, nil)
+	}
+
+	return cluster.ForEachMaster(ctx, func(ctx context.Context, master *redis.Client) error {
+		return c.pushServerStats(ctx, master, map[string]string{"address": master.Options().Addr})
+	})
+}
+
+// pushServerStats reads the statistics of the server `client` is connected
+// to, and emits them with the provided tags.
+func (c *Client) pushServerStats(ctx context.Context, client redis.Cmdable, tags map[string]string) error {
+	info, err := client.Info(ctx).Result()
+	if err != nil {
+		return err
+	}
+
+	stats := parseInfo(info)
+	for _, stat := range []struct {
+		metric *metrics.Metric
+		field  string
+	}{
+		{c.metrics.UsedMemory, "used_memory"},
+		{c.metrics.ConnectedClients, "connected_clients"},
+		{c.metrics.InstantaneousOpsPerSec, "instantaneous_ops_per_sec"},
+	} {
+		if value, ok := stats[stat.field].(int64); ok {
+			c.pushTaggedMetric(stat.metric, float64(value), tags)
+		}
+	}
+
+	return nil
+}
+
+// parseInfo parses the reply of INFO into an object mapping each field to
+// its value. Section headers, and empty lines, are skipped.
+func parseInfo(info string) map[string]interface{} {
+	fields := make(map[string]interface{})
+
+	for _, line := range strings.Split(info, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		field, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+
+		fields[field] = parseInfoValue(value)
+	}
+
+	return fields
+}
+
+// infoField returns the raw value of `field` in the reply of INFO, or an
+// empty string if it isn't there. Unlike parseInfo, it leaves values, such
+// as versions, unparsed.
+func infoField(info, field string) string {
+	for _, line := range strings.Split(info, "\n") {
+		if value, ok := strings.CutPrefix(strings.TrimSpace(line), field+":"); ok {
+			return value
+		}
+	}
+
+	return ""
+}
+
+// parseInfoValue parses an INFO value: an object if it is made of
+// comma-separated key=value pairs, such as "keys=1,expires=0", and a
+// scalar otherwise.
+func parseInfoValue(value string) interface{} {
+	pairs := strings.Split(value, ",")
+
+	object := make(map[string]interface{}, len(pairs))
+	for _, pair := range pairs {
+		key, v, ok := strings.Cut(pair, "=")
+		if !ok {
+			return parseScalar(value)
+		}
+
+		object[key] = parseScalar(v)
+	}
+
+	return object
+}
+
+// parseClientList parses the reply of CLIENT LIST, a line of
+// space-separated property=value pairs for each connection.
+func parseClientList(list string) []map[string]interface{} {
+	clients := []map[string]interface{}{}
+
+	for _, line := range strings.Split(list, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		client := make(map[string]interface{})
+		for _, pair := range strings.Fields(line) {
+			key, value, _ := strings.Cut(pair, "=")
+			client[key] = parseScalar(value)
+		}
+
+		clients = append(clients, client)
+	}
+
+	return clients
+}
+
+// parseScalar converts `value` to an integer, or a float, if it is one, and
+// returns it as is otherwise.
+func parseScalar(value string) interface{} {
+	if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return i
+	}
+
+	// ParseFloat also accepts names, such as "inf", which are left as is.
+	if value != "" && strings.ContainsRune("-0123456789", rune(value[0])) {
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return f
+		}
+	}
+
+	return value
+}
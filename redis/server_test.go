@@ -0,0 +1,199 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testInfo is an excerpt of the reply of INFO.
+const testInfo = "# Clients\r\nconnected_clients:12\r\n\r\n# Memory\r\nused_memory:1048576\r\n" +
+	"used_memory_human:1.00M\r\nmem_fragmentation_ratio:1.25\r\n\r\n# Stats\r\ninstantaneous_ops_per_sec:250\r\n" +
+	"\r\n# Keyspace\r\ndb0:keys=3,expires=1,avg_ttl=0\r\n"
+
+func TestClientServerCommands(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunT(t)
+	rs.RegisterCommandHandler("INFO", func(c *Connection, _ []string) {
+		c.WriteBulkString(testInfo)
+	})
+	rs.RegisterCommandHandler("CONFIG", func(c *Connection, args []string) {
+		if args[0] == "get" {
+			c.WriteArray("maxmemory-policy", "allkeys-lru")
+			return
+		}
+		c.WriteOK()
+	})
+	rs.RegisterCommandHandler("CLIENT", func(c *Connection, _ []string) {
+		c.WriteBulkString("id=3 addr=127.0.0.1:52555 name=worker age=12 cmd=client|list\n" +
+			"id=4 addr=127.0.0.1:52556 name= age=3 cmd=get\n")
+	})
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client('redis://%s');
+
+			redis.info("memory")
+				.then(info => {
+					if (info.used_memory !== 1048576 || info.used_memory_human !== "1.00M" || info.mem_fragmentation_ratio !== 1.25) {
+						throw 'unexpected value for info result: ' + JSON.stringify(info)
+					}
+					if (info.db0.keys !== 3 || info.db0.expires !== 1) {
+						throw 'unexpected keyspace info: ' + JSON.stringify(info.db0)
+					}
+				})
+				.then(() => redis.configGet("maxmemory-*"))
+				.then(res => {
+					if (res["maxmemory-policy"] !== "allkeys-lru") { throw 'unexpected value for configGet result: ' + JSON.stringify(res) }
+				})
+				.then(() => redis.configSet("maxmemory-policy", "allkeys-lfu"))
+				.then(res => { if (res !== "OK") { throw 'unexpected value for configSet result: ' + res } })
+				.then(() => redis.clientList())
+				.then(clients => {
+					if (clients.length !== 2 || clients[0].name !== "worker" || clients[0].age !== 12 || clients[1].name !== "") {
+						throw 'unexpected value for clientList result: ' + JSON.stringify(clients)
+					}
+				})
+		`, rs.Addr()))
+
+		return err
+	})
+
+	assert.NoError(t, gotScriptErr)
+	assert.Equal(t, [][]string{
+		{"HELLO", "2"},
+		{"INFO", "memory"},
+		{"CONFIG", "get", "maxmemory-*"},
+		{"CONFIG", "set", "maxmemory-policy", "allkeys-lfu"},
+		{"CLIENT", "list"},
+	}, rs.GotCommands())
+}
+
+func TestClientClientControl(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunT(t)
+	rs.RegisterCommandHandler("CLIENT", func(c *Connection, args []string) {
+		if args[0] == "kill" {
+			c.WriteInteger(2)
+			return
+		}
+		c.WriteOK()
+	})
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client('redis://%s');
+
+			redis.clientKill({ type: "normal", laddr: "127.0.0.1:6379", skipMe: false })
+				.then(res => { if (res !== 2) { throw 'unexpected value for clientKill result: ' + res } })
+				.then(() => redis.clientKill({ id: 12 }))
+				.then(() => redis.clientKill({}))
+				.then(
+					res => { throw 'expected clientKill to fail' },
+					err => { if (!err.error().includes('at least one of the id, addr, laddr, type, or user filters')) { throw 'unexpected error: ' + err.error() } }
+				)
+				.then(() => redis.clientKill({ type: "sentinel" }))
+				.then(
+					res => { throw 'expected clientKill to fail' },
+					err => { if (!err.error().includes('invalid type: "sentinel"')) { throw 'unexpected error: ' + err.error() } }
+				)
+				.then(() => redis.clientPause(500))
+				.then(res => { if (res !== "OK") { throw 'unexpected value for clientPause result: ' + res } })
+				.then(() => redis.clientPause(500, "write"))
+				.then(() => redis.clientPause(500, "reads"))
+				.then(
+					res => { throw 'expected clientPause to fail' },
+					err => { if (!err.error().startsWith('invalid clientPause mode')) { throw 'unexpected error: ' + err.error() } }
+				)
+				.then(() => redis.clientUnpause())
+				.then(res => { if (res !== "OK") { throw 'unexpected value for clientUnpause result: ' + res } })
+				.then(() => redis.clientNoEvict(true))
+				.then(res => { if (res !== "OK") { throw 'unexpected value for clientNoEvict result: ' + res } })
+		`, rs.Addr()))
+
+		return err
+	})
+
+	assert.NoError(t, gotScriptErr)
+	assert.Equal(t, [][]string{
+		{"HELLO", "2"},
+		{"CLIENT", "kill", "laddr", "127.0.0.1:6379", "type", "normal", "skipme", "no"},
+		{"CLIENT", "kill", "id", "12"},
+		{"CLIENT", "pause", "500"},
+		{"CLIENT", "pause", "500", "WRITE"},
+		{"CLIENT", "unpause"},
+		{"CLIENT", "no-evict", "on"},
+	}, rs.GotCommands())
+}
+
+func TestClientSampleServerStats(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunT(t)
+	rs.RegisterCommandHandler("INFO", func(c *Connection, _ []string) {
+		c.WriteBulkString(testInfo)
+	})
+
+	ctx, cancel := context.WithCancel(ts.runtime.VU.CtxField)
+	defer cancel()
+	ts.runtime.VU.CtxField = ctx
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client('redis://%s');
+
+			redis.sampleServerStats({ intervalMs: 20 })
+				.then(() => redis.sampleServerStats())
+				.then(
+					res => { throw 'expected sampleServerStats to fail' },
+					err => { if (!err.error().includes('already being sampled')) { throw 'unexpected error: ' + err.error() } }
+				)
+		`, rs.Addr()))
+
+		return err
+	})
+	assert.NoError(t, gotScriptErr)
+
+	// Let the sampler run for a few intervals.
+	time.Sleep(100 * time.Millisecond)
+
+	values := make(map[string][]float64)
+	for _, sample := range drainSamples(ts.samples) {
+		values[sample.Metric.Name] = append(values[sample.Metric.Name], sample.Value)
+	}
+	assert.GreaterOrEqual(t, len(values["redis_used_memory"]), 3)
+	assert.Equal(t, 1048576.0, values["redis_used_memory"][0])
+	assert.Equal(t, 12.0, values["redis_connected_clients"][0])
+	assert.Equal(t, 250.0, values["redis_instantaneous_ops_per_sec"][0])
+
+	// Sampling stops once the VU's context is done.
+	cancel()
+	time.Sleep(50 * time.Millisecond)
+	handled := rs.HandledCommandsCount()
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, handled, rs.HandledCommandsCount())
+}
+
+func TestParseInfo(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, map[string]interface{}{
+		"connected_clients":         int64(12),
+		"used_memory":               int64(1048576),
+		"used_memory_human":         "1.00M",
+		"mem_fragmentation_ratio":   1.25,
+		"instantaneous_ops_per_sec": int64(250),
+		"db0":                       map[string]interface{}{"keys": int64(3), "expires": int64(1), "avg_ttl": int64(0)},
+	}, parseInfo(testInfo))
+
+	assert.Equal(t, "inf", parseScalar("inf"))
+	assert.Equal(t, "1.2.3", parseInfoValue("1.2.3"))
+}
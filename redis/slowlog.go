@@ -0,0 +1,198 @@
+package redis
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/grafana/sobek"
+	"github.com/redis/go-redis/v9"
+)
+
+// SlowlogGet returns the `count` most recent entries of the server's slow
+// log, or all of them if `count` is negative. Without `count`, the server
+// returns 10 entries.
+//
+// The promise resolves with an array holding an object for each entry,
+// from the most recent to the oldest: its `id`, the Unix `timestamp`, in
+// milliseconds, it was logged at, its `duration`, in milliseconds, the
+// `args` of the command, and the `clientAddr` and `clientName` of the
+// client which sent it.
+func (c *Client) SlowlogGet(count ...int64) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	if len(count) > 1 {
+		reject(fmt.Errorf("slowlogGet accepts at most one argument; got %d", len(count)))
+		return promise
+	}
+
+	args := []interface{}{"slowlog", "get"}
+	if len(count) == 1 {
+		args = append(args, count[0])
+	}
+
+	go func() {
+		ctx := c.context()
+		cmd := redis.NewSlowLogCmd(ctx, args...)
+		_ = c.redisClient.Process(ctx, cmd)
+
+		logs, err := cmd.Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		entries := make([]map[string]interface{}, len(logs))
+		for idx, log := range logs {
+			entries[idx] = map[string]interface{}{
+				"id":         log.ID,
+				"timestamp":  log.Time.UnixMilli(),
+				"duration":   float64(log.Duration) / float64(time.Millisecond),
+				"args":       log.Args,
+				"clientAddr": log.ClientAddr,
+				"clientName": log.ClientName,
+			}
+		}
+
+		resolve(entries)
+	}()
+
+	return promise
+}
+
+// SlowlogLen returns the number of entries of the server's slow log.
+func (c *Client) SlowlogLen() *sobek.Promise {
+	return c.serverCommand("slowlog", "len")
+}
+
+// SlowlogReset empties the server's slow log, so that only the entries
+// logged during the test are reported afterwards.
+//
+// The promise resolves with "OK".
+func (c *Client) SlowlogReset() *sobek.Promise {
+	return c.serverCommand("slowlog", "reset")
+}
+
+// LatencyLatest returns the latest latency spikes of each event monitored
+// by the server's latency monitor, which its latency-monitor-threshold
+// configuration parameter enables.
+//
+// The promise resolves with an array holding an object for each event:
+// its name, `event`, the Unix `timestamp`, in milliseconds, of its latest
+// spike, and the `latest` and `max` spikes' latencies, in milliseconds.
+func (c *Client) LatencyLatest() *sobek.Promise {
+	return c.latencyCommand(func(reply []interface{}) (interface{}, error) {
+		events := make([]map[string]interface{}, len(reply))
+		for idx, item := range reply {
+			values, ok := item.([]interface{})
+			if !ok || len(values) < 4 {
+				return nil, fmt.Errorf("unexpected latency latest entry: %v", item)
+			}
+
+			event, _ := values[0].(string)
+			timestamp, _ := values[1].(int64)
+			latest, _ := values[2].(int64)
+			maxLatency, _ := values[3].(int64)
+
+			events[idx] = map[string]interface{}{
+				"event":     event,
+				"timestamp": timestamp * 1000,
+				"latest":    latest,
+				"max":       maxLatency,
+			}
+		}
+
+		return events, nil
+	}, "latency", "latest")
+}
+
+// LatencyHistory returns the latency spikes of `event`, as recorded by the
+// server's latency monitor.
+//
+// The promise resolves with an array holding an object for each spike,
+// from the oldest to the most recent: the Unix `timestamp`, in
+// milliseconds, it occurred at, and its `latency`, in milliseconds.
+func (c *Client) LatencyHistory(event string) *sobek.Promise {
+	return c.latencyCommand(func(reply []interface{}) (interface{}, error) {
+		spikes := make([]map[string]interface{}, len(reply))
+		for idx, item := range reply {
+			values, ok := item.([]interface{})
+			if !ok || len(values) < 2 {
+				return nil, fmt.Errorf("unexpected latency history entry: %v", item)
+			}
+
+			timestamp, _ := values[0].(int64)
+			latency, _ := values[1].(int64)
+
+			spikes[idx] = map[string]interface{}{
+				"timestamp": timestamp * 1000,
+				"latency":   latency,
+			}
+		}
+
+		return spikes, nil
+	}, "latency", "history", event)
+}
+
+// LatencyReset resets the latency spikes recorded for `events`, or for all
+// the events if none is provided.
+//
+// The promise resolves with the number of events reset.
+func (c *Client) LatencyReset(events ...string) *sobek.Promise {
+	return c.serverCommand(append([]interface{}{"latency", "reset"}, stringsToArgs(events)...)...)
+}
+
+// serverCommand sends a command, and resolves with its reply as is.
+func (c *Client) serverCommand(args ...interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		reply, err := c.redisClient.Do(c.context(), args...).Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(reply)
+	}()
+
+	return promise
+}
+
+// latencyCommand sends a LATENCY command, whose reply is an array, and
+// resolves with the result of `parse` applied to it.
+func (c *Client) latencyCommand(parse func(reply []interface{}) (interface{}, error), args ...interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		reply, err := c.redisClient.Do(c.context(), args...).Slice()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		result, err := parse(reply)
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(result)
+	}()
+
+	return promise
+}
@@ -0,0 +1,90 @@
+package redis
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientSlowlogAndLatency(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunT(t)
+	rs.RegisterCommandHandler("SLOWLOG", func(c *Connection, args []string) {
+		switch args[0] {
+		case "get":
+			c.WriteValue([]interface{}{
+				[]interface{}{int64(7), int64(1700000000), int64(12500), []interface{}{"keys", "*"}, "127.0.0.1:52555", "worker"},
+			})
+		case "len":
+			c.WriteInteger(1)
+		default:
+			c.WriteOK()
+		}
+	})
+	rs.RegisterCommandHandler("LATENCY", func(c *Connection, args []string) {
+		switch args[0] {
+		case "latest":
+			c.WriteValue([]interface{}{
+				[]interface{}{"command", int64(1700000000), int64(15), int64(40)},
+			})
+		case "history":
+			c.WriteValue([]interface{}{
+				[]interface{}{int64(1700000000), int64(40)},
+				[]interface{}{int64(1700000010), int64(15)},
+			})
+		default:
+			c.WriteInteger(len(args) - 1)
+		}
+	})
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client('redis://%s');
+
+			redis.slowlogGet(5)
+				.then(entries => {
+					const entry = entries[0];
+					if (entries.length !== 1 || entry.id !== 7 || entry.timestamp !== 1700000000000 || entry.duration !== 12.5) {
+						throw 'unexpected value for slowlogGet result: ' + JSON.stringify(entries)
+					}
+					if (entry.args.join(" ") !== "keys *" || entry.clientAddr !== "127.0.0.1:52555" || entry.clientName !== "worker") {
+						throw 'unexpected slowlog entry: ' + JSON.stringify(entry)
+					}
+				})
+				.then(() => redis.slowlogLen())
+				.then(res => { if (res !== 1) { throw 'unexpected value for slowlogLen result: ' + res } })
+				.then(() => redis.slowlogReset())
+				.then(res => { if (res !== "OK") { throw 'unexpected value for slowlogReset result: ' + res } })
+				.then(() => redis.latencyLatest())
+				.then(events => {
+					if (events.length !== 1 || events[0].event !== "command" || events[0].latest !== 15 || events[0].max !== 40) {
+						throw 'unexpected value for latencyLatest result: ' + JSON.stringify(events)
+					}
+				})
+				.then(() => redis.latencyHistory("command"))
+				.then(spikes => {
+					if (spikes.length !== 2 || spikes[1].timestamp !== 1700000010000 || spikes[1].latency !== 15) {
+						throw 'unexpected value for latencyHistory result: ' + JSON.stringify(spikes)
+					}
+				})
+				.then(() => redis.latencyReset("command", "fork"))
+				.then(res => { if (res !== 2) { throw 'unexpected value for latencyReset result: ' + res } })
+		`, rs.Addr()))
+
+		return err
+	})
+
+	assert.NoError(t, gotScriptErr)
+	assert.Equal(t, [][]string{
+		{"HELLO", "2"},
+		{"SLOWLOG", "get", "5"},
+		{"SLOWLOG", "len"},
+		{"SLOWLOG", "reset"},
+		{"LATENCY", "latest"},
+		{"LATENCY", "history", "command"},
+		{"LATENCY", "reset", "command", "fork"},
+	}, rs.GotCommands())
+}
@@ -0,0 +1,554 @@
+package redis
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/sobek"
+	"github.com/redis/go-redis/v9"
+)
+
+// zaddOptions holds the options of the Client's zadd method.
+type zaddOptions struct {
+	// NX only adds new members, and never updates existing ones.
+	NX bool `json:"nx,omitempty"`
+
+	// XX only updates existing members, and never adds new ones.
+	XX bool `json:"xx,omitempty"`
+
+	// GT only updates existing members whose new score is greater.
+	GT bool `json:"gt,omitempty"`
+
+	// LT only updates existing members whose new score is lower.
+	LT bool `json:"lt,omitempty"`
+
+	// Ch counts the updated members, on top of the added ones.
+	Ch bool `json:"ch,omitempty"`
+
+	// Incr increments the score of a single member, as ZINCRBY does, and
+	// returns its new score.
+	Incr bool `json:"incr,omitempty"`
+}
+
+// args returns the flags of the ZADD command the options translate to.
+func (o zaddOptions) args() ([]interface{}, error) {
+	switch {
+	case o.NX && o.XX:
+		return nil, errors.New("nx and xx are mutually exclusive")
+	case o.GT && o.LT:
+		return nil, errors.New("gt and lt are mutually exclusive")
+	case o.NX && (o.GT || o.LT):
+		return nil, errors.New("nx can't be combined with gt, or lt")
+	}
+
+	var args []interface{}
+	for _, flag := range []struct {
+		name string
+		set  bool
+	}{{"nx", o.NX}, {"xx", o.XX}, {"gt", o.GT}, {"lt", o.LT}, {"ch", o.Ch}, {"incr", o.Incr}} {
+		if flag.set {
+			args = append(args, flag.name)
+		}
+	}
+
+	return args, nil
+}
+
+// zaddArgs returns the arguments of the ZADD command adding `members` to
+// the sorted set stored at `key`, with the `flags` of zaddOptions.
+func zaddArgs(key string, flags []interface{}, members []redis.Z) []interface{} {
+	args := append([]interface{}{"zadd", key}, flags...)
+	for _, m := range members {
+		args = append(args, m.Score, m.Member)
+	}
+
+	return args
+}
+
+// zaddScore returns the score a ZADD command with the incr option replied
+// with, as a number: RESP2 replies it as a string.
+func zaddScore(reply interface{}) (interface{}, error) {
+	switch v := reply.(type) {
+	case string:
+		return strconv.ParseFloat(v, 64)
+	case int64:
+		return float64(v), nil
+	default:
+		return v, nil
+	}
+}
+
+// Zadd adds the provided members, as {score, member} objects, to the
+// sorted set stored at `key`, or updates the score of those already in it.
+//
+// The promise resolves with the number of members added, or changed when
+// the `ch` option is set. Calls exceeding the commandChunkSize option are
+// split in pipelined chunks. With the `incr` option set, the score of the
+// single member is incremented instead, and the promise resolves with its
+// new score, or null if the other options prevented the update.
+func (c *Client) Zadd(key string, members []interface{}, options map[string]interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	scored, err := c.scoredMembers(members)
+	if err != nil {
+		reject(err)
+		return promise
+	}
+
+	var opts zaddOptions
+	if err := decodeOptions(options, &opts); err != nil {
+		reject(fmt.Errorf("invalid zadd options; reason: %w", err))
+		return promise
+	}
+
+	flags, err := opts.args()
+	if err != nil {
+		reject(fmt.Errorf("invalid zadd options; %w", err))
+		return promise
+	}
+
+	if opts.Incr {
+		if len(scored) != 1 {
+			reject(fmt.Errorf("invalid zadd options; incr requires a single member, got %d", len(scored)))
+			return promise
+		}
+
+		go func() {
+			reply, err := c.redisClient.Do(c.context(), zaddArgs(key, flags, scored)...).Result()
+			if errors.Is(err, redis.Nil) {
+				resolve(nil)
+				return
+			}
+			if err != nil {
+				reject(err)
+				return
+			}
+
+			score, err := zaddScore(reply)
+			if err != nil {
+				reject(err)
+				return
+			}
+
+			resolve(score)
+		}()
+
+		return promise
+	}
+
+	go func() {
+		ctx := c.context()
+		n, err := sumChunks(ctx, c, scored, func(cmd redis.Cmdable, chunk []redis.Z) *redis.IntCmd {
+			return cmd.ZAddArgs(ctx, key, redis.ZAddArgs{
+				NX: opts.NX, XX: opts.XX, GT: opts.GT, LT: opts.LT, Ch: opts.Ch,
+				Members: chunk,
+			})
+		})
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(n)
+	}()
+
+	return promise
+}
+
+// zrangeLimit holds the limit option of the Client's sorted set range
+// methods.
+type zrangeLimit struct {
+	Offset int64 `json:"offset"`
+	Count  int64 `json:"count"`
+}
+
+// zrangeOptions holds the options of the Client's zrange method.
+type zrangeOptions struct {
+	// ByScore interprets `start` and `stop` as scores, rather than ranks.
+	ByScore bool `json:"byScore,omitempty"`
+
+	// ByLex interprets `start` and `stop` as lexicographical ranges.
+	ByLex bool `json:"byLex,omitempty"`
+
+	// Rev reverses the ordering, from the highest to the lowest score.
+	Rev bool `json:"rev,omitempty"`
+
+	// Limit restricts the range to `count` members, after skipping `offset`
+	// of them. It requires byScore, or byLex.
+	Limit *zrangeLimit `json:"limit,omitempty"`
+
+	// WithScores returns the members along with their score.
+	WithScores bool `json:"withScores,omitempty"`
+}
+
+// Zrange returns the members of the sorted set stored at `key` in the
+// range from `start` to `stop`.
+//
+// By default, the range is of ranks, from the lowest score to the highest.
+// The `byScore`, and `byLex`, options make it a range of scores, and a
+// lexicographical range, respectively, using the Redis syntax for exclusive
+// and infinite bounds, such as "(1" or "-inf". The `rev` option reverses
+// the ordering, and the `limit` option paginates score and lexicographical
+// ranges.
+//
+// The promise resolves with an array of members, or, with the `withScores`
+// option set, of {member, score} objects.
+func (c *Client) Zrange(key string, start, stop interface{}, options map[string]interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	if err := c.isSupportedType(1, start, stop); err != nil {
+		reject(err)
+		return promise
+	}
+
+	var opts zrangeOptions
+	if err := decodeOptions(options, &opts); err != nil {
+		reject(fmt.Errorf("invalid zrange options; reason: %w", err))
+		return promise
+	}
+
+	if opts.ByScore && opts.ByLex {
+		reject(errors.New("invalid zrange options; byScore and byLex are mutually exclusive"))
+		return promise
+	}
+
+	if opts.Limit != nil && !opts.ByScore && !opts.ByLex {
+		reject(errors.New("invalid zrange options; limit requires byScore or byLex"))
+		return promise
+	}
+
+	args := redis.ZRangeArgs{
+		Key:     key,
+		Start:   start,
+		Stop:    stop,
+		ByScore: opts.ByScore,
+		ByLex:   opts.ByLex,
+		Rev:     opts.Rev,
+	}
+	if opts.Limit != nil {
+		args.Offset, args.Count = opts.Limit.Offset, opts.Limit.Count
+	}
+
+	// go-redis expects reversed score and lexicographical ranges from their
+	// lowest bound to their highest, and swaps them, while Redis expects
+	// them from `start` to `stop`, as rank ranges.
+	if opts.Rev && (opts.ByScore || opts.ByLex) {
+		args.Start, args.Stop = args.Stop, args.Start
+	}
+
+	go func() {
+		if opts.WithScores {
+			members, err := c.redisClient.ZRangeArgsWithScores(c.context(), args).Result()
+			if err != nil {
+				reject(err)
+				return
+			}
+
+			resolve(scoredMembersToJS(members))
+			return
+		}
+
+		members, err := c.redisClient.ZRangeArgs(c.context(), args).Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(members)
+	}()
+
+	return promise
+}
+
+// zrangeByScoreOptions holds the options of the Client's zrangebyscore
+// method.
+type zrangeByScoreOptions struct {
+	// Limit restricts the range to `count` members, after skipping `offset`
+	// of them.
+	Limit *zrangeLimit `json:"limit,omitempty"`
+
+	// WithScores returns the members along with their score.
+	WithScores bool `json:"withScores,omitempty"`
+}
+
+// Zrangebyscore returns the members of the sorted set stored at `key` whose
+// score is between `min` and `max`, from the lowest score to the highest.
+// The bounds use the Redis syntax for exclusive and infinite bounds, such
+// as "(1" or "+inf".
+//
+// The promise resolves with an array of members, or, with the `withScores`
+// option set, of {member, score} objects.
+func (c *Client) Zrangebyscore(key string, min, max interface{}, options map[string]interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	if err := c.isSupportedType(1, min, max); err != nil {
+		reject(err)
+		return promise
+	}
+
+	var opts zrangeByScoreOptions
+	if err := decodeOptions(options, &opts); err != nil {
+		reject(fmt.Errorf("invalid zrangebyscore options; reason: %w", err))
+		return promise
+	}
+
+	by := &redis.ZRangeBy{Min: fmt.Sprint(min), Max: fmt.Sprint(max)}
+	if opts.Limit != nil {
+		by.Offset, by.Count = opts.Limit.Offset, opts.Limit.Count
+	}
+
+	go func() {
+		if opts.WithScores {
+			members, err := c.redisClient.ZRangeByScoreWithScores(c.context(), key, by).Result()
+			if err != nil {
+				reject(err)
+				return
+			}
+
+			resolve(scoredMembersToJS(members))
+			return
+		}
+
+		members, err := c.redisClient.ZRangeByScore(c.context(), key, by).Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(members)
+	}()
+
+	return promise
+}
+
+// Zincrby increments the score of `member` in the sorted set stored at
+// `key` by `increment`. If `member` doesn't exist, it is added with
+// `increment` as its score.
+//
+// The promise resolves with the new score of `member`.
+func (c *Client) Zincrby(key string, increment float64, member interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	members, err := c.binaryFields(2, member)
+	if err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		score, err := c.redisClient.ZIncrBy(c.context(), key, increment, members[0]).Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(score)
+	}()
+
+	return promise
+}
+
+// Zrem removes the specified members from the sorted set stored at `key`.
+// Members that are not in the sorted set are ignored.
+//
+// The promise resolves with the number of members removed. Calls exceeding
+// the commandChunkSize option are split in pipelined chunks.
+func (c *Client) Zrem(key string, members ...interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	memberArgs, err := c.binaryArgs(1, members...)
+	if err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		ctx := c.context()
+		n, err := sumChunks(ctx, c, memberArgs, func(cmd redis.Cmdable, chunk []interface{}) *redis.IntCmd {
+			return cmd.ZRem(ctx, key, chunk...)
+		})
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(n)
+	}()
+
+	return promise
+}
+
+// Zscore returns the score of `member` in the sorted set stored at `key`.
+//
+// If the sorted set, or the member, does not exist, the promise is rejected
+// with an error.
+func (c *Client) Zscore(key string, member interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	members, err := c.binaryFields(1, member)
+	if err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		score, err := c.redisClient.ZScore(c.context(), key, members[0]).Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(score)
+	}()
+
+	return promise
+}
+
+// Zcard returns the number of members of the sorted set stored at `key`,
+// or 0 if it does not exist.
+func (c *Client) Zcard(key string) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		count, err := c.redisClient.ZCard(c.context(), key).Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(count)
+	}()
+
+	return promise
+}
+
+// Zmpop pops up to `count` members, 1 by default, with the lowest, or
+// highest, scores, depending on `order`, "min" or "max", from the first
+// non-empty sorted set among `keys`. It requires Redis 7.
+//
+// The promise resolves with an object holding the `key` the members were
+// popped from, and the popped `members`, as {member, score} objects, or
+// null if all the sorted sets are empty.
+func (c *Client) Zmpop(keys []string, order string, count ...int64) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	if len(keys) == 0 {
+		reject(errors.New("at least one key must be provided to zmpop"))
+		return promise
+	}
+
+	if !strings.EqualFold(order, "min") && !strings.EqualFold(order, "max") {
+		reject(fmt.Errorf("invalid zmpop order %q; expected %q or %q", order, "min", "max"))
+		return promise
+	}
+
+	n, err := popCount("zmpop", count)
+	if err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		key, members, err := c.redisClient.ZMPop(c.context(), order, n, keys...).Result()
+		if errors.Is(err, redis.Nil) {
+			resolve(nil)
+			return
+		}
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(map[string]interface{}{"key": key, "members": scoredMembersToJS(members)})
+	}()
+
+	return promise
+}
+
+// Zintercard is like Sintercard, for the intersection of the sorted sets
+// stored at `keys`. It requires Redis 7.
+func (c *Client) Zintercard(keys []string, limit ...int64) *sobek.Promise {
+	return c.intercard("zintercard", keys, limit, redis.UniversalClient.ZInterCard)
+}
+
+// scoredMembers converts the provided {score, member} objects to the
+// members of a ZADD command.
+func (c *Client) scoredMembers(members []interface{}) ([]redis.Z, error) {
+	scored := make([]redis.Z, len(members))
+	for idx, m := range members {
+		obj, ok := m.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid member at index %d; expected a {score, member} object", idx)
+		}
+
+		score, ok := toFloat(obj["score"])
+		if !ok {
+			return nil, fmt.Errorf("invalid score for member at index %d; expected a number", idx)
+		}
+
+		member, err := c.binaryArgs(0, obj["member"])
+		if err != nil {
+			return nil, fmt.Errorf("invalid member at index %d; reason: %w", idx, err)
+		}
+
+		scored[idx] = redis.Z{Score: score, Member: member[0]}
+	}
+
+	return scored, nil
+}
+
+// scoredMembersToJS converts the provided sorted set members to an array of
+// {member, score} objects.
+func scoredMembersToJS(members []redis.Z) []map[string]interface{} {
+	converted := make([]map[string]interface{}, len(members))
+	for idx, m := range members {
+		converted[idx] = map[string]interface{}{
+			"member": m.Member,
+			"score":  m.Score,
+		}
+	}
+
+	return converted
+}
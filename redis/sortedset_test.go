@@ -0,0 +1,122 @@
+package redis
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientSortedSets(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunT(t)
+	rs.RegisterCommandHandler("ZADD", func(c *Connection, args []string) {
+		switch {
+		case args[1] == "incr":
+			c.WriteBulkString("3.5")
+		case args[1] == "nx" && args[2] == "incr":
+			c.WriteNull()
+		default:
+			c.WriteInteger(len(args[3:]) / 2)
+		}
+	})
+	rs.RegisterCommandHandler("ZRANGE", func(c *Connection, args []string) {
+		if args[len(args)-1] == "withscores" {
+			c.WriteArray("alice", "1", "bob", "2.5")
+			return
+		}
+
+		c.WriteArray("alice", "bob")
+	})
+	rs.RegisterCommandHandler("ZRANGEBYSCORE", func(c *Connection, _ []string) {
+		c.WriteArray("bob", "2.5")
+	})
+	rs.RegisterCommandHandler("ZINCRBY", func(c *Connection, _ []string) {
+		c.WriteBulkString("3.5")
+	})
+	rs.RegisterCommandHandler("ZSCORE", func(c *Connection, args []string) {
+		if args[1] != "alice" {
+			c.WriteNull()
+			return
+		}
+
+		c.WriteBulkString("1")
+	})
+	rs.RegisterCommandHandler("ZREM", func(c *Connection, args []string) {
+		c.WriteInteger(len(args[1:]))
+	})
+	rs.RegisterCommandHandler("ZCARD", func(c *Connection, _ []string) {
+		c.WriteInteger(1)
+	})
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client('redis://%s');
+
+			redis.zadd("scores", [{ score: 1, member: "alice" }, { score: 2.5, member: "bob" }], { gt: true, ch: true })
+				.then(res => { if (res !== 2) { throw 'unexpected value for zadd result: ' + res } })
+				.then(() => redis.zadd("scores", [{ score: 1, member: "bob" }], { incr: true }))
+				.then(res => { if (res !== 3.5) { throw 'unexpected value for zadd incr result: ' + res } })
+				.then(() => redis.zadd("scores", [{ score: 1, member: "bob" }], { nx: true, incr: true }))
+				.then(res => { if (res !== null) { throw 'unexpected value for zadd nx incr result: ' + res } })
+				.then(() => redis.zadd("scores", [{ score: 1, member: "alice" }, { score: 1, member: "bob" }], { incr: true }))
+				.then(
+					res => { throw 'expected zadd incr to fail with several members' },
+					err => { if (!err.error().includes('incr requires a single member')) { throw 'unexpected error: ' + err.error() } }
+				)
+				.then(() => redis.zrange("scores", 0, -1))
+				.then(res => { if (JSON.stringify(res) !== '["alice","bob"]') { throw 'unexpected value for zrange result: ' + JSON.stringify(res) } })
+				.then(() => redis.zrange("scores", "+inf", "(1", { byScore: true, rev: true, limit: { offset: 0, count: 10 }, withScores: true }))
+				.then(res => {
+					if (res.length !== 2 || res[0].member !== "alice" || res[0].score !== 1 || res[1].member !== "bob" || res[1].score !== 2.5) {
+						throw 'unexpected value for zrange withScores result: ' + JSON.stringify(res)
+					}
+				})
+				.then(() => redis.zrangebyscore("scores", 2, "+inf", { withScores: true }))
+				.then(res => { if (res.length !== 1 || res[0].member !== "bob" || res[0].score !== 2.5) { throw 'unexpected value for zrangebyscore result: ' + JSON.stringify(res) } })
+				.then(() => redis.zincrby("scores", 1, "bob"))
+				.then(res => { if (res !== 3.5) { throw 'unexpected value for zincrby result: ' + res } })
+				.then(() => redis.zscore("scores", "alice"))
+				.then(res => { if (res !== 1) { throw 'unexpected value for zscore result: ' + res } })
+				.then(() => redis.zrem("scores", "alice", "carol"))
+				.then(res => { if (res !== 2) { throw 'unexpected value for zrem result: ' + res } })
+				.then(() => redis.zcard("scores"))
+				.then(res => { if (res !== 1) { throw 'unexpected value for zcard result: ' + res } })
+				.then(() => redis.zscore("scores", "carol"))
+				.then(
+					res => { throw 'expected zscore to fail on a missing member' },
+					err => { if (!err.error().includes('nil')) { throw 'unexpected error: ' + err.error() } }
+				)
+				.then(() => redis.zadd("scores", [{ member: "alice" }]))
+				.then(
+					res => { throw 'expected zadd to fail' },
+					err => { if (!err.error().startsWith('invalid score for member at index 0')) { throw 'unexpected error: ' + err.error() } }
+				)
+				.then(() => redis.zrange("scores", 0, -1, { limit: { offset: 0, count: 1 } }))
+				.then(
+					res => { throw 'expected zrange to fail' },
+					err => { if (!err.error().includes('limit requires byScore or byLex')) { throw 'unexpected error: ' + err.error() } }
+				)
+		`, rs.Addr()))
+
+		return err
+	})
+
+	assert.NoError(t, gotScriptErr)
+	assert.Equal(t, [][]string{
+		{"HELLO", "2"},
+		{"ZADD", "scores", "gt", "ch", "1", "alice", "2.5", "bob"},
+		{"ZADD", "scores", "incr", "1", "bob"},
+		{"ZADD", "scores", "nx", "incr", "1", "bob"},
+		{"ZRANGE", "scores", "0", "-1"},
+		{"ZRANGE", "scores", "+inf", "(1", "byscore", "rev", "limit", "0", "10", "withscores"},
+		{"ZRANGEBYSCORE", "scores", "2", "+inf", "withscores"},
+		{"ZINCRBY", "scores", "1", "bob"},
+		{"ZSCORE", "scores", "alice"},
+		{"ZREM", "scores", "alice", "carol"},
+		{"ZCARD", "scores"},
+		{"ZSCORE", "scores", "carol"},
+	}, rs.GotCommands())
+}
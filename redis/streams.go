@@ -0,0 +1,485 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/sobek"
+	"github.com/redis/go-redis/v9"
+)
+
+// Stream commands are exposed as xadd, xread, etc. As k6 strips the leading X
+// of exported method names when exposing them to JS, the methods implementing
+// them carry an additional X prefix.
+
+// Xxadd appends a new entry, made of the provided fields, to the stream
+// stored at `key`. The entry is created with the provided `id`, or with an
+// auto-generated one when `id` is "*".
+//
+// Each successfully added entry is counted by the redis_stream_entries_added
+// metric.
+//
+// The promise resolves with the ID of the added entry.
+func (c *Client) Xxadd(key, id string, fields map[string]interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	if len(fields) == 0 {
+		reject(errors.New("at least one field must be provided to xadd"))
+		return promise
+	}
+
+	for _, value := range fields {
+		if err := c.isSupportedType(2, value); err != nil {
+			reject(err)
+			return promise
+		}
+	}
+
+	go func() {
+		addedID, err := c.redisClient.XAdd(c.context(), &redis.XAddArgs{
+			Stream: key,
+			ID:     id,
+			Values: fields,
+		}).Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		c.pushMetric(c.metrics.StreamEntriesAdded, 1)
+
+		resolve(addedID)
+	}()
+
+	return promise
+}
+
+// Xxread reads the entries of one or more streams, starting after the
+// provided IDs. The `streams` object maps each stream's key to the ID
+// after which its entries should be read. When `count` is positive, at most
+// `count` entries are returned per stream.
+//
+// Each read entry is counted by the redis_stream_entries_read metric, and
+// its end-to-end latency, computed from the millisecond timestamp encoded
+// in its ID, is emitted as the redis_stream_entry_latency metric.
+//
+// The promise resolves with an array of objects holding the `stream` key,
+// and its `entries`, each with an `id` and `fields` property. If no entries
+// are available, the promise resolves with an empty array.
+func (c *Client) Xxread(streams map[string]string, count int64) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	if len(streams) == 0 {
+		reject(errors.New("at least one stream must be provided to xread"))
+		return promise
+	}
+
+	args := streamsArg(streams)
+
+	go func() {
+		read, err := c.redisClient.XRead(c.context(), &redis.XReadArgs{
+			Streams: args,
+			Count:   count,
+			Block:   -1,
+		}).Result()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			reject(err)
+			return
+		}
+
+		resolve(c.readStreams(read))
+	}()
+
+	return promise
+}
+
+// Xxreadgroup reads the entries of one or more streams on behalf of
+// `consumer`, a member of the consumer group `group`. The `streams` object
+// maps each stream's key to the ID after which its entries should be read:
+// ">" reads the entries never delivered to the group's consumers, while
+// other IDs read the consumer's pending entries. When `count` is positive,
+// at most `count` entries are returned per stream.
+//
+// As for Xxread, read entries are counted by the redis_stream_entries_read
+// metric, and their latency emitted as the redis_stream_entry_latency one.
+//
+// The promise resolves with the read entries, in the same shape as Xxread.
+func (c *Client) Xxreadgroup(group, consumer string, streams map[string]string, count int64) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	if len(streams) == 0 {
+		reject(errors.New("at least one stream must be provided to xreadgroup"))
+		return promise
+	}
+
+	args := streamsArg(streams)
+
+	go func() {
+		read, err := c.redisClient.XReadGroup(c.context(), &redis.XReadGroupArgs{
+			Group:    group,
+			Consumer: consumer,
+			Streams:  args,
+			Count:    count,
+			Block:    -1,
+		}).Result()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			reject(err)
+			return
+		}
+
+		resolve(c.readStreams(read))
+	}()
+
+	return promise
+}
+
+// Xxrange returns the entries of the stream stored at `key` whose IDs are
+// within the `start` and `end` IDs, inclusive. The special "-" and "+" IDs
+// stand for the lowest and highest IDs of the stream. When `count` is
+// positive, at most `count` entries are returned.
+//
+// The promise resolves with an array of entries, each with an `id` and
+// `fields` property.
+func (c *Client) Xxrange(key, start, end string, count int64) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		var cmd *redis.XMessageSliceCmd
+		if count > 0 {
+			cmd = c.redisClient.XRangeN(c.context(), key, start, end, count)
+		} else {
+			cmd = c.redisClient.XRange(c.context(), key, start, end)
+		}
+
+		msgs, err := cmd.Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(streamEntries(msgs))
+	}()
+
+	return promise
+}
+
+// Xxack acknowledges the entries with the provided IDs as processed by the
+// consumer group `group` of the stream stored at `key`, removing them from
+// the group's pending entries list.
+//
+// The promise resolves with the number of acknowledged entries.
+func (c *Client) Xxack(key, group string, ids ...string) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	if len(ids) == 0 {
+		reject(errors.New("at least one entry ID must be provided to xack"))
+		return promise
+	}
+
+	go func() {
+		acked, err := c.redisClient.XAck(c.context(), key, group, ids...).Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(acked)
+	}()
+
+	return promise
+}
+
+// xgroupCreateOptions holds the options of XxgroupCreate.
+type xgroupCreateOptions struct {
+	// Mkstream creates the stream if it doesn't exist.
+	Mkstream bool `json:"mkstream,omitempty"`
+}
+
+// XxgroupCreate creates the consumer group `group` of the stream stored at
+// `key`, delivering the entries following the `start` ID: "$" for new
+// entries only, or "0" for all of them. With the `mkstream` option set, the
+// stream is created if it doesn't exist.
+//
+// The promise resolves with "OK".
+func (c *Client) XxgroupCreate(key, group, start string, options map[string]interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	var opts xgroupCreateOptions
+	if err := decodeOptions(options, &opts); err != nil {
+		reject(fmt.Errorf("invalid xgroupCreate options; reason: %w", err))
+		return promise
+	}
+
+	go func() {
+		var cmd *redis.StatusCmd
+		if opts.Mkstream {
+			cmd = c.redisClient.XGroupCreateMkStream(c.context(), key, group, start)
+		} else {
+			cmd = c.redisClient.XGroupCreate(c.context(), key, group, start)
+		}
+
+		result, err := cmd.Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(result)
+	}()
+
+	return promise
+}
+
+// Xxpending returns a summary of the pending entries of the consumer group
+// `group` of the stream stored at `key`: the entries delivered to its
+// consumers, but not acknowledged yet.
+//
+// The promise resolves with an object holding the `count` of pending
+// entries, the `lower` and `higher` of their IDs, and the number of pending
+// entries of each of the group's `consumers`.
+func (c *Client) Xxpending(key, group string) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		pending, err := c.redisClient.XPending(c.context(), key, group).Result()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(map[string]interface{}{
+			"count":     pending.Count,
+			"lower":     pending.Lower,
+			"higher":    pending.Higher,
+			"consumers": pending.Consumers,
+		})
+	}()
+
+	return promise
+}
+
+// streamsArg returns the streams argument of XREAD and XREADGROUP for the
+// provided object, mapping stream keys to IDs: all the stream keys first,
+// followed by their IDs.
+func streamsArg(streams map[string]string) []string {
+	args := make([]string, 0, 2*len(streams))
+	for key := range streams {
+		args = append(args, key)
+	}
+	for _, key := range args[:len(streams)] {
+		args = append(args, streams[key])
+	}
+
+	return args
+}
+
+// readStreams converts the entries read from streams by XREAD or XREADGROUP
+// to an array of objects holding the `stream` key, and its `entries`.
+//
+// Each entry is counted by the redis_stream_entries_read metric, and its
+// end-to-end latency is emitted as the redis_stream_entry_latency metric.
+func (c *Client) readStreams(read []redis.XStream) []map[string]interface{} {
+	now := time.Now()
+	result := make([]map[string]interface{}, 0, len(read))
+	for _, stream := range read {
+		for _, msg := range stream.Messages {
+			c.pushMetric(c.metrics.StreamEntriesRead, 1)
+			if latency, ok := streamEntryLatency(msg.ID, now); ok {
+				c.pushMetric(c.metrics.StreamEntryLatency, latency)
+			}
+		}
+
+		result = append(result, map[string]interface{}{
+			"stream":  stream.Stream,
+			"entries": streamEntries(stream.Messages),
+		})
+	}
+
+	return result
+}
+
+// streamEntries converts stream entries to an array of objects, each with
+// an `id` and `fields` property.
+func streamEntries(msgs []redis.XMessage) []map[string]interface{} {
+	entries := make([]map[string]interface{}, 0, len(msgs))
+	for _, msg := range msgs {
+		entries = append(entries, map[string]interface{}{
+			"id":     msg.ID,
+			"fields": msg.Values,
+		})
+	}
+
+	return entries
+}
+
+// streamEntryLatency returns the time elapsed, in milliseconds, between the
+// creation of the stream entry with the provided ID and `readAt`.
+//
+// Stream IDs are of the form `<milliseconds>-<sequence>`, where the
+// milliseconds part is the server's Unix time when the entry was added,
+// unless an explicit ID was provided. As the server's and k6's clocks
+// may drift apart, the latency is only approximate, and negative latencies
+// are reported as zero.
+func streamEntryLatency(id string, readAt time.Time) (float64, bool) {
+	msPart, _, _ := strings.Cut(id, "-")
+	ms, err := strconv.ParseInt(msPart, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	latency := readAt.UnixMilli() - ms
+	if latency < 0 {
+		latency = 0
+	}
+
+	return float64(latency), true
+}
+
+// defaultLagSampleInterval is the interval at which the consumer lag of
+// streams is sampled, unless the intervalMs option is set.
+const defaultLagSampleInterval = time.Second
+
+// sampleConsumerLagOptions holds the options of SampleConsumerLag.
+type sampleConsumerLagOptions struct {
+	// IntervalMs is the interval, in milliseconds, at which the lag is
+	// sampled.
+	IntervalMs int64 `json:"intervalMs,omitempty"`
+}
+
+// SampleConsumerLag periodically samples the lag of the consumer groups of
+// the stream stored at `key`, as reported by XINFO GROUPS, and emits it as
+// the redis_consumer_lag metric, tagged with the `stream` and `group`. It
+// tells whether the consumers of each group keep up with the producers.
+//
+// Sampling runs in the background, until the VU's context is done, at the
+// interval set by the `intervalMs` option, which defaults to one second.
+// Groups whose lag can't be determined by the server are reported with a
+// zero lag. Sampling errors following the first sample are ignored: the
+// samples are skipped, and sampling goes on.
+//
+// The promise resolves once the first sample is emitted. If the stream's
+// lag is already being sampled by the Client, or the first sample fails,
+// the promise is rejected with an error.
+func (c *Client) SampleConsumerLag(key string, options map[string]interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	var opts sampleConsumerLagOptions
+	if err := decodeOptions(options, &opts); err != nil {
+		reject(fmt.Errorf("invalid sampleConsumerLag options; reason: %w", err))
+		return promise
+	}
+
+	if opts.IntervalMs < 0 {
+		reject(fmt.Errorf("invalid intervalMs option: %d; expected a positive number", opts.IntervalMs))
+		return promise
+	}
+
+	interval := defaultLagSampleInterval
+	if opts.IntervalMs > 0 {
+		interval = time.Duration(opts.IntervalMs) * time.Millisecond
+	}
+
+	c.lagSamplersMu.Lock()
+	defer c.lagSamplersMu.Unlock()
+
+	if _, ok := c.lagSamplers[key]; ok {
+		reject(fmt.Errorf("the consumer lag of stream %q is already being sampled", key))
+		return promise
+	}
+
+	if c.lagSamplers == nil {
+		c.lagSamplers = make(map[string]struct{})
+	}
+	c.lagSamplers[key] = struct{}{}
+
+	go func() {
+		ctx := c.context()
+
+		if err := c.sampleConsumerLag(ctx, key); err != nil {
+			c.lagSamplersMu.Lock()
+			delete(c.lagSamplers, key)
+			c.lagSamplersMu.Unlock()
+
+			reject(err)
+			return
+		}
+
+		resolve(nil)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				_ = c.sampleConsumerLag(ctx, key)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return promise
+}
+
+// sampleConsumerLag emits the current lag of each consumer group of the
+// stream stored at `key` as the redis_consumer_lag metric.
+func (c *Client) sampleConsumerLag(ctx context.Context, key string) error {
+	groups, err := c.redisClient.XInfoGroups(ctx, key).Result()
+	if err != nil {
+		return err
+	}
+
+	for _, group := range groups {
+		c.pushTaggedMetric(c.metrics.ConsumerLag, float64(group.Lag), map[string]string{
+			"stream": key,
+			"group":  group.Name,
+		})
+	}
+
+	return nil
+}
@@ -0,0 +1,312 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientXadd(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunT(t)
+	rs.RegisterCommandHandler("XADD", func(c *Connection, args []string) {
+		if len(args) != 4 {
+			c.WriteError(errors.New("ERR unexpected number of arguments for 'XADD' command"))
+			return
+		}
+
+		c.WriteBulkString("1700000000000-0")
+	})
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client('redis://%s');
+
+			redis.xadd("events", "*", { kind: "click" })
+				.then(res => { if (res !== "1700000000000-0") { throw 'unexpected value for xadd result: ' + res } })
+				.then(() => redis.xadd("events", "*", {}))
+				.then(
+					res => { throw 'expected xadd without fields to fail' },
+					err => { if (err.error() !== 'at least one field must be provided to xadd') { throw 'unexpected error: ' + err } }
+				)
+		`, rs.Addr()))
+
+		return err
+	})
+
+	assert.NoError(t, gotScriptErr)
+	assert.Equal(t, 1, rs.HandledCommandsCount())
+	assert.Equal(t, [][]string{
+		{"HELLO", "2"},
+		{"XADD", "events", "*", "kind", "click"},
+	}, rs.GotCommands())
+
+	added := 0
+	for _, sample := range drainSamples(ts.samples) {
+		if sample.Metric.Name == "redis_stream_entries_added" {
+			added += int(sample.Value)
+		}
+	}
+	assert.Equal(t, 1, added)
+}
+
+func TestClientXread(t *testing.T) {
+	t.Parallel()
+
+	t.Run("entries are read and measured", func(t *testing.T) {
+		t.Parallel()
+
+		ts := newTestSetup(t)
+		rs := RunT(t)
+
+		createdAt := time.Now().Add(-time.Second).UnixMilli()
+		firstID := strconv.FormatInt(createdAt, 10) + "-0"
+		secondID := strconv.FormatInt(createdAt, 10) + "-1"
+
+		rs.RegisterCommandHandler("XREAD", func(c *Connection, args []string) {
+			c.WriteValue([]interface{}{
+				[]interface{}{
+					args[len(args)-2],
+					[]interface{}{
+						[]interface{}{firstID, []string{"kind", "click"}},
+						[]interface{}{secondID, []string{"kind", "scroll"}},
+					},
+				},
+			})
+		})
+
+		gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+			_, err := ts.rt.RunString(fmt.Sprintf(`
+				const redis = new Client('redis://%s');
+
+				redis.xread({ events: "0" }, 10)
+					.then(res => {
+						if (res.length !== 1 || res[0].stream !== "events") {
+							throw 'unexpected value for xread result: ' + JSON.stringify(res)
+						}
+
+						const entries = res[0].entries
+						if (entries.length !== 2 || entries[0].id !== "%s" || entries[1].fields.kind !== "scroll") {
+							throw 'unexpected value for xread entries: ' + JSON.stringify(entries)
+						}
+					})
+			`, rs.Addr(), firstID))
+
+			return err
+		})
+
+		assert.NoError(t, gotScriptErr)
+		assert.Equal(t, [][]string{
+			{"HELLO", "2"},
+			{"XREAD", "count", "10", "streams", "events", "0"},
+		}, rs.GotCommands())
+
+		var (
+			read      int
+			latencies []float64
+		)
+		for _, sample := range drainSamples(ts.samples) {
+			switch sample.Metric.Name {
+			case "redis_stream_entries_read":
+				read += int(sample.Value)
+			case "redis_stream_entry_latency":
+				latencies = append(latencies, sample.Value)
+			}
+		}
+
+		assert.Equal(t, 2, read)
+		assert.Len(t, latencies, 2)
+		for _, latency := range latencies {
+			assert.GreaterOrEqual(t, latency, float64(time.Second.Milliseconds()))
+		}
+	})
+
+	t.Run("no available entries resolves to an empty array", func(t *testing.T) {
+		t.Parallel()
+
+		ts := newTestSetup(t)
+		rs := RunT(t)
+		rs.RegisterCommandHandler("XREAD", func(c *Connection, _ []string) {
+			c.WriteNull()
+		})
+
+		gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+			_, err := ts.rt.RunString(fmt.Sprintf(`
+				const redis = new Client('redis://%s');
+
+				redis.xread({ events: "$" })
+					.then(res => { if (res.length !== 0) { throw 'unexpected value for xread result: ' + JSON.stringify(res) } })
+			`, rs.Addr()))
+
+			return err
+		})
+
+		assert.NoError(t, gotScriptErr)
+		assert.Equal(t, 1, rs.HandledCommandsCount())
+	})
+}
+
+func TestClientStreamConsumerGroups(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunT(t)
+	entry := []interface{}{"1700000000000-0", []interface{}{"task", "resize"}}
+	rs.RegisterCommandHandler("XGROUP", func(c *Connection, _ []string) {
+		c.WriteOK()
+	})
+	rs.RegisterCommandHandler("XREADGROUP", func(c *Connection, _ []string) {
+		c.WriteValue([]interface{}{[]interface{}{"jobs", []interface{}{entry}}})
+	})
+	rs.RegisterCommandHandler("XPENDING", func(c *Connection, _ []string) {
+		c.WriteValue([]interface{}{
+			1, "1700000000000-0", "1700000000000-0",
+			[]interface{}{[]interface{}{"worker-1", "1"}},
+		})
+	})
+	rs.RegisterCommandHandler("XACK", func(c *Connection, args []string) {
+		c.WriteInteger(len(args) - 2)
+	})
+	rs.RegisterCommandHandler("XRANGE", func(c *Connection, _ []string) {
+		c.WriteValue([]interface{}{entry})
+	})
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client('redis://%s');
+
+			redis.xgroupCreate("jobs", "workers", "$", { mkstream: true })
+				.then(res => { if (res !== "OK") { throw 'unexpected value for xgroupCreate result: ' + res } })
+				.then(() => redis.xreadgroup("workers", "worker-1", { jobs: ">" }, 10))
+				.then(res => {
+					if (res.length !== 1 || res[0].stream !== "jobs" || res[0].entries[0].fields.task !== "resize") {
+						throw 'unexpected value for xreadgroup result: ' + JSON.stringify(res)
+					}
+				})
+				.then(() => redis.xpending("jobs", "workers"))
+				.then(res => {
+					if (res.count !== 1 || res.lower !== "1700000000000-0" || res.consumers["worker-1"] !== 1) {
+						throw 'unexpected value for xpending result: ' + JSON.stringify(res)
+					}
+				})
+				.then(() => redis.xack("jobs", "workers", "1700000000000-0"))
+				.then(res => { if (res !== 1) { throw 'unexpected value for xack result: ' + res } })
+				.then(() => redis.xrange("jobs", "-", "+", 5))
+				.then(res => {
+					if (res.length !== 1 || res[0].id !== "1700000000000-0") {
+						throw 'unexpected value for xrange result: ' + JSON.stringify(res)
+					}
+				})
+		`, rs.Addr()))
+
+		return err
+	})
+
+	assert.NoError(t, gotScriptErr)
+	assert.Equal(t, [][]string{
+		{"HELLO", "2"},
+		{"XGROUP", "create", "jobs", "workers", "$", "mkstream"},
+		{"XREADGROUP", "group", "workers", "worker-1", "count", "10", "streams", "jobs", ">"},
+		{"XPENDING", "jobs", "workers"},
+		{"XACK", "jobs", "workers", "1700000000000-0"},
+		{"XRANGE", "jobs", "-", "+", "count", "5"},
+	}, rs.GotCommands())
+}
+
+func TestClientSampleConsumerLag(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunT(t)
+	rs.RegisterCommandHandler("XINFO", func(c *Connection, _ []string) {
+		c.WriteValue([]interface{}{
+			[]interface{}{
+				"name", "workers", "consumers", 2, "pending", 1,
+				"last-delivered-id", "1700000000000-0", "entries-read", 5, "lag", 3,
+			},
+		})
+	})
+
+	ctx, cancel := context.WithCancel(ts.runtime.VU.CtxField)
+	defer cancel()
+	ts.runtime.VU.CtxField = ctx
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client('redis://%s');
+
+			redis.sampleConsumerLag("jobs", { intervalMs: 20 })
+				.then(() => redis.sampleConsumerLag("jobs"))
+				.then(
+					res => { throw 'expected sampleConsumerLag to fail' },
+					err => { if (!err.error().includes('already being sampled')) { throw 'unexpected error: ' + err.error() } }
+				)
+		`, rs.Addr()))
+
+		return err
+	})
+	assert.NoError(t, gotScriptErr)
+
+	// Let the sampler run for a few intervals.
+	time.Sleep(100 * time.Millisecond)
+
+	samples := 0
+	for _, sample := range drainSamples(ts.samples) {
+		if sample.Metric.Name != "redis_consumer_lag" {
+			continue
+		}
+
+		samples++
+		assert.Equal(t, 3.0, sample.Value)
+
+		stream, _ := sample.Tags.Get("stream")
+		group, _ := sample.Tags.Get("group")
+		assert.Equal(t, "jobs", stream)
+		assert.Equal(t, "workers", group)
+	}
+	assert.GreaterOrEqual(t, samples, 3)
+	assert.Contains(t, rs.GotCommands(), []string{"XINFO", "groups", "jobs"})
+
+	// Sampling stops once the VU's context is done.
+	cancel()
+	time.Sleep(50 * time.Millisecond)
+	handled := rs.HandledCommandsCount()
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, handled, rs.HandledCommandsCount())
+}
+
+func TestStreamEntryLatency(t *testing.T) {
+	t.Parallel()
+
+	readAt := time.UnixMilli(1700000000500)
+
+	tests := []struct {
+		name       string
+		id         string
+		expLatency float64
+		expOK      bool
+	}{
+		{name: "auto-generated id", id: "1700000000000-0", expLatency: 500, expOK: true},
+		{name: "id without sequence", id: "1700000000000", expLatency: 500, expOK: true},
+		{name: "id in the future", id: "1700000001000-3", expLatency: 0, expOK: true},
+		{name: "non numeric id", id: "abc-0", expOK: false},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			latency, ok := streamEntryLatency(tt.id, readAt)
+			assert.Equal(t, tt.expOK, ok)
+			assert.Equal(t, tt.expLatency, latency)
+		})
+	}
+}
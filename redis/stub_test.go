@@ -375,6 +375,16 @@ func (c *Connection) WriteArray(arr ...string) {
 	})
 }
 
+// WriteValue writes the provided value as a redis message to the
+// Connection's writer. Strings are written as bulk strings, integers as
+// integers, nil as a Null message, errors as error messages, and slices
+// of values as (possibly nested) arrays.
+func (c *Connection) WriteValue(v interface{}) {
+	c.callFn(func(w *RESPResponseWriter) {
+		w.WriteValue(v)
+	})
+}
+
 // WriteNull writes a redis Null message to the Connection's writer.
 func (c *Connection) WriteNull() {
 	c.callFn(func(w *RESPResponseWriter) {
@@ -481,6 +491,35 @@ func (rw *RESPResponseWriter) WriteArray(strs ...string) {
 	}
 }
 
+// WriteValue writes a value of any supported type, recursively
+// writing the elements of slices as arrays.
+func (rw *RESPResponseWriter) WriteValue(v interface{}) {
+	switch val := v.(type) {
+	case nil:
+		rw.WriteNull()
+	case string:
+		rw.WriteBulkString(val)
+	case int:
+		rw.WriteInteger(val)
+	case int64:
+		rw.WriteInteger(int(val))
+	case error:
+		rw.WriteError(val)
+	case []string:
+		rw.writeLen(len(val))
+		for _, s := range val {
+			rw.WriteBulkString(s)
+		}
+	case []interface{}:
+		rw.writeLen(len(val))
+		for _, elem := range val {
+			rw.WriteValue(elem)
+		}
+	default:
+		panic(fmt.Sprintf("unsupported value type %T", v))
+	}
+}
+
 // WriteNull writes a redis Null element
 func (rw *RESPResponseWriter) WriteNull() {
 	_, _ = fmt.Fprintf(rw.writer, "$-1\r\n")
@@ -585,3 +624,94 @@ func scanLine(r *bufio.Reader) (string, error) {
 
 	return line, nil
 }
+
+// stubClusterShard describes a shard of a cluster emulated by stub servers:
+// a master server, and its replica servers.
+type stubClusterShard struct {
+	master   *StubServer
+	replicas []*StubServer
+}
+
+// registerClusterSlotsHandler registers a CLUSTER command handler on every
+// server of the provided shards, replying to the CLUSTER SLOTS, SHARDS, and
+// NODES subcommands with a layout in which the slots are evenly split
+// between the shards, and to CLUSTER KEYSLOT. The ID of each node is its
+// port, as 40 hexadecimal digits.
+func registerClusterSlotsHandler(shards ...stubClusterShard) {
+	const slotsCount = 16384
+
+	nodeID := func(rs *StubServer) string {
+		return fmt.Sprintf("%040x", rs.Addr().Port)
+	}
+
+	nodeInfo := func(rs *StubServer) []interface{} {
+		return []interface{}{rs.Addr().IP.String(), rs.Addr().Port, nodeID(rs)}
+	}
+
+	shardNode := func(rs *StubServer, role string) []interface{} {
+		return []interface{}{
+			"id", nodeID(rs), "port", rs.Addr().Port, "ip", rs.Addr().IP.String(),
+			"endpoint", rs.Addr().IP.String(), "role", role, "replication-offset", 0, "health", "online",
+		}
+	}
+
+	var (
+		slots      = make([]interface{}, 0, len(shards))
+		shardsInfo = make([]interface{}, 0, len(shards))
+		nodes      strings.Builder
+	)
+	for idx, shard := range shards {
+		start := idx * slotsCount / len(shards)
+		end := (idx+1)*slotsCount/len(shards) - 1
+
+		slot := []interface{}{start, end, nodeInfo(shard.master)}
+		shardNodes := []interface{}{shardNode(shard.master, "master")}
+		fmt.Fprintf(&nodes, "%s %s@1%d master - 0 0 %d connected %d-%d\n",
+			nodeID(shard.master), shard.master.Addr(), shard.master.Addr().Port, idx+1, start, end)
+
+		for _, replica := range shard.replicas {
+			slot = append(slot, nodeInfo(replica))
+			shardNodes = append(shardNodes, shardNode(replica, "replica"))
+			fmt.Fprintf(&nodes, "%s %s@1%d slave %s 0 0 %d connected\n",
+				nodeID(replica), replica.Addr(), replica.Addr().Port, nodeID(shard.master), idx+1)
+		}
+
+		slots = append(slots, slot)
+		shardsInfo = append(shardsInfo, []interface{}{"slots", []interface{}{start, end}, "nodes", shardNodes})
+	}
+
+	handler := func(c *Connection, args []string) {
+		if len(args) == 0 {
+			c.WriteError(ErrUnknownCommand)
+			return
+		}
+
+		switch strings.ToUpper(args[0]) {
+		case "SLOTS":
+			c.WriteValue(slots)
+		case "SHARDS":
+			c.WriteValue(shardsInfo)
+		case "NODES":
+			c.WriteBulkString(nodes.String())
+		case "KEYSLOT":
+			if len(args) != 2 {
+				c.WriteError(errors.New("wrong number of arguments"))
+				return
+			}
+			c.WriteInteger(keySlot(args[1]))
+		default:
+			c.WriteError(ErrUnknownCommand)
+		}
+	}
+
+	readOnlyHandler := func(c *Connection, _ []string) {
+		c.WriteOK()
+	}
+
+	for _, shard := range shards {
+		for _, rs := range append([]*StubServer{shard.master}, shard.replicas...) {
+			rs.RegisterCommandHandler("CLUSTER", handler)
+			rs.RegisterCommandHandler("READONLY", readOnlyHandler)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package redis
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/grafana/sobek"
+	"go.k6.io/k6/js/common"
+	"go.k6.io/k6/js/modules"
+)
+
+// syncCalls makes the methods of the clients created with the blocking
+// option wait for the results of their commands, and return them, or
+// throw their errors, rather than return promises, so that scripts don't
+// need to await them.
+//
+// The clients' methods still run their commands in the background, and
+// return a promise. JS reaches them through syncObject wrappers instead,
+// which block the event loop until the promises the methods created are
+// settled, then unwrap them.
+type syncCalls struct {
+	vu modules.VU
+
+	// pending holds the calls started by the method being called. As
+	// methods are called from the event loop, it needs no locking.
+	pending []*syncCall
+}
+
+// syncCall is a command started by a method of a blocking client.
+type syncCall struct {
+	promise *sobek.Promise
+
+	// convert, if set, converts the result on the event loop, such as
+	// to create ArrayBuffer objects.
+	convert func(rt *sobek.Runtime, result interface{}) interface{}
+
+	once     sync.Once
+	done     chan struct{}
+	result   interface{}
+	reason   interface{}
+	rejected bool
+}
+
+// settle records the outcome of the call, unless it was already settled.
+func (c *syncCall) settle(result, reason interface{}, rejected bool) {
+	c.once.Do(func() {
+		c.result, c.reason, c.rejected = result, reason, rejected
+		close(c.done)
+	})
+}
+
+// newPromise is the newPromise of blocking clients: the promise it returns
+// is never settled, but its outcome is recorded for the syncObject
+// wrapper calling the method to return it.
+func (s *syncCalls) newPromise(
+	convert func(rt *sobek.Runtime, result interface{}) interface{},
+) (*sobek.Promise, func(result interface{}), func(reason interface{})) {
+	promise, _, _ := s.vu.Runtime().NewPromise()
+
+	call := &syncCall{promise: promise, convert: convert, done: make(chan struct{})}
+	s.pending = append(s.pending, call)
+
+	resolve := func(result interface{}) {
+		call.settle(result, nil, false)
+	}
+
+	reject := func(reason interface{}) {
+		if err, ok := reason.(error); ok {
+			reason = classifyError(err)
+		}
+
+		call.settle(nil, reason, true)
+	}
+
+	return promise, resolve, reject
+}
+
+// wrap returns `value` as a JS value, wrapped in a syncObject if it is one
+// of the module's objects whose methods return promises.
+func (s *syncCalls) wrap(value interface{}) sobek.Value {
+	rt := s.vu.Runtime()
+
+	switch value.(type) {
+	case *Client, *Pipeline, *Lock, *Script:
+		return rt.NewDynamicObject(&syncObject{calls: s, target: rt.ToValue(value).ToObject(rt)})
+	default:
+		return rt.ToValue(value)
+	}
+}
+
+// call calls `method`, and waits for the command it started, if any, to
+// complete. It returns the command's result, or throws its error.
+func (s *syncCalls) call(method sobek.Callable, this sobek.Value, args []sobek.Value) sobek.Value {
+	rt := s.vu.Runtime()
+
+	s.pending = nil
+	ret, err := method(this, args...)
+	calls := s.pending
+	s.pending = nil
+
+	if err != nil {
+		common.Throw(rt, err)
+	}
+
+	var returned *syncCall
+	for _, call := range calls {
+		select {
+		case <-call.done:
+		case <-s.vu.Context().Done():
+			common.Throw(rt, fmt.Errorf("the command was interrupted; reason: %w", s.vu.Context().Err()))
+		}
+
+		if ret.Export() == call.promise {
+			returned = call
+		}
+	}
+
+	if returned == nil {
+		switch value := ret.Export().(type) {
+		case *Client, *Pipeline, *Lock, *Script:
+			return s.wrap(value)
+		default:
+			return ret
+		}
+	}
+
+	if returned.rejected {
+		panic(rt.ToValue(returned.reason))
+	}
+
+	result := returned.result
+	if returned.convert != nil {
+		result = returned.convert(rt, result)
+	}
+
+	return s.wrap(result)
+}
+
+// syncObject exposes the properties of one of the module's objects to JS,
+// replacing its methods with functions waiting for their results, as
+// syncCalls.call does.
+type syncObject struct {
+	calls  *syncCalls
+	target *sobek.Object
+}
+
+var _ sobek.DynamicObject = &syncObject{}
+
+// Get implements sobek.DynamicObject.
+func (o *syncObject) Get(key string) sobek.Value {
+	value := o.target.Get(key)
+
+	method, ok := sobek.AssertFunction(value)
+	if !ok {
+		return value
+	}
+
+	return o.calls.vu.Runtime().ToValue(func(call sobek.FunctionCall) sobek.Value {
+		return o.calls.call(method, o.target, call.Arguments)
+	})
+}
+
+// Set implements sobek.DynamicObject.
+func (o *syncObject) Set(key string, value sobek.Value) bool {
+	return o.target.Set(key, value) == nil
+}
+
+// Has implements sobek.DynamicObject.
+func (o *syncObject) Has(key string) bool {
+	return o.target.Get(key) != nil
+}
+
+// Delete implements sobek.DynamicObject.
+func (o *syncObject) Delete(key string) bool {
+	return o.target.Delete(key) == nil
+}
+
+// Keys implements sobek.DynamicObject.
+func (o *syncObject) Keys() []string {
+	return o.target.Keys()
+}
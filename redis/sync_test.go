@@ -0,0 +1,88 @@
+package redis
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlockingClient(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunT(t)
+	rs.RegisterCommandHandler("GET", func(c *Connection, args []string) {
+		c.WriteBulkString("value:" + args[0])
+	})
+	rs.RegisterCommandHandler("SET", func(c *Connection, _ []string) {
+		c.WriteOK()
+	})
+	rs.RegisterCommandHandler("LPUSH", func(c *Connection, _ []string) {
+		c.WriteError(errors.New("WRONGTYPE Operation against a key holding the wrong kind of value"))
+	})
+	rs.RegisterCommandHandler("INCR", func(c *Connection, _ []string) {
+		c.WriteInteger(1)
+	})
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client({ socket: { host: "%s", port: %d }, blocking: true });
+
+			if (redis.options().blocking !== true) {
+				throw 'expected the blocking option to be reported'
+			}
+
+			const value = redis.get("foo");
+			if (value !== "value:foo") {
+				throw 'unexpected value for get result: ' + value
+			}
+
+			const buffer = redis.getBuffer("bar");
+			if (!(buffer instanceof ArrayBuffer) || buffer.byteLength !== 9) {
+				throw 'expected getBuffer to return an ArrayBuffer'
+			}
+
+			const results = redis.pipeline().set("foo", "bar", 0).incr("counter").exec();
+			if (results.length !== 2 || results[0] !== "OK" || results[1] !== 1) {
+				throw 'unexpected value for exec result: ' + JSON.stringify(results)
+			}
+
+			if (redis.withTimeout(1000).get("baz") !== "value:baz") {
+				throw 'expected clients derived from blocking clients to block too'
+			}
+
+			try {
+				redis.lpush("foo", "value");
+				throw 'expected lpush to throw';
+			} catch (err) {
+				if (err.kind !== "wrongtype") {
+					throw 'unexpected error: ' + err
+				}
+			}
+
+			try {
+				redis.watch(["foo"], () => {});
+				throw 'expected watch to throw';
+			} catch (err) {
+				if (err.error() !== "watch is not supported by blocking clients") {
+					throw 'unexpected error: ' + err
+				}
+			}
+		`, rs.Addr().IP.String(), rs.Addr().Port))
+
+		return err
+	})
+
+	assert.NoError(t, gotScriptErr)
+	assert.Equal(t, [][]string{
+		{"HELLO", "2"},
+		{"GET", "foo"},
+		{"GET", "bar"},
+		{"SET", "foo", "bar"},
+		{"INCR", "counter"},
+		{"GET", "baz"},
+		{"LPUSH", "foo", "value"},
+	}, rs.GotCommands())
+}
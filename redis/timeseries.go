@@ -0,0 +1,564 @@
+package redis
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/grafana/sobek"
+	"github.com/redis/go-redis/v9"
+)
+
+// tsAppendScript adds a member to a sorted set, trims the members whose
+// score is older than the retention window, if any, and returns the
+// resulting cardinality of the set. Running all of it as a script makes it
+// atomic, so that concurrent appends and trims never interleave.
+//
+// KEYS[1] is the sorted set's key, ARGV[1] the new member's score, ARGV[2]
+// the member, and ARGV[3] the retention window, zero disabling trimming.
+var tsAppendScript = redis.NewScript(tsAppendSource)
+
+// tsAppendSource is the source of tsAppendScript.
+const tsAppendSource = `
+local timestamp = tonumber(ARGV[1])
+local retention = tonumber(ARGV[3])
+
+redis.call('ZADD', KEYS[1], timestamp, ARGV[2])
+if retention > 0 then
+	redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', '(' .. (timestamp - retention))
+end
+
+return redis.call('ZCARD', KEYS[1])
+`
+
+// tsAppendOptions holds the options of TsAppend.
+type tsAppendOptions struct {
+	// RetentionMs is the duration, in milliseconds, entries are retained
+	// for, relative to the timestamp of the appended entry. Zero disables
+	// trimming.
+	RetentionMs int64 `json:"retentionMs,omitempty"`
+}
+
+// TsAppend appends `value` to the time series stored, as a sorted set, at
+// `key`, with `timestamp`, in milliseconds, as its score. With the
+// `retentionMs` option set, the entries older than `timestamp` minus the
+// retention are removed, so that the sorted set holds a sliding window of
+// entries.
+//
+// The append and the trim are performed atomically, by a Lua script, so
+// that concurrent VUs appending to the same time series can't race. As
+// sorted sets hold unique members, appending a value already present in the
+// time series moves it to the new timestamp.
+//
+// The promise resolves with the number of entries of the time series.
+func (c *Client) TsAppend(key string, timestamp float64, value interface{}, options map[string]interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	if err := c.isSupportedType(2, value); err != nil {
+		reject(err)
+		return promise
+	}
+
+	var opts tsAppendOptions
+	if err := decodeOptions(options, &opts); err != nil {
+		reject(fmt.Errorf("invalid tsAppend options; reason: %w", err))
+		return promise
+	}
+
+	if opts.RetentionMs < 0 {
+		reject(fmt.Errorf("invalid retentionMs option: %d; expected a positive number", opts.RetentionMs))
+		return promise
+	}
+
+	go func() {
+		length, err := tsAppendScript.Run(c.context(), c.redisClient, []string{key},
+			timestamp, value, opts.RetentionMs).Int64()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(length)
+	}()
+
+	return promise
+}
+
+// tsAddOptions holds the options of the Client's tsAdd method, which apply
+// when the time series is created by the call.
+type tsAddOptions struct {
+	// RetentionMs is the duration, in milliseconds, samples are retained
+	// for, relative to the latest sample. Zero retains them forever.
+	RetentionMs int64 `json:"retentionMs,omitempty"`
+
+	// Labels are the labels of the time series, which tsMRange filters
+	// time series by.
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// OnDuplicate is the policy applied to samples added with the
+	// timestamp of an existing sample: "block", "first", "last", "min",
+	// "max", or "sum".
+	OnDuplicate string `json:"onDuplicate,omitempty"`
+}
+
+// TsAdd adds a sample of `value`, at `timestamp`, in milliseconds, or "*"
+// for the server's current time, to the RedisTimeSeries time series
+// stored at `key`, creating it with the provided options if it does not
+// exist.
+//
+// The promise resolves with the timestamp of the added sample.
+func (c *Client) TsAdd(key string, timestamp interface{}, value float64, options map[string]interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	ts, err := tsTimestamp(timestamp, "*")
+	if err != nil {
+		reject(fmt.Errorf("invalid tsAdd timestamp; %w", err))
+		return promise
+	}
+
+	var opts tsAddOptions
+	if err := decodeOptions(options, &opts); err != nil {
+		reject(fmt.Errorf("invalid tsAdd options; reason: %w", err))
+		return promise
+	}
+
+	args := []interface{}{"ts.add", key, ts, value}
+	if opts.RetentionMs != 0 {
+		args = append(args, "retention", opts.RetentionMs)
+	}
+	if opts.OnDuplicate != "" {
+		args = append(args, "on_duplicate", opts.OnDuplicate)
+	}
+	if len(opts.Labels) > 0 {
+		args = append(args, "labels")
+		for _, label := range sortedKeys(opts.Labels) {
+			args = append(args, label, opts.Labels[label])
+		}
+	}
+
+	go func() {
+		added, err := c.redisClient.Do(c.context(), args...).Int64()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(added)
+	}()
+
+	return promise
+}
+
+// tsSample is a sample added with the Client's tsMAdd method.
+type tsSample struct {
+	Key       string      `json:"key"`
+	Timestamp interface{} `json:"timestamp"`
+	Value     float64     `json:"value"`
+}
+
+// TsMAdd adds the provided samples, objects holding a `key`, a
+// `timestamp`, and a `value`, as with TsAdd, to existing time series.
+//
+// The promise resolves with an array holding the timestamp of each added
+// sample, in order. If any of the samples could not be added, such as
+// when its time series does not exist, the promise is rejected with the
+// error of the first of them.
+func (c *Client) TsMAdd(samples []interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	if len(samples) == 0 {
+		reject(errors.New("at least one sample must be provided to tsMAdd"))
+		return promise
+	}
+
+	args := make([]interface{}, 0, 1+3*len(samples))
+	args = append(args, "ts.madd")
+	for idx, sample := range samples {
+		obj, ok := sample.(map[string]interface{})
+		if !ok {
+			reject(fmt.Errorf("invalid tsMAdd sample at index %d; expected an object", idx))
+			return promise
+		}
+
+		var s tsSample
+		if err := decodeOptions(obj, &s); err != nil {
+			reject(fmt.Errorf("invalid tsMAdd sample at index %d; reason: %w", idx, err))
+			return promise
+		}
+
+		ts, err := tsTimestamp(s.Timestamp, "*")
+		if err != nil {
+			reject(fmt.Errorf("invalid tsMAdd sample at index %d; %w", idx, err))
+			return promise
+		}
+
+		args = append(args, s.Key, ts, s.Value)
+	}
+
+	go func() {
+		replies, err := c.redisClient.Do(c.context(), args...).Slice()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		timestamps := make([]int64, len(replies))
+		for idx, reply := range replies {
+			switch reply := reply.(type) {
+			case int64:
+				timestamps[idx] = reply
+			case error:
+				reject(fmt.Errorf("unable to add the tsMAdd sample at index %d; reason: %w", idx, reply))
+				return
+			default:
+				reject(fmt.Errorf("invalid tsMAdd reply at index %d; unexpected type %T", idx, reply))
+				return
+			}
+		}
+
+		resolve(timestamps)
+	}()
+
+	return promise
+}
+
+// tsAggregation aggregates the samples returned by tsRange, and tsMRange,
+// in buckets.
+type tsAggregation struct {
+	// Type is the aggregator, such as "avg", "sum", "min", "max",
+	// "count", "first", "last", "range", "std.p", or "twa".
+	Type string `json:"type"`
+
+	// BucketMs is the duration of the buckets, in milliseconds.
+	BucketMs int64 `json:"bucketMs"`
+}
+
+// tsValueFilter filters the samples returned by tsRange, and tsMRange, by
+// value.
+type tsValueFilter struct {
+	Min float64 `json:"min"`
+	Max float64 `json:"max"`
+}
+
+// tsGroupBy groups the time series returned by tsMRange by label.
+type tsGroupBy struct {
+	// Label is the label whose values the time series are grouped by.
+	Label string `json:"label"`
+
+	// Reduce is the reducer merging the samples of each group, such as
+	// "avg", "sum", "min", "max", or "count".
+	Reduce string `json:"reduce"`
+}
+
+// tsRangeOptions holds the options of the Client's tsRange and tsMRange
+// methods. The withLabels, selectedLabels, and groupBy options only apply
+// to tsMRange.
+type tsRangeOptions struct {
+	Latest         bool           `json:"latest,omitempty"`
+	FilterByTs     []int64        `json:"filterByTs,omitempty"`
+	FilterByValue  *tsValueFilter `json:"filterByValue,omitempty"`
+	WithLabels     bool           `json:"withLabels,omitempty"`
+	SelectedLabels []string       `json:"selectedLabels,omitempty"`
+	Count          int64          `json:"count,omitempty"`
+	Aggregation    *tsAggregation `json:"aggregation,omitempty"`
+	GroupBy        *tsGroupBy     `json:"groupBy,omitempty"`
+}
+
+// args returns the arguments of TS.RANGE, or TS.MRANGE, preceding the
+// filters of the latter, for the options.
+func (o tsRangeOptions) args() ([]interface{}, error) {
+	var args []interface{}
+
+	if o.Latest {
+		args = append(args, "latest")
+	}
+	if len(o.FilterByTs) > 0 {
+		args = append(args, "filter_by_ts")
+		for _, ts := range o.FilterByTs {
+			args = append(args, ts)
+		}
+	}
+	if o.FilterByValue != nil {
+		args = append(args, "filter_by_value", o.FilterByValue.Min, o.FilterByValue.Max)
+	}
+
+	if o.WithLabels && len(o.SelectedLabels) > 0 {
+		return nil, errors.New("withLabels and selectedLabels are mutually exclusive")
+	}
+	if o.WithLabels {
+		args = append(args, "withlabels")
+	}
+	if len(o.SelectedLabels) > 0 {
+		args = append(args, "selected_labels")
+		args = append(args, stringsToArgs(o.SelectedLabels)...)
+	}
+
+	if o.Count < 0 {
+		return nil, fmt.Errorf("invalid count: %d; expected a positive number", o.Count)
+	}
+	if o.Count > 0 {
+		args = append(args, "count", o.Count)
+	}
+
+	if o.Aggregation != nil {
+		if o.Aggregation.Type == "" || o.Aggregation.BucketMs <= 0 {
+			return nil, errors.New("aggregation requires a type, and a positive bucketMs")
+		}
+		args = append(args, "aggregation", o.Aggregation.Type, o.Aggregation.BucketMs)
+	}
+
+	return args, nil
+}
+
+// TsRange returns the samples of the time series stored at `key`, from
+// `from` to `to`, both inclusive, which are timestamps in milliseconds,
+// or "-" and "+" for the earliest and latest samples.
+//
+// The `filterByTs`, and `filterByValue`, options only return the samples
+// with one of the provided timestamps, or whose value is within `min` and
+// `max`. The `count` option limits the number of samples returned. The
+// `aggregation` option aggregates the samples in buckets of `bucketMs`
+// milliseconds, with the aggregator of its `type`, such as "avg".
+//
+// The promise resolves with an array of objects holding the `timestamp`,
+// and the `value`, of each sample, in chronological order.
+func (c *Client) TsRange(key string, from, to interface{}, options map[string]interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	args, _, err := tsRangeArgs("tsRange", from, to, options)
+	if err != nil {
+		reject(err)
+		return promise
+	}
+	args = append([]interface{}{"ts.range", key}, args...)
+
+	go func() {
+		reply, err := c.redisClient.Do(c.context(), args...).Slice()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		samples, err := tsSamples(reply)
+		if err != nil {
+			reject(fmt.Errorf("invalid tsRange reply for key %q; %w", key, err))
+			return
+		}
+
+		resolve(samples)
+	}()
+
+	return promise
+}
+
+// TsMRange is like TsRange, except that it returns the samples of all the
+// time series matching the provided `filters`, such as "sensor=temp", or
+// "region=(eu,us)", as supported by TS.MRANGE.
+//
+// On top of those of tsRange, the `withLabels` option returns the labels
+// of the time series, or the `selectedLabels` option only the provided
+// ones, and the `groupBy` option merges the time series sharing the same
+// value of its `label`, with its `reduce` reducer, such as "sum".
+//
+// The promise resolves with an array of objects holding the `key` of each
+// time series, its `labels`, as an object, empty unless requested, and
+// its `samples`, as returned by tsRange.
+func (c *Client) TsMRange(from, to interface{}, filters []string, options map[string]interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	if len(filters) == 0 {
+		reject(errors.New("at least one filter must be provided to tsMRange"))
+		return promise
+	}
+
+	args, opts, err := tsRangeArgs("tsMRange", from, to, options)
+	if err != nil {
+		reject(err)
+		return promise
+	}
+
+	args = append([]interface{}{"ts.mrange"}, args...)
+	args = append(args, "filter")
+	args = append(args, stringsToArgs(filters)...)
+
+	if opts.GroupBy != nil {
+		if opts.GroupBy.Label == "" || opts.GroupBy.Reduce == "" {
+			reject(errors.New("invalid tsMRange options; groupBy requires a label, and a reduce reducer"))
+			return promise
+		}
+		args = append(args, "groupby", opts.GroupBy.Label, "reduce", opts.GroupBy.Reduce)
+	}
+
+	go func() {
+		reply, err := c.redisClient.Do(c.context(), args...).Slice()
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		series := make([]map[string]interface{}, 0, len(reply))
+		for _, r := range reply {
+			s, err := tsSeries(r)
+			if err != nil {
+				reject(fmt.Errorf("invalid tsMRange reply; %w", err))
+				return
+			}
+
+			series = append(series, s)
+		}
+
+		resolve(series)
+	}()
+
+	return promise
+}
+
+// tsRangeArgs returns the arguments of TS.RANGE, following the key, or of
+// TS.MRANGE, preceding its filters, on behalf of `method`, along with the
+// decoded options.
+func tsRangeArgs(
+	method string, from, to interface{}, options map[string]interface{},
+) ([]interface{}, tsRangeOptions, error) {
+	var opts tsRangeOptions
+
+	fromTs, err := tsTimestamp(from, "-")
+	if err != nil {
+		return nil, opts, fmt.Errorf("invalid %s from timestamp; %w", method, err)
+	}
+
+	toTs, err := tsTimestamp(to, "+")
+	if err != nil {
+		return nil, opts, fmt.Errorf("invalid %s to timestamp; %w", method, err)
+	}
+
+	if err := decodeOptions(options, &opts); err != nil {
+		return nil, opts, fmt.Errorf("invalid %s options; reason: %w", method, err)
+	}
+
+	if method == "tsRange" && (opts.WithLabels || len(opts.SelectedLabels) > 0 || opts.GroupBy != nil) {
+		return nil, opts, errors.New(
+			"invalid tsRange options; withLabels, selectedLabels, and groupBy only apply to tsMRange")
+	}
+
+	optArgs, err := opts.args()
+	if err != nil {
+		return nil, opts, fmt.Errorf("invalid %s options; %w", method, err)
+	}
+
+	return append([]interface{}{fromTs, toTs}, optArgs...), opts, nil
+}
+
+// tsTimestamp returns the argument standing for the provided timestamp: a
+// number of milliseconds, or the `special` timestamp, such as "*".
+func tsTimestamp(timestamp interface{}, special string) (interface{}, error) {
+	switch ts := timestamp.(type) {
+	case int64:
+		return ts, nil
+	case float64:
+		return int64(ts), nil
+	case string:
+		if ts == special {
+			return ts, nil
+		}
+	}
+
+	return nil, fmt.Errorf("expected a number of milliseconds, or %q; got %v", special, timestamp)
+}
+
+// tsSamples parses the samples of a TS.RANGE reply, each being an array of
+// a timestamp and a value.
+func tsSamples(reply []interface{}) ([]map[string]interface{}, error) {
+	samples := make([]map[string]interface{}, 0, len(reply))
+	for _, r := range reply {
+		pair, ok := r.([]interface{})
+		if !ok || len(pair) != 2 {
+			return nil, errors.New("expected timestamp and value pairs")
+		}
+
+		timestamp, ok := pair[0].(int64)
+		if !ok {
+			return nil, fmt.Errorf("unexpected timestamp of type %T", pair[0])
+		}
+
+		raw, ok := pair[1].(string)
+		if !ok {
+			return nil, fmt.Errorf("unexpected value of type %T", pair[1])
+		}
+
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse value %q; reason: %w", raw, err)
+		}
+
+		samples = append(samples, map[string]interface{}{"timestamp": timestamp, "value": value})
+	}
+
+	return samples, nil
+}
+
+// tsSeries parses a time series of a TS.MRANGE reply, an array of its key,
+// its labels, as an array of name and value pairs, and its samples.
+func tsSeries(reply interface{}) (map[string]interface{}, error) {
+	parts, ok := reply.([]interface{})
+	if !ok || len(parts) != 3 {
+		return nil, errors.New("expected key, labels, and samples triplets")
+	}
+
+	key, ok := parts[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("unexpected key of type %T", parts[0])
+	}
+
+	rawLabels, ok := parts[1].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected labels of type %T for key %q", parts[1], key)
+	}
+
+	labels := make(map[string]interface{}, len(rawLabels))
+	for _, l := range rawLabels {
+		pair, ok := l.([]interface{})
+		if !ok || len(pair) != 2 {
+			return nil, fmt.Errorf("expected label name and value pairs for key %q", key)
+		}
+
+		name, _ := pair[0].(string)
+		labels[name] = pair[1]
+	}
+
+	rawSamples, ok := parts[2].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected samples of type %T for key %q", parts[2], key)
+	}
+
+	samples, err := tsSamples(rawSamples)
+	if err != nil {
+		return nil, fmt.Errorf("%w for key %q", err, key)
+	}
+
+	return map[string]interface{}{"key": key, "labels": labels, "samples": samples}, nil
+}
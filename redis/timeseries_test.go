@@ -0,0 +1,133 @@
+package redis
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientTsAppend(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunT(t)
+	rs.RegisterCommandHandler("EVALSHA", func(c *Connection, _ []string) {
+		c.WriteError(errors.New("NOSCRIPT No matching script. Please use EVAL."))
+	})
+	rs.RegisterCommandHandler("EVAL", func(c *Connection, _ []string) {
+		c.WriteInteger(3)
+	})
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client('redis://%s');
+
+			redis.tsAppend("cpu", 1700000000000, "42", { retentionMs: 60000 })
+				.then(res => { if (res !== 3) { throw 'unexpected value for tsAppend result: ' + res } })
+				.then(() => redis.tsAppend("cpu", 1700000000000, "42", { retentionMs: -1 }))
+				.then(
+					res => { throw 'expected tsAppend to fail' },
+					err => { if (!err.error().startsWith('invalid retentionMs option: -1')) { throw 'unexpected error: ' + err.error() } }
+				)
+		`, rs.Addr()))
+
+		return err
+	})
+
+	assert.NoError(t, gotScriptErr)
+	assert.Equal(t, [][]string{
+		{"HELLO", "2"},
+		{"EVALSHA", tsAppendScript.Hash(), "1", "cpu", "1700000000000", "42", "60000"},
+		{"EVAL", tsAppendSource, "1", "cpu", "1700000000000", "42", "60000"},
+	}, rs.GotCommands())
+}
+
+func TestClientRedisTimeSeries(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunT(t)
+	rs.RegisterCommandHandler("TS.ADD", func(c *Connection, _ []string) {
+		c.WriteInteger(1000)
+	})
+	rs.RegisterCommandHandler("TS.MADD", func(c *Connection, args []string) {
+		if args[0] == "missing" {
+			c.WriteValue([]interface{}{errors.New("ERR TSDB: the key does not exist")})
+			return
+		}
+
+		c.WriteValue([]interface{}{1000, 2000})
+	})
+	rs.RegisterCommandHandler("TS.RANGE", func(c *Connection, _ []string) {
+		c.WriteValue([]interface{}{
+			[]interface{}{1000, "1.5"},
+			[]interface{}{2000, "2"},
+		})
+	})
+	rs.RegisterCommandHandler("TS.MRANGE", func(c *Connection, _ []string) {
+		c.WriteValue([]interface{}{
+			[]interface{}{
+				"temp:1",
+				[]interface{}{[]interface{}{"sensor", "temp"}},
+				[]interface{}{[]interface{}{1000, "21.5"}},
+			},
+		})
+	})
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client('redis://%s');
+
+			const expect = (method, got, want) => {
+				if (JSON.stringify(got) !== JSON.stringify(want)) {
+					throw 'unexpected value for ' + method + ' result: ' + JSON.stringify(got)
+				}
+			};
+
+			redis.tsAdd("temp:1", 1000, 21.5, { retentionMs: 60000, labels: { sensor: "temp", room: "a" } })
+				.then(res => expect("tsAdd", res, 1000))
+				.then(() => redis.tsAdd("temp:1", "*", 22))
+				.then(() => redis.tsMAdd([
+					{ key: "temp:1", timestamp: 1000, value: 1 },
+					{ key: "temp:2", timestamp: 2000, value: 2 },
+				]))
+				.then(res => expect("tsMAdd", res, [1000, 2000]))
+				.then(() => redis.tsMAdd([{ key: "missing", timestamp: "*", value: 1 }]))
+				.then(
+					res => { throw 'expected tsMAdd to fail' },
+					err => { if (!err.error().includes('the key does not exist')) { throw 'unexpected error: ' + err.error() } }
+				)
+				.then(() => redis.tsRange("temp:1", "-", "+", { count: 10, aggregation: { type: "avg", bucketMs: 1000 } }))
+				.then(res => expect("tsRange", res.map(s => [s.timestamp, s.value]), [[1000, 1.5], [2000, 2]]))
+				.then(() => redis.tsMRange(0, "+", ["sensor=temp"], { withLabels: true, filterByValue: { min: 0, max: 50 } }))
+				.then(res => {
+					if (res.length !== 1 || res[0].key !== "temp:1" || res[0].labels.sensor !== "temp" ||
+						res[0].samples[0].timestamp !== 1000 || res[0].samples[0].value !== 21.5) {
+						throw 'unexpected value for tsMRange result: ' + JSON.stringify(res)
+					}
+				})
+				.then(() => redis.tsMRange("-", "+", ["sensor=temp"], { groupBy: { label: "room", reduce: "max" } }))
+				.then(() => redis.tsRange("temp:1", "+", "-"))
+				.then(
+					res => { throw 'expected tsRange to fail' },
+					err => { if (!err.error().includes('invalid tsRange from timestamp')) { throw 'unexpected error: ' + err.error() } }
+				)
+		`, rs.Addr()))
+
+		return err
+	})
+
+	assert.NoError(t, gotScriptErr)
+	assert.Equal(t, [][]string{
+		{"HELLO", "2"},
+		{"TS.ADD", "temp:1", "1000", "21.5", "retention", "60000", "labels", "room", "a", "sensor", "temp"},
+		{"TS.ADD", "temp:1", "*", "22"},
+		{"TS.MADD", "temp:1", "1000", "1", "temp:2", "2000", "2"},
+		{"TS.MADD", "missing", "*", "1"},
+		{"TS.RANGE", "temp:1", "-", "+", "count", "10", "aggregation", "avg", "1000"},
+		{"TS.MRANGE", "0", "+", "filter_by_value", "0", "50", "withlabels", "filter", "sensor=temp"},
+		{"TS.MRANGE", "-", "+", "filter", "sensor=temp", "groupby", "room", "reduce", "max"},
+	}, rs.GotCommands())
+}
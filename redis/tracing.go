@@ -0,0 +1,158 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName is the name of the OpenTelemetry tracer commands are traced
+// with.
+const tracerName = "k6/x/redis"
+
+// tracingHook is the go-redis hook creating an OpenTelemetry span for each
+// command, and pipeline, when k6's tracing is enabled with the
+// --traces-output flag. The spans are children of the span carried by the
+// VU's context, if any, so that they can be correlated with the other
+// spans of the iteration.
+type tracingHook struct {
+	address string
+}
+
+var _ redis.Hook = &tracingHook{}
+
+// DialHook implements the redis.Hook interface.
+func (h *tracingHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+// ProcessHook implements the redis.Hook interface.
+func (h *tracingHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		attrs := []attribute.KeyValue{attribute.String("db.operation", cmd.Name())}
+		if key := commandKey(cmd); key != "" {
+			attrs = append(attrs, attribute.String("db.redis.key", key))
+		}
+
+		ctx, span, ok := h.start(ctx, cmd.Name(), attrs...)
+		if !ok {
+			return next(ctx, cmd)
+		}
+		defer span.End()
+
+		// go-redis only sets the error of single commands once the hooks
+		// returned.
+		err := next(ctx, cmd)
+		if isCommandError(err) {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+
+		return err
+	}
+}
+
+// ProcessPipelineHook implements the redis.Hook interface.
+//
+// Pipelines, and transactions, are traced as a single "pipeline" span,
+// which records the errors of their failed commands.
+func (h *tracingHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		ctx, span, ok := h.start(ctx, "pipeline", attribute.Int("db.redis.num_cmd", len(cmds)))
+		if !ok {
+			return next(ctx, cmds)
+		}
+		defer span.End()
+
+		err := next(ctx, cmds)
+		for _, cmd := range cmds {
+			if isCommandError(cmd.Err()) {
+				span.RecordError(cmd.Err(), trace.WithAttributes(attribute.String("db.operation", cmd.Name())))
+				span.SetStatus(codes.Error, cmd.Err().Error())
+			}
+		}
+
+		return err
+	}
+}
+
+// start starts a span named `name`, on behalf of the Client carried by the
+// context. It returns false if there is no such Client, or if its VU has
+// no tracer provider.
+func (h *tracingHook) start(
+	ctx context.Context, name string, attrs ...attribute.KeyValue,
+) (context.Context, trace.Span, bool) {
+	c, ok := clientFromContext(ctx)
+	if !ok {
+		return ctx, nil, false
+	}
+
+	state := c.vu.State()
+	if state == nil || state.TracerProvider == nil {
+		return ctx, nil, false
+	}
+
+	attrs = append(attrs, attribute.String("db.system", "redis"), attribute.String("server.address", h.address))
+	ctx, span := state.TracerProvider.Tracer(tracerName).Start(
+		ctx, name, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...),
+	)
+
+	return ctx, span, true
+}
+
+// keylessCommands lists the commands whose first argument isn't a key.
+var keylessCommands = map[string]struct{}{
+	"auth": {}, "bgrewriteaof": {}, "bgsave": {}, "client": {}, "cluster": {}, "command": {}, "config": {},
+	"dbsize": {}, "debug": {}, "discard": {}, "echo": {}, "exec": {}, "failover": {}, "flushall": {},
+	"flushdb": {}, "function": {}, "hello": {}, "info": {}, "lastsave": {}, "latency": {}, "migrate": {},
+	"multi": {}, "ping": {}, "psubscribe": {}, "publish": {}, "quit": {}, "randomkey": {}, "readonly": {},
+	"readwrite": {}, "reset": {}, "role": {}, "save": {}, "scan": {}, "script": {}, "select": {},
+	"sentinel": {}, "slowlog": {}, "spublish": {}, "subscribe": {}, "swapdb": {}, "time": {}, "unwatch": {},
+	"wait": {},
+
+	// RedisTimeSeries commands selecting time series by label.
+	"ts.mget": {}, "ts.mrange": {}, "ts.mrevrange": {}, "ts.queryindex": {},
+}
+
+// commandKey returns the first key `cmd` operates on, or an empty string if
+// it has none. It only accounts for the most common command layouts: it
+// is meant to label spans, not to route commands.
+func commandKey(cmd redis.Cmder) string {
+	args := cmd.Args()
+	name := strings.ToLower(cmd.Name())
+	if _, ok := keylessCommands[name]; ok {
+		return ""
+	}
+
+	pos := 1
+	switch name {
+	case "eval", "evalsha", "eval_ro", "evalsha_ro", "fcall", "fcall_ro":
+		if len(args) > 2 && fmt.Sprint(args[2]) == "0" {
+			return ""
+		}
+		pos = 3
+	case "memory", "object":
+		pos = 2
+	case "xread", "xreadgroup":
+		pos = -1
+		for idx, arg := range args {
+			if s, ok := arg.(string); ok && strings.EqualFold(s, "streams") {
+				pos = idx + 1
+				break
+			}
+		}
+	}
+
+	if pos < 0 || pos >= len(args) {
+		return ""
+	}
+
+	key, _ := args[pos].(string)
+
+	return key
+}
@@ -0,0 +1,86 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestClientTracing(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	recorder := tracetest.NewSpanRecorder()
+	ts.state.TracerProvider = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	rs := RunT(t)
+	rs.RegisterCommandHandler("GET", func(c *Connection, _ []string) {
+		c.WriteBulkString("bar")
+	})
+	rs.RegisterCommandHandler("LPUSH", func(c *Connection, _ []string) {
+		c.WriteError(errors.New("WRONGTYPE Operation against a key holding the wrong kind of value"))
+	})
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client('redis://%s');
+
+			redis.get("foo")
+				.then(() => redis.lpush("foo", "bar"))
+				.then(
+					res => { throw 'expected lpush to fail' },
+					err => {}
+				)
+				.then(() => redis.pipeline().get("foo").get("baz").exec())
+		`, rs.Addr()))
+
+		return err
+	})
+	require.NoError(t, gotScriptErr)
+
+	spans := make(map[string]sdktrace.ReadOnlySpan)
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = span
+	}
+
+	require.Contains(t, spans, "get")
+	attrs := spans["get"].Attributes()
+	assert.Contains(t, attrs, attribute.String("db.system", "redis"))
+	assert.Contains(t, attrs, attribute.String("db.redis.key", "foo"))
+	assert.Contains(t, attrs, attribute.String("server.address", rs.Addr().String()))
+
+	require.Contains(t, spans, "lpush")
+	assert.Equal(t, codes.Error, spans["lpush"].Status().Code)
+
+	require.Contains(t, spans, "pipeline")
+	assert.Contains(t, spans["pipeline"].Attributes(), attribute.Int("db.redis.num_cmd", 2))
+}
+
+func TestCommandKey(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		args []interface{}
+		want string
+	}{
+		{[]interface{}{"get", "foo"}, "foo"},
+		{[]interface{}{"ping"}, ""},
+		{[]interface{}{"info", "memory"}, ""},
+		{[]interface{}{"evalsha", "abc", 1, "foo", "arg"}, "foo"},
+		{[]interface{}{"eval", "return 1", 0}, ""},
+		{[]interface{}{"object", "encoding", "foo"}, "foo"},
+		{[]interface{}{"xread", "count", 10, "streams", "events", "$"}, "events"},
+	} {
+		cmd := redis.NewCmd(context.Background(), tc.args...)
+		assert.Equal(t, tc.want, commandKey(cmd), tc.args)
+	}
+}
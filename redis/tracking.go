@@ -0,0 +1,154 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// invalidationChannel is the channel Redis publishes the keys invalidated by
+// client tracking on, for the connections redirecting their invalidations
+// to a subscribed connection.
+const invalidationChannel = "__redis__:invalidate"
+
+// invalidationTracker implements the clientTracking option. It holds the
+// read cache of the clients sharing the same underlying go-redis client,
+// and invalidates its entries as Redis reports writes to their keys.
+//
+// go-redis doesn't deliver the invalidation push messages RESP3 connections
+// receive in-band, so the tracker relies on the redirect mode of client
+// tracking instead, which works with both protocol versions: each of the
+// go-redis client's connections redirects its invalidations to a dedicated
+// connection, subscribed to the invalidation channel.
+//
+// If the dedicated connection fails, invalidations might have been missed,
+// and the cache is disabled for the rest of the test.
+type invalidationTracker struct {
+	// cache is the read cache whose entries are invalidated.
+	cache resultCache
+
+	// client owns the dedicated connection.
+	client *redis.Client
+
+	// connID is the client ID of the dedicated connection, looked up as
+	// it is dialed, as pub/sub connections don't serve other commands.
+	connID atomic.Int64
+
+	mu sync.Mutex
+
+	// redirectID is the client ID of the dedicated connection, which
+	// tracking connections redirect their invalidations to, once it is
+	// subscribed to the invalidation channel.
+	redirectID int64
+
+	// failed is set once the dedicated connection fails.
+	failed bool
+}
+
+// newInvalidationTracker returns an invalidationTracker whose dedicated
+// connection is dialed using the provided options.
+func newInvalidationTracker(opts *redis.Options) *invalidationTracker {
+	t := &invalidationTracker{}
+
+	// The invalidation channel is subscribed to with RESP2, as RESP3
+	// connections receive invalidations as push messages go-redis can't
+	// parse, regardless of the protocol used by tracking connections.
+	opts.Protocol = 2
+	opts.PoolSize = 1
+	opts.OnConnect = func(ctx context.Context, cn *redis.Conn) error {
+		id, err := cn.ClientID(ctx).Result()
+		t.connID.Store(id)
+		return err
+	}
+	t.client = redis.NewClient(opts)
+
+	return t
+}
+
+// close closes the tracker's dedicated connection.
+func (t *invalidationTracker) close() {
+	_ = t.client.Close()
+}
+
+// enableTracking is the OnConnect hook of the go-redis client's
+// connections, enabling client tracking on `cn`, with its invalidations
+// redirected to the tracker's dedicated connection.
+func (t *invalidationTracker) enableTracking(ctx context.Context, cn *redis.Conn) error {
+	redirectID, err := t.subscribe(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Connections dialed after the dedicated connection failed are left
+	// alone, the cache being disabled.
+	if redirectID == 0 {
+		return nil
+	}
+
+	if err := cn.Process(ctx, redis.NewStatusCmd(ctx, "client", "tracking", "on", "redirect", redirectID)); err != nil {
+		return fmt.Errorf("unable to enable client tracking; reason: %w", err)
+	}
+
+	return nil
+}
+
+// subscribe subscribes the dedicated connection to the invalidation
+// channel, if it isn't yet, and returns its client ID, or zero if it
+// failed.
+func (t *invalidationTracker) subscribe(ctx context.Context) (int64, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.failed || t.redirectID != 0 {
+		return t.redirectID, nil
+	}
+
+	pubsub := t.client.Subscribe(ctx, invalidationChannel)
+	if _, err := pubsub.Receive(ctx); err != nil {
+		_ = pubsub.Close()
+		return 0, fmt.Errorf("unable to subscribe to client tracking invalidations; reason: %w", err)
+	}
+
+	t.redirectID = t.connID.Load()
+	go t.listen(pubsub)
+
+	return t.redirectID, nil
+}
+
+// listen invalidates the cache entries of the keys received on `pubsub`,
+// until it fails.
+func (t *invalidationTracker) listen(pubsub *redis.PubSub) {
+	defer pubsub.Close() //nolint:errcheck
+
+	for {
+		msg, err := pubsub.ReceiveMessage(context.Background())
+
+		var netErr net.Error
+		switch {
+		case errors.Is(err, redis.ErrClosed):
+			return
+		case errors.As(err, &netErr), errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+			t.mu.Lock()
+			t.failed, t.redirectID = true, 0
+			t.mu.Unlock()
+
+			t.cache.flush(true)
+			return
+		case err != nil:
+			// Redis invalidates all the keys, when the database is
+			// flushed, with a null payload go-redis fails to parse.
+			// Flushing the whole cache is always safe.
+			t.cache.flush(false)
+		case msg.PayloadSlice != nil:
+			t.cache.invalidate(msg.PayloadSlice...)
+		default:
+			t.cache.invalidate(msg.Payload)
+		}
+	}
+}
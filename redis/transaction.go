@@ -0,0 +1,183 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/grafana/sobek"
+	"github.com/mstoykov/k6-taskqueue-lib/taskqueue"
+	"github.com/redis/go-redis/v9"
+)
+
+// defaultWatchRetries is the number of times a watch transaction is retried
+// after its watched keys were modified, when the retries option is unset.
+const defaultWatchRetries = 3
+
+// Multi returns a new, empty, pipeline executing its commands in a
+// MULTI/EXEC transaction.
+func (c *Client) Multi() *Pipeline {
+	return &Pipeline{client: c, tx: true}
+}
+
+// watchOptions holds the options of the Client's watch method.
+type watchOptions struct {
+	// Retries is the number of times the transaction is retried after its
+	// watched keys were modified. It defaults to defaultWatchRetries.
+	Retries *int `json:"retries,omitempty"`
+}
+
+// Watch executes an optimistically locked transaction over `keys`.
+//
+// The keys are watched, and their values read. `callback` is then called
+// with the values, null standing for a missing key, and a transaction
+// pipeline to queue the transaction's commands on, which are executed in a
+// MULTI/EXEC transaction once the callback returns. If any of the watched
+// keys is modified before the transaction is executed, the transaction is
+// aborted, and the whole process is retried, up to `retries` times.
+//
+// The promise resolves with the results of the transaction's commands. It
+// is rejected if the callback throws, or the transaction is still aborted
+// after the last retry.
+func (c *Client) Watch(keys []string, callback sobek.Callable, options map[string]interface{}) *sobek.Promise {
+	promise, resolve, reject := c.newPromise()
+
+	if err := c.connect(); err != nil {
+		reject(err)
+		return promise
+	}
+
+	if len(keys) == 0 {
+		reject(errors.New("at least one key must be provided to watch"))
+		return promise
+	}
+
+	if callback == nil {
+		reject(errors.New("a transaction callback function must be provided to watch"))
+		return promise
+	}
+
+	// The callback is called on the event loop, which blocking clients
+	// keep busy until the transaction completes.
+	if c.syncCalls != nil {
+		reject(errors.New("watch is not supported by blocking clients"))
+		return promise
+	}
+
+	var opts watchOptions
+	if err := decodeOptions(options, &opts); err != nil {
+		reject(fmt.Errorf("invalid watch options; reason: %w", err))
+		return promise
+	}
+
+	retries := defaultWatchRetries
+	if opts.Retries != nil {
+		retries = *opts.Retries
+	}
+
+	if retries < 0 {
+		reject(fmt.Errorf("invalid retries option: %d; expected a positive number", retries))
+		return promise
+	}
+
+	queue := taskqueue.New(c.vu.RegisterCallback)
+
+	go func() {
+		defer queue.Close()
+
+		ctx := c.context()
+		for attempt := 0; attempt <= retries; attempt++ {
+			var results []interface{}
+			err := c.redisClient.Watch(ctx, func(tx *redis.Tx) error {
+				values, err := watchedValues(ctx, tx, keys)
+				if err != nil {
+					return err
+				}
+
+				queued, err := c.queueTransaction(ctx, queue, callback, values)
+				if err != nil {
+					return err
+				}
+
+				results, err = execQueued(ctx, tx.TxPipeline(), queued)
+				return err
+			}, keys...)
+
+			if errors.Is(err, redis.TxFailedErr) {
+				continue
+			}
+
+			if err != nil {
+				reject(err)
+				return
+			}
+
+			resolve(results)
+			return
+		}
+
+		reject(fmt.Errorf("transaction aborted after %d attempts, as the watched keys kept being modified", retries+1))
+	}()
+
+	return promise
+}
+
+// watchedValues reads the values of the watched keys, in a single
+// round-trip, nil standing for a missing key.
+func watchedValues(ctx context.Context, tx *redis.Tx, keys []string) ([]interface{}, error) {
+	cmds := make([]*redis.StringCmd, len(keys))
+	_, err := tx.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for idx, key := range keys {
+			cmds[idx] = pipe.Get(ctx, key)
+		}
+
+		return nil
+	})
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+
+	values := make([]interface{}, len(keys))
+	for idx, cmd := range cmds {
+		value, err := cmd.Result()
+		switch {
+		case errors.Is(err, redis.Nil):
+			values[idx] = nil
+		case err != nil:
+			return nil, err
+		default:
+			values[idx] = value
+		}
+	}
+
+	return values, nil
+}
+
+// queueTransaction calls the watch callback on the event loop, and returns
+// the commands it queued on the transaction pipeline it is handed.
+func (c *Client) queueTransaction(
+	ctx context.Context, queue *taskqueue.TaskQueue, callback sobek.Callable, values []interface{},
+) ([]queuedCommand, error) {
+	type outcome struct {
+		queued []queuedCommand
+		err    error
+	}
+
+	done := make(chan outcome, 1)
+	queue.Queue(func() error {
+		rt := c.vu.Runtime()
+		pipe := &Pipeline{client: c, tx: true, watched: true}
+
+		_, err := callback(sobek.Undefined(), rt.ToValue(values), rt.ToValue(pipe))
+		done <- outcome{queued: pipe.cmds, err: err}
+
+		return nil
+	})
+
+	select {
+	case out := <-done:
+		return out.queued, out.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
@@ -0,0 +1,215 @@
+package redis
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientMulti(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunT(t)
+	registerTransactionHandlers(rs, 0)
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client('redis://%s');
+
+			redis.multi().decrBy("stock", 1).get("missing").decrBy("stock", 2).exec()
+				.then(res => {
+					if (res.length !== 3 || res[0] !== 9 || res[1] !== null || res[2] !== 7) {
+						throw 'unexpected value for multi results: ' + JSON.stringify(res)
+					}
+				})
+		`, rs.Addr()))
+
+		return err
+	})
+
+	assert.NoError(t, gotScriptErr)
+	assert.Equal(t, [][]string{
+		{"HELLO", "2"},
+		{"MULTI"},
+		{"DECRBY", "stock", "1"},
+		{"GET", "missing"},
+		{"DECRBY", "stock", "2"},
+		{"EXEC"},
+	}, rs.GotCommands())
+}
+
+func TestClientWatch(t *testing.T) {
+	t.Parallel()
+
+	t.Run("retries aborted transactions", func(t *testing.T) {
+		t.Parallel()
+
+		ts := newTestSetup(t)
+		rs := RunT(t)
+		registerTransactionHandlers(rs, 1)
+
+		gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+			_, err := ts.rt.RunString(fmt.Sprintf(`
+				const redis = new Client('redis://%s');
+
+				let calls = 0;
+				redis.watch(["stock", "missing"], (values, tx) => {
+					calls++;
+					if (values[0] !== "10" || values[1] !== null) {
+						throw 'unexpected watched values: ' + JSON.stringify(values)
+					}
+
+					tx.decrBy("stock", 1).get("missing");
+				})
+					.then(res => {
+						if (calls !== 2) { throw 'expected the callback to be called twice, got ' + calls }
+						if (res.length !== 2 || res[0] !== 9 || res[1] !== null) { throw 'unexpected value for watch results: ' + JSON.stringify(res) }
+					})
+			`, rs.Addr()))
+
+			return err
+		})
+
+		assert.NoError(t, gotScriptErr)
+		assert.Equal(t, 2, countCommands(rs.GotCommands(), "EXEC"))
+	})
+
+	t.Run("fails once the retries are exhausted", func(t *testing.T) {
+		t.Parallel()
+
+		ts := newTestSetup(t)
+		rs := RunT(t)
+		registerTransactionHandlers(rs, 2)
+
+		gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+			_, err := ts.rt.RunString(fmt.Sprintf(`
+				const redis = new Client('redis://%s');
+
+				redis.watch(["stock"], (values, tx) => { tx.decrBy("stock", 1) }, { retries: 1 })
+					.then(
+						res => { throw 'expected watch to fail' },
+						err => { if (!err.error().includes('aborted after 2 attempts')) { throw 'unexpected error: ' + err.error() } }
+					)
+			`, rs.Addr()))
+
+			return err
+		})
+
+		assert.NoError(t, gotScriptErr)
+		assert.Equal(t, 2, countCommands(rs.GotCommands(), "EXEC"))
+	})
+
+	t.Run("fails when the callback throws", func(t *testing.T) {
+		t.Parallel()
+
+		ts := newTestSetup(t)
+		rs := RunT(t)
+		registerTransactionHandlers(rs, 0)
+
+		gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+			_, err := ts.rt.RunString(fmt.Sprintf(`
+				const redis = new Client('redis://%s');
+
+				redis.watch(["stock"], (values, tx) => { throw 'out of stock' })
+					.then(
+						res => { throw 'expected watch to fail' },
+						err => { if (!String(err).includes('out of stock')) { throw 'unexpected error: ' + err } }
+					)
+			`, rs.Addr()))
+
+			return err
+		})
+
+		assert.NoError(t, gotScriptErr)
+		assert.Equal(t, 0, countCommands(rs.GotCommands(), "EXEC"))
+	})
+}
+
+// registerTransactionHandlers registers handlers emulating transactions
+// over a "stock" counter holding 10, whose first `aborts` EXEC commands
+// report the transaction as aborted.
+func registerTransactionHandlers(rs *StubServer, aborts int) {
+	var (
+		mu      sync.Mutex
+		inMulti bool
+		stock   = 10
+		queued  []interface{}
+	)
+
+	rs.RegisterCommandHandler("WATCH", func(c *Connection, _ []string) {
+		c.WriteOK()
+	})
+	rs.RegisterCommandHandler("UNWATCH", func(c *Connection, _ []string) {
+		c.WriteOK()
+	})
+	rs.RegisterCommandHandler("GET", func(c *Connection, args []string) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		var value interface{}
+		if args[0] == "stock" {
+			value = fmt.Sprint(stock)
+		}
+
+		if inMulti {
+			queued = append(queued, value)
+			c.WriteSimpleString("QUEUED")
+			return
+		}
+
+		c.WriteValue(value)
+	})
+	rs.RegisterCommandHandler("MULTI", func(c *Connection, _ []string) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		inMulti, queued = true, nil
+		c.WriteOK()
+	})
+	rs.RegisterCommandHandler("DECRBY", func(c *Connection, args []string) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		var decrement int
+		fmt.Sscan(args[1], &decrement) //nolint:errcheck
+		stock -= decrement
+		queued = append(queued, stock)
+
+		if inMulti {
+			c.WriteSimpleString("QUEUED")
+			return
+		}
+
+		c.WriteInteger(stock)
+	})
+	rs.RegisterCommandHandler("EXEC", func(c *Connection, _ []string) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		inMulti = false
+		if aborts > 0 {
+			aborts--
+			stock = 10
+			c.WriteNull()
+			return
+		}
+
+		c.WriteValue(queued)
+	})
+}
+
+// countCommands returns the number of occurrences of `command` in the
+// provided commands.
+func countCommands(commands [][]string, command string) int {
+	count := 0
+	for _, cmd := range commands {
+		if cmd[0] == command {
+			count++
+		}
+	}
+
+	return count
+}
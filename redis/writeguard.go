@@ -0,0 +1,197 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// writeNotAllowedError is the error the commands modifying data are failed
+// with, when the allowWriteCommands option is false.
+type writeNotAllowedError struct {
+	command string
+}
+
+// Error implements the error interface.
+func (e *writeNotAllowedError) Error() string {
+	return fmt.Sprintf("the %s command is not allowed, as the allowWriteCommands option is false", e.command)
+}
+
+// writeGuardHook is the go-redis hook implementing the allowWriteCommands
+// option: it fails the commands modifying data sent on behalf of a Client
+// with the option set to false, before they reach the server. Pipelines,
+// and transactions, holding such a command are failed as a whole.
+//
+// As keyPrefixHook, the hook is installed on the go-redis client, and on
+// the nodes of cluster clients.
+type writeGuardHook struct{}
+
+var _ redis.Hook = writeGuardHook{}
+
+// DialHook implements the redis.Hook interface.
+func (writeGuardHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+// ProcessHook implements the redis.Hook interface.
+func (h writeGuardHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		if err := checkWriteAllowed(ctx, cmd); err != nil {
+			cmd.SetErr(err)
+			return err
+		}
+
+		return next(ctx, cmd)
+	}
+}
+
+// ProcessPipelineHook implements the redis.Hook interface.
+func (h writeGuardHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		if err := checkWriteAllowed(ctx, cmds...); err != nil {
+			for _, cmd := range cmds {
+				cmd.SetErr(err)
+			}
+			return err
+		}
+
+		return next(ctx, cmds)
+	}
+}
+
+// checkWriteAllowed returns a writeNotAllowedError if any of `cmds` is a
+// write command, and the Client carried by the context doesn't allow them.
+func checkWriteAllowed(ctx context.Context, cmds ...redis.Cmder) error {
+	c, ok := clientFromContext(ctx)
+	if !ok || c.redisOptions == nil || c.redisOptions.allowsWriteCommands() {
+		return nil
+	}
+
+	for _, cmd := range cmds {
+		if command, ok := writeCommand(cmd); ok {
+			return &writeNotAllowedError{command: command}
+		}
+	}
+
+	return nil
+}
+
+// writeCommands lists the commands modifying data, or the server's state,
+// including those of the RedisJSON, RedisTimeSeries, RediSearch, and
+// RedisBloom modules.
+var writeCommands = map[string]struct{}{
+	// Generic.
+	"copy": {}, "del": {}, "expire": {}, "expireat": {}, "flushall": {}, "flushdb": {}, "migrate": {},
+	"move": {}, "persist": {}, "pexpire": {}, "pexpireat": {}, "rename": {}, "renamenx": {}, "restore": {},
+	"sort": {}, "swapdb": {}, "unlink": {},
+
+	// Strings and bitmaps.
+	"append": {}, "bitfield": {}, "bitop": {}, "decr": {}, "decrby": {}, "getdel": {}, "getex": {},
+	"getset": {}, "incr": {}, "incrby": {}, "incrbyfloat": {}, "mset": {}, "msetnx": {}, "psetex": {},
+	"set": {}, "setbit": {}, "setex": {}, "setnx": {}, "setrange": {},
+
+	// Hashes.
+	"hdel": {}, "hexpire": {}, "hincrby": {}, "hincrbyfloat": {}, "hmset": {}, "hpersist": {},
+	"hpexpire": {}, "hset": {}, "hsetnx": {},
+
+	// Lists.
+	"blmove": {}, "blmpop": {}, "blpop": {}, "brpop": {}, "brpoplpush": {}, "linsert": {}, "lmove": {},
+	"lmpop": {}, "lpop": {}, "lpush": {}, "lpushx": {}, "lrem": {}, "lset": {}, "ltrim": {}, "rpop": {},
+	"rpoplpush": {}, "rpush": {}, "rpushx": {},
+
+	// Sets.
+	"sadd": {}, "sdiffstore": {}, "sinterstore": {}, "smove": {}, "spop": {}, "srem": {}, "sunionstore": {},
+
+	// Sorted sets.
+	"bzmpop": {}, "bzpopmax": {}, "bzpopmin": {}, "zadd": {}, "zdiffstore": {}, "zincrby": {},
+	"zinterstore": {}, "zmpop": {}, "zpopmax": {}, "zpopmin": {}, "zrangestore": {}, "zrem": {},
+	"zremrangebylex": {}, "zremrangebyrank": {}, "zremrangebyscore": {}, "zunionstore": {},
+
+	// Geospatial indexes, and HyperLogLogs.
+	"geoadd": {}, "georadius": {}, "georadiusbymember": {}, "geosearchstore": {}, "pfadd": {}, "pfmerge": {},
+
+	// Streams.
+	"xack": {}, "xadd": {}, "xautoclaim": {}, "xclaim": {}, "xdel": {}, "xgroup": {}, "xreadgroup": {},
+	"xsetid": {}, "xtrim": {},
+
+	// Scripting, and server administration. Scripts may write, unless
+	// they are run with the read-only variants of EVAL, and FCALL.
+	"bgrewriteaof": {}, "bgsave": {}, "debug": {}, "eval": {}, "evalsha": {}, "failover": {}, "fcall": {},
+	"publish": {}, "replicaof": {}, "save": {}, "shutdown": {}, "slaveof": {}, "spublish": {},
+
+	// RedisJSON.
+	"json.arrappend": {}, "json.arrinsert": {}, "json.arrpop": {}, "json.arrtrim": {}, "json.clear": {},
+	"json.del": {}, "json.forget": {}, "json.merge": {}, "json.mset": {}, "json.numincrby": {},
+	"json.nummultby": {}, "json.set": {}, "json.strappend": {}, "json.toggle": {},
+
+	// RedisTimeSeries.
+	"ts.add": {}, "ts.alter": {}, "ts.create": {}, "ts.createrule": {}, "ts.decrby": {}, "ts.del": {},
+	"ts.deleterule": {}, "ts.incrby": {}, "ts.madd": {},
+
+	// RediSearch.
+	"ft.aliasadd": {}, "ft.aliasdel": {}, "ft.aliasupdate": {}, "ft.alter": {}, "ft.create": {},
+	"ft.dictadd": {}, "ft.dictdel": {}, "ft.dropindex": {}, "ft.sugadd": {}, "ft.sugdel": {},
+	"ft.synupdate": {},
+
+	// RedisBloom.
+	"bf.add": {}, "bf.insert": {}, "bf.loadchunk": {}, "bf.madd": {}, "bf.reserve": {}, "cf.add": {},
+	"cf.addnx": {}, "cf.del": {}, "cf.insert": {}, "cf.insertnx": {}, "cf.loadchunk": {}, "cf.reserve": {},
+	"cms.incrby": {}, "cms.initbydim": {}, "cms.initbyprob": {}, "cms.merge": {}, "tdigest.add": {},
+	"tdigest.create": {}, "tdigest.merge": {}, "tdigest.reset": {}, "topk.add": {}, "topk.incrby": {},
+	"topk.reserve": {},
+}
+
+// writeSubcommands lists the subcommands modifying data, or the server's
+// state, of the commands whose other subcommands don't.
+var writeSubcommands = map[string]map[string]struct{}{
+	"acl":      {"deluser": {}, "load": {}, "save": {}, "setuser": {}},
+	"client":   {"kill": {}, "pause": {}},
+	"config":   {"set": {}, "resetstat": {}, "rewrite": {}},
+	"function": {"delete": {}, "flush": {}, "load": {}, "restore": {}},
+	"script":   {"flush": {}, "load": {}},
+	"cluster": {
+		"addslots": {}, "addslotsrange": {}, "delslots": {}, "delslotsrange": {}, "failover": {}, "forget": {},
+		"meet": {}, "replicate": {}, "reset": {}, "setslot": {}, "flushslots": {},
+	},
+}
+
+// writeCommand returns the name of `cmd`, along with its subcommand if
+// relevant, and whether it modifies data, or the server's state.
+func writeCommand(cmd redis.Cmder) (string, bool) {
+	name := strings.ToLower(cmd.Name())
+	if _, ok := writeCommands[name]; ok {
+		switch name {
+		case "sort":
+			// SORT only writes when given a STORE argument.
+			return name, hasArg(cmd, "store")
+		case "georadius", "georadiusbymember":
+			return name, hasArg(cmd, "store") || hasArg(cmd, "storedist")
+		default:
+			return name, true
+		}
+	}
+
+	subcommands, ok := writeSubcommands[name]
+	if !ok || len(cmd.Args()) < 2 {
+		return name, false
+	}
+
+	subcommand := strings.ToLower(argString(cmd.Args()[1]))
+	_, ok = subcommands[subcommand]
+
+	return name + " " + subcommand, ok
+}
+
+// hasArg returns whether `arg` is one of the arguments of `cmd`, regardless
+// of its case.
+func hasArg(cmd redis.Cmder, arg string) bool {
+	for _, a := range cmd.Args()[1:] {
+		if s, ok := a.(string); ok && strings.EqualFold(s, arg) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,86 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientAllowWriteCommands(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestSetup(t)
+	rs := RunT(t)
+	rs.RegisterCommandHandler("GET", func(c *Connection, _ []string) {
+		c.WriteBulkString("bar")
+	})
+	rs.RegisterCommandHandler("SET", func(c *Connection, _ []string) {
+		c.WriteOK()
+	})
+
+	gotScriptErr := ts.runtime.EventLoop.Start(func() error {
+		_, err := ts.rt.RunString(fmt.Sprintf(`
+			const redis = new Client({ socket: { host: "%s", port: %d }, allowWriteCommands: false });
+
+			if (redis.options().allowWriteCommands !== false) {
+				throw 'expected the allowWriteCommands option to be reported';
+			}
+
+			redis.get("foo")
+				.then(res => { if (res !== "bar") { throw 'unexpected value for get result: ' + res } })
+				.then(() => redis.set("foo", "bar", 0))
+				.then(
+					res => { throw 'expected set to fail' },
+					err => {
+						if (err.kind !== "write_not_allowed") { throw 'unexpected error kind: ' + err.kind }
+					}
+				)
+				.then(() => redis.pipeline().get("foo").sendCommand("FLUSHALL").exec())
+				.then(
+					res => { throw 'expected the pipeline to fail' },
+					err => {
+						if (err.error() !== "the flushall command is not allowed, as the allowWriteCommands option is false") {
+							throw 'unexpected error: ' + err.error()
+						}
+					}
+				)
+		`, rs.Addr().IP, rs.Addr().Port))
+
+		return err
+	})
+	require.NoError(t, gotScriptErr)
+
+	assert.Equal(t, [][]string{
+		{"HELLO", "2"},
+		{"GET", "foo"},
+	}, rs.GotCommands())
+}
+
+func TestWriteCommand(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		args  []interface{}
+		name  string
+		write bool
+	}{
+		{[]interface{}{"get", "foo"}, "get", false},
+		{[]interface{}{"SET", "foo", "bar"}, "set", true},
+		{[]interface{}{"json.set", "foo", "$", "{}"}, "json.set", true},
+		{[]interface{}{"sort", "foo", "limit", 0, 10}, "sort", false},
+		{[]interface{}{"sort", "foo", "store", "bar"}, "sort", true},
+		{[]interface{}{"config", "get", "maxmemory"}, "config get", false},
+		{[]interface{}{"config", "SET", "maxmemory", "1gb"}, "config set", true},
+		{[]interface{}{"client", "kill", "type", "normal"}, "client kill", true},
+		{[]interface{}{"client", "list"}, "client list", false},
+		{[]interface{}{"evalsha_ro", "abc", 0}, "evalsha_ro", false},
+	} {
+		name, write := writeCommand(redis.NewCmd(context.Background(), tc.args...))
+		assert.Equal(t, tc.name, name, "%v", tc.args)
+		assert.Equal(t, tc.write, write, "%v", tc.args)
+	}
+}
@@ -1,6 +1,8 @@
 // Package redis only exists to register the redis extension
 package redis
 
+//go:generate go run ./internal/gendts
+
 import (
 	"github.com/grafana/xk6-redis/redis"
 	"go.k6.io/k6/js/modules"
@@ -9,5 +11,5 @@ import (
 // Register the extension on module initialization, available to
 // import from JS as "k6/x/redis".
 func init() {
-	modules.Register("k6/x/redis", new(redis.RootModule))
+	modules.Register("k6/x/redis", redis.New())
 }